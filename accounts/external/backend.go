@@ -17,6 +17,9 @@
 package external
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -197,6 +200,12 @@ type signTransactionResult struct {
 }
 
 func (api *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return api.SignTxWithContext(context.Background(), account, tx, chainID)
+}
+
+// SignTxWithContext is the same as SignTx but passes the metadata of the private
+// transaction found in ctx along to the signer
+func (api *ExternalSigner) SignTxWithContext(ctx context.Context, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
 	data := hexutil.Bytes(tx.Data())
 	var to *common.MixedcaseAddress
 	if tx.To() != nil {
@@ -213,10 +222,26 @@ func (api *ExternalSigner) SignTx(account accounts.Account, tx *types.Transactio
 		From:      common.NewMixedcaseAddress(account.Address),
 		IsPrivate: tx.IsPrivate(),
 	}
+	// Quorum
+	if metadata := accounts.PrivateTxMetadataFromContext(ctx); tx.IsPrivate() && metadata != nil {
+		args.PrivateFrom = metadata.PrivateFrom
+		args.PrivateFor = metadata.PrivateFor
+		args.PrivacyFlag = metadata.PrivacyFlag
+	}
+	// /Quorum
 	var res signTransactionResult
-	if err := api.client.Call(&res, "account_signTransaction", args); err != nil {
+	if err := api.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
 		return nil, err
 	}
+	// Quorum
+	// the data of a private transaction is the hash of the payload already stored in the
+	// private transaction manager, the signer must not change it
+	if tx.IsPrivate() {
+		if res.Tx == nil || !res.Tx.IsPrivate() || !bytes.Equal(res.Tx.Data(), tx.Data()) {
+			return nil, errors.New("external signer did not sign the private transaction as requested")
+		}
+	}
+	// /Quorum
 	return res.Tx, nil
 }
 
@@ -227,6 +252,14 @@ func (api *ExternalSigner) SignTextWithPassphrase(account accounts.Account, pass
 func (api *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
 	return nil, fmt.Errorf("password-operations not supported on external signers")
 }
+
+// Quorum
+// SignTxWithPassphraseWithContext is not supported either, it is implemented so that the
+// callers passing the metadata of private transactions in ctx find SignTxWithContext
+func (api *ExternalSigner) SignTxWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return api.SignTxWithPassphrase(account, passphrase, tx, chainID)
+}
+
 func (api *ExternalSigner) SignDataWithPassphrase(account accounts.Account, passphrase, mimeType string, data []byte) ([]byte, error) {
 	return nil, fmt.Errorf("password-operations not supported on external signers")
 }
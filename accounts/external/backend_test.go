@@ -0,0 +1,65 @@
+package external
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClef records the transactions it is asked to sign and signs them with its key
+type stubClef struct {
+	args *core.SendTxArgs
+	tx   *types.Transaction
+}
+
+func (c *stubClef) SignTransaction(args core.SendTxArgs) (*signTransactionResult, error) {
+	c.args = &args
+	return &signTransactionResult{Tx: c.tx}, nil
+}
+
+// contextSigner mirrors the interface the RPC API looks the wallets up with, to pass
+// the metadata of the private transactions along
+type contextSigner interface {
+	SignTxWithContext(ctx context.Context, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	SignTxWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+func TestExternalSigner_SignTxWithContext_passesPrivateTxMetadata(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), common.EncryptedPayloadHash{1}.Bytes())
+	tx.SetPrivate()
+	signed, err := types.SignTx(tx, types.QuorumPrivateTxSigner{}, key)
+	require.NoError(t, err)
+	clef := &stubClef{tx: signed}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", clef))
+	defer server.Stop()
+	var wallet accounts.Wallet = &ExternalSigner{client: rpc.DialInProc(server)}
+
+	signer, ok := wallet.(contextSigner)
+	require.True(t, ok, "the metadata of the private transactions would be dropped")
+	ctx := accounts.WithPrivateTxMetadata(context.Background(), &accounts.PrivateTxMetadata{
+		PrivateFrom: "from",
+		PrivateFor:  []string{"for"},
+		PrivacyFlag: engine.PrivacyFlagPartyProtection,
+	})
+	_, err = signer.SignTxWithContext(ctx, accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)}, tx, big.NewInt(10))
+
+	require.NoError(t, err)
+	require.NotNil(t, clef.args)
+	assert.True(t, clef.args.IsPrivate)
+	assert.Equal(t, "from", clef.args.PrivateFrom)
+	assert.Equal(t, []string{"for"}, clef.args.PrivateFor)
+	assert.Equal(t, engine.PrivacyFlagPartyProtection, clef.args.PrivacyFlag)
+}
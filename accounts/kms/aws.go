@@ -0,0 +1,116 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// awsClient signs with AWS KMS keys of key spec ECC_SECG_P256K1, the credentials are
+// retrieved by the default chain of the AWS SDK (environment, shared configuration,
+// instance role...).
+type awsClient struct {
+	kms kmsiface.KMSAPI
+}
+
+func newAWSClient(region string) (*awsClient, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the AWS session: %v", err)
+	}
+	return &awsClient{kms: kms.New(sess)}, nil
+}
+
+func (c *awsClient) PublicKey(ctx context.Context, keyID string) (*ecdsa.PublicKey, error) {
+	out, err := c.kms.GetPublicKeyWithContext(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, err
+	}
+	if spec := aws.StringValue(out.CustomerMasterKeySpec); spec != kms.CustomerMasterKeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("unsupported key spec %s, expected %s", spec, kms.CustomerMasterKeySpecEccSecgP256k1)
+	}
+	return parsePublicKeyInfo(out.PublicKey)
+}
+
+func (c *awsClient) Sign(ctx context.Context, keyID string, digest []byte) (*big.Int, *big.Int, error) {
+	out, err := c.kms.SignWithContext(ctx, &kms.SignInput{
+		KeyId:            aws.String(keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(kms.SigningAlgorithmSpecEcdsaSha256),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseSignature(out.Signature)
+}
+
+type publicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// parsePublicKeyInfo parses a DER encoded secp256k1 public key in the X.509
+// SubjectPublicKeyInfo format, not supported by the x509 package
+func parsePublicKeyInfo(der []byte) (*ecdsa.PublicKey, error) {
+	var info publicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	} else if len(rest) > 0 {
+		return nil, errors.New("invalid public key: trailing data")
+	}
+	var curve asn1.ObjectIdentifier
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, fmt.Errorf("unsupported public key algorithm %v", info.Algorithm.Algorithm)
+	}
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidCurveSecp256k1) {
+		return nil, errors.New("unsupported public key curve, expected secp256k1")
+	}
+	return crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// parseSignature parses a DER encoded ECDSA signature
+func parseSignature(der []byte) (*big.Int, *big.Int, error) {
+	var sig ecdsaSignature
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, nil, fmt.Errorf("invalid signature: %v", err)
+	} else if len(rest) > 0 {
+		return nil, nil, errors.New("invalid signature: trailing data")
+	}
+	return sig.R, sig.S, nil
+}
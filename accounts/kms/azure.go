@@ -0,0 +1,238 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.1"
+	azureKeyVaultResource   = "https://vault.azure.net"
+	azureIMDSTokenURL       = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=" + azureKeyVaultResource
+)
+
+// azureClient signs with Azure Key Vault keys of curve P-256K through the REST API. It is
+// authenticated with the service principal of the AZURE_TENANT_ID, AZURE_CLIENT_ID and
+// AZURE_CLIENT_SECRET environment variables if set, with the managed identity of the host
+// otherwise.
+type azureClient struct {
+	vaultURL string
+	tokens   *azureTokenSource
+	client   *http.Client
+}
+
+func newAzureClient(vault string) *azureClient {
+	client := &http.Client{}
+	return &azureClient{
+		vaultURL: "https://" + vault + ".vault.azure.net",
+		tokens:   newAzureTokenSource(client),
+		client:   client,
+	}
+}
+
+type azureJSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (c *azureClient) PublicKey(ctx context.Context, keyID string) (*ecdsa.PublicKey, error) {
+	var res struct {
+		Key azureJSONWebKey `json:"key"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/keys/"+keyID, nil, &res); err != nil {
+		return nil, err
+	}
+	if (res.Key.Kty != "EC" && res.Key.Kty != "EC-HSM") || res.Key.Crv != "P-256K" {
+		return nil, fmt.Errorf("unsupported key type %s and curve %s, expected EC or EC-HSM and P-256K", res.Key.Kty, res.Key.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(res.Key.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(res.Key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("invalid public key length")
+	}
+	return crypto.UnmarshalPubkey(append(append([]byte{4}, x...), y...))
+}
+
+func (c *azureClient) Sign(ctx context.Context, keyID string, digest []byte) (*big.Int, *big.Int, error) {
+	req := map[string]string{
+		"alg":   "ES256K",
+		"value": base64.RawURLEncoding.EncodeToString(digest),
+	}
+	var res struct {
+		Value string `json:"value"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/keys/"+keyID+"/sign", req, &res); err != nil {
+		return nil, nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(res.Value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	if len(sig) != 64 {
+		return nil, nil, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), nil
+}
+
+// call sends a request to the Key Vault REST API, decoding the JSON response into result
+func (c *azureClient) call(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	token, err := c.tokens.token(ctx)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+	req, err := http.NewRequest(method, c.vaultURL+path+"?api-version="+azureKeyVaultAPIVersion, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(res.Body, 1024*1024))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		var failure struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(content, &failure) == nil && failure.Error.Code != "" {
+			return fmt.Errorf("%s: %s", failure.Error.Code, failure.Error.Message)
+		}
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return json.Unmarshal(content, result)
+}
+
+// azureTokenSource retrieves the access tokens of the Key Vault REST API, caching them
+// until shortly before they expire
+type azureTokenSource struct {
+	client   *http.Client
+	tokenURL string
+	form     url.Values // the client credentials grant, nil to use the managed identity
+
+	mu      sync.Mutex
+	current string
+	expiry  time.Time
+}
+
+func newAzureTokenSource(client *http.Client) *azureTokenSource {
+	s := &azureTokenSource{client: client, tokenURL: azureIMDSTokenURL}
+	if tenant, clientID, secret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET"); tenant != "" && clientID != "" && secret != "" {
+		s.tokenURL = "https://login.microsoftonline.com/" + url.PathEscape(tenant) + "/oauth2/v2.0/token"
+		s.form = url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {secret},
+			"scope":         {azureKeyVaultResource + "/.default"},
+		}
+	}
+	return s
+}
+
+func (s *azureTokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != "" && time.Now().Before(s.expiry) {
+		return s.current, nil
+	}
+	var (
+		req *http.Request
+		err error
+	)
+	if s.form != nil {
+		req, err = http.NewRequest(http.MethodPost, s.tokenURL, strings.NewReader(s.form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequest(http.MethodGet, s.tokenURL, nil)
+		if err == nil {
+			req.Header.Set("Metadata", "true")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve the Azure access token: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to retrieve the Azure access token: unexpected status %s", res.Status)
+	}
+	var token struct {
+		AccessToken string          `json:"access_token"`
+		ExpiresIn   json.RawMessage `json:"expires_in"` // a number, or a string for the managed identity
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1024*1024)).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid Azure access token: %v", err)
+	}
+	expiresIn, err := strconv.Atoi(strings.Trim(string(token.ExpiresIn), `"`))
+	if err != nil || token.AccessToken == "" {
+		return "", errors.New("invalid Azure access token")
+	}
+	s.current = token.AccessToken
+	// renew the token one minute before it expires
+	s.expiry = time.Now().Add(time.Duration(expiresIn)*time.Second - time.Minute)
+	return s.current, nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BackendType is the reflect type of the key management service backend
+var BackendType = reflect.TypeOf(&Backend{})
+
+// maxRetryInterval is the maximum interval between the attempts to retrieve a public key
+const maxRetryInterval = 5 * time.Minute
+
+// errWalletLocked is returned when signing with a wallet which isn't opened
+var errWalletLocked = errors.New("key management service wallet is locked, open it with personal_openWallet or start the node with --kms.unlocked")
+
+// HashSigner signs hashes with the key of an account held outside of the node, e.g.: by a
+// key management service or shared between the parties of a threshold signing service
+type HashSigner interface {
+	// URL identifies the key
+	URL() accounts.URL
+	// Sign signs hash, returning the signature in the [R || S || V] format where V is 0 or 1
+	Sign(hash []byte) ([]byte, error)
+}
+
+// Backend is an accounts.Backend with a wallet per key management service key. The
+// public keys are retrieved in the background, a wallet arriving once its public key
+// has been retrieved. Other signers are added with Register.
+//
+// The wallets are locked until they are opened, e.g. with personal_openWallet, unless
+// the backend is created unlocked.
+type Backend struct {
+	feed     event.Feed
+	scope    event.SubscriptionScope
+	quit     chan struct{}
+	unlocked bool // the wallets arrive opened
+
+	mu      sync.RWMutex
+	wallets []accounts.Wallet // sorted by URL
+}
+
+// NewBackend creates a backend with the keys at urls, e.g.: azurekv://vault/key/version.
+// The wallets sign without being opened first if unlocked is set.
+func NewBackend(urls []string, unlocked bool) (*Backend, error) {
+	signers := make([]*Signer, 0, len(urls))
+	for _, rawurl := range urls {
+		signer, err := newSignerFromURL(rawurl)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return newBackend(signers, unlocked), nil
+}
+
+func newBackend(signers []*Signer, unlocked bool) *Backend {
+	b := &Backend{quit: make(chan struct{}), unlocked: unlocked}
+	for _, signer := range signers {
+		go b.open(signer)
+	}
+	return b
+}
+
+// open retrieves the public key of signer, retrying until it succeeds, then adds its wallet
+func (b *Backend) open(signer *Signer) {
+	for retryInterval := time.Second; ; {
+		ctx, cancel := context.WithTimeout(context.Background(), signer.timeout)
+		pubkey, err := signer.PublicKey(ctx)
+		cancel()
+		if err == nil {
+			if err := b.Register(signer, crypto.PubkeyToAddress(*pubkey)); err != nil {
+				log.Warn("Failed to open key management service wallet", "url", signer.URL(), "err", err)
+			}
+			return
+		}
+		log.Warn("Failed to open key management service wallet", "url", signer.URL(), "retry", retryInterval, "err", err)
+		select {
+		case <-time.After(retryInterval):
+		case <-b.quit:
+			return
+		}
+		if retryInterval *= 2; retryInterval > maxRetryInterval {
+			retryInterval = maxRetryInterval
+		}
+	}
+}
+
+// Register adds the wallet of the account address signed for by signer, failing if a
+// wallet with the URL of signer has already been added
+func (b *Backend) Register(signer HashSigner, address common.Address) error {
+	w := &wallet{signer: signer, account: accounts.Account{Address: address, URL: signer.URL()}, opened: b.unlocked}
+	b.mu.Lock()
+	i := sort.Search(len(b.wallets), func(i int) bool { return b.wallets[i].URL().Cmp(w.URL()) >= 0 })
+	if i < len(b.wallets) && b.wallets[i].URL() == w.URL() {
+		b.mu.Unlock()
+		return fmt.Errorf("account %s already registered", address.Hex())
+	}
+	b.wallets = append(b.wallets, nil)
+	copy(b.wallets[i+1:], b.wallets[i:])
+	b.wallets[i] = w
+	b.mu.Unlock()
+
+	log.Info("Opened wallet", "url", w.URL(), "address", address)
+	b.feed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletArrived})
+	return nil
+}
+
+// Wallets implements accounts.Backend, returning the wallets whose public key has been retrieved
+func (b *Backend) Wallets() []accounts.Wallet {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	copy(cpy, b.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.scope.Track(b.feed.Subscribe(sink))
+}
+
+// Close stops the retrieval of the public keys and the subscriptions
+func (b *Backend) Close() {
+	close(b.quit)
+	b.scope.Close()
+}
+
+// wallet implements accounts.Wallet for a single key held outside of the node. The
+// access to the key is authenticated by the credentials of the node, so the wallet
+// is opened without passphrase.
+type wallet struct {
+	signer  HashSigner
+	account accounts.Account
+
+	mu     sync.RWMutex
+	opened bool // signing is allowed
+}
+
+func (w *wallet) URL() accounts.URL {
+	return w.signer.URL()
+}
+
+func (w *wallet) Status() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.opened {
+		return "Unlocked", nil
+	}
+	return "Locked", nil
+}
+
+// Open unlocks the wallet, the passphrase is ignored
+func (w *wallet) Open(passphrase string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.opened = true
+	return nil
+}
+
+// Close locks the wallet
+func (w *wallet) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.opened = false
+	return nil
+}
+
+func (w *wallet) Accounts() []accounts.Account {
+	return []accounts.Account{w.account}
+}
+
+func (w *wallet) Contains(account accounts.Account) bool {
+	return account.Address == w.account.Address && (account.URL == (accounts.URL{}) || account.URL == w.account.URL)
+}
+
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+func (w *wallet) SelfDerive(bases []accounts.DerivationPath, chain ethereum.ChainStateReader) {}
+
+func (w *wallet) signHash(account accounts.Account, hash []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	w.mu.RLock()
+	opened := w.opened
+	w.mu.RUnlock()
+	if !opened {
+		return nil, errWalletLocked
+	}
+	return w.signer.Sign(hash)
+}
+
+func (w *wallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	return w.signHash(account, crypto.Keccak256(data))
+}
+
+func (w *wallet) SignDataWithPassphrase(account accounts.Account, passphrase, mimeType string, data []byte) ([]byte, error) {
+	return w.SignData(account, mimeType, data)
+}
+
+func (w *wallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	return w.signHash(account, accounts.TextHash(text))
+}
+
+func (w *wallet) SignTextWithPassphrase(account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	return w.SignText(account, text)
+}
+
+// SignTx implements accounts.Wallet, private transactions being signed with V 37/38
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var signer types.Signer
+	switch {
+	case tx.IsPrivate():
+		signer = types.QuorumPrivateTxSigner{}
+	case chainID != nil:
+		signer = types.NewEIP155Signer(chainID)
+	default:
+		signer = types.HomesteadSigner{}
+	}
+	sig, err := w.signHash(account, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, chainID)
+}
@@ -0,0 +1,241 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient holds a single key, returning high S values as the services may do
+type fakeClient struct {
+	key           *ecdsa.PrivateKey
+	publicKeyHits int
+}
+
+func (c *fakeClient) PublicKey(_ context.Context, _ string) (*ecdsa.PublicKey, error) {
+	c.publicKeyHits++
+	return &c.key.PublicKey, nil
+}
+
+func (c *fakeClient) Sign(_ context.Context, _ string, digest []byte) (*big.Int, *big.Int, error) {
+	sig, err := crypto.Sign(digest, c.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := new(big.Int).SetBytes(sig[32:64])
+	return new(big.Int).SetBytes(sig[:32]), s.Sub(secp256k1N, s), nil
+}
+
+func newTestSigner(t *testing.T) (*Signer, *fakeClient) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	client := &fakeClient{key: key}
+	return NewSigner(client, "key", accounts.URL{Scheme: AWSScheme, Path: "us-east-1/key"}), client
+}
+
+func TestSigner_Sign(t *testing.T) {
+	signer, client := newTestSigner(t)
+
+	for i := 0; i < 10; i++ {
+		hash := crypto.Keccak256([]byte{byte(i)})
+		sig, err := signer.Sign(hash)
+		require.NoError(t, err)
+
+		pubkey, err := crypto.SigToPub(hash, sig)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(client.key.PublicKey), crypto.PubkeyToAddress(*pubkey))
+		assert.True(t, crypto.ValidateSignatureValues(sig[64], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), true), "S must be in the lower half of the curve order")
+	}
+	assert.Equal(t, 1, client.publicKeyHits, "public key must be cached")
+	assert.Equal(t, crypto.PubkeyToAddress(client.key.PublicKey), signer.Address())
+}
+
+func TestSigner_SignWhenInvalidHash(t *testing.T) {
+	signer, _ := newTestSigner(t)
+
+	_, err := signer.Sign([]byte("not a hash"))
+
+	assert.Error(t, err)
+}
+
+func TestWallet_SignTx(t *testing.T) {
+	signer, client := newTestSigner(t)
+	address := crypto.PubkeyToAddress(client.key.PublicKey)
+	w := &wallet{signer: signer, account: accounts.Account{Address: address, URL: signer.URL()}, opened: true}
+	chainID := big.NewInt(10)
+
+	signed, err := w.SignTx(w.account, types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(0), nil), chainID)
+	require.NoError(t, err)
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+
+	private := types.NewTransaction(1, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), common.BytesToEncryptedPayloadHash([]byte("payload")).Bytes())
+	private.SetPrivate()
+	signed, err = w.SignTx(w.account, private, nil)
+	require.NoError(t, err)
+	assert.True(t, signed.IsPrivate())
+	sender, err = types.Sender(types.NewEIP155Signer(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+
+	_, err = w.SignTx(accounts.Account{Address: common.Address{1}}, signed, chainID)
+	assert.Equal(t, accounts.ErrUnknownAccount, err)
+}
+
+func TestWallet_lockedUntilOpened(t *testing.T) {
+	signer, client := newTestSigner(t)
+	w := &wallet{signer: signer, account: accounts.Account{Address: crypto.PubkeyToAddress(client.key.PublicKey), URL: signer.URL()}}
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(0), nil)
+
+	_, err := w.SignTx(w.account, tx, big.NewInt(10))
+	assert.Equal(t, errWalletLocked, err)
+	_, err = w.SignTxWithPassphrase(w.account, "", tx, big.NewInt(10))
+	assert.Equal(t, errWalletLocked, err)
+	status, _ := w.Status()
+	assert.Equal(t, "Locked", status)
+
+	require.NoError(t, w.Open(""))
+	_, err = w.SignTx(w.account, tx, big.NewInt(10))
+	assert.NoError(t, err)
+
+	require.NoError(t, w.Close())
+	_, err = w.SignText(w.account, []byte("text"))
+	assert.Equal(t, errWalletLocked, err)
+}
+
+func TestBackend_walletArrivesOnceOpened(t *testing.T) {
+	signer, client := newTestSigner(t)
+	events := make(chan accounts.WalletEvent, 1)
+	b := newBackend(nil, false)
+	defer b.Close()
+	sub := b.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	go b.open(signer)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, accounts.WalletArrived, event.Kind)
+		assert.Equal(t, []accounts.Account{{Address: crypto.PubkeyToAddress(client.key.PublicKey), URL: signer.URL()}}, event.Wallet.Accounts())
+	case <-time.After(5 * time.Second):
+		t.Fatal("wallet did not arrive")
+	}
+	assert.Len(t, b.Wallets(), 1)
+}
+
+func TestNewBackend_whenInvalidURL(t *testing.T) {
+	for _, rawurl := range []string{"keystore://key", "awskms:///key", "azurekv://vault/key"} {
+		_, err := NewBackend([]string{rawurl}, false)
+
+		assert.Error(t, err, rawurl)
+	}
+}
+
+func TestParsePublicKeyInfo(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	curve, err := asn1.Marshal(oidCurveSecp256k1)
+	require.NoError(t, err)
+	der, err := asn1.Marshal(publicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 8 * 65},
+	})
+	require.NoError(t, err)
+
+	pubkey, err := parsePublicKeyInfo(der)
+
+	require.NoError(t, err)
+	assert.Equal(t, crypto.FromECDSAPub(&key.PublicKey), crypto.FromECDSAPub(pubkey))
+}
+
+func TestParseSignature(t *testing.T) {
+	der, err := asn1.Marshal(ecdsaSignature{R: big.NewInt(1), S: big.NewInt(2)})
+	require.NoError(t, err)
+
+	r, s, err := parseSignature(der)
+
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), r)
+	assert.Equal(t, big.NewInt(2), s)
+	_, _, err = parseSignature(append(der, 0))
+	assert.Error(t, err)
+}
+
+func TestAzureClient(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	encoded := crypto.FromECDSAPub(&key.PublicKey)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("api-version") != azureKeyVaultAPIVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/keys/key/version":
+			json.NewEncoder(w).Encode(map[string]interface{}{"key": azureJSONWebKey{
+				Kty: "EC-HSM",
+				Crv: "P-256K",
+				X:   base64.RawURLEncoding.EncodeToString(encoded[1:33]),
+				Y:   base64.RawURLEncoding.EncodeToString(encoded[33:]),
+			}})
+		case "/keys/key/version/sign":
+			var req struct{ Alg, Value string }
+			json.NewDecoder(r.Body).Decode(&req)
+			digest, _ := base64.RawURLEncoding.DecodeString(req.Value)
+			sig, _ := crypto.Sign(digest, key)
+			json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(sig[:64])})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"KeyNotFound","message":"key not found"}}`))
+		}
+	}))
+	defer server.Close()
+	client := &azureClient{
+		vaultURL: server.URL,
+		tokens:   &azureTokenSource{current: "token", expiry: time.Now().Add(time.Hour)},
+		client:   server.Client(),
+	}
+	signer := NewSigner(client, "key/version", accounts.URL{Scheme: AzureScheme, Path: "vault/key/version"})
+	hash := crypto.Keccak256([]byte("data"))
+
+	sig, err := signer.Sign(hash)
+
+	require.NoError(t, err)
+	pubkey, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pubkey))
+	_, err = client.PublicKey(context.Background(), "unknown/version")
+	assert.EqualError(t, err, "KeyNotFound: key not found")
+}
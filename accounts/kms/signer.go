@@ -0,0 +1,199 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package kms implements the signing with secp256k1 keys held by cloud key management
+// services (AWS KMS and Azure Key Vault), for the transactions of the node accounts and
+// for the Istanbul messages and seals.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// AWSScheme is the scheme of the URLs of the AWS KMS keys: awskms://<region>/<key id, ARN or alias>
+	AWSScheme = "awskms"
+	// AzureScheme is the scheme of the URLs of the Azure Key Vault keys: azurekv://<vault name>/<key name>/<key version>
+	AzureScheme = "azurekv"
+
+	// DefaultTimeout is the timeout of the requests to the key management services
+	DefaultTimeout = 10 * time.Second
+)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// Client is a key management service holding secp256k1 keys
+type Client interface {
+	// PublicKey retrieves the public key of the key
+	PublicKey(ctx context.Context, keyID string) (*ecdsa.PublicKey, error)
+	// Sign signs digest with the key, returning the R and S values of the signature
+	Sign(ctx context.Context, keyID string, digest []byte) (r, s *big.Int, err error)
+}
+
+// Signer signs with a key of a key management service, its public key is retrieved once
+// then cached.
+type Signer struct {
+	client  Client
+	keyID   string
+	url     accounts.URL
+	timeout time.Duration
+
+	mu     sync.Mutex
+	pubkey *ecdsa.PublicKey
+}
+
+// NewSigner creates a signer with the key keyID of client, identified by url
+func NewSigner(client Client, keyID string, url accounts.URL) *Signer {
+	return &Signer{
+		client:  client,
+		keyID:   keyID,
+		url:     url,
+		timeout: DefaultTimeout,
+	}
+}
+
+// NewSignerFromURL creates a signer with the key at rawurl, e.g.: awskms://us-east-1/alias/validator,
+// and retrieves its public key
+func NewSignerFromURL(ctx context.Context, rawurl string) (*Signer, error) {
+	signer, err := newSignerFromURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := signer.PublicKey(ctx); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+func newSignerFromURL(rawurl string) (*Signer, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key URL %q: %v", rawurl, err)
+	}
+	keyURL := accounts.URL{Scheme: u.Scheme, Path: u.Host + u.Path}
+	keyID := strings.TrimPrefix(u.Path, "/")
+	switch u.Scheme {
+	case AWSScheme:
+		if u.Host == "" || keyID == "" {
+			return nil, fmt.Errorf("invalid key URL %q, expected %s://<region>/<key id>", rawurl, AWSScheme)
+		}
+		client, err := newAWSClient(u.Host)
+		if err != nil {
+			return nil, err
+		}
+		return NewSigner(client, keyID, keyURL), nil
+	case AzureScheme:
+		if parts := strings.Split(keyID, "/"); u.Host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid key URL %q, expected %s://<vault name>/<key name>/<key version>", rawurl, AzureScheme)
+		}
+		return NewSigner(newAzureClient(u.Host), keyID, keyURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported key URL scheme %q, expected %s or %s", u.Scheme, AWSScheme, AzureScheme)
+	}
+}
+
+// URL returns the URL of the key
+func (s *Signer) URL() accounts.URL {
+	return s.url
+}
+
+// PublicKey returns the public key of the key, retrieving it on first use
+func (s *Signer) PublicKey(ctx context.Context) (*ecdsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pubkey == nil {
+		pubkey, err := s.client.PublicKey(ctx, s.keyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the public key of %s: %v", s.url, err)
+		}
+		s.pubkey = pubkey
+	}
+	return s.pubkey, nil
+}
+
+// Address returns the address of the key, the zero address if its public key has not
+// been retrieved yet
+func (s *Signer) Address() common.Address {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pubkey == nil {
+		return common.Address{}
+	}
+	return crypto.PubkeyToAddress(*s.pubkey)
+}
+
+// Sign signs hash, returning the signature in the [R || S || V] format where V is 0 or 1
+func (s *Signer) Sign(hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.SignContext(ctx, hash)
+}
+
+// SignContext is the same as Sign but the requests to the service are bound to ctx
+func (s *Signer) SignContext(ctx context.Context, hash []byte) ([]byte, error) {
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash is required to be exactly %d bytes (%d)", common.HashLength, len(hash))
+	}
+	pubkey, err := s.PublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, sv, err := s.client.Sign(ctx, s.keyID, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with %s: %v", s.url, err)
+	}
+	return RecoverableSignature(hash, r, sv, pubkey)
+}
+
+// RecoverableSignature turns the R and S values of a signature into the [R || S || V]
+// format, normalizing S to the lower half of the curve order as ethereum requires and
+// finding the recovery id V against pubkey
+func RecoverableSignature(hash []byte, r, s *big.Int, pubkey *ecdsa.PublicKey) ([]byte, error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("invalid signature values")
+	}
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	math.ReadBits(r, sig[:32])
+	math.ReadBits(s, sig[32:64])
+	expected := crypto.FromECDSAPub(pubkey)
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		if recovered, err := crypto.Ecrecover(hash, sig); err == nil && bytes.Equal(recovered, expected) {
+			return sig, nil
+		}
+	}
+	return nil, errors.New("signature does not match the public key")
+}
@@ -46,6 +46,11 @@ func (b *Backend) SetPluginService(s plugin.Service) error {
 	return b.wallet().setPluginService(s)
 }
 
+// SetSigningPolicy enforces p for every signing request, nil value disables it
+func (b *Backend) SetSigningPolicy(p plugin.SigningPolicy) {
+	b.wallet().setSigningPolicy(p)
+}
+
 func (b *Backend) TimedUnlock(account accounts.Account, password string, duration time.Duration) error {
 	return b.wallet().timedUnlock(account, password, duration)
 }
@@ -2,6 +2,7 @@ package pluggable
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"sync"
 	"time"
@@ -12,12 +13,33 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	plugin "github.com/ethereum/go-ethereum/plugin/account"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type wallet struct {
 	url           accounts.URL
 	mu            sync.Mutex
 	pluginService plugin.Service
+	signingPolicy plugin.SigningPolicy // nil if no signing policy is enforced
+}
+
+// SigningPolicyError is returned when the signing policy of the account plugin denies a request
+type SigningPolicyError struct {
+	Reason string
+}
+
+func (e *SigningPolicyError) Error() string {
+	if e.Reason == "" {
+		return "signing request denied by policy"
+	}
+	return fmt.Sprintf("signing request denied by policy: %s", e.Reason)
+}
+
+func (w *wallet) setSigningPolicy(p plugin.SigningPolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.signingPolicy = p
 }
 
 func (w *wallet) setPluginService(s plugin.Service) error {
@@ -59,26 +81,71 @@ func (w *wallet) Derive(_ accounts.DerivationPath, _ bool) (accounts.Account, er
 
 func (w *wallet) SelfDerive(_ []accounts.DerivationPath, _ ethereum.ChainStateReader) {}
 
-func (w *wallet) SignData(account accounts.Account, _ string, data []byte) ([]byte, error) {
-	return w.pluginService.Sign(context.Background(), account, crypto.Keccak256(data))
+func (w *wallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	return w.SignDataWithContext(context.Background(), account, mimeType, data)
+}
+
+// SignDataWithContext is the same as SignData but the signing policy is evaluated
+// with the identity of the caller found in ctx
+func (w *wallet) SignDataWithContext(ctx context.Context, account accounts.Account, _ string, data []byte) ([]byte, error) {
+	if err := w.evaluate(ctx, &plugin.SigningRequest{Account: account, Data: data}); err != nil {
+		return nil, err
+	}
+	return w.pluginService.Sign(ctx, account, crypto.Keccak256(data))
 }
 
-func (w *wallet) SignDataWithPassphrase(account accounts.Account, passphrase, _ string, data []byte) ([]byte, error) {
-	return w.pluginService.UnlockAndSign(context.Background(), account, crypto.Keccak256(data), passphrase)
+func (w *wallet) SignDataWithPassphrase(account accounts.Account, passphrase, mimeType string, data []byte) ([]byte, error) {
+	return w.SignDataWithPassphraseWithContext(context.Background(), account, passphrase, mimeType, data)
+}
+
+// SignDataWithPassphraseWithContext is the same as SignDataWithPassphrase but the signing
+// policy is evaluated with the identity of the caller found in ctx
+func (w *wallet) SignDataWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase, _ string, data []byte) ([]byte, error) {
+	if err := w.evaluate(ctx, &plugin.SigningRequest{Account: account, Data: data}); err != nil {
+		return nil, err
+	}
+	return w.pluginService.UnlockAndSign(ctx, account, crypto.Keccak256(data), passphrase)
 }
 
 func (w *wallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
-	return w.pluginService.Sign(context.Background(), account, accounts.TextHash(text))
+	return w.SignTextWithContext(context.Background(), account, text)
+}
+
+// SignTextWithContext is the same as SignText but the signing policy is evaluated
+// with the identity of the caller found in ctx
+func (w *wallet) SignTextWithContext(ctx context.Context, account accounts.Account, text []byte) ([]byte, error) {
+	if err := w.evaluate(ctx, &plugin.SigningRequest{Account: account, Data: text}); err != nil {
+		return nil, err
+	}
+	return w.pluginService.Sign(ctx, account, accounts.TextHash(text))
 }
 
 func (w *wallet) SignTextWithPassphrase(account accounts.Account, passphrase string, text []byte) ([]byte, error) {
-	return w.pluginService.UnlockAndSign(context.Background(), account, accounts.TextHash(text), passphrase)
+	return w.SignTextWithPassphraseWithContext(context.Background(), account, passphrase, text)
+}
+
+// SignTextWithPassphraseWithContext is the same as SignTextWithPassphrase but the signing
+// policy is evaluated with the identity of the caller found in ctx
+func (w *wallet) SignTextWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	if err := w.evaluate(ctx, &plugin.SigningRequest{Account: account, Data: text}); err != nil {
+		return nil, err
+	}
+	return w.pluginService.UnlockAndSign(ctx, account, accounts.TextHash(text), passphrase)
 }
 
 func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTxWithContext(context.Background(), account, tx, chainID)
+}
+
+// SignTxWithContext is the same as SignTx but the signing policy is evaluated
+// with the identity of the caller found in ctx
+func (w *wallet) SignTxWithContext(ctx context.Context, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if err := w.evaluate(ctx, &plugin.SigningRequest{Account: account, Transaction: tx, ChainID: chainID}); err != nil {
+		return nil, err
+	}
 	toSign, signer := prepareTxForSign(tx, chainID)
 
-	sig, err := w.pluginService.Sign(context.Background(), account, toSign.Bytes())
+	sig, err := w.pluginService.Sign(ctx, account, toSign.Bytes())
 	if err != nil {
 		return nil, err
 	}
@@ -87,9 +154,18 @@ func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID
 }
 
 func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTxWithPassphraseWithContext(context.Background(), account, passphrase, tx, chainID)
+}
+
+// SignTxWithPassphraseWithContext is the same as SignTxWithPassphrase but the signing policy
+// is evaluated with the identity of the caller found in ctx
+func (w *wallet) SignTxWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if err := w.evaluate(ctx, &plugin.SigningRequest{Account: account, Transaction: tx, ChainID: chainID}); err != nil {
+		return nil, err
+	}
 	toSign, signer := prepareTxForSign(tx, chainID)
 
-	sig, err := w.pluginService.UnlockAndSign(context.Background(), account, toSign.Bytes(), passphrase)
+	sig, err := w.pluginService.UnlockAndSign(ctx, account, toSign.Bytes(), passphrase)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +173,30 @@ func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase strin
 	return tx.WithSignature(signer, sig)
 }
 
+// evaluate checks the signing request against the policy enforced by the account plugin, if any
+func (w *wallet) evaluate(ctx context.Context, req *plugin.SigningRequest) error {
+	w.mu.Lock()
+	policy := w.signingPolicy
+	w.mu.Unlock()
+	if policy == nil {
+		return nil
+	}
+	req.Identity = rpc.PreauthenticatedTokenFromContext(ctx)
+	if tx := req.Transaction; tx != nil && tx.IsPrivate() {
+		if metadata := accounts.PrivateTxMetadataFromContext(ctx); metadata != nil {
+			req.PrivatePayload = metadata.Payload
+		}
+	}
+	allowed, reason, err := policy.Evaluate(ctx, req)
+	if err != nil {
+		return fmt.Errorf("unable to evaluate signing policy: %v", err)
+	}
+	if !allowed {
+		return &SigningPolicyError{Reason: reason}
+	}
+	return nil
+}
+
 func (w *wallet) timedUnlock(account accounts.Account, password string, duration time.Duration) error {
 	return w.pluginService.TimedUnlock(context.Background(), account, password, duration)
 }
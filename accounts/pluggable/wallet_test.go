@@ -1,6 +1,7 @@
 package pluggable
 
 import (
+	"context"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -10,7 +11,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	plugin "github.com/ethereum/go-ethereum/plugin/account"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/golang/mock/gomock"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -395,3 +399,139 @@ func TestWallet_SignTxWithPassphrase(t *testing.T) {
 		})
 	}
 }
+
+type stubSigningPolicy struct {
+	allowed bool
+	reason  string
+	got     *plugin.SigningRequest
+}
+
+func (p *stubSigningPolicy) Evaluate(_ context.Context, req *plugin.SigningRequest) (bool, string, error) {
+	p.got = req
+	return p.allowed, p.reason, nil
+}
+
+func TestWallet_SignTxWithContext_whenDeniedBySigningPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_plugin.NewMockService(ctrl)
+	mockClient.EXPECT().Sign(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	policy := &stubSigningPolicy{allowed: false, reason: "destination not allowed"}
+	w := validWallet(mockClient)
+	w.setSigningPolicy(policy)
+	identity := &proto.PreAuthenticatedAuthenticationToken{RawToken: []byte("arbitrary token")}
+	ctx := rpc.WithPreauthenticatedToken(context.Background(), identity)
+	toSign := types.NewTransaction(1, acct2.Address, big.NewInt(1), 0, big.NewInt(1), nil)
+
+	_, err := w.SignTxWithContext(ctx, acct1, toSign, nil)
+
+	assert.EqualError(t, err, "signing request denied by policy: destination not allowed")
+	assert.IsType(t, &SigningPolicyError{}, err)
+	assert.Equal(t, acct1, policy.got.Account)
+	assert.Equal(t, toSign, policy.got.Transaction)
+	assert.Equal(t, identity, policy.got.Identity)
+}
+
+func TestWallet_SignTxWithContext_whenPrivateTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_plugin.NewMockService(ctrl)
+	mockClient.EXPECT().Sign(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	policy := &stubSigningPolicy{allowed: false, reason: "payload not allowed"}
+	w := validWallet(mockClient)
+	w.setSigningPolicy(policy)
+	ctx := accounts.WithPrivateTxMetadata(context.Background(), &accounts.PrivateTxMetadata{PrivateFor: []string{"key"}, Payload: []byte("plaintext")})
+	toSign := types.NewTransaction(1, acct2.Address, big.NewInt(0), 0, big.NewInt(0), common.EncryptedPayloadHash{1}.Bytes())
+	toSign.SetPrivate()
+
+	_, err := w.SignTxWithContext(ctx, acct1, toSign, nil)
+
+	assert.IsType(t, &SigningPolicyError{}, err)
+	assert.Equal(t, []byte("plaintext"), policy.got.PrivatePayload, "the policy is evaluated against the plaintext payload")
+}
+
+func TestWallet_SignData_whenAllowedBySigningPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	toSign := []byte("to sign")
+	mockClient := mock_plugin.NewMockService(ctrl)
+	mockClient.EXPECT().Sign(gomock.Any(), acct1, crypto.Keccak256(toSign)).Return([]byte("signed"), nil)
+
+	policy := &stubSigningPolicy{allowed: true}
+	w := validWallet(mockClient)
+	w.setSigningPolicy(policy)
+
+	got, err := w.SignData(acct1, "", toSign)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("signed"), got)
+	assert.Equal(t, toSign, policy.got.Data)
+	assert.Nil(t, policy.got.Identity)
+}
+
+func TestWallet_SignDataWithPassphraseWithContext_whenDeniedBySigningPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_plugin.NewMockService(ctrl)
+	mockClient.EXPECT().UnlockAndSign(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	policy := &stubSigningPolicy{allowed: false, reason: "data not allowed"}
+	w := validWallet(mockClient)
+	w.setSigningPolicy(policy)
+	identity := &proto.PreAuthenticatedAuthenticationToken{RawToken: []byte("arbitrary token")}
+	ctx := rpc.WithPreauthenticatedToken(context.Background(), identity)
+	toSign := []byte("to sign")
+
+	_, err := w.SignDataWithPassphraseWithContext(ctx, acct1, "pwd", "", toSign)
+
+	assert.EqualError(t, err, "signing request denied by policy: data not allowed")
+	assert.Equal(t, toSign, policy.got.Data)
+	assert.Equal(t, identity, policy.got.Identity)
+}
+
+func TestWallet_SignTextWithContext_whenAllowedBySigningPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	toSign := []byte("to sign")
+	mockClient := mock_plugin.NewMockService(ctrl)
+	mockClient.EXPECT().Sign(gomock.Any(), acct1, accounts.TextHash(toSign)).Return([]byte("signed"), nil)
+
+	policy := &stubSigningPolicy{allowed: true}
+	w := validWallet(mockClient)
+	w.setSigningPolicy(policy)
+	identity := &proto.PreAuthenticatedAuthenticationToken{RawToken: []byte("arbitrary token")}
+	ctx := rpc.WithPreauthenticatedToken(context.Background(), identity)
+
+	got, err := w.SignTextWithContext(ctx, acct1, toSign)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("signed"), got)
+	assert.Equal(t, toSign, policy.got.Data)
+	assert.Equal(t, identity, policy.got.Identity)
+}
+
+func TestWallet_SignTextWithPassphraseWithContext_whenDeniedBySigningPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_plugin.NewMockService(ctrl)
+	mockClient.EXPECT().UnlockAndSign(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	policy := &stubSigningPolicy{allowed: false, reason: "text not allowed"}
+	w := validWallet(mockClient)
+	w.setSigningPolicy(policy)
+	identity := &proto.PreAuthenticatedAuthenticationToken{RawToken: []byte("arbitrary token")}
+	ctx := rpc.WithPreauthenticatedToken(context.Background(), identity)
+
+	_, err := w.SignTextWithPassphraseWithContext(ctx, acct1, "pwd", []byte("to sign"))
+
+	assert.EqualError(t, err, "signing request denied by policy: text not allowed")
+	assert.Equal(t, identity, policy.got.Identity)
+}
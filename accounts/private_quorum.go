@@ -0,0 +1,54 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/private/engine"
+)
+
+// Quorum
+
+// PrivateTxMetadata describes a private transaction to the wallets signing it. The data
+// of the transaction being signed is the hash of the encrypted payload stored in the
+// private transaction manager, the metadata allows signers (e.g.: clef) to evaluate their
+// policies against the parties of the transaction.
+type PrivateTxMetadata struct {
+	PrivateFrom string                 `json:"privateFrom,omitempty"`
+	PrivateFor  []string               `json:"privateFor"`
+	PrivacyFlag engine.PrivacyFlagType `json:"privacyFlag"`
+	// Payload is the plaintext payload of the transaction, nil if it is unknown, e.g.:
+	// the caller signs a transaction whose payload is already stored. It is only given to
+	// the wallets of the node, the external signers do not receive it.
+	Payload []byte `json:"-"`
+}
+
+type privateTxMetadataKey struct{}
+
+// WithPrivateTxMetadata returns a copy of ctx carrying the metadata of the private
+// transaction to sign
+func WithPrivateTxMetadata(ctx context.Context, metadata *PrivateTxMetadata) context.Context {
+	return context.WithValue(ctx, privateTxMetadataKey{}, metadata)
+}
+
+// PrivateTxMetadataFromContext returns the metadata of the private transaction to sign
+// carried by ctx, nil if there is none
+func PrivateTxMetadataFromContext(ctx context.Context) *PrivateTxMetadata {
+	metadata, _ := ctx.Value(privateTxMetadataKey{}).(*PrivateTxMetadata)
+	return metadata
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package threshold
+
+import (
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/kms"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BackendType is the reflect type of the threshold signing backend
+var BackendType = reflect.TypeOf(&Backend{})
+
+// Backend is an accounts.Backend with a wallet per registered threshold signer, the
+// signing services plugging their signers in with Register. The wallets are the ones of
+// the key management service backend, unlocked as the parties authenticate the node.
+type Backend struct {
+	*kms.Backend
+}
+
+func NewBackend() *Backend {
+	b, _ := kms.NewBackend(nil, true)
+	return &Backend{Backend: b}
+}
+
+// Register adds the wallet of the account of signer
+func (b *Backend) Register(signer *Signer) error {
+	if err := b.Backend.Register(signer, signer.Address()); err != nil {
+		return err
+	}
+	log.Info("Registered threshold signing account", "address", signer.Address(), "threshold", signer.threshold, "parties", len(signer.parties))
+	return nil
+}
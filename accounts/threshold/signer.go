@@ -0,0 +1,237 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package threshold is the hook to sign with keys shared between the parties of a
+// threshold signing service (e.g.: MPC), for treasury-class accounts whose key must
+// never be held by a single party. A signature is produced by a multi-round ceremony
+// between a threshold of the parties, the partial signatures of the last round being
+// assembled into the final signature.
+package threshold
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/kms"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Scheme is a threshold signature scheme, implemented by the signing service
+type Scheme interface {
+	// Rounds returns the number of rounds of a ceremony, the messages of the parties in
+	// the last round being their partial signatures
+	Rounds() int
+
+	// Assemble assembles the signature of the ceremony from the partial signatures of
+	// the parties by party ID, in the [R || S || V] format where V is 0 or 1
+	Assemble(ceremony *Ceremony, partials map[string][]byte) ([]byte, error)
+}
+
+// Party holds a share of the key, implemented by the signing service
+type Party interface {
+	// ID identifies the party in the ceremonies
+	ID() string
+
+	// Round runs a round of the ceremony, numbered from 1, with the messages of the
+	// parties of the ceremony in the previous round by party ID (nil in the first round),
+	// returning the message of the party in this round
+	Round(ctx context.Context, ceremony *Ceremony, round int, messages map[string][]byte) ([]byte, error)
+}
+
+// Ceremony is a signing ceremony
+type Ceremony struct {
+	ID      string
+	Account common.Address
+	Hash    []byte   // the hash to sign
+	Parties []string // the IDs of the parties taking part in the ceremony
+}
+
+// PartyError is the failure of a party in a ceremony, which is then retried without it
+type PartyError struct {
+	Party string
+	Round int
+	Err   error
+}
+
+func (e *PartyError) Error() string {
+	return fmt.Sprintf("party %s failed in round %d: %v", e.Party, e.Round, e.Err)
+}
+
+func (e *PartyError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultTimeout is the timeout of a signing ceremony
+const DefaultTimeout = time.Minute
+
+// Signer signs with the key of an account shared between parties
+type Signer struct {
+	address   common.Address
+	scheme    Scheme
+	parties   []Party
+	threshold int
+	timeout   time.Duration
+}
+
+// NewSigner creates a signer with the key of address shared between parties, threshold
+// of them taking part in the ceremonies
+func NewSigner(address common.Address, scheme Scheme, parties []Party, threshold int) (*Signer, error) {
+	if threshold < 1 || threshold > len(parties) {
+		return nil, fmt.Errorf("invalid threshold %d of %d parties", threshold, len(parties))
+	}
+	ids := make(map[string]bool)
+	for _, party := range parties {
+		if ids[party.ID()] {
+			return nil, fmt.Errorf("duplicate party %s", party.ID())
+		}
+		ids[party.ID()] = true
+	}
+	return &Signer{
+		address:   address,
+		scheme:    scheme,
+		parties:   parties,
+		threshold: threshold,
+		timeout:   DefaultTimeout,
+	}, nil
+}
+
+// Address returns the address of the shared key
+func (s *Signer) Address() common.Address {
+	return s.address
+}
+
+// URL returns the URL of the signer
+func (s *Signer) URL() accounts.URL {
+	return accounts.URL{Scheme: "threshold", Path: s.address.Hex()}
+}
+
+// Sign signs hash, returning the signature in the [R || S || V] format where V is 0 or 1.
+// A ceremony failing because of a party is retried with the other parties, as long as
+// there are enough of them.
+func (s *Signer) Sign(hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.SignContext(ctx, hash)
+}
+
+// SignContext is the same as Sign but the ceremonies are bound to ctx
+func (s *Signer) SignContext(ctx context.Context, hash []byte) ([]byte, error) {
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash is required to be exactly %d bytes (%d)", common.HashLength, len(hash))
+	}
+	failed := make(map[string]bool)
+	for {
+		parties := make([]Party, 0, s.threshold)
+		for _, party := range s.parties {
+			if !failed[party.ID()] && len(parties) < s.threshold {
+				parties = append(parties, party)
+			}
+		}
+		if len(parties) < s.threshold {
+			return nil, fmt.Errorf("not enough parties to sign, %d failed", len(failed))
+		}
+		sig, err := s.ceremony(ctx, hash, parties)
+		var partyErr *PartyError
+		if err == nil || !errors.As(err, &partyErr) || ctx.Err() != nil {
+			return sig, err
+		}
+		log.Warn("Threshold signing ceremony failed, retrying without the party", "account", s.address, "err", err)
+		failed[partyErr.Party] = true
+	}
+}
+
+// ceremony runs a ceremony between parties
+func (s *Signer) ceremony(ctx context.Context, hash []byte, parties []Party) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	ceremony := &Ceremony{
+		ID:      hex.EncodeToString(id),
+		Account: s.address,
+		Hash:    common.CopyBytes(hash),
+	}
+	for _, party := range parties {
+		ceremony.Parties = append(ceremony.Parties, party.ID())
+	}
+	var messages map[string][]byte
+	for round := 1; round <= s.scheme.Rounds(); round++ {
+		var err error
+		if messages, err = runRound(ctx, ceremony, round, parties, messages); err != nil {
+			return nil, err
+		}
+	}
+	sig, err := s.scheme.Assemble(ceremony, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble the signature: %v", err)
+	}
+	// the service is not trusted to assemble a valid signature
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != s.address {
+		return nil, fmt.Errorf("signature of %s, expected %s", signer.Hex(), s.address.Hex())
+	}
+	// the schemes are not required to produce the low S values ethereum requires
+	return kms.RecoverableSignature(hash, new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), pubkey)
+}
+
+// runRound runs a round of ceremony with all the parties concurrently, returning their messages
+func runRound(ctx context.Context, ceremony *Ceremony, round int, parties []Party, previous map[string][]byte) (map[string][]byte, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		messages = make(map[string][]byte, len(parties))
+		firstErr error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for _, party := range parties {
+		wg.Add(1)
+		go func(party Party) {
+			defer wg.Done()
+			message, err := party.Round(ctx, ceremony, round, previous)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = &PartyError{Party: party.ID(), Round: round, Err: err}
+					cancel()
+				}
+				return
+			}
+			messages[party.ID()] = message
+		}(party)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return messages, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package threshold
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testScheme runs a commitment round then a signing round, every test party holding the
+// whole key
+type testScheme struct{}
+
+func (testScheme) Rounds() int {
+	return 2
+}
+
+func (testScheme) Assemble(ceremony *Ceremony, partials map[string][]byte) ([]byte, error) {
+	if len(partials) != len(ceremony.Parties) {
+		return nil, errors.New("missing partial signatures")
+	}
+	return partials[ceremony.Parties[0]], nil
+}
+
+type testParty struct {
+	id     string
+	key    *ecdsa.PrivateKey
+	fail   bool
+	highS  bool // the partial signatures have the high S value
+	rounds []int
+}
+
+func (p *testParty) ID() string {
+	return p.id
+}
+
+func (p *testParty) Round(_ context.Context, ceremony *Ceremony, round int, messages map[string][]byte) ([]byte, error) {
+	p.rounds = append(p.rounds, round)
+	switch round {
+	case 1:
+		if messages != nil {
+			return nil, errors.New("unexpected messages in the first round")
+		}
+		return crypto.Keccak256([]byte(p.id)), nil
+	default:
+		if p.fail {
+			return nil, errors.New("unavailable")
+		}
+		for _, id := range ceremony.Parties {
+			if !bytes.Equal(crypto.Keccak256([]byte(id)), messages[id]) {
+				return nil, errors.New("missing commitment of " + id)
+			}
+		}
+		sig, err := crypto.Sign(ceremony.Hash, p.key)
+		if err != nil || !p.highS {
+			return sig, err
+		}
+		s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(sig[32:64]))
+		copy(sig[32:64], common.LeftPadBytes(s.Bytes(), 32))
+		sig[64] ^= 1
+		return sig, nil
+	}
+}
+
+func newTestSigner(t *testing.T, threshold int, parties ...*testParty) (*Signer, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	var ps []Party
+	for _, party := range parties {
+		party.key = key
+		ps = append(ps, party)
+	}
+	signer, err := NewSigner(crypto.PubkeyToAddress(key.PublicKey), testScheme{}, ps, threshold)
+	require.NoError(t, err)
+	return signer, key
+}
+
+func TestSigner_Sign(t *testing.T) {
+	a, b, c := &testParty{id: "a"}, &testParty{id: "b"}, &testParty{id: "c"}
+	signer, key := newTestSigner(t, 2, a, b, c)
+	hash := crypto.Keccak256([]byte("data"))
+
+	sig, err := signer.Sign(hash)
+
+	require.NoError(t, err)
+	pubkey, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pubkey))
+	assert.Equal(t, []int{1, 2}, a.rounds)
+	assert.Equal(t, []int{1, 2}, b.rounds)
+	assert.Empty(t, c.rounds, "only threshold parties take part")
+}
+
+func TestSigner_SignNormalizesHighS(t *testing.T) {
+	signer, key := newTestSigner(t, 1, &testParty{id: "a", highS: true})
+	hash := crypto.Keccak256([]byte("data"))
+
+	sig, err := signer.Sign(hash)
+
+	require.NoError(t, err)
+	assert.True(t, crypto.ValidateSignatureValues(sig[64], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), true), "S is not normalized")
+	pubkey, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pubkey))
+}
+
+func TestSigner_SignRetriesWithoutFailedParty(t *testing.T) {
+	a, b, c := &testParty{id: "a", fail: true}, &testParty{id: "b"}, &testParty{id: "c"}
+	signer, _ := newTestSigner(t, 2, a, b, c)
+
+	_, err := signer.Sign(crypto.Keccak256([]byte("data")))
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, a.rounds)
+	assert.Equal(t, []int{1, 2, 1, 2}, b.rounds)
+	assert.Equal(t, []int{1, 2}, c.rounds)
+}
+
+func TestSigner_SignWhenNotEnoughParties(t *testing.T) {
+	signer, _ := newTestSigner(t, 2, &testParty{id: "a", fail: true}, &testParty{id: "b"})
+
+	_, err := signer.Sign(crypto.Keccak256([]byte("data")))
+
+	assert.EqualError(t, err, "not enough parties to sign, 1 failed")
+}
+
+func TestSigner_SignWhenSignatureOfAnotherKey(t *testing.T) {
+	party := &testParty{id: "a"}
+	signer, _ := newTestSigner(t, 1, party)
+	party.key, _ = crypto.GenerateKey()
+
+	_, err := signer.Sign(crypto.Keccak256([]byte("data")))
+
+	assert.Error(t, err)
+}
+
+func TestNewSigner_whenInvalid(t *testing.T) {
+	_, err := NewSigner(common.Address{1}, testScheme{}, []Party{&testParty{id: "a"}}, 2)
+	assert.EqualError(t, err, "invalid threshold 2 of 1 parties")
+
+	_, err = NewSigner(common.Address{1}, testScheme{}, []Party{&testParty{id: "a"}, &testParty{id: "a"}}, 1)
+	assert.EqualError(t, err, "duplicate party a")
+}
+
+func TestBackend_Register(t *testing.T) {
+	signer, key := newTestSigner(t, 1, &testParty{id: "a"})
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	b := NewBackend()
+	events := make(chan accounts.WalletEvent, 1)
+	sub := b.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	require.NoError(t, b.Register(signer))
+	assert.EqualError(t, b.Register(signer), "account "+address.Hex()+" already registered")
+
+	event := <-events
+	assert.Equal(t, accounts.WalletArrived, event.Kind)
+	require.Len(t, b.Wallets(), 1)
+	w := b.Wallets()[0]
+	private := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), common.BytesToEncryptedPayloadHash([]byte("payload")).Bytes())
+	private.SetPrivate()
+	signed, err := w.SignTx(accounts.Account{Address: address}, private, nil)
+	require.NoError(t, err)
+	assert.True(t, signed.IsPrivate())
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(10)), signed)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+}
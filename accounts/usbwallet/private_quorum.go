@@ -0,0 +1,50 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Quorum
+
+// deviceTxSignature returns the signer of a transaction signed by a device, chosen from
+// the chain ID and the privacy of the transaction, and the signature of the device
+// transformed into the [R || S || V] format with V 0/1 expected by the signer
+func deviceTxSignature(tx *types.Transaction, chainID *big.Int, signature []byte) (types.Signer, []byte) {
+	switch {
+	case tx.IsPrivate():
+		return types.QuorumPrivateTxSigner{}, privateTxSignature(signature)
+	case chainID == nil:
+		return new(types.HomesteadSigner), signature
+	default:
+		signature[64] -= byte(chainID.Uint64()*2 + 35)
+		return types.NewEIP155Signer(chainID), signature
+	}
+}
+
+// privateTxSignature turns the signature of a private transaction returned by a device,
+// whose V is 27/28 as the transaction is signed in Homestead mode, into the [R || S || V]
+// format with V 0/1 expected by the QuorumPrivateTxSigner
+func privateTxSignature(signature []byte) []byte {
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+	return signature
+}
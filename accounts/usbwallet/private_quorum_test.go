@@ -0,0 +1,65 @@
+package usbwallet
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceTxSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(10)
+	testCases := []struct {
+		name    string
+		private bool
+		parity  byte
+		// deviceV is the V of the signature returned by the device from the recovery id
+		deviceV func(recID byte) byte
+		wantV   int64
+	}{
+		{name: "private, even", private: true, parity: 0, deviceV: func(recID byte) byte { return recID + 27 }, wantV: 37},
+		{name: "private, odd", private: true, parity: 1, deviceV: func(recID byte) byte { return recID + 27 }, wantV: 38},
+		{name: "public, even", private: false, parity: 0, deviceV: func(recID byte) byte { return recID + 10*2 + 35 }, wantV: 10*2 + 35},
+		{name: "public, odd", private: false, parity: 1, deviceV: func(recID byte) byte { return recID + 10*2 + 35 }, wantV: 10*2 + 36},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// find a transaction whose signature has the parity of the test case
+			var (
+				tx        *types.Transaction
+				signature []byte
+				signer    types.Signer = types.NewEIP155Signer(chainID)
+			)
+			if tc.private {
+				signer = types.HomesteadSigner{}
+			}
+			for nonce := uint64(0); signature == nil || signature[64] != tc.parity; nonce++ {
+				tx = types.NewTransaction(nonce, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+				if tc.private {
+					tx.SetPrivate()
+				}
+				hash := signer.Hash(tx)
+				signature, err = crypto.Sign(hash[:], key)
+				require.NoError(t, err)
+			}
+			signature[64] = tc.deviceV(signature[64])
+
+			signer, signature = deviceTxSignature(tx, chainID, signature)
+			signed, err := tx.WithSignature(signer, signature)
+
+			require.NoError(t, err)
+			v, _, _ := signed.RawSignatureValues()
+			assert.Equal(t, tc.wantV, v.Int64())
+			assert.Equal(t, tc.private, signed.IsPrivate())
+			sender, err := types.Sender(signer, signed)
+			require.NoError(t, err)
+			assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender)
+		})
+	}
+}
@@ -19,7 +19,6 @@ package usbwallet
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -550,9 +549,13 @@ func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID
 	w.stateLock.RLock() // Comms have own mutex, this is for the state fields
 	defer w.stateLock.RUnlock()
 
+	// Quorum
+	// private transactions are signed in Homestead mode, the V value of the signature
+	// then being set to 37/38 to mark them private
 	if tx.IsPrivate() {
-		return nil, errors.New("Signing Quorum Private transactions with a USB wallet not yet supported")
+		chainID = nil
 	}
+	// /Quorum
 
 	// If the wallet is closed, abort
 	if w.device == nil {
@@ -0,0 +1,209 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/raft"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var (
+	backupCommand = cli.Command{
+		Action:    utils.MigrateFlags(backup),
+		Name:      "backup",
+		Usage:     "Take a consistent backup of a running node",
+		ArgsUsage: "<dir> [endpoint]",
+		Flags:     append([]cli.Flag{utils.DataDirFlag}, rpcClientFlags...),
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The backup command connects to a running node (by default through the IPC endpoint
+of the data directory) and makes it write a consistent backup into <dir>, on the
+host of the node: the chain database (including the private states), the raft state,
+the permissioning files and the journal of the pending local transactions.
+
+The writes of the node are paused while the snapshot of the chain is taken. The
+backup contains a manifest with the checksums of its files, verified on restore.`,
+	}
+	restoreCommand = cli.Command{
+		Action:    utils.MigrateFlags(restore),
+		Name:      "restore",
+		Usage:     "Restore a backup into an empty data directory",
+		ArgsUsage: "<dir>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.RaftLogDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+			utils.TxPoolJournalFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The restore command verifies the integrity of the backup in <dir> against its
+manifest, restores it into the data directory, which must not contain a chain
+database, then checks the head block of the restored chain.
+
+The node must be stopped.`,
+	}
+)
+
+// backup is the backup command.
+func backup(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		utils.Fatalf("This command requires the backup directory as argument.")
+	}
+	dir, err := filepath.Abs(ctx.Args().Get(0))
+	if err != nil {
+		utils.Fatalf("Invalid backup directory: %v", err)
+	}
+	endpoint := ctx.Args().Get(1)
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		endpoint = filepath.Join(path, "geth.ipc")
+	}
+	client, err := dialRPC(endpoint, ctx)
+	if err != nil {
+		utils.Fatalf("Unable to attach to geth: %v", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	var manifest eth.BackupManifest
+	if err := client.Call(&manifest, "admin_backup", dir); err != nil {
+		utils.Fatalf("Backup failed: %v", err)
+	}
+	fmt.Printf("Backup of block #%d (%x) written to %s in %v\n", manifest.Block, manifest.BlockHash, dir, time.Since(start))
+	return nil
+}
+
+// restore is the restore command.
+func restore(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		utils.Fatalf("This command requires the backup directory as argument.")
+	}
+	dir := ctx.Args().First()
+	manifest, err := eth.VerifyBackup(dir)
+	if err != nil {
+		utils.Fatalf("Backup integrity check failed: %v", err)
+	}
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+
+	if _, err := os.Stat(stack.ResolvePath("chaindata")); err == nil {
+		utils.Fatalf("A chain database already exists in %s, restore into an empty data directory", stack.DataDir())
+	}
+	start := time.Now()
+	if err := restoreChainData(ctx, stack, filepath.Join(dir, eth.BackupChainDataFile), manifest); err != nil {
+		utils.Fatalf("Chain database restore failed: %v", err)
+	}
+	if raftDir := filepath.Join(dir, raft.BackupDir); fileExists(raftDir) {
+		if err := copyTree(raftDir, cfg.Node.RaftLogDir); err != nil {
+			utils.Fatalf("Raft state restore failed: %v", err)
+		}
+	}
+	if journal := filepath.Join(dir, eth.BackupTxJournalFile); fileExists(journal) && cfg.Eth.TxPool.Journal != "" {
+		if err := copyTree(journal, stack.ResolvePath(cfg.Eth.TxPool.Journal)); err != nil {
+			utils.Fatalf("Transaction journal restore failed: %v", err)
+		}
+	}
+	if permissionDir := filepath.Join(dir, eth.BackupPermissionDir); fileExists(permissionDir) {
+		if err := copyTree(permissionDir, stack.DataDir()); err != nil {
+			utils.Fatalf("Permissioning files restore failed: %v", err)
+		}
+	}
+	fmt.Printf("Restored block #%d (%x) into %s in %v\n", manifest.Block, manifest.BlockHash, stack.DataDir(), time.Since(start))
+	return nil
+}
+
+// restoreChainData imports the chain database export at path and checks its head block
+func restoreChainData(ctx *cli.Context, stack *node.Node, path string, manifest *eth.BackupManifest) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	if err := rawdb.ImportDatabase(bufio.NewReader(f), db); err != nil {
+		return err
+	}
+	head := rawdb.ReadHeadBlockHash(db)
+	if head != manifest.BlockHash {
+		return fmt.Errorf("head block %x, expected %x", head, manifest.BlockHash)
+	}
+	if number := rawdb.ReadHeaderNumber(db, head); number == nil || *number != manifest.Block || rawdb.ReadBlock(db, head, *number) == nil {
+		return fmt.Errorf("head block #%d %x missing", manifest.Block, head)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// copyTree copies the file or directory at src to dst, failing rather than overwriting
+// existing files
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0700)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		if _, err := io.Copy(out, in); err != nil {
+			return err
+		}
+		return out.Close()
+	})
+}
@@ -27,13 +27,18 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common/http"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/extension/privacyExtension"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/les"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/plugin"
 	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/publisher"
+	"github.com/ethereum/go-ethereum/relay"
 	"github.com/naoina/toml"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -91,6 +96,20 @@ type gethConfig struct {
 	Shh      whisperDeprecatedConfig
 	Node     node.Config
 	Ethstats ethstatsConfig
+	Quorum   quorumConfig
+}
+
+// Quorum
+// quorumConfig holds the Quorum options which belong neither to the eth nor to the node
+// config, so that every Quorum option can be set in the TOML configuration file.
+type quorumConfig struct {
+	// PrivateTransactionManager is the connection to the private transaction manager. The
+	// PRIVATE_CONFIG environment variable takes precedence over it.
+	PrivateTransactionManager *http.Config `toml:",omitempty"`
+	Raft                      utils.RaftConfig
+	PluginVerification        utils.PluginVerificationConfig
+	Publisher                 publisher.Config
+	Relay                     relay.Config
 }
 
 func loadConfig(file string, cfg *gethConfig) error {
@@ -120,16 +139,11 @@ func defaultNodeConfig() node.Config {
 
 // makeConfigNode loads geth configuration and creates a blank node instance.
 func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
-	// Quorum: Must occur before setQuorumConfig, as it needs an initialised PTM to be enabled
-	// 		   Extension Service and Multitenancy feature validation also depend on PTM availability
-	if err := quorumInitialisePrivacy(ctx); err != nil {
-		utils.Fatalf("Error initialising Private Transaction Manager: %s", err.Error())
-	}
-
 	// Load defaults.
 	cfg := gethConfig{
-		Eth:  eth.DefaultConfig,
-		Node: defaultNodeConfig(),
+		Eth:    eth.DefaultConfig,
+		Node:   defaultNodeConfig(),
+		Quorum: quorumConfig{Raft: utils.DefaultRaftConfig, Publisher: publisher.DefaultConfig, Relay: relay.DefaultConfig},
 	}
 
 	// Load config file.
@@ -143,8 +157,16 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 		}
 	}
 
+	// Quorum: Must occur before setQuorumConfig, as it needs an initialised PTM to be enabled
+	// 		   Extension Service and Multitenancy feature validation also depend on PTM availability
+	if err := quorumInitialisePrivacy(ctx, &cfg.Quorum); err != nil {
+		utils.Fatalf("Error initialising Private Transaction Manager: %s", err.Error())
+	}
+
 	// Apply flags.
 	utils.SetNodeConfig(ctx, &cfg.Node)
+	utils.SetQLightConfig(ctx, &cfg.Eth, &cfg.Node)    // Quorum
+	utils.SetStateSyncConfig(ctx, &cfg.Eth, &cfg.Node) // Quorum
 	stack, err := node.New(&cfg.Node)
 	if err != nil {
 		utils.Fatalf("Failed to create the protocol stack: %v", err)
@@ -155,9 +177,82 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 	}
 	utils.SetShhConfig(ctx, stack)
 
+	// Quorum
+	utils.SetRaftConfig(ctx, &cfg.Quorum.Raft)
+	utils.SetPluginVerificationConfig(ctx, &cfg.Quorum.PluginVerification)
+	utils.SetPublisherConfig(ctx, &cfg.Quorum.Publisher)
+	utils.SetRelayConfig(ctx, &cfg.Quorum.Relay)
+	if err := quorumValidateConfig(&cfg); err != nil {
+		utils.Fatalf("Quorum configuration has an error: %v", err)
+	}
+
 	return stack, cfg
 }
 
+// quorumValidateConfig checks the Quorum options, once the configuration file and the
+// flags are applied
+func quorumValidateConfig(cfg *gethConfig) error {
+	if cfg.Eth.RaftMode {
+		if err := cfg.Quorum.Raft.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := cfg.Quorum.PluginVerification.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Quorum.Publisher.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Quorum.Relay.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Eth.Miner.OrgQuotas.Validate(); err != nil {
+		return fmt.Errorf("invalid org quotas: %v", err)
+	}
+	if cfg.Eth.SyncMode == downloader.LightSync {
+		switch {
+		case cfg.Eth.RaftMode:
+			return errors.New("a light client can't be a raft member, it syncs the headers from a raft node serving LES instead")
+		case cfg.Eth.QuorumLightClient != nil:
+			return errors.New("a qlight client executes the blocks, it requires a full sync")
+		case cfg.Quorum.Publisher.Enabled():
+			return errors.New("a light client has no receipts to publish, the publisher requires a full sync")
+		case cfg.Quorum.Relay.Enabled():
+			return errors.New("a light client has no private transactions to relay, the relay requires a full sync")
+		}
+	}
+	if cfg.Eth.QuorumLightClient != nil {
+		switch {
+		case cfg.Eth.QuorumLightServer != nil:
+			return errors.New("a node can't be both a qlight client and a qlight server")
+		case cfg.Eth.RaftMode:
+			return errors.New("raft does not propagate the blocks to a qlight client, which requires an eth protocol based consensus")
+		case cfg.Quorum.PrivateTransactionManager != nil:
+			return errors.New("a qlight client fetches the private payloads from its server, it must not connect to a private transaction manager")
+		}
+	}
+	if cfg.Eth.QuorumLightServer != nil && cfg.Quorum.PrivateTransactionManager == nil {
+		return errors.New("a qlight server requires a private transaction manager")
+	}
+	if cfg.Eth.QuorumStateSyncServerNode != "" {
+		switch {
+		case cfg.Eth.SyncMode != downloader.FastSync:
+			return errors.New("the private states are synced from a state sync server at the pivot block of a fast sync, it requires --syncmode fast")
+		case cfg.Eth.QuorumStateSyncServer != nil:
+			return errors.New("a node can't be both a state sync client and a state sync server")
+		case cfg.Eth.QuorumLightClient != nil:
+			return errors.New("a qlight client has no private states to sync")
+		}
+	}
+	if cfg.Quorum.Relay.Enabled() && cfg.Quorum.PrivateTransactionManager == nil {
+		return errors.New("the relay reads the private transactions from the private transaction manager, which is required")
+	}
+	if cfg.Eth.TrieCleanCacheJournal != "" && cfg.Eth.TrieCleanCacheJournal == cfg.Eth.PrivateTrieCleanCacheJournal {
+		return fmt.Errorf("the trie cache journals of the public and private states must be different, both are %q", cfg.Eth.TrieCleanCacheJournal)
+	}
+	return nil
+}
+
 // enableWhisper returns true in case one of the whisper flags is set.
 func checkWhisper(ctx *cli.Context) {
 	for _, flag := range whisperFlags {
@@ -178,20 +273,32 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	// plugin service must be after eth service so that eth service will be stopped gradually if any of the plugin
 	// fails to start
 	if cfg.Node.Plugins != nil {
-		utils.RegisterPluginService(stack, &cfg.Node, ctx.Bool(utils.PluginSkipVerifyFlag.Name), ctx.Bool(utils.PluginLocalVerifyFlag.Name), ctx.String(utils.PluginPublicKeyFlag.Name))
+		verification := cfg.Quorum.PluginVerification
+		utils.RegisterPluginService(stack, &cfg.Node, verification.SkipVerify, verification.LocalVerify, verification.PublicKey)
+		if stack.PluginManager().IsEnabled(plugin.BlockValidationPluginInterfaceName) {
+			utils.RegisterBlockValidationPlugin(stack, ethService)
+		}
 	}
 
 	if cfg.Node.IsPermissionEnabled() {
-		utils.RegisterPermissionService(stack, ctx.Bool(utils.RaftDNSEnabledFlag.Name))
+		utils.RegisterPermissionService(stack, cfg.Quorum.Raft.DNS)
 	}
 
-	if ctx.GlobalBool(utils.RaftModeFlag.Name) {
-		utils.RegisterRaftService(stack, ctx, &cfg.Node, ethService)
+	if cfg.Eth.RaftMode {
+		utils.RegisterRaftService(stack, &cfg.Quorum.Raft, &cfg.Node, ethService)
 	}
 
 	if private.IsQuorumPrivacyEnabled() {
 		utils.RegisterExtensionService(stack, ethService)
 	}
+
+	if cfg.Quorum.Publisher.Enabled() {
+		utils.RegisterPublisherService(stack, ethService, &cfg.Quorum.Publisher)
+	}
+
+	if cfg.Quorum.Relay.Enabled() {
+		utils.RegisterRelayService(stack, ethService, &cfg.Quorum.Relay)
+	}
 	// End Quorum
 
 	checkWhisper(ctx)
@@ -235,16 +342,31 @@ func dumpConfig(ctx *cli.Context) error {
 	return nil
 }
 
+// quorumIsRaftMode reports whether the ethereum service runs raft consensus, as set by the
+// flags or the configuration file
+func quorumIsRaftMode(stack *node.Node) bool {
+	var ethereum *eth.Ethereum
+	if err := stack.Lifecycle(&ethereum); err != nil {
+		return false
+	}
+	return ethereum.RaftMode()
+}
+
 // quorumValidateEthService checks quorum features that depend on the ethereum service
-func quorumValidateEthService(stack *node.Node, isRaft bool) {
+func quorumValidateEthService(stack *node.Node) {
 	var ethereum *eth.Ethereum
 
 	err := stack.Lifecycle(&ethereum)
 	if err != nil {
+		// a light client neither runs the consensus nor executes the private transactions
+		var lightEthereum *les.LightEthereum
+		if stack.Lifecycle(&lightEthereum) == nil {
+			return
+		}
 		utils.Fatalf("Error retrieving Ethereum service: %v", err)
 	}
 
-	quorumValidateConsensus(ethereum, isRaft)
+	quorumValidateConsensus(ethereum, ethereum.RaftMode())
 
 	quorumValidatePrivacyEnhancements(ethereum)
 }
@@ -269,28 +391,47 @@ func quorumValidatePrivacyEnhancements(ethereum *eth.Ethereum) {
 }
 
 // configure and set up quorum transaction privacy
-func quorumInitialisePrivacy(ctx *cli.Context) error {
-	cfg, err := QuorumSetupPrivacyConfiguration(ctx)
+func quorumInitialisePrivacy(ctx *cli.Context, quorumCfg *quorumConfig) error {
+	cfg, err := QuorumSetupPrivacyConfiguration(ctx, quorumCfg.PrivateTransactionManager)
 	if err != nil {
 		return err
 	}
+	// the connection in use is dumped by dumpconfig
+	if cfg.ConnectionType != "" && cfg.ConnectionType != http.NoConnection {
+		quorumCfg.PrivateTransactionManager = &cfg
+	}
 
 	err = private.InitialiseConnection(cfg)
 	if err != nil {
 		return err
 	}
+	if path := ctx.GlobalString(utils.QuorumPTMAliasesFlag.Name); path != "" {
+		aliases, err := private.LoadKeyAliases(path)
+		if err != nil {
+			return err
+		}
+		if err := private.SetKeyAliases(aliases); err != nil {
+			return err
+		}
+		log.Info("Loaded private transaction manager key aliases", "path", path, "count", len(aliases))
+	}
 	privacyExtension.Init()
 
 	return nil
 }
 
-// Get private transaction manager configuration
-func QuorumSetupPrivacyConfiguration(ctx *cli.Context) (http.Config, error) {
+// Get private transaction manager configuration, from the PRIVATE_CONFIG environment
+// variable or else the TOML configuration file, overridden by the command line flags
+func QuorumSetupPrivacyConfiguration(ctx *cli.Context, fileCfg *http.Config) (http.Config, error) {
 	// get default configuration
 	cfg, err := private.GetLegacyEnvironmentConfig()
 	if err != nil {
 		return http.Config{}, err
 	}
+	if os.Getenv("PRIVATE_CONFIG") == "" && fileCfg != nil {
+		cfg = *fileCfg
+		cfg.SetConnectionType()
+	}
 
 	// override the config with command line parameters
 	if ctx.GlobalIsSet(utils.QuorumPTMUnixSocketFlag.Name) {
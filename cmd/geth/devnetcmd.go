@@ -0,0 +1,271 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/raft"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var (
+	devnetNodesFlag = cli.IntFlag{
+		Name:  "nodes",
+		Usage: "Number of nodes of the development network",
+		Value: 4,
+	}
+	devnetConsensusFlag = cli.StringFlag{
+		Name:  "consensus",
+		Usage: "Consensus of the development network: istanbul or raft",
+		Value: "istanbul",
+	}
+	devnetDirFlag = utils.DirectoryFlag{
+		Name:  "devnet.dir",
+		Usage: "Directory of the node data directories, kept between runs",
+		Value: utils.DirectoryString(filepath.Join(os.TempDir(), "quorum-dev")),
+	}
+	devnetP2PPortFlag = cli.IntFlag{
+		Name:  "devnet.p2pport",
+		Usage: "P2P port of the first node, incremented for the next ones",
+		Value: 21000,
+	}
+	devnetHTTPPortFlag = cli.IntFlag{
+		Name:  "devnet.httpport",
+		Usage: "HTTP-RPC port of the first node, incremented for the next ones",
+		Value: 22000,
+	}
+	devnetRaftPortFlag = cli.IntFlag{
+		Name:  "devnet.raftport",
+		Usage: "Raft port of the first node, incremented for the next ones",
+		Value: 50401,
+	}
+)
+
+var quorumDevCommand = cli.Command{
+	Action:   utils.MigrateFlags(quorumDev),
+	Name:     "quorum-dev",
+	Usage:    "Run a local development network of in-process raft or Istanbul nodes",
+	Category: "MISCELLANEOUS COMMANDS",
+	Flags: []cli.Flag{
+		devnetNodesFlag,
+		devnetConsensusFlag,
+		devnetDirFlag,
+		devnetP2PPortFlag,
+		devnetHTTPPortFlag,
+		devnetRaftPortFlag,
+	},
+	Description: `
+The quorum-dev command runs in a single process a development network of raft or
+Istanbul nodes, each one with a prefunded and unlocked account and its HTTP-RPC
+endpoint on localhost.
+
+The private transactions are handled by an in-memory private transaction manager
+shared by the nodes: the payloads are neither encrypted nor restricted to the
+parties, every node executes every private transaction. It is meant for the local
+development and the integration tests of dApps only.`,
+}
+
+const (
+	devnetChainID     = 1337
+	devnetRaftBlockMs = 50
+)
+
+// devnetBalance is the balance of the prefunded accounts
+var devnetBalance = new(big.Int).Mul(big.NewInt(1000000000), big.NewInt(1e18))
+
+// devnetNode is a node of the development network
+type devnetNode struct {
+	dir        string
+	nodeKey    *ecdsa.PrivateKey
+	accountKey *ecdsa.PrivateKey
+	p2pPort    int
+	httpPort   int
+	raftPort   int
+}
+
+// newDevnetNodes loads the keys of the nodes from dir, generating them on the first run
+func newDevnetNodes(dir string, count, p2pPort, httpPort, raftPort int) ([]*devnetNode, error) {
+	nodes := make([]*devnetNode, count)
+	for i := range nodes {
+		n := &devnetNode{
+			dir:      filepath.Join(dir, fmt.Sprintf("node%d", i+1)),
+			p2pPort:  p2pPort + i,
+			httpPort: httpPort + i,
+			raftPort: raftPort + i,
+		}
+		config := node.Config{DataDir: n.dir, Name: clientIdentifier}
+		n.nodeKey = config.NodeKey()
+		keyfile := filepath.Join(n.dir, "devaccount.key")
+		if key, err := crypto.LoadECDSA(keyfile); err == nil {
+			n.accountKey = key
+		} else {
+			if n.accountKey, err = crypto.GenerateKey(); err != nil {
+				return nil, err
+			}
+			if err := crypto.SaveECDSA(keyfile, n.accountKey); err != nil {
+				return nil, err
+			}
+		}
+		nodes[i] = n
+	}
+	return nodes, nil
+}
+
+func (n *devnetNode) enode() string {
+	return fmt.Sprintf("enode://%x@127.0.0.1:%d?discport=0", crypto.FromECDSAPub(&n.nodeKey.PublicKey)[1:], n.p2pPort)
+}
+
+func (n *devnetNode) address() common.Address {
+	return crypto.PubkeyToAddress(n.accountKey.PublicKey)
+}
+
+// devnetSpec returns the genesis-gen spec of the development network
+func devnetSpec(consensus string, nodes []*devnetNode) *networkSpec {
+	spec := &networkSpec{
+		Consensus: consensus,
+		ChainID:   devnetChainID,
+		Alloc:     core.GenesisAlloc{},
+	}
+	if consensus == "istanbul" {
+		spec.Istanbul = &params.IstanbulConfig{Epoch: defaultIstanbulEpoch, Ceil2Nby3Block: big.NewInt(0)}
+	}
+	for _, n := range nodes {
+		specNode := networkSpecNode{Enode: n.enode()}
+		if consensus == "raft" {
+			specNode.RaftPort = uint16(n.raftPort)
+		}
+		spec.Nodes = append(spec.Nodes, specNode)
+		spec.Alloc[n.address()] = core.GenesisAccount{Balance: devnetBalance}
+	}
+	return spec
+}
+
+// newStack creates the protocol stack of the id-th node, starting at 1
+func (n *devnetNode) newStack(id int, consensus string, genesis *core.Genesis, staticNodes []*enode.Node) (*node.Node, *eth.Ethereum, error) {
+	cfg := defaultNodeConfig()
+	cfg.DataDir = n.dir
+	cfg.RaftLogDir = n.dir
+	cfg.P2P.PrivateKey = n.nodeKey
+	cfg.P2P.ListenAddr = fmt.Sprintf("127.0.0.1:%d", n.p2pPort)
+	cfg.P2P.NoDiscovery = true
+	cfg.P2P.StaticNodes = staticNodes
+	cfg.HTTPHost = "127.0.0.1"
+	cfg.HTTPPort = n.httpPort
+	cfg.HTTPModules = append(cfg.HTTPModules, "personal", "admin", "debug", "txpool", consensus)
+	stack, err := node.New(&cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ethCfg := eth.DefaultConfig
+	ethCfg.Genesis = genesis
+	ethCfg.NetworkId = devnetChainID
+	ethCfg.Miner.Etherbase = n.address()
+	ethCfg.RaftMode = consensus == "raft"
+	ethCfg.Istanbul.BlockPeriod = 1
+	_, ethService := utils.RegisterEthService(stack, &ethCfg)
+	if consensus == "raft" {
+		blockTime := devnetRaftBlockMs * time.Millisecond
+		if _, err := raft.New(stack, genesis.Config, uint16(id), uint16(n.raftPort), false, blockTime, ethService, staticNodes, n.dir, false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// import and unlock the prefunded account
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	account := accounts.Account{Address: n.address()}
+	if !ks.HasAddress(account.Address) {
+		if account, err = ks.ImportECDSA(n.accountKey, ""); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		return nil, nil, err
+	}
+	return stack, ethService, nil
+}
+
+// quorumDev is the quorum-dev command.
+func quorumDev(ctx *cli.Context) error {
+	count := ctx.GlobalInt(devnetNodesFlag.Name)
+	if count < 1 {
+		utils.Fatalf("At least one node is required")
+	}
+	consensus := ctx.GlobalString(devnetConsensusFlag.Name)
+	nodes, err := newDevnetNodes(ctx.GlobalString(devnetDirFlag.Name), count,
+		ctx.GlobalInt(devnetP2PPortFlag.Name), ctx.GlobalInt(devnetHTTPPortFlag.Name), ctx.GlobalInt(devnetRaftPortFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to load the node keys: %v", err)
+	}
+	files, err := devnetSpec(consensus, nodes).generate()
+	if err != nil {
+		utils.Fatalf("Invalid development network: %v", err)
+	}
+	staticNodes := make([]*enode.Node, len(files.staticNodes))
+	for i, url := range files.staticNodes {
+		if staticNodes[i], err = enode.ParseV4(url); err != nil {
+			utils.Fatalf("Invalid static node: %v", err)
+		}
+	}
+	private.InitialiseInMemory()
+
+	stacks := make([]*node.Node, len(nodes))
+	for i, n := range nodes {
+		stack, ethService, err := n.newStack(i+1, consensus, files.genesis, staticNodes)
+		if err != nil {
+			utils.Fatalf("Failed to create node %d: %v", i+1, err)
+		}
+		utils.StartNode(stack)
+		if consensus == "istanbul" {
+			if err := ethService.StartMining(1); err != nil {
+				utils.Fatalf("Failed to start mining on node %d: %v", i+1, err)
+			}
+		}
+		stacks[i] = stack
+	}
+
+	fmt.Printf("\nDevelopment network of %d %s nodes, chain id %d\n", len(nodes), consensus, devnetChainID)
+	for i, n := range nodes {
+		fmt.Printf("  node%d: http://127.0.0.1:%d account %s private key %x\n",
+			i+1, n.httpPort, n.address().Hex(), crypto.FromECDSA(n.accountKey))
+	}
+	fmt.Println()
+
+	for _, stack := range stacks {
+		stack.Wait()
+	}
+	return nil
+}
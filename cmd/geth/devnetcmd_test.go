@@ -0,0 +1,69 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDevnetNodes_keepsKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quorum-dev")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	nodes, err := newDevnetNodes(dir, 3, 21000, 22000, 50401)
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, 21002, nodes[2].p2pPort)
+	assert.Equal(t, 22002, nodes[2].httpPort)
+	assert.Equal(t, 50403, nodes[2].raftPort)
+
+	reloaded, err := newDevnetNodes(dir, 3, 21000, 22000, 50401)
+	require.NoError(t, err)
+	for i := range nodes {
+		assert.Equal(t, crypto.FromECDSA(nodes[i].nodeKey), crypto.FromECDSA(reloaded[i].nodeKey))
+		assert.Equal(t, nodes[i].address(), reloaded[i].address())
+	}
+	assert.NotEqual(t, nodes[0].address(), nodes[1].address())
+}
+
+func TestDevnetSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quorum-dev")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	nodes, err := newDevnetNodes(dir, 2, 21000, 22000, 50401)
+	require.NoError(t, err)
+
+	files, err := devnetSpec("istanbul", nodes).generate()
+	require.NoError(t, err)
+	assert.Len(t, files.genesis.Alloc, 2)
+	assert.Equal(t, devnetBalance, files.genesis.Alloc[nodes[0].address()].Balance)
+	extra, err := types.ExtractIstanbulExtra(&types.Header{Extra: files.genesis.ExtraData})
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(nodes[1].nodeKey.PublicKey), extra.Validators[1])
+
+	files, err = devnetSpec("raft", nodes).generate()
+	require.NoError(t, err)
+	assert.Contains(t, files.staticNodes[1], "raftport=50402")
+}
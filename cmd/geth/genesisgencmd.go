@@ -0,0 +1,290 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var genesisGenCommand = cli.Command{
+	Action:    utils.MigrateFlags(genesisGen),
+	Name:      "genesis-gen",
+	Usage:     "Generate the genesis and the bootstrap files of a raft or Istanbul network",
+	ArgsUsage: "<specPath> <outputDir>",
+	Category:  "BLOCKCHAIN COMMANDS",
+	Description: `
+The genesis-gen command generates from a JSON spec file the files needed to bootstrap
+a raft or Istanbul network into <outputDir>:
+  - genesis.json, with the quorum chain configuration and, for Istanbul, the extra-data
+    of the validators
+  - static-nodes.json, with the raft ports for raft
+  - permissioned-nodes.json if "permissioned" is set
+  - permission-config.json if "permission" is set, enabling smart-contract-based
+    permissioning from the genesis block
+
+Spec file example:
+{
+  "consensus": "istanbul",
+  "chainId": 10,
+  "istanbul": {"epoch": 30000, "policy": 0},
+  "nodes": [
+    {"enode": "enode://<pubkey>@10.0.0.1:21000?discport=0"},
+    {"enode": "enode://<pubkey>@10.0.0.2:21000?discport=0", "validator": false}
+  ],
+  "alloc": {"0xed9d02e382b34818e88b88a309c7fe71e65f419d": {"balance": "1000000000000000000000000000"}}
+}`,
+}
+
+const (
+	defaultGenesisGasLimit      = 0xE0000000
+	defaultTransactionSizeLimit = 64
+	defaultIstanbulEpoch        = 30000
+)
+
+// networkSpec is the spec file of the genesis-gen command
+type networkSpec struct {
+	Consensus           string                  `json:"consensus"` // istanbul or raft
+	ChainID             uint64                  `json:"chainId"`
+	GasLimit            math.HexOrDecimal64     `json:"gasLimit"`
+	Istanbul            *params.IstanbulConfig  `json:"istanbul"`
+	MaxCodeSize         uint64                  `json:"maxCodeSize"`
+	TxnSizeLimit        uint64                  `json:"txnSizeLimit"`
+	PrivacyEnhancements bool                    `json:"privacyEnhancements"`
+	IsMPS               bool                    `json:"isMPS"`
+	Permissioned        bool                    `json:"permissioned"`
+	Permission          *ptype.PermissionConfig `json:"permission"`
+	Nodes               []networkSpecNode       `json:"nodes"`
+	Alloc               core.GenesisAlloc       `json:"alloc"`
+}
+
+type networkSpecNode struct {
+	Enode     string `json:"enode"`
+	RaftPort  uint16 `json:"raftPort"`  // raft only, the port the node listens to for raft communication
+	Validator *bool  `json:"validator"` // Istanbul only, defaults to true
+}
+
+// networkFiles are the bootstrap files of a network
+type networkFiles struct {
+	genesis          *core.Genesis
+	staticNodes      []string
+	permissionConfig *ptype.PermissionConfig
+}
+
+// genesisGen is the genesis-gen command.
+func genesisGen(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		utils.Fatalf("This command requires the spec file and the output directory as arguments.")
+	}
+	content, err := ioutil.ReadFile(ctx.Args().Get(0))
+	if err != nil {
+		utils.Fatalf("Failed to read the spec file: %v", err)
+	}
+	var spec networkSpec
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		utils.Fatalf("Invalid spec file: %v", err)
+	}
+	files, err := spec.generate()
+	if err != nil {
+		utils.Fatalf("Invalid spec file: %v", err)
+	}
+	outputDir := ctx.Args().Get(1)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		utils.Fatalf("Failed to create the output directory: %v", err)
+	}
+	write := func(name string, v interface{}) {
+		content, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			utils.Fatalf("Failed to encode %s: %v", name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(outputDir, name), append(content, '\n'), 0644); err != nil {
+			utils.Fatalf("Failed to write %s: %v", name, err)
+		}
+		fmt.Printf("Wrote %s\n", filepath.Join(outputDir, name))
+	}
+	write("genesis.json", files.genesis)
+	write("static-nodes.json", files.staticNodes)
+	if spec.Permissioned {
+		write(params.PERMISSIONED_CONFIG, files.staticNodes)
+	}
+	if files.permissionConfig != nil {
+		write(params.PERMISSION_MODEL_CONFIG, files.permissionConfig)
+	}
+	return nil
+}
+
+// generate validates the spec and generates the bootstrap files of the network
+func (spec *networkSpec) generate() (*networkFiles, error) {
+	if spec.ChainID == 0 {
+		return nil, errors.New("chainId is required")
+	}
+	if spec.ChainID == 1 {
+		return nil, errors.New("chainId 1 is reserved for the Ethereum mainnet")
+	}
+	if len(spec.Nodes) == 0 {
+		return nil, errors.New("at least one node is required")
+	}
+	gasLimit := uint64(spec.GasLimit)
+	if gasLimit == 0 {
+		gasLimit = defaultGenesisGasLimit
+	}
+	txnSizeLimit := spec.TxnSizeLimit
+	if txnSizeLimit == 0 {
+		txnSizeLimit = defaultTransactionSizeLimit
+	}
+	config := &params.ChainConfig{
+		ChainID:              new(big.Int).SetUint64(spec.ChainID),
+		HomesteadBlock:       big.NewInt(0),
+		EIP150Block:          big.NewInt(0),
+		EIP155Block:          big.NewInt(0),
+		EIP158Block:          big.NewInt(0),
+		ByzantiumBlock:       big.NewInt(0),
+		ConstantinopleBlock:  big.NewInt(0),
+		PetersburgBlock:      big.NewInt(0),
+		IstanbulBlock:        big.NewInt(0),
+		IsQuorum:             true,
+		TransactionSizeLimit: txnSizeLimit,
+		MaxCodeSize:          spec.MaxCodeSize,
+		IsMPS:                spec.IsMPS,
+	}
+	if spec.PrivacyEnhancements {
+		config.PrivacyEnhancementsBlock = big.NewInt(0)
+	}
+	var permissionConfig *ptype.PermissionConfig
+	if spec.Permission != nil {
+		permissionConfig = spec.Permission
+		permissionConfig.PermissionsModel = strings.ToLower(permissionConfig.PermissionsModel)
+		if permissionConfig.PermissionsModel != ptype.PERMISSION_V1 && permissionConfig.PermissionsModel != ptype.PERMISSION_V2 {
+			return nil, fmt.Errorf("invalid permission model %q, expected %s or %s", permissionConfig.PermissionsModel, ptype.PERMISSION_V1, ptype.PERMISSION_V2)
+		}
+		config.QIP714Block = big.NewInt(0)
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		return nil, err
+	}
+	genesis := &core.Genesis{
+		Config:   config,
+		GasLimit: gasLimit,
+		Alloc:    spec.Alloc,
+	}
+	if genesis.Alloc == nil {
+		genesis.Alloc = core.GenesisAlloc{}
+	}
+
+	var (
+		staticNodes []string
+		validators  []common.Address
+	)
+	for i, specNode := range spec.Nodes {
+		node, err := enode.ParseV4(specNode.Enode)
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %v", i, err)
+		}
+		url := specNode.Enode
+		switch spec.Consensus {
+		case "raft":
+			if specNode.Validator != nil {
+				return nil, fmt.Errorf("node %d: validator is only supported by istanbul", i)
+			}
+			if !strings.Contains(url, "raftport=") {
+				if specNode.RaftPort == 0 {
+					return nil, fmt.Errorf("node %d: raftPort is required", i)
+				}
+				url = withQueryParam(url, "raftport", fmt.Sprint(specNode.RaftPort))
+			}
+		case "istanbul":
+			if specNode.RaftPort != 0 {
+				return nil, fmt.Errorf("node %d: raftPort is only supported by raft", i)
+			}
+			if specNode.Validator == nil || *specNode.Validator {
+				validators = append(validators, crypto.PubkeyToAddress(*node.Pubkey()))
+			}
+		}
+		staticNodes = append(staticNodes, url)
+	}
+
+	switch spec.Consensus {
+	case "raft":
+		if spec.Istanbul != nil {
+			return nil, errors.New("istanbul config is not supported by raft")
+		}
+		genesis.Difficulty = big.NewInt(0)
+	case "istanbul":
+		config.Istanbul = spec.Istanbul
+		if config.Istanbul == nil {
+			config.Istanbul = &params.IstanbulConfig{Epoch: defaultIstanbulEpoch}
+		}
+		if len(validators) == 0 {
+			return nil, errors.New("at least one validator is required")
+		}
+		extra, err := istanbulGenesisExtra(validators)
+		if err != nil {
+			return nil, err
+		}
+		genesis.ExtraData = extra
+		genesis.Mixhash = types.IstanbulDigest
+		genesis.Difficulty = big.NewInt(1)
+	default:
+		return nil, fmt.Errorf("unsupported consensus %q, expected istanbul or raft", spec.Consensus)
+	}
+	return &networkFiles{genesis: genesis, staticNodes: staticNodes, permissionConfig: permissionConfig}, nil
+}
+
+// istanbulGenesisExtra returns the extra-data of the Istanbul genesis block: the vanity
+// followed by the RLP encoding of the validators with empty seals
+func istanbulGenesisExtra(validators []common.Address) ([]byte, error) {
+	extra, err := rlp.EncodeToBytes(&types.IstanbulExtra{
+		Validators:    validators,
+		Seal:          make([]byte, types.IstanbulExtraSeal),
+		CommittedSeal: [][]byte{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]byte, types.IstanbulExtraVanity), extra...), nil
+}
+
+func withQueryParam(url, key, value string) string {
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+	return url + separator + key + "=" + value
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEnode(t *testing.T, port int) (string, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return fmt.Sprintf("enode://%x@127.0.0.1:%d?discport=0", crypto.FromECDSAPub(&key.PublicKey)[1:], port), key
+}
+
+func TestNetworkSpec_generateIstanbul(t *testing.T) {
+	validator, validatorKey := newTestEnode(t, 21000)
+	nonValidator, _ := newTestEnode(t, 21001)
+	notValidator := false
+	spec := &networkSpec{
+		Consensus: "istanbul",
+		ChainID:   10,
+		Nodes:     []networkSpecNode{{Enode: validator}, {Enode: nonValidator, Validator: &notValidator}},
+	}
+
+	files, err := spec.generate()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{validator, nonValidator}, files.staticNodes)
+	genesis := files.genesis
+	assert.True(t, genesis.Config.IsQuorum)
+	assert.Equal(t, big.NewInt(10), genesis.Config.ChainID)
+	assert.Equal(t, uint64(defaultIstanbulEpoch), genesis.Config.Istanbul.Epoch)
+	assert.Equal(t, types.IstanbulDigest, genesis.Mixhash)
+	extra, err := types.ExtractIstanbulExtra(&types.Header{Extra: genesis.ExtraData})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{crypto.PubkeyToAddress(validatorKey.PublicKey)}, extra.Validators)
+	assert.Nil(t, files.permissionConfig)
+}
+
+func TestNetworkSpec_generateRaft(t *testing.T) {
+	node, _ := newTestEnode(t, 21000)
+	spec := &networkSpec{
+		Consensus:           "raft",
+		ChainID:             10,
+		PrivacyEnhancements: true,
+		Nodes:               []networkSpecNode{{Enode: node, RaftPort: 50400}},
+	}
+
+	files, err := spec.generate()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{node + "&raftport=50400"}, files.staticNodes)
+	_, err = enode.ParseV4(files.staticNodes[0])
+	assert.NoError(t, err)
+	assert.Nil(t, files.genesis.Config.Istanbul)
+	assert.Equal(t, big.NewInt(0), files.genesis.Config.PrivacyEnhancementsBlock)
+	assert.Empty(t, files.genesis.ExtraData)
+}
+
+func TestNetworkSpec_generateWhenInvalid(t *testing.T) {
+	node, _ := newTestEnode(t, 21000)
+	testCases := []struct {
+		name     string
+		spec     networkSpec
+		expected string
+	}{
+		{"no chain id", networkSpec{Consensus: "raft", Nodes: []networkSpecNode{{Enode: node, RaftPort: 1}}}, "chainId is required"},
+		{"no nodes", networkSpec{Consensus: "raft", ChainID: 10}, "at least one node is required"},
+		{"unsupported consensus", networkSpec{Consensus: "clique", ChainID: 10, Nodes: []networkSpecNode{{Enode: node}}}, `unsupported consensus "clique", expected istanbul or raft`},
+		{"missing raft port", networkSpec{Consensus: "raft", ChainID: 10, Nodes: []networkSpecNode{{Enode: node}}}, "node 0: raftPort is required"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.spec.generate()
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -69,8 +69,11 @@ var (
 		utils.DataDirFlag,
 		utils.RaftLogDirFlag,
 		utils.AncientFlag,
+		utils.DiskUsageThresholdsFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
+		utils.KMSKeysFlag,
+		utils.KMSUnlockedFlag,
 		utils.NoUSBFlag,
 		utils.SmartCardDaemonPathFlag,
 		utils.EthashCacheDirFlag,
@@ -135,6 +138,8 @@ var (
 		utils.LegacyMinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerfiyFlag,
+		utils.MinerOrgQuotaGasShareFlag,
+		utils.MinerOrgQuotaMaxTxsFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -164,16 +169,26 @@ var (
 		configFileFlag,
 		// Quorum
 		utils.PrivateCacheTrieJournalFlag,
+		utils.PrivateStateColdAfterFlag,
+		utils.PrivatePayloadIndexFlag,
 		utils.QuorumImmutabilityThreshold,
 		utils.EnableNodePermissionFlag,
+		utils.PermissionApiKeysFlag,
+		utils.NodeIdentityCertFlag,
+		utils.NodeIdentityCAFlag,
+		utils.NodeIdentityCRLFlag,
 		utils.RaftModeFlag,
 		utils.RaftBlockTimeFlag,
 		utils.RaftJoinExistingFlag,
 		utils.RaftPortFlag,
 		utils.RaftDNSEnabledFlag,
+		utils.RaftMaxSyncPeersFlag,
+		utils.RaftSyncBandwidthFlag,
+		utils.RaftFollowerSyncFlag,
 		utils.EmitCheckpointsFlag,
 		utils.IstanbulRequestTimeoutFlag,
 		utils.IstanbulBlockPeriodFlag,
+		utils.IstanbulKMSKeyFlag,
 		utils.PluginSettingsFlag,
 		utils.PluginSkipVerifyFlag,
 		utils.PluginLocalVerifyFlag,
@@ -182,6 +197,24 @@ var (
 		utils.EVMCallTimeOutFlag,
 		utils.MultitenancyFlag,
 		utils.RevertReasonFlag,
+		utils.HealthMinPeersFlag,
+		utils.HealthMaxBlockAgeFlag,
+		utils.AdvisoryFeedFlag,
+		utils.AdvisoryIntervalFlag,
+		utils.QLightServerFlag,
+		utils.QLightServerEntitlementsFlag,
+		utils.QLightServerRateLimitFlag,
+		utils.QLightServerRateLimitBurstFlag,
+		utils.QLightClientFlag,
+		utils.QLightClientPSIFlag,
+		utils.QLightClientTokenFlag,
+		utils.QLightClientServerNodeFlag,
+		utils.StateSyncServerFlag,
+		utils.StateSyncServerEntitlementsFlag,
+		utils.StateSyncServerNodeFlag,
+		utils.PublisherURLFlag,
+		utils.PublisherTopicFlag,
+		utils.RelayConfigFlag,
 		utils.QuorumPTMUnixSocketFlag,
 		utils.QuorumPTMUrlFlag,
 		utils.QuorumPTMTimeoutFlag,
@@ -194,6 +227,7 @@ var (
 		utils.QuorumPTMTlsClientCertFlag,
 		utils.QuorumPTMTlsClientKeyFlag,
 		utils.QuorumPTMTlsInsecureSkipVerify,
+		utils.QuorumPTMAliasesFlag,
 		// End-Quorum
 	}
 
@@ -221,12 +255,33 @@ var (
 		utils.WSApiFlag,
 		utils.LegacyWSApiFlag,
 		utils.WSAllowedOriginsFlag,
+		utils.WSPingIntervalFlag,
+		utils.WSPongTimeoutFlag,
+		utils.WSMaxMissedPongsFlag,
 		utils.LegacyWSAllowedOriginsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
+		utils.IPCSecurityDescriptorFlag,
+		utils.IPCAllowedSIDsFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCBatchRequestLimitFlag,
+		utils.RPCBatchResponseMaxSizeFlag,
+		utils.RPCMethodTimeoutsFlag,
+		utils.RPCRateLimitGlobalFlag,
+		utils.RPCRateLimitGlobalBurstFlag,
+		utils.RPCRateLimitConnFlag,
+		utils.RPCRateLimitConnBurstFlag,
+		utils.RPCRateLimitWaitFlag,
+		utils.RPCTrustedProxiesFlag,
+		utils.RPCProxyProtocolFlag,
+		utils.HTTP2Flag,
+		utils.HTTPGzipMinSizeFlag,
+		utils.GRPCEnabledFlag,
+		utils.GRPCListenAddrFlag,
+		utils.GRPCPortFlag,
+		utils.GRPCApiFlag,
 	}
 
 	whisperFlags = []cli.Flag{
@@ -247,6 +302,9 @@ var (
 		utils.MetricsInfluxDBUsernameFlag,
 		utils.MetricsInfluxDBPasswordFlag,
 		utils.MetricsInfluxDBTagsFlag,
+		// Quorum
+		utils.TracingOTLPEndpointFlag,
+		utils.TracingServiceNameFlag,
 	}
 )
 
@@ -266,7 +324,21 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
+		// Quorum: see genesisgencmd.go
+		genesisGenCommand,
+		quorumDevCommand,
 		inspectCommand,
+		// Quorum: see backupcmd.go
+		backupCommand,
+		restoreCommand,
+		// Quorum: see reportcmd.go
+		exportReportCommand,
+		// Quorum: see verifycmd.go
+		verifyDBCommand,
+		// Quorum: see statedigestcmd.go
+		stateDigestCommand,
+		// Quorum: see migratecmd.go
+		migrateChaindataCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
@@ -281,6 +353,8 @@ func init() {
 		licenseCommand,
 		// See config.go
 		dumpConfigCommand,
+		// Quorum: see doctorcmd.go
+		quorumDoctorCommand,
 		// See retesteth.go
 		retestethCommand,
 		// See cmd/utils/flags_legacy.go
@@ -375,6 +449,9 @@ func prepare(ctx *cli.Context) {
 	// Start metrics export if enabled
 	utils.SetupMetrics(ctx)
 
+	// Quorum: start tracing export if enabled
+	utils.SetupTracing(ctx)
+
 	// Start system runtime metrics collection
 	go metrics.CollectProcessMetrics(3 * time.Second)
 }
@@ -406,10 +483,26 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
 	debug.Memsize.Add("node", stack)
 
 	// raft mode does not support --exitwhensynced
-	if ctx.GlobalBool(utils.ExitWhenSyncedFlag.Name) && ctx.GlobalBool(utils.RaftModeFlag.Name) {
+	if ctx.GlobalBool(utils.ExitWhenSyncedFlag.Name) && quorumIsRaftMode(stack) {
 		utils.Fatalf("raft consensus does not support --exitwhensynced")
 	}
 
+	// Quorum
+	//
+	// the hook must be in place before p2p server accepts any connection
+	if stack.PluginManager().IsEnabled(plugin.NodePermissionPluginInterfaceName) {
+		t := new(plugin.NodePermissionPluginTemplate)
+		if err := stack.PluginManager().GetPluginTemplate(plugin.NodePermissionPluginInterfaceName, t); err != nil {
+			utils.Fatalf("failed to load node permission plugin: %v", err)
+		}
+		p, err := t.Get()
+		if err != nil {
+			utils.Fatalf("failed to load node permission plugin: %v", err)
+		}
+		stack.Server().SetConnectionHook(permission.NewConnectionHook(p))
+	}
+	// End Quorum
+
 	// Start up the node itself
 	utils.StartNode(stack)
 
@@ -421,6 +514,15 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
 		}
 	}
 
+	// Quorum
+	stack.PluginManager().StartEventService(backend)
+	if stack.PluginManager().IsEnabled(plugin.ExporterPluginInterfaceName) {
+		if err := stack.PluginManager().StartChainExporter(backend); err != nil {
+			utils.Fatalf("failed to start exporter plugin: %v", err)
+		}
+	}
+	// End Quorum
+
 	// Unlock any account specifically requested
 	unlockAccounts(ctx, stack)
 
@@ -436,7 +538,7 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
 	ethClient := ethclient.NewClient(rpcClient)
 
 	// Quorum
-	if ctx.GlobalBool(utils.MultitenancyFlag.Name) && !stack.PluginManager().IsEnabled(plugin.SecurityPluginInterfaceName) {
+	if stack.Config().EnableMultitenancy && !stack.PluginManager().IsEnabled(plugin.SecurityPluginInterfaceName) {
 		utils.Fatalf("multitenancy requires RPC Security Plugin to be configured")
 	}
 	// End Quorum
@@ -543,7 +645,7 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
 	}
 
 	// checks quorum features that depend on the ethereum service
-	quorumValidateEthService(stack, ctx.GlobalBool(utils.RaftModeFlag.Name))
+	quorumValidateEthService(stack)
 }
 
 // unlockAccounts unlocks any account specifically requested.
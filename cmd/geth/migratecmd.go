@@ -0,0 +1,113 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var (
+	migrateDryRunFlag = cli.BoolFlag{
+		Name:  "migrate.dryrun",
+		Usage: "Report the legacy data without converting it",
+	}
+	migrateVerifyFlag = cli.BoolFlag{
+		Name:  "migrate.verify",
+		Usage: "Fail if legacy data is left, without converting it",
+	}
+	migrateMPSFlag = cli.BoolFlag{
+		Name:  "migrate.mps",
+		Usage: "Convert the private state to the multiple private states layout, as the default private state",
+	}
+
+	migrateChaindataCommand = cli.Command{
+		Action: utils.MigrateFlags(migrateChaindata),
+		Name:   "migrate-chaindata",
+		Usage:  "Convert a chain database written by an older Quorum release into the current format",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			configFileFlag,
+			migrateDryRunFlag,
+			migrateVerifyFlag,
+			migrateMPSFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The migrate-chaindata command converts offline the data of the canonical chain written
+by older Quorum releases:
+  - the receipts stored in the encoding of the database version 3 or 4. The receipts
+    in the freezer can't be rewritten, they are left in their encoding which stays
+    readable.
+  - with --migrate.mps, the private state of the node, converted to the multiple
+    private states layout as the default private state ("private"). The chain config
+    stored in the database is updated with isMPS, the genesis file of the node must
+    set it too before it is initialized again.
+
+With --migrate.dryrun the legacy data is reported only. With --migrate.verify the
+command fails if legacy data is left, e.g. to check a migrated database. Back up the
+data directory first (see the backup command), the node must be stopped.`,
+	}
+)
+
+// migrateChaindata is the migrate-chaindata command.
+func migrateChaindata(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	verify := ctx.Bool(migrateVerifyFlag.Name)
+	start := time.Now()
+	summary, err := core.MigrateChaindata(db, &core.MigrateConfig{
+		DryRun: verify || ctx.Bool(migrateDryRunFlag.Name),
+		MPS:    ctx.Bool(migrateMPSFlag.Name),
+	})
+	if err != nil {
+		utils.Fatalf("Migration error: %v", err)
+	}
+	if summary.Converted {
+		fmt.Printf("Migrated blocks #0-#%d in %v\n", summary.Head, time.Since(start))
+	} else {
+		fmt.Printf("Inspected blocks #0-#%d in %v\n", summary.Head, time.Since(start))
+	}
+	fmt.Printf("  frozen blocks:                      %d\n", summary.Ancients)
+	fmt.Printf("  blocks with legacy receipts:        %d\n", summary.Receipts)
+	if summary.FrozenReceipts > 0 {
+		fmt.Printf("  frozen blocks with legacy receipts: %d (left as is, readable)\n", summary.FrozenReceipts)
+	}
+	if ctx.Bool(migrateMPSFlag.Name) {
+		fmt.Printf("  blocks with legacy private state:   %d\n", summary.PrivateStates)
+		fmt.Printf("  mismatched private states:          %d\n", summary.Mismatches)
+		fmt.Printf("  chain config isMPS to set:          %v\n", summary.ChainConfig)
+	}
+	if verify && summary.Pending() {
+		utils.Fatalf("Legacy data left to migrate")
+	}
+	if summary.Mismatches > 0 {
+		utils.Fatalf("%d converted private states don't match their legacy private state", summary.Mismatches)
+	}
+	return nil
+}
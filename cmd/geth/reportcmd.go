@@ -0,0 +1,205 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var (
+	reportFromFlag = cli.Int64Flag{
+		Name:  "report.from",
+		Usage: "First block of the report (default = genesis)",
+		Value: -1,
+	}
+	reportToFlag = cli.Int64Flag{
+		Name:  "report.to",
+		Usage: "Last block of the report (default = head)",
+		Value: -1,
+	}
+	reportFromTimeFlag = cli.StringFlag{
+		Name:  "report.fromtime",
+		Usage: "Report the blocks from this time (RFC3339), instead of --report.from",
+	}
+	reportToTimeFlag = cli.StringFlag{
+		Name:  "report.totime",
+		Usage: "Report the blocks up to this time (RFC3339), instead of --report.to",
+	}
+	reportDatasetsFlag = cli.StringFlag{
+		Name:  "report.datasets",
+		Usage: "Comma separated datasets of the report: transactions, receipts, events (default = all)",
+	}
+	reportFormatFlag = cli.StringFlag{
+		Name:  "report.format",
+		Usage: "Format of the report files: csv, jsonl or parquet",
+		Value: eth.ReportFormatCSV,
+	}
+	reportRedactFlag = cli.StringFlag{
+		Name:  "report.redact",
+		Usage: "Comma separated columns whose values are masked, or replaced by their hash with a :hash suffix (e.g. input,from:hash)",
+	}
+	reportPSIFlag = cli.StringFlag{
+		Name:  "report.psi",
+		Usage: "Private state whose receipts and events of the private transactions are reported",
+		Value: types.DefaultPrivateStateIdentifier.String(),
+	}
+
+	exportReportCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportReport),
+		Name:      "export-report",
+		Usage:     "Export the transactions, receipts and events of a range of blocks for compliance reporting",
+		ArgsUsage: "<dir>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.RaftModeFlag,
+			configFileFlag,
+			reportFromFlag,
+			reportToFlag,
+			reportFromTimeFlag,
+			reportToTimeFlag,
+			reportDatasetsFlag,
+			reportFormatFlag,
+			reportRedactFlag,
+			reportPSIFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-report command writes one file per dataset into <dir>: the transactions,
+their receipts and their events (logs) of a range of blocks, selected by number or
+by time. The private transactions are reported with the receipts and events of the
+private state --report.psi only.
+
+The values of sensitive columns can be masked, or pseudonymized by their hash so that
+the records stay correlated. The node must be stopped, a running node serves the
+same reports over RPC with debug_exportReport.`,
+	}
+)
+
+// exportReport is the export-report command.
+func exportReport(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		utils.Fatalf("This command requires the report directory as argument.")
+	}
+	dir := ctx.Args().First()
+	query, err := makeReportQuery(ctx)
+	if err != nil {
+		utils.Fatalf("Invalid report: %v", err)
+	}
+	if err := query.Validate(); err != nil {
+		utils.Fatalf("Invalid report: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		utils.Fatalf("Failed to create the report directory: %v", err)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+	chain, _ := utils.MakeChain(ctx, stack, true, true)
+	defer chain.Stop()
+
+	var (
+		outputs = make(map[string]io.Writer)
+		files   []*os.File
+		writers []*bufio.Writer
+	)
+	for _, dataset := range query.DatasetNames() {
+		f, err := os.OpenFile(filepath.Join(dir, dataset+query.FileExtension()), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			utils.Fatalf("Failed to create the report file: %v", err)
+		}
+		defer f.Close()
+		w := bufio.NewWriter(f)
+		files, writers = append(files, f), append(writers, w)
+		outputs[dataset] = w
+	}
+	start := time.Now()
+	psi := types.PrivateStateIdentifier(ctx.String(reportPSIFlag.Name))
+	summary, err := eth.ExportReport(chain, cfg.Eth.RaftMode, psi, query, outputs)
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	for i, w := range writers {
+		if err := w.Flush(); err != nil {
+			utils.Fatalf("Failed to write the report file: %v", err)
+		}
+		if err := files[i].Sync(); err != nil {
+			utils.Fatalf("Failed to write the report file: %v", err)
+		}
+	}
+	fmt.Printf("Report of blocks #%d-#%d written to %s in %v\n", summary.FromBlock, summary.ToBlock, dir, time.Since(start))
+	for _, dataset := range query.DatasetNames() {
+		fmt.Printf("  %s: %d records\n", dataset, summary.Records[dataset])
+	}
+	return nil
+}
+
+// makeReportQuery returns the report selected by the flags
+func makeReportQuery(ctx *cli.Context) (*eth.ReportQuery, error) {
+	query := &eth.ReportQuery{
+		Format: ctx.String(reportFormatFlag.Name),
+	}
+	if n := ctx.Int64(reportFromFlag.Name); n >= 0 {
+		from := rpc.BlockNumber(n)
+		query.FromBlock = &from
+	}
+	if n := ctx.Int64(reportToFlag.Name); n >= 0 {
+		to := rpc.BlockNumber(n)
+		query.ToBlock = &to
+	}
+	for _, t := range []struct {
+		flag string
+		dst  **time.Time
+	}{{reportFromTimeFlag.Name, &query.FromTime}, {reportToTimeFlag.Name, &query.ToTime}} {
+		if s := ctx.String(t.flag); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("option %q: %v", t.flag, err)
+			}
+			*t.dst = &parsed
+		}
+	}
+	query.Datasets = splitList(ctx.String(reportDatasetsFlag.Name))
+	query.Redact = splitList(ctx.String(reportRedactFlag.Name))
+	return query, nil
+}
+
+// splitList returns the non-empty elements of a comma separated list
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
@@ -892,7 +892,7 @@ func retesteth(ctx *cli.Context) error {
 	if err != nil {
 		utils.Fatalf("Could not register RPC apis: %w", err)
 	}
-	handler := node.NewHTTPHandlerStack(srv, cors, vhosts)
+	handler := node.NewHTTPHandlerStack(srv, cors, vhosts, 0)
 
 	// start http server
 	var RetestethHTTPTimeouts = rpc.HTTPTimeouts{
@@ -0,0 +1,177 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var (
+	stateDigestPSIsFlag = cli.StringFlag{
+		Name:  "statedigest.psis",
+		Usage: "Comma separated private state identifiers of the private states digested (default = all the private states of the node)",
+	}
+	stateDigestAccountsFlag = cli.BoolFlag{
+		Name:  "statedigest.accounts",
+		Usage: "Include the digest of each account, to locate the divergence of the states",
+	}
+	stateDigestCompareFlag = cli.StringFlag{
+		Name:  "statedigest.compare",
+		Usage: "File of the state digest exported by another node, compared with the states of this node",
+	}
+
+	stateDigestCommand = cli.Command{
+		Action:    utils.MigrateFlags(stateDigest),
+		Name:      "state-digest",
+		Usage:     "Export a canonical digest of the public and private states at a block",
+		ArgsUsage: "[<blockHash> | <blockNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.RaftModeFlag,
+			configFileFlag,
+			stateDigestPSIsFlag,
+			stateDigestAccountsFlag,
+			stateDigestCompareFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The state-digest command prints the digest of the public state and of each private
+state at the block, the head block by default, as JSON. The digest of a state covers
+its accounts in the order of their address hash, leaving out the data local to the
+node, so the member nodes of a private state get the same digest for it as long as
+they agree on its accounts, balances, code and storage.
+
+With --statedigest.accounts, the digest of each account is printed as well. Given
+the file exported with --statedigest.accounts by another node at the same block,
+--statedigest.compare prints the states and the accounts which diverge, and fails
+if any does. The node must be stopped.`,
+	}
+)
+
+// stateDigest is the state-digest command.
+func stateDigest(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	chain, _ := utils.MakeChain(ctx, stack, true, true)
+	defer chain.Stop()
+
+	block := chain.CurrentBlock()
+	if arg := ctx.Args().First(); arg != "" {
+		if hashish(arg) {
+			block = chain.GetBlockByHash(common.HexToHash(arg))
+		} else {
+			num, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				utils.Fatalf("Invalid block number %q: %v", arg, err)
+			}
+			block = chain.GetBlockByNumber(num)
+		}
+		if block == nil {
+			utils.Fatalf("Block %s not found", arg)
+		}
+	}
+	var psis []types.PrivateStateIdentifier
+	if ctx.IsSet(stateDigestPSIsFlag.Name) {
+		for _, psi := range strings.Split(ctx.String(stateDigestPSIsFlag.Name), ",") {
+			psis = append(psis, types.PrivateStateIdentifier(strings.TrimSpace(psi)))
+		}
+	}
+	compare := ctx.String(stateDigestCompareFlag.Name)
+	digest, err := core.StateDigestAt(chain, block, psis, ctx.Bool(stateDigestAccountsFlag.Name) || compare != "")
+	if err != nil {
+		utils.Fatalf("Failed to digest the states: %v", err)
+	}
+	if compare == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(digest)
+	}
+
+	data, err := ioutil.ReadFile(compare)
+	if err != nil {
+		utils.Fatalf("Failed to read the state digest: %v", err)
+	}
+	other := new(core.ChainStateDigest)
+	if err := json.Unmarshal(data, other); err != nil {
+		utils.Fatalf("Invalid state digest %s: %v", compare, err)
+	}
+	if other.Hash != digest.Hash {
+		utils.Fatalf("The state digest is of block #%d %x, not #%d %x", other.Number, other.Hash, digest.Number, digest.Hash)
+	}
+	diverging := compareStateDigest("public state", digest.Public, other.Public)
+	for psi, privateDigest := range digest.Private {
+		otherDigest, ok := other.Private[psi]
+		if !ok {
+			fmt.Printf("private state %s: not in %s\n", psi, compare)
+			continue
+		}
+		if compareStateDigest("private state "+psi.String(), privateDigest, otherDigest) {
+			diverging = true
+		}
+	}
+	if diverging {
+		utils.Fatalf("The states diverge from the ones of %s", compare)
+	}
+	fmt.Printf("The states at block #%d match the ones of %s\n", digest.Number, compare)
+	return nil
+}
+
+// compareStateDigest prints the accounts of the state which diverge from the ones
+// of the other node, and reports whether any does
+func compareStateDigest(name string, digest, other *state.StateDigest) bool {
+	if digest.Digest == other.Digest {
+		fmt.Printf("%s: %x matches\n", name, digest.Digest)
+		return false
+	}
+	fmt.Printf("%s: %x diverges from %x\n", name, digest.Digest, other.Digest)
+	if len(other.Accounts) == 0 && other.Count > 0 {
+		fmt.Println("  the accounts are not in the digest of the other node, see --statedigest.accounts")
+		return true
+	}
+	addresses := make(map[common.Hash]*common.Address)
+	for _, accounts := range [][]state.AccountDigest{digest.Accounts, other.Accounts} {
+		for _, account := range accounts {
+			if account.Address != nil {
+				addresses[account.AddrHash] = account.Address
+			}
+		}
+	}
+	for _, addrHash := range digest.Diverging(other) {
+		if addr := addresses[addrHash]; addr != nil {
+			fmt.Printf("  account %s diverges\n", addr.Hex())
+		} else {
+			fmt.Printf("  account with address hash %x diverges\n", addrHash)
+		}
+	}
+	return true
+}
@@ -41,6 +41,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			configFileFlag,
 			utils.DataDirFlag,
 			utils.AncientFlag,
+			utils.DiskUsageThresholdsFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.SmartCardDaemonPathFlag,
@@ -127,6 +128,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.UnlockedAccountFlag,
 			utils.PasswordFileFlag,
 			utils.ExternalSignerFlag,
+			utils.KMSKeysFlag,
+			utils.KMSUnlockedFlag,
 			utils.InsecureUnlockAllowedFlag,
 		},
 	},
@@ -135,6 +138,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 		Flags: []cli.Flag{
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
+			utils.IPCSecurityDescriptorFlag,
+			utils.IPCAllowedSIDsFlag,
 			utils.HTTPEnabledFlag,
 			utils.HTTPListenAddrFlag,
 			utils.HTTPPortFlag,
@@ -146,11 +151,30 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.WSPortFlag,
 			utils.WSApiFlag,
 			utils.WSAllowedOriginsFlag,
+			utils.WSPingIntervalFlag,
+			utils.WSPongTimeoutFlag,
+			utils.WSMaxMissedPongsFlag,
 			utils.GraphQLEnabledFlag,
 			utils.GraphQLCORSDomainFlag,
 			utils.GraphQLVirtualHostsFlag,
 			utils.RPCGlobalGasCapFlag,
 			utils.RPCGlobalTxFeeCapFlag,
+			utils.RPCBatchRequestLimitFlag,
+			utils.RPCBatchResponseMaxSizeFlag,
+			utils.RPCMethodTimeoutsFlag,
+			utils.RPCRateLimitGlobalFlag,
+			utils.RPCRateLimitGlobalBurstFlag,
+			utils.RPCRateLimitConnFlag,
+			utils.RPCRateLimitConnBurstFlag,
+			utils.RPCRateLimitWaitFlag,
+			utils.RPCTrustedProxiesFlag,
+			utils.RPCProxyProtocolFlag,
+			utils.HTTP2Flag,
+			utils.HTTPGzipMinSizeFlag,
+			utils.GRPCEnabledFlag,
+			utils.GRPCListenAddrFlag,
+			utils.GRPCPortFlag,
+			utils.GRPCApiFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -192,6 +216,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
 			utils.MinerNoVerfiyFlag,
+			utils.MinerOrgQuotaGasShareFlag,
+			utils.MinerOrgQuotaMaxTxsFlag,
 		},
 	},
 	{
@@ -252,6 +278,10 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 		Flags: []cli.Flag{
 			utils.QuorumImmutabilityThreshold,
 			utils.EnableNodePermissionFlag,
+			utils.PermissionApiKeysFlag,
+			utils.NodeIdentityCertFlag,
+			utils.NodeIdentityCAFlag,
+			utils.NodeIdentityCRLFlag,
 			utils.PluginSettingsFlag,
 			utils.PluginSkipVerifyFlag,
 			utils.PluginLocalVerifyFlag,
@@ -260,6 +290,46 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MultitenancyFlag,
 			utils.RevertReasonFlag,
 			utils.PrivateCacheTrieJournalFlag,
+			utils.PrivateStateColdAfterFlag,
+			utils.PrivatePayloadIndexFlag,
+			utils.HealthMinPeersFlag,
+			utils.HealthMaxBlockAgeFlag,
+			utils.AdvisoryFeedFlag,
+			utils.AdvisoryIntervalFlag,
+		},
+	},
+	{
+		Name: "QLIGHT",
+		Flags: []cli.Flag{
+			utils.QLightServerFlag,
+			utils.QLightServerEntitlementsFlag,
+			utils.QLightServerRateLimitFlag,
+			utils.QLightServerRateLimitBurstFlag,
+			utils.QLightClientFlag,
+			utils.QLightClientPSIFlag,
+			utils.QLightClientTokenFlag,
+			utils.QLightClientServerNodeFlag,
+		},
+	},
+	{
+		Name: "STATE SYNC",
+		Flags: []cli.Flag{
+			utils.StateSyncServerFlag,
+			utils.StateSyncServerEntitlementsFlag,
+			utils.StateSyncServerNodeFlag,
+		},
+	},
+	{
+		Name: "EVENT PUBLISHING",
+		Flags: []cli.Flag{
+			utils.PublisherURLFlag,
+			utils.PublisherTopicFlag,
+		},
+	},
+	{
+		Name: "PRIVATE TRANSACTION RELAY",
+		Flags: []cli.Flag{
+			utils.RelayConfigFlag,
 		},
 	},
 	{
@@ -277,6 +347,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.QuorumPTMTlsClientCertFlag,
 			utils.QuorumPTMTlsClientKeyFlag,
 			utils.QuorumPTMTlsInsecureSkipVerify,
+			utils.QuorumPTMAliasesFlag,
 		},
 	},
 	{
@@ -293,6 +364,9 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.RaftJoinExistingFlag,
 			utils.RaftPortFlag,
 			utils.RaftDNSEnabledFlag,
+			utils.RaftMaxSyncPeersFlag,
+			utils.RaftSyncBandwidthFlag,
+			utils.RaftFollowerSyncFlag,
 		},
 	},
 	{
@@ -300,6 +374,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 		Flags: []cli.Flag{
 			utils.IstanbulRequestTimeoutFlag,
 			utils.IstanbulBlockPeriodFlag,
+			utils.IstanbulKMSKeyFlag,
 		},
 	},
 	// END QUORUM
@@ -0,0 +1,112 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Quorum
+
+var (
+	verifyFromFlag = cli.Uint64Flag{
+		Name:  "verify.from",
+		Usage: "First block verified",
+	}
+	verifyToFlag = cli.Uint64Flag{
+		Name:  "verify.to",
+		Usage: "Last block verified (default = head)",
+	}
+	verifySamplesFlag = cli.IntFlag{
+		Name:  "verify.samples",
+		Usage: "Number of ranges of blocks re-executed to recompute their public and private state roots",
+		Value: 10,
+	}
+	verifySampleSizeFlag = cli.Uint64Flag{
+		Name:  "verify.samplesize",
+		Usage: "Number of blocks re-executed per range",
+		Value: 16,
+	}
+
+	verifyDBCommand = cli.Command{
+		Action: utils.MigrateFlags(verifyDB),
+		Name:   "verify-db",
+		Usage:  "Verify the integrity of the chain database and of the public and private states",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.RaftModeFlag,
+			configFileFlag,
+			verifyFromFlag,
+			verifyToFlag,
+			verifySamplesFlag,
+			verifySampleSizeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The verify-db command walks the headers, bodies and receipts of the canonical chain,
+and checks the freezer against the key-value store. It re-executes sampled ranges of
+blocks to recompute their public and private state roots, from the nearest state
+kept by the node: on a node with pruned public state, use --gcmode=archive to verify
+more blocks.
+
+Each corruption is reported with the block, the store and table or key of the
+corrupted data, and the suggested repair. The command fails if any corruption is
+found. The node must be stopped.`,
+	}
+)
+
+// verifyDB is the verify-db command.
+func verifyDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	chain, _ := utils.MakeChain(ctx, stack, true, true)
+	defer chain.Stop()
+
+	start := time.Now()
+	summary, err := core.VerifyChain(chain, &core.VerifyConfig{
+		From:       ctx.Uint64(verifyFromFlag.Name),
+		To:         ctx.Uint64(verifyToFlag.Name),
+		Samples:    ctx.Int(verifySamplesFlag.Name),
+		SampleSize: ctx.Uint64(verifySampleSizeFlag.Name),
+	}, func(c *core.Corruption) {
+		fmt.Printf("CORRUPTED block #%d %x\n", c.Number, c.Hash)
+		fmt.Printf("  %s: %s\n", c.Kind, c.Message)
+		fmt.Printf("  location: %s\n", c.Location)
+		fmt.Printf("  action:   %s\n", c.Action)
+	})
+	if err != nil {
+		utils.Fatalf("Verification error: %v", err)
+	}
+	fmt.Printf("Verified blocks #%d-#%d in %v\n", summary.From, summary.To, time.Since(start))
+	fmt.Printf("  frozen blocks:      %d\n", summary.Ancients)
+	fmt.Printf("  blocks re-executed: %d\n", summary.Executed)
+	if summary.SkippedSamples > 0 {
+		fmt.Printf("  ranges skipped:     %d (no state to re-execute them from)\n", summary.SkippedSamples)
+	}
+	if summary.Corruptions > 0 {
+		utils.Fatalf("%d corruptions found", summary.Corruptions)
+	}
+	fmt.Println("No corruption found")
+	return nil
+}
@@ -18,8 +18,10 @@
 package utils
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -35,6 +37,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/accounts/kms"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
 	http2 "github.com/ethereum/go-ethereum/common/http"
@@ -45,6 +48,7 @@ import (
 	istanbulBackend "github.com/ethereum/go-ethereum/consensus/istanbul/backend"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
@@ -70,10 +74,18 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/netutil"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/permission"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
 	"github.com/ethereum/go-ethereum/permission/core/types"
 	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/ethereum/go-ethereum/plugin/blockvalidation"
 	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/publisher"
+	"github.com/ethereum/go-ethereum/qlight"
 	"github.com/ethereum/go-ethereum/raft"
+	"github.com/ethereum/go-ethereum/relay"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/statesync"
+	"github.com/ethereum/go-ethereum/tracing"
 	pcsclite "github.com/gballet/go-libpcsclite"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -129,6 +141,11 @@ var (
 		Name:  "datadir.ancient",
 		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
 	}
+	// Quorum
+	DiskUsageThresholdsFlag = cli.StringFlag{
+		Name:  "datadir.thresholds",
+		Usage: "Comma separated disk usage warning thresholds of the chaindata, freezer, raftwal, raftsnap and raftstate folders (e.g. chaindata=500GB,raftwal=10GB)",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -446,6 +463,15 @@ var (
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
 	}
+	// Quorum
+	MinerOrgQuotaGasShareFlag = cli.Float64Flag{
+		Name:  "miner.orgquota.gasshare",
+		Usage: "Share of the block gas limit the transactions of each organization may use, 0 for no limit (raft and istanbul, requires permissioning)",
+	}
+	MinerOrgQuotaMaxTxsFlag = cli.IntFlag{
+		Name:  "miner.orgquota.maxtxs",
+		Usage: "Number of transactions of each organization per block, 0 for no limit (raft and istanbul, requires permissioning)",
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -462,6 +488,16 @@ var (
 		Usage: "External signer (url or path to ipc file)",
 		Value: "",
 	}
+	// Quorum
+	KMSKeysFlag = cli.StringFlag{
+		Name:  "kms.keys",
+		Usage: "Comma separated list of the URLs of cloud KMS keys to use as accounts (awskms://<region>/<key id> or azurekv://<vault>/<key>/<version>)",
+		Value: "",
+	}
+	KMSUnlockedFlag = cli.BoolFlag{
+		Name:  "kms.unlocked",
+		Usage: "Sign with the cloud KMS accounts without opening their wallets first with personal_openWallet",
+	}
 	VMEnableDebugFlag = cli.BoolFlag{
 		Name:  "vmdebug",
 		Usage: "Record information useful for VM and contract debugging",
@@ -480,6 +516,47 @@ var (
 		Usage: "Sets a cap on transaction fee (in ether) that can be sent via the RPC APIs (0 = no cap)",
 		Value: eth.DefaultConfig.RPCTxFeeCap,
 	}
+	// Quorum
+	RPCBatchRequestLimitFlag = cli.IntFlag{
+		Name:  "rpc.batch-request-limit",
+		Usage: "Maximum number of messages in a JSON-RPC batch served over HTTP and WS (0 = no limit)",
+	}
+	RPCBatchResponseMaxSizeFlag = cli.IntFlag{
+		Name:  "rpc.batch-response-max-size",
+		Usage: "Maximum aggregated size in bytes of the results of a JSON-RPC batch served over HTTP and WS (0 = no limit)",
+	}
+	RPCMethodTimeoutsFlag = cli.StringFlag{
+		Name:  "rpc.method-timeouts",
+		Usage: "Comma separated execution deadlines of RPC methods, by method or namespace (e.g. eth_call=5s,debug_*=60s)",
+	}
+	RPCRateLimitGlobalFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit.global",
+		Usage: "Maximum number of RPC calls per second served over all the connections (0 = no limit)",
+	}
+	RPCRateLimitGlobalBurstFlag = cli.IntFlag{
+		Name:  "rpc.ratelimit.global-burst",
+		Usage: "Maximum burst of RPC calls served over all the connections (0 = the global rate)",
+	}
+	RPCRateLimitConnFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit.conn",
+		Usage: "Maximum number of RPC calls per second served for a client address, or over an IPC connection (0 = no limit)",
+	}
+	RPCRateLimitConnBurstFlag = cli.IntFlag{
+		Name:  "rpc.ratelimit.conn-burst",
+		Usage: "Maximum burst of RPC calls served for a client address, or over an IPC connection (0 = the connection rate)",
+	}
+	RPCRateLimitWaitFlag = cli.BoolFlag{
+		Name:  "rpc.ratelimit.wait",
+		Usage: "Delay the RPC calls exceeding the rate limits instead of rejecting them",
+	}
+	RPCTrustedProxiesFlag = cli.StringFlag{
+		Name:  "rpc.trusted-proxies",
+		Usage: "Comma separated list of IP addresses or CIDR ranges of the reverse proxies allowed to forward the client address of HTTP, WS and gRPC requests (X-Forwarded-For)",
+	}
+	RPCProxyProtocolFlag = cli.BoolFlag{
+		Name:  "rpc.proxy-protocol",
+		Usage: "Require the PROXY protocol header on the HTTP, WS and gRPC connections from the trusted proxies",
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ethstats",
@@ -523,6 +600,15 @@ var (
 		Name:  "ipcpath",
 		Usage: "Filename for IPC socket/pipe within the datadir (explicit paths escape it)",
 	}
+	// Quorum
+	IPCSecurityDescriptorFlag = cli.StringFlag{
+		Name:  "ipc.sddl",
+		Usage: "SDDL security descriptor of the IPC named pipe (Windows only)",
+	}
+	IPCAllowedSIDsFlag = cli.StringFlag{
+		Name:  "ipc.allowed-sids",
+		Usage: "Comma separated list of SIDs of the accounts allowed to attach to the IPC named pipe besides the node's account (Windows only)",
+	}
 	HTTPEnabledFlag = cli.BoolFlag{
 		Name:  "http",
 		Usage: "Enable the HTTP-RPC server",
@@ -590,6 +676,48 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	// Quorum
+	WSPingIntervalFlag = cli.DurationFlag{
+		Name:  "ws.ping-interval",
+		Usage: "Idle period after which a ping is sent over a WS-RPC connection",
+		Value: rpc.DefaultWebsocketKeepalive.PingInterval,
+	}
+	WSPongTimeoutFlag = cli.DurationFlag{
+		Name:  "ws.pong-timeout",
+		Usage: "Period to wait for the pong answering a WS-RPC ping (0 = the ping interval)",
+	}
+	WSMaxMissedPongsFlag = cli.IntFlag{
+		Name:  "ws.max-missed-pongs",
+		Usage: "Number of consecutive unanswered pings after which a WS-RPC connection and its subscriptions are closed (0 = never close)",
+	}
+	// Quorum
+	HTTP2Flag = cli.BoolFlag{
+		Name:  "http.http2",
+		Usage: "Enable HTTP/2 on the HTTP-RPC and WS-RPC servers (cleartext h2c when TLS is not enabled)",
+	}
+	HTTPGzipMinSizeFlag = cli.IntFlag{
+		Name:  "http.gzip.minsize",
+		Usage: "Size in bytes above which HTTP-RPC responses are compressed with gzip when accepted by the client (-1 = disabled)",
+	}
+	GRPCEnabledFlag = cli.BoolFlag{
+		Name:  "grpc",
+		Usage: "Enable the gRPC server serving the JSON-RPC API",
+	}
+	GRPCListenAddrFlag = cli.StringFlag{
+		Name:  "grpc.addr",
+		Usage: "gRPC server listening interface",
+		Value: node.DefaultGRPCHost,
+	}
+	GRPCPortFlag = cli.IntFlag{
+		Name:  "grpc.port",
+		Usage: "gRPC server listening port",
+		Value: node.DefaultGRPCPort,
+	}
+	GRPCApiFlag = cli.StringFlag{
+		Name:  "grpc.api",
+		Usage: "API's offered over the gRPC interface",
+		Value: "",
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -749,6 +877,18 @@ var (
 		Usage: "Comma-separated InfluxDB tags (key/values) attached to all measurements",
 		Value: "host=localhost",
 	}
+	// Quorum
+	TracingOTLPEndpointFlag = cli.StringFlag{
+		Name:  "tracing.otlp.endpoint",
+		Usage: "OTLP/HTTP collector endpoint to export the transaction and block processing spans to (e.g.: http://localhost:4318), tracing is disabled if empty",
+		Value: "",
+	}
+	TracingServiceNameFlag = cli.StringFlag{
+		Name:  "tracing.service-name",
+		Usage: "Service name reported with the exported spans",
+		Value: "geth",
+	}
+	// End Quorum
 	EWASMInterpreterFlag = cli.StringFlag{
 		Name:  "vm.ewasm",
 		Usage: "External ewasm configuration (default = built-in interpreter)",
@@ -803,12 +943,40 @@ var (
 		Name:  "raftdnsenable",
 		Usage: "Enable DNS resolution of peers",
 	}
+	RaftMaxSyncPeersFlag = cli.IntFlag{
+		Name:  "raftmaxsyncpeers",
+		Usage: "Maximum number of chain requests of the peers catching up served at once (0 = no limit)",
+	}
+	RaftSyncBandwidthFlag = cli.IntFlag{
+		Name:  "raftsyncbandwidth",
+		Usage: "Maximum bytes per second of the chain served to the peers catching up (0 = no limit)",
+	}
+	RaftFollowerSyncFlag = cli.BoolFlag{
+		Name:  "raftfollowersync",
+		Usage: "Sync the chain from the followers before the leader when catching up",
+	}
 
 	// Permission
 	EnableNodePermissionFlag = cli.BoolFlag{
 		Name:  "permissioned",
 		Usage: "If enabled, the node will allow only a defined list of nodes to connect",
 	}
+	PermissionApiKeysFlag = cli.BoolFlag{
+		Name:  "permissioned.apikeys",
+		Usage: "Authenticate the RPC calls with the API keys created by the org admins (requires permissioning). The HTTP, WS and gRPC RPC then require authentication, by an API key or the security plugin",
+	}
+	NodeIdentityCertFlag = DirectoryFlag{
+		Name:  "identity.cert",
+		Usage: "PEM file with the node certificate issued by its organization for the node key, followed by the intermediate certificates. The peers must present a valid certificate too",
+	}
+	NodeIdentityCAFlag = DirectoryFlag{
+		Name:  "identity.ca",
+		Usage: "PEM file with the trusted CA certificates of the organizations",
+	}
+	NodeIdentityCRLFlag = DirectoryFlag{
+		Name:  "identity.crl",
+		Usage: "PEM file with the revocation lists of the organizations",
+	}
 	AllowedFutureBlockTimeFlag = cli.Uint64Flag{
 		Name:  "allowedfutureblocktime",
 		Usage: "Max time (in seconds) from current time allowed for blocks, before they're considered future blocks",
@@ -847,6 +1015,10 @@ var (
 		Usage: "Default minimum difference between two consecutive block's timestamps in seconds",
 		Value: eth.DefaultConfig.Istanbul.BlockPeriod,
 	}
+	IstanbulKMSKeyFlag = cli.StringFlag{
+		Name:  "istanbul.kmskey",
+		Usage: "URL of the cloud KMS key signing the Istanbul messages and seals instead of the node key (awskms://<region>/<key id> or azurekv://<vault>/<key>/<version>)",
+	}
 	// Multitenancy setting
 	MultitenancyFlag = cli.BoolFlag{
 		Name:  "multitenancy",
@@ -859,12 +1031,109 @@ var (
 		Usage: "Enable saving revert reason in the transaction receipts for this node.",
 	}
 
+	// Health probes
+	HealthMinPeersFlag = cli.IntFlag{
+		Name:  "health.min-peers",
+		Usage: "Minimum number of connected peers for the node to be reported ready by /health/ready (0 = no check)",
+		Value: eth.DefaultConfig.Health.MinPeers,
+	}
+	HealthMaxBlockAgeFlag = cli.DurationFlag{
+		Name:  "health.max-block-age",
+		Usage: "Maximum age of the head block for the node to be reported ready by /health/ready (0 = no check)",
+		Value: eth.DefaultConfig.Health.MaxBlockAge,
+	}
+
+	// Security advisories
+	AdvisoryFeedFlag = cli.StringFlag{
+		Name:  "advisory.feed",
+		Usage: "URL or file path of the security advisory feed the running Quorum and plugin versions are checked against (disabled if empty)",
+	}
+	AdvisoryIntervalFlag = cli.DurationFlag{
+		Name:  "advisory.interval",
+		Usage: "Interval between the checks of the security advisory feed",
+		Value: node.DefaultAdvisoryInterval,
+	}
+
+	// qlight
+	QLightServerFlag = cli.BoolFlag{
+		Name:  "qlight.server",
+		Usage: "Serve the private payloads to the qlight client nodes",
+	}
+	QLightServerEntitlementsFlag = cli.StringFlag{
+		Name:  "qlight.server.entitlements",
+		Usage: "JSON file of the private states and TM keys each qlight client is entitled to, by client node ID (e.g. {\"<node ID>\": {\"psis\": [\"PS1\"], \"tmKeys\": [\"<key>\"]}})",
+	}
+	QLightServerRateLimitFlag = cli.Float64Flag{
+		Name:  "qlight.server.ratelimit",
+		Usage: "Maximum number of private payloads per second served to a qlight client (0 = no limit)",
+	}
+	QLightServerRateLimitBurstFlag = cli.IntFlag{
+		Name:  "qlight.server.ratelimit.burst",
+		Usage: "Maximum burst of private payloads served to a qlight client (0 = the rate limit)",
+	}
+	QLightClientFlag = cli.BoolFlag{
+		Name:  "qlight.client",
+		Usage: "Run as a qlight client node, syncing from a qlight server and fetching the private payloads from it instead of running a private transaction manager",
+	}
+	QLightClientPSIFlag = cli.StringFlag{
+		Name:  "qlight.client.psi",
+		Usage: "Private state of the qlight client",
+		Value: coretypes.DefaultPrivateStateIdentifier.String(),
+	}
+	QLightClientTokenFlag = cli.StringFlag{
+		Name:  "qlight.client.token",
+		Usage: "Token authorizing the qlight client on a multitenant server",
+	}
+	QLightClientServerNodeFlag = cli.StringFlag{
+		Name:  "qlight.client.servernode",
+		Usage: "Enode URL of the qlight server",
+	}
+
+	// State sync
+	StateSyncServerFlag = cli.BoolFlag{
+		Name:  "statesync.server",
+		Usage: "Serve the public and private states to the state sync client nodes",
+	}
+	StateSyncServerEntitlementsFlag = cli.StringFlag{
+		Name:  "statesync.server.entitlements",
+		Usage: "JSON file of the private states each state sync client is entitled to, by client node ID (e.g. {\"<node ID>\": {\"psis\": [\"PS1\"]}})",
+	}
+	StateSyncServerNodeFlag = cli.StringFlag{
+		Name:  "statesync.servernode",
+		Usage: "Enode URL of the state sync server the private states of the fast sync pivot block are synced from",
+	}
+
+	// Event publishing
+	PublisherURLFlag = cli.StringFlag{
+		Name:  "publisher.url",
+		Usage: "Message broker the new blocks, receipts and permission events are published to: nats(+tls)://host:port[?jetstream=true][&tlsca=file] or kafka+http(s)://host:port of a Kafka REST proxy (disabled if empty)",
+	}
+	PublisherTopicFlag = cli.StringFlag{
+		Name:  "publisher.topic",
+		Usage: "Prefix of the topics the chain data is published to, e.g. <prefix>.blocks",
+		Value: publisher.DefaultConfig.Topic,
+	}
+
+	// Private transaction relay
+	RelayConfigFlag = cli.StringFlag{
+		Name:  "relay.config",
+		Usage: "JSON file of the destination network, TM key mapping and rules of the private transactions relayed to another network (disabled if empty)",
+	}
+
 	// Private state cache
 	PrivateCacheTrieJournalFlag = cli.StringFlag{
 		Name:  "private.cache.trie.journal",
 		Usage: "Disk journal directory for private trie cache to survive node restarts",
 		Value: eth.DefaultConfig.PrivateTrieCleanCacheJournal,
 	}
+	PrivateStateColdAfterFlag = cli.DurationFlag{
+		Name:  "private.cold.after",
+		Usage: "Inactivity after which the storage of a private contract is moved to the cold store, restored on the next access (0 = disabled)",
+	}
+	PrivatePayloadIndexFlag = cli.BoolFlag{
+		Name:  "private.payload.index",
+		Usage: "Index the hashes of all the private payloads with their blocks, party or not, served by quorum_getPrivatePayloadInclusions (requires --syncmode full)",
+	}
 
 	// Quorum Private Transaction Manager connection options
 	QuorumPTMUnixSocketFlag = DirectoryFlag{
@@ -920,6 +1189,10 @@ var (
 		Name:  "ptm.tls.insecureskipverify",
 		Usage: "Disable verification of server's TLS certificate on connection to private transaction manager",
 	}
+	QuorumPTMAliasesFlag = cli.StringFlag{
+		Name:  "ptm.aliases",
+		Usage: "Path to the JSON file of the private transaction manager public keys by participant name, accepted in privateFrom and privateFor",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -1136,6 +1409,48 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(HTTPVirtualHostsFlag.Name) {
 		cfg.HTTPVirtualHosts = SplitAndTrim(ctx.GlobalString(HTTPVirtualHostsFlag.Name))
 	}
+
+	// Quorum
+	if ctx.GlobalIsSet(RPCBatchRequestLimitFlag.Name) {
+		cfg.BatchRequestLimit = ctx.GlobalInt(RPCBatchRequestLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCBatchResponseMaxSizeFlag.Name) {
+		cfg.BatchResponseMaxSize = ctx.GlobalInt(RPCBatchResponseMaxSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCMethodTimeoutsFlag.Name) {
+		timeouts, err := rpc.ParseMethodTimeouts(ctx.GlobalString(RPCMethodTimeoutsFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", RPCMethodTimeoutsFlag.Name, err)
+		}
+		cfg.RPCMethodTimeouts = timeouts
+	}
+	if ctx.GlobalIsSet(RPCRateLimitGlobalFlag.Name) {
+		cfg.RPCRateLimits.GlobalRate = ctx.GlobalFloat64(RPCRateLimitGlobalFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCRateLimitGlobalBurstFlag.Name) {
+		cfg.RPCRateLimits.GlobalBurst = ctx.GlobalInt(RPCRateLimitGlobalBurstFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCRateLimitConnFlag.Name) {
+		cfg.RPCRateLimits.ConnRate = ctx.GlobalFloat64(RPCRateLimitConnFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCRateLimitConnBurstFlag.Name) {
+		cfg.RPCRateLimits.ConnBurst = ctx.GlobalInt(RPCRateLimitConnBurstFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCRateLimitWaitFlag.Name) {
+		cfg.RPCRateLimits.Wait = ctx.GlobalBool(RPCRateLimitWaitFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTrustedProxiesFlag.Name) {
+		cfg.RPCTrustedProxies = SplitAndTrim(ctx.GlobalString(RPCTrustedProxiesFlag.Name))
+	}
+	if ctx.GlobalIsSet(RPCProxyProtocolFlag.Name) {
+		cfg.RPCProxyProtocol = ctx.GlobalBool(RPCProxyProtocolFlag.Name)
+	}
+	if ctx.GlobalIsSet(HTTP2Flag.Name) {
+		cfg.HTTP2 = ctx.GlobalBool(HTTP2Flag.Name)
+	}
+	if ctx.GlobalIsSet(HTTPGzipMinSizeFlag.Name) {
+		cfg.HTTPGzipMinSize = ctx.GlobalInt(HTTPGzipMinSizeFlag.Name)
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1185,6 +1500,35 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(WSApiFlag.Name) {
 		cfg.WSModules = SplitAndTrim(ctx.GlobalString(WSApiFlag.Name))
 	}
+
+	// Quorum
+	if ctx.GlobalIsSet(WSPingIntervalFlag.Name) {
+		cfg.WSKeepalive.PingInterval = ctx.GlobalDuration(WSPingIntervalFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSPongTimeoutFlag.Name) {
+		cfg.WSKeepalive.PongTimeout = ctx.GlobalDuration(WSPongTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSMaxMissedPongsFlag.Name) {
+		cfg.WSKeepalive.MaxMissedPongs = ctx.GlobalInt(WSMaxMissedPongsFlag.Name)
+	}
+}
+
+// Quorum
+// setGRPC creates the gRPC listener interface string from the set command line
+// flags, returning empty if the gRPC endpoint is disabled.
+func setGRPC(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalBool(GRPCEnabledFlag.Name) && cfg.GRPCHost == "" {
+		cfg.GRPCHost = "127.0.0.1"
+		if ctx.GlobalIsSet(GRPCListenAddrFlag.Name) {
+			cfg.GRPCHost = ctx.GlobalString(GRPCListenAddrFlag.Name)
+		}
+	}
+	if ctx.GlobalIsSet(GRPCPortFlag.Name) {
+		cfg.GRPCPort = ctx.GlobalInt(GRPCPortFlag.Name)
+	}
+	if ctx.GlobalIsSet(GRPCApiFlag.Name) {
+		cfg.GRPCModules = SplitAndTrim(ctx.GlobalString(GRPCApiFlag.Name))
+	}
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -1197,6 +1541,15 @@ func setIPC(ctx *cli.Context, cfg *node.Config) {
 	case ctx.GlobalIsSet(IPCPathFlag.Name):
 		cfg.IPCPath = ctx.GlobalString(IPCPathFlag.Name)
 	}
+
+	// Quorum
+	CheckExclusive(ctx, IPCSecurityDescriptorFlag, IPCAllowedSIDsFlag)
+	if ctx.GlobalIsSet(IPCSecurityDescriptorFlag.Name) {
+		cfg.IPCSecurity.SecurityDescriptor = ctx.GlobalString(IPCSecurityDescriptorFlag.Name)
+	}
+	if ctx.GlobalIsSet(IPCAllowedSIDsFlag.Name) {
+		cfg.IPCSecurity.AllowedSIDs = SplitAndTrim(ctx.GlobalString(IPCAllowedSIDsFlag.Name))
+	}
 }
 
 // setLes configures the les server and ultra light client settings from the command line flags.
@@ -1323,6 +1676,23 @@ func MakePasswordList(ctx *cli.Context) []string {
 	return lines
 }
 
+// Quorum
+// setNodeIdentity configures the node certificates from the command line flags.
+func setNodeIdentity(ctx *cli.Context, cfg *p2p.Config) {
+	if ctx.GlobalIsSet(NodeIdentityCertFlag.Name) {
+		cfg.NodeIdentity.Certificate = ctx.GlobalString(NodeIdentityCertFlag.Name)
+	}
+	if ctx.GlobalIsSet(NodeIdentityCAFlag.Name) {
+		cfg.NodeIdentity.CA = ctx.GlobalString(NodeIdentityCAFlag.Name)
+	}
+	if ctx.GlobalIsSet(NodeIdentityCRLFlag.Name) {
+		cfg.NodeIdentity.CRL = ctx.GlobalString(NodeIdentityCRLFlag.Name)
+	}
+	if cfg.NodeIdentity.Enabled() && cfg.NodeIdentity.CA == "" {
+		Fatalf("Option %q is required with %q", NodeIdentityCAFlag.Name, NodeIdentityCertFlag.Name)
+	}
+}
+
 func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setNodeKey(ctx, cfg)
 	setNAT(ctx, cfg)
@@ -1381,6 +1751,9 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		cfg.DiscoveryV5 = true
 	}
 
+	// Quorum
+	setNodeIdentity(ctx, cfg)
+
 	if netrestrict := ctx.GlobalString(NetrestrictFlag.Name); netrestrict != "" {
 		list, err := netutil.ParseNetlist(netrestrict)
 		if err != nil {
@@ -1405,14 +1778,23 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
 	setWS(ctx, cfg)
+	setGRPC(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	setDataDir(ctx, cfg)
 	setRaftLogDir(ctx, cfg)
+	setDiskUsageThresholds(ctx, cfg)
+	setAdvisoryFeed(ctx, cfg)
 	setSmartCard(ctx, cfg)
 
 	if ctx.GlobalIsSet(ExternalSignerFlag.Name) {
 		cfg.ExternalSigner = ctx.GlobalString(ExternalSignerFlag.Name)
 	}
+	if ctx.GlobalIsSet(KMSKeysFlag.Name) {
+		cfg.KMSKeys = SplitAndTrim(ctx.GlobalString(KMSKeysFlag.Name))
+	}
+	if ctx.GlobalIsSet(KMSUnlockedFlag.Name) {
+		cfg.KMSUnlocked = ctx.GlobalBool(KMSUnlockedFlag.Name)
+	}
 
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
@@ -1431,6 +1813,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(EnableNodePermissionFlag.Name) {
 		cfg.EnableNodePermission = ctx.GlobalBool(EnableNodePermissionFlag.Name)
 	}
+	if ctx.GlobalIsSet(PermissionApiKeysFlag.Name) {
+		cfg.EnablePermissionApiKeys = ctx.GlobalBool(PermissionApiKeysFlag.Name)
+	}
 	if ctx.GlobalIsSet(MultitenancyFlag.Name) {
 		cfg.EnableMultitenancy = ctx.GlobalBool(MultitenancyFlag.Name)
 	}
@@ -1492,6 +1877,128 @@ func setRaftLogDir(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// Quorum
+func setDiskUsageThresholds(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(DiskUsageThresholdsFlag.Name) {
+		thresholds, err := node.ParseDiskUsageThresholds(ctx.GlobalString(DiskUsageThresholdsFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", DiskUsageThresholdsFlag.Name, err)
+		}
+		cfg.DiskUsageThresholds = thresholds
+	}
+}
+
+// Quorum
+//
+// SetQLightConfig applies the qlight flags to the config. A qlight client only peers with
+// its server, it must be called before the node is created.
+func SetQLightConfig(ctx *cli.Context, ethCfg *eth.Config, nodeCfg *node.Config) {
+	if ctx.GlobalBool(QLightServerFlag.Name) {
+		if ethCfg.QuorumLightServer == nil {
+			ethCfg.QuorumLightServer = new(qlight.ServerConfig)
+		}
+		if ctx.GlobalIsSet(QLightServerEntitlementsFlag.Name) {
+			path := ctx.GlobalString(QLightServerEntitlementsFlag.Name)
+			blob, err := ioutil.ReadFile(path)
+			if err != nil {
+				Fatalf("Option %q: %v", QLightServerEntitlementsFlag.Name, err)
+			}
+			var entitlements map[string]*qlight.Entitlement
+			if err := json.Unmarshal(blob, &entitlements); err != nil {
+				Fatalf("Option %q: invalid entitlements file %s: %v", QLightServerEntitlementsFlag.Name, path, err)
+			}
+			ethCfg.QuorumLightServer.Entitlements = entitlements
+		}
+		if ctx.GlobalIsSet(QLightServerRateLimitFlag.Name) {
+			ethCfg.QuorumLightServer.RateLimit = ctx.GlobalFloat64(QLightServerRateLimitFlag.Name)
+		}
+		if ctx.GlobalIsSet(QLightServerRateLimitBurstFlag.Name) {
+			ethCfg.QuorumLightServer.RateBurst = ctx.GlobalInt(QLightServerRateLimitBurstFlag.Name)
+		}
+	}
+	if ctx.GlobalBool(QLightClientFlag.Name) {
+		if ethCfg.QuorumLightClient == nil {
+			ethCfg.QuorumLightClient = &eth.QuorumLightClientConfig{PSI: QLightClientPSIFlag.Value}
+		}
+		if ctx.GlobalIsSet(QLightClientPSIFlag.Name) {
+			ethCfg.QuorumLightClient.PSI = ctx.GlobalString(QLightClientPSIFlag.Name)
+		}
+		if ctx.GlobalIsSet(QLightClientTokenFlag.Name) {
+			ethCfg.QuorumLightClient.Token = ctx.GlobalString(QLightClientTokenFlag.Name)
+		}
+		if ctx.GlobalIsSet(QLightClientServerNodeFlag.Name) {
+			ethCfg.QuorumLightClient.ServerNode = ctx.GlobalString(QLightClientServerNodeFlag.Name)
+		}
+	}
+	if ethCfg.QuorumLightClient == nil {
+		return
+	}
+	server, err := enode.Parse(enode.ValidSchemes, ethCfg.QuorumLightClient.ServerNode)
+	if err != nil {
+		Fatalf("Option %q: invalid qlight server node: %v", QLightClientServerNodeFlag.Name, err)
+	}
+	static := false
+	for _, n := range nodeCfg.P2P.StaticNodes {
+		static = static || n.ID() == server.ID()
+	}
+	if !static {
+		nodeCfg.P2P.StaticNodes = append(nodeCfg.P2P.StaticNodes, server)
+	}
+	nodeCfg.P2P.MaxPeers = 1
+	nodeCfg.P2P.NoDiscovery = true
+	nodeCfg.P2P.DiscoveryV5 = false
+}
+
+// Quorum
+//
+// SetStateSyncConfig applies the state sync flags to the config. The server of a state
+// sync client is a static node, it must be called before the node is created.
+func SetStateSyncConfig(ctx *cli.Context, ethCfg *eth.Config, nodeCfg *node.Config) {
+	if ctx.GlobalBool(StateSyncServerFlag.Name) {
+		if ethCfg.QuorumStateSyncServer == nil {
+			ethCfg.QuorumStateSyncServer = new(statesync.ServerConfig)
+		}
+		if ctx.GlobalIsSet(StateSyncServerEntitlementsFlag.Name) {
+			path := ctx.GlobalString(StateSyncServerEntitlementsFlag.Name)
+			blob, err := ioutil.ReadFile(path)
+			if err != nil {
+				Fatalf("Option %q: %v", StateSyncServerEntitlementsFlag.Name, err)
+			}
+			var entitlements map[string]*statesync.Entitlement
+			if err := json.Unmarshal(blob, &entitlements); err != nil {
+				Fatalf("Option %q: invalid entitlements file %s: %v", StateSyncServerEntitlementsFlag.Name, path, err)
+			}
+			ethCfg.QuorumStateSyncServer.Entitlements = entitlements
+		}
+	}
+	if ctx.GlobalIsSet(StateSyncServerNodeFlag.Name) {
+		ethCfg.QuorumStateSyncServerNode = ctx.GlobalString(StateSyncServerNodeFlag.Name)
+	}
+	if ethCfg.QuorumStateSyncServerNode == "" {
+		return
+	}
+	server, err := enode.Parse(enode.ValidSchemes, ethCfg.QuorumStateSyncServerNode)
+	if err != nil {
+		Fatalf("Option %q: invalid state sync server node: %v", StateSyncServerNodeFlag.Name, err)
+	}
+	for _, n := range nodeCfg.P2P.StaticNodes {
+		if n.ID() == server.ID() {
+			return
+		}
+	}
+	nodeCfg.P2P.StaticNodes = append(nodeCfg.P2P.StaticNodes, server)
+}
+
+// Quorum
+func setAdvisoryFeed(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(AdvisoryFeedFlag.Name) {
+		cfg.AdvisoryFeed = ctx.GlobalString(AdvisoryFeedFlag.Name)
+	}
+	if ctx.GlobalIsSet(AdvisoryIntervalFlag.Name) {
+		cfg.AdvisoryInterval = ctx.GlobalDuration(AdvisoryIntervalFlag.Name)
+	}
+}
+
 // Quorum
 //
 // Read plugin settings from --plugins flag. Overwrite settings defined in --config if any
@@ -1667,6 +2174,18 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(AllowedFutureBlockTimeFlag.Name) {
 		cfg.AllowedFutureBlockTime = ctx.GlobalUint64(AllowedFutureBlockTimeFlag.Name) //Quorum
 	}
+	// Quorum
+	if ctx.GlobalIsSet(MinerOrgQuotaGasShareFlag.Name) || ctx.GlobalIsSet(MinerOrgQuotaMaxTxsFlag.Name) {
+		if cfg.OrgQuotas == nil {
+			cfg.OrgQuotas = new(pcore.OrgQuotaConfig)
+		}
+		if ctx.GlobalIsSet(MinerOrgQuotaGasShareFlag.Name) {
+			cfg.OrgQuotas.GasShare = ctx.GlobalFloat64(MinerOrgQuotaGasShareFlag.Name)
+		}
+		if ctx.GlobalIsSet(MinerOrgQuotaMaxTxsFlag.Name) {
+			cfg.OrgQuotas.MaxTxs = ctx.GlobalInt(MinerOrgQuotaMaxTxsFlag.Name)
+		}
+	}
 }
 
 func setWhitelist(ctx *cli.Context, cfg *eth.Config) {
@@ -1700,21 +2219,55 @@ func setIstanbul(ctx *cli.Context, cfg *eth.Config) {
 	if ctx.GlobalIsSet(IstanbulBlockPeriodFlag.Name) {
 		cfg.Istanbul.BlockPeriod = ctx.GlobalUint64(IstanbulBlockPeriodFlag.Name)
 	}
+	if ctx.GlobalIsSet(IstanbulKMSKeyFlag.Name) {
+		cfg.IstanbulKMSKey = ctx.GlobalString(IstanbulKMSKeyFlag.Name)
+	}
+	if cfg.IstanbulKMSKey != "" {
+		timeoutCtx, cancel := context.WithTimeout(context.Background(), kms.DefaultTimeout)
+		defer cancel()
+		signer, err := kms.NewSignerFromURL(timeoutCtx, cfg.IstanbulKMSKey)
+		if err != nil {
+			Fatalf("Failed to open the Istanbul signing key: %v", err)
+		}
+		log.Info("Signing Istanbul messages with cloud KMS key", "url", signer.URL(), "address", signer.Address())
+		cfg.IstanbulSigner = signer
+	}
 }
 
 func setRaft(ctx *cli.Context, cfg *eth.Config) {
-	cfg.RaftMode = ctx.GlobalBool(RaftModeFlag.Name)
+	if ctx.GlobalIsSet(RaftModeFlag.Name) {
+		cfg.RaftMode = ctx.GlobalBool(RaftModeFlag.Name)
+	}
 }
 
+// the flags only override the options set in the TOML config when they are set
 func setQuorumConfig(ctx *cli.Context, cfg *eth.Config) error {
-	cfg.EVMCallTimeOut = time.Duration(ctx.GlobalInt(EVMCallTimeOutFlag.Name)) * time.Second
-	cfg.EnableMultitenancy = ctx.GlobalBool(MultitenancyFlag.Name)
-	cfg.SaveRevertReason = ctx.GlobalBool(RevertReasonFlag.Name)
+	if ctx.GlobalIsSet(EVMCallTimeOutFlag.Name) {
+		cfg.EVMCallTimeOut = time.Duration(ctx.GlobalInt(EVMCallTimeOutFlag.Name)) * time.Second
+	}
+	if ctx.GlobalIsSet(RevertReasonFlag.Name) {
+		cfg.SaveRevertReason = ctx.GlobalBool(RevertReasonFlag.Name)
+	}
+	if ctx.GlobalIsSet(QuorumImmutabilityThreshold.Name) {
+		cfg.QuorumImmutabilityThreshold = ctx.GlobalInt(QuorumImmutabilityThreshold.Name)
+	}
+	if ctx.GlobalIsSet(HealthMinPeersFlag.Name) {
+		cfg.Health.MinPeers = ctx.GlobalInt(HealthMinPeersFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthMaxBlockAgeFlag.Name) {
+		cfg.Health.MaxBlockAge = ctx.GlobalDuration(HealthMaxBlockAgeFlag.Name)
+	}
 	setIstanbul(ctx, cfg)
 	setRaft(ctx, cfg)
 	if ctx.GlobalIsSet(PrivateCacheTrieJournalFlag.Name) {
 		cfg.PrivateTrieCleanCacheJournal = ctx.GlobalString(PrivateCacheTrieJournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(PrivateStateColdAfterFlag.Name) {
+		cfg.PrivateStateColdAfter = ctx.GlobalDuration(PrivateStateColdAfterFlag.Name)
+	}
+	if ctx.GlobalIsSet(PrivatePayloadIndexFlag.Name) {
+		cfg.IndexPrivatePayloads = ctx.GlobalBool(PrivatePayloadIndexFlag.Name)
+	}
 	if ctx.GlobalString(CacheTrieJournalFlag.Name) == cfg.PrivateTrieCleanCacheJournal {
 		return fmt.Errorf("configuration collision with '%s' and '%s' that must be different", CacheTrieJournalFlag.Name, PrivateCacheTrieJournalFlag.Name)
 	}
@@ -1800,6 +2353,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if err != nil {
 		Fatalf("Quorum configuration has an error: %v", err)
 	}
+	cfg.EnableMultitenancy = stack.Config().EnableMultitenancy
 
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
@@ -1882,7 +2436,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	}
 
 	// set immutability threshold in config
-	params.SetQuorumImmutabilityThreshold(ctx.GlobalInt(QuorumImmutabilityThreshold.Name))
+	params.SetQuorumImmutabilityThreshold(cfg.QuorumImmutabilityThreshold)
 
 	// Override any default configs for hard coded networks.
 	switch {
@@ -2035,6 +2589,25 @@ func RegisterPluginService(stack *node.Node, cfg *node.Config, skipVerify bool,
 	log.Info("plugin service registered")
 }
 
+// Quorum
+//
+// Configure the block validation plugin to veto blocks produced by this node
+func RegisterBlockValidationPlugin(stack *node.Node, ethService *eth.Ethereum) {
+	if ethService == nil {
+		Fatalf("plugins: block validation plugin requires a full node")
+	}
+	t := new(plugin.BlockValidationPluginTemplate)
+	if err := stack.PluginManager().GetPluginTemplate(plugin.BlockValidationPluginInterfaceName, t); err != nil {
+		Fatalf("plugins: Failed to load the block validation plugin: %v", err)
+	}
+	p, err := t.Get()
+	if err != nil {
+		Fatalf("plugins: Failed to load the block validation plugin: %v", err)
+	}
+	ethService.BlockChain().SetLocalBlockValidator(blockvalidation.NewLocalBlockValidator(p))
+	log.Info("block validation plugin registered")
+}
+
 // Configure smart-contract-based permissioning service
 func RegisterPermissionService(stack *node.Node, useDns bool) {
 	permissionConfig, err := types.ParsePermissionConfig(stack.DataDir())
@@ -2049,12 +2622,129 @@ func RegisterPermissionService(stack *node.Node, useDns bool) {
 	log.Info("permission service registered")
 }
 
-func RegisterRaftService(stack *node.Node, ctx *cli.Context, nodeCfg *node.Config, ethService *eth.Ethereum) {
-	blockTimeMillis := ctx.GlobalInt(RaftBlockTimeFlag.Name)
+// Quorum
+// RaftConfig holds the options of the raft service, the raft mode itself is enabled in
+// the eth config
+type RaftConfig struct {
+	BlockTime    int    // Milliseconds between the blocks minted
+	Port         uint16 // Port of the raft transport
+	JoinExisting uint16 `toml:",omitempty"` // Raft ID of the node joining an existing network
+	DNS          bool   `toml:",omitempty"` // Resolves the host names of the peers
+
+	// Throttling of the catch-up of the joining peers
+	MaxSyncPeers  int  `toml:",omitempty"` // Chain requests served at once, 0 for no limit
+	SyncBandwidth int  `toml:",omitempty"` // Bytes per second of the chain served, 0 for no cap
+	FollowerSync  bool `toml:",omitempty"` // Syncs the chain from the followers first
+}
+
+// DefaultRaftConfig contains the default raft options
+var DefaultRaftConfig = RaftConfig{
+	BlockTime: 50,
+	Port:      50400,
+}
+
+// SetRaftConfig applies the raft command line flags to the config
+func SetRaftConfig(ctx *cli.Context, cfg *RaftConfig) {
+	if ctx.GlobalIsSet(RaftBlockTimeFlag.Name) {
+		cfg.BlockTime = ctx.GlobalInt(RaftBlockTimeFlag.Name)
+	}
+	if ctx.GlobalIsSet(RaftPortFlag.Name) {
+		cfg.Port = uint16(ctx.GlobalInt(RaftPortFlag.Name))
+	}
+	if ctx.GlobalIsSet(RaftJoinExistingFlag.Name) {
+		cfg.JoinExisting = uint16(ctx.GlobalInt(RaftJoinExistingFlag.Name))
+	}
+	if ctx.GlobalIsSet(RaftDNSEnabledFlag.Name) {
+		cfg.DNS = ctx.GlobalBool(RaftDNSEnabledFlag.Name)
+	}
+	if ctx.GlobalIsSet(RaftMaxSyncPeersFlag.Name) {
+		cfg.MaxSyncPeers = ctx.GlobalInt(RaftMaxSyncPeersFlag.Name)
+	}
+	if ctx.GlobalIsSet(RaftSyncBandwidthFlag.Name) {
+		cfg.SyncBandwidth = ctx.GlobalInt(RaftSyncBandwidthFlag.Name)
+	}
+	if ctx.GlobalIsSet(RaftFollowerSyncFlag.Name) {
+		cfg.FollowerSync = ctx.GlobalBool(RaftFollowerSyncFlag.Name)
+	}
+}
+
+// Validate checks the raft options
+func (cfg *RaftConfig) Validate() error {
+	if cfg.BlockTime <= 0 {
+		return fmt.Errorf("raft block time must be positive, got %d", cfg.BlockTime)
+	}
+	if cfg.Port == 0 {
+		return errors.New("raft port must be set")
+	}
+	if cfg.MaxSyncPeers < 0 || cfg.SyncBandwidth < 0 {
+		return errors.New("raft sync limits must not be negative")
+	}
+	return nil
+}
+
+// Quorum
+// PluginVerificationConfig holds how the integrity of the plugins is verified
+type PluginVerificationConfig struct {
+	SkipVerify  bool   `toml:",omitempty"` // Plugin integrity is not verified
+	LocalVerify bool   `toml:",omitempty"` // Plugin integrity is verified from the local file system
+	PublicKey   string `toml:",omitempty"` // URI of the PGP public key of the local verification
+}
+
+// SetPublisherConfig applies the event publishing command line flags to the config
+func SetPublisherConfig(ctx *cli.Context, cfg *publisher.Config) {
+	if ctx.GlobalIsSet(PublisherURLFlag.Name) {
+		cfg.URL = ctx.GlobalString(PublisherURLFlag.Name)
+	}
+	if ctx.GlobalIsSet(PublisherTopicFlag.Name) {
+		cfg.Topic = ctx.GlobalString(PublisherTopicFlag.Name)
+	}
+}
+
+// SetRelayConfig applies the private transaction relay command line flags to the config
+func SetRelayConfig(ctx *cli.Context, cfg *relay.Config) {
+	if !ctx.GlobalIsSet(RelayConfigFlag.Name) {
+		return
+	}
+	path := ctx.GlobalString(RelayConfigFlag.Name)
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		Fatalf("Option %q: %v", RelayConfigFlag.Name, err)
+	}
+	if err := json.Unmarshal(blob, cfg); err != nil {
+		Fatalf("Option %q: invalid relay configuration file %s: %v", RelayConfigFlag.Name, path, err)
+	}
+}
+
+// SetPluginVerificationConfig applies the plugin verification command line flags to the config
+func SetPluginVerificationConfig(ctx *cli.Context, cfg *PluginVerificationConfig) {
+	if ctx.GlobalIsSet(PluginSkipVerifyFlag.Name) {
+		cfg.SkipVerify = ctx.GlobalBool(PluginSkipVerifyFlag.Name)
+	}
+	if ctx.GlobalIsSet(PluginLocalVerifyFlag.Name) {
+		cfg.LocalVerify = ctx.GlobalBool(PluginLocalVerifyFlag.Name)
+	}
+	if ctx.GlobalIsSet(PluginPublicKeyFlag.Name) {
+		cfg.PublicKey = ctx.GlobalString(PluginPublicKeyFlag.Name)
+	}
+}
+
+// Validate checks the plugin verification options
+func (cfg *PluginVerificationConfig) Validate() error {
+	if cfg.SkipVerify && cfg.LocalVerify {
+		return fmt.Errorf("only --%s or --%s must be set", PluginSkipVerifyFlag.Name, PluginLocalVerifyFlag.Name)
+	}
+	if !cfg.LocalVerify && cfg.PublicKey != "" {
+		return fmt.Errorf("--%s is required for setting --%s", PluginLocalVerifyFlag.Name, PluginPublicKeyFlag.Name)
+	}
+	return nil
+}
+
+func RegisterRaftService(stack *node.Node, raftCfg *RaftConfig, nodeCfg *node.Config, ethService *eth.Ethereum) {
+	blockTimeMillis := raftCfg.BlockTime
 	raftLogDir := nodeCfg.RaftLogDir // default value is set either 'datadir' or 'raftlogdir'
-	joinExistingId := ctx.GlobalInt(RaftJoinExistingFlag.Name)
-	useDns := ctx.GlobalBool(RaftDNSEnabledFlag.Name)
-	raftPort := uint16(ctx.GlobalInt(RaftPortFlag.Name))
+	joinExistingId := int(raftCfg.JoinExisting)
+	useDns := raftCfg.DNS
+	raftPort := raftCfg.Port
 
 	privkey := nodeCfg.NodeKey()
 	strId := enode.PubkeyToIDV4(&privkey.PublicKey).String()
@@ -2089,10 +2779,12 @@ func RegisterRaftService(stack *node.Node, ctx *cli.Context, nodeCfg *node.Confi
 		}
 	}
 
-	_, err := raft.New(stack, ethService.BlockChain().Config(), myId, raftPort, joinExisting, blockTimeNanos, ethService, peers, raftLogDir, useDns)
+	raftService, err := raft.New(stack, ethService.BlockChain().Config(), myId, raftPort, joinExisting, blockTimeNanos, ethService, peers, raftLogDir, useDns)
 	if err != nil {
 		Fatalf("raft: Failed to register the Raft service: %v", err)
 	}
+	raftService.SetCatchUpConfig(&raft.CatchUpConfig{FollowerSync: raftCfg.FollowerSync})
+	ethService.SetServeThrottle(raftCfg.MaxSyncPeers, raftCfg.SyncBandwidth)
 
 	log.Info("raft service registered")
 }
@@ -2106,6 +2798,27 @@ func RegisterExtensionService(stack *node.Node, ethService *eth.Ethereum) {
 	log.Info("extension service registered")
 }
 
+// RegisterPublisherService publishes the chain data of ethService to a message broker
+func RegisterPublisherService(stack *node.Node, ethService *eth.Ethereum, cfg *publisher.Config) {
+	service, err := publisher.New(cfg, ethService.BlockChain(), ethService.ChainDb())
+	if err != nil {
+		Fatalf("Failed to register the publisher service: %v", err)
+	}
+	stack.RegisterLifecycle(service)
+	log.Info("publisher service registered", "url", cfg.URL)
+}
+
+// RegisterRelayService relays the selected private transactions of ethService to
+// another network
+func RegisterRelayService(stack *node.Node, ethService *eth.Ethereum, cfg *relay.Config) {
+	service, err := relay.New(cfg, ethService.BlockChain(), private.P, ethService.ChainDb())
+	if err != nil {
+		Fatalf("Failed to register the relay service: %v", err)
+	}
+	stack.RegisterLifecycle(service)
+	log.Info("relay service registered", "url", cfg.URL)
+}
+
 func SetupMetrics(ctx *cli.Context) {
 	if metrics.Enabled {
 		log.Info("Enabling metrics collection")
@@ -2134,6 +2847,17 @@ func SetupMetrics(ctx *cli.Context) {
 	}
 }
 
+// Quorum
+// SetupTracing enables the export of the transaction and block processing spans
+// if an OTLP collector endpoint is configured.
+func SetupTracing(ctx *cli.Context) {
+	if endpoint := ctx.GlobalString(TracingOTLPEndpointFlag.Name); endpoint != "" {
+		serviceName := ctx.GlobalString(TracingServiceNameFlag.Name)
+		log.Info("Enabling tracing export to OTLP collector", "endpoint", endpoint, "service", serviceName)
+		tracing.Setup(endpoint, serviceName)
+	}
+}
+
 func SplitTagsFlag(tagsFlag string) map[string]string {
 	tags := strings.Split(tagsFlag, ",")
 	tagsMap := map[string]string{}
@@ -2222,9 +2946,12 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readOnly bool, useExist bool)
 		}
 		istanbulConfig.ProposerPolicy = istanbul.ProposerPolicy(config.Istanbul.ProposerPolicy)
 		istanbulConfig.Ceil2Nby3Block = config.Istanbul.Ceil2Nby3Block
+		istanbulConfig.KeyRotationBlock = config.Istanbul.KeyRotationBlock
 		engine = istanbulBackend.New(istanbulConfig, stack.GetNodeKey(), chainDb)
-	} else if config.IsQuorum {
+	} else if config.IsNoPoW() && !ctx.GlobalBool(FakePoWFlag.Name) {
 		// for Raft
+		engine = ethash.NewNoPoW()
+	} else if config.IsQuorum {
 		engine = ethash.NewFullFaker()
 	} else {
 		engine = ethash.NewFaker()
@@ -2252,6 +2979,12 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readOnly bool, useExist bool)
 		TrieTimeLimit:       eth.DefaultConfig.TrieTimeout,
 		SnapshotLimit:       eth.DefaultConfig.SnapshotCache,
 	}
+	// Quorum: restore the private contracts moved to the cold store
+	coldDb, err := stack.OpenDatabase("coldstate", 16, 16, "")
+	if err != nil {
+		Fatalf("Could not open the cold store: %v", err)
+	}
+	cache.PrivateColdStore = coldDb
 	if !ctx.GlobalIsSet(SnapshotFlag.Name) {
 		cache.SnapshotLimit = 0 // Disabled
 	}
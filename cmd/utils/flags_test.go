@@ -175,3 +175,25 @@ func Test_SplitTagsFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRaftConfig_whenFlagsOverrideConfigFile(t *testing.T) {
+	fs := &flag.FlagSet{}
+	fs.Int(RaftPortFlag.Name, 0, "")
+	arbitraryCLIContext := cli.NewContext(nil, fs, nil)
+	assert.NoError(t, arbitraryCLIContext.GlobalSet(RaftPortFlag.Name, "50401"))
+	cfg := RaftConfig{BlockTime: 100, Port: 50400}
+
+	SetRaftConfig(arbitraryCLIContext, &cfg)
+
+	assert.Equal(t, RaftConfig{BlockTime: 100, Port: 50401}, cfg)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.BlockTime = 0
+	assert.Error(t, cfg.Validate())
+}
+
+func TestPluginVerificationConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&PluginVerificationConfig{LocalVerify: true, PublicKey: "file:///tmp/key"}).Validate())
+	assert.Error(t, (&PluginVerificationConfig{SkipVerify: true, LocalVerify: true}).Validate())
+	assert.Error(t, (&PluginVerificationConfig{PublicKey: "file:///tmp/key"}).Validate())
+}
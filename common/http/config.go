@@ -97,15 +97,24 @@ func LoadConfigFile(path string) (Config, error) {
 	}
 	cfg.TlsMode = strings.ToLower(cfg.TlsMode)
 
+	cfg.SetConnectionType()
+	if cfg.ConnectionType == NoConnection {
+		return Config{}, fmt.Errorf("either Socket or HTTP connection must be specified in config file")
+	}
+
+	return cfg, nil
+}
+
+// SetConnectionType derives the connection type from the socket or the URL of the
+// config, e.g. once it has been decoded from the geth TOML configuration file
+func (cfg *Config) SetConnectionType() {
 	if cfg.Socket != "" {
 		cfg.ConnectionType = UnixDomainSocketConnection
 	} else if cfg.HttpUrl != "" {
 		cfg.ConnectionType = HttpConnection
 	} else {
-		return Config{}, fmt.Errorf("either Socket or HTTP connection must be specified in config file")
+		cfg.ConnectionType = NoConnection
 	}
-
-	return cfg, nil
 }
 
 func (cfg *Config) Validate() error {
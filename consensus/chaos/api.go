@@ -0,0 +1,137 @@
+package chaos
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// API is the chaos namespace of the RPC API, controlling the faults injected
+// by the node.
+type API struct {
+	injector *Injector
+}
+
+// NewAPI creates the chaos API of the injector.
+func NewAPI(injector *Injector) *API {
+	return &API{injector}
+}
+
+func newAPIs(injector *Injector) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "chaos",
+			Version:   "1.0",
+			Service:   NewAPI(injector),
+			Public:    false,
+		},
+	}
+}
+
+// SetSeed restarts the random sequence of the injected faults from the seed.
+func (api *API) SetSeed(seed int64) bool {
+	api.injector.SetSeed(seed)
+	return true
+}
+
+// SetRule drops the consensus messages sent to the peer with the probability
+// drop, and delays the others by delay, e.g. "500ms". An empty peer sets the
+// rule of all the peers without one of their own.
+func (api *API) SetRule(peer string, drop float64, delay string) (bool, error) {
+	id, err := parsePeer(peer)
+	if err != nil {
+		return false, err
+	}
+	var d time.Duration
+	if delay != "" {
+		if d, err = time.ParseDuration(delay); err != nil {
+			return false, err
+		}
+	}
+	if err := api.injector.SetRule(id, drop, d); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveRule removes the rule of the peer, or the default rule if peer is empty.
+func (api *API) RemoveRule(peer string) (bool, error) {
+	id, err := parsePeer(peer)
+	if err != nil {
+		return false, err
+	}
+	api.injector.RemoveRule(id)
+	return true, nil
+}
+
+// Partition drops all the consensus messages exchanged with the peers.
+func (api *API) Partition(peers []string) (bool, error) {
+	ids := make([]enode.ID, 0, len(peers))
+	for _, peer := range peers {
+		id, err := parsePeer(peer)
+		if err != nil {
+			return false, err
+		}
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	api.injector.Partition(ids)
+	return true, nil
+}
+
+// Heal reconnects the node to all the partitioned peers.
+func (api *API) Heal() bool {
+	api.injector.Heal()
+	return true
+}
+
+// CrashBlockMaker stops making blocks, until RestartBlockMaker is called.
+func (api *API) CrashBlockMaker() (bool, error) {
+	if err := api.injector.CrashBlockMaker(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RestartBlockMaker restarts making blocks after CrashBlockMaker.
+func (api *API) RestartBlockMaker() (bool, error) {
+	if err := api.injector.RestartBlockMaker(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Reset removes all the rules and heals the partitions.
+func (api *API) Reset() bool {
+	api.injector.Reset()
+	return true
+}
+
+// Status returns the faults currently injected.
+func (api *API) Status() *Status {
+	return api.injector.Status()
+}
+
+// parsePeer accepts an enode URL, a node ID or an empty string, meaning all
+// the peers.
+func parsePeer(peer string) (*enode.ID, error) {
+	if peer == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(peer, "enode://") {
+		node, err := enode.ParseV4(peer)
+		if err != nil {
+			return nil, err
+		}
+		id := node.ID()
+		return &id, nil
+	}
+	id, err := enode.ParseID(peer)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,26 @@
+// +build !chaos
+
+package chaos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Enabled reports whether the binary is built with the chaos build tag.
+const Enabled = false
+
+// Intercept never drops nor delays the consensus messages without the chaos build tag.
+func Intercept(peer enode.ID, dir Direction) (drop bool, delay time.Duration) {
+	return false, 0
+}
+
+// RegisterBlockMaker is a no-op without the chaos build tag.
+func RegisterBlockMaker(bm BlockMaker) {}
+
+// APIs returns no API without the chaos build tag.
+func APIs() []rpc.API {
+	return nil
+}
@@ -0,0 +1,33 @@
+// +build chaos
+
+package chaos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Enabled reports whether the binary is built with the chaos build tag.
+const Enabled = true
+
+// injector is the fault injector of the node, starting from a fixed seed so
+// runs are reproducible unless chaos_setSeed is called.
+var injector = NewInjector(1)
+
+// Intercept returns whether a consensus message exchanged with the peer must be
+// dropped, or else how long its sending must be delayed.
+func Intercept(peer enode.ID, dir Direction) (drop bool, delay time.Duration) {
+	return injector.Intercept(peer, dir)
+}
+
+// RegisterBlockMaker sets the block maker crashed and restarted by the chaos API.
+func RegisterBlockMaker(bm BlockMaker) {
+	injector.RegisterBlockMaker(bm)
+}
+
+// APIs returns the chaos RPC API.
+func APIs() []rpc.API {
+	return newAPIs(injector)
+}
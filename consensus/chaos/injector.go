@@ -0,0 +1,251 @@
+// Package chaos injects faults in the consensus of a node: consensus messages
+// are delayed or dropped, peers are partitioned and the block maker is crashed
+// and restarted on demand, so the liveness and safety of raft and istanbul
+// networks can be tested reproducibly.
+//
+// The faults are only injected by the binaries built with the chaos build tag,
+// e.g. go build -tags chaos ./cmd/geth. The hooks are no-ops in other builds.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// Direction of a consensus message, relative to this node.
+type Direction int
+
+const (
+	Outbound Direction = iota
+	Inbound
+)
+
+var (
+	ErrNoBlockMaker = errors.New("chaos: no block maker registered")
+	ErrInvalidRule  = errors.New("chaos: drop probability must be between 0 and 1")
+)
+
+// BlockMaker is implemented by the consensus engines which can stop and
+// restart making blocks.
+type BlockMaker interface {
+	StopBlockMaker() error
+	StartBlockMaker() error
+}
+
+// BlockMakerFuncs adapts a pair of functions to the BlockMaker interface.
+type BlockMakerFuncs struct {
+	Stop  func() error
+	Start func() error
+}
+
+func (f BlockMakerFuncs) StopBlockMaker() error  { return f.Stop() }
+func (f BlockMakerFuncs) StartBlockMaker() error { return f.Start() }
+
+// rule is the fault applied to the messages sent to a peer.
+type rule struct {
+	drop  float64
+	delay time.Duration
+}
+
+// Injector decides the fate of the consensus messages. Its decisions only
+// depend on the seed and on the sequence of messages, so a test run can be
+// replayed by setting the same seed.
+type Injector struct {
+	mu          sync.Mutex
+	seed        int64
+	rand        *rand.Rand
+	rules       map[enode.ID]rule // rules of the individual peers
+	defaultRule *rule             // rule of the peers without one of their own
+	partitioned map[enode.ID]struct{}
+	blockMaker  BlockMaker
+	crashed     bool
+}
+
+// NewInjector creates an injector which doesn't inject any fault.
+func NewInjector(seed int64) *Injector {
+	return &Injector{
+		seed:        seed,
+		rand:        rand.New(rand.NewSource(seed)),
+		rules:       make(map[enode.ID]rule),
+		partitioned: make(map[enode.ID]struct{}),
+	}
+}
+
+// Intercept returns whether a consensus message exchanged with the peer must be
+// dropped, or else how long its sending must be delayed. Partitioned peers lose
+// the messages in both directions, the rules only apply to outbound messages.
+func (inj *Injector) Intercept(peer enode.ID, dir Direction) (drop bool, delay time.Duration) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	if _, ok := inj.partitioned[peer]; ok {
+		return true, 0
+	}
+	if dir == Inbound {
+		return false, 0
+	}
+	r, ok := inj.rules[peer]
+	if !ok {
+		if inj.defaultRule == nil {
+			return false, 0
+		}
+		r = *inj.defaultRule
+	}
+	if r.drop > 0 && inj.rand.Float64() < r.drop {
+		return true, 0
+	}
+	return false, r.delay
+}
+
+// SetSeed restarts the random sequence of the injector from the seed.
+func (inj *Injector) SetSeed(seed int64) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.seed = seed
+	inj.rand = rand.New(rand.NewSource(seed))
+}
+
+// SetRule drops the outbound messages to the peer with the given probability
+// and delays the others. A nil peer sets the rule of all the peers without one
+// of their own.
+func (inj *Injector) SetRule(peer *enode.ID, drop float64, delay time.Duration) error {
+	if drop < 0 || drop > 1 {
+		return ErrInvalidRule
+	}
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	r := rule{drop: drop, delay: delay}
+	if peer == nil {
+		inj.defaultRule = &r
+	} else {
+		inj.rules[*peer] = r
+	}
+	log.Warn("Chaos rule set", "peer", peer, "drop", drop, "delay", delay)
+	return nil
+}
+
+// RemoveRule removes the rule of the peer, or the default rule if peer is nil.
+func (inj *Injector) RemoveRule(peer *enode.ID) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	if peer == nil {
+		inj.defaultRule = nil
+	} else {
+		delete(inj.rules, *peer)
+	}
+}
+
+// Partition cuts this node off from the peers.
+func (inj *Injector) Partition(peers []enode.ID) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	for _, id := range peers {
+		inj.partitioned[id] = struct{}{}
+	}
+	log.Warn("Chaos partition", "peers", len(peers), "partitioned", len(inj.partitioned))
+}
+
+// Heal reconnects this node to all the partitioned peers.
+func (inj *Injector) Heal() {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.partitioned = make(map[enode.ID]struct{})
+}
+
+// Reset removes all the rules and heals the partitions. The random sequence
+// restarts from the current seed.
+func (inj *Injector) Reset() {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.rand = rand.New(rand.NewSource(inj.seed))
+	inj.rules = make(map[enode.ID]rule)
+	inj.defaultRule = nil
+	inj.partitioned = make(map[enode.ID]struct{})
+}
+
+// RegisterBlockMaker sets the block maker crashed and restarted by the injector.
+func (inj *Injector) RegisterBlockMaker(bm BlockMaker) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.blockMaker = bm
+}
+
+// CrashBlockMaker stops the block maker, until RestartBlockMaker is called.
+func (inj *Injector) CrashBlockMaker() error {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	if inj.blockMaker == nil {
+		return ErrNoBlockMaker
+	}
+	if err := inj.blockMaker.StopBlockMaker(); err != nil {
+		return err
+	}
+	inj.crashed = true
+	log.Warn("Chaos crashed the block maker")
+	return nil
+}
+
+// RestartBlockMaker restarts the block maker stopped by CrashBlockMaker.
+func (inj *Injector) RestartBlockMaker() error {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	if inj.blockMaker == nil {
+		return ErrNoBlockMaker
+	}
+	if err := inj.blockMaker.StartBlockMaker(); err != nil {
+		return err
+	}
+	inj.crashed = false
+	log.Warn("Chaos restarted the block maker")
+	return nil
+}
+
+// Status describes the faults currently injected.
+type Status struct {
+	Seed        int64             `json:"seed"`
+	Rules       map[string]string `json:"rules"`
+	Partitioned []string          `json:"partitioned"`
+	Crashed     bool              `json:"crashed"`
+}
+
+// Status returns the faults currently injected.
+func (inj *Injector) Status() *Status {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	status := &Status{
+		Seed:        inj.seed,
+		Rules:       make(map[string]string),
+		Partitioned: make([]string, 0, len(inj.partitioned)),
+		Crashed:     inj.crashed,
+	}
+	for id, r := range inj.rules {
+		status.Rules[id.String()] = r.String()
+	}
+	if inj.defaultRule != nil {
+		status.Rules["*"] = inj.defaultRule.String()
+	}
+	for id := range inj.partitioned {
+		status.Partitioned = append(status.Partitioned, id.String())
+	}
+	return status
+}
+
+func (r rule) String() string {
+	return fmt.Sprintf("drop=%g delay=%v", r.drop, r.delay)
+}
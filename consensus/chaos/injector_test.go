@@ -0,0 +1,120 @@
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	peerA = enode.ID{1}
+	peerB = enode.ID{2}
+)
+
+func interceptN(inj *Injector, peer enode.ID, n int) []bool {
+	drops := make([]bool, n)
+	for i := range drops {
+		drops[i], _ = inj.Intercept(peer, Outbound)
+	}
+	return drops
+}
+
+func TestInjector_whenNoFault(t *testing.T) {
+	inj := NewInjector(1)
+
+	drop, delay := inj.Intercept(peerA, Outbound)
+
+	assert.False(t, drop)
+	assert.Zero(t, delay)
+}
+
+func TestInjector_Intercept_isDeterministic(t *testing.T) {
+	first, second := NewInjector(42), NewInjector(42)
+	assert.NoError(t, first.SetRule(nil, 0.5, 0))
+	assert.NoError(t, second.SetRule(nil, 0.5, 0))
+
+	drops := interceptN(first, peerA, 100)
+
+	assert.Equal(t, drops, interceptN(second, peerA, 100))
+	assert.Contains(t, drops, true)
+	assert.Contains(t, drops, false)
+
+	first.Reset()
+	assert.NoError(t, first.SetRule(nil, 0.5, 0))
+	assert.Equal(t, drops, interceptN(first, peerA, 100), "reset must replay the sequence")
+}
+
+func TestInjector_Intercept_peerRuleOverridesDefault(t *testing.T) {
+	inj := NewInjector(1)
+	assert.NoError(t, inj.SetRule(nil, 1, 0))
+	assert.NoError(t, inj.SetRule(&peerB, 0, time.Second))
+
+	dropA, _ := inj.Intercept(peerA, Outbound)
+	dropB, delayB := inj.Intercept(peerB, Outbound)
+	dropInbound, _ := inj.Intercept(peerA, Inbound)
+
+	assert.True(t, dropA)
+	assert.False(t, dropB)
+	assert.Equal(t, time.Second, delayB)
+	assert.False(t, dropInbound, "rules only apply to outbound messages")
+}
+
+func TestInjector_SetRule_whenInvalidProbability(t *testing.T) {
+	inj := NewInjector(1)
+
+	assert.Equal(t, ErrInvalidRule, inj.SetRule(&peerA, 1.5, 0))
+}
+
+func TestInjector_Partition(t *testing.T) {
+	inj := NewInjector(1)
+
+	inj.Partition([]enode.ID{peerA})
+
+	for _, dir := range []Direction{Outbound, Inbound} {
+		drop, _ := inj.Intercept(peerA, dir)
+		assert.True(t, drop)
+		drop, _ = inj.Intercept(peerB, dir)
+		assert.False(t, drop)
+	}
+	assert.Equal(t, []string{peerA.String()}, inj.Status().Partitioned)
+
+	inj.Heal()
+
+	drop, _ := inj.Intercept(peerA, Inbound)
+	assert.False(t, drop)
+}
+
+func TestInjector_CrashBlockMaker(t *testing.T) {
+	inj := NewInjector(1)
+	assert.Equal(t, ErrNoBlockMaker, inj.CrashBlockMaker())
+
+	running := true
+	inj.RegisterBlockMaker(BlockMakerFuncs{
+		Stop:  func() error { running = false; return nil },
+		Start: func() error { running = true; return nil },
+	})
+
+	assert.NoError(t, inj.CrashBlockMaker())
+	assert.False(t, running)
+	assert.True(t, inj.Status().Crashed)
+
+	assert.NoError(t, inj.RestartBlockMaker())
+	assert.True(t, running)
+	assert.False(t, inj.Status().Crashed)
+}
+
+func TestAPI_SetRule_acceptsEnodeURL(t *testing.T) {
+	api := NewAPI(NewInjector(1))
+	url := "enode://1dd9d65c4552b5eb43d5ad55a2ee3f56c6cbc1c64a5c8d659f51fcd51bace24351232b8d7821617d2b29b54b81cdefb9b3e9c37d7fd5f63270bcc9e1a6f6a439@127.0.0.1:30303"
+
+	ok, err := api.SetRule(url, 0, "250ms")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, api.Status().Rules, 1)
+
+	_, err = api.SetRule("not a peer", 0, "")
+	assert.Error(t, err)
+}
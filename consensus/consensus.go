@@ -18,12 +18,15 @@
 package consensus
 
 import (
+	"context"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -156,3 +159,62 @@ type Istanbul interface {
 	// Stop stops the engine
 	Stop() error
 }
+
+// Quorum
+//
+// NodeRoleEvent is posted when the consensus role of the local node changes,
+// e.g.: when it becomes raft minter or Istanbul proposer
+type NodeRoleEvent struct {
+	Consensus string `json:"consensus"` // raft or istanbul
+	Role      string `json:"role"`
+}
+
+// PeerInfoProvider is implemented by consensus engines reporting consensus data about peers in admin_peers
+type PeerInfoProvider interface {
+	// PeerInfo returns the consensus data about the peer, nil if there is none
+	PeerInfo(chain ChainHeaderReader, node *enode.Node) interface{}
+}
+
+// NodeRoleNotifier is implemented by consensus protocols reporting the role of the local node
+type NodeRoleNotifier interface {
+	// NodeRole returns the current role of the local node
+	NodeRole() NodeRoleEvent
+	// SubscribeNodeRoleEvent registers a subscription of NodeRoleEvent
+	SubscribeNodeRoleEvent(ch chan<- NodeRoleEvent) event.Subscription
+}
+
+// PublicNodeRoleAPI provides the quorum_subscribe("nodeRole") subscription
+type PublicNodeRoleAPI struct {
+	notifier NodeRoleNotifier
+}
+
+func NewPublicNodeRoleAPI(notifier NodeRoleNotifier) *PublicNodeRoleAPI {
+	return &PublicNodeRoleAPI{notifier: notifier}
+}
+
+// NodeRole sends the current role of the local node then a notification each time it changes
+func (api *PublicNodeRoleAPI) NodeRole(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		roles := make(chan NodeRoleEvent, 10)
+		sub := api.notifier.SubscribeNodeRoleEvent(roles)
+		defer sub.Unsubscribe()
+
+		_ = notifier.Notify(rpcSub.ID, api.notifier.NodeRole())
+		for {
+			select {
+			case role := <-roles:
+				_ = notifier.Notify(rpcSub.ID, role)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -397,6 +397,10 @@ const (
 	ModeTest
 	ModeFake
 	ModeFullFake
+	// Quorum
+	// ModeNone accepts all the blocks as ModeFullFake does, and disables the proof-of-work
+	// entirely: no cache nor dataset is ever generated and no mining API is served
+	ModeNone
 )
 
 // Config are the configuration parameters of the ethash.
@@ -529,6 +533,24 @@ func NewFullFaker() *Ethash {
 	}
 }
 
+// Quorum
+// NewNoPoW creates an ethash consensus engine for the chains without proof-of-work,
+// accepting all the blocks as NewFullFaker does without any cache, dataset, or
+// mining API
+func NewNoPoW() *Ethash {
+	return &Ethash{
+		config: Config{
+			PowMode: ModeNone,
+			Log:     log.Root(),
+		},
+	}
+}
+
+// fullFake returns whether the engine accepts any input as valid
+func (ethash *Ethash) fullFake() bool {
+	return ethash.config.PowMode == ModeFullFake || ethash.config.PowMode == ModeNone
+}
+
 // NewShared creates a full sized ethash PoW shared between all requesters running
 // in the same process.
 func NewShared() *Ethash {
@@ -660,6 +682,10 @@ func (ethash *Ethash) Hashrate() float64 {
 
 // APIs implements consensus.Engine, returning the user facing RPC APIs.
 func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
+	// Quorum: there is no work to serve without proof-of-work
+	if ethash.config.PowMode == ModeNone {
+		return nil
+	}
 	// In order to ensure backward compatibility, we exposes ethash RPC APIs
 	// to both eth and ethash namespaces.
 	return []rpc.API{
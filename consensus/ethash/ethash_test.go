@@ -54,6 +54,22 @@ func TestTestMode(t *testing.T) {
 	}
 }
 
+// Quorum
+// Tests that the engine without proof-of-work accepts any seal and serves no API.
+func TestNoPoWMode(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100), Nonce: types.EncodeNonce(42)}
+
+	ethash := NewNoPoW()
+	defer ethash.Close()
+
+	if err := ethash.VerifySeal(nil, header); err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+	if apis := ethash.APIs(nil); len(apis) != 0 {
+		t.Errorf("APIs served without proof-of-work: %v", apis)
+	}
+}
+
 // This test checks that cache lru logic doesn't crash under load.
 // It reproduces https://github.com/ethereum/go-ethereum/issues/14943
 func TestCacheFileEvict(t *testing.T) {
@@ -24,6 +24,14 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 )
 
+// Quorum
+// Roles of the local node reported by Backend.NodeRoleChanged
+const (
+	RoleProposer     = "proposer"
+	RoleValidator    = "validator"
+	RoleNonValidator = "non-validator"
+)
+
 // Backend provides application specific functions for Istanbul core
 type Backend interface {
 	// Address returns the owner's address
@@ -71,5 +79,20 @@ type Backend interface {
 	// HasBadBlock returns whether the block with the hash is a bad block
 	HasBadProposal(hash common.Hash) bool
 
+	// Quorum
+	// NodeRoleChanged notifies the role of the local node (proposer, validator or non-validator) for the new round
+	NodeRoleChanged(role string)
+
 	Close() error
 }
+
+// Quorum
+// Signer signs the messages and the seals of the local node instead of its node key,
+// e.g.: with a key held by a cloud key management service
+type Signer interface {
+	// Address returns the address of the signing key, the validator address of the node
+	Address() common.Address
+
+	// Sign signs hash, returning the signature in the [R || S || V] format where V is 0 or 1
+	Sign(hash []byte) ([]byte, error)
+}
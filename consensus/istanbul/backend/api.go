@@ -21,6 +21,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -270,3 +271,40 @@ func (api *API) IsValidator(blockNum *rpc.BlockNumber) (bool, error) {
 	}
 	return false, nil
 }
+
+// Quorum
+// PublicBlockMakerAPI provides quorum_blockMakerStatus, to diagnose the networks no
+// longer making blocks
+type PublicBlockMakerAPI struct {
+	istanbul *backend
+}
+
+// BlockMakerStatus returns the validator expected to propose the next block, the
+// window of the current round and the turns missed by the validators
+func (api *PublicBlockMakerAPI) BlockMakerStatus() (*istanbul.BlockMakerStatus, error) {
+	return api.istanbul.BlockMakerStatus()
+}
+
+// Quorum
+// PrivateKeyRotationAPI provides the rotation of the validator key of the node
+type PrivateKeyRotationAPI struct {
+	chain    consensus.ChainHeaderReader
+	istanbul *backend
+}
+
+// RegisterKeyRotation registers the new validator key in keyfile, in the node key format.
+// From the target block on, the validator hands over its seat to the new key in the
+// blocks it proposes, then signs with the new key once the handover is in the chain.
+func (api *PrivateKeyRotationAPI) RegisterKeyRotation(keyfile string, block uint64) (*consensus.KeyRotation, error) {
+	return api.istanbul.registerKeyRotation(api.chain, keyfile, block)
+}
+
+// CancelKeyRotation cancels the key rotation, until the new key is in the validators
+func (api *PrivateKeyRotationAPI) CancelKeyRotation() error {
+	return api.istanbul.cancelKeyRotation()
+}
+
+// KeyRotation returns the key rotation of the node, nil if there is none
+func (api *PrivateKeyRotationAPI) KeyRotation() *consensus.KeyRotation {
+	return api.istanbul.keyRotation()
+}
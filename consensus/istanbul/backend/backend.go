@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/trie"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -44,6 +45,17 @@ const (
 
 // New creates an Ethereum backend for Istanbul core engine.
 func New(config *istanbul.Config, privateKey *ecdsa.PrivateKey, db ethdb.Database) consensus.Istanbul {
+	return newBackend(config, privateKey, nil, crypto.PubkeyToAddress(privateKey.PublicKey), db)
+}
+
+// Quorum
+// NewWithSigner creates an Ethereum backend for Istanbul core engine signing with signer
+// instead of the node key, the validator address of the node being the address of signer.
+func NewWithSigner(config *istanbul.Config, signer istanbul.Signer, db ethdb.Database) consensus.Istanbul {
+	return newBackend(config, nil, signer, signer.Address(), db)
+}
+
+func newBackend(config *istanbul.Config, privateKey *ecdsa.PrivateKey, signer istanbul.Signer, address common.Address, db ethdb.Database) *backend {
 	// Allocate the snapshot caches and create the engine
 	recents, _ := lru.NewARC(inmemorySnapshots)
 	recentMessages, _ := lru.NewARC(inmemoryPeers)
@@ -52,7 +64,8 @@ func New(config *istanbul.Config, privateKey *ecdsa.PrivateKey, db ethdb.Databas
 		config:           config,
 		istanbulEventMux: new(event.TypeMux),
 		privateKey:       privateKey,
-		address:          crypto.PubkeyToAddress(privateKey.PublicKey),
+		signer:           signer,
+		address:          address,
 		logger:           log.New(),
 		db:               db,
 		commitCh:         make(chan *types.Block, 1),
@@ -62,6 +75,7 @@ func New(config *istanbul.Config, privateKey *ecdsa.PrivateKey, db ethdb.Databas
 		recentMessages:   recentMessages,
 		knownMessages:    knownMessages,
 	}
+	backend.loadKeyRotation()
 	backend.core = istanbulCore.New(backend, backend.config)
 	return backend
 }
@@ -72,7 +86,11 @@ type backend struct {
 	config           *istanbul.Config
 	istanbulEventMux *event.TypeMux
 	privateKey       *ecdsa.PrivateKey
+	signer           istanbul.Signer // Quorum: signs instead of privateKey if set
 	address          common.Address
+	keyMu            sync.RWMutex           // Quorum: protects the signing key, rotated by the key rotation
+	rotation         *consensus.KeyRotation // Quorum: rotation of the validator key, nil if there is none
+	maintenance      int32                  // Quorum: 1 while the proposals are skipped for maintenance
 	core             istanbulCore.Engine
 	logger           log.Logger
 	db               ethdb.Database
@@ -99,6 +117,69 @@ type backend struct {
 
 	recentMessages *lru.ARCCache // the cache of peer's messages
 	knownMessages  *lru.ARCCache // the cache of self messages
+
+	// Quorum
+	roleFeed event.Feed
+	role     string
+	roleMu   sync.RWMutex
+}
+
+// NodeRoleChanged implements istanbul.Backend.NodeRoleChanged
+func (sb *backend) NodeRoleChanged(role string) {
+	sb.roleMu.Lock()
+	changed := role != sb.role
+	sb.role = role
+	sb.roleMu.Unlock()
+
+	if changed {
+		sb.roleFeed.Send(consensus.NodeRoleEvent{Consensus: "istanbul", Role: role})
+	}
+}
+
+// NodeRole implements consensus.NodeRoleNotifier.NodeRole
+func (sb *backend) NodeRole() consensus.NodeRoleEvent {
+	sb.roleMu.RLock()
+	defer sb.roleMu.RUnlock()
+	return consensus.NodeRoleEvent{Consensus: "istanbul", Role: sb.role}
+}
+
+// BlockMakerStatus returns the state of the block production of the current round,
+// ErrStoppedEngine unless the node is validating
+func (sb *backend) BlockMakerStatus() (*istanbul.BlockMakerStatus, error) {
+	sb.coreMu.RLock()
+	defer sb.coreMu.RUnlock()
+	if !sb.coreStarted {
+		return nil, istanbul.ErrStoppedEngine
+	}
+	return sb.core.BlockMakerStatus(), nil
+}
+
+// PeerInfo implements consensus.PeerInfoProvider.PeerInfo, reporting whether the peer
+// is a validator at the current block
+func (sb *backend) PeerInfo(chain consensus.ChainHeaderReader, node *enode.Node) interface{} {
+	header := chain.CurrentHeader()
+	if header == nil || node.Pubkey() == nil {
+		return nil
+	}
+	snap, err := sb.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		log.Debug("Unable to retrieve the validators", "number", header.Number, "err", err)
+		return nil
+	}
+	address := crypto.PubkeyToAddress(*node.Pubkey())
+	_, v := snap.ValSet.GetByAddress(address)
+	return &PeerInfo{Address: address, Validator: v != nil}
+}
+
+// PeerInfo is the Istanbul data about a peer shown in admin_peers
+type PeerInfo struct {
+	Address   common.Address `json:"address"`
+	Validator bool           `json:"validator"`
+}
+
+// SubscribeNodeRoleEvent implements consensus.NodeRoleNotifier.SubscribeNodeRoleEvent
+func (sb *backend) SubscribeNodeRoleEvent(ch chan<- consensus.NodeRoleEvent) event.Subscription {
+	return sb.roleFeed.Subscribe(ch)
 }
 
 // zekun: HACK
@@ -108,6 +189,8 @@ func (sb *backend) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64
 
 // Address implements istanbul.Backend.Address
 func (sb *backend) Address() common.Address {
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
 	return sb.address
 }
 
@@ -141,6 +224,12 @@ func (sb *backend) Gossip(valSet istanbul.ValidatorSet, payload []byte) error {
 	}
 	if sb.broadcaster != nil && len(targets) > 0 {
 		ps := sb.broadcaster.FindPeers(targets)
+		// Quorum
+		// the validators signing with another key than their node key (e.g.: a cloud KMS
+		// key) cannot be found by address, the message is then sent to all the peers
+		if len(ps) < len(targets) {
+			ps = sb.broadcaster.FindPeers(nil)
+		}
 		for addr, p := range ps {
 			ms, ok := sb.recentMessages.Get(addr)
 			var m *lru.ARCCache
@@ -242,6 +331,11 @@ func (sb *backend) Verify(proposal istanbul.Proposal) (time.Duration, error) {
 // Sign implements istanbul.Backend.Sign
 func (sb *backend) Sign(data []byte) ([]byte, error) {
 	hashData := crypto.Keccak256(data)
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
+	if sb.signer != nil {
+		return sb.signer.Sign(hashData)
+	}
 	return crypto.Sign(hashData, sb.privateKey)
 }
 
@@ -83,6 +83,8 @@ var (
 	errEmptyCommittedSeals = errors.New("zero committed seals")
 	// errMismatchTxhashes is returned if the TxHash in header is mismatch.
 	errMismatchTxhashes = errors.New("mismatch transactions hashes")
+	// errInvalidKeyRotation is returned if a validator hands over its seat to a validator or to the zero address.
+	errInvalidKeyRotation = errors.New("key rotation to a validator or to the zero address")
 )
 var (
 	defaultDifficulty = big.NewInt(1)
@@ -92,6 +94,8 @@ var (
 
 	nonceAuthVote = hexutil.MustDecode("0xffffffffffffffff") // Magic nonce number to vote on adding a new validator
 	nonceDropVote = hexutil.MustDecode("0x0000000000000000") // Magic nonce number to vote on removing a validator.
+	// Quorum
+	nonceRotateVote = hexutil.MustDecode("0x00000000000000ff") // Magic nonce number of a validator handing over its seat to its new key
 
 	inmemoryAddresses  = 20 // Number of recent addresses from ecrecover
 	recentAddresses, _ = lru.NewARC(inmemoryAddresses)
@@ -158,7 +162,10 @@ func (sb *backend) verifyHeader(chain consensus.ChainHeaderReader, header *types
 
 	// Ensure that the coinbase is valid
 	if header.Nonce != (emptyNonce) && !bytes.Equal(header.Nonce[:], nonceAuthVote) && !bytes.Equal(header.Nonce[:], nonceDropVote) {
-		return errInvalidNonce
+		// Quorum: the key rotations are valid from the fork block on
+		if !bytes.Equal(header.Nonce[:], nonceRotateVote) || !sb.config.IsKeyRotationEnabled(header.Number) {
+			return errInvalidNonce
+		}
 	}
 	// Ensure that the mix digest is zero as we don't have fork protection currently
 	if header.MixDigest != types.IstanbulDigest {
@@ -211,6 +218,13 @@ func (sb *backend) verifyCascadingFields(chain consensus.ChainHeaderReader, head
 	if err := sb.verifySigner(chain, header, parents); err != nil {
 		return err
 	}
+	// Quorum: an invalid key rotation is rejected before the block is committed, as the
+	// snapshots of the blocks on top of it would fail to apply it
+	if bytes.Equal(header.Nonce[:], nonceRotateVote) {
+		if err := verifyKeyRotation(header, snap); err != nil {
+			return err
+		}
+	}
 
 	return sb.verifyCommittedSeals(chain, header, parents)
 }
@@ -379,8 +393,12 @@ func (sb *backend) Prepare(chain consensus.ChainHeaderReader, header *types.Head
 	}
 	sb.candidatesLock.RUnlock()
 
-	// pick one of the candidates randomly
-	if len(addresses) > 0 {
+	// Quorum: the validator rotating its key votes for the handover first
+	if rotation := sb.pendingKeyRotation(header.Number); rotation != nil && snap.canRotate(rotation.OldAddress, rotation.NewAddress) {
+		header.Coinbase = rotation.NewAddress
+		copy(header.Nonce[:], nonceRotateVote)
+	} else if len(addresses) > 0 {
+		// pick one of the candidates randomly
 		index := rand.Intn(len(addresses))
 		// add validator voting in coinbase
 		header.Coinbase = addresses[index]
@@ -432,6 +450,10 @@ func (sb *backend) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header
 // Seal generates a new block for the given input block with the local miner's
 // seal place on top.
 func (sb *backend) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+	// Quorum: the block isn't proposed while the validator is in maintenance
+	if sb.inMaintenance() {
+		return nil
+	}
 
 	// update the block header timestamp and signature and propose the block to core engine
 	header := block.Header()
@@ -441,7 +463,7 @@ func (sb *backend) Seal(chain consensus.ChainHeaderReader, block *types.Block, r
 	if err != nil {
 		return err
 	}
-	if _, v := snap.ValSet.GetByAddress(sb.address); v == nil {
+	if _, v := snap.ValSet.GetByAddress(sb.Address()); v == nil {
 		return errUnauthorized
 	}
 
@@ -519,6 +541,21 @@ func (sb *backend) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 		Version:   "1.0",
 		Service:   &API{chain: chain, istanbul: sb},
 		Public:    true,
+	}, {
+		Namespace: "quorum",
+		Version:   "1.0",
+		Service:   consensus.NewPublicNodeRoleAPI(sb),
+		Public:    true,
+	}, {
+		Namespace: "quorum",
+		Version:   "1.0",
+		Service:   &PublicBlockMakerAPI{istanbul: sb},
+		Public:    true,
+	}, {
+		Namespace: "istanbul",
+		Version:   "1.0",
+		Service:   &PrivateKeyRotationAPI{chain: chain, istanbul: sb},
+		Public:    false,
 	}}
 }
 
@@ -540,6 +577,8 @@ func (sb *backend) Start(chain consensus.ChainHeaderReader, currentBlock func()
 	sb.chain = chain
 	sb.currentBlock = currentBlock
 	sb.hasBadBlock = hasBadBlock
+	// Quorum: the key may have been rotated while the engine was stopped
+	sb.switchKeyIfRotated(chain, chain.CurrentHeader())
 
 	if err := sb.core.Start(); err != nil {
 		return err
@@ -601,6 +640,21 @@ func (sb *backend) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 			log.Trace("Stored genesis voting snapshot to disk")
 			break
 		}
+		// Quorum: a light client synced from a trusted checkpoint doesn't have the headers
+		// before it. The validators in the extra data of an epoch block are the ones of
+		// its parent, which has no pending votes, so trust them as the parent snapshot.
+		if sb.config.Epoch != 0 && number%sb.config.Epoch == 0 && chain.GetHeaderByNumber(number-1) == nil {
+			if checkpoint := chain.GetHeaderByNumber(number); checkpoint != nil && checkpoint.Hash() == hash {
+				istanbulExtra, err := types.ExtractIstanbulExtra(checkpoint)
+				if err != nil {
+					return nil, err
+				}
+				snap = newSnapshot(sb.config.Epoch, number-1, checkpoint.ParentHash, validator.NewSet(istanbulExtra.Validators, sb.config.ProposerPolicy))
+				headers = append(headers, checkpoint)
+				log.Info("Loaded voting snapshot from trusted checkpoint", "number", number, "hash", hash)
+				break
+			}
+		}
 		// No snapshot for this header, gather the header and move backward
 		var header *types.Header
 		if len(parents) > 0 {
@@ -577,3 +577,52 @@ func TestWriteCommittedSeals(t *testing.T) {
 		t.Errorf("error mismatch: have %v, want %v", err, errInvalidCommittedSeals)
 	}
 }
+
+// checkpointHeaderReader only has the headers from a trusted checkpoint onwards, as a
+// light client synced from the checkpoint
+type checkpointHeaderReader struct {
+	consensus.ChainHeaderReader
+	headers map[uint64]*types.Header
+}
+
+func (r *checkpointHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := r.headers[number]; header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
+func (r *checkpointHeaderReader) GetHeaderByNumber(number uint64) *types.Header {
+	return r.headers[number]
+}
+
+func TestSnapshotFromTrustedCheckpoint(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	block1 := makeBlock(chain, engine, chain.Genesis())
+	if _, err := chain.InsertChain(types.Blocks{block1}); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+	block2 := makeBlock(chain, engine, block1)
+
+	config := *istanbul.DefaultConfig
+	config.Epoch = 2
+	lightEngine := New(&config, engine.privateKey, rawdb.NewMemoryDatabase()).(*backend)
+	reader := &checkpointHeaderReader{ChainHeaderReader: chain, headers: map[uint64]*types.Header{2: block2.Header()}}
+
+	snap, err := lightEngine.snapshot(reader, 2, block2.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to load snapshot from checkpoint: %v", err)
+	}
+	if snap.Number != 2 || snap.Hash != block2.Hash() {
+		t.Errorf("snapshot mismatch: have %d %x, want 2 %x", snap.Number, snap.Hash, block2.Hash())
+	}
+	if validators := snap.validators(); !reflect.DeepEqual(validators, []common.Address{engine.Address()}) {
+		t.Errorf("validators mismatch: have %v, want %v", validators, []common.Address{engine.Address()})
+	}
+
+	// not an epoch block
+	config.Epoch = 4
+	if _, err := New(&config, engine.privateKey, rawdb.NewMemoryDatabase()).(*backend).snapshot(reader, 2, block2.Hash(), nil); err != consensus.ErrUnknownAncestor {
+		t.Errorf("error mismatch: have %v, want %v", err, consensus.ErrUnknownAncestor)
+	}
+}
@@ -133,6 +133,8 @@ func (sb *backend) NewChainHead() error {
 	if !sb.coreStarted {
 		return istanbul.ErrStoppedEngine
 	}
+	// Quorum: the new round is started with the new key once rotated
+	sb.switchKeyIfRotated(sb.chain, sb.chain.CurrentHeader())
 	go sb.istanbulEventMux.Post(istanbul.FinalCommittedEvent{})
 	return nil
 }
@@ -0,0 +1,190 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+//
+// The validator key rotation hands over the seat of the validator to its new key:
+// from the target block on, the validator casts a rotation vote in the blocks it
+// proposes, applied without tally as the validator gives up its own seat. The node
+// signs with the new key once the vote is in the chain, its node key is unchanged.
+
+// keyRotationEngine names the key rotation of the validator in the database
+const keyRotationEngine = "istanbul"
+
+var (
+	errKeyRotationDisabled = errors.New("the key rotation is not enabled by the chain config at the target block")
+	errKeyRotationPast     = errors.New("the target block of the key rotation is in the past")
+	errNotValidator        = errors.New("the node is not a validator")
+	errAlreadyValidator    = errors.New("the new key is already a validator")
+	errRotateRotatedKey    = errors.New("the key is already rotated, the node must sign with the new key from startup to rotate it again")
+)
+
+// loadKeyRotation resumes the key rotation of the validator key of the node, if any
+func (sb *backend) loadKeyRotation() {
+	rotation, err := consensus.ReadKeyRotation(sb.db, keyRotationEngine)
+	if err != nil {
+		log.Error("Failed to load the validator key rotation", "err", err)
+		return
+	}
+	// the rotations of other keys are ignored, e.g. if the node was started with the new key
+	if rotation == nil || rotation.OldAddress != sb.address {
+		return
+	}
+	sb.rotation = rotation
+	if rotation.Switched {
+		sb.useKey(rotation)
+	}
+}
+
+// useKey signs with the new key of the rotation, keyMu must be held unless the backend is
+// being created
+func (sb *backend) useKey(rotation *consensus.KeyRotation) {
+	sb.privateKey = rotation.Key()
+	sb.signer = nil
+	sb.address = rotation.NewAddress
+}
+
+// pendingKeyRotation returns the key rotation to vote for in the block, nil if there is none
+func (sb *backend) pendingKeyRotation(number *big.Int) *consensus.KeyRotation {
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
+	if sb.rotation == nil || sb.rotation.Switched || number.Uint64() < sb.rotation.Block || !sb.config.IsKeyRotationEnabled(number) {
+		return nil
+	}
+	return sb.rotation
+}
+
+// registerKeyRotation registers the new validator key of keyfile, voted for from the
+// target block on
+func (sb *backend) registerKeyRotation(chain consensus.ChainHeaderReader, keyfile string, block uint64) (*consensus.KeyRotation, error) {
+	if !sb.config.IsKeyRotationEnabled(new(big.Int).SetUint64(block)) {
+		return nil, errKeyRotationDisabled
+	}
+	header := chain.CurrentHeader()
+	if block <= header.Number.Uint64() {
+		return nil, errKeyRotationPast
+	}
+	sb.keyMu.Lock()
+	defer sb.keyMu.Unlock()
+	if sb.rotation != nil {
+		if !sb.rotation.Switched {
+			return nil, consensus.ErrKeyRotationPending
+		}
+		// the rotations are resumed at startup from the node key
+		return nil, errRotateRotatedKey
+	}
+	rotation, err := consensus.NewKeyRotation(keyfile, sb.address, block)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := sb.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, v := snap.ValSet.GetByAddress(rotation.OldAddress); v == nil {
+		return nil, errNotValidator
+	}
+	if _, v := snap.ValSet.GetByAddress(rotation.NewAddress); v != nil {
+		return nil, errAlreadyValidator
+	}
+	if err := consensus.WriteKeyRotation(sb.db, keyRotationEngine, rotation); err != nil {
+		return nil, err
+	}
+	sb.rotation = rotation
+	log.Info("Registered the validator key rotation", "old", rotation.OldAddress, "new", rotation.NewAddress, "block", block)
+	return rotation, nil
+}
+
+// cancelKeyRotation cancels the key rotation until the new key is in the validators
+func (sb *backend) cancelKeyRotation() error {
+	sb.keyMu.Lock()
+	defer sb.keyMu.Unlock()
+	if sb.rotation == nil {
+		return consensus.ErrNoKeyRotation
+	}
+	if sb.rotation.Switched {
+		return consensus.ErrKeyRotated
+	}
+	if err := consensus.DeleteKeyRotation(sb.db, keyRotationEngine); err != nil {
+		return err
+	}
+	log.Info("Cancelled the validator key rotation", "new", sb.rotation.NewAddress)
+	sb.rotation = nil
+	return nil
+}
+
+// keyRotation returns a copy of the key rotation, nil if there is none
+func (sb *backend) keyRotation() *consensus.KeyRotation {
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
+	if sb.rotation == nil {
+		return nil
+	}
+	rotation := *sb.rotation
+	return &rotation
+}
+
+// verifyKeyRotation checks that the proposer of the header may hand over its seat to the
+// new key in the coinbase, against the snapshot of the parent
+func verifyKeyRotation(header *types.Header, snap *Snapshot) error {
+	proposer, err := ecrecover(header)
+	if err != nil {
+		return err
+	}
+	if !snap.canRotate(proposer, header.Coinbase) {
+		return errInvalidKeyRotation
+	}
+	return nil
+}
+
+// switchKeyIfRotated signs with the new key once it replaces the old key in the validators
+// at the header, the old key is retired
+func (sb *backend) switchKeyIfRotated(chain consensus.ChainHeaderReader, header *types.Header) {
+	if chain == nil || header == nil {
+		return
+	}
+	sb.keyMu.Lock()
+	defer sb.keyMu.Unlock()
+	rotation := sb.rotation
+	if rotation == nil || rotation.Switched || header.Number.Uint64() < rotation.Block {
+		return
+	}
+	snap, err := sb.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		log.Warn("Failed to check the validator key rotation", "number", header.Number, "err", err)
+		return
+	}
+	if _, v := snap.ValSet.GetByAddress(rotation.NewAddress); v == nil {
+		return
+	}
+	rotation.Switched = true
+	if err := consensus.WriteKeyRotation(sb.db, keyRotationEngine, rotation); err != nil {
+		log.Error("Failed to store the validator key rotation", "err", err)
+	}
+	sb.useKey(rotation)
+	log.Info("Rotated the validator key", "old", rotation.OldAddress, "new", rotation.NewAddress, "number", header.Number)
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newKeyRotationChain returns a chain of one validator rotating its key from block 1 on,
+// and the new address of the validator. The returned func removes the new key file.
+func newKeyRotationChain(t *testing.T) (*core.BlockChain, *backend, common.Address, func()) {
+	chain, engine := newBlockChain(1)
+	config := *engine.config
+	config.KeyRotationBlock = common.Big1
+	engine.config = &config
+
+	dir, err := ioutil.TempDir("", "istanbul-keyrotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := crypto.GenerateKey()
+	keyfile := filepath.Join(dir, "nodekey")
+	if err := crypto.SaveECDSA(keyfile, key); err != nil {
+		t.Fatal(err)
+	}
+	if engine.rotation, err = consensus.NewKeyRotation(keyfile, engine.address, 1); err != nil {
+		t.Fatal(err)
+	}
+	return chain, engine, crypto.PubkeyToAddress(key.PublicKey), func() {
+		chain.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPrepareKeyRotation(t *testing.T) {
+	chain, engine, newAddress, cleanup := newKeyRotationChain(t)
+	defer cleanup()
+
+	header := makeHeader(chain.Genesis(), engine.config)
+	if err := engine.Prepare(chain, header); err != nil {
+		t.Fatalf("error mismatch: have %v, want nil", err)
+	}
+	if header.Coinbase != newAddress {
+		t.Errorf("coinbase mismatch: have %v, want %v", header.Coinbase.Hex(), newAddress.Hex())
+	}
+	if !bytes.Equal(header.Nonce[:], nonceRotateVote) {
+		t.Errorf("nonce mismatch: have %x, want %x", header.Nonce[:], nonceRotateVote)
+	}
+}
+
+func TestVerifyHeaderKeyRotation(t *testing.T) {
+	chain, engine, _, cleanup := newKeyRotationChain(t)
+	defer cleanup()
+
+	// the rotation to the new key passes, the committed seals are checked next
+	block := makeBlockWithoutSeal(chain, engine, chain.Genesis())
+	block, _ = engine.updateBlock(chain.Genesis().Header(), block)
+	if err := engine.VerifyHeader(chain, block.Header(), false); err != errEmptyCommittedSeals {
+		t.Errorf("error mismatch: have %v, want %v", err, errEmptyCommittedSeals)
+	}
+
+	// rotation to the zero address
+	block = makeBlockWithoutSeal(chain, engine, chain.Genesis())
+	header := block.Header()
+	header.Coinbase = common.Address{}
+	block, _ = engine.updateBlock(chain.Genesis().Header(), block.WithSeal(header))
+	if err := engine.VerifyHeader(chain, block.Header(), false); err != errInvalidKeyRotation {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidKeyRotation)
+	}
+
+	// rotation to a validator
+	block = makeBlockWithoutSeal(chain, engine, chain.Genesis())
+	header = block.Header()
+	header.Coinbase = engine.Address()
+	block, _ = engine.updateBlock(chain.Genesis().Header(), block.WithSeal(header))
+	if err := engine.VerifyHeader(chain, block.Header(), false); err != errInvalidKeyRotation {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidKeyRotation)
+	}
+}
+
+func TestSwitchKeyIfRotated(t *testing.T) {
+	chain, engine, newAddress, cleanup := newKeyRotationChain(t)
+	defer cleanup()
+	oldAddress := engine.Address()
+
+	block := makeBlock(chain, engine, chain.Genesis())
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to insert the block: %v", err)
+	}
+	engine.switchKeyIfRotated(chain, chain.CurrentHeader())
+
+	if engine.Address() != newAddress {
+		t.Errorf("address mismatch: have %v, want %v", engine.Address().Hex(), newAddress.Hex())
+	}
+	if rotation := engine.keyRotation(); rotation == nil || !rotation.Switched {
+		t.Errorf("the key rotation is not switched: %v", rotation)
+	}
+	snap, err := engine.snapshot(chain, block.NumberU64(), block.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to get the snapshot: %v", err)
+	}
+	if _, v := snap.ValSet.GetByAddress(newAddress); v == nil {
+		t.Errorf("the new key is not a validator")
+	}
+	if _, v := snap.ValSet.GetByAddress(oldAddress); v != nil {
+		t.Errorf("the old key is still a validator")
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Quorum
+// The validator in maintenance keeps validating the blocks proposed by the others but
+// skips its own proposals, the next proposer proposing once the round times out.
+
+// EnterMaintenance skips the proposals of the validator from now on
+func (sb *backend) EnterMaintenance(ctx context.Context) error {
+	if atomic.CompareAndSwapInt32(&sb.maintenance, 0, 1) {
+		sb.logger.Info("Skipping the Istanbul proposals for maintenance", "address", sb.Address())
+	}
+	return nil
+}
+
+// ExitMaintenance makes the proposals of the validator again
+func (sb *backend) ExitMaintenance() error {
+	if atomic.CompareAndSwapInt32(&sb.maintenance, 1, 0) {
+		sb.logger.Info("Making the Istanbul proposals again", "address", sb.Address())
+	}
+	return nil
+}
+
+func (sb *backend) inMaintenance() bool {
+	return atomic.LoadInt32(&sb.maintenance) == 1
+}
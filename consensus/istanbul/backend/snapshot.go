@@ -211,6 +211,12 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			authorize = true
 		case bytes.Equal(header.Nonce[:], nonceDropVote):
 			authorize = false
+		case bytes.Equal(header.Nonce[:], nonceRotateVote):
+			// Quorum: the validator hands over its seat to its new key, without tally
+			if err := snap.rotate(validator, header.Coinbase); err != nil {
+				return nil, err
+			}
+			continue
 		default:
 			return nil, errInvalidVote
 		}
@@ -258,6 +264,40 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	return snap, nil
 }
 
+// Quorum
+//
+// canRotate returns whether the validator from may hand over its seat to its new key to,
+// which must not be a validator nor the zero address
+func (s *Snapshot) canRotate(from, to common.Address) bool {
+	if to == (common.Address{}) {
+		return false
+	}
+	_, oldValidator := s.ValSet.GetByAddress(from)
+	_, newValidator := s.ValSet.GetByAddress(to)
+	return oldValidator != nil && newValidator == nil
+}
+
+// Quorum
+//
+// rotate replaces the validator from by its new key to, the votes cast by from and on from
+// are discarded
+func (s *Snapshot) rotate(from, to common.Address) error {
+	if !s.canRotate(from, to) {
+		return errInvalidKeyRotation
+	}
+	s.ValSet.RemoveValidator(from)
+	s.ValSet.AddValidator(to)
+	for i := 0; i < len(s.Votes); i++ {
+		if vote := s.Votes[i]; vote.Validator == from || vote.Address == from {
+			s.uncast(vote.Address, vote.Authorize)
+			s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+			i--
+		}
+	}
+	delete(s.Tally, from)
+	return nil
+}
+
 // validators retrieves the list of authorized validators in ascending order.
 func (s *Snapshot) validators() []common.Address {
 	validators := make([]common.Address, 0, s.ValSet.Size())
@@ -455,3 +455,39 @@ func TestSaveAndLoad(t *testing.T) {
 		t.Errorf("validator set mismatch: have %v, want %v", snap1.ValSet, snap.ValSet)
 	}
 }
+
+func TestRotate(t *testing.T) {
+	accounts := newTesterAccountPool()
+	snap := &Snapshot{
+		Votes: []*Vote{
+			{Validator: accounts.address("A"), Block: 1, Address: accounts.address("D"), Authorize: true},
+			{Validator: accounts.address("B"), Block: 2, Address: accounts.address("D"), Authorize: true},
+		},
+		Tally: map[common.Address]Tally{
+			accounts.address("D"): {Authorize: true, Votes: 2},
+		},
+		ValSet: validator.NewSet([]common.Address{accounts.address("A"), accounts.address("B"), accounts.address("C")}, istanbul.RoundRobin),
+	}
+
+	if snap.canRotate(accounts.address("A"), accounts.address("B")) {
+		t.Errorf("rotated to the key of another validator")
+	}
+	if err := snap.rotate(accounts.address("D"), accounts.address("E")); err != errInvalidKeyRotation {
+		t.Errorf("rotate of a non validator: have %v, want %v", err, errInvalidKeyRotation)
+	}
+	if err := snap.rotate(accounts.address("A"), accounts.address("E")); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if _, v := snap.ValSet.GetByAddress(accounts.address("A")); v != nil {
+		t.Errorf("the old key is still a validator")
+	}
+	if _, v := snap.ValSet.GetByAddress(accounts.address("E")); v == nil {
+		t.Errorf("the new key is not a validator")
+	}
+	if len(snap.Votes) != 1 || snap.Votes[0].Validator != accounts.address("B") {
+		t.Errorf("votes mismatch: have %v, want the vote of B only", snap.Votes)
+	}
+	if tally := snap.Tally[accounts.address("D")]; tally.Votes != 1 {
+		t.Errorf("tally mismatch: have %d, want 1", tally.Votes)
+	}
+}
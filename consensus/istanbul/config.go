@@ -32,6 +32,14 @@ type Config struct {
 	Epoch                  uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
 	Ceil2Nby3Block         *big.Int       `toml:",omitempty"` // Number of confirmations required to move from one state to next [2F + 1 to Ceil(2N/3)]
 	AllowedFutureBlockTime uint64         `toml:",omitempty"` // Max time (in seconds) from current time allowed for blocks, before they're considered future blocks
+	KeyRotationBlock       *big.Int       `toml:",omitempty"` // Quorum: block from which the validators may hand over their seat to their new key (disabled if nil)
+}
+
+// Quorum
+//
+// IsKeyRotationEnabled returns whether the validators may rotate their key at the block
+func (c *Config) IsKeyRotationEnabled(number *big.Int) bool {
+	return c.KeyRotationBlock != nil && number.Cmp(c.KeyRotationBlock) >= 0
 }
 
 var DefaultConfig = &Config{
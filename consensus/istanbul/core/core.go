@@ -32,6 +32,14 @@ import (
 	"gopkg.in/karalabe/cookiejar.v2/collections/prque"
 )
 
+// Quorum
+var (
+	// missedTurnMeter records the rounds the proposers failed to commit a block in
+	missedTurnMeter = metrics.NewRegisteredMeter("consensus/istanbul/core/missedturns", nil)
+	// roundGauge reports the round of the block being made, above 0 once the proposers miss their turn
+	roundGauge = metrics.NewRegisteredGauge("consensus/istanbul/core/currentround", nil)
+)
+
 // New creates an Istanbul consensus core
 func New(backend istanbul.Backend, config *istanbul.Config) Engine {
 	r := metrics.NewRegistry()
@@ -50,6 +58,7 @@ func New(backend istanbul.Backend, config *istanbul.Config) Engine {
 		roundMeter:         metrics.NewMeter(),
 		sequenceMeter:      metrics.NewMeter(),
 		consensusTimer:     metrics.NewTimer(),
+		missedTurns:        make(map[common.Address]uint64),
 	}
 
 	r.Register("consensus/istanbul/core/round", c.roundMeter)
@@ -97,6 +106,12 @@ type core struct {
 	sequenceMeter metrics.Meter
 	// the timer to record consensus duration (from accepting a preprepare to final committed stage)
 	consensusTimer metrics.Timer
+
+	// Quorum
+	// the state of the block production reported by BlockMakerStatus
+	status      istanbul.BlockMakerStatus
+	missedTurns map[common.Address]uint64
+	statusMu    sync.RWMutex
 }
 
 func (c *core) finalizeMessage(msg *message) ([]byte, error) {
@@ -190,6 +205,9 @@ func (c *core) commit() {
 
 // startNewRound starts a new round. if round equals to 0, it means to starts a new sequence
 func (c *core) startNewRound(round *big.Int) {
+	// Quorum: the validator key may have been rotated by the last proposal
+	c.address = c.backend.Address()
+
 	var logger log.Logger
 	if c.current == nil {
 		logger = c.logger.New("old_round", -1, "old_seq", 0)
@@ -241,6 +259,10 @@ func (c *core) startNewRound(round *big.Int) {
 
 	// Update logger
 	logger = logger.New("old_proposer", c.valSet.GetProposer())
+	if roundChange {
+		// the proposer of the previous round failed to get its block committed
+		c.missedTurn(c.valSet.GetProposer())
+	}
 	// Clear invalid ROUND CHANGE messages
 	c.roundChangeSet = newRoundChangeSet(c.valSet)
 	// New snapshot for new round
@@ -264,6 +286,61 @@ func (c *core) startNewRound(round *big.Int) {
 	c.newRoundChangeTimer()
 
 	logger.Debug("New round", "new_round", newView.Round, "new_seq", newView.Sequence, "new_proposer", c.valSet.GetProposer(), "valSet", c.valSet.List(), "size", c.valSet.Size(), "IsProposer", c.IsProposer())
+	c.backend.NodeRoleChanged(c.nodeRole())
+}
+
+// Quorum
+// nodeRole returns the role of the local node in the current round
+func (c *core) nodeRole() string {
+	if c.IsProposer() {
+		return istanbul.RoleProposer
+	}
+	if _, v := c.valSet.GetByAddress(c.backend.Address()); v != nil {
+		return istanbul.RoleValidator
+	}
+	return istanbul.RoleNonValidator
+}
+
+// missedTurn records a round the proposer failed to commit a block in
+func (c *core) missedTurn(proposer istanbul.Validator) {
+	if proposer == nil {
+		return
+	}
+	missedTurnMeter.Mark(1)
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	c.missedTurns[proposer.Address()]++
+}
+
+// updateBlockMakerStatus records the proposer expected to make the block of the current
+// round, and the window the round lasts until a round change is voted
+func (c *core) updateBlockMakerStatus(timeout time.Duration) {
+	status := istanbul.BlockMakerStatus{
+		Sequence:   new(big.Int).Set(c.current.Sequence()),
+		Round:      new(big.Int).Set(c.current.Round()),
+		RoundStart: time.Now(),
+	}
+	status.RoundEnd = status.RoundStart.Add(timeout)
+	if proposer := c.valSet.GetProposer(); proposer != nil {
+		status.Proposer = proposer.Address()
+	}
+	roundGauge.Update(status.Round.Int64())
+
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	c.status = status
+}
+
+// BlockMakerStatus implements core.Engine.BlockMakerStatus
+func (c *core) BlockMakerStatus() *istanbul.BlockMakerStatus {
+	c.statusMu.RLock()
+	defer c.statusMu.RUnlock()
+	status := c.status
+	status.MissedTurns = make(map[common.Address]uint64, len(c.missedTurns))
+	for address, missed := range c.missedTurns {
+		status.MissedTurns[address] = missed
+	}
+	return &status
 }
 
 func (c *core) catchUpRound(view *istanbul.View) {
@@ -335,6 +412,7 @@ func (c *core) newRoundChangeTimer() {
 	c.roundChangeTimer = time.AfterFunc(timeout, func() {
 		c.sendEvent(timeoutEvent{})
 	})
+	c.updateBlockMakerStatus(timeout)
 }
 
 func (c *core) checkValidatorSignature(data []byte, sig []byte) (common.Address, error) {
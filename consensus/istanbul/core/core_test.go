@@ -79,6 +79,31 @@ func TestNewRequest(t *testing.T) {
 	}
 }
 
+func TestNodeRole(t *testing.T) {
+	N := uint64(4)
+	F := uint64(1)
+
+	sys := NewTestSystemWithBackend(N, F)
+
+	proposers := 0
+	for _, backend := range sys.backends {
+		backend.engine.(*core).startNewRound(common.Big1)
+		if len(backend.roles) == 0 {
+			t.Fatalf("no role notified for backend %d", backend.id)
+		}
+		switch role := backend.roles[0]; role {
+		case istanbul.RoleProposer:
+			proposers++
+		case istanbul.RoleValidator:
+		default:
+			t.Errorf("unexpected role for backend %d: %v", backend.id, role)
+		}
+	}
+	if proposers != 1 {
+		t.Errorf("the number of proposers mismatch: have %v, want 1", proposers)
+	}
+}
+
 func TestQuorumSize(t *testing.T) {
 	N := uint64(4)
 	F := uint64(1)
@@ -95,3 +120,25 @@ func TestQuorumSize(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockMakerStatus_missedTurn(t *testing.T) {
+	sys := NewTestSystemWithBackend(4, 1)
+	c := sys.backends[0].engine.(*core)
+	missed := c.valSet.GetProposer().Address()
+
+	c.startNewRound(common.Big1)
+
+	status := c.BlockMakerStatus()
+	if status.Round.Cmp(common.Big1) != 0 || status.Sequence.Cmp(common.Big1) != 0 {
+		t.Errorf("view mismatch: have {%v, %v}, want {1, 1}", status.Round, status.Sequence)
+	}
+	if status.Proposer != c.valSet.GetProposer().Address() || status.Proposer == missed {
+		t.Errorf("proposer mismatch: have %v, want %v", status.Proposer, c.valSet.GetProposer().Address())
+	}
+	if !status.RoundEnd.After(status.RoundStart) {
+		t.Errorf("round window mismatch: have %v to %v", status.RoundStart, status.RoundEnd)
+	}
+	if have := status.MissedTurns[missed]; have != 1 {
+		t.Errorf("missed turns mismatch: have %v, want 1", have)
+	}
+}
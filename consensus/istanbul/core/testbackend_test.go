@@ -43,6 +43,7 @@ type testSystemBackend struct {
 
 	committedMsgs []testCommittedMsgs
 	sentMsgs      [][]byte // store the message when Send is called by core
+	roles         []string // store the roles notified by core
 
 	address common.Address
 	db      ethdb.Database
@@ -157,6 +158,10 @@ func (self *testSystemBackend) ParentValidators(proposal istanbul.Proposal) ista
 	return self.peers
 }
 
+func (self *testSystemBackend) NodeRoleChanged(role string) {
+	self.roles = append(self.roles, role)
+}
+
 func (sb *testSystemBackend) Close() error {
 	return nil
 }
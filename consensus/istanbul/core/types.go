@@ -22,6 +22,7 @@ import (
 	"io"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -38,6 +39,10 @@ type Engine interface {
 	// pending request is populated right at the preprepare stage so this would give us the earliest verification
 	// to avoid any race condition of coming propagated blocks
 	IsCurrentProposal(blockHash common.Hash) bool
+
+	// Quorum
+	// BlockMakerStatus returns the state of the block production of the current round
+	BlockMakerStatus() *istanbul.BlockMakerStatus
 }
 
 type State uint64
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -79,6 +80,20 @@ func (v *View) String() string {
 	return fmt.Sprintf("{Round: %d, Sequence: %d}", v.Round.Uint64(), v.Sequence.Uint64())
 }
 
+// Quorum
+// BlockMakerStatus is the state of the block production of the current round, to
+// diagnose the networks no longer making blocks
+type BlockMakerStatus struct {
+	Sequence   *big.Int       `json:"sequence"` // number of the block being made
+	Round      *big.Int       `json:"round"`
+	Proposer   common.Address `json:"proposer"` // validator expected to propose the block
+	RoundStart time.Time      `json:"roundStart"`
+	RoundEnd   time.Time      `json:"roundEnd"` // the validators vote a round change from then on unless the block is committed
+	// MissedTurns are the rounds the validators failed to get their block committed
+	// in as proposers, since the node started
+	MissedTurns map[common.Address]uint64 `json:"missedTurns"`
+}
+
 // Cmp compares v and y and returns:
 //   -1 if v <  y
 //    0 if v == y
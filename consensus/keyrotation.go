@@ -0,0 +1,121 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Quorum
+
+var (
+	keyRotationPrefix = []byte("quorum-key-rotation-") // keyRotationPrefix + engine -> KeyRotation
+
+	// ErrKeyRotationPending is returned when a rotation of the signing key is already pending
+	ErrKeyRotationPending = errors.New("a key rotation is already pending")
+	// ErrNoKeyRotation is returned when no rotation of the signing key is pending
+	ErrNoKeyRotation = errors.New("no key rotation is pending")
+	// ErrKeyRotated is returned when the rotation of the signing key is already done
+	ErrKeyRotated = errors.New("the key is already rotated")
+)
+
+// KeyRotation is the rotation of the key the node signs the blocks with, the Istanbul
+// validator key, from the target block on. The node keeps its node key and its place in
+// the network, the old signing key being retired once the new key signs. Raft is not
+// supported: its followers do not verify the seal of the blocks, so there is no signing
+// key to hand over, the block maker being authorized by its raft membership.
+type KeyRotation struct {
+	KeyFile    string         `json:"keyFile"`    // File of the new key, in the node key format
+	OldAddress common.Address `json:"oldAddress"` // Address of the retired key
+	NewAddress common.Address `json:"newAddress"` // Address of the new key
+	Block      uint64         `json:"block"`      // Target block, the first block the new key may sign
+	Switched   bool           `json:"switched"`   // Whether the new key signs in place of the old one
+
+	key *ecdsa.PrivateKey
+}
+
+// NewKeyRotation loads the new key from keyfile for the rotation of the key of address
+// from the block on
+func NewKeyRotation(keyfile string, address common.Address, block uint64) (*KeyRotation, error) {
+	key, err := crypto.LoadECDSA(keyfile)
+	if err != nil {
+		return nil, err
+	}
+	rotation := &KeyRotation{
+		KeyFile:    keyfile,
+		OldAddress: address,
+		NewAddress: crypto.PubkeyToAddress(key.PublicKey),
+		Block:      block,
+		key:        key,
+	}
+	if rotation.NewAddress == address {
+		return nil, errors.New("the new key is the current key")
+	}
+	return rotation, nil
+}
+
+// Key returns the new key
+func (r *KeyRotation) Key() *ecdsa.PrivateKey {
+	return r.key
+}
+
+func keyRotationKey(engine string) []byte {
+	return append(append([]byte{}, keyRotationPrefix...), engine...)
+}
+
+// ReadKeyRotation retrieves the key rotation of the engine, with its new key loaded
+// again from its file, nil if there is none
+func ReadKeyRotation(db ethdb.KeyValueReader, engine string) (*KeyRotation, error) {
+	if has, err := db.Has(keyRotationKey(engine)); err != nil || !has {
+		return nil, err
+	}
+	data, err := db.Get(keyRotationKey(engine))
+	if err != nil {
+		return nil, err
+	}
+	rotation := new(KeyRotation)
+	if err := json.Unmarshal(data, rotation); err != nil {
+		return nil, err
+	}
+	if rotation.key, err = crypto.LoadECDSA(rotation.KeyFile); err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(rotation.key.PublicKey) != rotation.NewAddress {
+		return nil, errors.New("the key file of the key rotation has changed")
+	}
+	return rotation, nil
+}
+
+// WriteKeyRotation stores the key rotation of the engine
+func WriteKeyRotation(db ethdb.KeyValueWriter, engine string, rotation *KeyRotation) error {
+	data, err := json.Marshal(rotation)
+	if err != nil {
+		return err
+	}
+	return db.Put(keyRotationKey(engine), data)
+}
+
+// DeleteKeyRotation removes the key rotation of the engine
+func DeleteKeyRotation(db ethdb.KeyValueWriter, engine string) error {
+	return db.Delete(keyRotationKey(engine))
+}
@@ -63,7 +63,7 @@ type Protocol struct {
 type Broadcaster interface {
 	// Enqueue add a block into fetcher queue
 	Enqueue(id string, block *types.Block)
-	// FindPeers retrives peers by addresses
+	// FindPeers retrives peers by addresses, all the peers if the addresses are nil
 	FindPeers(map[common.Address]bool) map[common.Address]Peer
 }
 
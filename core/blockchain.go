@@ -46,6 +46,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/tracing"
 	"github.com/ethereum/go-ethereum/trie"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
@@ -136,7 +137,9 @@ type CacheConfig struct {
 
 	SnapshotWait bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 
-	PrivateTrieCleanJournal string // Quorum: Disk journal for saving clean private cache entries.
+	PrivateTrieCleanJournal string              // Quorum: Disk journal for saving clean private cache entries.
+	PrivateColdStore        ethdb.KeyValueStore // Quorum: Database of the storage of the private contracts moved to the cold store
+	PrivateColdAfter        time.Duration       // Quorum: Inactivity after which the storage of a private contract is moved to the cold store (0 = disabled)
 }
 
 // defaultCacheConfig are the default caching values if none are specified by the
@@ -224,10 +227,19 @@ type BlockChain struct {
 	isMultitenant bool // if this blockchain supports multitenancy
 	// privateStateManager manages private state(s) for this blockchain
 	privateStateManager mps.PrivateStateManager
-	saveRevertReason    bool // if we should save the revert reasons in the Tx Receipts
+	saveRevertReason    bool                 // if we should save the revert reasons in the Tx Receipts
+	localBlockValidator atomic.Value         // LocalBlockValidator checking blocks produced by this node, if any
+	privateTiering      *privateStateTiering // moves the inactive private contracts to the cold store, nil if disabled
+	indexPayloads       bool                 // if we should index the hashes of all the private payloads, party or not
 	// End Quorum
 }
 
+// Quorum
+//
+// LocalBlockValidator checks a block produced by this node before it is sealed and broadcast.
+// Returning an error vetoes the block
+type LocalBlockValidator func(block *types.Block, receipts types.Receipts) error
+
 // NewBlockChain returns a fully initialised block chain using information
 // available in the database. It initialises the default Ethereum Validator and
 // Processor.
@@ -271,6 +283,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if bc.privateStateManager, err = newPrivateStateManager(bc.db, cacheConfig, chainConfig.IsMPS); err != nil {
 		return nil, err
 	}
+	bc.privateTiering = newPrivateStateTiering(cacheConfig)
 	bc.hc, err = NewHeaderChain(db, chainConfig, engine, bc.insertStopped)
 	if err != nil {
 		return nil, err
@@ -403,6 +416,12 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	}
 	// Take ownership of this particular state
 	go bc.update()
+	// Quorum
+	if bc.privateTiering != nil {
+		bc.wg.Add(1)
+		go bc.privateTiering.loop(bc)
+	}
+	// End Quorum
 	if txLookupLimit != nil {
 		bc.txLookupLimit = *txLookupLimit
 
@@ -449,6 +468,13 @@ func (bc *BlockChain) SetPrivateStateManager(psm mps.PrivateStateManager) {
 	bc.privateStateManager = psm
 }
 
+// PauseWrites blocks the insertion of blocks into the chain until the returned
+// function is called, e.g.: to take a consistent backup of the database.
+func (bc *BlockChain) PauseWrites() (resume func()) {
+	bc.chainmu.Lock()
+	return bc.chainmu.Unlock
+}
+
 // End Quorum
 
 // GetVMConfig returns the block chain VM config.
@@ -759,6 +785,24 @@ func (bc *BlockChain) State() (*state.StateDB, mps.PrivateStateRepository, error
 	return bc.StateAt(bc.CurrentBlock().Root())
 }
 
+// Quorum
+//
+// SetLocalBlockValidator registers the validator used by ValidateLocalBlock
+func (bc *BlockChain) SetLocalBlockValidator(v LocalBlockValidator) {
+	bc.localBlockValidator.Store(v)
+}
+
+// Quorum
+//
+// ValidateLocalBlock returns an error if the block produced by this node is vetoed
+// by the registered LocalBlockValidator
+func (bc *BlockChain) ValidateLocalBlock(block *types.Block, receipts types.Receipts) error {
+	if v, ok := bc.localBlockValidator.Load().(LocalBlockValidator); ok && v != nil {
+		return v(block, receipts)
+	}
+	return nil
+}
+
 // Quorum
 //
 // StatePSI returns a new mutable public state and a mutable private state for given PSI,
@@ -1606,17 +1650,17 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 }
 
 // WriteBlockWithState writes the block and all associated state to the database.
-func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, psManager mps.PrivateStateRepository, emitHeadEvent bool) (status WriteStatus, err error) {
+func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts, privateReceipts []*types.Receipt, logs []*types.Log, state *state.StateDB, psManager mps.PrivateStateRepository, emitHeadEvent bool) (status WriteStatus, err error) {
 	bc.chainmu.Lock()
 	defer bc.chainmu.Unlock()
 
-	return bc.writeBlockWithState(block, receipts, logs, state, psManager, emitHeadEvent)
+	return bc.writeBlockWithState(block, receipts, privateReceipts, logs, state, psManager, emitHeadEvent)
 }
 
 // QUORUM
 // checks if the consensus engine is Rfat
 func (bc *BlockChain) isRaft() bool {
-	return bc.chainConfig.IsQuorum && bc.chainConfig.Istanbul == nil && bc.chainConfig.Clique == nil
+	return isRaftConfig(bc.chainConfig)
 }
 
 // function specifically added for Raft consensus. This is called from mintNewBlock
@@ -1643,7 +1687,10 @@ func (bc *BlockChain) CommitBlockWithState(deleteEmptyObjects bool, state, priva
 
 // writeBlockWithState writes the block and all associated state to the database,
 // but is expects the chain mutex to be held.
-func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, psManager mps.PrivateStateRepository, emitHeadEvent bool) (status WriteStatus, err error) {
+//
+// Quorum: the private receipts are only used for the private bloom of the block, the
+// receipts are the public receipts merged with the private ones
+func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts, privateReceipts []*types.Receipt, logs []*types.Log, state *state.StateDB, psManager mps.PrivateStateRepository, emitHeadEvent bool) (status WriteStatus, err error) {
 	bc.wg.Add(1)
 	defer bc.wg.Done()
 
@@ -1653,23 +1700,43 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		return NonStatTy, consensus.ErrUnknownAncestor
 	}
 	// Make sure no inconsistent state is leaked during insertion
+
+	// Irrelevant of the canonical status, write the block itself to the database.
+	//
+	// Note all the components of block(td, hash->number map, header, body, receipts)
+	// should be written aeth/downloader/downloader.gotomically. BlockBatch is used for containing all components.
+	blockBatch := bc.db.NewBatch()
+
 	// Quorum
-	// Write private state changes to database
-	err = psManager.CommitAndWrite(bc.chainConfig.IsEIP158(block.Number()), block)
+	if bc.privateTiering != nil {
+		blockTime := block.Time()
+		if bc.isRaft() {
+			blockTime /= 1e9
+		}
+		bc.privateTiering.track(block, blockTime, privateReceipts, psManager)
+		// the contracts are only moved with the blocks extending the canonical chain
+		if block.ParentHash() == bc.CurrentBlock().Hash() {
+			bc.privateTiering.switchToColdStore(block.Hash(), psManager)
+		}
+	}
+	// Write private state changes to database, the private state roots and the private
+	// bloom are written with the block
+	err = psManager.CommitAndWrite(bc.chainConfig.IsEIP158(block.Number()), block, blockBatch)
 	if err != nil {
 		return NonStatTy, err
 	}
+	if err := rawdb.WritePrivateBlockBloom(blockBatch, block.NumberU64(), privateReceipts); err != nil {
+		return NonStatTy, err
+	}
+	if bc.indexPayloads {
+		rawdb.WritePrivatePayloadIndex(blockBatch, block)
+	}
 	// /Quorum
 
 	currentBlock := bc.CurrentBlock()
 	localTd := bc.GetTd(currentBlock.Hash(), currentBlock.NumberU64())
 	externTd := new(big.Int).Add(block.Difficulty(), ptd)
 
-	// Irrelevant of the canonical status, write the block itself to the database.
-	//
-	// Note all the components of block(td, hash->number map, header, body, receipts)
-	// should be written aeth/downloader/downloader.gotomically. BlockBatch is used for containing all components.
-	blockBatch := bc.db.NewBatch()
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
@@ -1770,6 +1837,10 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// Set new head.
 	if status == CanonStatTy {
 		bc.writeHeadBlock(block)
+
+		// Quorum: the state of the block is committed, blocks made locally were never inserted
+		txLatency.include(block)
+		txLatency.commit(block)
 	}
 	bc.futureBlocks.Remove(block.Hash())
 
@@ -1865,8 +1936,8 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		}
 		return 0, nil
 	}
-	// Start a parallel signature recovery (signer will fluke on fork transition, minimal perf loss)
-	senderCacher.recoverFromBlocks(types.MakeSigner(bc.chainConfig, chain[0].Number()), chain)
+	// Start a parallel signature recovery
+	senderCacher.recoverFromBlocks(bc.chainConfig, chain)
 
 	var (
 		stats     = insertStats{startTime: mclock.Now()}
@@ -2021,6 +2092,8 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		}
 		// Retrieve the parent block and it's state to execute on top
 		start := time.Now()
+		// Quorum
+		span := tracing.StartRoot("block.import").SetAttribute("block.number", block.NumberU64()).SetAttribute("block.hash", block.Hash().Hex()).SetAttribute("block.txs", len(block.Transactions()))
 
 		parent := it.previous()
 		if parent == nil {
@@ -2060,10 +2133,15 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		// Process block using the parent state as reference point
 		substart := time.Now()
 
+		txLatency.include(block) // Quorum
+
+		processSpan := span.Child("block.process")
 		receipts, privateReceipts, logs, usedGas, err := bc.processor.Process(block, statedb, privateStateRepo, bc.vmConfig)
+		processSpan.SetError(err).End()
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
+			span.SetError(err).End()
 			return it.index, err
 		}
 		// Update the metrics touched during block processing
@@ -2082,11 +2160,15 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 
 		// Validate the state using the default validator
 		substart = time.Now()
+		validateSpan := span.Child("block.validate")
 		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
 			bc.reportBlock(block, receipts, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
+			validateSpan.SetError(err).End()
+			span.SetError(err).End()
 			return it.index, err
 		}
+		validateSpan.End()
 
 		allReceipts := privateStateRepo.MergeReceipts(receipts, privateReceipts)
 		proctime := time.Since(start)
@@ -2098,12 +2180,12 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 
 		// Write the block to the chain and get the status.
 		substart = time.Now()
-		status, err := bc.writeBlockWithState(block, allReceipts, logs, statedb, privateStateRepo, false)
+		writeSpan := span.Child("block.write")
+		status, err := bc.writeBlockWithState(block, allReceipts, privateReceipts, logs, statedb, privateStateRepo, false)
 		atomic.StoreUint32(&followupInterrupt, 1)
+		writeSpan.SetError(err).End()
 		if err != nil {
-			return it.index, err
-		}
-		if err := rawdb.WritePrivateBlockBloom(bc.db, block.NumberU64(), privateReceipts); err != nil {
+			span.SetError(err).End()
 			return it.index, err
 		}
 		// Update the metrics touched during block commit
@@ -2113,6 +2195,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 
 		blockWriteTimer.Update(time.Since(substart) - statedb.AccountCommits - statedb.StorageCommits - statedb.SnapshotCommits)
 		blockInsertTimer.UpdateSince(start)
+		span.End()
 
 		switch status {
 		case CanonStatTy:
@@ -2771,3 +2854,15 @@ func (bc *BlockChain) SetSaveRevertReason(enabled bool) {
 func (bc *BlockChain) SaveRevertReason() bool {
 	return bc.saveRevertReason
 }
+
+// Quorum
+// SetIndexPrivatePayloads enables the index of the hashes of the private payloads of
+// the blocks written from then on, whether the node is party to the payloads or not
+func (bc *BlockChain) SetIndexPrivatePayloads(enabled bool) {
+	bc.indexPayloads = enabled
+}
+
+// Quorum
+func (bc *BlockChain) IndexPrivatePayloads() bool {
+	return bc.indexPayloads
+}
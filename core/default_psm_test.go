@@ -55,7 +55,7 @@ func TestLegacyPrivateStateCreated(t *testing.T) {
 
 		}
 		//CommitAndWrite to db
-		privateStateRepo.CommitAndWrite(false, block)
+		privateStateRepo.CommitAndWrite(false, block, blockchain.db)
 
 		for _, privateReceipt := range privateReceipts {
 			expectedContractAddress := privateReceipt.ContractAddress
@@ -9,7 +9,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 )
 
 var dualStateTestHeader = types.Header{
@@ -211,3 +213,27 @@ func TestStaticCall_whenPrivateToPublic(t *testing.T) {
 
 	verifyStaticCall(t, privateState, publicState, common.Hash{10})
 }
+
+func TestDualStatePrivateCreate2_whenPublicCollision(t *testing.T) {
+	author := common.Address{1}
+	initCode := common.Hex2Bytes("00")
+	address := crypto.CreateAddress2(author, common.Hash{}, crypto.Keccak256(initCode))
+	create2 := func(config *params.ChainConfig) error {
+		db := rawdb.NewMemoryDatabase()
+		publicState, _ := state.New(common.Hash{}, state.NewDatabase(db), nil)
+		publicState.SetCode(address, common.Hex2Bytes("600a6000526001601ff300"))
+		privateState, _ := state.New(common.Hash{}, state.NewDatabase(db), nil)
+
+		msg := callmsg{addr: author, gas: 1000000, gasPrice: new(big.Int), value: new(big.Int)}
+		env := vm.NewEVM(NewEVMContext(msg, &dualStateTestHeader, nil, &author), publicState, privateState, config, vm.Config{})
+		_, _, _, err := env.Create2(vm.AccountRef(author), initCode, msg.gas, new(big.Int), new(uint256.Int))
+		return err
+	}
+
+	if err := create2(&params.ChainConfig{}); err != nil {
+		t.Errorf("expected the public account to be shadowed before the fork, got %v", err)
+	}
+	if err := create2(&params.ChainConfig{PrivateContractCollisionBlock: big.NewInt(0)}); err != vm.ErrContractAddressCollision {
+		t.Errorf("expected %v, got %v", vm.ErrContractAddressCollision, err)
+	}
+}
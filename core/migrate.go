@@ -0,0 +1,216 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Quorum
+
+// MigrateConfig selects the conversions of MigrateChaindata
+type MigrateConfig struct {
+	// DryRun reports the legacy data without converting it
+	DryRun bool
+	// MPS converts the private states to the multiple private states layout, the
+	// private state of the node becoming the default private state
+	MPS bool
+}
+
+// MigrateSummary summarizes the legacy data of a chain database
+type MigrateSummary struct {
+	Head     uint64
+	Ancients uint64 // number of frozen blocks
+	// Receipts is the number of blocks whose receipts are in the encoding of the
+	// database version 3 or 4
+	Receipts int
+	// FrozenReceipts is the number of frozen blocks whose receipts are in a legacy
+	// encoding. The freezer is append-only: they are left as is and stay readable.
+	FrozenReceipts int
+	// PrivateStates is the number of blocks whose private state is in the legacy
+	// layout, when converting to the multiple private states layout
+	PrivateStates int
+	// Mismatches is the number of blocks whose converted private state isn't the legacy one
+	Mismatches int
+	// ChainConfig reports whether the multiple private states flag of the stored chain
+	// config is to be set
+	ChainConfig bool
+	// Converted reports whether the legacy data was converted
+	Converted bool
+}
+
+// Pending returns whether legacy data is left to convert
+func (s *MigrateSummary) Pending() bool {
+	return s.Receipts > 0 || s.PrivateStates > 0 || s.Mismatches > 0 || s.ChainConfig
+}
+
+// MigrateChaindata converts the data written by older Quorum releases, from the
+// genesis to the head block, into the current format: the receipts stored in the
+// encoding of the database version 3 or 4 and, if config.MPS is set, the private
+// states stored in the single private state layout. The legacy private state roots
+// are kept, the conversion is idempotent. The database must not be in use.
+func MigrateChaindata(db ethdb.Database, config *MigrateConfig) (*MigrateSummary, error) {
+	genesisHash := rawdb.ReadCanonicalHash(db, 0)
+	chainConfig := rawdb.ReadChainConfig(db, genesisHash)
+	if chainConfig == nil {
+		return nil, errors.New("no chain config, the database isn't initialized")
+	}
+	headHash := rawdb.ReadHeadBlockHash(db)
+	head := rawdb.ReadHeaderNumber(db, headHash)
+	if head == nil {
+		return nil, fmt.Errorf("missing number of the head block %x", headHash)
+	}
+	ancients, _ := db.Ancients() // 0 without freezer
+	summary := &MigrateSummary{Head: *head, Ancients: ancients}
+	summary.ChainConfig = config.MPS && !chainConfig.IsMPS
+
+	var (
+		batch      = db.NewBatch()
+		triedb     = trie.NewDatabase(db)
+		start      = time.Now()
+		lastLogged = time.Now()
+		flushBatch = func() error {
+			if config.DryRun || batch.ValueSize() == 0 {
+				return nil
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+			return nil
+		}
+	)
+	log.Info("Migrating the chain data", "head", *head, "ancients", ancients, "mps", config.MPS, "dryrun", config.DryRun)
+	for number := uint64(0); number <= *head; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		header := rawdb.ReadHeader(db, hash, number)
+		if header == nil {
+			return nil, fmt.Errorf("missing header of canonical block #%d %x", number, hash)
+		}
+		legacy, err := hasLegacyReceipts(rawdb.ReadReceiptsRLP(db, hash, number))
+		if err != nil {
+			return nil, fmt.Errorf("block #%d: %v", number, err)
+		}
+		switch {
+		case legacy && number < ancients:
+			summary.FrozenReceipts++
+		case legacy:
+			summary.Receipts++
+			if !config.DryRun {
+				rawdb.WriteReceipts(batch, hash, number, rawdb.ReadRawReceipts(db, hash, number))
+			}
+		}
+		if config.MPS {
+			if err := migratePrivateState(db, batch, triedb, header.Root, config.DryRun, summary); err != nil {
+				return nil, fmt.Errorf("block #%d: %v", number, err)
+			}
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := flushBatch(); err != nil {
+				return nil, err
+			}
+		}
+		if time.Since(lastLogged) > 8*time.Second {
+			log.Info("Migrating the chain data", "number", number, "receipts", summary.Receipts, "privatestates", summary.PrivateStates, "elapsed", common.PrettyDuration(time.Since(start)))
+			lastLogged = time.Now()
+		}
+	}
+	if config.DryRun {
+		return summary, nil
+	}
+	if err := flushBatch(); err != nil {
+		return nil, err
+	}
+	if summary.ChainConfig {
+		chainConfig.IsMPS = true
+		rawdb.WriteChainConfig(db, genesisHash, chainConfig)
+	}
+	rawdb.WriteDatabaseVersion(db, BlockChainVersion)
+	summary.Converted = true
+	log.Info("Migrated the chain data", "receipts", summary.Receipts, "privatestates", summary.PrivateStates, "elapsed", common.PrettyDuration(time.Since(start)))
+	return summary, nil
+}
+
+// hasLegacyReceipts returns whether one of the stored receipts of a block is in a
+// legacy encoding
+func hasLegacyReceipts(data rlp.RawValue) (bool, error) {
+	if len(data) == 0 {
+		return false, nil
+	}
+	var blobs []rlp.RawValue
+	if err := rlp.DecodeBytes(data, &blobs); err != nil {
+		return false, fmt.Errorf("invalid receipts: %v", err)
+	}
+	for _, blob := range blobs {
+		if types.IsLegacyStoredReceipt(blob) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// migratePrivateState writes the trie of private states of a block, holding its legacy
+// private state as the default private state. The empty private state stays empty.
+// A block whose trie of private states exists is checked instead.
+func migratePrivateState(db ethdb.Database, batch ethdb.KeyValueWriter, triedb *trie.Database, blockRoot common.Hash, dryRun bool, summary *MigrateSummary) error {
+	legacyRoot := rawdb.GetPrivateStateRoot(db, blockRoot)
+	if legacyRoot == (common.Hash{}) || legacyRoot == types.EmptyRootHash {
+		return nil
+	}
+	if root := rawdb.GetPrivateStatesTrieRoot(db, blockRoot); root != (common.Hash{}) {
+		tr, err := trie.NewSecure(root, triedb)
+		if err != nil {
+			summary.Mismatches++
+			return nil
+		}
+		value, err := tr.TryGet([]byte(types.DefaultPrivateStateIdentifier))
+		if err != nil || !bytes.Equal(value, legacyRoot.Bytes()) {
+			summary.Mismatches++
+		}
+		return nil
+	}
+	summary.PrivateStates++
+	if dryRun {
+		return nil
+	}
+	tr, err := trie.NewSecure(common.Hash{}, triedb)
+	if err != nil {
+		return err
+	}
+	if err := tr.TryUpdate([]byte(types.DefaultPrivateStateIdentifier), legacyRoot.Bytes()); err != nil {
+		return err
+	}
+	root, err := tr.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if err := triedb.Commit(root, false, nil); err != nil {
+		return err
+	}
+	return rawdb.WritePrivateStatesTrieRoot(batch, blockRoot, root)
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// v3StoredReceipt is the receipt encoding of the database version 3
+type v3StoredReceipt struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             types.Bloom
+	TxHash            common.Hash
+	ContractAddress   common.Address
+	Logs              []*types.LogForStorage
+	GasUsed           uint64
+}
+
+func TestMigrateChaindata(t *testing.T) {
+	chain := newVerifiedChain(t, 3)
+	db := chain.db
+	block := chain.GetBlockByNumber(2)
+	receipts := chain.GetReceiptsByHash(block.Hash())
+	require.Len(t, receipts, 1)
+	legacy := []*v3StoredReceipt{{
+		PostStateOrStatus: []byte{0x01},
+		CumulativeGasUsed: receipts[0].CumulativeGasUsed,
+		Bloom:             receipts[0].Bloom,
+		TxHash:            receipts[0].TxHash,
+		Logs:              []*types.LogForStorage{},
+		GasUsed:           receipts[0].GasUsed,
+	}}
+	data, err := rlp.EncodeToBytes(legacy)
+	require.NoError(t, err)
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, 2)
+	require.NoError(t, db.Put(append(append([]byte("r"), key...), block.Hash().Bytes()...), data)) // receipts key of the block
+	privateRoot := common.Hash{1}
+	require.NoError(t, rawdb.WritePrivateStateRoot(db, block.Root(), privateRoot))
+
+	summary, err := MigrateChaindata(db, &MigrateConfig{DryRun: true, MPS: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), summary.Head)
+	assert.Equal(t, 1, summary.Receipts)
+	assert.Equal(t, 1, summary.PrivateStates)
+	assert.True(t, summary.ChainConfig)
+	assert.False(t, summary.Converted)
+	assert.Equal(t, common.Hash{}, rawdb.GetPrivateStatesTrieRoot(db, block.Root()), "a dry run converts nothing")
+
+	summary, err = MigrateChaindata(db, &MigrateConfig{MPS: true})
+
+	require.NoError(t, err)
+	assert.True(t, summary.Converted)
+	legacyLeft, err := hasLegacyReceipts(rawdb.ReadReceiptsRLP(db, block.Hash(), 2))
+	require.NoError(t, err)
+	assert.False(t, legacyLeft)
+	migrated := rawdb.ReadRawReceipts(db, block.Hash(), 2)
+	require.Len(t, migrated, 1)
+	assert.Equal(t, types.ReceiptStatusSuccessful, migrated[0].Status)
+	assert.Equal(t, receipts[0].CumulativeGasUsed, migrated[0].CumulativeGasUsed)
+	tr, err := trie.NewSecure(rawdb.GetPrivateStatesTrieRoot(db, block.Root()), trie.NewDatabase(db))
+	require.NoError(t, err)
+	value, err := tr.TryGet([]byte(types.DefaultPrivateStateIdentifier))
+	require.NoError(t, err)
+	assert.Equal(t, privateRoot.Bytes(), value)
+	assert.True(t, rawdb.ReadChainConfig(db, chain.Genesis().Hash()).IsMPS)
+
+	summary, err = MigrateChaindata(db, &MigrateConfig{DryRun: true, MPS: true})
+
+	require.NoError(t, err)
+	assert.False(t, summary.Pending(), "the migration is idempotent")
+}
@@ -62,14 +62,15 @@ func (dpsr *DefaultPrivateStateRepository) Reset() error {
 	return dpsr.stateDB.Reset(dpsr.root)
 }
 
-// CommitAndWrite commits the private state and writes to disk
-func (dpsr *DefaultPrivateStateRepository) CommitAndWrite(isEIP158 bool, block *types.Block) error {
+// CommitAndWrite commits the private state and writes to disk, the root of the private
+// state is written to batch
+func (dpsr *DefaultPrivateStateRepository) CommitAndWrite(isEIP158 bool, block *types.Block, batch ethdb.KeyValueWriter) error {
 	privateRoot, err := dpsr.stateDB.Commit(isEIP158)
 	if err != nil {
 		return err
 	}
 
-	if err := rawdb.WritePrivateStateRoot(dpsr.db, block.Root(), privateRoot); err != nil {
+	if err := rawdb.WritePrivateStateRoot(batch, block.Root(), privateRoot); err != nil {
 		log.Error("Failed writing private state root", "err", err)
 		return err
 	}
@@ -160,7 +160,12 @@ func TestDefaultPSRCommitAndWrite(t *testing.T) {
 	}
 	assert.Equal(t, rawdb.GetPrivateStateRoot(testdb, block.Root()), common.Hash{})
 
-	psr.CommitAndWrite(false, block)
+	batch := testdb.NewBatch()
+	psr.CommitAndWrite(false, block, batch)
+
+	//private root is written with the batch of the block
+	assert.Equal(t, rawdb.GetPrivateStateRoot(testdb, block.Root()), common.Hash{})
+	assert.NoError(t, batch.Write())
 
 	//private root gets committed to db, but isn't updated on psr (only needed for commit)
 	assert.Equal(t, psr.root, common.Hash{})
@@ -11,6 +11,7 @@ import (
 	common "github.com/ethereum/go-ethereum/common"
 	state "github.com/ethereum/go-ethereum/core/state"
 	types "github.com/ethereum/go-ethereum/core/types"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
 	trie "github.com/ethereum/go-ethereum/trie"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -268,17 +269,17 @@ func (mr *MockPrivateStateRepositoryMockRecorder) Commit(isEIP158, block interfa
 }
 
 // CommitAndWrite mocks base method.
-func (m *MockPrivateStateRepository) CommitAndWrite(isEIP158 bool, block *types.Block) error {
+func (m *MockPrivateStateRepository) CommitAndWrite(isEIP158 bool, block *types.Block, batch ethdb.KeyValueWriter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CommitAndWrite", isEIP158, block)
+	ret := m.ctrl.Call(m, "CommitAndWrite", isEIP158, block, batch)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CommitAndWrite indicates an expected call of CommitAndWrite.
-func (mr *MockPrivateStateRepositoryMockRecorder) CommitAndWrite(isEIP158, block interface{}) *gomock.Call {
+func (mr *MockPrivateStateRepositoryMockRecorder) CommitAndWrite(isEIP158, block, batch interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitAndWrite", reflect.TypeOf((*MockPrivateStateRepository)(nil).CommitAndWrite), isEIP158, block)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitAndWrite", reflect.TypeOf((*MockPrivateStateRepository)(nil).CommitAndWrite), isEIP158, block, batch)
 }
 
 // Copy mocks base method.
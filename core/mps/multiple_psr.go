@@ -13,9 +13,14 @@ import (
 // MultiplePrivateStateRepository manages a number of state DB objects
 // identified by their types.PrivateStateIdentifier. It also maintains a trie
 // of private states whose root hash is mapped with a block hash.
+//
+// The private states share a single state cache with the trie of private states,
+// so the trie nodes and contract code identical across the private states are
+// cached once. They were already stored once on disk, as they are addressed by
+// their hash.
 type MultiplePrivateStateRepository struct {
 	db ethdb.Database
-	// trie of private states cache
+	// cache of the trie of private states and of the private states
 	repoCache state.Database
 
 	// the trie of private states
@@ -41,17 +46,14 @@ func NewMultiplePrivateStateRepository(db ethdb.Database, cache state.Database,
 		managedStates: make(map[types.PrivateStateIdentifier]*managedState)}, nil
 }
 
-// A managed state is a pair of stateDb and it's corresponding stateCache objects
-// Although right now we may not need a separate stateCache it may be useful if we'll do multiple managed state commits in parallel
+// A managed state is the stateDb of a private state, backed by the state cache of the repository
 type managedState struct {
-	stateDb    *state.StateDB
-	stateCache state.Database
+	stateDb *state.StateDB
 }
 
 func (ms *managedState) Copy() *managedState {
 	return &managedState{
-		stateDb:    ms.stateDb.Copy(),
-		stateCache: ms.stateCache,
+		stateDb: ms.stateDb.Copy(),
 	}
 }
 
@@ -78,7 +80,6 @@ func (mpsr *MultiplePrivateStateRepository) StatePSI(psi types.PrivateStateIdent
 	if err != nil {
 		return nil, err
 	}
-	var stateCache state.Database
 	var stateDB *state.StateDB
 	if privateStateRoot == nil && psi != EmptyPrivateStateMetadata.ID {
 		// this is the first time we are trying to use this private state so branch from the empty state
@@ -86,15 +87,9 @@ func (mpsr *MultiplePrivateStateRepository) StatePSI(psi types.PrivateStateIdent
 		if err != nil {
 			return nil, err
 		}
-		mpsr.mux.Lock()
-		ms := mpsr.managedStates[EmptyPrivateStateMetadata.ID]
-		mpsr.mux.Unlock()
-
 		stateDB = emptyState.Copy()
-		stateCache = ms.stateCache
 	} else {
-		stateCache = state.NewDatabase(mpsr.db)
-		stateDB, err = state.New(common.BytesToHash(privateStateRoot), stateCache, nil)
+		stateDB, err = state.New(common.BytesToHash(privateStateRoot), mpsr.repoCache, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -102,8 +97,7 @@ func (mpsr *MultiplePrivateStateRepository) StatePSI(psi types.PrivateStateIdent
 	mpsr.mux.Lock()
 	defer mpsr.mux.Unlock()
 	mpsr.managedStates[psi] = &managedState{
-		stateCache: stateCache,
-		stateDb:    stateDB,
+		stateDb: stateDB,
 	}
 	return stateDB, nil
 }
@@ -129,10 +123,13 @@ func (mpsr *MultiplePrivateStateRepository) Reset() error {
 	return nil
 }
 
-// commitAndWrite- commits all private states, updates the trie of private states, writes to disk
-func (mpsr *MultiplePrivateStateRepository) CommitAndWrite(isEIP158 bool, block *types.Block) error {
+// commitAndWrite- commits all private states, updates the trie of private states, writes to disk.
+// The nodes shared by several private states are flushed by the first commit reaching them.
+// The root of the trie of private states is written to batch.
+func (mpsr *MultiplePrivateStateRepository) CommitAndWrite(isEIP158 bool, block *types.Block, batch ethdb.KeyValueWriter) error {
 	mpsr.mux.Lock()
 	defer mpsr.mux.Unlock()
+	privateTriedb := mpsr.repoCache.TrieDB()
 	for psi, managedState := range mpsr.managedStates {
 		// commit each managed state
 		privateRoot, err := managedState.stateDb.Commit(isEIP158)
@@ -144,7 +141,7 @@ func (mpsr *MultiplePrivateStateRepository) CommitAndWrite(isEIP158 bool, block
 		if err != nil {
 			return err
 		}
-		err = managedState.stateCache.TrieDB().Commit(privateRoot, false, nil)
+		err = privateTriedb.Commit(privateRoot, false, nil)
 		if err != nil {
 			return err
 		}
@@ -154,11 +151,10 @@ func (mpsr *MultiplePrivateStateRepository) CommitAndWrite(isEIP158 bool, block
 	if err != nil {
 		return err
 	}
-	err = rawdb.WritePrivateStatesTrieRoot(mpsr.db, block.Root(), mtRoot)
+	err = rawdb.WritePrivateStatesTrieRoot(batch, block.Root(), mtRoot)
 	if err != nil {
 		return err
 	}
-	privateTriedb := mpsr.repoCache.TrieDB()
 	err = privateTriedb.Commit(mtRoot, false, nil)
 	return err
 }
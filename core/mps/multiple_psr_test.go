@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -127,7 +128,7 @@ func TestMultiplePSRReset(t *testing.T) {
 	emptyState.AddBalance(addr, big.NewInt(int64(254)))
 
 	// have something to revert to (rather than the empty trie of private states)
-	psr.CommitAndWrite(false, types.NewBlockWithHeader(&types.Header{Root: common.Hash{}}))
+	psr.CommitAndWrite(false, types.NewBlockWithHeader(&types.Header{Root: common.Hash{}}), testdb)
 
 	// testState2 should branch from the emptyState - so it should contain the contract with address 254...
 	testState2, _ := psr.StatePSI(types.PrivateStateIdentifier("test2"))
@@ -258,7 +259,7 @@ func TestMultiplePSRCommitAndWrite(t *testing.T) {
 
 	assert.Equal(t, rawdb.GetPrivateStatesTrieRoot(testdb, block.Root()), common.Hash{})
 
-	psr.CommitAndWrite(false, block)
+	psr.CommitAndWrite(false, block, testdb)
 
 	//trie root updated and committed to db
 	assert.NotEqual(t, psr.trie.Hash(), common.Hash{})
@@ -312,7 +313,7 @@ func TestMultiplePSRIntroduceNewPrivateState(t *testing.T) {
 
 	assert.Equal(t, rawdb.GetPrivateStatesTrieRoot(testdb, block1.Root()), common.Hash{})
 
-	psr.CommitAndWrite(false, block1)
+	psr.CommitAndWrite(false, block1, testdb)
 
 	//trie root updated and committed to db
 	psrRootHash1 := psr.trie.Hash()
@@ -347,7 +348,7 @@ func TestMultiplePSRIntroduceNewPrivateState(t *testing.T) {
 		emptyState.AddBalance(addr, big.NewInt(int64(i)))
 	}
 
-	psr.CommitAndWrite(false, block2)
+	psr.CommitAndWrite(false, block2, testdb)
 
 	psr, _ = NewMultiplePrivateStateRepository(testdb, testCache, rawdb.GetPrivateStatesTrieRoot(testdb, block2.Root()))
 
@@ -409,7 +410,7 @@ func TestMultiplePSRRemovalFromPrivateState(t *testing.T) {
 
 	assert.Equal(t, rawdb.GetPrivateStatesTrieRoot(testdb, block1.Root()), common.Hash{})
 
-	psr.CommitAndWrite(false, block1)
+	psr.CommitAndWrite(false, block1, testdb)
 
 	psr, _ = NewMultiplePrivateStateRepository(testdb, testCache, rawdb.GetPrivateStatesTrieRoot(testdb, block1.Root()))
 
@@ -419,7 +420,7 @@ func TestMultiplePSRRemovalFromPrivateState(t *testing.T) {
 	removedAddress := common.BytesToAddress([]byte{1})
 	testState1.Suicide(removedAddress)
 
-	psr.CommitAndWrite(false, block2)
+	psr.CommitAndWrite(false, block2, testdb)
 
 	psr, _ = NewMultiplePrivateStateRepository(testdb, testCache, rawdb.GetPrivateStatesTrieRoot(testdb, block2.Root()))
 	testState1, _ = psr.StatePSI(testPS1)
@@ -428,3 +429,33 @@ func TestMultiplePSRRemovalFromPrivateState(t *testing.T) {
 	assert.False(t, testState1.Exist(removedAddress))
 	assert.True(t, emptyState.Exist(removedAddress))
 }
+
+//TestMultiplePSRSharesStateCache tests that the private states share the state cache of the repository,
+// so that the code identical across the private states is cached once
+func TestMultiplePSRSharesStateCache(t *testing.T) {
+	testdb := rawdb.NewMemoryDatabase()
+	testCache := state.NewDatabase(testdb)
+	psr, _ := NewMultiplePrivateStateRepository(testdb, testCache, common.Hash{})
+	header := &types.Header{Number: big.NewInt(int64(1)), Root: common.Hash{123}}
+	block := types.NewBlockWithHeader(header)
+
+	addr := common.BytesToAddress([]byte{1})
+	code := []byte{0x60, 0x01, 0x60, 0x02}
+	for _, psi := range []types.PrivateStateIdentifier{"tenantA", "tenantB"} {
+		s, err := psr.StatePSI(psi)
+		assert.NoError(t, err)
+		s.SetCode(addr, code)
+	}
+	assert.NoError(t, psr.CommitAndWrite(false, block))
+
+	// tenantA loads the code in the cache, tenantB no longer reads it from disk
+	stateA, err := psr.StatePSI(types.PrivateStateIdentifier("tenantA"))
+	assert.NoError(t, err)
+	assert.Equal(t, code, stateA.GetCode(addr))
+	rawdb.DeleteCode(testdb, crypto.Keccak256Hash(code))
+	stateB, err := psr.StatePSI(types.PrivateStateIdentifier("tenantB"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, code, stateB.GetCode(addr))
+	assert.NoError(t, stateB.Error())
+}
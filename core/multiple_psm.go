@@ -16,7 +16,8 @@ import (
 
 type MultiplePrivateStateManager struct {
 	// Low level persistent database to store final content in
-	db                     ethdb.Database
+	db ethdb.Database
+	// cache of the trie of private states, shared by all the private states
 	privateStatesTrieCache state.Database
 
 	residentGroupByKey map[string]*mps.PrivateStateMetadata
@@ -26,7 +27,7 @@ type MultiplePrivateStateManager struct {
 func newMultiplePrivateStateManager(db ethdb.Database, cacheConfig *CacheConfig, residentGroupByKey map[string]*mps.PrivateStateMetadata, privacyGroupById map[types.PrivateStateIdentifier]*mps.PrivateStateMetadata) (*MultiplePrivateStateManager, error) {
 	return &MultiplePrivateStateManager{
 		db:                     db,
-		privateStatesTrieCache: state.NewDatabaseWithCache(db, cacheConfig.TrieCleanLimit, cacheConfig.TrieCleanJournal),
+		privateStatesTrieCache: newPrivateStateCache(db, cacheConfig, cacheConfig.TrieCleanJournal),
 		residentGroupByKey:     residentGroupByKey,
 		privacyGroupById:       privacyGroupById,
 	}, nil
@@ -116,7 +116,7 @@ func TestMultiplePSMRStateCreated(t *testing.T) {
 
 		}
 		//CommitAndWrite to db
-		privateStateRepo.CommitAndWrite(false, block)
+		privateStateRepo.CommitAndWrite(false, block, blockchain.db)
 
 		//managed states test
 		for _, privateReceipt := range privateReceipts {
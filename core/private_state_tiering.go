@@ -0,0 +1,392 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+
+// coldStateScanPeriod is the interval, in block time, between the scans of the
+// private contracts inactive for long enough to be moved to the cold store
+const coldStateScanPeriod = uint64(time.Hour / time.Second)
+
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// newPrivateStateCache returns the cache of the private states, loaded from the journal,
+// whose contracts moved to the cold store are restored on access
+func newPrivateStateCache(db ethdb.Database, cacheConfig *CacheConfig, journal string) state.Database {
+	cache := state.NewDatabaseWithCache(db, cacheConfig.TrieCleanLimit, journal)
+	if cacheConfig.PrivateColdStore != nil {
+		return state.NewDatabaseWithColdStore(cache, state.NewColdStore(cacheConfig.PrivateColdStore))
+	}
+	return cache
+}
+
+const (
+	// maxColdMovesPerBlock bounds the contracts moved to the cold store with a block
+	maxColdMovesPerBlock = 64
+	// maxColdMovesPerScan bounds the contracts copied to the cold store by a scan,
+	// the other inactive contracts are copied by the next scans
+	maxColdMovesPerScan = 4096
+	// pendingActivityDepth is the number of blocks below the chain head after which
+	// the activity of the blocks left on a side chain is dropped
+	pendingActivityDepth = 128
+)
+
+// privateStateTiering moves the storage of the private contracts inactive for longer
+// than the configured period to the cold store. The storage trie nodes are left in the
+// state database, they are only dropped by a resync: the tiering doesn't reduce the
+// disk usage of the node.
+//
+// A contract is active when it is accessed by a private transaction, e.g. as the
+// target or through an internal call, or it is created or emits events in a private
+// transaction. Its storage is restored from the cold store the next time it is
+// accessed, with the latency of the restore.
+//
+// The activity of the blocks is recorded once they are canonical, and the private
+// states are scanned for inactive contracts in the background, fed by the chain head
+// events. The storage of the inactive contracts is copied to the cold store by the
+// scans, and only switched out of the private states with the next blocks, a bounded
+// number per block.
+//
+// The contracts moved depend on when the node scanned its private states, so the
+// private state roots differ from the ones of the other nodes. The moves are recorded
+// with the block, and applied again when the block is re-executed, e.g. by verify-db.
+type privateStateTiering struct {
+	db    ethdb.KeyValueStore // cold store, holding the last activity of the contracts
+	after uint64              // inactivity, in seconds, after which the storage is moved
+
+	mu      sync.Mutex
+	pending map[common.Hash]*blockActivity // activity of the blocks written, recorded once canonical
+	moves   []coldMove                     // storage copied to the cold store, switched with the next blocks
+
+	lastScan uint64 // block time of the last scan, used by the loop only
+}
+
+// blockActivity is the activity of the private contracts in a block
+type blockActivity struct {
+	number    uint64
+	parent    common.Hash
+	time      uint64
+	contracts map[types.PrivateStateIdentifier]map[common.Address]struct{}
+}
+
+// coldMove is the storage of a contract copied to the cold store, to be switched out
+// of its private state
+type coldMove struct {
+	psi  types.PrivateStateIdentifier
+	addr common.Address
+	root common.Hash
+}
+
+func newPrivateStateTiering(cacheConfig *CacheConfig) *privateStateTiering {
+	if cacheConfig.PrivateColdStore == nil || cacheConfig.PrivateColdAfter <= 0 {
+		return nil
+	}
+	return &privateStateTiering{
+		db:      cacheConfig.PrivateColdStore,
+		after:   uint64(cacheConfig.PrivateColdAfter / time.Second),
+		pending: make(map[common.Hash]*blockActivity),
+	}
+}
+
+// track keeps the activity of the contracts in the private states of a block written,
+// until the block is canonical. It must be called before the private states are committed.
+func (t *privateStateTiering) track(block *types.Block, blockTime uint64, privateReceipts []*types.Receipt, repo mps.PrivateStateRepository) {
+	contracts := activeContracts(block, privateReceipts)
+	for psi, active := range contracts {
+		statedb, err := repo.StatePSI(psi)
+		if err != nil {
+			continue
+		}
+		for _, addr := range statedb.AccessedContracts() {
+			active[addr] = struct{}{}
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[block.Hash()] = &blockActivity{number: block.NumberU64(), parent: block.ParentHash(), time: blockTime, contracts: contracts}
+}
+
+// switchToColdStore moves the storage copied to the cold store by the last scan out of
+// the private states of a block, at most maxColdMovesPerBlock contracts, and records
+// the moves of the block. It must be called before the private states are committed.
+func (t *privateStateTiering) switchToColdStore(hash common.Hash, repo mps.PrivateStateRepository) {
+	t.mu.Lock()
+	n := len(t.moves)
+	if n > maxColdMovesPerBlock {
+		n = maxColdMovesPerBlock
+	}
+	moves := t.moves[:n]
+	t.moves = t.moves[n:]
+	t.mu.Unlock()
+
+	var moved []rawdb.ColdMove
+	for _, m := range moves {
+		statedb, err := repo.StatePSI(m.psi)
+		if err != nil {
+			log.Warn("Failed to move an inactive private contract to the cold store", "psi", m.psi, "address", m.addr, "err", err)
+			continue
+		}
+		ok, err := statedb.SwitchStorageToColdStore(m.addr, m.root)
+		if err != nil {
+			log.Warn("Failed to move an inactive private contract to the cold store", "psi", m.psi, "address", m.addr, "err", err)
+			continue
+		}
+		if ok {
+			moved = append(moved, rawdb.ColdMove{PSI: m.psi.String(), Address: m.addr, Root: m.root})
+		}
+	}
+	if len(moved) == 0 {
+		return
+	}
+	if err := rawdb.WriteColdMoves(t.db, hash, moved); err != nil {
+		log.Warn("Failed to record the private contracts moved to the cold store", "err", err)
+	}
+	log.Debug("Moved inactive private contracts to the cold store", "count", len(moved))
+}
+
+// replayColdMoves moves the private contracts moved with a block out of its private
+// states again, when the block is re-executed
+func replayColdMoves(db ethdb.KeyValueReader, hash common.Hash, repo mps.PrivateStateRepository) error {
+	moves, err := rawdb.ReadColdMoves(db, hash)
+	if err != nil {
+		return err
+	}
+	for _, m := range moves {
+		statedb, err := repo.StatePSI(types.PrivateStateIdentifier(m.PSI))
+		if err != nil {
+			return err
+		}
+		if ok, err := statedb.SwitchStorageToColdStore(m.Address, m.Root); err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("private contract %x of %s can't be moved to the cold store", m.Address, m.PSI)
+		}
+	}
+	return nil
+}
+
+// loop records the activity of the canonical blocks and scans the private states in
+// the background, once per scan period, fed by the chain head events
+func (t *privateStateTiering) loop(bc *BlockChain) {
+	defer bc.wg.Done()
+
+	headCh := make(chan ChainHeadEvent, chainHeadChanSize)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	var scanDone chan struct{} // nil unless a scan is running
+	for {
+		select {
+		case ev := <-headCh:
+			head := ev.Block
+			t.record(head)
+			blockTime := head.Time()
+			if bc.isRaft() {
+				blockTime /= 1e9
+			}
+			if scanDone != nil || blockTime < t.lastScan+coldStateScanPeriod {
+				continue
+			}
+			t.lastScan = blockTime
+			done := make(chan struct{})
+			scanDone = done
+			go func() {
+				defer close(done)
+				t.scan(bc, head, blockTime)
+			}()
+		case <-scanDone:
+			scanDone = nil
+		case <-sub.Err():
+			return
+		case <-bc.quit:
+			if scanDone != nil {
+				<-scanDone
+			}
+			return
+		}
+	}
+}
+
+// record writes the activity of the blocks made canonical by the chain head, and
+// drops the activity of the blocks left on side chains
+func (t *privateStateTiering) record(head *types.Block) {
+	var canonical []*blockActivity
+	t.mu.Lock()
+	for hash := head.Hash(); ; {
+		activity, ok := t.pending[hash]
+		if !ok {
+			break
+		}
+		delete(t.pending, hash)
+		canonical = append(canonical, activity)
+		hash = activity.parent
+	}
+	for hash, activity := range t.pending {
+		if activity.number+pendingActivityDepth < head.NumberU64() {
+			delete(t.pending, hash)
+		}
+	}
+	t.mu.Unlock()
+
+	batch := t.db.NewBatch()
+	// from the oldest block, so that the last activity is kept
+	for i := len(canonical) - 1; i >= 0; i-- {
+		for psi, contracts := range canonical[i].contracts {
+			for addr := range contracts {
+				rawdb.WriteContractActivity(batch, psi.String(), addr, canonical[i].time)
+			}
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Warn("Failed to record the activity of the private contracts", "err", err)
+	}
+}
+
+// scan copies the storage of the inactive contracts of the private states at the chain
+// head to the cold store, to be switched with the next blocks. The first scan of a
+// private state records the existing contracts as active.
+func (t *privateStateTiering) scan(bc *BlockChain, head *types.Block, blockTime uint64) {
+	repo, err := bc.privateStateManager.StateRepository(head.Root())
+	if err != nil {
+		log.Warn("Failed to scan the private states for inactive contracts", "err", err)
+		return
+	}
+	var moves []coldMove
+	for _, psi := range bc.privateStateManager.PSIs() {
+		statedb, err := repo.StatePSI(psi)
+		if err != nil {
+			log.Warn("Failed to scan the private state for inactive contracts", "psi", psi, "err", err)
+			continue
+		}
+		if !rawdb.ReadColdStateSeeded(t.db, psi.String()) {
+			t.seed(psi.String(), statedb, blockTime, bc.quit)
+			continue
+		}
+		moves = t.copyInactive(psi, statedb, blockTime, moves, bc.quit)
+	}
+	t.mu.Lock()
+	t.moves = moves
+	t.mu.Unlock()
+	if len(moves) > 0 {
+		log.Info("Moving inactive private contracts to the cold store", "count", len(moves))
+	}
+}
+
+// copyInactive copies the storage of the inactive contracts of a private state to the
+// cold store, until there are maxColdMovesPerScan moves. The activity of the contracts
+// which can't be moved is forgotten, until they are accessed again.
+func (t *privateStateTiering) copyInactive(psi types.PrivateStateIdentifier, statedb *state.StateDB, blockTime uint64, moves []coldMove, quit <-chan struct{}) []coldMove {
+	batch := t.db.NewBatch()
+	defer func() {
+		if err := batch.Write(); err != nil {
+			log.Warn("Failed to record the activity of the private contracts", "err", err)
+		}
+	}()
+	for addr, lastActive := range rawdb.ReadContractActivities(t.db, psi.String()) {
+		if len(moves) >= maxColdMovesPerScan {
+			return moves
+		}
+		if blockTime < lastActive+t.after {
+			continue
+		}
+		select {
+		case <-quit:
+			return moves
+		default:
+		}
+		root, ok, err := statedb.CopyStorageToColdStore(addr)
+		if err != nil {
+			log.Warn("Failed to copy a private contract to the cold store", "psi", psi, "address", addr, "err", err)
+			continue
+		}
+		if !ok {
+			// moved already, without storage or with enhanced privacy
+			rawdb.DeleteContractActivity(batch, psi.String(), addr)
+			continue
+		}
+		moves = append(moves, coldMove{psi: psi, addr: addr, root: root})
+	}
+	return moves
+}
+
+// seed records the contracts of a private state as active
+func (t *privateStateTiering) seed(psi string, statedb *state.StateDB, blockTime uint64, quit <-chan struct{}) {
+	batch := t.db.NewBatch()
+	var start []byte
+	for {
+		select {
+		case <-quit:
+			return
+		default:
+		}
+		dump := state.IteratorDump{Accounts: make(map[common.Address]state.DumpAccount)}
+		start = statedb.DumpToCollector(&dump, true, true, true, start, 1000)
+		for addr, account := range dump.Accounts {
+			if common.HexToHash(account.CodeHash) != emptyCodeHash {
+				rawdb.WriteContractActivity(batch, psi, addr, blockTime)
+			}
+		}
+		if start == nil {
+			break
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Warn("Failed to record the activity of the private contracts", "err", err)
+				return
+			}
+			batch.Reset()
+		}
+	}
+	rawdb.WriteColdStateSeeded(batch, psi)
+	if err := batch.Write(); err != nil {
+		log.Warn("Failed to record the activity of the private contracts", "err", err)
+	}
+}
+
+// activeContracts returns the contracts of each private state which are the target of
+// a private transaction of the block, or are created or emit events in one
+func activeContracts(block *types.Block, privateReceipts []*types.Receipt) map[types.PrivateStateIdentifier]map[common.Address]struct{} {
+	targets := make(map[common.Hash]*common.Address)
+	for _, tx := range block.Transactions() {
+		if tx.IsPrivate() {
+			targets[tx.Hash()] = tx.To()
+		}
+	}
+	active := make(map[types.PrivateStateIdentifier]map[common.Address]struct{})
+	for _, receipt := range privateReceipts {
+		psReceipts := receipt.PSReceipts
+		if len(psReceipts) == 0 {
+			psReceipts = map[types.PrivateStateIdentifier]*types.Receipt{types.DefaultPrivateStateIdentifier: receipt}
+		}
+		for psi, psReceipt := range psReceipts {
+			contracts := active[psi]
+			if contracts == nil {
+				contracts = make(map[common.Address]struct{})
+				active[psi] = contracts
+			}
+			if to := targets[receipt.TxHash]; to != nil {
+				contracts[*to] = struct{}{}
+			}
+			if psReceipt.ContractAddress != (common.Address{}) {
+				contracts[psReceipt.ContractAddress] = struct{}{}
+			}
+			for _, l := range psReceipt.Logs {
+				contracts[l.Address] = struct{}{}
+			}
+		}
+	}
+	return active
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveContracts(t *testing.T) {
+	target, created, emitter := common.Address{1}, common.Address{2}, common.Address{3}
+	privateTx := types.NewTransaction(0, target, big.NewInt(0), 0, nil, nil)
+	privateTx.SetPrivate()
+	publicTx := types.NewTransaction(1, common.Address{4}, big.NewInt(0), 0, nil, nil)
+	block := types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{privateTx, publicTx}, nil)
+	receipts := []*types.Receipt{{
+		TxHash: privateTx.Hash(),
+		PSReceipts: map[types.PrivateStateIdentifier]*types.Receipt{
+			"psi1": {ContractAddress: created},
+			"psi2": {Logs: []*types.Log{{Address: emitter}}},
+		},
+	}}
+
+	active := activeContracts(block, receipts)
+
+	assert.Equal(t, map[types.PrivateStateIdentifier]map[common.Address]struct{}{
+		"psi1": {target: {}, created: {}},
+		"psi2": {target: {}, emitter: {}},
+	}, active)
+}
+
+func TestPrivateStateTiering_copyInactive(t *testing.T) {
+	coldDb := rawdb.NewMemoryDatabase()
+	db := state.NewDatabaseWithColdStore(state.NewDatabase(rawdb.NewMemoryDatabase()), state.NewColdStore(coldDb))
+	contract := common.Address{1}
+	statedb, _ := state.New(common.Hash{}, db, nil)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetState(contract, common.Hash{1}, common.Hash{1})
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	tiering := &privateStateTiering{db: coldDb, after: 100}
+
+	statedb, _ = state.New(root, db, nil)
+	tiering.seed("psi", statedb, 1000, nil)
+	assert.Equal(t, map[common.Address]uint64{contract: 1000}, rawdb.ReadContractActivities(coldDb, "psi"), "the existing contracts are recorded as active")
+
+	assert.Empty(t, tiering.copyInactive("psi", statedb, 1050, nil, nil), "the contract is active")
+
+	moves := tiering.copyInactive("psi", statedb, 1100, nil, nil)
+	require.Len(t, moves, 1)
+	coldRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	assert.Equal(t, root, coldRoot, "the storage is only copied to the cold store")
+
+	moved, err := statedb.SwitchStorageToColdStore(moves[0].addr, moves[0].root)
+	require.NoError(t, err)
+	require.True(t, moved)
+	coldRoot, err = statedb.Commit(true)
+	require.NoError(t, err)
+	assert.NotEqual(t, root, coldRoot, "the inactive contract is moved to the cold store")
+
+	assert.Empty(t, tiering.copyInactive("psi", statedb, 1200, nil, nil))
+	assert.Empty(t, rawdb.ReadContractActivities(coldDb, "psi"), "the activity of the moved contract is forgotten")
+}
+
+func TestPrivateStateTiering_record(t *testing.T) {
+	coldDb := rawdb.NewMemoryDatabase()
+	tiering := newPrivateStateTiering(&CacheConfig{PrivateColdStore: coldDb, PrivateColdAfter: time.Hour})
+	contract, sideContract := common.Address{1}, common.Address{2}
+	block1 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	block2 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: block1.Hash()})
+	side2 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: block1.Hash(), Extra: []byte("side")})
+	activity := func(block *types.Block, time uint64, addr common.Address) *blockActivity {
+		return &blockActivity{number: block.NumberU64(), parent: block.ParentHash(), time: time, contracts: map[types.PrivateStateIdentifier]map[common.Address]struct{}{
+			types.DefaultPrivateStateIdentifier: {addr: {}},
+		}}
+	}
+	tiering.pending[block1.Hash()] = activity(block1, 10, contract)
+	tiering.pending[block2.Hash()] = activity(block2, 20, contract)
+	tiering.pending[side2.Hash()] = activity(side2, 20, sideContract)
+
+	tiering.record(block2)
+
+	assert.Equal(t, map[common.Address]uint64{contract: 20}, rawdb.ReadContractActivities(coldDb, "private"), "only the activity of the canonical blocks is recorded")
+	assert.Len(t, tiering.pending, 1)
+
+	tiering.record(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2 + pendingActivityDepth + 1)}))
+	assert.Empty(t, tiering.pending, "the activity of the side chain is dropped")
+}
+
+func TestPrivateStateTiering_switchToColdStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := state.NewDatabaseWithColdStore(state.NewDatabase(rawdb.NewMemoryDatabase()), state.NewColdStore(rawdb.NewMemoryDatabase()))
+	statedb, _ := state.New(common.Hash{}, db, nil)
+	repo := mps.NewMockPrivateStateRepository(ctrl)
+	repo.EXPECT().StatePSI(types.DefaultPrivateStateIdentifier).Return(statedb, nil).AnyTimes()
+	tiering := &privateStateTiering{}
+	for i := 0; i < maxColdMovesPerBlock+10; i++ {
+		tiering.moves = append(tiering.moves, coldMove{psi: types.DefaultPrivateStateIdentifier, addr: common.BigToAddress(big.NewInt(int64(i)))})
+	}
+
+	tiering.switchToColdStore(common.Hash{1}, repo)
+
+	assert.Len(t, tiering.moves, 10, "the moves are bounded per block")
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Quorum
+
+// backupVersion is the version of the database backup encoding
+const backupVersion = 1
+
+// backupHeader starts a database backup, it is followed by the ancient
+// blocks then by the key/value entries of the database.
+type backupHeader struct {
+	Version  uint64
+	Ancients uint64
+}
+
+type backupAncient struct {
+	Hash, Header, Body, Receipts, Td []byte
+}
+
+type backupEntry struct {
+	Key, Value []byte
+}
+
+// ExportDatabase writes to w the first ancients blocks of the ancient store and the
+// key/value entries of it. The iterator should be created before the number of
+// ancients is read, so that blocks moved to the ancient store in between are exported
+// twice rather than lost.
+func ExportDatabase(w io.Writer, it ethdb.Iterator, db ethdb.AncientReader, ancients uint64) error {
+	if err := rlp.Encode(w, &backupHeader{Version: backupVersion, Ancients: ancients}); err != nil {
+		return err
+	}
+	for number := uint64(0); number < ancients; number++ {
+		var (
+			entry backupAncient
+			err   error
+		)
+		for _, item := range []struct {
+			kind  string
+			value *[]byte
+		}{
+			{freezerHashTable, &entry.Hash},
+			{freezerHeaderTable, &entry.Header},
+			{freezerBodiesTable, &entry.Body},
+			{freezerReceiptTable, &entry.Receipts},
+			{freezerDifficultyTable, &entry.Td},
+		} {
+			if *item.value, err = db.Ancient(item.kind, number); err != nil {
+				return fmt.Errorf("ancient %s #%d: %v", item.kind, number, err)
+			}
+		}
+		if err := rlp.Encode(w, &entry); err != nil {
+			return err
+		}
+	}
+	for it.Next() {
+		if err := rlp.Encode(w, &backupEntry{Key: it.Key(), Value: it.Value()}); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// ImportDatabase restores into db a backup written by ExportDatabase, db is expected to be empty.
+func ImportDatabase(r io.Reader, db ethdb.Database) error {
+	stream := rlp.NewStream(r, 0)
+	var header backupHeader
+	if err := stream.Decode(&header); err != nil {
+		return err
+	}
+	if header.Version != backupVersion {
+		return fmt.Errorf("unsupported backup version %d", header.Version)
+	}
+	for number := uint64(0); number < header.Ancients; number++ {
+		var entry backupAncient
+		if err := stream.Decode(&entry); err != nil {
+			return fmt.Errorf("ancient #%d: %v", number, err)
+		}
+		if err := db.AppendAncient(number, entry.Hash, entry.Header, entry.Body, entry.Receipts, entry.Td); err != nil {
+			return err
+		}
+	}
+	batch := db.NewBatch()
+	for {
+		var entry backupEntry
+		if err := stream.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		if err := batch.Put(entry.Key, entry.Value); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if header.Ancients > 0 {
+		return db.Sync()
+	}
+	return nil
+}
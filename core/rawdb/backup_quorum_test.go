@@ -0,0 +1,37 @@
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportDatabase(t *testing.T) {
+	db := NewMemoryDatabase()
+	WriteCanonicalHash(db, common.HexToHash("0x01"), 1)
+	WriteHeadBlockHash(db, common.HexToHash("0x01"))
+	WritePrivateStateRoot(db, common.HexToHash("0x02"), common.HexToHash("0x03"))
+	var buf bytes.Buffer
+	it := db.NewIterator(nil, nil)
+	ancients, _ := db.Ancients() // the memory database has no ancient store
+
+	require.NoError(t, ExportDatabase(&buf, it, db, ancients))
+	it.Release()
+
+	restored := NewMemoryDatabase()
+	require.NoError(t, ImportDatabase(&buf, restored))
+	assert.Equal(t, common.HexToHash("0x01"), ReadCanonicalHash(restored, 1))
+	assert.Equal(t, common.HexToHash("0x01"), ReadHeadBlockHash(restored))
+	assert.Equal(t, common.HexToHash("0x03"), GetPrivateStateRoot(restored, common.HexToHash("0x02")))
+}
+
+func TestImportDatabase_whenUnsupportedVersion(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ExportDatabase(&buf, NewMemoryDatabase().NewIterator(nil, nil), nil, 0))
+	buf.Bytes()[1] = backupVersion + 1
+
+	assert.EqualError(t, ImportDatabase(&buf, NewMemoryDatabase()), "unsupported backup version 2")
+}
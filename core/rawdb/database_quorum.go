@@ -21,9 +21,16 @@
 package rawdb
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 var (
@@ -31,6 +38,13 @@ var (
 	privateStatesTrieRootPrefix = []byte("PSTP")
 	privateBloomPrefix          = []byte("Pb")
 	quorumEIP155ActivatedPrefix = []byte("quorum155active")
+	asyncCallbackPrefix         = []byte("quorum-async-callback-") // asyncCallbackPrefix + id (uint64 big endian) -> pending callback
+	persistentFilterPrefix      = []byte("quorum-filter-")         // persistentFilterPrefix + filter id -> persistent log filter
+	coldStoragePrefix           = []byte("quorum-cold-storage-")   // coldStoragePrefix + storage root -> compressed storage of a private contract
+	coldActivityPrefix          = []byte("quorum-cold-activity-")  // coldActivityPrefix + psi + address -> last activity of a private contract (uint64 big endian)
+	coldSeededPrefix            = []byte("quorum-cold-seeded-")    // coldSeededPrefix + psi -> flag set once the activity of the private state is recorded
+	coldMovesPrefix             = []byte("quorum-cold-moves-")     // coldMovesPrefix + block hash -> private contracts moved to the cold store with the block
+	privatePayloadIndexPrefix   = []byte("quorum-payload-index-")  // privatePayloadIndexPrefix + payload hash + num (uint64 big endian) + tx hash -> private payload inclusion
 	// Quorum
 	// we introduce a generic approach to store extra data for an account. PrivacyMetadata is wrapped.
 	// However, this value is kept as-is to support backward compatibility
@@ -51,6 +65,201 @@ func WriteQuorumEIP155Activation(db ethdb.KeyValueWriter) error {
 	return db.Put(quorumEIP155ActivatedPrefix, []byte{1})
 }
 
+// WriteAsyncCallback stores a pending eth_sendTransactionAsync callback
+func WriteAsyncCallback(db ethdb.KeyValueWriter, id uint64, data []byte) error {
+	return db.Put(append(asyncCallbackPrefix, encodeBlockNumber(id)...), data)
+}
+
+// DeleteAsyncCallback removes a pending eth_sendTransactionAsync callback
+func DeleteAsyncCallback(db ethdb.KeyValueWriter, id uint64) error {
+	return db.Delete(append(asyncCallbackPrefix, encodeBlockNumber(id)...))
+}
+
+// ReadAsyncCallbacks retrieves all pending eth_sendTransactionAsync callbacks, keyed by id
+func ReadAsyncCallbacks(db ethdb.Iteratee) map[uint64][]byte {
+	it := db.NewIterator(asyncCallbackPrefix, nil)
+	defer it.Release()
+
+	callbacks := make(map[uint64][]byte)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(asyncCallbackPrefix)+8 {
+			continue
+		}
+		callbacks[binary.BigEndian.Uint64(key[len(asyncCallbackPrefix):])] = common.CopyBytes(it.Value())
+	}
+	return callbacks
+}
+
+// WritePersistentFilter stores a log filter surviving node restarts
+func WritePersistentFilter(db ethdb.KeyValueWriter, id string, data []byte) error {
+	return db.Put(append(persistentFilterPrefix, id...), data)
+}
+
+// DeletePersistentFilter removes a log filter surviving node restarts
+func DeletePersistentFilter(db ethdb.KeyValueWriter, id string) error {
+	return db.Delete(append(persistentFilterPrefix, id...))
+}
+
+// ReadPersistentFilters retrieves all log filters surviving node restarts, keyed by id
+func ReadPersistentFilters(db ethdb.Iteratee) map[string][]byte {
+	it := db.NewIterator(persistentFilterPrefix, nil)
+	defer it.Release()
+
+	filters := make(map[string][]byte)
+	for it.Next() {
+		filters[string(it.Key()[len(persistentFilterPrefix):])] = common.CopyBytes(it.Value())
+	}
+	return filters
+}
+
+// WriteColdStorage stores the compressed storage of a private contract in the cold store
+func WriteColdStorage(db ethdb.KeyValueWriter, root common.Hash, data []byte) error {
+	return db.Put(append(coldStoragePrefix, root[:]...), data)
+}
+
+// ReadColdStorage retrieves the compressed storage of a private contract from the cold
+// store, nil if not found
+func ReadColdStorage(db ethdb.KeyValueReader, root common.Hash) []byte {
+	data, _ := db.Get(append(coldStoragePrefix, root[:]...))
+	return data
+}
+
+func coldActivityKey(psi string, addr common.Address) []byte {
+	key := append(append([]byte{}, coldActivityPrefix...), psi...)
+	return append(key, addr[:]...)
+}
+
+// WriteContractActivity stores the time of the last activity of a private contract
+func WriteContractActivity(db ethdb.KeyValueWriter, psi string, addr common.Address, time uint64) error {
+	return db.Put(coldActivityKey(psi, addr), encodeBlockNumber(time))
+}
+
+// DeleteContractActivity removes the time of the last activity of a private contract
+func DeleteContractActivity(db ethdb.KeyValueWriter, psi string, addr common.Address) error {
+	return db.Delete(coldActivityKey(psi, addr))
+}
+
+// ReadContractActivities retrieves the time of the last activity of the private contracts
+// of a private state, keyed by address
+func ReadContractActivities(db ethdb.Iteratee, psi string) map[common.Address]uint64 {
+	prefix := append(append([]byte{}, coldActivityPrefix...), psi...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	activities := make(map[common.Address]uint64)
+	for it.Next() {
+		// the prefix of a private state also matches the private states it prefixes
+		key := it.Key()
+		if len(key) != len(prefix)+common.AddressLength || len(it.Value()) != 8 {
+			continue
+		}
+		activities[common.BytesToAddress(key[len(prefix):])] = binary.BigEndian.Uint64(it.Value())
+	}
+	return activities
+}
+
+// WriteColdStateSeeded flags that the activity of the contracts of a private state is recorded
+func WriteColdStateSeeded(db ethdb.KeyValueWriter, psi string) error {
+	return db.Put(append(append([]byte{}, coldSeededPrefix...), psi...), []byte{1})
+}
+
+// ReadColdStateSeeded returns whether the activity of the contracts of a private state
+// is recorded
+func ReadColdStateSeeded(db ethdb.KeyValueReader, psi string) bool {
+	data, _ := db.Get(append(append([]byte{}, coldSeededPrefix...), psi...))
+	return len(data) == 1
+}
+
+// ColdMove is a private contract moved to the cold store with a block
+type ColdMove struct {
+	PSI     string
+	Address common.Address
+	Root    common.Hash // root of the storage moved
+}
+
+// WriteColdMoves stores the private contracts moved to the cold store with a block
+func WriteColdMoves(db ethdb.KeyValueWriter, hash common.Hash, moves []ColdMove) error {
+	data, err := rlp.EncodeToBytes(moves)
+	if err != nil {
+		return err
+	}
+	return db.Put(append(append([]byte{}, coldMovesPrefix...), hash[:]...), data)
+}
+
+// ReadColdMoves retrieves the private contracts moved to the cold store with a block
+func ReadColdMoves(db ethdb.KeyValueReader, hash common.Hash) ([]ColdMove, error) {
+	data, _ := db.Get(append(append([]byte{}, coldMovesPrefix...), hash[:]...))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var moves []ColdMove
+	if err := rlp.DecodeBytes(data, &moves); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// PrivatePayloadInclusion is a transaction of a block carrying the hash of a private
+// payload, recorded whether the node is party to the payload or not
+type PrivatePayloadInclusion struct {
+	BlockNumber uint64
+	BlockHash   common.Hash
+	BlockTime   uint64
+	TxHash      common.Hash
+	TxIndex     uint64
+}
+
+func privatePayloadIndexKey(hash common.EncryptedPayloadHash, number uint64, txHash common.Hash) []byte {
+	key := append(append([]byte{}, privatePayloadIndexPrefix...), hash[:]...)
+	key = append(key, encodeBlockNumber(number)...)
+	return append(key, txHash[:]...)
+}
+
+// WritePrivatePayloadIndex records the hashes of the private payloads carried by the
+// private transactions of a block, without decrypting them
+func WritePrivatePayloadIndex(db ethdb.KeyValueWriter, block *types.Block) {
+	for i, tx := range block.Transactions() {
+		if !tx.IsPrivate() || len(tx.Data()) != common.EncryptedPayloadHashLength {
+			continue
+		}
+		inclusion := &PrivatePayloadInclusion{
+			BlockNumber: block.NumberU64(),
+			BlockHash:   block.Hash(),
+			BlockTime:   block.Time(),
+			TxHash:      tx.Hash(),
+			TxIndex:     uint64(i),
+		}
+		data, err := rlp.EncodeToBytes(inclusion)
+		if err != nil {
+			log.Crit("Failed to encode private payload inclusion", "err", err)
+		}
+		key := privatePayloadIndexKey(common.BytesToEncryptedPayloadHash(tx.Data()), inclusion.BlockNumber, inclusion.TxHash)
+		if err := db.Put(key, data); err != nil {
+			log.Crit("Failed to store private payload inclusion", "err", err)
+		}
+	}
+}
+
+// ReadPrivatePayloadInclusions retrieves the transactions carrying the hash of a private
+// payload, by block number. The blocks are not necessarily canonical.
+func ReadPrivatePayloadInclusions(db ethdb.Iteratee, hash common.EncryptedPayloadHash) []*PrivatePayloadInclusion {
+	prefix := append(append([]byte{}, privatePayloadIndexPrefix...), hash[:]...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var inclusions []*PrivatePayloadInclusion
+	for it.Next() {
+		inclusion := new(PrivatePayloadInclusion)
+		if err := rlp.DecodeBytes(it.Value(), inclusion); err != nil {
+			log.Error("Invalid private payload inclusion", "hash", hash, "err", err)
+			continue
+		}
+		inclusions = append(inclusions, inclusion)
+	}
+	return inclusions
+}
+
 func GetPrivateStateRoot(db ethdb.Database, blockRoot common.Hash) common.Hash {
 	root, _ := db.Get(append(privateRootPrefix, blockRoot[:]...))
 	return common.BytesToHash(root)
@@ -66,14 +275,37 @@ func GetAccountExtraDataRoot(db ethdb.KeyValueReader, stateRoot common.Hash) com
 	return common.BytesToHash(root)
 }
 
-func WritePrivateStateRoot(db ethdb.Database, blockRoot, root common.Hash) error {
+func WritePrivateStateRoot(db ethdb.KeyValueWriter, blockRoot, root common.Hash) error {
 	return db.Put(append(privateRootPrefix, blockRoot[:]...), root[:])
 }
 
-func WritePrivateStatesTrieRoot(db ethdb.Database, blockRoot, root common.Hash) error {
+func WritePrivateStatesTrieRoot(db ethdb.KeyValueWriter, blockRoot, root common.Hash) error {
 	return db.Put(append(privateStatesTrieRootPrefix, blockRoot[:]...), root[:])
 }
 
+// CheckAncientBlock checks that a frozen block is in every freezer table, and that the
+// frozen header is the one of the frozen canonical hash. It returns the table of the
+// first inconsistency found.
+func CheckAncientBlock(db ethdb.AncientReader, number uint64) (string, error) {
+	hash, err := db.Ancient(freezerHashTable, number)
+	if err != nil {
+		return freezerHashTable, err
+	}
+	header, err := db.Ancient(freezerHeaderTable, number)
+	if err != nil {
+		return freezerHeaderTable, err
+	}
+	if headerHash := crypto.Keccak256Hash(header); !bytes.Equal(headerHash[:], hash) {
+		return freezerHeaderTable, fmt.Errorf("header hash %x differs from the canonical hash %x", headerHash, hash)
+	}
+	for _, table := range []string{freezerBodiesTable, freezerReceiptTable, freezerDifficultyTable} {
+		if _, err := db.Ancient(table, number); err != nil {
+			return table, err
+		}
+	}
+	return "", nil
+}
+
 // WriteRootHashMapping stores the mapping between root hash of state trie and
 // root hash of state.AccountExtraData trie to persistent storage
 func WriteRootHashMapping(db ethdb.KeyValueWriter, stateRoot, extraDataRoot common.Hash) error {
@@ -82,7 +314,7 @@ func WriteRootHashMapping(db ethdb.KeyValueWriter, stateRoot, extraDataRoot comm
 
 // WritePrivateBlockBloom creates a bloom filter for the given receipts and saves it to the database
 // with the number given as identifier (i.e. block number).
-func WritePrivateBlockBloom(db ethdb.Database, number uint64, receipts types.Receipts) error {
+func WritePrivateBlockBloom(db ethdb.KeyValueWriter, number uint64, receipts types.Receipts) error {
 	rbloom := types.CreateBloom(receipts.Flatten())
 	return db.Put(append(privateBloomPrefix, encodeBlockNumber(number)...), rbloom[:])
 }
@@ -18,9 +18,11 @@ package rawdb
 
 import (
 	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/stretchr/testify/assert"
 )
@@ -182,3 +184,72 @@ func TestPrivateStateRoot(t *testing.T) {
 	retrievedEmptyRoot := GetPrivateStateRoot(db, common.Hash{})
 	assert.Equal(t, common.Hash{}, retrievedEmptyRoot)
 }
+
+func TestAsyncCallbacks(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	assert.NoError(t, WriteAsyncCallback(db, 1, []byte("first")))
+	assert.NoError(t, WriteAsyncCallback(db, 2, []byte("second")))
+	assert.Equal(t, map[uint64][]byte{1: []byte("first"), 2: []byte("second")}, ReadAsyncCallbacks(db))
+
+	assert.NoError(t, DeleteAsyncCallback(db, 1))
+	assert.Equal(t, map[uint64][]byte{2: []byte("second")}, ReadAsyncCallbacks(db))
+}
+
+func TestPersistentFilters(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	assert.NoError(t, WritePersistentFilter(db, "0x1", []byte("first")))
+	assert.NoError(t, WritePersistentFilter(db, "0x2", []byte("second")))
+	assert.Equal(t, map[string][]byte{"0x1": []byte("first"), "0x2": []byte("second")}, ReadPersistentFilters(db))
+
+	assert.NoError(t, DeletePersistentFilter(db, "0x1"))
+	assert.Equal(t, map[string][]byte{"0x2": []byte("second")}, ReadPersistentFilters(db))
+}
+
+func TestContractActivities(t *testing.T) {
+	db := NewMemoryDatabase()
+	addr1, addr2 := common.Address{1}, common.Address{2}
+
+	assert.NoError(t, WriteContractActivity(db, "psi", addr1, 10))
+	assert.NoError(t, WriteContractActivity(db, "psi", addr2, 20))
+	assert.NoError(t, WriteContractActivity(db, "psi2", addr1, 30))
+	assert.Equal(t, map[common.Address]uint64{addr1: 10, addr2: 20}, ReadContractActivities(db, "psi"))
+
+	assert.NoError(t, DeleteContractActivity(db, "psi", addr1))
+	assert.Equal(t, map[common.Address]uint64{addr2: 20}, ReadContractActivities(db, "psi"))
+	assert.Equal(t, map[common.Address]uint64{addr1: 30}, ReadContractActivities(db, "psi2"))
+}
+
+func TestColdMoves(t *testing.T) {
+	db := NewMemoryDatabase()
+	moves := []ColdMove{{PSI: "psi", Address: common.Address{1}, Root: common.Hash{2}}}
+
+	assert.NoError(t, WriteColdMoves(db, common.Hash{1}, moves))
+
+	read, err := ReadColdMoves(db, common.Hash{1})
+	assert.NoError(t, err)
+	assert.Equal(t, moves, read)
+	read, err = ReadColdMoves(db, common.Hash{2})
+	assert.NoError(t, err)
+	assert.Empty(t, read)
+}
+
+func TestPrivatePayloadIndex(t *testing.T) {
+	db := NewMemoryDatabase()
+	payloadHash := common.BytesToEncryptedPayloadHash([]byte("payload"))
+	private := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), payloadHash.Bytes())
+	private.SetPrivate()
+	public := types.NewTransaction(1, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), payloadHash.Bytes())
+	block1 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Time: 10}).WithBody([]*types.Transaction{public, private}, nil)
+	block2 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), Time: 20}).WithBody([]*types.Transaction{private}, nil)
+
+	WritePrivatePayloadIndex(db, block2)
+	WritePrivatePayloadIndex(db, block1)
+
+	assert.Equal(t, []*PrivatePayloadInclusion{
+		{BlockNumber: 1, BlockHash: block1.Hash(), BlockTime: 10, TxHash: private.Hash(), TxIndex: 1},
+		{BlockNumber: 2, BlockHash: block2.Hash(), BlockTime: 20, TxHash: private.Hash(), TxIndex: 0},
+	}, ReadPrivatePayloadInclusions(db, payloadHash))
+	assert.Empty(t, ReadPrivatePayloadInclusions(db, common.BytesToEncryptedPayloadHash([]byte("other"))))
+}
@@ -37,6 +37,9 @@ type AccountExtraData struct {
 	// list of public keys managed by the corresponding Tessera.
 	// This is for multitenancy
 	ManagedParties []string
+	// root of the storage trie moved to the cold store, nil unless the storage
+	// of the contract is in the cold store
+	ColdStorageRoot *common.Hash
 }
 
 func (qmd *AccountExtraData) DecodeRLP(stream *rlp.Stream) error {
@@ -75,6 +78,13 @@ func (qmd *AccountExtraData) DecodeRLP(stream *rlp.Stream) error {
 			qmd.ManagedParties = managedParties
 		}
 	}
+	if len(dataRLP.Rest) > 1 {
+		var coldStorageRoot common.Hash
+		if err := rlp.DecodeBytes(dataRLP.Rest[1], &coldStorageRoot); err != nil {
+			return fmt.Errorf("fail to decode coldStorageRoot with error %v", err)
+		}
+		qmd.ColdStorageRoot = &coldStorageRoot
+	}
 	return nil
 }
 
@@ -87,6 +97,21 @@ func (qmd *AccountExtraData) EncodeRLP(writer io.Writer) error {
 		hash = &qmd.PrivacyMetadata.CreationTxHash
 		flag = &qmd.PrivacyMetadata.PrivacyFlag
 	}
+	// the cold storage root is only encoded when set, so that the encoding of the
+	// other accounts is unchanged
+	if qmd.ColdStorageRoot != nil {
+		return rlp.Encode(writer, struct {
+			CreationTxHash  *common.EncryptedPayloadHash `rlp:"nil"`
+			PrivacyFlag     *engine.PrivacyFlagType      `rlp:"nil"`
+			ManagedParties  []string
+			ColdStorageRoot common.Hash
+		}{
+			CreationTxHash:  hash,
+			PrivacyFlag:     flag,
+			ManagedParties:  qmd.ManagedParties,
+			ColdStorageRoot: *qmd.ColdStorageRoot,
+		})
+	}
 	return rlp.Encode(writer, struct {
 		CreationTxHash *common.EncryptedPayloadHash `rlp:"nil"`
 		PrivacyFlag    *engine.PrivacyFlagType      `rlp:"nil"`
@@ -111,9 +136,15 @@ func (qmd *AccountExtraData) copy() *AccountExtraData {
 	}
 	copyManagedParties := make([]string, len(qmd.ManagedParties))
 	copy(copyManagedParties, qmd.ManagedParties)
+	var copyColdStorageRoot *common.Hash
+	if qmd.ColdStorageRoot != nil {
+		root := *qmd.ColdStorageRoot
+		copyColdStorageRoot = &root
+	}
 	return &AccountExtraData{
 		PrivacyMetadata: copyPM,
 		ManagedParties:  copyManagedParties,
+		ColdStorageRoot: copyColdStorageRoot,
 	}
 }
 
@@ -186,6 +186,31 @@ func TestRLP_AccountExtraData_whenHavingEmptyManagedParties(t *testing.T) {
 	assert.Nil(t, actual.PrivacyMetadata)
 }
 
+func TestRLP_AccountExtraData_withField_ColdStorageRoot(t *testing.T) {
+	root := common.HexToHash("0x1234")
+	expected := AccountExtraData{
+		ManagedParties:  []string{"XYZ"},
+		ColdStorageRoot: &root,
+	}
+
+	data, err := rlp.EncodeToBytes(&expected)
+	assert.NoError(t, err)
+
+	var actual AccountExtraData
+	assert.NoError(t, rlp.DecodeBytes(data, &actual))
+	assert.Equal(t, expected.ManagedParties, actual.ManagedParties)
+	assert.Equal(t, &root, actual.ColdStorageRoot)
+
+	// the accounts without cold storage are encoded as before
+	expected.ColdStorageRoot = nil
+	data, err = rlp.EncodeToBytes(&expected)
+	assert.NoError(t, err)
+
+	actual = AccountExtraData{}
+	assert.NoError(t, rlp.DecodeBytes(data, &actual))
+	assert.Nil(t, actual.ColdStorageRoot)
+}
+
 func TestCopy_whenNil(t *testing.T) {
 	var testObj *AccountExtraData = nil
 
@@ -0,0 +1,292 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/snappy"
+)
+
+// Quorum
+
+var (
+	coldStorageMovedMeter    = metrics.NewRegisteredMeter("state/cold/moved", nil)
+	coldStorageRestoredMeter = metrics.NewRegisteredMeter("state/cold/restored", nil)
+
+	errNoColdStore = errors.New("no cold store")
+)
+
+// ColdStore keeps the storage of the contracts moved out of the state. The storage
+// is keyed by the root of the storage trie, the entries are the ones of the trie:
+// the hashed storage keys and the RLP encoded values.
+type ColdStore interface {
+	// ReadStorage retrieves the storage of the storage trie root
+	ReadStorage(root common.Hash) (map[common.Hash][]byte, error)
+	// WriteStorage stores the storage of the storage trie root
+	WriteStorage(root common.Hash, storage map[common.Hash][]byte) error
+}
+
+// coldStorageEntry is an entry of a storage trie in the cold store
+type coldStorageEntry struct {
+	Key   common.Hash
+	Value []byte
+}
+
+// dbColdStore is a ColdStore compressing the storage into a key-value store
+type dbColdStore struct {
+	db ethdb.KeyValueStore
+}
+
+// NewColdStore creates a cold store persisted in the key-value store
+func NewColdStore(db ethdb.KeyValueStore) ColdStore {
+	return &dbColdStore{db: db}
+}
+
+func (cs *dbColdStore) ReadStorage(root common.Hash) (map[common.Hash][]byte, error) {
+	data := rawdb.ReadColdStorage(cs.db, root)
+	if data == nil {
+		return nil, fmt.Errorf("storage %x not found in the cold store", root)
+	}
+	enc, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, err
+	}
+	var entries []coldStorageEntry
+	if err := rlp.DecodeBytes(enc, &entries); err != nil {
+		return nil, err
+	}
+	storage := make(map[common.Hash][]byte, len(entries))
+	for _, entry := range entries {
+		storage[entry.Key] = entry.Value
+	}
+	return storage, nil
+}
+
+func (cs *dbColdStore) WriteStorage(root common.Hash, storage map[common.Hash][]byte) error {
+	entries := make([]coldStorageEntry, 0, len(storage))
+	for key, value := range storage {
+		entries = append(entries, coldStorageEntry{key, value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].Key[:], entries[j].Key[:]) < 0 })
+	enc, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteColdStorage(cs.db, root, snappy.Encode(nil, enc))
+}
+
+// coldStoreDatabase is a Database whose states move the storage of contracts to a
+// cold store
+type coldStoreDatabase struct {
+	Database
+	coldStore ColdStore
+}
+
+// NewDatabaseWithColdStore returns a Database whose states restore the storage of the
+// contracts moved to the cold store when they are accessed.
+func NewDatabaseWithColdStore(db Database, coldStore ColdStore) Database {
+	return &coldStoreDatabase{Database: db, coldStore: coldStore}
+}
+
+// MoveStorageToColdStore moves the storage of a contract to the cold store, the
+// storage is restored from the cold store the next time the contract is accessed.
+// It reports whether the storage was moved: the contracts accessed in this state,
+// without storage or with enhanced privacy stay in the state.
+//
+// Only the account is changed, the trie nodes of the storage aren't deleted from the
+// state database since they may be shared with other tries.
+//
+// The change isn't journaled, it must not be made during a transaction.
+func (s *StateDB) MoveStorageToColdStore(addr common.Address) (bool, error) {
+	root, ok, err := s.CopyStorageToColdStore(addr)
+	if !ok {
+		return false, err
+	}
+	return s.SwitchStorageToColdStore(addr, root)
+}
+
+// CopyStorageToColdStore writes the storage of a contract to the cold store, leaving
+// the state unchanged, and returns the root of the storage. It reports whether the
+// storage can be moved, as MoveStorageToColdStore does.
+//
+// The storage is switched to the cold store by SwitchStorageToColdStore, possibly in
+// a later state, so that the storage tries are walked outside of the block processing.
+func (s *StateDB) CopyStorageToColdStore(addr common.Address) (common.Hash, bool, error) {
+	obj, err := s.coldStorageCandidate(addr)
+	if obj == nil {
+		return common.Hash{}, false, err
+	}
+	storage := make(map[common.Hash][]byte)
+	it := trie.NewIterator(obj.getTrie(s.db).NodeIterator(nil))
+	for it.Next() {
+		storage[common.BytesToHash(it.Key)] = common.CopyBytes(it.Value)
+	}
+	if it.Err != nil {
+		return common.Hash{}, false, it.Err
+	}
+	if err := s.coldStore.WriteStorage(obj.data.Root, storage); err != nil {
+		return common.Hash{}, false, err
+	}
+	return obj.data.Root, true, nil
+}
+
+// SwitchStorageToColdStore moves the storage of a contract, copied to the cold store
+// by CopyStorageToColdStore, out of the state. It reports whether the storage was
+// moved: the storage is kept if it changed since it was copied.
+//
+// The change isn't journaled, it must not be made during a transaction.
+func (s *StateDB) SwitchStorageToColdStore(addr common.Address, root common.Hash) (bool, error) {
+	obj, err := s.coldStorageCandidate(addr)
+	if obj == nil || obj.data.Root != root {
+		return false, err
+	}
+	extraData, err := obj.AccountExtraData()
+	if errors.Is(err, common.ErrNoAccountExtraData) {
+		extraData = &AccountExtraData{}
+	} else if err != nil {
+		return false, err
+	}
+	extraData = extraData.copy()
+	extraData.ColdStorageRoot = &root
+	obj.data.Root = emptyRoot
+	obj.trie = nil
+	obj.setAccountExtraData(extraData)
+	// the object is written right away and left out of the live objects, so that it
+	// is restored if accessed again
+	s.updateStateObject(obj)
+	coldStorageMovedMeter.Mark(1)
+	return true, s.dbErr
+}
+
+// coldStorageCandidate returns the contract, loaded from the trie, if its storage can
+// be moved to the cold store, nil otherwise
+func (s *StateDB) coldStorageCandidate(addr common.Address) (*stateObject, error) {
+	if s.coldStore == nil {
+		return nil, errNoColdStore
+	}
+	if _, live := s.stateObjects[addr]; live {
+		return nil, nil
+	}
+	enc, err := s.trie.TryGet(addr.Bytes())
+	if err != nil || len(enc) == 0 {
+		return nil, err
+	}
+	var data Account
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		return nil, err
+	}
+	if data.Root == emptyRoot || bytes.Equal(data.CodeHash, emptyCodeHash) {
+		return nil, nil
+	}
+	obj := newObject(s, addr, data)
+	// the storage of the contracts with enhanced privacy is part of their validation
+	if pm, err := obj.PrivacyMetadata(); err == nil && pm.PrivacyFlag != engine.PrivacyFlagStandardPrivate {
+		return nil, nil
+	}
+	return obj, nil
+}
+
+// AccessedContracts returns the contracts accessed in this state, e.g. called by a
+// transaction or by another contract
+func (s *StateDB) AccessedContracts() []common.Address {
+	var contracts []common.Address
+	for addr, obj := range s.stateObjects {
+		if !bytes.Equal(obj.CodeHash(), emptyCodeHash) {
+			contracts = append(contracts, addr)
+		}
+	}
+	return contracts
+}
+
+// restoreColdStorage restores the storage of a contract loaded from the trie, if it
+// was moved to the cold store. The restored contract is written back to the trie on
+// commit, the change isn't journaled.
+//
+// The trie nodes of the storage are left in the state database when it is moved, so
+// the storage is restored from them if the cold store can't be read.
+func (s *StateDB) restoreColdStorage(obj *stateObject) {
+	extraData, err := obj.AccountExtraData()
+	if err != nil || extraData.ColdStorageRoot == nil {
+		return
+	}
+	root := *extraData.ColdStorageRoot
+	if err := s.rebuildColdStorage(root); err != nil {
+		if !s.hasStorageTrie(obj.addrHash, root) {
+			s.setError(fmt.Errorf("restore cold storage of %x: %v", obj.address, err))
+			return
+		}
+		log.Warn("Failed to read the cold store, restored the storage from the state database", "address", obj.address, "err", err)
+	}
+	extraData = extraData.copy()
+	extraData.ColdStorageRoot = nil
+	obj.data.Root = root
+	obj.setAccountExtraData(extraData)
+	s.stateObjectsPending[obj.address] = struct{}{}
+	s.stateObjectsDirty[obj.address] = struct{}{}
+	coldStorageRestoredMeter.Mark(1)
+}
+
+// rebuildColdStorage rebuilds the storage trie of the root from the cold store, as it
+// was, so that the contract is executed the same
+func (s *StateDB) rebuildColdStorage(root common.Hash) error {
+	storage, err := s.coldStore.ReadStorage(root)
+	if err != nil {
+		return err
+	}
+	tr, err := trie.New(common.Hash{}, s.db.TrieDB())
+	if err != nil {
+		return err
+	}
+	for key, value := range storage {
+		if err := tr.TryUpdate(key[:], value); err != nil {
+			return err
+		}
+	}
+	restored, err := tr.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if restored != root {
+		return fmt.Errorf("root %x, want %x", restored, root)
+	}
+	return nil
+}
+
+// hasStorageTrie reports whether all the nodes of the storage trie of the root are in
+// the state database
+func (s *StateDB) hasStorageTrie(addrHash common.Hash, root common.Hash) bool {
+	tr, err := s.db.OpenStorageTrie(addrHash, root)
+	if err != nil {
+		return false
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+	}
+	return it.Error() == nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveStorageToColdStore(t *testing.T) {
+	db := NewDatabaseWithColdStore(NewDatabase(rawdb.NewMemoryDatabase()), NewColdStore(rawdb.NewMemoryDatabase()))
+	contract, key, value := common.Address{1}, common.Hash{2}, common.Hash{3}
+	statedb, _ := New(common.Hash{}, db, nil)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetState(contract, key, value)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+
+	statedb, _ = New(root, db, nil)
+	moved, err := statedb.MoveStorageToColdStore(contract)
+	require.NoError(t, err)
+	require.True(t, moved)
+	coldRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	assert.NotEqual(t, root, coldRoot)
+
+	statedb, _ = New(coldRoot, db, nil)
+	assert.Equal(t, value, statedb.GetState(contract, key), "the storage is restored on access")
+	restoredRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	assert.Equal(t, root, restoredRoot)
+
+	statedb, _ = New(coldRoot, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	_, err = statedb.MoveStorageToColdStore(contract)
+	assert.Equal(t, errNoColdStore, err)
+}
+
+// unreadableColdStore is a cold store whose storage can't be read back
+type unreadableColdStore struct {
+	ColdStore
+}
+
+func (unreadableColdStore) ReadStorage(root common.Hash) (map[common.Hash][]byte, error) {
+	return nil, errors.New("corrupted")
+}
+
+func TestMoveStorageToColdStore_whenColdStoreUnreadable(t *testing.T) {
+	db := NewDatabaseWithColdStore(NewDatabase(rawdb.NewMemoryDatabase()), unreadableColdStore{NewColdStore(rawdb.NewMemoryDatabase())})
+	contract, key, value := common.Address{1}, common.Hash{2}, common.Hash{3}
+	statedb, _ := New(common.Hash{}, db, nil)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetState(contract, key, value)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(root, db, nil)
+	moved, err := statedb.MoveStorageToColdStore(contract)
+	require.NoError(t, err)
+	require.True(t, moved)
+	coldRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+
+	statedb, _ = New(coldRoot, db, nil)
+
+	assert.Equal(t, value, statedb.GetState(contract, key), "the storage is restored from the state database")
+	assert.NoError(t, statedb.Error())
+	restoredRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	assert.Equal(t, root, restoredRoot)
+}
+
+func TestMoveStorageToColdStore_whenNotMovable(t *testing.T) {
+	db := NewDatabaseWithColdStore(NewDatabase(rawdb.NewMemoryDatabase()), NewColdStore(rawdb.NewMemoryDatabase()))
+	psv, account, noStorage, active := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{5}
+	statedb, _ := New(common.Hash{}, db, nil)
+	statedb.SetCode(psv, []byte("code"))
+	statedb.SetState(psv, common.Hash{1}, common.Hash{1})
+	statedb.SetPrivacyMetadata(psv, NewStatePrivacyMetadata(common.EncryptedPayloadHash{1}, engine.PrivacyFlagStateValidation))
+	statedb.SetBalance(account, common.Big1)
+	statedb.SetCode(noStorage, []byte("code"))
+	statedb.SetCode(active, []byte("code"))
+	statedb.SetState(active, common.Hash{1}, common.Hash{1})
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+
+	statedb, _ = New(root, db, nil)
+	for _, addr := range []common.Address{psv, account, noStorage, {4}} {
+		moved, err := statedb.MoveStorageToColdStore(addr)
+
+		assert.NoError(t, err)
+		assert.False(t, moved, "%x", addr)
+	}
+	statedb.GetState(active, common.Hash{1})
+	moved, err := statedb.MoveStorageToColdStore(active)
+
+	assert.NoError(t, err)
+	assert.False(t, moved, "the contract accessed in the state stays")
+}
+
+func TestSwitchStorageToColdStore_whenStorageChanged(t *testing.T) {
+	db := NewDatabaseWithColdStore(NewDatabase(rawdb.NewMemoryDatabase()), NewColdStore(rawdb.NewMemoryDatabase()))
+	contract := common.Address{1}
+	statedb, _ := New(common.Hash{}, db, nil)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetState(contract, common.Hash{1}, common.Hash{1})
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+
+	statedb, _ = New(root, db, nil)
+	storageRoot, ok, err := statedb.CopyStorageToColdStore(contract)
+	require.NoError(t, err)
+	require.True(t, ok)
+	copiedRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	assert.Equal(t, root, copiedRoot, "copying the storage leaves the state unchanged")
+
+	statedb, _ = New(root, db, nil)
+	statedb.SetState(contract, common.Hash{1}, common.Hash{2})
+	root, err = statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(root, db, nil)
+	moved, err := statedb.SwitchStorageToColdStore(contract, storageRoot)
+
+	assert.NoError(t, err)
+	assert.False(t, moved, "the storage changed since it was copied")
+}
+
+func TestAccessedContracts(t *testing.T) {
+	statedb, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	contract, account := common.Address{1}, common.Address{2}
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetBalance(account, common.Big1)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+
+	statedb, _ = New(root, statedb.Database(), nil)
+	assert.Empty(t, statedb.AccessedContracts())
+
+	statedb.GetCode(contract)
+	statedb.GetBalance(account)
+	assert.Equal(t, []common.Address{contract}, statedb.AccessedContracts())
+}
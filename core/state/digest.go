@@ -0,0 +1,138 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"golang.org/x/crypto/sha3"
+)
+
+// Quorum
+
+// AccountDigest is the digest of an account of a state, the same on all the nodes
+// having the account in their state
+type AccountDigest struct {
+	Address     *common.Address `json:"address,omitempty"` // nil if the preimage of the address hash is missing
+	AddrHash    common.Hash     `json:"addrHash"`
+	Nonce       uint64          `json:"nonce"`
+	Balance     *big.Int        `json:"balance"`
+	CodeHash    common.Hash     `json:"codeHash"`
+	StorageRoot common.Hash     `json:"storageRoot"`
+	Digest      common.Hash     `json:"digest"`
+}
+
+// StateDigest is the digest of a state, which two nodes compare to check that they
+// agree on the state. Unlike the root of the state trie, the digest leaves out the
+// data local to the node: the managed parties of the private contracts, and whether
+// their storage was moved to the cold store.
+type StateDigest struct {
+	Root     common.Hash     `json:"root"`               // Root of the state trie, which may differ between nodes
+	Digest   common.Hash     `json:"digest"`             // Digest of the accounts, in the order of their address hash
+	Count    int             `json:"count"`              // Number of accounts
+	Accounts []AccountDigest `json:"accounts,omitempty"` // Digests of the accounts, if requested
+}
+
+// Digest computes the digest of the state, with the digests of its accounts if
+// withAccounts is set. The storage roots of the accounts with missing address
+// preimages can't be resolved from the cold store.
+func (s *StateDB) Digest(withAccounts bool) (*StateDigest, error) {
+	digest := &StateDigest{Root: s.trie.Hash()}
+	hasher := sha3.NewLegacyKeccak256().(crypto.KeccakState)
+	it := trie.NewIterator(s.trie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return nil, err
+		}
+		account := AccountDigest{
+			AddrHash:    common.BytesToHash(it.Key),
+			Nonce:       data.Nonce,
+			Balance:     data.Balance,
+			CodeHash:    common.BytesToHash(data.CodeHash),
+			StorageRoot: data.Root,
+		}
+		if addrBytes := s.trie.GetKey(it.Key); addrBytes != nil {
+			addr := common.BytesToAddress(addrBytes)
+			account.Address = &addr
+			extraData, err := newObject(s, addr, data).AccountExtraData()
+			if err != nil && !errors.Is(err, common.ErrNoAccountExtraData) {
+				return nil, err
+			}
+			if extraData != nil && extraData.ColdStorageRoot != nil {
+				account.StorageRoot = *extraData.ColdStorageRoot
+			}
+		}
+		enc, err := rlp.EncodeToBytes([]interface{}{account.Nonce, account.Balance, account.CodeHash, account.StorageRoot})
+		if err != nil {
+			return nil, err
+		}
+		account.Digest = crypto.Keccak256Hash(enc)
+
+		hasher.Write(account.AddrHash[:])
+		hasher.Write(account.Digest[:])
+		digest.Count++
+		if withAccounts {
+			digest.Accounts = append(digest.Accounts, account)
+		}
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	hasher.Read(digest.Digest[:])
+	return digest, nil
+}
+
+// Diverging returns the address hashes of the accounts whose digests differ from the
+// ones of other, or which are in one state only. Both digests need their accounts.
+func (d *StateDigest) Diverging(other *StateDigest) []common.Hash {
+	var diverging []common.Hash
+	i, j := 0, 0
+	for i < len(d.Accounts) || j < len(other.Accounts) {
+		switch {
+		case j == len(other.Accounts):
+			diverging = append(diverging, d.Accounts[i].AddrHash)
+			i++
+		case i == len(d.Accounts):
+			diverging = append(diverging, other.Accounts[j].AddrHash)
+			j++
+		default:
+			a, b := d.Accounts[i], other.Accounts[j]
+			switch cmp := bytes.Compare(a.AddrHash[:], b.AddrHash[:]); {
+			case cmp < 0:
+				diverging = append(diverging, a.AddrHash)
+				i++
+			case cmp > 0:
+				diverging = append(diverging, b.AddrHash)
+				j++
+			default:
+				if a.Digest != b.Digest {
+					diverging = append(diverging, a.AddrHash)
+				}
+				i++
+				j++
+			}
+		}
+	}
+	return diverging
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigest_independentOfNodeLocalData(t *testing.T) {
+	db := NewDatabaseWithColdStore(NewDatabase(rawdb.NewMemoryDatabase()), NewColdStore(rawdb.NewMemoryDatabase()))
+	contract, account := common.Address{1}, common.Address{2}
+	statedb, _ := New(common.Hash{}, db, nil)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetState(contract, common.Hash{1}, common.Hash{1})
+	statedb.SetManagedParties(contract, []string{"key1"})
+	statedb.SetBalance(account, common.Big1)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(root, db, nil)
+	digest, err := statedb.Digest(true)
+	require.NoError(t, err)
+
+	statedb.SetManagedParties(contract, []string{"key2"})
+	otherRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(otherRoot, db, nil)
+	moved, err := statedb.MoveStorageToColdStore(contract)
+	require.NoError(t, err)
+	require.True(t, moved)
+	otherRoot, err = statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(otherRoot, db, nil)
+	otherDigest, err := statedb.Digest(true)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest.Root, otherDigest.Root)
+	assert.Equal(t, digest.Digest, otherDigest.Digest)
+	assert.Equal(t, 2, otherDigest.Count)
+	assert.Empty(t, digest.Diverging(otherDigest))
+}
+
+func TestDigest_Diverging(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	contract, account, other := common.Address{1}, common.Address{2}, common.Address{3}
+	statedb, _ := New(common.Hash{}, db, nil)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetState(contract, common.Hash{1}, common.Hash{1})
+	statedb.SetBalance(account, common.Big1)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(root, db, nil)
+	digest, err := statedb.Digest(true)
+	require.NoError(t, err)
+
+	statedb.SetState(contract, common.Hash{1}, common.Hash{2})
+	statedb.SetBalance(other, common.Big1)
+	otherRoot, err := statedb.Commit(true)
+	require.NoError(t, err)
+	statedb, _ = New(otherRoot, db, nil)
+	otherDigest, err := statedb.Digest(true)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest.Digest, otherDigest.Digest)
+	assert.ElementsMatch(t, []common.Hash{crypto.Keccak256Hash(contract[:]), crypto.Keccak256Hash(other[:])}, digest.Diverging(otherDigest))
+}
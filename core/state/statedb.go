@@ -18,6 +18,7 @@
 package state
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -73,6 +74,8 @@ type StateDB struct {
 
 	// Quorum - a trie to hold extra account information that cannot be stored in the accounts trie
 	accountExtraDataTrie Trie
+	// Quorum - the store of the storage moved out of the state, nil if none
+	coldStore ColdStore
 
 	// This map holds 'live' objects, which will get modified while processing a state transition.
 	stateObjects        map[common.Address]*stateObject
@@ -149,6 +152,10 @@ func New(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error)
 		// Quorum - Privacy Enhancements
 		accountExtraDataTrie: accountExtraDataTrie,
 	}
+	// Quorum
+	if cdb, ok := db.(*coldStoreDatabase); ok {
+		sdb.coldStore = cdb.coldStore
+	}
 
 	if sdb.snaps != nil {
 		if sdb.snap = sdb.snaps.Snapshot(root); sdb.snap != nil {
@@ -664,6 +671,10 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	// Insert into the live set
 	obj := newObject(s, addr, *data)
 	s.setStateObject(obj)
+	// Quorum - the storage of the contracts moved to the cold store is restored on access
+	if s.coldStore != nil && data.Root == emptyRoot && !bytes.Equal(data.CodeHash, emptyCodeHash) {
+		s.restoreColdStorage(obj)
+	}
 	return obj
 }
 
@@ -761,6 +772,7 @@ func (s *StateDB) Copy() *StateDB {
 		trie: s.db.CopyTrie(s.trie),
 		// Quorum - Privacy Enhancements
 		accountExtraDataTrie: s.db.CopyTrie(s.accountExtraDataTrie),
+		coldStore:            s.coldStore,
 		stateObjects:         make(map[common.Address]*stateObject, len(s.journal.dirties)),
 		stateObjectsPending:  make(map[common.Address]struct{}, len(s.stateObjectsPending)),
 		stateObjectsDirty:    make(map[common.Address]struct{}, len(s.journal.dirties)),
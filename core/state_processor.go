@@ -235,6 +235,10 @@ func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common
 	if config.IsQuorum && tx.GasPrice() != nil && tx.GasPrice().Cmp(common.Big0) > 0 {
 		return nil, nil, ErrInvalidGasPrice
 	}
+	// Quorum - reject the transactions past their validity window
+	if err := checkTxValidity(config, tx, header.Number, header.Time); err != nil {
+		return nil, nil, err
+	}
 
 	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
 	if err != nil {
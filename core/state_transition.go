@@ -20,6 +20,7 @@ import (
 	"errors"
 	"math"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -267,7 +268,9 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		isPrivate = true
 		pmh.snapshot = snapshot
 		pmh.eph = common.BytesToEncryptedPayloadHash(st.data)
+		receiveStart := time.Now()
 		_, _, data, pmh.receivedPrivacyMetadata, err = private.P.Receive(pmh.eph)
+		ptmReceiveTimer.UpdateSince(receiveStart)
 		// Increment the public account nonce if:
 		// 1. Tx is private and *not* a participant of the group and either call or create
 		// 2. Tx is private we are part of the group and is a call
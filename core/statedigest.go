@@ -0,0 +1,66 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Quorum
+
+// ChainStateDigest is the digest of the public state and of the private states at a
+// block, which the member nodes compare to reconcile their states
+type ChainStateDigest struct {
+	Number  uint64                                              `json:"number"`
+	Hash    common.Hash                                         `json:"hash"`
+	Public  *state.StateDigest                                  `json:"public"`
+	Private map[types.PrivateStateIdentifier]*state.StateDigest `json:"private"` // by PSI, in the order of the PSIs when encoded
+}
+
+// StateDigestAt computes the digest of the public state and of the private states of
+// psis at the block, of all the private states of the node if psis is empty
+func StateDigestAt(bc *BlockChain, block *types.Block, psis []types.PrivateStateIdentifier, withAccounts bool) (*ChainStateDigest, error) {
+	publicState, privateStateRepo, err := bc.StateAt(block.Root())
+	if err != nil {
+		return nil, fmt.Errorf("state of block #%d missing: %v", block.NumberU64(), err)
+	}
+	digest := &ChainStateDigest{
+		Number:  block.NumberU64(),
+		Hash:    block.Hash(),
+		Private: make(map[types.PrivateStateIdentifier]*state.StateDigest),
+	}
+	if digest.Public, err = publicState.Digest(withAccounts); err != nil {
+		return nil, fmt.Errorf("public state: %v", err)
+	}
+	if len(psis) == 0 {
+		psis = bc.PrivateStateManager().PSIs()
+	}
+	for _, psi := range psis {
+		privateState, err := privateStateRepo.StatePSI(psi)
+		if err != nil {
+			return nil, fmt.Errorf("private state %s: %v", psi, err)
+		}
+		if digest.Private[psi], err = privateState.Digest(withAccounts); err != nil {
+			return nil, fmt.Errorf("private state %s: %v", psi, err)
+		}
+	}
+	return digest, nil
+}
@@ -20,6 +20,7 @@ import (
 	"runtime"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // senderCacher is a concurrent transaction sender recoverer and cacher.
@@ -92,14 +93,27 @@ func (cacher *txSenderCacher) recover(signer types.Signer, txs []*types.Transact
 // recoverFromBlocks recovers the senders from a batch of blocks and caches them
 // back into the same data structures. There is no validation being done, nor
 // any reaction to invalid signatures. That is up to calling code later.
-func (cacher *txSenderCacher) recoverFromBlocks(signer types.Signer, blocks []*types.Block) {
-	count := 0
-	for _, block := range blocks {
-		count += len(block.Transactions())
-	}
-	txs := make([]*types.Transaction, 0, count)
-	for _, block := range blocks {
-		txs = append(txs, block.Transactions()...)
+//
+// Quorum: the senders are recovered with the signer of each block, the one the
+// state processor uses, so that the cached senders are hits even when the batch
+// spans a signer fork. The signers handle the V values of the private transactions.
+func (cacher *txSenderCacher) recoverFromBlocks(config *params.ChainConfig, blocks []*types.Block) {
+	for len(blocks) > 0 {
+		// batch the consecutive blocks sharing the same signer
+		signer := types.MakeSigner(config, blocks[0].Number())
+		n := 1
+		for n < len(blocks) && types.MakeSigner(config, blocks[n].Number()).Equal(signer) {
+			n++
+		}
+		count := 0
+		for _, block := range blocks[:n] {
+			count += len(block.Transactions())
+		}
+		txs := make([]*types.Transaction, 0, count)
+		for _, block := range blocks[:n] {
+			txs = append(txs, block.Transactions()...)
+		}
+		cacher.recover(signer, txs)
+		blocks = blocks[n:]
 	}
-	cacher.recover(signer, txs)
 }
@@ -0,0 +1,95 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxSenderCacher_recoverFromBlocks_withSignerOfEachBlock(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	config := &params.ChainConfig{ChainID: big.NewInt(10), HomesteadBlock: big.NewInt(0), EIP155Block: big.NewInt(2)}
+	signTx := func(nonce uint64, signer types.Signer, private bool) *types.Transaction {
+		tx := types.NewTransaction(nonce, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+		if private {
+			tx.SetPrivate()
+		}
+		tx, err := types.SignTx(tx, signer, key)
+		require.NoError(t, err)
+		return tx
+	}
+	blocks := types.Blocks{
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(types.Transactions{
+			signTx(0, types.HomesteadSigner{}, false),
+			signTx(1, types.QuorumPrivateTxSigner{}, true),
+		}, nil),
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2)}).WithBody(types.Transactions{
+			signTx(2, types.NewEIP155Signer(config.ChainID), false),
+			signTx(3, types.QuorumPrivateTxSigner{}, true),
+		}, nil),
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(3)}).WithBody(types.Transactions{
+			signTx(4, types.NewEIP155Signer(config.ChainID), false),
+		}, nil),
+	}
+	// no workers, the recoveries are run below
+	cacher := &txSenderCacher{threads: 2, tasks: make(chan *txSenderCacherRequest, 16)}
+
+	cacher.recoverFromBlocks(config, blocks)
+	close(cacher.tasks)
+
+	scheduled := make(map[common.Hash]types.Signer)
+	var tasks []*txSenderCacherRequest
+	for task := range cacher.tasks {
+		tasks = append(tasks, task)
+		for i := 0; i < len(task.txs); i += task.inc {
+			scheduled[task.txs[i].Hash()] = task.signer
+		}
+	}
+	for _, block := range blocks {
+		signer := types.MakeSigner(config, block.Number())
+		for _, tx := range block.Transactions() {
+			if assert.Contains(t, scheduled, tx.Hash(), "sender of tx %d not recovered", tx.Nonce()) {
+				assert.True(t, signer.Equal(scheduled[tx.Hash()]), "sender of tx %d recovered with the signer of another block", tx.Nonce())
+			}
+		}
+	}
+	replay := make(chan *txSenderCacherRequest, len(tasks))
+	for _, task := range tasks {
+		replay <- task
+	}
+	close(replay)
+	cacher.tasks = replay
+	cacher.cache()
+	for _, block := range blocks {
+		for _, tx := range block.Transactions() {
+			// types.Sender would recover the sender on a cache miss
+			from, cached := types.CachedSender(types.MakeSigner(config, block.Number()), tx)
+			assert.True(t, cached, "sender of tx %d not cached", tx.Nonce())
+			assert.Equal(t, sender, from, "sender of tx %d", tx.Nonce())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+const (
+	// txLatencyLimit is the maximum number of transactions tracked at once, the
+	// oldest admitted being dropped beyond.
+	txLatencyLimit = 100000
+
+	// txLatencyExpiry is how long a transaction is tracked before it is
+	// considered lost, e.g. evicted from the pool.
+	txLatencyExpiry = 3 * time.Hour
+)
+
+var (
+	// ptmReceiveTimer measures the round trip to the privacy manager for the
+	// payloads of the private transactions executed.
+	ptmReceiveTimer = metrics.NewRegisteredTimer("txlatency/ptm/receive", nil)
+
+	// txLatency tracks the transactions admitted to the pool of this node.
+	txLatency = newTxLatencyTracker()
+)
+
+// SetTxLatencyConsensus sets the consensus engine in the name of the latency
+// metrics, e.g. txlatency/raft/private/commit, so the percentiles of the
+// networks running different engines can be compared.
+func SetTxLatencyConsensus(consensus string) {
+	txLatency.setConsensus(consensus)
+}
+
+// txLatencyEntry is a transaction waiting to be included or committed.
+type txLatencyEntry struct {
+	admitted time.Time
+	private  bool
+	included bool
+}
+
+// txLatencyTracker measures the end-to-end latency of the transactions: from
+// their admission in the pool to the inclusion of their block in the chain, and
+// to the commit of the state of this block. Public and private transactions are
+// measured separately, the latter including the round trips to the privacy
+// manager while the block is executed.
+type txLatencyTracker struct {
+	mu        sync.Mutex
+	consensus string
+	txs       *simplelru.LRU // common.Hash -> *txLatencyEntry, in the order of admission
+}
+
+func newTxLatencyTracker() *txLatencyTracker {
+	txs, _ := simplelru.NewLRU(txLatencyLimit, nil)
+	return &txLatencyTracker{
+		consensus: "unknown",
+		txs:       txs,
+	}
+}
+
+func (t *txLatencyTracker) setConsensus(consensus string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.consensus = consensus
+}
+
+// admit starts measuring the latency of a transaction accepted by the pool.
+func (t *txLatencyTracker) admit(tx *types.Transaction) {
+	if !metrics.Enabled {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hash := tx.Hash()
+	if t.txs.Contains(hash) {
+		return
+	}
+	now := time.Now()
+	for {
+		_, oldest, ok := t.txs.GetOldest()
+		if !ok || now.Sub(oldest.(*txLatencyEntry).admitted) <= txLatencyExpiry {
+			break
+		}
+		t.txs.RemoveOldest()
+	}
+	t.txs.Add(hash, &txLatencyEntry{admitted: now, private: tx.IsPrivate()})
+}
+
+// include measures the latency of the tracked transactions of a block about
+// to be inserted in the chain.
+func (t *txLatencyTracker) include(block *types.Block) {
+	if !metrics.Enabled {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, tx := range block.Transactions() {
+		if value, ok := t.txs.Peek(tx.Hash()); ok {
+			if entry := value.(*txLatencyEntry); !entry.included {
+				entry.included = true
+				t.timer(entry, "inclusion").Update(now.Sub(entry.admitted))
+			}
+		}
+	}
+}
+
+// commit measures the latency of the tracked transactions of a block whose
+// state is committed, and stops tracking them.
+func (t *txLatencyTracker) commit(block *types.Block) {
+	if !metrics.Enabled {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, tx := range block.Transactions() {
+		hash := tx.Hash()
+		if value, ok := t.txs.Peek(hash); ok {
+			entry := value.(*txLatencyEntry)
+			t.timer(entry, "commit").Update(now.Sub(entry.admitted))
+			t.txs.Remove(hash)
+		}
+	}
+}
+
+// timer returns the timer of a stage of the transaction, e.g.
+// txlatency/istanbul/public/inclusion. It must be called with t.mu held.
+func (t *txLatencyTracker) timer(entry *txLatencyEntry, stage string) metrics.Timer {
+	kind := "public"
+	if entry.private {
+		kind = "private"
+	}
+	return metrics.GetOrRegisterTimer("txlatency/"+t.consensus+"/"+kind+"/"+stage, nil)
+}
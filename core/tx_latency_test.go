@@ -0,0 +1,61 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxLatencyTracker(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	tracker := newTxLatencyTracker()
+	tracker.setConsensus("test")
+
+	public := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	private := types.NewTransaction(1, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	private.SetPrivate()
+	untracked := types.NewTransaction(2, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(types.Transactions{public, private, untracked}, nil)
+
+	tracker.admit(public)
+	tracker.admit(private)
+	assert.Equal(t, 2, tracker.txs.Len())
+
+	tracker.include(block)
+	tracker.include(block)
+	tracker.commit(block)
+
+	assert.Equal(t, 0, tracker.txs.Len())
+	for _, name := range []string{"public/inclusion", "public/commit", "private/inclusion", "private/commit"} {
+		timer, ok := metrics.DefaultRegistry.Get("txlatency/test/" + name).(metrics.Timer)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, int64(1), timer.Count(), name)
+		}
+	}
+}
+
+func TestTxLatencyTracker_admitDropsExpired(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	tracker := newTxLatencyTracker()
+	lost := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	pending := types.NewTransaction(1, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	tracker.txs.Add(lost.Hash(), &txLatencyEntry{admitted: time.Now().Add(-txLatencyExpiry - time.Minute)})
+	tracker.txs.Add(pending.Hash(), &txLatencyEntry{admitted: time.Now()})
+
+	tracker.admit(types.NewTransaction(2, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil))
+
+	assert.Equal(t, 2, tracker.txs.Len())
+	assert.False(t, tracker.txs.Contains(lost.Hash()))
+	assert.True(t, tracker.txs.Contains(pending.Hash()))
+}
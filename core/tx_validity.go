@@ -0,0 +1,96 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Quorum
+
+var (
+	// ErrTxValidityNotEnabled is returned if a transaction has a validity window before
+	// the transaction validity fork
+	ErrTxValidityNotEnabled = errors.New("transaction validity window not enabled")
+
+	// ErrTxExpired is returned if a transaction is past its validity window
+	ErrTxExpired = errors.New("transaction expired")
+)
+
+// expiredTxMeter counts the transactions evicted from the pool past their validity window
+var expiredTxMeter = metrics.NewRegisteredMeter("txpool/expired", nil)
+
+// isRaftConfig reports whether the chain is a raft chain, whose block timestamps are
+// in nanoseconds
+func isRaftConfig(config *params.ChainConfig) bool {
+	return config.IsQuorum && config.Istanbul == nil && config.Clique == nil
+}
+
+// checkTxValidity checks a transaction can be included in a block with the number and
+// the timestamp, in the unit of the block timestamps of the chain
+func checkTxValidity(config *params.ChainConfig, tx *types.Transaction, number *big.Int, timestamp uint64) error {
+	if tx.Validity() == nil {
+		return nil
+	}
+	if !config.IsTransactionValidityEnabled(number) {
+		return ErrTxValidityNotEnabled
+	}
+	if isRaftConfig(config) {
+		timestamp /= 1e9
+	}
+	if tx.IsExpired(number.Uint64(), timestamp) {
+		return ErrTxExpired
+	}
+	return nil
+}
+
+// checkTxValidityAfter checks a transaction can be included in the block after the head,
+// minted now
+func checkTxValidityAfter(config *params.ChainConfig, tx *types.Transaction, head *types.Header) error {
+	now := time.Now()
+	timestamp := uint64(now.Unix())
+	if isRaftConfig(config) {
+		timestamp = uint64(now.UnixNano())
+	}
+	return checkTxValidity(config, tx, new(big.Int).Add(head.Number, common.Big1), timestamp)
+}
+
+// removeExpired evicts the transactions which can't be included in the block after the
+// head anymore, the caller holds the pool lock
+func (pool *TxPool) removeExpired() {
+	head := pool.chain.CurrentBlock().Header()
+	var expired []common.Hash
+	pool.all.Range(func(hash common.Hash, tx *types.Transaction) bool {
+		if tx.Validity() != nil && checkTxValidityAfter(pool.chainconfig, tx, head) != nil {
+			expired = append(expired, hash)
+		}
+		return true
+	})
+	for _, hash := range expired {
+		log.Trace("Removing expired transaction", "hash", hash)
+		pool.removeTx(hash, true)
+	}
+	expiredTxMeter.Mark(int64(len(expired)))
+}
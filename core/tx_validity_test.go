@@ -0,0 +1,46 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTxValidity(t *testing.T) {
+	config := &params.ChainConfig{IsQuorum: true, Istanbul: &params.IstanbulConfig{}, TransactionValidityBlock: big.NewInt(5)}
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+
+	assert.NoError(t, checkTxValidity(config, tx, big.NewInt(1), 0))
+	assert.Equal(t, ErrTxValidityNotEnabled, checkTxValidity(config, tx.WithValidity(10, 0), big.NewInt(4), 0))
+	assert.NoError(t, checkTxValidity(config, tx.WithValidity(10, 0), big.NewInt(10), 0))
+	assert.Equal(t, ErrTxExpired, checkTxValidity(config, tx.WithValidity(10, 0), big.NewInt(11), 0))
+	assert.Equal(t, ErrTxExpired, checkTxValidity(config, tx.WithValidity(0, 1000), big.NewInt(6), 1001))
+}
+
+func TestCheckTxValidity_raftTimestamps(t *testing.T) {
+	config := &params.ChainConfig{IsQuorum: true, TransactionValidityBlock: big.NewInt(0)}
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil).WithValidity(0, 1000)
+
+	assert.NoError(t, checkTxValidity(config, tx, big.NewInt(1), 1000*1e9))
+	assert.Equal(t, ErrTxExpired, checkTxValidity(config, tx, big.NewInt(1), 1001*1e9))
+}
@@ -311,6 +311,18 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	return decodeV4StoredReceiptRLP(r, blob)
 }
 
+// Quorum
+// IsLegacyStoredReceipt reports whether a stored receipt is in the encoding of the
+// database version 3 or 4, which is still decoded but no longer written
+func IsLegacyStoredReceipt(blob []byte) bool {
+	r := new(ReceiptForStorage)
+	if decodeStoredMPSReceiptRLPWithRevertReason(r, blob) == nil || decodeStoredMPSReceiptRLP(r, blob) == nil ||
+		decodeStoredReceiptRLPWithRevertReason(r, blob) == nil || decodeStoredReceiptRLP(r, blob) == nil {
+		return false
+	}
+	return decodeV3StoredReceiptRLP(r, blob) == nil || decodeV4StoredReceiptRLP(r, blob) == nil
+}
+
 func decodeStoredReceiptRLP(r *ReceiptForStorage, blob []byte) error {
 	var stored storedReceiptRLP
 	if err := rlp.DecodeBytes(blob, &stored); err != nil {
@@ -139,6 +139,32 @@ func TestLegacyReceiptDecodingWithRevertReason(t *testing.T) {
 	}
 }
 
+func TestIsLegacyStoredReceipt(t *testing.T) {
+	receipt := &Receipt{
+		Status:            ReceiptStatusSuccessful,
+		CumulativeGasUsed: 1,
+		Logs:              []*Log{{Address: common.BytesToAddress([]byte{0x11}), Data: []byte{0x01}}},
+		GasUsed:           1,
+	}
+	receipt.Bloom = CreateBloom(Receipts{receipt})
+
+	for _, tc := range []struct {
+		name   string
+		encode func(*Receipt) ([]byte, error)
+		legacy bool
+	}{
+		{"StoredReceiptRLP", encodeAsStoredReceiptRLP, false},
+		{"StoredReceiptRLPWithRevertReason", encodeAsStoredReceiptRLPWithRevertReason, false},
+		{"V4StoredReceiptRLP", encodeAsV4StoredReceiptRLP, true},
+		{"V3StoredReceiptRLP", encodeAsV3StoredReceiptRLP, true},
+	} {
+		enc, err := tc.encode(receipt)
+		assert.NoError(t, err)
+
+		assert.Equal(t, tc.legacy, IsLegacyStoredReceipt(enc), tc.name)
+	}
+}
+
 func TestMPSReceiptDecoding(t *testing.T) {
 	tx := NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil)
 	psiReceipt := &Receipt{
@@ -81,6 +81,10 @@ type txdata struct {
 
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
+
+	// Quorum: the validity window of the transaction, at most one, none for most
+	// transactions. It must stay the last field.
+	Validity []*TxValidity `json:"validity,omitempty" rlp:"tail"`
 }
 
 type txdataMarshaling struct {
@@ -171,6 +175,12 @@ func (tx *Transaction) EncodeRLP(w io.Writer) error {
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 	_, size, _ := s.Kind()
 	err := s.Decode(&tx.data)
+	// Quorum
+	if err == nil && len(tx.data.Validity) > 1 {
+		err = ErrTooManyValidities
+	} else if len(tx.data.Validity) == 0 {
+		tx.data.Validity = nil
+	}
 	if err == nil {
 		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
 		tx.time = time.Now()
@@ -88,6 +88,18 @@ func Sender(signer Signer, tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// Quorum
+// CachedSender returns the address cached by a previous Sender call with signer,
+// without deriving it. It is false if the address derived with signer isn't cached.
+func CachedSender(signer Signer, tx *Transaction) (common.Address, bool) {
+	if sc := tx.from.Load(); sc != nil {
+		if sigCache := sc.(sigCache); sigCache.signer.Equal(signer) {
+			return sigCache.from, true
+		}
+	}
+	return common.Address{}, false
+}
+
 // Signer encapsulates transaction signature handling. Note that this interface is not a
 // stable API and may change at any time to accommodate new protocol rules.
 type Signer interface {
@@ -156,7 +168,7 @@ func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
+	return rlpHash(append([]interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -164,7 +176,7 @@ func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
 		tx.data.Amount,
 		tx.data.Payload,
 		s.chainId, uint(0), uint(0),
-	})
+	}, tx.validitySigningFields()...)) // Quorum: the validity window is signed
 }
 
 // HomesteadTransaction implements TransactionInterface using the
@@ -208,14 +220,14 @@ func (fs FrontierSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v *
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
+	return rlpHash(append([]interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
 		tx.data.Recipient,
 		tx.data.Amount,
 		tx.data.Payload,
-	})
+	}, tx.validitySigningFields()...)) // Quorum: the validity window is signed
 }
 
 func (fs FrontierSigner) Sender(tx *Transaction) (common.Address, error) {
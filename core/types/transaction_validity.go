@@ -0,0 +1,101 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Quorum
+
+// ErrTooManyValidities is returned when decoding a transaction with more than one
+// validity window
+var ErrTooManyValidities = errors.New("transaction with more than one validity window")
+
+// TxValidity is the window in which a transaction may be included in a block. It is
+// encoded after the signature of the transactions which have one, and signed with
+// them, so that the encoding and the hash of the other transactions are unchanged.
+type TxValidity struct {
+	Block uint64 // last block number the transaction may be included in, 0 for no limit
+	Time  uint64 // last block time in seconds the transaction may be included at, 0 for no limit
+}
+
+type txValidityJSON struct {
+	Block hexutil.Uint64 `json:"validUntilBlock"`
+	Time  hexutil.Uint64 `json:"validUntilTime"`
+}
+
+// MarshalJSON marshals as JSON.
+func (v *TxValidity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&txValidityJSON{Block: hexutil.Uint64(v.Block), Time: hexutil.Uint64(v.Time)})
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (v *TxValidity) UnmarshalJSON(input []byte) error {
+	var dec txValidityJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	v.Block, v.Time = uint64(dec.Block), uint64(dec.Time)
+	return nil
+}
+
+// WithValidity returns a copy of the unsigned transaction valid until the block number
+// validUntilBlock or the block time validUntilTime (in seconds), 0 for no limit.
+func (tx *Transaction) WithValidity(validUntilBlock, validUntilTime uint64) *Transaction {
+	cpy := &Transaction{
+		data:            tx.data,
+		time:            tx.time,
+		privacyMetadata: tx.privacyMetadata,
+	}
+	cpy.data.Validity = nil
+	if validUntilBlock != 0 || validUntilTime != 0 {
+		cpy.data.Validity = []*TxValidity{{Block: validUntilBlock, Time: validUntilTime}}
+	}
+	return cpy
+}
+
+// Validity returns the validity window of the transaction, nil if it has none
+func (tx *Transaction) Validity() *TxValidity {
+	if len(tx.data.Validity) == 0 {
+		return nil
+	}
+	v := *tx.data.Validity[0]
+	return &v
+}
+
+// IsExpired reports whether the transaction can't be included in a block with the
+// number and the time in seconds
+func (tx *Transaction) IsExpired(number uint64, time uint64) bool {
+	v := tx.Validity()
+	if v == nil {
+		return false
+	}
+	return (v.Block != 0 && number > v.Block) || (v.Time != 0 && time > v.Time)
+}
+
+// validitySigningFields returns the fields of the validity window appended to the
+// signed fields of the transaction, none if it has no validity window
+func (tx *Transaction) validitySigningFields() []interface{} {
+	if v := tx.Validity(); v != nil {
+		return []interface{}{v.Block, v.Time}
+	}
+	return nil
+}
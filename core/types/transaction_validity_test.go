@@ -0,0 +1,89 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_WithValidity(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := NewEIP155Signer(big.NewInt(10))
+	unsigned := NewTransaction(1, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+
+	legacy, err := SignTx(unsigned, signer, key)
+	require.NoError(t, err)
+	tx, err := SignTx(unsigned.WithValidity(100, 1600000000), signer, key)
+	require.NoError(t, err)
+
+	assert.Nil(t, legacy.Validity())
+	assert.Equal(t, signer.Hash(unsigned), signer.Hash(unsigned.WithValidity(0, 0)), "a transaction without validity window is unchanged")
+	assert.NotEqual(t, signer.Hash(unsigned), signer.Hash(unsigned.WithValidity(100, 0)))
+	assert.NotEqual(t, legacy.Hash(), tx.Hash())
+
+	enc, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	var decoded Transaction
+	require.NoError(t, rlp.DecodeBytes(enc, &decoded))
+	assert.Equal(t, tx.Hash(), decoded.Hash())
+	assert.Equal(t, &TxValidity{Block: 100, Time: 1600000000}, decoded.Validity())
+	from, err := Sender(signer, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), from)
+
+	// the validity window is signed
+	tampered := decoded.WithValidity(200, 0)
+	tampered.data.V, tampered.data.R, tampered.data.S = decoded.RawSignatureValues()
+	from, err = Sender(signer, tampered)
+	if err == nil {
+		assert.NotEqual(t, crypto.PubkeyToAddress(key.PublicKey), from)
+	}
+
+	js, err := json.Marshal(tx)
+	require.NoError(t, err)
+	var fromJSON Transaction
+	require.NoError(t, json.Unmarshal(js, &fromJSON))
+	assert.Equal(t, tx.Hash(), fromJSON.Hash())
+}
+
+func TestTransaction_IsExpired(t *testing.T) {
+	tx := NewTransaction(1, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)
+
+	assert.False(t, tx.IsExpired(1000, 2000))
+	assert.False(t, tx.WithValidity(10, 0).IsExpired(10, 2000))
+	assert.True(t, tx.WithValidity(10, 0).IsExpired(11, 0))
+	assert.False(t, tx.WithValidity(0, 100).IsExpired(1000, 100))
+	assert.True(t, tx.WithValidity(0, 100).IsExpired(1, 101))
+}
+
+func TestTransaction_DecodeRLP_whenTooManyValidities(t *testing.T) {
+	tx := NewTransaction(1, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil).WithValidity(1, 1)
+	tx.data.Validity = append(tx.data.Validity, &TxValidity{Block: 2})
+	enc, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+
+	var decoded Transaction
+	assert.Equal(t, ErrTooManyValidities, rlp.DecodeBytes(enc, &decoded))
+}
@@ -0,0 +1,400 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Quorum
+
+// maxVerifyStateSearch is the number of blocks searched back from a sampled range for
+// a state to re-execute the range from
+const maxVerifyStateSearch = 128
+
+// Kinds of the corruptions
+const (
+	CorruptionHead         = "head"
+	CorruptionFreezer      = "freezer"
+	CorruptionCanonical    = "canonical"
+	CorruptionHeader       = "header"
+	CorruptionBody         = "body"
+	CorruptionReceipts     = "receipts"
+	CorruptionTd           = "td"
+	CorruptionState        = "state"
+	CorruptionPrivateState = "private state"
+)
+
+// the actions suggested to repair the corruptions
+const (
+	actionRewind = "rewind the chain below the block with debug_setHead, the node syncs the blocks again from its peers"
+	actionResync = "restore a backup or resync the node, the freezer can't be rewound below the corrupted block"
+)
+
+// VerifyConfig selects the blocks verified by VerifyChain
+type VerifyConfig struct {
+	From uint64 // first block verified
+	To   uint64 // last block verified, the head if 0
+	// Samples is the number of ranges of blocks re-executed to recompute their public
+	// and private state roots, spread over the blocks verified
+	Samples int
+	// SampleSize is the number of blocks re-executed per range
+	SampleSize uint64
+}
+
+// Corruption is an inconsistency of the database
+type Corruption struct {
+	Kind     string
+	Number   uint64
+	Hash     common.Hash // zero if unknown
+	Location string      // store and key or table of the corrupted data
+	Message  string
+	Action   string // suggested repair
+}
+
+func (c *Corruption) String() string {
+	return fmt.Sprintf("block #%d %x: %s: %s (at %s), %s", c.Number, c.Hash, c.Kind, c.Message, c.Location, c.Action)
+}
+
+// VerifySummary summarizes the verification of a chain
+type VerifySummary struct {
+	From, To       uint64
+	Ancients       uint64 // number of frozen blocks
+	Executed       uint64 // number of blocks re-executed
+	SkippedSamples int    // number of ranges without state to re-execute them from
+	Corruptions    int
+}
+
+// VerifyChain walks the headers, bodies and receipts of the canonical chain, checks
+// the freezer against the key-value store, and re-executes sampled ranges of blocks
+// to recompute their public and private state roots. Each corruption found is
+// reported, the verification goes on. The chain must not be processing blocks.
+func VerifyChain(bc *BlockChain, config *VerifyConfig, report func(*Corruption)) (*VerifySummary, error) {
+	v := &chainVerifier{bc: bc, report: report}
+	summary, err := v.verify(config)
+	if err != nil {
+		return nil, err
+	}
+	summary.Corruptions = v.corruptions
+	return summary, nil
+}
+
+type chainVerifier struct {
+	bc          *BlockChain
+	ancients    uint64
+	report      func(*Corruption)
+	corruptions int
+}
+
+func (v *chainVerifier) corrupted(c *Corruption) {
+	v.corruptions++
+	v.report(c)
+}
+
+// location returns the location of the data of a block, the freezer table if the
+// block is frozen, the key-value store key otherwise
+func (v *chainVerifier) location(number uint64, table string, key string) string {
+	if number < v.ancients {
+		return "freezer table " + table
+	}
+	return "key-value store " + key
+}
+
+// action returns the repair suggested for a corrupted block
+func (v *chainVerifier) action(number uint64) string {
+	if number < v.ancients {
+		return actionResync
+	}
+	return actionRewind
+}
+
+func (v *chainVerifier) verify(config *VerifyConfig) (*VerifySummary, error) {
+	db := v.bc.db
+	ancients, err := db.Ancients()
+	if err != nil {
+		return nil, err
+	}
+	v.ancients = ancients
+	head := v.bc.CurrentBlock().NumberU64()
+	to := config.To
+	if to == 0 || to > head {
+		to = head
+	}
+	if config.From > to {
+		return nil, fmt.Errorf("first block #%d after the last block #%d", config.From, to)
+	}
+	v.verifyHeads()
+	if ancients > 0 && ancients-1 > v.bc.CurrentHeader().Number.Uint64() {
+		v.corrupted(&Corruption{
+			Kind:     CorruptionFreezer,
+			Number:   ancients - 1,
+			Location: "freezer",
+			Message:  fmt.Sprintf("the freezer holds %d blocks, beyond the head header #%d", ancients, v.bc.CurrentHeader().Number),
+			Action:   actionResync,
+		})
+	}
+	log.Info("Verifying the chain", "from", config.From, "to", to, "ancients", ancients)
+	var parent common.Hash
+	if config.From > 0 {
+		parent = rawdb.ReadCanonicalHash(db, config.From-1)
+	}
+	for number := config.From; number <= to; number++ {
+		parent = v.verifyBlock(number, parent)
+		if number%100000 == 0 && number > config.From {
+			log.Info("Verifying the chain", "number", number, "corruptions", v.corruptions)
+		}
+	}
+	summary := &VerifySummary{From: config.From, To: to, Ancients: ancients}
+	for _, r := range sampleRanges(config.From, to, config.Samples, config.SampleSize) {
+		executed, ok := v.reexecute(r[0], r[1])
+		summary.Executed += executed
+		if !ok {
+			summary.SkippedSamples++
+		}
+	}
+	return summary, nil
+}
+
+// verifyHeads checks the head markers point to canonical blocks
+func (v *chainVerifier) verifyHeads() {
+	db := v.bc.db
+	for _, head := range []struct {
+		name string
+		hash common.Hash
+	}{
+		{"LastHeader", rawdb.ReadHeadHeaderHash(db)},
+		{"LastBlock", rawdb.ReadHeadBlockHash(db)},
+		{"LastFast", rawdb.ReadHeadFastBlockHash(db)},
+	} {
+		if head.hash == (common.Hash{}) {
+			continue
+		}
+		number := rawdb.ReadHeaderNumber(db, head.hash)
+		if number == nil {
+			v.corrupted(&Corruption{Kind: CorruptionHead, Hash: head.hash, Location: "key-value store " + head.name, Message: "the head is an unknown block", Action: actionRewind})
+			continue
+		}
+		if canonical := rawdb.ReadCanonicalHash(db, *number); canonical != head.hash {
+			v.corrupted(&Corruption{Kind: CorruptionHead, Number: *number, Hash: head.hash, Location: "key-value store " + head.name, Message: fmt.Sprintf("the head is not the canonical block %x", canonical), Action: actionRewind})
+		}
+	}
+}
+
+// verifyBlock checks a canonical block is complete and consistent, and returns its hash
+func (v *chainVerifier) verifyBlock(number uint64, parent common.Hash) common.Hash {
+	db := v.bc.db
+	if number < v.ancients {
+		if table, err := rawdb.CheckAncientBlock(db, number); err != nil {
+			v.corrupted(&Corruption{Kind: CorruptionFreezer, Number: number, Location: "freezer table " + table, Message: err.Error(), Action: actionResync})
+		}
+	}
+	hash := rawdb.ReadCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		v.corrupted(&Corruption{Kind: CorruptionCanonical, Number: number, Location: v.location(number, "hashes", "h<number>n"), Message: "no canonical block", Action: v.action(number)})
+		return common.Hash{}
+	}
+	corrupted := func(kind, table, key, format string, args ...interface{}) {
+		v.corrupted(&Corruption{Kind: kind, Number: number, Hash: hash, Location: v.location(number, table, key), Message: fmt.Sprintf(format, args...), Action: v.action(number)})
+	}
+	if n := rawdb.ReadHeaderNumber(db, hash); n == nil || *n != number {
+		v.corrupted(&Corruption{Kind: CorruptionCanonical, Number: number, Hash: hash, Location: "key-value store H<hash>", Message: "the number of the canonical block is missing or wrong", Action: actionRewind})
+	}
+	header := rawdb.ReadHeader(db, hash, number)
+	if header == nil {
+		corrupted(CorruptionHeader, "headers", "h<number><hash>", "header missing")
+		return hash
+	}
+	if header.Hash() != hash {
+		corrupted(CorruptionHeader, "headers", "h<number><hash>", "header hash %x differs from the canonical hash", header.Hash())
+	}
+	if number > 0 && parent != (common.Hash{}) && header.ParentHash != parent {
+		corrupted(CorruptionHeader, "headers", "h<number><hash>", "parent hash %x differs from the canonical block #%d %x", header.ParentHash, number-1, parent)
+	}
+	if rawdb.ReadTd(db, hash, number) == nil {
+		corrupted(CorruptionTd, "diffs", "h<number><hash>t", "total difficulty missing")
+	}
+	body := rawdb.ReadBody(db, hash, number)
+	if body == nil {
+		corrupted(CorruptionBody, "bodies", "b<number><hash>", "body missing")
+		return hash
+	}
+	if txHash := types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)); txHash != header.TxHash {
+		corrupted(CorruptionBody, "bodies", "b<number><hash>", "transaction root %x differs from the header %x", txHash, header.TxHash)
+	}
+	if uncleHash := types.CalcUncleHash(body.Uncles); uncleHash != header.UncleHash {
+		corrupted(CorruptionBody, "bodies", "b<number><hash>", "uncle hash %x differs from the header %x", uncleHash, header.UncleHash)
+	}
+	receipts := rawdb.ReadRawReceipts(db, hash, number)
+	if receipts == nil && len(body.Transactions) > 0 {
+		corrupted(CorruptionReceipts, "receipts", "r<number><hash>", "receipts missing")
+		return hash
+	}
+	if len(receipts) != len(body.Transactions) {
+		corrupted(CorruptionReceipts, "receipts", "r<number><hash>", "%d receipts for %d transactions", len(receipts), len(body.Transactions))
+		return hash
+	}
+	// the receipts of the private transactions are the private receipts, the receipt
+	// root of a block with private transactions is only checked by re-executing it
+	for _, tx := range body.Transactions {
+		if tx.IsPrivate() {
+			return hash
+		}
+	}
+	if receiptHash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); receiptHash != header.ReceiptHash {
+		corrupted(CorruptionReceipts, "receipts", "r<number><hash>", "receipt root %x differs from the header %x", receiptHash, header.ReceiptHash)
+	}
+	return hash
+}
+
+// sampleRanges returns the ranges of blocks re-executed, spread over the blocks from
+// first to last, the last range ending at the last block
+func sampleRanges(first, last uint64, samples int, size uint64) [][2]uint64 {
+	if first == 0 {
+		first = 1 // the genesis block is not executed
+	}
+	if samples <= 0 || size == 0 || first > last {
+		return nil
+	}
+	var ranges [][2]uint64
+	span := last - first + 1
+	for i := 1; i <= samples; i++ {
+		end := first + span*uint64(i)/uint64(samples) - 1
+		start := first
+		if end+1 >= first+size {
+			start = end + 1 - size
+		}
+		if len(ranges) > 0 && start <= ranges[len(ranges)-1][1] {
+			start = ranges[len(ranges)-1][1] + 1
+		}
+		if start <= end {
+			ranges = append(ranges, [2]uint64{start, end})
+		}
+	}
+	return ranges
+}
+
+// reexecute re-executes the blocks from first to last, from the state of the nearest
+// block before first which has its public and private states. It returns the number of
+// blocks executed, and false if no state was found.
+func (v *chainVerifier) reexecute(first, last uint64) (uint64, bool) {
+	base := v.stateBase(first)
+	if base == nil {
+		log.Warn("No state to re-execute the blocks from, skipping them", "first", first, "last", last)
+		return 0, false
+	}
+	statedb, privateStateRepo, err := v.bc.StateAt(base.Root())
+	if err != nil {
+		log.Warn("Failed to open the state to re-execute the blocks from", "number", base.NumberU64(), "err", err)
+		return 0, false
+	}
+	log.Info("Re-executing blocks", "from", base.NumberU64()+1, "to", last)
+	var executed uint64
+	for number := base.NumberU64() + 1; number <= last; number++ {
+		block := v.bc.GetBlockByNumber(number)
+		if block == nil {
+			// reported by the walk of the chain
+			return executed, true
+		}
+		corrupted := func(kind, location, format string, args ...interface{}) {
+			v.corrupted(&Corruption{Kind: kind, Number: number, Hash: block.Hash(), Location: location, Message: fmt.Sprintf(format, args...), Action: v.action(number)})
+		}
+		receipts, _, _, usedGas, err := v.bc.Processor().Process(block, statedb, privateStateRepo, *v.bc.GetVMConfig())
+		if err == nil {
+			err = v.bc.Validator().ValidateState(block, statedb, receipts, usedGas)
+		}
+		executed++
+		if err != nil {
+			corrupted(CorruptionState, "public state", "re-execution: %v", err)
+			// the next blocks would be executed on a wrong state
+			return executed, true
+		}
+		// the private contracts moved to the cold store with the block are moved again
+		if coldDb := v.bc.cacheConfig.PrivateColdStore; coldDb != nil {
+			if err := replayColdMoves(coldDb, block.Hash(), privateStateRepo); err != nil {
+				corrupted(CorruptionPrivateState, "cold store", "re-execution: %v", err)
+				return executed, true
+			}
+		}
+		if !v.verifyPrivateStates(block, privateStateRepo, corrupted) {
+			return executed, true
+		}
+		isEIP158 := v.bc.Config().IsEIP158(block.Number())
+		if _, err := statedb.Commit(isEIP158); err != nil {
+			log.Warn("Failed to commit the re-executed state", "number", number, "err", err)
+			return executed, true
+		}
+		if err := privateStateRepo.Commit(isEIP158, block); err != nil {
+			log.Warn("Failed to commit the re-executed private states", "number", number, "err", err)
+			return executed, true
+		}
+	}
+	return executed, true
+}
+
+// verifyPrivateStates compares the private states recomputed by the re-execution of a
+// block with the ones stored, and reports whether they match
+func (v *chainVerifier) verifyPrivateStates(block *types.Block, recomputed mps.PrivateStateRepository, corrupted func(kind, location, format string, args ...interface{})) bool {
+	location := "key-value store P<state root>"
+	if recomputed.IsMPS() {
+		location = "key-value store PSTP<state root>"
+	}
+	stored, err := v.bc.PrivateStateManager().StateRepository(block.Root())
+	if err != nil {
+		corrupted(CorruptionPrivateState, location, "private states missing: %v", err)
+		return false
+	}
+	isEIP158 := v.bc.Config().IsEIP158(block.Number())
+	for _, psi := range v.bc.PrivateStateManager().PSIs() {
+		storedState, err := stored.StatePSI(psi)
+		if err != nil {
+			corrupted(CorruptionPrivateState, location, "private state %s missing: %v", psi, err)
+			return false
+		}
+		recomputedState, err := recomputed.StatePSI(psi)
+		if err != nil {
+			corrupted(CorruptionPrivateState, location, "private state %s: %v", psi, err)
+			return false
+		}
+		if storedRoot, root := storedState.IntermediateRoot(isEIP158), recomputedState.IntermediateRoot(isEIP158); storedRoot != root {
+			corrupted(CorruptionPrivateState, location, "private state %s root %x differs from the re-executed root %x", psi, storedRoot, root)
+			return false
+		}
+	}
+	return true
+}
+
+// stateBase returns the nearest block before number whose public and private states
+// are in the database, nil if there is none within maxVerifyStateSearch blocks
+func (v *chainVerifier) stateBase(number uint64) *types.Block {
+	for i := uint64(1); i <= maxVerifyStateSearch && i <= number; i++ {
+		block := v.bc.GetBlockByNumber(number - i)
+		if block == nil {
+			return nil
+		}
+		if v.bc.HasState(block.Root()) && v.bc.PrivateStateManager().CheckAt(block.Root()) == nil {
+			return block
+		}
+	}
+	return nil
+}
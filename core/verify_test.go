@@ -0,0 +1,116 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVerifiedChain returns an archive chain of blocks with transactions
+func newVerifiedChain(t *testing.T, n int) *BlockChain {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, n, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{1}, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		require.NoError(t, err)
+		block.AddTx(tx)
+	})
+	archiveCaching := *defaultCacheConfig
+	archiveCaching.TrieDirtyDisabled = true
+	chain, err := NewBlockChain(db, &archiveCaching, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err)
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+	return chain
+}
+
+func verifyChain(t *testing.T, chain *BlockChain) (*VerifySummary, []*Corruption) {
+	var corruptions []*Corruption
+	summary, err := VerifyChain(chain, &VerifyConfig{Samples: 2, SampleSize: 3}, func(c *Corruption) {
+		corruptions = append(corruptions, c)
+	})
+	require.NoError(t, err)
+	return summary, corruptions
+}
+
+func TestVerifyChain(t *testing.T) {
+	chain := newVerifiedChain(t, 10)
+	defer chain.Stop()
+
+	summary, corruptions := verifyChain(t, chain)
+
+	assert.Empty(t, corruptions)
+	assert.Equal(t, uint64(10), summary.To)
+	assert.Equal(t, uint64(6), summary.Executed)
+	assert.Zero(t, summary.SkippedSamples)
+}
+
+func TestVerifyChain_whenReceiptsMissing(t *testing.T) {
+	chain := newVerifiedChain(t, 10)
+	defer chain.Stop()
+	block := chain.GetBlockByNumber(4)
+	rawdb.DeleteReceipts(chain.db, block.Hash(), 4)
+
+	summary, corruptions := verifyChain(t, chain)
+
+	require.Len(t, corruptions, 1)
+	assert.Equal(t, 1, summary.Corruptions)
+	assert.Equal(t, CorruptionReceipts, corruptions[0].Kind)
+	assert.Equal(t, uint64(4), corruptions[0].Number)
+	assert.Equal(t, block.Hash(), corruptions[0].Hash)
+	assert.Equal(t, "key-value store r<number><hash>", corruptions[0].Location)
+	assert.Equal(t, actionRewind, corruptions[0].Action)
+}
+
+func TestVerifyChain_whenBodyCorrupted(t *testing.T) {
+	chain := newVerifiedChain(t, 10)
+	defer chain.Stop()
+	block := chain.GetBlockByNumber(9)
+	rawdb.WriteBody(chain.db, block.Hash(), 9, chain.GetBlockByNumber(8).Body())
+
+	_, corruptions := verifyChain(t, chain)
+
+	require.NotEmpty(t, corruptions)
+	assert.Equal(t, CorruptionBody, corruptions[0].Kind)
+	assert.Equal(t, uint64(9), corruptions[0].Number)
+}
+
+func TestSampleRanges(t *testing.T) {
+	assert.Equal(t, [][2]uint64{{3, 5}, {8, 10}}, sampleRanges(0, 10, 2, 3))
+	assert.Equal(t, [][2]uint64{{1, 5}, {6, 10}}, sampleRanges(0, 10, 2, 100))
+	assert.Nil(t, sampleRanges(0, 10, 0, 3))
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessListTracer(t *testing.T) {
+	var (
+		from, to    = common.Address{1}, common.Address{2}
+		precompiles = ActivePrecompiles(params.TestChainConfig.Rules(big.NewInt(0)))
+		tracer      = NewAccessListTracer(nil, from, to, precompiles)
+		env         = NewEVM(Context{}, nil, nil, params.TestChainConfig, Config{})
+		mem         = NewMemory()
+		rstack      = newReturnStack()
+		contract    = NewContract(AccountRef(from), AccountRef(to), new(big.Int), 0)
+	)
+	capture := func(op OpCode, args ...common.Address) {
+		stack := newstack()
+		for _, arg := range args {
+			stack.push(new(uint256.Int).SetBytes(arg.Bytes()))
+		}
+		tracer.CaptureState(env, 0, op, 0, 0, mem, stack, rstack, nil, contract, 0, nil)
+	}
+	capture(SLOAD, common.Address{0x11})
+	capture(BALANCE, common.Address{3})
+	capture(BALANCE, from)
+	capture(EXTCODESIZE, precompiles[0])
+	capture(CALL, common.Address{}, common.Address{}, common.Address{}, common.Address{4}, common.Address{})
+
+	acl := tracer.AccessList()
+	assert.Len(t, acl, 3)
+	assert.Equal(t, 1, acl.StorageKeys())
+	for _, tuple := range acl {
+		switch tuple.Address {
+		case to:
+			assert.Equal(t, []common.Hash{common.BytesToHash(common.Address{0x11}.Bytes())}, tuple.StorageKeys)
+		case common.Address{3}, common.Address{4}:
+			assert.Empty(t, tuple.StorageKeys)
+		default:
+			t.Errorf("unexpected address %x in the access list", tuple.Address)
+		}
+	}
+	assert.IsType(t, types.AccessList{}, acl)
+}
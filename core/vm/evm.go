@@ -60,12 +60,17 @@ type (
 // ActivePrecompiles returns the addresses of the precompiles enabled with the current
 // configuration
 func (evm *EVM) ActivePrecompiles() []common.Address {
+	return ActivePrecompiles(evm.chainRules)
+}
+
+// ActivePrecompiles returns the addresses of the precompiles enabled with the given rules
+func ActivePrecompiles(rules params.Rules) []common.Address {
 	switch {
-	case evm.chainRules.IsYoloV2:
+	case rules.IsYoloV2:
 		return PrecompiledAddressesYoloV2
-	case evm.chainRules.IsIstanbul:
+	case rules.IsIstanbul:
 		return PrecompiledAddressesIstanbul
-	case evm.chainRules.IsByzantium:
+	case rules.IsByzantium:
 		return PrecompiledAddressesByzantium
 	default:
 		return PrecompiledAddressesHomestead
@@ -85,6 +90,13 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 		precompiles = PrecompiledContractsHomestead
 	}
 	p, ok := precompiles[addr]
+	// Quorum
+	if ok && evm.gasSchedule != nil {
+		if gas, overridden := evm.gasSchedule.Precompiles[addr]; overridden {
+			p = &fixedGasPrecompile{PrecompiledContract: p, gas: gas}
+		}
+	}
+	// End Quorum
 	return p, ok
 }
 
@@ -192,6 +204,8 @@ type EVM struct {
 	// these are for privacy enhancements and multitenancy
 	affectedContracts map[common.Address]AffectedReason // affected contract account address -> type
 	currentTx         *types.Transaction                // transaction currently being applied on this EVM
+
+	gasSchedule *params.GasScheduleConfig // gas cost overrides of the chain config, nil if none
 }
 
 // AffectedReason defines a type of operation that was applied to a contract.
@@ -218,6 +232,7 @@ func NewEVM(ctx Context, statedb, privateState StateDB, chainConfig *params.Chai
 		privateState: privateState,
 
 		affectedContracts: make(map[common.Address]AffectedReason),
+		gasSchedule:       chainConfig.GetGasSchedule(ctx.BlockNumber),
 	}
 
 	if chainConfig.IsEWASM(ctx.BlockNumber) {
@@ -549,6 +564,17 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if evm.StateDB.GetNonce(address) != 0 || (contractHash != (common.Hash{}) && contractHash != emptyCodeHash) {
 		return nil, common.Address{}, 0, ErrContractAddressCollision
 	}
+	// Quorum
+	// A private contract must not shadow an account of the public state either, the
+	// calls to the address would resolve to the private contract (see getDualState).
+	// The private states are checked one at a time: CREATE2 derives the same address
+	// in all the private states the contract is deployed to.
+	if evm.StateDB == evm.privateState && evm.privateState != evm.publicState && evm.chainConfig.IsPrivateContractCollisionEnabled(evm.BlockNumber) {
+		publicHash := evm.publicState.GetCodeHash(address)
+		if evm.publicState.GetNonce(address) != 0 || (publicHash != (common.Hash{}) && publicHash != emptyCodeHash) {
+			return nil, common.Address{}, 0, ErrContractAddressCollision
+		}
+	}
 	// Create a new account on the state
 	snapshot := evm.StateDB.Snapshot()
 	evm.StateDB.CreateAccount(address)
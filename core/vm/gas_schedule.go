@@ -0,0 +1,94 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Quorum
+
+// gasScheduleFixedOpcodes are the opcodes whose cost can't be overridden, their dynamic
+// gas also computes the gas available to the callee
+var gasScheduleFixedOpcodes = map[OpCode]bool{
+	CALL:         true,
+	CALLCODE:     true,
+	DELEGATECALL: true,
+	STATICCALL:   true,
+	CREATE:       true,
+	CREATE2:      true,
+}
+
+// CheckGasScheduleConfig checks the opcodes of the gas schedules of a chain config
+func CheckGasScheduleConfig(config *params.ChainConfig) error {
+	for _, schedule := range config.GasScheduleConfig {
+		for name := range schedule.Opcodes {
+			op, ok := stringToOp[name]
+			if !ok {
+				return fmt.Errorf("unknown opcode %q in the gas schedule of block %v", name, schedule.Block)
+			}
+			if gasScheduleFixedOpcodes[op] {
+				return fmt.Errorf("the cost of %s can't be overridden, in the gas schedule of block %v", name, schedule.Block)
+			}
+		}
+	}
+	return nil
+}
+
+// applyGasSchedule overrides the costs of the opcodes of a jump table. The operations
+// are copied, as they are shared by the jump tables of the forks.
+func applyGasSchedule(jt *JumpTable, schedule *params.GasScheduleConfig) {
+	for name, gas := range schedule.Opcodes {
+		code, ok := stringToOp[name]
+		if !ok || gasScheduleFixedOpcodes[code] || jt[code] == nil {
+			continue
+		}
+		op := *jt[code]
+		op.constantGas, op.dynamicGas = gas, nil
+		switch {
+		case code == SSTORE:
+			op.constantGas, op.dynamicGas = 0, makeGasSStoreOverride(gas)
+		case op.memorySize != nil:
+			op.dynamicGas = pureMemoryGascost
+		}
+		jt[code] = &op
+	}
+}
+
+// makeGasSStoreOverride returns the gas function of an overridden SSTORE, which keeps
+// the EIP-2200 protection against reentrancy: it fails if the gas left is within the
+// stipend of a call.
+func makeGasSStoreOverride(gas uint64) gasFunc {
+	return func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		if evm.chainRules.IsIstanbul && contract.Gas <= params.SstoreSentryGasEIP2200 {
+			return 0, ErrOutOfGas
+		}
+		return gas, nil
+	}
+}
+
+// fixedGasPrecompile is a precompiled contract whose cost is overridden by a gas schedule
+type fixedGasPrecompile struct {
+	PrecompiledContract
+	gas uint64
+}
+
+func (p *fixedGasPrecompile) RequiredGas(input []byte) uint64 {
+	return p.gas
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var gasScheduleTests = []struct {
+	block   int64
+	to      common.Address
+	gaspool uint64
+	used    uint64
+	failure error
+}{
+	{9, common.BytesToAddress([]byte("contract")), 100000, 20006, nil},       // 0 -> 1, before the gas schedule
+	{10, common.BytesToAddress([]byte("contract")), 100000, 106, nil},        // 0 -> 1, overridden SSTORE
+	{10, common.BytesToAddress([]byte("contract")), 2306, 2306, ErrOutOfGas}, // 2300 sentry + 2xPUSH
+	{9, common.BytesToAddress([]byte{2}), 100000, 60, nil},                   // sha256 of empty input
+	{10, common.BytesToAddress([]byte{2}), 100000, 7, nil},                   // overridden sha256
+}
+
+func TestGasScheduleOverrides(t *testing.T) {
+	config := *params.AllEthashProtocolChanges
+	config.GasScheduleConfig = []params.GasScheduleConfig{{
+		Block:       big.NewInt(10),
+		Opcodes:     map[string]uint64{"SSTORE": 100},
+		Precompiles: map[common.Address]uint64{common.BytesToAddress([]byte{2}): 7},
+	}}
+	for i, tt := range gasScheduleTests {
+		address := common.BytesToAddress([]byte("contract"))
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, hexutil.MustDecode("0x6001600055"))
+		statedb.Finalise(true)
+
+		vmctx := Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: big.NewInt(tt.block),
+		}
+		vmenv := NewEVM(vmctx, statedb, statedb, &config, Config{})
+
+		_, gas, err := vmenv.Call(AccountRef(common.Address{}), tt.to, nil, tt.gaspool, new(big.Int))
+		if err != tt.failure {
+			t.Errorf("test %d: failure mismatch: have %v, want %v", i, err, tt.failure)
+		}
+		if used := tt.gaspool - gas; used != tt.used {
+			t.Errorf("test %d: gas used mismatch: have %v, want %v", i, used, tt.used)
+		}
+	}
+}
+
+func TestCheckGasScheduleConfig(t *testing.T) {
+	tests := []struct {
+		opcode string
+		valid  bool
+	}{
+		{"SSTORE", true},
+		{"SHA3", true},
+		{"sstore", false},
+		{"CALL", false},
+		{"CREATE2", false},
+	}
+	for _, tt := range tests {
+		config := &params.ChainConfig{GasScheduleConfig: []params.GasScheduleConfig{{
+			Block:   big.NewInt(1),
+			Opcodes: map[string]uint64{tt.opcode: 1},
+		}}}
+		if err := CheckGasScheduleConfig(config); (err == nil) != tt.valid {
+			t.Errorf("opcode %s: have error %v, want valid %v", tt.opcode, err, tt.valid)
+		}
+	}
+}
@@ -124,6 +124,10 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 				log.Error("EIP activation failed", "eip", eip, "error", err)
 			}
 		}
+		// Quorum
+		if evm.gasSchedule != nil {
+			applyGasSchedule(&jt, evm.gasSchedule)
+		}
 		cfg.JumpTable = jt
 	}
 
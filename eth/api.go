@@ -238,6 +238,38 @@ func (api *PrivateAdminAPI) ExportChain(file string, first *uint64, last *uint64
 	return true, nil
 }
 
+// Quorum
+// Backup writes a consistent backup of the node into dir, which must not exist, pausing
+// the writes while the snapshot is taken. See Ethereum.Backup.
+func (api *PrivateAdminAPI) Backup(dir string) (*BackupManifest, error) {
+	return api.eth.Backup(dir)
+}
+
+// Quorum
+// PauseMinting stops the block production of the node (raft and istanbul) for maintenance
+// or emergency response, until admin_resumeMinting is called or the timeout in seconds
+// elapses (default 1 hour). It returns right away the time the block production will
+// resume at.
+func (api *PrivateAdminAPI) PauseMinting(timeout *uint64) (time.Time, error) {
+	var d time.Duration
+	if timeout != nil {
+		if *timeout == 0 || *timeout > uint64(MaxMintingPauseTimeout/time.Second) {
+			return time.Time{}, fmt.Errorf("the timeout must be between 1 and %d seconds", uint64(MaxMintingPauseTimeout/time.Second))
+		}
+		d = time.Duration(*timeout) * time.Second
+	}
+	return api.eth.PauseMinting(d)
+}
+
+// Quorum
+// ResumeMinting resumes the block production paused by admin_pauseMinting
+func (api *PrivateAdminAPI) ResumeMinting() (bool, error) {
+	if err := api.eth.ResumeMinting(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	for _, b := range bs {
 		if !chain.HasBlock(b.Hash(), b.NumberU64()) {
@@ -37,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
 	pcore "github.com/ethereum/go-ethereum/permission/core"
@@ -253,9 +254,12 @@ func (b *EthAPIBackend) GetTd(ctx context.Context, hash common.Hash) *big.Int {
 	return b.eth.blockchain.GetTdByHash(hash)
 }
 
-func (b *EthAPIBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header) (*vm.EVM, func() error, error) {
+func (b *EthAPIBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
 	statedb := state.(EthAPIState)
 	vmError := func() error { return nil }
+	if vmConfig == nil {
+		vmConfig = b.eth.blockchain.GetVMConfig()
+	}
 
 	evmCtx := core.NewEVMContext(msg, header, b.eth.BlockChain(), nil)
 
@@ -270,7 +274,7 @@ func (b *EthAPIBackend) GetEVM(ctx context.Context, msg core.Message, state vm.M
 		privateState = statedb.state
 	}
 
-	return vm.NewEVM(evmCtx, statedb.state, privateState, b.eth.blockchain.Config(), *b.eth.blockchain.GetVMConfig()), vmError, nil
+	return vm.NewEVM(evmCtx, statedb.state, privateState, b.eth.blockchain.Config(), *vmConfig), vmError, nil
 }
 
 func (b *EthAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
@@ -303,7 +307,7 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	if b.hexNodeId != "" && !pcore.ValidateNodeForTxn(b.hexNodeId, signedTx.From()) {
 		return errors.New("cannot send transaction from this node")
 	}
-	return b.eth.txPool.AddLocal(signedTx)
+	return b.eth.txPool.AddLocalContext(ctx, signedTx)
 }
 
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
@@ -428,6 +432,10 @@ func (b *EthAPIBackend) SupportsMultitenancy(rpcCtx context.Context) (*proto.Pre
 	return nil, false
 }
 
+func (b *EthAPIBackend) AsyncCallbackOutbox() *ethapi.AsyncCallbackOutbox {
+	return b.eth.asyncCallbacks
+}
+
 func (b *EthAPIBackend) AccountExtraDataStateGetterByNumber(ctx context.Context, number rpc.BlockNumber) (vm.AccountExtraDataStateGetter, error) {
 	s, _, err := b.StateAndHeaderByNumber(ctx, number)
 	return s, err
@@ -438,6 +446,21 @@ type EthAPIState struct {
 	state, privateState *state.StateDB
 }
 
+// PrivateState returns the private state, used to override private accounts in eth_call
+func (s EthAPIState) PrivateState() vm.MinimalApiState {
+	return s.privateState
+}
+
+// IsPrivate returns true if the account lives in the private state
+func (s EthAPIState) IsPrivate(addr common.Address) bool {
+	return s.privateState.Exist(addr)
+}
+
+// PrivateStateRoot returns the root of the private state, the private accounts are proved against it
+func (s EthAPIState) PrivateStateRoot() common.Hash {
+	return s.privateState.IntermediateRoot(false)
+}
+
 func (s EthAPIState) GetBalance(addr common.Address) *big.Int {
 	if s.privateState.Exist(addr) {
 		return s.privateState.GetBalance(addr)
@@ -0,0 +1,157 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/consensus"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/ethereum/go-ethereum/private"
+)
+
+// Quorum
+
+// QuorumNodeInfo is the aggregated status of a Quorum node, returned by quorum_nodeInfo
+type QuorumNodeInfo struct {
+	Consensus      ConsensusInfo                         `json:"consensus"`
+	PrivacyManager PrivacyManagerInfo                    `json:"privacyManager"`
+	Permissioning  PermissioningInfo                     `json:"permissioning"`
+	Plugins        map[plugin.PluginInterfaceName]string `json:"plugins"`
+	Multitenancy   bool                                  `json:"multitenancy"`
+}
+
+// ConsensusInfo describes the consensus protocol and the current role of the node in it
+type ConsensusInfo struct {
+	Engine string `json:"engine"`
+	Role   string `json:"role,omitempty"`
+}
+
+// PrivacyManagerInfo describes the private transaction manager the node is connected to
+type PrivacyManagerInfo struct {
+	Enabled    bool   `json:"enabled"`
+	Name       string `json:"name,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Status     string `json:"status,omitempty"` // "up" or the reason it is unavailable
+}
+
+// PermissioningInfo describes the permissioning of the node
+type PermissioningInfo struct {
+	// Mode is "none", "basic" (permissioned-nodes.json), "v1" or "v2" (smart-contract-based)
+	Mode string `json:"mode"`
+	// BootCompleted is true once the network boot has been completed (smart-contract-based permissioning only)
+	BootCompleted bool `json:"bootCompleted"`
+	// Active is true once the smart-contract-based permissions are enforced
+	Active bool `json:"active"`
+}
+
+// PublicQuorumNodeInfoAPI provides quorum_nodeInfo for monitoring agents and support bundles
+type PublicQuorumNodeInfoAPI struct {
+	e *Ethereum
+}
+
+// NewPublicQuorumNodeInfoAPI creates a new PublicQuorumNodeInfoAPI
+func NewPublicQuorumNodeInfoAPI(e *Ethereum) *PublicQuorumNodeInfoAPI {
+	return &PublicQuorumNodeInfoAPI{e: e}
+}
+
+// NodeInfo returns the consensus engine and role, private transaction manager status,
+// permissioning mode, plugins health and multitenancy flag of the node
+func (api *PublicQuorumNodeInfoAPI) NodeInfo() *QuorumNodeInfo {
+	info := &QuorumNodeInfo{
+		Consensus:      api.consensusInfo(),
+		PrivacyManager: privacyManagerInfo(),
+		Permissioning:  api.permissioningInfo(),
+		Plugins:        make(map[plugin.PluginInterfaceName]string),
+		Multitenancy:   api.e.config.EnableMultitenancy,
+	}
+	if api.e.node != nil {
+		if pm := api.e.node.PluginManager(); pm != nil {
+			info.Plugins = pm.PluginsHealth()
+		}
+	}
+	return info
+}
+
+func (api *PublicQuorumNodeInfoAPI) consensusInfo() ConsensusInfo {
+	if notifier := api.e.NodeRoleNotifier(); notifier != nil {
+		role := notifier.NodeRole()
+		return ConsensusInfo{Engine: role.Consensus, Role: role.Role}
+	}
+	config := api.e.blockchain.Config()
+	switch {
+	case config.Istanbul != nil:
+		return ConsensusInfo{Engine: "istanbul"}
+	case config.Clique != nil:
+		return ConsensusInfo{Engine: "clique"}
+	default:
+		return ConsensusInfo{Engine: "ethash"}
+	}
+}
+
+func privacyManagerInfo() PrivacyManagerInfo {
+	if !private.IsQuorumPrivacyEnabled() || private.P == nil {
+		return PrivacyManagerInfo{}
+	}
+	info := PrivacyManagerInfo{Enabled: true, Name: private.P.Name()}
+	if reporter, ok := private.P.(private.StatusReporter); ok {
+		info.APIVersion = reporter.APIVersion()
+		if err := reporter.Upcheck(); err != nil {
+			info.Status = err.Error()
+		} else {
+			info.Status = "up"
+		}
+	}
+	return info
+}
+
+func (api *PublicQuorumNodeInfoAPI) permissioningInfo() PermissioningInfo {
+	info := PermissioningInfo{Mode: "none"}
+	switch pcore.PermissionModel {
+	case pcore.V1:
+		info.Mode = "v1"
+	case pcore.V2:
+		info.Mode = "v2"
+	default:
+		if api.e.node != nil && api.e.node.Config().EnableNodePermission {
+			info.Mode = "basic"
+		}
+		return info
+	}
+	info.BootCompleted = pcore.IsNetworkBootUpCompleted()
+	info.Active = pcore.PermissionsEnabled()
+	return info
+}
+
+// SetNodeRoleNotifier sets the source of the role of this node, for consensus protocols
+// not running as the consensus engine (i.e.: raft)
+func (s *Ethereum) SetNodeRoleNotifier(notifier consensus.NodeRoleNotifier) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.nodeRoleNotifier = notifier
+}
+
+// NodeRoleNotifier returns the source of the role of this node, nil if the consensus protocol
+// doesn't report roles
+func (s *Ethereum) NodeRoleNotifier() consensus.NodeRoleNotifier {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.nodeRoleNotifier != nil {
+		return s.nodeRoleNotifier
+	}
+	notifier, _ := s.engine.(consensus.NodeRoleNotifier)
+	return notifier
+}
@@ -0,0 +1,37 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubNodeRoleNotifier struct {
+	role consensus.NodeRoleEvent
+}
+
+func (s *stubNodeRoleNotifier) NodeRole() consensus.NodeRoleEvent {
+	return s.role
+}
+
+func (s *stubNodeRoleNotifier) SubscribeNodeRoleEvent(chan<- consensus.NodeRoleEvent) event.Subscription {
+	return nil
+}
+
+func TestQuorumNodeInfo(t *testing.T) {
+	e := &Ethereum{config: &Config{EnableMultitenancy: true}, engine: ethash.NewFaker()}
+	e.SetNodeRoleNotifier(&stubNodeRoleNotifier{role: consensus.NodeRoleEvent{Consensus: "raft", Role: "minter"}})
+
+	info := NewPublicQuorumNodeInfoAPI(e).NodeInfo()
+
+	assert.Equal(t, &QuorumNodeInfo{
+		Consensus:     ConsensusInfo{Engine: "raft", Role: "minter"},
+		Permissioning: PermissioningInfo{Mode: "none"},
+		Plugins:       map[plugin.PluginInterfaceName]string{},
+		Multitenancy:  true,
+	}, info)
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -70,6 +71,10 @@ type StdTraceConfig struct {
 	*vm.LogConfig
 	Reexec *uint64
 	TxHash common.Hash
+	// Quorum
+	// PSI is the private state to trace the private transactions against, defaults to
+	// the private state of the caller
+	PSI types.PrivateStateIdentifier `json:"psi"`
 }
 
 // txTraceResult is the result of a single transaction trace.
@@ -595,7 +600,11 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	if err != nil {
 		return nil, err
 	}
-	psm, err := api.eth.blockchain.PrivateStateManager().ResolveForUserContext(ctx)
+	var psi types.PrivateStateIdentifier
+	if config != nil {
+		psi = config.PSI
+	}
+	psm, err := api.resolvePrivateStateMetadata(ctx, psi)
 	if err != nil {
 		return nil, err
 	}
@@ -641,6 +650,10 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 		}
 	}
 	for i, tx := range block.Transactions() {
+		// Quorum: stop when the call is cancelled, e.g.: the method timed out
+		if err := ctx.Err(); err != nil {
+			return dumps, err
+		}
 		// Prepare the trasaction for un-traced execution
 		var (
 			msg, _ = tx.AsMessage(signer)
@@ -658,6 +671,10 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 			if !canon {
 				prefix = fmt.Sprintf("%valt-", prefix)
 			}
+			// Quorum: private transactions are traced against the selected private state
+			if tx.IsPrivate() {
+				prefix = fmt.Sprintf("%v%s-", prefix, psm.ID)
+			}
 			dump, err = ioutil.TempFile(os.TempDir(), prefix)
 			if err != nil {
 				return nil, err
@@ -703,6 +720,25 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	return dumps, nil
 }
 
+// Quorum
+// resolvePrivateStateMetadata returns the given private state, making sure the caller is
+// authorized to access it, or the private state of the caller if no PSI is given
+func (api *PrivateDebugAPI) resolvePrivateStateMetadata(ctx context.Context, psi types.PrivateStateIdentifier) (*mps.PrivateStateMetadata, error) {
+	if psi != "" {
+		if token := rpc.PreauthenticatedTokenFromContext(ctx); token != nil {
+			authorized, err := multitenancy.IsPSIAuthorized(token, psi)
+			if err != nil {
+				return nil, err
+			}
+			if !authorized {
+				return nil, multitenancy.ErrNotAuthorized
+			}
+		}
+		ctx = rpc.WithPrivateStateIdentifier(ctx, psi)
+	}
+	return api.eth.blockchain.PrivateStateManager().ResolveForUserContext(ctx)
+}
+
 // containsTx reports whether the transaction with a certain hash
 // is contained within the specified block.
 func containsTx(block *types.Block, hash common.Hash) bool {
@@ -923,12 +959,24 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, t
 	// Run the transaction with tracing enabled.
 	vmenv := vm.NewEVM(vmctx, statedb, privateStateDbToUse, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
 	vmenv.SetCurrentTX(tx)
+
+	// Interrupt the execution when the call is cancelled, e.g.: the method timed out
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-cancelCtx.Done()
+		vmenv.Cancel()
+	}()
 	// /Quorum
 
 	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %v", err)
 	}
+	// Quorum
+	if vmenv.Cancelled() && ctx.Err() != nil {
+		return nil, fmt.Errorf("tracing aborted: %v", ctx.Err())
+	}
 	// Depending on the tracer type, format and return the output
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
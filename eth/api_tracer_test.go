@@ -0,0 +1,99 @@
+package eth
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTracerTestAPI returns a debug API on an MPS chain with the resident private states
+// RG1 and RG2, and a block with a private contract creation of a party of RG1. The
+// returned func restores the private transaction manager and stops the chain.
+func newTracerTestAPI(t *testing.T, ctrl *gomock.Controller) (*PrivateDebugAPI, *types.Block, func()) {
+	mockptm := private.NewMockPrivateTransactionManager(ctrl)
+	saved := private.P
+	private.P = mockptm
+	mockptm.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(true)
+	mockptm.EXPECT().Groups().Return([]engine.PrivacyGroup{
+		{Type: engine.PrivacyGroupResident, Name: "RG1", PrivacyGroupId: base64.StdEncoding.EncodeToString([]byte("RG1")), Members: []string{"AAA"}},
+		{Type: engine.PrivacyGroupResident, Name: "RG2", PrivacyGroupId: base64.StdEncoding.EncodeToString([]byte("RG2")), Members: []string{"BBB"}},
+	}, nil)
+	// the private contract stores 1 at slot 0
+	mockptm.EXPECT().Receive(gomock.Any()).Return("", []string{"AAA"}, common.FromHex("0x600160005500"), nil, nil).AnyTimes()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	db := rawdb.NewMemoryDatabase()
+	genesis := core.GenesisBlockForTesting(db, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	blockchain, err := core.NewBlockChain(db, nil, params.QuorumMPSTestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err)
+
+	tx := types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(0), common.EncryptedPayloadHash{1}.Bytes())
+	tx.SetPrivate()
+	tx, err = types.SignTx(tx, types.QuorumPrivateTxSigner{}, key)
+	require.NoError(t, err)
+	blocks, _ := core.GenerateChain(params.QuorumMPSTestChainConfig, genesis, ethash.NewFaker(), db, 1, func(i int, block *core.BlockGen) {
+		block.AddUncheckedTx(tx)
+	})
+	return NewPrivateDebugAPI(&Ethereum{blockchain: blockchain, engine: ethash.NewFaker(), chainDb: db}), blocks[0], func() {
+		blockchain.Stop()
+		private.P = saved
+	}
+}
+
+func withPSIToken(psi string) context.Context {
+	token := &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{{Raw: "psi://" + psi + "?self.eoa=0x0&node.eoa=0x0"}}}
+	return rpc.WithPreauthenticatedToken(context.Background(), token)
+}
+
+func TestPrivateDebugAPI_standardTraceBlockToFile_onNamedPSI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	api, block, cleanup := newTracerTestAPI(t, ctrl)
+	defer cleanup()
+
+	files, err := api.standardTraceBlockToFile(withPSIToken("RG1"), block, &StdTraceConfig{PSI: "RG1"})
+
+	require.NoError(t, err)
+	for _, file := range files {
+		defer os.Remove(file)
+	}
+	require.Len(t, files, 1)
+	assert.Contains(t, filepath.Base(files[0]), "-RG1-", "the private transaction was not traced on RG1")
+	trace, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(trace), "SSTORE", "the private transaction was not executed on RG1")
+}
+
+func TestPrivateDebugAPI_standardTraceBlockToFile_whenPSINotAuthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	api, block, cleanup := newTracerTestAPI(t, ctrl)
+	defer cleanup()
+
+	files, err := api.standardTraceBlockToFile(withPSIToken("RG2"), block, &StdTraceConfig{PSI: "RG1"})
+
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+	assert.Empty(t, files)
+}
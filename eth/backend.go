@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/chaos"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
@@ -52,8 +53,13 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/plugin/security"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/qlight"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/statesync"
 )
 
 // Ethereum implements the Ethereum full node service.
@@ -92,6 +98,22 @@ type Ethereum struct {
 
 	// Quorum - consensus as eth-service (e.g. raft)
 	consensusServicePendingLogsFeed *event.Feed
+	nodeRoleNotifier                consensus.NodeRoleNotifier // the source of the node role if not the consensus engine
+	node                            *node.Node
+	backupSources                   []BackupSource    // the sources taking part in the backups besides the chain
+	qlightClient                    *qlight.Client    // fetches the private payloads from the server, nil unless qlight client
+	qlightServer                    *qlight.Server    // serves the private payloads to the qlight clients, nil unless qlight server
+	stateSyncClient                 *statesync.Client // syncs the private states of the fast sync pivot, nil unless configured
+	stateSyncServer                 *statesync.Server // serves the states to the state sync clients, nil unless state sync server
+	mintingController               MintingController // pauses the block production if not the consensus engine
+	mintingMu                       sync.Mutex        // protects mintingPause
+	mintingPause                    *mintingPause     // nil unless the block production is paused
+	mintingPaused                   int32             // 1 while the block production is paused (atomic)
+	resendMu                        sync.Mutex        // protects payloadResend
+	payloadResend                   *payloadResend    // the running or last resend of private payloads
+
+	asyncCallbacks *ethapi.AsyncCallbackOutbox // delivers the eth_sendTransactionAsync callbacks
+	filterAPI      *filters.PublicFilterAPI    // restores the persistent filters on start
 }
 
 // New creates a new Ethereum object (including the
@@ -104,6 +126,10 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	// Quorum: the blocks written by fast sync are not executed, nor indexed
+	if config.IndexPrivatePayloads && config.SyncMode != downloader.FullSync {
+		return nil, errors.New("the private payload index requires full sync mode")
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Cmp(common.Big0) <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", DefaultConfig.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(DefaultConfig.Miner.GasPrice)
@@ -119,11 +145,29 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 	}
 	log.Info("Allocated trie memory caches", "clean", common.StorageSize(config.TrieCleanCache)*1024*1024, "dirty", common.StorageSize(config.TrieDirtyCache)*1024*1024)
 
+	// Quorum: the payloads of a qlight client are fetched from its server, which must be
+	// in place before any block is processed
+	var qlightClient *qlight.Client
+	if config.QuorumLightClient != nil {
+		server, err := enode.Parse(enode.ValidSchemes, config.QuorumLightClient.ServerNode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid qlight server node: %v", err)
+		}
+		qlightClient = qlight.NewClient(config.QuorumLightClient.PSI, config.QuorumLightClient.Token, server)
+		private.InitialiseWith(qlightClient)
+	}
+
 	// Assemble the Ethereum object
 	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "eth/db/chaindata/")
 	if err != nil {
 		return nil, err
 	}
+	// Quorum: the cold store is opened even if no private contract is moved to it
+	// anymore, to restore the ones moved before
+	coldDb, err := stack.OpenDatabase("coldstate", 16, 16, "eth/db/coldstate/")
+	if err != nil {
+		return nil, err
+	}
 	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
@@ -157,6 +201,8 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 		bloomIndexer:                    NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 		p2pServer:                       stack.Server(),
 		consensusServicePendingLogsFeed: new(event.Feed),
+		node:                            stack,
+		qlightClient:                    qlightClient,
 	}
 
 	// Quorum: Set protocol Name/Version
@@ -173,6 +219,12 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 		quorumConsensusProtocolName = quorumProtocol.Name
 		quorumConsensusProtocolVersions = quorumProtocol.Versions
 		quorumConsensusProtocolLengths = quorumProtocol.Lengths
+		// report the consensus data about peers in admin_peers
+		if provider, ok := eth.engine.(consensus.PeerInfoProvider); ok {
+			eth.p2pServer.SetPeerInfoProvider(quorumConsensusProtocolName, func(node *enode.Node) interface{} {
+				return provider.PeerInfo(eth.blockchain, node)
+			})
+		}
 	}
 
 	// force to set the istanbul etherbase to node key address
@@ -214,6 +266,8 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 			SnapshotLimit:       config.SnapshotCache,
 			// Quorum
 			PrivateTrieCleanJournal: stack.ResolvePath(config.PrivateTrieCleanCacheJournal),
+			PrivateColdStore:        coldDb,
+			PrivateColdAfter:        config.PrivateStateColdAfter,
 		}
 	)
 	newBlockChainFunc := core.NewBlockChain
@@ -224,6 +278,7 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 
 	// Quorum
 	eth.blockchain.SetSaveRevertReason(config.SaveRevertReason)
+	eth.blockchain.SetIndexPrivatePayloads(config.IndexPrivatePayloads)
 
 	if err != nil {
 		return nil, err
@@ -250,16 +305,48 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 	if eth.protocolManager, err = NewProtocolManager(chainConfig, checkpoint, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb, cacheLimit, config.Whitelist, config.RaftMode); err != nil {
 		return nil, err
 	}
+	// Quorum
+	if config.QuorumLightServer != nil {
+		// the authentication managers are resolved once the services have registered theirs,
+		// e.g. the API keys of the permission service
+		var authManager security.AuthenticationManagerDeferFunc
+		if config.EnableMultitenancy {
+			authManager = func() (security.AuthenticationManager, error) {
+				_, am, err := stack.GetSecuritySupports()
+				return am, err
+			}
+		}
+		if eth.qlightServer, err = qlight.NewServer(private.P, eth.blockchain.PrivateStateManager(), authManager, config.QuorumLightServer); err != nil {
+			return nil, err
+		}
+	}
+	if config.QuorumStateSyncServer != nil {
+		psm := eth.blockchain.PrivateStateManager()
+		if eth.stateSyncServer, err = statesync.NewServer(chainDb, eth.blockchain.StateCache().TrieDB(), psm.TrieDB(), config.QuorumStateSyncServer); err != nil {
+			return nil, err
+		}
+	}
+	if config.QuorumStateSyncServerNode != "" {
+		server, err := enode.Parse(enode.ValidSchemes, config.QuorumStateSyncServerNode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state sync server node: %v", err)
+		}
+		eth.stateSyncClient = statesync.NewClient(server, chainDb, eth.blockchain.PrivateStateManager(), chainConfig.IsMPS)
+		eth.protocolManager.downloader.SetPrivateStateSyncer(eth.stateSyncClient)
+	}
+
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData, eth.blockchain.Config().IsQuorum))
 
 	hexNodeId := fmt.Sprintf("%x", crypto.FromECDSAPub(&stack.GetNodeKey().PublicKey)[1:]) // Quorum
+	eth.asyncCallbacks = ethapi.NewAsyncCallbackOutbox(chainDb)                            // Quorum
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), eth, nil, hexNodeId, config.EVMCallTimeOut}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
 		gpoParams.Default = config.Miner.GasPrice
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, gpoParams)
+	eth.filterAPI = filters.NewPublicFilterAPI(eth.APIBackend, false) // Quorum
 
 	eth.dialCandidates, err = eth.setupDiscovery(&stack.Config().P2P)
 	if err != nil {
@@ -273,6 +360,23 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 	stack.RegisterAPIs(eth.APIs())
 	stack.RegisterProtocols(eth.Protocols())
 	stack.RegisterLifecycle(eth)
+	// Quorum
+	core.SetTxLatencyConsensus(eth.protocolManager.getConsensusAlgorithm())
+	stack.RegisterAPIs(chaos.APIs())
+	if _, ok := eth.engine.(consensus.Handler); ok && !config.RaftMode {
+		// the block maker crashed and restarted by the chaos API is the istanbul miner
+		chaos.RegisterBlockMaker(chaos.BlockMakerFuncs{
+			Stop:  func() error { eth.StopMining(); return nil },
+			Start: func() error { return eth.StartMining(1) },
+		})
+	}
+	// the consensus roles are handed off when the node enters maintenance
+	if handler, ok := eth.engine.(node.MaintenanceHandler); ok {
+		stack.RegisterMaintenanceHandler(handler)
+	}
+	health := newHealthHandler(eth, stack)
+	stack.RegisterHandler("Health probes", healthLivePath, health)
+	stack.RegisterHandler("Health probes", healthReadyPath, health)
 	return eth, nil
 }
 
@@ -307,11 +411,22 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 		}
 		config.Istanbul.ProposerPolicy = istanbul.ProposerPolicy(chainConfig.Istanbul.ProposerPolicy)
 		config.Istanbul.Ceil2Nby3Block = chainConfig.Istanbul.Ceil2Nby3Block
+		config.Istanbul.KeyRotationBlock = chainConfig.Istanbul.KeyRotationBlock
 		config.Istanbul.AllowedFutureBlockTime = config.Miner.AllowedFutureBlockTime //Quorum
 
+		if config.IstanbulSigner != nil {
+			return istanbulBackend.NewWithSigner(&config.Istanbul, config.IstanbulSigner, db)
+		}
 		return istanbulBackend.New(&config.Istanbul, stack.GetNodeKey(), db)
 	}
 
+	// Quorum: raft runs as a separate service, the Ethereum service still needs a
+	// consensus engine but no proof-of-work, unless an ethash mode is explicitly configured
+	if chainConfig.IsNoPoW() && config.Ethash.PowMode == ethash.ModeNormal {
+		log.Info("Proof-of-work disabled by the chain config")
+		return ethash.NewNoPoW()
+	}
+
 	// Otherwise assume proof-of-work
 	switch config.Ethash.PowMode {
 	case ethash.ModeFake:
@@ -365,7 +480,7 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   filters.NewPublicFilterAPI(s.APIBackend, false),
+			Service:   s.filterAPI,
 			Public:    true,
 		}, {
 			Namespace: "admin",
@@ -385,6 +500,20 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "quorum",
+			Version:   "1.0",
+			Service:   NewPublicQuorumNodeInfoAPI(s),
+			Public:    true,
+		}, {
+			Namespace: "quorum",
+			Version:   "1.0",
+			Service:   NewPrivateQuorumResendAPI(s),
+		}, {
+			Namespace: "quorum",
+			Version:   "1.0",
+			Service:   NewPublicQuorumPayloadIndexAPI(s),
+			Public:    true,
 		},
 	}...)
 	return apis
@@ -488,6 +617,13 @@ func (s *Ethereum) SetEtherbase(etherbase common.Address) {
 // is already running, this method adjust the number of threads allowed to use
 // and updates the minimum price required by the transaction pool.
 func (s *Ethereum) StartMining(threads int) error {
+	// Quorum
+	if s.qlightClient != nil {
+		return errors.New("a qlight client can't mine")
+	}
+	if s.isMintingPaused() {
+		return errMintingPaused
+	}
 	// Update the thread count within the consensus engine
 	type threaded interface {
 		SetThreads(threads int)
@@ -560,6 +696,7 @@ func (s *Ethereum) Downloader() *downloader.Downloader { return s.protocolManage
 func (s *Ethereum) Synced() bool                       { return atomic.LoadUint32(&s.protocolManager.acceptTxs) == 1 }
 func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
 func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
+func (s *Ethereum) RaftMode() bool                     { return s.config.RaftMode } // Quorum
 
 // Quorum
 // adds quorum specific protocols to the Protocols() function which in the associated upstream geth version returns
@@ -588,6 +725,16 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 		quorumProtos := s.quorumConsensusProtocols()
 		protos = append(protos, quorumProtos...)
 	}
+	if s.qlightClient != nil {
+		protos = append(protos, qlight.MakeProtocol(s.qlightClient))
+	} else if s.qlightServer != nil {
+		protos = append(protos, qlight.MakeProtocol(s.qlightServer))
+	}
+	if s.stateSyncClient != nil {
+		protos = append(protos, statesync.MakeProtocol(s.stateSyncClient))
+	} else if s.stateSyncServer != nil {
+		protos = append(protos, statesync.MakeProtocol(s.stateSyncServer))
+	}
 	// /end Quorum
 
 	return protos
@@ -611,12 +758,27 @@ func (s *Ethereum) Start() error {
 	}
 	// Start the networking layer and the light server if requested
 	s.protocolManager.Start(maxPeers)
+
+	// Quorum: resume the delivery of the async transaction callbacks and the persistent
+	// filters left over from before a restart
+	s.asyncCallbacks.Start()
+	s.filterAPI.RestoreFilters()
 	return nil
 }
 
 // Stop implements node.Lifecycle, terminating all internal goroutines used by the
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
+	// Quorum: abort the blocks waiting for private payloads
+	if s.qlightClient != nil {
+		s.qlightClient.Close()
+	}
+	if s.stateSyncClient != nil {
+		s.stateSyncClient.Close()
+	}
+	s.stopMintingPause()
+	s.stopPayloadResend()
+	s.asyncCallbacks.Stop()
 	// Stop all the peer-related stuff first.
 	s.protocolManager.Stop()
 
@@ -636,6 +798,22 @@ func (s *Ethereum) CalcGasLimit(block *types.Block) uint64 {
 	return core.CalcGasLimit(block, s.config.Miner.GasFloor, s.config.Miner.GasCeil)
 }
 
+// Quorum
+// SetServeThrottle limits the chain served to the peers syncing from the node to maxPeers
+// requests at once and bandwidth bytes per second, 0 for no limit. It must be called
+// before the service is started.
+func (s *Ethereum) SetServeThrottle(maxPeers, bandwidth int) {
+	if maxPeers > 0 || bandwidth > 0 {
+		s.protocolManager.serveThrottle = newServeThrottle(maxPeers, bandwidth)
+	}
+}
+
+// Quorum
+// OrgQuotas returns the shares of the blocks each organization may use, nil if unlimited
+func (s *Ethereum) OrgQuotas() *pcore.OrgQuotaConfig {
+	return s.config.Miner.OrgQuotas
+}
+
 // (Quorum)
 // ConsensusServicePendingLogsFeed returns an event.Feed.  When the consensus protocol does not use eth.worker (e.g. raft), the event.Feed should be used to send logs from transactions included in the pending block
 func (s *Ethereum) ConsensusServicePendingLogsFeed() *event.Feed {
@@ -0,0 +1,277 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Quorum
+
+const (
+	// BackupManifestFile describes the backup and the checksums of its files
+	BackupManifestFile = "manifest.json"
+	// BackupChainDataFile contains the export of the chain database
+	BackupChainDataFile = "chaindata.rlp"
+	// BackupTxJournalFile is the copy of the transaction pool journal, holding the pending
+	// local (public and private) transactions
+	BackupTxJournalFile = "transactions.rlp"
+	// BackupPermissionDir contains the copy of the permissioning files of the data directory
+	BackupPermissionDir = "permission"
+
+	backupManifestVersion = 1
+)
+
+// BackupSource takes part in the backup of the node, e.g.: the raft state.
+type BackupSource interface {
+	// PauseWrites blocks the writes of the source until the returned function is called
+	PauseWrites() (resume func())
+	// Backup copies the state of the source into dir, it is called while writes are paused
+	Backup(dir string) error
+}
+
+// BackupManifest describes a backup
+type BackupManifest struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Block     uint64            `json:"block"`
+	BlockHash common.Hash       `json:"blockHash"`
+	Files     map[string]string `json:"files"` // hex sha256 of the files by slash-separated path in the backup
+}
+
+// AddBackupSource adds a source to the backups of the node
+func (s *Ethereum) AddBackupSource(source BackupSource) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.backupSources = append(s.backupSources, source)
+}
+
+// Backup writes a consistent backup of the node into dir, which must not exist: the chain
+// database, the transaction pool journal, the permissioning files and the state of the
+// backup sources (e.g.: raft).
+//
+// The writes are paused while the sources are copied and a snapshot of the chain database
+// is taken, the database export then runs from the snapshot with the writes resumed.
+func (s *Ethereum) Backup(dir string) (*BackupManifest, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return nil, errors.New("location would overwrite an existing directory")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	s.lock.RLock()
+	sources := append([]BackupSource(nil), s.backupSources...)
+	s.lock.RUnlock()
+
+	// the sources are paused before the chain as they may be inserting blocks
+	start := time.Now()
+	var resumes []func()
+	for _, source := range sources {
+		resumes = append(resumes, source.PauseWrites())
+	}
+	resumes = append(resumes, s.blockchain.PauseWrites())
+	resume := func() {
+		for i := len(resumes) - 1; i >= 0; i-- {
+			resumes[i]()
+		}
+		resumes = nil
+	}
+	defer func() {
+		if resumes != nil {
+			resume()
+		}
+	}()
+
+	head := s.blockchain.CurrentBlock()
+	manifest := &BackupManifest{
+		Version:   backupManifestVersion,
+		CreatedAt: time.Now().UTC(),
+		Block:     head.NumberU64(),
+		BlockHash: head.Hash(),
+	}
+	for _, source := range sources {
+		if err := source.Backup(dir); err != nil {
+			return nil, err
+		}
+	}
+	if journal := s.config.TxPool.Journal; journal != "" {
+		if err := copyFileIfExists(journal, filepath.Join(dir, BackupTxJournalFile)); err != nil {
+			return nil, err
+		}
+	}
+	if s.node != nil {
+		for _, file := range []string{params.PERMISSIONED_CONFIG, params.BLACKLIST_CONFIG, params.PERMISSION_MODEL_CONFIG} {
+			if err := copyFileIfExists(filepath.Join(s.node.DataDir(), file), filepath.Join(dir, BackupPermissionDir, file)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// the iterator is a snapshot of the database, it must be created before the ancients are counted
+	it := s.chainDb.NewIterator(nil, nil)
+	defer it.Release()
+	ancients, _ := s.chainDb.Ancients()
+	resume()
+	log.Info("Resumed writes after backup snapshot", "dir", dir, "block", manifest.Block, "paused", common.PrettyDuration(time.Since(start)))
+
+	if err := exportChainData(filepath.Join(dir, BackupChainDataFile), it, s.chainDb, ancients); err != nil {
+		return nil, err
+	}
+	if err := WriteBackupManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+	log.Info("Backup completed", "dir", dir, "block", manifest.Block, "files", len(manifest.Files), "elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, nil
+}
+
+func exportChainData(path string, it ethdb.Iterator, db ethdb.AncientReader, ancients uint64) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if err := rawdb.ExportDatabase(w, it, db, ancients); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// WriteBackupManifest computes the checksums of the files of the backup in dir and writes
+// manifest into it
+func WriteBackupManifest(dir string, manifest *BackupManifest) error {
+	files, err := backupChecksums(dir)
+	if err != nil {
+		return err
+	}
+	manifest.Files = files
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, BackupManifestFile), content, 0600)
+}
+
+// VerifyBackup checks the files of the backup in dir against the checksums of its manifest
+func VerifyBackup(dir string) (*BackupManifest, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, BackupManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest BackupManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if manifest.Version != backupManifestVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+	files, err := backupChecksums(dir)
+	if err != nil {
+		return nil, err
+	}
+	for name, checksum := range manifest.Files {
+		actual, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("missing file %s", name)
+		}
+		if actual != checksum {
+			return nil, fmt.Errorf("corrupted file %s", name)
+		}
+		delete(files, name)
+	}
+	if len(files) > 0 {
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unexpected files %v", names)
+	}
+	return &manifest, nil
+}
+
+// backupChecksums returns the hex sha256 of the files in dir but the manifest
+func backupChecksums(dir string) (map[string]string, error) {
+	checksums := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if name == BackupManifestFile {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, f); err != nil {
+			return err
+		}
+		checksums[filepath.ToSlash(name)] = hex.EncodeToString(hash.Sum(nil))
+		return nil
+	})
+	return checksums, err
+}
+
+// copyFileIfExists copies the file at src to dst, creating the parent directories of dst
+func copyFileIfExists(src, dst string) error {
+	in, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,63 @@
+package eth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackupDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, BackupChainDataFile), []byte("chain"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "raft", "raft-wal"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "raft", "raft-wal", "0.wal"), []byte("wal"), 0600))
+	require.NoError(t, WriteBackupManifest(dir, &BackupManifest{Version: backupManifestVersion, Block: 10, BlockHash: common.HexToHash("0x1")}))
+	return dir
+}
+
+func TestVerifyBackup(t *testing.T) {
+	dir := newTestBackupDir(t)
+	defer os.RemoveAll(dir)
+
+	manifest, err := VerifyBackup(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), manifest.Block)
+	assert.Len(t, manifest.Files, 2)
+	assert.Contains(t, manifest.Files, "raft/raft-wal/0.wal")
+}
+
+func TestVerifyBackup_whenCorrupted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		modify   func(dir string) error
+		expected string
+	}{
+		{"modified file", func(dir string) error {
+			return ioutil.WriteFile(filepath.Join(dir, BackupChainDataFile), []byte("chain!"), 0600)
+		}, "corrupted file chaindata.rlp"},
+		{"missing file", func(dir string) error {
+			return os.Remove(filepath.Join(dir, "raft", "raft-wal", "0.wal"))
+		}, "missing file raft/raft-wal/0.wal"},
+		{"unexpected file", func(dir string) error {
+			return ioutil.WriteFile(filepath.Join(dir, "extra"), nil, 0600)
+		}, "unexpected files [extra]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := newTestBackupDir(t)
+			defer os.RemoveAll(dir)
+			require.NoError(t, tc.modify(dir))
+
+			_, err := VerifyBackup(dir)
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/qlight"
+	"github.com/ethereum/go-ethereum/statesync"
 )
 
 // DefaultFullGPOConfig contains default gasprice oracle settings for full node.
@@ -84,6 +86,9 @@ var DefaultConfig = Config{
 	// Quorum
 	Istanbul:                     *istanbul.DefaultConfig, // Quorum
 	PrivateTrieCleanCacheJournal: "privatetriecache",
+	Health:                       DefaultHealthConfig,
+	EVMCallTimeOut:               5 * time.Second,
+	QuorumImmutabilityThreshold:  3162240,
 }
 
 func init() {
@@ -174,6 +179,10 @@ type Config struct {
 	EnableNodePermission bool
 	// Istanbul options
 	Istanbul istanbul.Config
+	// Quorum: signs the Istanbul messages and seals instead of the node key if set
+	IstanbulSigner istanbul.Signer `toml:"-"`
+	// Quorum: URL of the cloud KMS key the IstanbulSigner is opened from
+	IstanbulKMSKey string `toml:",omitempty"`
 
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
@@ -205,8 +214,51 @@ type Config struct {
 	EnableMultitenancy bool `toml:"-"`
 
 	// Quorum
-	SaveRevertReason bool `toml:"-"`
+	SaveRevertReason bool `toml:",omitempty"`
+
+	// Quorum
+	// QuorumImmutabilityThreshold is the number of blocks after which the chain data is moved
+	// to the ancient database
+	QuorumImmutabilityThreshold int `toml:",omitempty"`
 
 	// Quorum
 	PrivateTrieCleanCacheJournal string `toml:",omitempty"` // Disk journal directory for private trie cache to survive node restarts
+
+	// Quorum
+	// PrivateStateColdAfter is the inactivity after which the storage of a private contract
+	// is moved to the cold store, 0 to keep all the storage in the private states
+	PrivateStateColdAfter time.Duration `toml:",omitempty"`
+
+	// Quorum
+	// IndexPrivatePayloads indexes the hashes of all the private payloads with the blocks
+	// including them, whether the node is party to the payloads or not, for the archive
+	// and audit nodes proving the inclusion of private transactions
+	IndexPrivatePayloads bool `toml:",omitempty"`
+
+	// Quorum
+	// Health contains the thresholds of the /health/ready probe
+	Health HealthConfig `toml:",omitempty"`
+
+	// Quorum
+	// QuorumLightServer serves the private payloads to the qlight clients it entitles, nil otherwise
+	QuorumLightServer *qlight.ServerConfig `toml:",omitempty"`
+	// QuorumLightClient runs the node as a qlight client, nil otherwise
+	QuorumLightClient *QuorumLightClientConfig `toml:",omitempty"`
+
+	// Quorum
+	// QuorumStateSyncServer serves the states of the blocks to the state sync clients it
+	// entitles, nil otherwise
+	QuorumStateSyncServer *statesync.ServerConfig `toml:",omitempty"`
+	// QuorumStateSyncServerNode is the enode URL of the server the private states of the
+	// fast sync pivot block are synced from, empty otherwise
+	QuorumStateSyncServerNode string `toml:",omitempty"`
+}
+
+// Quorum
+// QuorumLightClientConfig configures a qlight client: the node syncs from its server and
+// fetches from it the private payloads of its private state, instead of running a PTM
+type QuorumLightClientConfig struct {
+	PSI        string // Private state of the client
+	Token      string `toml:",omitempty"` // Token authorizing the client on a multitenant server
+	ServerNode string // Enode URL of the server
 }
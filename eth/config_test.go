@@ -3,6 +3,9 @@ package eth
 import (
 	"testing"
 
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,3 +22,15 @@ func TestQuorumDefautConfig(t *testing.T) {
 		assert.Equal(t, v.expected, v.actual, k+" value mismatch")
 	}
 }
+
+func TestCreateConsensusEngine_whenNoPoW(t *testing.T) {
+	chainConfig := &params.ChainConfig{IsQuorum: true}
+
+	engine := CreateConsensusEngine(nil, chainConfig, &Config{}, nil, false, rawdb.NewMemoryDatabase())
+	defer engine.Close()
+	assert.Empty(t, engine.APIs(nil), "proof-of-work is not disabled")
+
+	explicit := CreateConsensusEngine(nil, chainConfig, &Config{Ethash: ethash.Config{PowMode: ethash.ModeFake}}, nil, false, rawdb.NewMemoryDatabase())
+	defer explicit.Close()
+	assert.NotEmpty(t, explicit.APIs(nil), "the explicit ethash mode is overridden")
+}
@@ -158,6 +158,9 @@ type Downloader struct {
 	quitCh   chan struct{} // Quit channel to signal termination
 	quitLock sync.Mutex    // Lock to prevent double closes
 
+	// Quorum
+	privateStateSyncer PrivateStateSyncer // Syncs the private states of the fast sync pivot, nil if none
+
 	// Testing hooks
 	syncInitHook     func(uint64, uint64)  // Method to call upon initiating a new sync run
 	bodyFetchHook    func([]*types.Header) // Method to call upon starting a block body fetch
@@ -186,6 +189,15 @@ type LightChain interface {
 	SetHead(uint64) error
 }
 
+// Quorum
+// PrivateStateSyncer syncs the private states of a block, which are not part of the
+// blocks downloaded by a fast sync.
+type PrivateStateSyncer interface {
+	// SyncPrivateStates downloads the private states of the block of the header, until
+	// the cancel channel is closed.
+	SyncPrivateStates(header *types.Header, cancel <-chan struct{}) error
+}
+
 // BlockChain encapsulates functions required to sync a (full or fast) blockchain.
 type BlockChain interface {
 	LightChain
@@ -303,6 +315,13 @@ func (d *Downloader) SyncBloomContains(hash []byte) bool {
 	return d.stateBloom == nil || d.stateBloom.Contains(hash)
 }
 
+// Quorum
+// SetPrivateStateSyncer sets the syncer of the private states of the fast sync pivot
+// block, without which a fast synced node misses the private states.
+func (d *Downloader) SetPrivateStateSyncer(syncer PrivateStateSyncer) {
+	d.privateStateSyncer = syncer
+}
+
 // RegisterPeer injects a new download peer into the set of block source to be
 // used for fetching hashes and blocks from.
 func (d *Downloader) RegisterPeer(id string, version int, peer Peer) error {
@@ -1904,6 +1923,18 @@ func (d *Downloader) commitPivotBlock(result *fetchResult) error {
 	block := types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles)
 	log.Debug("Committing fast sync pivot as new head", "number", block.Number(), "hash", block.Hash())
 
+	// Quorum
+	// the private states must be in place before the blocks after the pivot are executed
+	if d.privateStateSyncer != nil {
+		d.cancelLock.RLock()
+		cancel := d.cancelCh
+		d.cancelLock.RUnlock()
+		if err := d.privateStateSyncer.SyncPrivateStates(result.Header, cancel); err != nil {
+			return fmt.Errorf("private states of the pivot block: %v", err)
+		}
+	}
+	// End Quorum
+
 	// Commit the pivot block as the new head, will require full sync from here on
 	if _, err := d.blockchain.InsertReceiptChain([]*types.Block{block}, []types.Receipts{result.Receipts}, d.ancientLimit); err != nil {
 		return err
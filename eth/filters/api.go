@@ -18,6 +18,7 @@ package filters
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,14 +29,27 @@ import (
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var (
 	deadline = 5 * time.Minute // consider a filter inactive if it has not been polled for within deadline
+
+	// Quorum
+	paginatedLogsBlockRange = uint64(1024)  // number of blocks filtered at once by a paginated eth_getLogs query
+	paginatedLogsMaxBlocks  = uint64(16384) // number of blocks scanned by a paginated eth_getLogs query at most
+	maxPersistentFilters    = 64            // number of persistent filters installed at most
+
+	errTooManyPersistentFilters = errors.New("too many persistent filters")
 )
 
 // filter is a helper struct that holds meta information over the filter type
@@ -47,6 +61,18 @@ type filter struct {
 	crit     FilterCriteria
 	logs     []*types.Log
 	s        *Subscription // associated subscription in event system
+
+	// Quorum
+	persistent bool   // filter survives node restarts and doesn't time out
+	lastBlock  uint64 // number of the last block whose logs were delivered, for persistent filters
+	polledHead uint64 // number of the head block at the last poll, for persistent filters
+}
+
+// Quorum
+// persistentFilter is the record of a log filter surviving node restarts
+type persistentFilter struct {
+	Criteria  ethereum.FilterQuery `json:"criteria"`
+	LastBlock uint64               `json:"lastBlock"`
 }
 
 // PublicFilterAPI offers support to create and manage filters. This will allow external clients to retrieve various
@@ -83,6 +109,9 @@ func (api *PublicFilterAPI) timeoutLoop() {
 		<-ticker.C
 		api.filtersMu.Lock()
 		for id, f := range api.filters {
+			if f.persistent {
+				continue
+			}
 			select {
 			case <-f.deadline.C:
 				f.s.Unsubscribe()
@@ -133,14 +162,36 @@ func (api *PublicFilterAPI) NewPendingTransactionFilter() rpc.ID {
 	return pendingTxSub.ID
 }
 
+// Quorum
+// PendingTransactionsCriteria are the optional settings of the newPendingTransactions subscription
+type PendingTransactionsCriteria struct {
+	FullTransactions bool `json:"fullTransactions"` // send transaction objects instead of hashes
+}
+
+// PendingTransaction is sent by the newPendingTransactions subscription when full transactions are requested
+type PendingTransaction struct {
+	*ethapi.RPCTransaction
+	IsPrivate bool `json:"isPrivate"`
+	// PrivateInput is the decrypted input of a private transaction, only set if the caller's private state
+	// is a party of the transaction
+	PrivateInput hexutil.Bytes `json:"privateInput,omitempty"`
+}
+
 // NewPendingTransactions creates a subscription that is triggered each time a transaction
 // enters the transaction pool and was signed from one of the transactions this nodes manages.
-func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+//
+// Quorum: if criteria.FullTransactions is set, complete transaction objects are sent instead of hashes
+func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, criteria *PendingTransactionsCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	// Quorum
+	if criteria != nil && criteria.FullTransactions {
+		return api.newFullPendingTransactions(ctx, notifier)
+	}
+
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
@@ -168,6 +219,58 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
+// Quorum
+// newFullPendingTransactions sends a PendingTransaction each time a transaction enters the transaction pool
+func (api *PublicFilterAPI) newFullPendingTransactions(ctx context.Context, notifier *rpc.Notifier) (*rpc.Subscription, error) {
+	psm, err := api.backend.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return &rpc.Subscription{}, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		txs := make(chan core.NewTxsEvent, 128)
+		txsSub := api.backend.SubscribeNewTxsEvent(txs)
+		defer txsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-txs:
+				for _, tx := range ev.Txs {
+					notifier.Notify(rpcSub.ID, api.newPendingTransaction(tx, psm))
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// newPendingTransaction decrypts the input of private transactions the given private state is a party of
+func (api *PublicFilterAPI) newPendingTransaction(tx *types.Transaction, psm *mps.PrivateStateMetadata) *PendingTransaction {
+	result := &PendingTransaction{
+		RPCTransaction: ethapi.NewRPCPendingTransaction(tx),
+		IsPrivate:      tx.IsPrivate(),
+	}
+	if !tx.IsPrivate() {
+		return result
+	}
+	_, managedParties, data, _, err := private.P.Receive(common.BytesToEncryptedPayloadHash(tx.Data()))
+	if err != nil {
+		log.Debug("Unable to retrieve private payload of pending transaction", "tx", tx.Hash(), "err", err)
+		return result
+	}
+	if !api.backend.PSMR().NotIncludeAny(psm, managedParties...) {
+		result.PrivateInput = data
+	}
+	return result
+}
+
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 //
@@ -304,30 +407,162 @@ func (api *PublicFilterAPI) NewFilter(ctx context.Context, crit FilterCriteria)
 	if err != nil {
 		return rpc.ID(""), err
 	}
+	f := &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub}
+
+	// Quorum: the logs are delivered from the current block onwards, even after a restart
+	if crit.Persistent {
+		head, err := api.currentBlock(ctx)
+		if err != nil {
+			logsSub.Unsubscribe()
+			return rpc.ID(""), err
+		}
+		f.persistent, f.lastBlock, f.polledHead = true, head, head
+	}
 
 	api.filtersMu.Lock()
-	api.filters[logsSub.ID] = &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub}
+	if f.persistent {
+		if api.persistentFilters() >= maxPersistentFilters {
+			api.filtersMu.Unlock()
+			logsSub.Unsubscribe()
+			return rpc.ID(""), errTooManyPersistentFilters
+		}
+		if err := api.persistFilter(logsSub.ID, f); err != nil {
+			api.filtersMu.Unlock()
+			logsSub.Unsubscribe()
+			return rpc.ID(""), err
+		}
+	}
+	api.filters[logsSub.ID] = f
 	api.filtersMu.Unlock()
 
-	go func() {
-		for {
-			select {
-			case l := <-logs:
-				api.filtersMu.Lock()
-				if f, found := api.filters[logsSub.ID]; found {
-					f.logs = append(f.logs, l...)
+	go api.collectLogs(logsSub.ID, logsSub, logs, 0)
+
+	return logsSub.ID, nil
+}
+
+// collectLogs buffers the logs of blocks after skipUntil matched by the subscription
+// until they are retrieved with eth_getFilterChanges.
+func (api *PublicFilterAPI) collectLogs(id rpc.ID, logsSub *Subscription, logs chan []*types.Log, skipUntil uint64) {
+	for {
+		select {
+		case l := <-logs:
+			api.filtersMu.Lock()
+			if f, found := api.filters[id]; found {
+				for _, log := range l {
+					if log.BlockNumber > skipUntil {
+						f.logs = append(f.logs, log)
+					}
 				}
-				api.filtersMu.Unlock()
-			case <-logsSub.Err():
-				api.filtersMu.Lock()
-				delete(api.filters, logsSub.ID)
-				api.filtersMu.Unlock()
-				return
 			}
+			api.filtersMu.Unlock()
+		case <-logsSub.Err():
+			api.filtersMu.Lock()
+			delete(api.filters, id)
+			api.filtersMu.Unlock()
+			return
 		}
-	}()
+	}
+}
 
-	return logsSub.ID, nil
+// Quorum
+// persistFilter stores the criteria and the delivery cursor of a persistent filter.
+func (api *PublicFilterAPI) persistFilter(id rpc.ID, f *filter) error {
+	data, err := json.Marshal(&persistentFilter{Criteria: ethereum.FilterQuery(f.crit), LastBlock: f.lastBlock})
+	if err != nil {
+		return err
+	}
+	return rawdb.WritePersistentFilter(api.chainDb, string(id), data)
+}
+
+// Quorum
+// persistentFilters returns the number of persistent filters installed, filtersMu must be held.
+func (api *PublicFilterAPI) persistentFilters() int {
+	count := 0
+	for _, f := range api.filters {
+		if f.persistent {
+			count++
+		}
+	}
+	return count
+}
+
+// Quorum
+// RestoreFilters reinstalls the persistent filters under their original id, with the
+// logs of the blocks imported since their last delivery. It is called once by the
+// service on start, when the chain is available.
+func (api *PublicFilterAPI) RestoreFilters() {
+	for id, data := range rawdb.ReadPersistentFilters(api.chainDb) {
+		var pf persistentFilter
+		if err := json.Unmarshal(data, &pf); err != nil {
+			log.Warn("Dropping invalid persistent filter", "id", id, "err", err)
+			rawdb.DeletePersistentFilter(api.chainDb, id)
+			continue
+		}
+		if err := api.restoreFilter(rpc.ID(id), &pf); err != nil {
+			log.Warn("Unable to restore persistent filter", "id", id, "err", err)
+			continue
+		}
+		log.Info("Restored persistent filter", "id", id, "lastBlock", pf.LastBlock)
+	}
+}
+
+func (api *PublicFilterAPI) restoreFilter(id rpc.ID, pf *persistentFilter) error {
+	logs := make(chan []*types.Log)
+	logsSub, err := api.events.SubscribeLogs(pf.Criteria, logs)
+	if err != nil {
+		return err
+	}
+	head, err := api.currentBlock(context.Background())
+	if err != nil {
+		logsSub.Unsubscribe()
+		return err
+	}
+	crit := FilterCriteria(pf.Criteria)
+	missed, err := api.missedLogs(crit, pf.LastBlock, head)
+	if err != nil {
+		logsSub.Unsubscribe()
+		return err
+	}
+	f := &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: returnLogs(missed), s: logsSub, persistent: true, lastBlock: pf.LastBlock, polledHead: head}
+
+	api.filtersMu.Lock()
+	api.filters[id] = f
+	api.filtersMu.Unlock()
+
+	// the subscription may deliver again the logs of the blocks replayed above
+	go api.collectLogs(id, logsSub, logs, head)
+	return nil
+}
+
+// currentBlock returns the number of the head block.
+func (api *PublicFilterAPI) currentBlock(ctx context.Context) (uint64, error) {
+	head, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	if head == nil {
+		return 0, nil
+	}
+	return head.Number.Uint64(), nil
+}
+
+// missedLogs returns the logs matched by the filter in the blocks after lastBlock up to
+// head, within the block range of the filter.
+func (api *PublicFilterAPI) missedLogs(crit FilterCriteria, lastBlock, head uint64) ([]*types.Log, error) {
+	if crit.BlockHash != nil {
+		return nil, nil
+	}
+	begin, end := int64(lastBlock+1), int64(head)
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 && crit.FromBlock.Int64() > begin {
+		begin = crit.FromBlock.Int64()
+	}
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 && crit.ToBlock.Int64() < end {
+		end = crit.ToBlock.Int64()
+	}
+	if begin > end {
+		return nil, nil
+	}
+	return NewRangeFilter(api.backend, begin, end, crit.Addresses, crit.Topics, crit.PSI).Logs(context.Background())
 }
 
 // GetLogs returns logs matching the given argument that are stored within the state.
@@ -363,6 +598,129 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	return returnLogs(logs), err
 }
 
+// Quorum
+// LogsPage is a page of the logs returned by a paginated eth_getLogs query.
+type LogsPage struct {
+	Logs []*types.Log `json:"logs"`
+	// Cursor is passed to the next query to retrieve the next page, nil on the last page
+	Cursor *hexutil.Bytes `json:"cursor"`
+}
+
+// logsCursor is the position of a paginated eth_getLogs query: the block to resume from
+// and the number of its matching logs which have already been returned.
+type logsCursor struct {
+	block, skip uint64
+}
+
+func (c logsCursor) encode() *hexutil.Bytes {
+	enc := make(hexutil.Bytes, 16)
+	binary.BigEndian.PutUint64(enc[:8], c.block)
+	binary.BigEndian.PutUint64(enc[8:], c.skip)
+	return &enc
+}
+
+func decodeLogsCursor(enc hexutil.Bytes) (logsCursor, error) {
+	if len(enc) != 16 {
+		return logsCursor{}, errors.New("invalid cursor")
+	}
+	return logsCursor{block: binary.BigEndian.Uint64(enc[:8]), skip: binary.BigEndian.Uint64(enc[8:])}, nil
+}
+
+// GetLogsPaginated returns at most maxResults logs matching the given argument, starting at
+// the cursor returned with the previous page if any. Wide range queries can then be retrieved
+// incrementally instead of timing out or exceeding the response size limit. A page scans
+// paginatedLogsMaxBlocks blocks at most, so it may hold fewer logs and still have a cursor.
+func (api *PublicFilterAPI) GetLogsPaginated(ctx context.Context, crit FilterCriteria, maxResults hexutil.Uint64, cursor *hexutil.Bytes) (*LogsPage, error) {
+	if maxResults == 0 {
+		return nil, errors.New("maxResults must be greater than 0")
+	}
+	psm, err := api.backend.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var from logsCursor
+	if cursor != nil {
+		if from, err = decodeLogsCursor(*cursor); err != nil {
+			return nil, err
+		}
+	}
+	page := &LogsPage{Logs: []*types.Log{}}
+	if crit.BlockHash != nil {
+		logs, err := NewBlockFilter(api.backend, *crit.BlockHash, crit.Addresses, crit.Topics, psm.ID).Logs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		page.fill(logs, from, uint64(maxResults))
+		return page, nil
+	}
+	// Resolve the range, the paginated queries are against the blocks already imported
+	head, err := api.currentBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	begin, end := head, head
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 {
+		begin = crit.FromBlock.Uint64()
+	}
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 && crit.ToBlock.Uint64() < head {
+		end = crit.ToBlock.Uint64()
+	}
+	if cursor != nil {
+		if from.block < begin || from.block > end {
+			return nil, errors.New("cursor out of the requested range")
+		}
+		begin = from.block
+	}
+	for start := begin; start <= end; start += paginatedLogsBlockRange {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if start-begin >= paginatedLogsMaxBlocks {
+			// resume from the next block, none of its logs is returned yet
+			page.Cursor = logsCursor{block: start}.encode()
+			break
+		}
+		stop := end
+		if end-start >= paginatedLogsBlockRange {
+			stop = start + paginatedLogsBlockRange - 1
+		}
+		logs, err := NewRangeFilter(api.backend, int64(start), int64(stop), crit.Addresses, crit.Topics, psm.ID).Logs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if page.fill(logs, from, uint64(maxResults)) || stop == end {
+			break
+		}
+	}
+	return page, nil
+}
+
+// fill appends the logs after the cursor to the page until it holds maxResults logs, in which
+// case the cursor of the next page is set and true is returned.
+func (page *LogsPage) fill(logs []*types.Log, from logsCursor, maxResults uint64) bool {
+	var skipped uint64
+	for _, l := range logs {
+		if l.BlockNumber == from.block && skipped < from.skip {
+			skipped++
+			continue
+		}
+		if uint64(len(page.Logs)) == maxResults {
+			// count the logs of the block already returned to resume in the middle of it
+			next := logsCursor{block: l.BlockNumber}
+			if l.BlockNumber == from.block {
+				next.skip = from.skip
+			}
+			for i := len(page.Logs) - 1; i >= 0 && page.Logs[i].BlockNumber == l.BlockNumber; i-- {
+				next.skip++
+			}
+			page.Cursor = next.encode()
+			return true
+		}
+		page.Logs = append(page.Logs, l)
+	}
+	return false
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_uninstallfilter
@@ -375,6 +733,12 @@ func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
 	api.filtersMu.Unlock()
 	if found {
 		f.s.Unsubscribe()
+		// Quorum
+		if f.persistent {
+			if err := rawdb.DeletePersistentFilter(api.chainDb, string(id)); err != nil {
+				log.Warn("Unable to delete persistent filter", "id", id, "err", err)
+			}
+		}
 	}
 
 	return found
@@ -437,6 +801,8 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getfilterchanges
 func (api *PublicFilterAPI) GetFilterChanges(ctx context.Context, id rpc.ID) (interface{}, error) {
+	head, headErr := api.currentBlock(ctx) // Quorum: moves the cursor of persistent filters
+
 	api.filtersMu.Lock()
 	defer api.filtersMu.Unlock()
 
@@ -456,6 +822,24 @@ func (api *PublicFilterAPI) GetFilterChanges(ctx context.Context, id rpc.ID) (in
 		case LogsSubscription, MinedAndPendingLogsSubscription:
 			logs := f.logs
 			f.logs = nil
+			// Quorum: move the cursor of persistent filters past the delivered logs, and past
+			// the head of the previous poll when no log matched: the logs of the blocks up to
+			// that head have reached the filter, those of the current head may not have yet
+			if f.persistent {
+				last := f.polledHead
+				if len(logs) > 0 && logs[len(logs)-1].BlockNumber > last {
+					last = logs[len(logs)-1].BlockNumber
+				}
+				if headErr == nil {
+					f.polledHead = head
+				}
+				if last > f.lastBlock {
+					f.lastBlock = last
+					if err := api.persistFilter(id, f); err != nil {
+						log.Warn("Unable to persist filter cursor", "id", id, "err", err)
+					}
+				}
+			}
 			return returnLogs(logs), nil
 		}
 	}
@@ -489,6 +873,8 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		ToBlock   *rpc.BlockNumber `json:"toBlock"`
 		Addresses interface{}      `json:"address"`
 		Topics    []interface{}    `json:"topics"`
+		// Quorum
+		Persistent bool `json:"persistent"`
 	}
 
 	var raw input
@@ -512,6 +898,7 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	args.Persistent = raw.Persistent
 	args.Addresses = []common.Address{}
 
 	if raw.Addresses != nil {
@@ -17,6 +17,7 @@
 package filters
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
@@ -34,10 +36,13 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
 )
 
 type testBackend struct {
@@ -334,6 +339,212 @@ func TestLogFilterCreation(t *testing.T) {
 	}
 }
 
+// Quorum
+// TestPersistentLogFilter tests that a persistent filter survives a restart and delivers
+// the logs of the blocks imported while the node was down.
+func TestPersistentLogFilter(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		topic   = common.BytesToHash([]byte("topic"))
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {
+		// logs in blocks 3 and 8
+		if i == 2 || i == 7 {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr, Topics: []common.Hash{topic}}}
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+		}
+	})
+	importBlocks := func(blocks types.Blocks, receipts []types.Receipts) {
+		for i, block := range blocks {
+			rawdb.WriteBlock(db, block)
+			rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+			rawdb.WriteHeadBlockHash(db, block.Hash())
+			rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+		}
+	}
+	importBlocks(chain[:5], receipts[:5])
+
+	api := NewPublicFilterAPI(backend, false)
+	id, err := api.NewFilter(context.Background(), FilterCriteria{Addresses: []common.Address{addr}, Persistent: true})
+	if err != nil {
+		t.Fatalf("unable to create filter: %v", err)
+	}
+	api.filters[id].s.Unsubscribe()
+
+	// blocks imported while the node is down
+	importBlocks(chain[5:], receipts[5:])
+
+	api = NewPublicFilterAPI(backend, false)
+	api.RestoreFilters()
+	changes, err := api.GetFilterChanges(context.Background(), id)
+	if err != nil {
+		t.Fatalf("persistent filter not restored: %v", err)
+	}
+	logs := changes.([]*types.Log)
+	if len(logs) != 1 || logs[0].BlockNumber != 8 {
+		t.Fatalf("expected the log of block 8, got %v", logs)
+	}
+	// the logs were replayed up to the head
+	if api.filters[id].lastBlock != 10 {
+		t.Errorf("expected cursor at block 10, got %d", api.filters[id].lastBlock)
+	}
+
+	if !api.UninstallFilter(id) {
+		t.Fatal("unable to uninstall filter")
+	}
+	if persisted := rawdb.ReadPersistentFilters(db); len(persisted) != 0 {
+		t.Errorf("expected no persistent filter, got %d", len(persisted))
+	}
+}
+
+// Quorum
+// TestPersistentLogFilter_whenNoLogMatches tests that the cursor of a persistent filter
+// moves on every poll, so that a restart doesn't scan again the blocks already polled.
+func TestPersistentLogFilter_whenNoLogMatches(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		genesis = new(core.Genesis).MustCommit(db)
+	)
+	chain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {})
+	setHead := func(block *types.Block) {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	setHead(chain[1])
+
+	api := NewPublicFilterAPI(backend, false)
+	id, err := api.NewFilter(context.Background(), FilterCriteria{Addresses: []common.Address{{1}}, Persistent: true})
+	if err != nil {
+		t.Fatalf("unable to create filter: %v", err)
+	}
+	for _, block := range chain[2:] {
+		setHead(block)
+		if _, err := api.GetFilterChanges(context.Background(), id); err != nil {
+			t.Fatalf("unable to poll filter: %v", err)
+		}
+	}
+
+	// the logs of the head may not have reached the filter at the last poll
+	if api.filters[id].lastBlock != 9 {
+		t.Errorf("expected cursor at block 9, got %d", api.filters[id].lastBlock)
+	}
+}
+
+// Quorum
+// TestPersistentLogFilter_whenTooManyFilters tests that the number of persistent filters is capped.
+func TestPersistentLogFilter_whenTooManyFilters(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		api     = NewPublicFilterAPI(backend, false)
+	)
+	for i := 0; i < maxPersistentFilters; i++ {
+		if _, err := api.NewFilter(context.Background(), FilterCriteria{Persistent: true}); err != nil {
+			t.Fatalf("unable to create filter %d: %v", i, err)
+		}
+	}
+
+	if _, err := api.NewFilter(context.Background(), FilterCriteria{Persistent: true}); err != errTooManyPersistentFilters {
+		t.Errorf("expected %v, got %v", errTooManyPersistentFilters, err)
+	}
+	if _, err := api.NewFilter(context.Background(), FilterCriteria{}); err != nil {
+		t.Errorf("non persistent filter not created: %v", err)
+	}
+	if persisted := rawdb.ReadPersistentFilters(db); len(persisted) != maxPersistentFilters {
+		t.Errorf("expected %d persistent filters, got %d", maxPersistentFilters, len(persisted))
+	}
+}
+
+// Quorum
+// TestGetLogsPaginated tests that a paginated query returns all the matching logs across
+// pages, resuming in the middle of a block when needed.
+func TestGetLogsPaginated(t *testing.T) {
+	defer func(blockRange, maxBlocks uint64) {
+		paginatedLogsBlockRange, paginatedLogsMaxBlocks = blockRange, maxBlocks
+	}(paginatedLogsBlockRange, paginatedLogsMaxBlocks)
+	paginatedLogsBlockRange = 3
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		api     = NewPublicFilterAPI(backend, false)
+		addr    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {
+		// two logs in blocks 2, 4 and 6
+		if i == 1 || i == 3 || i == 5 {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr}, {Address: addr}}
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+		}
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	crit := FilterCriteria{FromBlock: big.NewInt(0), Addresses: []common.Address{addr}}
+
+	page, err := api.GetLogsPaginated(context.Background(), crit, 3, nil)
+	if err != nil {
+		t.Fatalf("unable to get the first page: %v", err)
+	}
+	if len(page.Logs) != 3 || page.Logs[2].BlockNumber != 4 || page.Cursor == nil {
+		t.Fatalf("unexpected first page: %d logs, cursor %v", len(page.Logs), page.Cursor)
+	}
+	page, err = api.GetLogsPaginated(context.Background(), crit, 3, page.Cursor)
+	if err != nil {
+		t.Fatalf("unable to get the second page: %v", err)
+	}
+	if len(page.Logs) != 3 || page.Logs[0].BlockNumber != 4 || page.Logs[0].Index != 1 || page.Logs[2].BlockNumber != 6 {
+		t.Fatalf("unexpected second page: %v", page.Logs)
+	}
+	if page.Cursor != nil {
+		t.Errorf("expected no cursor after the last page, got %v", page.Cursor)
+	}
+
+	if _, err := api.GetLogsPaginated(context.Background(), crit, 0, nil); err == nil {
+		t.Error("expected an error when maxResults is 0")
+	}
+	if _, err := api.GetLogsPaginated(context.Background(), crit, 3, &hexutil.Bytes{0x1}); err == nil {
+		t.Error("expected an error for an invalid cursor")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := api.GetLogsPaginated(ctx, crit, 3, nil); err != context.Canceled {
+		t.Errorf("expected the query to be canceled, got %v", err)
+	}
+
+	// the range is clamped to the head, and a page scans paginatedLogsMaxBlocks blocks at most
+	paginatedLogsMaxBlocks = 3
+	crit.ToBlock = big.NewInt(1000)
+	page, err = api.GetLogsPaginated(context.Background(), crit, 10, nil)
+	if err != nil {
+		t.Fatalf("unable to get the first capped page: %v", err)
+	}
+	if len(page.Logs) != 2 || page.Logs[0].BlockNumber != 2 || page.Cursor == nil {
+		t.Fatalf("unexpected first capped page: %d logs, cursor %v", len(page.Logs), page.Cursor)
+	}
+	page, err = api.GetLogsPaginated(context.Background(), crit, 10, page.Cursor)
+	if err != nil {
+		t.Fatalf("unable to get the second capped page: %v", err)
+	}
+	if len(page.Logs) != 2 || page.Logs[0].BlockNumber != 4 || page.Cursor == nil {
+		t.Fatalf("unexpected second capped page: %d logs, cursor %v", len(page.Logs), page.Cursor)
+	}
+}
+
 // TestInvalidLogFilterCreation tests whether invalid filter log criteria results in an error
 // when the filter is created.
 func TestInvalidLogFilterCreation(t *testing.T) {
@@ -636,3 +847,68 @@ func flattenLogs(pl [][]*types.Log) []*types.Log {
 	}
 	return logs
 }
+
+// Quorum
+func TestPendingTxSubscription_whenFullTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	saved := private.P
+	defer func() { private.P = saved }()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		api     = NewPublicFilterAPI(backend, false)
+		server  = rpc.NewServer()
+
+		payloadHash = common.BytesToEncryptedPayloadHash([]byte("arbitrary payload hash"))
+		publicTx    = types.NewTransaction(0, common.HexToAddress("0xb794f5ea0ba39494ce83a213fffba74279579268"), new(big.Int), 0, new(big.Int), []byte("public input"))
+		privateTx   = types.NewTransaction(1, common.HexToAddress("0xb794f5ea0ba39494ce83a213fffba74279579268"), new(big.Int), 0, new(big.Int), payloadHash.Bytes())
+	)
+	privateTx.SetPrivate()
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().Receive(payloadHash).Return("", []string{"party"}, []byte("private input"), nil, nil)
+	private.P = mockPTM
+
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatalf("unable to register api: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	txs := make(chan *PendingTransaction)
+	sub, err := client.EthSubscribe(context.Background(), txs, "newPendingTransactions", PendingTransactionsCriteria{FullTransactions: true})
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	time.Sleep(1 * time.Second)
+	backend.txFeed.Send(core.NewTxsEvent{Txs: []*types.Transaction{publicTx, privateTx}})
+
+	for i, want := range []struct {
+		tx           *types.Transaction
+		isPrivate    bool
+		privateInput []byte
+	}{
+		{publicTx, false, nil},
+		{privateTx, true, []byte("private input")},
+	} {
+		select {
+		case tx := <-txs:
+			if tx.Hash != want.tx.Hash() {
+				t.Errorf("tx %d: hash mismatch, want %x, got %x", i, want.tx.Hash(), tx.Hash)
+			}
+			if tx.IsPrivate != want.isPrivate {
+				t.Errorf("tx %d: isPrivate mismatch, want %v, got %v", i, want.isPrivate, tx.IsPrivate)
+			}
+			if !bytes.Equal(tx.PrivateInput, want.privateInput) {
+				t.Errorf("tx %d: privateInput mismatch, want %x, got %x", i, want.privateInput, tx.PrivateInput)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("tx %d: timeout waiting for pending transaction", i)
+		}
+	}
+}
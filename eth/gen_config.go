@@ -13,50 +13,68 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/qlight"
+	"github.com/ethereum/go-ethereum/statesync"
 )
 
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               uint64
-		SyncMode                downloader.SyncMode
-		DiscoveryURLs           []string
-		NoPruning               bool
-		NoPrefetch              bool
-		TxLookupLimit           uint64                 `toml:",omitempty"`
-		Whitelist               map[uint64]common.Hash `toml:"-"`
-		LightServ               int                    `toml:",omitempty"`
-		LightIngress            int                    `toml:",omitempty"`
-		LightEgress             int                    `toml:",omitempty"`
-		LightPeers              int                    `toml:",omitempty"`
-		LightNoPrune            bool                   `toml:",omitempty"`
-		UltraLightServers       []string               `toml:",omitempty"`
-		UltraLightFraction      int                    `toml:",omitempty"`
-		UltraLightOnlyAnnounce  bool                   `toml:",omitempty"`
-		SkipBcVersionCheck      bool                   `toml:"-"`
-		DatabaseHandles         int                    `toml:"-"`
-		DatabaseCache           int
-		DatabaseFreezer         string
-		TrieCleanCache          int
-		TrieCleanCacheJournal   string        `toml:",omitempty"`
-		TrieCleanCacheRejournal time.Duration `toml:",omitempty"`
-		TrieDirtyCache          int
-		TrieTimeout             time.Duration
-		SnapshotCache           int
-		Miner                   miner.Config
-		Ethash                  ethash.Config
-		TxPool                  core.TxPoolConfig
-		GPO                     gasprice.Config
-		EnablePreimageRecording bool
-		Istanbul                istanbul.Config
-		DocRoot                 string `toml:"-"`
-		EWASMInterpreter        string
-		EVMInterpreter          string
-		RPCGasCap               uint64                         `toml:",omitempty"`
-		RPCTxFeeCap             float64                        `toml:",omitempty"`
-		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
-		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		Genesis                      *core.Genesis `toml:",omitempty"`
+		NetworkId                    uint64
+		SyncMode                     downloader.SyncMode
+		DiscoveryURLs                []string
+		NoPruning                    bool
+		NoPrefetch                   bool
+		TxLookupLimit                uint64                 `toml:",omitempty"`
+		Whitelist                    map[uint64]common.Hash `toml:"-"`
+		LightServ                    int                    `toml:",omitempty"`
+		LightIngress                 int                    `toml:",omitempty"`
+		LightEgress                  int                    `toml:",omitempty"`
+		LightPeers                   int                    `toml:",omitempty"`
+		LightNoPrune                 bool                   `toml:",omitempty"`
+		UltraLightServers            []string               `toml:",omitempty"`
+		UltraLightFraction           int                    `toml:",omitempty"`
+		UltraLightOnlyAnnounce       bool                   `toml:",omitempty"`
+		SkipBcVersionCheck           bool                   `toml:"-"`
+		DatabaseHandles              int                    `toml:"-"`
+		DatabaseCache                int
+		DatabaseFreezer              string
+		TrieCleanCache               int
+		TrieCleanCacheJournal        string        `toml:",omitempty"`
+		TrieCleanCacheRejournal      time.Duration `toml:",omitempty"`
+		TrieDirtyCache               int
+		TrieTimeout                  time.Duration
+		SnapshotCache                int
+		Miner                        miner.Config
+		Ethash                       ethash.Config
+		TxPool                       core.TxPoolConfig
+		GPO                          gasprice.Config
+		EnablePreimageRecording      bool
+		RaftMode                     bool
+		EnableNodePermission         bool
+		Istanbul                     istanbul.Config
+		IstanbulSigner               istanbul.Signer `toml:"-"`
+		IstanbulKMSKey               string          `toml:",omitempty"`
+		DocRoot                      string          `toml:"-"`
+		EWASMInterpreter             string
+		EVMInterpreter               string
+		RPCGasCap                    uint64                         `toml:",omitempty"`
+		RPCTxFeeCap                  float64                        `toml:",omitempty"`
+		Checkpoint                   *params.TrustedCheckpoint      `toml:",omitempty"`
+		CheckpointOracle             *params.CheckpointOracleConfig `toml:",omitempty"`
+		EVMCallTimeOut               time.Duration
+		EnableMultitenancy           bool                     `toml:"-"`
+		SaveRevertReason             bool                     `toml:",omitempty"`
+		QuorumImmutabilityThreshold  int                      `toml:",omitempty"`
+		PrivateTrieCleanCacheJournal string                   `toml:",omitempty"`
+		PrivateStateColdAfter        time.Duration            `toml:",omitempty"`
+		IndexPrivatePayloads         bool                     `toml:",omitempty"`
+		Health                       HealthConfig             `toml:",omitempty"`
+		QuorumLightServer            *qlight.ServerConfig     `toml:",omitempty"`
+		QuorumLightClient            *QuorumLightClientConfig `toml:",omitempty"`
+		QuorumStateSyncServer        *statesync.ServerConfig  `toml:",omitempty"`
+		QuorumStateSyncServerNode    string                   `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -90,7 +108,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.RaftMode = c.RaftMode
+	enc.EnableNodePermission = c.EnableNodePermission
 	enc.Istanbul = c.Istanbul
+	enc.IstanbulSigner = c.IstanbulSigner
+	enc.IstanbulKMSKey = c.IstanbulKMSKey
 	enc.DocRoot = c.DocRoot
 	enc.EWASMInterpreter = c.EWASMInterpreter
 	enc.EVMInterpreter = c.EVMInterpreter
@@ -98,51 +120,79 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
+	enc.EVMCallTimeOut = c.EVMCallTimeOut
+	enc.EnableMultitenancy = c.EnableMultitenancy
+	enc.SaveRevertReason = c.SaveRevertReason
+	enc.QuorumImmutabilityThreshold = c.QuorumImmutabilityThreshold
+	enc.PrivateTrieCleanCacheJournal = c.PrivateTrieCleanCacheJournal
+	enc.PrivateStateColdAfter = c.PrivateStateColdAfter
+	enc.IndexPrivatePayloads = c.IndexPrivatePayloads
+	enc.Health = c.Health
+	enc.QuorumLightServer = c.QuorumLightServer
+	enc.QuorumLightClient = c.QuorumLightClient
+	enc.QuorumStateSyncServer = c.QuorumStateSyncServer
+	enc.QuorumStateSyncServerNode = c.QuorumStateSyncServerNode
 	return &enc, nil
 }
 
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               *uint64
-		SyncMode                *downloader.SyncMode
-		DiscoveryURLs           []string
-		NoPruning               *bool
-		NoPrefetch              *bool
-		TxLookupLimit           *uint64                `toml:",omitempty"`
-		Whitelist               map[uint64]common.Hash `toml:"-"`
-		LightServ               *int                   `toml:",omitempty"`
-		LightIngress            *int                   `toml:",omitempty"`
-		LightEgress             *int                   `toml:",omitempty"`
-		LightPeers              *int                   `toml:",omitempty"`
-		LightNoPrune            *bool                  `toml:",omitempty"`
-		UltraLightServers       []string               `toml:",omitempty"`
-		UltraLightFraction      *int                   `toml:",omitempty"`
-		UltraLightOnlyAnnounce  *bool                  `toml:",omitempty"`
-		SkipBcVersionCheck      *bool                  `toml:"-"`
-		DatabaseHandles         *int                   `toml:"-"`
-		DatabaseCache           *int
-		DatabaseFreezer         *string
-		TrieCleanCache          *int
-		TrieCleanCacheJournal   *string        `toml:",omitempty"`
-		TrieCleanCacheRejournal *time.Duration `toml:",omitempty"`
-		TrieDirtyCache          *int
-		TrieTimeout             *time.Duration
-		SnapshotCache           *int
-		Miner                   *miner.Config
-		Ethash                  *ethash.Config
-		TxPool                  *core.TxPoolConfig
-		GPO                     *gasprice.Config
-		EnablePreimageRecording *bool
-		Istanbul                *istanbul.Config
-		DocRoot                 *string `toml:"-"`
-		EWASMInterpreter        *string
-		EVMInterpreter          *string
-		RPCGasCap               *uint64                        `toml:",omitempty"`
-		RPCTxFeeCap             *float64                       `toml:",omitempty"`
-		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
-		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		Genesis                      *core.Genesis `toml:",omitempty"`
+		NetworkId                    *uint64
+		SyncMode                     *downloader.SyncMode
+		DiscoveryURLs                []string
+		NoPruning                    *bool
+		NoPrefetch                   *bool
+		TxLookupLimit                *uint64                `toml:",omitempty"`
+		Whitelist                    map[uint64]common.Hash `toml:"-"`
+		LightServ                    *int                   `toml:",omitempty"`
+		LightIngress                 *int                   `toml:",omitempty"`
+		LightEgress                  *int                   `toml:",omitempty"`
+		LightPeers                   *int                   `toml:",omitempty"`
+		LightNoPrune                 *bool                  `toml:",omitempty"`
+		UltraLightServers            []string               `toml:",omitempty"`
+		UltraLightFraction           *int                   `toml:",omitempty"`
+		UltraLightOnlyAnnounce       *bool                  `toml:",omitempty"`
+		SkipBcVersionCheck           *bool                  `toml:"-"`
+		DatabaseHandles              *int                   `toml:"-"`
+		DatabaseCache                *int
+		DatabaseFreezer              *string
+		TrieCleanCache               *int
+		TrieCleanCacheJournal        *string        `toml:",omitempty"`
+		TrieCleanCacheRejournal      *time.Duration `toml:",omitempty"`
+		TrieDirtyCache               *int
+		TrieTimeout                  *time.Duration
+		SnapshotCache                *int
+		Miner                        *miner.Config
+		Ethash                       *ethash.Config
+		TxPool                       *core.TxPoolConfig
+		GPO                          *gasprice.Config
+		EnablePreimageRecording      *bool
+		RaftMode                     *bool
+		EnableNodePermission         *bool
+		Istanbul                     *istanbul.Config
+		IstanbulSigner               istanbul.Signer `toml:"-"`
+		IstanbulKMSKey               *string         `toml:",omitempty"`
+		DocRoot                      *string         `toml:"-"`
+		EWASMInterpreter             *string
+		EVMInterpreter               *string
+		RPCGasCap                    *uint64                        `toml:",omitempty"`
+		RPCTxFeeCap                  *float64                       `toml:",omitempty"`
+		Checkpoint                   *params.TrustedCheckpoint      `toml:",omitempty"`
+		CheckpointOracle             *params.CheckpointOracleConfig `toml:",omitempty"`
+		EVMCallTimeOut               *time.Duration
+		EnableMultitenancy           *bool                    `toml:"-"`
+		SaveRevertReason             *bool                    `toml:",omitempty"`
+		QuorumImmutabilityThreshold  *int                     `toml:",omitempty"`
+		PrivateTrieCleanCacheJournal *string                  `toml:",omitempty"`
+		PrivateStateColdAfter        *time.Duration           `toml:",omitempty"`
+		IndexPrivatePayloads         *bool                    `toml:",omitempty"`
+		Health                       *HealthConfig            `toml:",omitempty"`
+		QuorumLightServer            *qlight.ServerConfig     `toml:",omitempty"`
+		QuorumLightClient            *QuorumLightClientConfig `toml:",omitempty"`
+		QuorumStateSyncServer        *statesync.ServerConfig  `toml:",omitempty"`
+		QuorumStateSyncServerNode    *string                  `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -241,9 +291,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.RaftMode != nil {
+		c.RaftMode = *dec.RaftMode
+	}
+	if dec.EnableNodePermission != nil {
+		c.EnableNodePermission = *dec.EnableNodePermission
+	}
 	if dec.Istanbul != nil {
 		c.Istanbul = *dec.Istanbul
 	}
+	if dec.IstanbulSigner != nil {
+		c.IstanbulSigner = dec.IstanbulSigner
+	}
+	if dec.IstanbulKMSKey != nil {
+		c.IstanbulKMSKey = *dec.IstanbulKMSKey
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
@@ -265,5 +327,41 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.CheckpointOracle != nil {
 		c.CheckpointOracle = dec.CheckpointOracle
 	}
+	if dec.EVMCallTimeOut != nil {
+		c.EVMCallTimeOut = *dec.EVMCallTimeOut
+	}
+	if dec.EnableMultitenancy != nil {
+		c.EnableMultitenancy = *dec.EnableMultitenancy
+	}
+	if dec.SaveRevertReason != nil {
+		c.SaveRevertReason = *dec.SaveRevertReason
+	}
+	if dec.QuorumImmutabilityThreshold != nil {
+		c.QuorumImmutabilityThreshold = *dec.QuorumImmutabilityThreshold
+	}
+	if dec.PrivateTrieCleanCacheJournal != nil {
+		c.PrivateTrieCleanCacheJournal = *dec.PrivateTrieCleanCacheJournal
+	}
+	if dec.PrivateStateColdAfter != nil {
+		c.PrivateStateColdAfter = *dec.PrivateStateColdAfter
+	}
+	if dec.IndexPrivatePayloads != nil {
+		c.IndexPrivatePayloads = *dec.IndexPrivatePayloads
+	}
+	if dec.Health != nil {
+		c.Health = *dec.Health
+	}
+	if dec.QuorumLightServer != nil {
+		c.QuorumLightServer = dec.QuorumLightServer
+	}
+	if dec.QuorumLightClient != nil {
+		c.QuorumLightClient = dec.QuorumLightClient
+	}
+	if dec.QuorumStateSyncServer != nil {
+		c.QuorumStateSyncServer = dec.QuorumStateSyncServer
+	}
+	if dec.QuorumStateSyncServerNode != nil {
+		c.QuorumStateSyncServerNode = *dec.QuorumStateSyncServerNode
+	}
 	return nil
 }
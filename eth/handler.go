@@ -28,6 +28,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/chaos"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
@@ -99,8 +100,9 @@ type ProtocolManager struct {
 	peerWG    sync.WaitGroup
 
 	// Quorum
-	raftMode bool
-	engine   consensus.Engine
+	raftMode      bool
+	engine        consensus.Engine
+	serveThrottle *serveThrottle // nil unless the chain served to the syncing peers is throttled
 
 	// Test fields or hooks
 	broadcastTxAnnouncesOnly bool // Testing field, disable transaction propagation
@@ -447,6 +449,10 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			return nil
 		}
 	} else if handler, ok := pm.engine.(consensus.Handler); ok { // quorum: NewBlock required for consensus, e.g. "istanbul"
+		// messages from partitioned peers are lost in chaos builds
+		if drop, _ := chaos.Intercept(p.Node().ID(), chaos.Inbound); drop {
+			return nil
+		}
 		pubKey := p.Node().Pubkey()
 		addr := crypto.PubkeyToAddress(*pubKey)
 		handled, err := handler.HandleMsg(addr, msg)
@@ -464,6 +470,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 	// Block header query, collect the requested headers and reply
 	case msg.Code == GetBlockHeadersMsg:
+		// Quorum: the chain served to the syncing peers may be throttled
+		if !pm.serveThrottle.acquire(pm.quitSync) {
+			return p2p.DiscQuitting
+		}
+		defer pm.serveThrottle.release()
 		// Decode the complex header query
 		var query getBlockHeadersData
 		if err := msg.Decode(&query); err != nil {
@@ -548,6 +559,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				query.Origin.Number += query.Skip + 1
 			}
 		}
+		if !pm.serveThrottle.wait(int(bytes), pm.quitSync) { // Quorum
+			return p2p.DiscQuitting
+		}
 		return p.SendBlockHeaders(headers)
 
 	case msg.Code == BlockHeadersMsg:
@@ -604,6 +618,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 	case msg.Code == GetBlockBodiesMsg:
+		// Quorum: the chain served to the syncing peers may be throttled
+		if !pm.serveThrottle.acquire(pm.quitSync) {
+			return p2p.DiscQuitting
+		}
+		defer pm.serveThrottle.release()
 		// Decode the retrieval message
 		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
 		if _, err := msgStream.List(); err != nil {
@@ -628,6 +647,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				bytes += len(data)
 			}
 		}
+		if !pm.serveThrottle.wait(bytes, pm.quitSync) { // Quorum
+			return p2p.DiscQuitting
+		}
 		return p.SendBlockBodiesRLP(bodies)
 
 	case msg.Code == BlockBodiesMsg:
@@ -657,6 +679,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 	case p.version >= eth63 && msg.Code == GetNodeDataMsg:
+		// Quorum: the chain served to the syncing peers may be throttled
+		if !pm.serveThrottle.acquire(pm.quitSync) {
+			return p2p.DiscQuitting
+		}
+		defer pm.serveThrottle.release()
 		// Decode the retrieval message
 		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
 		if _, err := msgStream.List(); err != nil {
@@ -692,6 +719,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				bytes += len(entry)
 			}
 		}
+		if !pm.serveThrottle.wait(bytes, pm.quitSync) { // Quorum
+			return p2p.DiscQuitting
+		}
 		return p.SendNodeData(data)
 
 	case p.version >= eth63 && msg.Code == NodeDataMsg:
@@ -706,6 +736,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 	case p.version >= eth63 && msg.Code == GetReceiptsMsg:
+		// Quorum: the chain served to the syncing peers may be throttled
+		if !pm.serveThrottle.acquire(pm.quitSync) {
+			return p2p.DiscQuitting
+		}
+		defer pm.serveThrottle.release()
 		// Decode the retrieval message
 		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
 		if _, err := msgStream.List(); err != nil {
@@ -739,6 +774,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				bytes += len(encoded)
 			}
 		}
+		if !pm.serveThrottle.wait(bytes, pm.quitSync) { // Quorum
+			return p2p.DiscQuitting
+		}
 		return p.SendReceiptsRLP(receipts)
 
 	case p.version >= eth63 && msg.Code == ReceiptsMsg:
@@ -1059,7 +1097,7 @@ func (self *ProtocolManager) FindPeers(targets map[common.Address]bool) map[comm
 	for _, p := range self.peers.Peers() {
 		pubKey := p.Node().Pubkey()
 		addr := crypto.PubkeyToAddress(*pubKey)
-		if targets[addr] {
+		if targets == nil || targets[addr] {
 			m[addr] = p
 		}
 	}
@@ -0,0 +1,155 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// Quorum
+
+const (
+	healthLivePath  = "/health/live"
+	healthReadyPath = "/health/ready"
+)
+
+// HealthConfig contains the thresholds of the readiness probe
+type HealthConfig struct {
+	MinPeers    int           // Minimum number of connected peers, 0 disables the check
+	MaxBlockAge time.Duration // Maximum age of the head block, 0 disables the check
+}
+
+// DefaultHealthConfig contains the default thresholds of the readiness probe
+var DefaultHealthConfig = HealthConfig{
+	MinPeers: 1,
+}
+
+// HealthCheck is the result of one of the checks of the readiness probe
+type HealthCheck struct {
+	Healthy bool   `json:"healthy"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// HealthStatus is the response of the /health/live and /health/ready probes
+type HealthStatus struct {
+	Healthy bool                   `json:"healthy"`
+	Checks  map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// healthHandler serves the liveness and readiness probes, for Kubernetes and load-balancer
+// health checks. The node is ready when it is connected to enough peers, not syncing,
+// importing blocks and its private transaction manager is up, until it starts entering
+// maintenance. Its consensus role is reported.
+type healthHandler struct {
+	config         HealthConfig
+	raftMode       bool
+	peerCount      func() int
+	syncing        func() bool
+	currentHeader  func() *types.Header
+	nodeRole       func() consensus.NodeRoleNotifier
+	privacyManager func() PrivacyManagerInfo
+	maintenance    func() node.MaintenanceStatus
+}
+
+func newHealthHandler(e *Ethereum, stack *node.Node) *healthHandler {
+	return &healthHandler{
+		config:         e.config.Health,
+		raftMode:       e.config.RaftMode,
+		peerCount:      func() int { return e.p2pServer.PeerCount() },
+		syncing:        func() bool { return e.Downloader().Synchronising() },
+		currentHeader:  func() *types.Header { return e.blockchain.CurrentHeader() },
+		nodeRole:       e.NodeRoleNotifier,
+		privacyManager: privacyManagerInfo,
+		maintenance:    stack.MaintenanceStatus,
+	}
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var status *HealthStatus
+	switch r.URL.Path {
+	case healthLivePath:
+		// the node is alive as long as it serves HTTP requests
+		status = &HealthStatus{Healthy: true}
+	case healthReadyPath:
+		status = h.readiness()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !status.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+// readiness runs the checks of the readiness probe
+func (h *healthHandler) readiness() *HealthStatus {
+	status := &HealthStatus{Healthy: true, Checks: make(map[string]HealthCheck)}
+	add := func(name string, healthy bool, detail string) {
+		status.Checks[name] = HealthCheck{Healthy: healthy, Detail: detail}
+		status.Healthy = status.Healthy && healthy
+	}
+	if h.config.MinPeers > 0 {
+		peers := h.peerCount()
+		add("peers", peers >= h.config.MinPeers, fmt.Sprintf("%d connected, %d required", peers, h.config.MinPeers))
+	}
+	if h.syncing() {
+		add("sync", false, "synchronising with the network")
+	} else {
+		add("sync", true, "")
+	}
+	if notifier := h.nodeRole(); notifier != nil {
+		// reported only, a node without a role (e.g. not a validator) still serves the requests
+		role := notifier.NodeRole()
+		detail := fmt.Sprintf("%s %s", role.Consensus, role.Role)
+		if role.Role == "" {
+			detail = fmt.Sprintf("no %s role", role.Consensus)
+		}
+		add("consensus", true, detail)
+	}
+	if h.config.MaxBlockAge > 0 {
+		if header := h.currentHeader(); header == nil {
+			add("blockAge", false, "no head block")
+		} else {
+			blockTime := time.Unix(int64(header.Time), 0)
+			if h.raftMode {
+				// raft block timestamps are in nanoseconds
+				blockTime = time.Unix(0, int64(header.Time))
+			}
+			age := time.Since(blockTime)
+			add("blockAge", age <= h.config.MaxBlockAge, fmt.Sprintf("head block #%d is %v old", header.Number, age.Round(time.Second)))
+		}
+	}
+	if info := h.privacyManager(); info.Enabled {
+		// the private transaction managers not reporting their status are assumed up
+		add("privacyManager", info.Status == "" || info.Status == "up", info.Status)
+	}
+	// the load balancers stop routing new calls as soon as the node starts draining its
+	// calls in flight, the phase tells when it may be restarted
+	if phase := h.maintenance().Phase; phase != node.MaintenanceOff {
+		add("maintenance", false, phase)
+	}
+	return status
+}
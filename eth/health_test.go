@@ -0,0 +1,128 @@
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHealthHandler() *healthHandler {
+	return &healthHandler{
+		config:        HealthConfig{MinPeers: 2, MaxBlockAge: time.Minute},
+		peerCount:     func() int { return 2 },
+		syncing:       func() bool { return false },
+		currentHeader: func() *types.Header { return &types.Header{Number: big.NewInt(10), Time: uint64(time.Now().Unix())} },
+		nodeRole: func() consensus.NodeRoleNotifier {
+			return &stubNodeRoleNotifier{role: consensus.NodeRoleEvent{Consensus: "istanbul", Role: "validator"}}
+		},
+		privacyManager: func() PrivacyManagerInfo { return PrivacyManagerInfo{Enabled: true, Status: "up"} },
+		maintenance:    func() node.MaintenanceStatus { return node.MaintenanceStatus{} },
+	}
+}
+
+func serveHealth(t *testing.T, h *healthHandler, path string) (int, *HealthStatus) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var status HealthStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+	return rec.Code, &status
+}
+
+func TestHealthHandler_Live(t *testing.T) {
+	h := newTestHealthHandler()
+	h.syncing = func() bool { return true }
+
+	code, status := serveHealth(t, h, healthLivePath)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Healthy)
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	code, status := serveHealth(t, newTestHealthHandler(), healthReadyPath)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Healthy)
+	assert.Len(t, status.Checks, 5)
+}
+
+func TestHealthHandler_NotReady(t *testing.T) {
+	testCases := []struct {
+		name   string
+		check  string
+		modify func(h *healthHandler)
+	}{
+		{"not enough peers", "peers", func(h *healthHandler) { h.peerCount = func() int { return 1 } }},
+		{"syncing", "sync", func(h *healthHandler) { h.syncing = func() bool { return true } }},
+		{"stale head block", "blockAge", func(h *healthHandler) {
+			h.currentHeader = func() *types.Header {
+				return &types.Header{Number: big.NewInt(10), Time: uint64(time.Now().Add(-time.Hour).Unix())}
+			}
+		}},
+		{"privacy manager down", "privacyManager", func(h *healthHandler) {
+			h.privacyManager = func() PrivacyManagerInfo { return PrivacyManagerInfo{Enabled: true, Status: "connection refused"} }
+		}},
+		{"in maintenance", "maintenance", func(h *healthHandler) {
+			h.maintenance = func() node.MaintenanceStatus { return node.MaintenanceStatus{Phase: node.MaintenanceOn} }
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newTestHealthHandler()
+			tc.modify(h)
+
+			code, status := serveHealth(t, h, healthReadyPath)
+
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+			assert.False(t, status.Healthy)
+			assert.False(t, status.Checks[tc.check].Healthy)
+		})
+	}
+}
+
+func TestHealthHandler_ReadyWithoutConsensusRole(t *testing.T) {
+	h := newTestHealthHandler()
+	h.nodeRole = func() consensus.NodeRoleNotifier {
+		return &stubNodeRoleNotifier{role: consensus.NodeRoleEvent{Consensus: "raft"}}
+	}
+
+	code, status := serveHealth(t, h, healthReadyPath)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Checks["consensus"].Healthy)
+	assert.Equal(t, "no raft role", status.Checks["consensus"].Detail)
+}
+
+func TestHealthHandler_RaftBlockAge(t *testing.T) {
+	h := newTestHealthHandler()
+	h.raftMode = true
+	h.currentHeader = func() *types.Header {
+		return &types.Header{Number: big.NewInt(10), Time: uint64(time.Now().UnixNano())}
+	}
+
+	_, status := serveHealth(t, h, healthReadyPath)
+
+	assert.True(t, status.Checks["blockAge"].Healthy)
+}
+
+func TestHealthHandler_NotReadyWhileEnteringMaintenance(t *testing.T) {
+	for _, phase := range []string{node.MaintenanceDraining, node.MaintenanceHandoff} {
+		h := newTestHealthHandler()
+		h.maintenance = func() node.MaintenanceStatus { return node.MaintenanceStatus{Phase: phase} }
+
+		code, status := serveHealth(t, h, healthReadyPath)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code, phase)
+		assert.False(t, status.Checks["maintenance"].Healthy, phase)
+		assert.Equal(t, phase, status.Checks["maintenance"].Detail)
+	}
+}
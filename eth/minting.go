@@ -0,0 +1,175 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+
+const (
+	// DefaultMintingPauseTimeout is how long minting stays paused when no timeout is given
+	DefaultMintingPauseTimeout = time.Hour
+	// MaxMintingPauseTimeout is the longest pause of minting, it is resumed afterwards
+	// even if the operator forgot to
+	MaxMintingPauseTimeout = 24 * time.Hour
+)
+
+var (
+	errMintingPauseUnsupported = errors.New("pausing the block production is only supported by raft and istanbul")
+	errMintingPaused           = errors.New("the block production is paused, resume it with admin_resumeMinting")
+	errMintingNotPaused        = errors.New("the block production is not paused")
+)
+
+// MintingController stops and restarts the block production of the consensus
+// protocols not running as the consensus engine (i.e.: raft)
+type MintingController interface {
+	// PauseMinting stops producing blocks, even if the node is or becomes the block producer
+	PauseMinting() error
+	// ResumeMinting produces blocks again, if the node is the block producer
+	ResumeMinting() error
+}
+
+// mintingPause is a pause of the block production
+type mintingPause struct {
+	resumeAt  time.Time
+	timer     *time.Timer // resumes the block production when the pause times out
+	wasMining bool        // whether the istanbul miner was running before the pause
+}
+
+// SetMintingController sets the controller of the block production, for consensus
+// protocols not running as the consensus engine (i.e.: raft)
+func (s *Ethereum) SetMintingController(controller MintingController) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mintingController = controller
+}
+
+// PauseMinting stops the block production of the node until ResumeMinting is called
+// or the timeout elapses, DefaultMintingPauseTimeout if 0. Pausing again extends the
+// pause. It returns right away the time the block production will resume at.
+func (s *Ethereum) PauseMinting(timeout time.Duration) (time.Time, error) {
+	if timeout == 0 {
+		timeout = DefaultMintingPauseTimeout
+	}
+	if timeout < 0 || timeout > MaxMintingPauseTimeout {
+		return time.Time{}, errors.New("the pause timeout must be positive and at most 24 hours")
+	}
+	s.mintingMu.Lock()
+	defer s.mintingMu.Unlock()
+
+	resumeAt := time.Now().Add(timeout)
+	if s.mintingPause != nil {
+		s.mintingPause.timer.Stop()
+	} else {
+		pause := new(mintingPause)
+		if controller := s.getMintingController(); controller != nil {
+			if err := controller.PauseMinting(); err != nil {
+				return time.Time{}, err
+			}
+		} else if _, ok := s.engine.(consensus.Handler); ok && !s.config.RaftMode {
+			pause.wasMining = s.IsMining()
+			s.StopMining()
+		} else {
+			return time.Time{}, errMintingPauseUnsupported
+		}
+		s.mintingPause = pause
+		atomic.StoreInt32(&s.mintingPaused, 1)
+	}
+	pause := s.mintingPause
+	pause.resumeAt = resumeAt
+	pause.timer = time.AfterFunc(timeout, func() {
+		s.mintingMu.Lock()
+		defer s.mintingMu.Unlock()
+		if s.mintingPause != pause {
+			return
+		}
+		log.Warn("Block production pause timed out, resuming it")
+		if err := s.resumeMinting(); err != nil {
+			log.Error("Failed to resume the block production", "err", err)
+		}
+	})
+	log.Warn("Block production paused", "resumeAt", resumeAt)
+	return resumeAt, nil
+}
+
+// ResumeMinting resumes the block production paused by PauseMinting
+func (s *Ethereum) ResumeMinting() error {
+	s.mintingMu.Lock()
+	defer s.mintingMu.Unlock()
+	if s.mintingPause == nil {
+		return errMintingNotPaused
+	}
+	s.mintingPause.timer.Stop()
+	return s.resumeMinting()
+}
+
+// resumeMinting restarts the block production, the caller holds mintingMu
+func (s *Ethereum) resumeMinting() error {
+	pause := s.mintingPause
+	s.mintingPause = nil
+	atomic.StoreInt32(&s.mintingPaused, 0)
+	if controller := s.getMintingController(); controller != nil {
+		if err := controller.ResumeMinting(); err != nil {
+			return err
+		}
+	} else if pause.wasMining {
+		if err := s.StartMining(1); err != nil {
+			return err
+		}
+	}
+	log.Info("Block production resumed")
+	return nil
+}
+
+// MintingPausedUntil returns when the paused block production resumes, zero if it is
+// not paused
+func (s *Ethereum) MintingPausedUntil() time.Time {
+	s.mintingMu.Lock()
+	defer s.mintingMu.Unlock()
+	if s.mintingPause == nil {
+		return time.Time{}
+	}
+	return s.mintingPause.resumeAt
+}
+
+// stopMintingPause cancels the resumption of the block production, when stopping the node
+func (s *Ethereum) stopMintingPause() {
+	s.mintingMu.Lock()
+	defer s.mintingMu.Unlock()
+	if s.mintingPause != nil {
+		s.mintingPause.timer.Stop()
+		s.mintingPause = nil
+	}
+}
+
+// isMintingPaused reports whether the block production is paused
+func (s *Ethereum) isMintingPaused() bool {
+	return atomic.LoadInt32(&s.mintingPaused) == 1
+}
+
+func (s *Ethereum) getMintingController() MintingController {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mintingController
+}
@@ -0,0 +1,76 @@
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMintingController struct {
+	paused chan bool
+}
+
+func (c *stubMintingController) PauseMinting() error {
+	c.paused <- true
+	return nil
+}
+
+func (c *stubMintingController) ResumeMinting() error {
+	c.paused <- false
+	return nil
+}
+
+func TestPauseMinting(t *testing.T) {
+	controller := &stubMintingController{paused: make(chan bool, 4)}
+	e := &Ethereum{config: &Config{RaftMode: true}, engine: ethash.NewFaker()}
+	e.SetMintingController(controller)
+
+	resumeAt, err := e.PauseMinting(time.Minute)
+
+	require.NoError(t, err)
+	assert.True(t, <-controller.paused)
+	assert.Equal(t, resumeAt, e.MintingPausedUntil())
+	assert.Equal(t, errMintingPaused, e.StartMining(1))
+
+	_, err = e.PauseMinting(2 * time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, controller.paused, 0, "pausing again only extends the pause")
+
+	require.NoError(t, e.ResumeMinting())
+	assert.False(t, <-controller.paused)
+	assert.True(t, e.MintingPausedUntil().IsZero())
+	assert.Equal(t, errMintingNotPaused, e.ResumeMinting())
+}
+
+func TestPauseMinting_resumesAfterTimeout(t *testing.T) {
+	controller := &stubMintingController{paused: make(chan bool, 4)}
+	e := &Ethereum{config: &Config{RaftMode: true}, engine: ethash.NewFaker()}
+	e.SetMintingController(controller)
+
+	_, err := e.PauseMinting(10 * time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, <-controller.paused)
+
+	select {
+	case paused := <-controller.paused:
+		assert.False(t, paused)
+	case <-time.After(time.Second):
+		t.Fatal("the block production was not resumed after the timeout")
+	}
+	assert.True(t, e.MintingPausedUntil().IsZero())
+}
+
+func TestPauseMinting_whenUnsupported(t *testing.T) {
+	e := &Ethereum{config: &Config{}, engine: ethash.NewFaker()}
+
+	_, err := e.PauseMinting(0)
+
+	assert.Equal(t, errMintingPauseUnsupported, err)
+
+	_, err = e.PauseMinting(MaxMintingPauseTimeout + time.Second)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Quorum
+
+var errPayloadIndexDisabled = errors.New("the private payload index is not enabled, see --private.payload.index")
+
+// PrivatePayloadInclusion is a transaction carrying the hash of a private payload,
+// whether the node is party to the payload or not
+type PrivatePayloadInclusion struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockTime   hexutil.Uint64 `json:"timestamp"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     hexutil.Uint64 `json:"transactionIndex"`
+	// Canonical is false for the blocks replaced by a reorg since they were indexed
+	Canonical bool `json:"canonical"`
+}
+
+// PublicQuorumPayloadIndexAPI serves the index of the private payloads, for the archive
+// and audit nodes proving when private transactions were included in the chain
+// without being party to them
+type PublicQuorumPayloadIndexAPI struct {
+	e *Ethereum
+}
+
+// NewPublicQuorumPayloadIndexAPI creates a new PublicQuorumPayloadIndexAPI
+func NewPublicQuorumPayloadIndexAPI(e *Ethereum) *PublicQuorumPayloadIndexAPI {
+	return &PublicQuorumPayloadIndexAPI{e: e}
+}
+
+// GetPrivatePayloadInclusions returns the transactions carrying the hash of a private
+// payload in the blocks indexed, by block number
+func (api *PublicQuorumPayloadIndexAPI) GetPrivatePayloadInclusions(hash hexutil.Bytes) ([]*PrivatePayloadInclusion, error) {
+	if !api.e.blockchain.IndexPrivatePayloads() {
+		return nil, errPayloadIndexDisabled
+	}
+	if len(hash) != common.EncryptedPayloadHashLength {
+		return nil, fmt.Errorf("invalid private payload hash length %d, want %d", len(hash), common.EncryptedPayloadHashLength)
+	}
+	inclusions := rawdb.ReadPrivatePayloadInclusions(api.e.chainDb, common.BytesToEncryptedPayloadHash(hash))
+	result := make([]*PrivatePayloadInclusion, len(inclusions))
+	for i, inclusion := range inclusions {
+		result[i] = &PrivatePayloadInclusion{
+			BlockNumber: hexutil.Uint64(inclusion.BlockNumber),
+			BlockHash:   inclusion.BlockHash,
+			BlockTime:   hexutil.Uint64(inclusion.BlockTime),
+			TxHash:      inclusion.TxHash,
+			TxIndex:     hexutil.Uint64(inclusion.TxIndex),
+			Canonical:   api.e.blockchain.GetCanonicalHash(inclusion.BlockNumber) == inclusion.BlockHash,
+		}
+	}
+	return result, nil
+}
+
+// PrivatePayloadRef is the hash of a private payload carried by a transaction of a block
+type PrivatePayloadRef struct {
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     hexutil.Uint64 `json:"transactionIndex"`
+	PayloadHash hexutil.Bytes  `json:"payloadHash"`
+}
+
+// GetPrivatePayloadsByBlock returns the hashes of the private payloads carried by the
+// transactions of a block, read from the block whether the index is enabled or not
+func (api *PublicQuorumPayloadIndexAPI) GetPrivatePayloadsByBlock(number rpc.BlockNumber) ([]*PrivatePayloadRef, error) {
+	block := api.e.blockchain.CurrentBlock()
+	if number >= 0 {
+		block = api.e.blockchain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	refs := make([]*PrivatePayloadRef, 0)
+	for i, tx := range block.Transactions() {
+		if !tx.IsPrivate() || len(tx.Data()) != common.EncryptedPayloadHashLength {
+			continue
+		}
+		refs = append(refs, &PrivatePayloadRef{
+			TxHash:      tx.Hash(),
+			TxIndex:     hexutil.Uint64(i),
+			PayloadHash: common.CopyBytes(tx.Data()),
+		})
+	}
+	return refs, nil
+}
@@ -3,11 +3,13 @@ package eth
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/chaos"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
@@ -121,6 +123,10 @@ func (pm *ProtocolManager) handleConsensus(p *p2p.Peer, protoRW p2p.MsgReadWrite
 
 func (pm *ProtocolManager) handleConsensusMsg(p *p2p.Peer, msg p2p.Msg) (bool, error) {
 	if handler, ok := pm.engine.(consensus.Handler); ok {
+		// messages from partitioned peers are lost in chaos builds
+		if drop, _ := chaos.Intercept(p.ID(), chaos.Inbound); drop {
+			return true, nil
+		}
 		pubKey := p.Node().Pubkey()
 		addr := crypto.PubkeyToAddress(*pubKey)
 		handled, err := handler.HandleMsg(addr, msg)
@@ -200,7 +206,20 @@ func (p *peer) SendConsensus(msgcode uint64, data interface{}) error {
 	if p.consensusRw == nil {
 		return nil
 	}
-	return p2p.Send(p.consensusRw, msgcode, data)
+	// the faults of chaos builds are injected here, on the way to the peer
+	drop, delay := chaos.Intercept(p.ID(), chaos.Outbound)
+	if drop {
+		return nil
+	}
+	if delay > 0 {
+		time.AfterFunc(delay, func() {
+			if err := p2p.SendPriority(p.consensusRw, msgcode, data); err != nil {
+				p.Log().Debug("Failed to send delayed consensus message", "err", err)
+			}
+		})
+		return nil
+	}
+	return p2p.SendPriority(p.consensusRw, msgcode, data)
 }
 
 func (p *peer) addConsensusProtoRW(rw p2p.MsgReadWriter) *peer {
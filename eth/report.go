@@ -0,0 +1,505 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Quorum
+
+// Datasets of a compliance report
+const (
+	ReportTransactions = "transactions"
+	ReportReceipts     = "receipts"
+	ReportEvents       = "events"
+)
+
+// Formats of a compliance report
+const (
+	ReportFormatCSV     = "csv"
+	ReportFormatJSONL   = "jsonl" // one JSON object per line
+	ReportFormatParquet = "parquet"
+)
+
+const (
+	// maxRPCReportBlocks is the largest range of blocks exported by debug_exportReport,
+	// the export-report command has no limit
+	maxRPCReportBlocks = 10000
+
+	// reportChunkSize is the size of the data of a debug_exportReport notification
+	reportChunkSize = 256 * 1024
+
+	redactedValue = "REDACTED"
+)
+
+// reportColumns are the columns of each dataset, in order
+var reportColumns = map[string][]string{
+	ReportTransactions: {"blockNumber", "blockHash", "timestamp", "txHash", "txIndex", "from", "to", "value", "gas", "gasPrice", "nonce", "input", "isPrivate"},
+	ReportReceipts:     {"blockNumber", "blockHash", "timestamp", "txHash", "txIndex", "status", "gasUsed", "cumulativeGasUsed", "contractAddress", "logs", "revertReason", "isPrivate"},
+	ReportEvents:       {"blockNumber", "blockHash", "timestamp", "txHash", "txIndex", "logIndex", "address", "topics", "data", "isPrivate"},
+}
+
+// ReportChain is the chain a compliance report is extracted from
+type ReportChain interface {
+	Config() *params.ChainConfig
+	CurrentBlock() *types.Block
+	GetHeaderByNumber(number uint64) *types.Header
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+}
+
+// ReportQuery selects the records of a compliance report and how they are written
+type ReportQuery struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock,omitempty"` // first block, the genesis if nil
+	ToBlock   *rpc.BlockNumber `json:"toBlock,omitempty"`   // last block, the head if nil
+	// FromTime and ToTime select the blocks by their timestamp, instead of their number
+	FromTime *time.Time `json:"fromTime,omitempty"`
+	ToTime   *time.Time `json:"toTime,omitempty"`
+	// Datasets are the exported datasets, all of them if empty
+	Datasets []string `json:"datasets,omitempty"`
+	// Format is ReportFormatCSV, the default, ReportFormatJSONL or ReportFormatParquet
+	Format string `json:"format,omitempty"`
+	// Redact are the columns whose values are masked, or replaced by their keccak256
+	// hash with a ":hash" suffix, e.g. "from:hash" keeps the senders correlated
+	Redact []string `json:"redact,omitempty"`
+}
+
+// ReportSummary describes an exported compliance report
+type ReportSummary struct {
+	FromBlock uint64         `json:"fromBlock"`
+	ToBlock   uint64         `json:"toBlock"`
+	Records   map[string]int `json:"records"` // number of records by dataset
+}
+
+// ReportChunk is a notification of the debug_exportReport subscription, the data of
+// each dataset is streamed in chunks and the last notification is the summary
+type ReportChunk struct {
+	Dataset string         `json:"dataset,omitempty"`
+	Data    []byte         `json:"data,omitempty"`    // next bytes of the dataset file
+	Summary *ReportSummary `json:"summary,omitempty"` // set on the last notification
+	Error   string         `json:"error,omitempty"`   // set on the last notification of a failed report
+}
+
+// Validate checks the query, before any block is read
+func (q *ReportQuery) Validate() error {
+	if q.FromBlock != nil && q.FromTime != nil {
+		return errors.New("the first block of a report is selected by number or by time, not both")
+	}
+	if q.ToBlock != nil && q.ToTime != nil {
+		return errors.New("the last block of a report is selected by number or by time, not both")
+	}
+	for _, dataset := range q.Datasets {
+		if _, ok := reportColumns[dataset]; !ok {
+			return fmt.Errorf("unknown report dataset %q, want %s, %s or %s", dataset, ReportTransactions, ReportReceipts, ReportEvents)
+		}
+	}
+	switch q.Format {
+	case "", ReportFormatCSV, ReportFormatJSONL, ReportFormatParquet:
+	default:
+		return fmt.Errorf("unsupported report format %q, want %s, %s or %s", q.Format, ReportFormatCSV, ReportFormatJSONL, ReportFormatParquet)
+	}
+	_, err := q.redactions()
+	return err
+}
+
+// DatasetNames returns the exported datasets
+func (q *ReportQuery) DatasetNames() []string {
+	if len(q.Datasets) > 0 {
+		return q.Datasets
+	}
+	return []string{ReportTransactions, ReportReceipts, ReportEvents}
+}
+
+// FileExtension returns the extension of the files the datasets are written to
+func (q *ReportQuery) FileExtension() string {
+	switch q.Format {
+	case ReportFormatJSONL:
+		return ".jsonl"
+	case ReportFormatParquet:
+		return ".parquet"
+	}
+	return ".csv"
+}
+
+// redactions returns whether the value of each redacted column is hashed, rather than masked
+func (q *ReportQuery) redactions() (map[string]bool, error) {
+	redactions := make(map[string]bool, len(q.Redact))
+	for _, spec := range q.Redact {
+		column := strings.TrimSuffix(spec, ":hash")
+		known := false
+		for _, columns := range reportColumns {
+			for _, c := range columns {
+				known = known || c == column
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown report column %q", column)
+		}
+		redactions[column] = column != spec
+	}
+	return redactions, nil
+}
+
+// blockRange resolves the numbers of the first and last blocks of the report
+func (q *ReportQuery) blockRange(chain ReportChain, raftMode bool) (uint64, uint64, error) {
+	head := chain.CurrentBlock().NumberU64()
+	resolve := func(n *rpc.BlockNumber, def uint64) uint64 {
+		switch {
+		case n == nil:
+			return def
+		case *n < 0: // latest or pending
+			return head
+		case uint64(*n) > head:
+			return head
+		}
+		return uint64(*n)
+	}
+	from, to := resolve(q.FromBlock, 0), resolve(q.ToBlock, head)
+	// the block timestamps increase with the block numbers
+	firstAfter := func(t time.Time, orEqual bool) uint64 {
+		return uint64(sort.Search(int(head)+1, func(i int) bool {
+			bt := blockTime(chain.GetHeaderByNumber(uint64(i)), raftMode)
+			return bt.After(t) || (orEqual && bt.Equal(t))
+		}))
+	}
+	if q.FromTime != nil {
+		from = firstAfter(*q.FromTime, true)
+	}
+	if q.ToTime != nil {
+		if to = firstAfter(*q.ToTime, false); to == 0 {
+			return 0, 0, errors.New("no block before the end of the report")
+		}
+		to--
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("empty report block range %d-%d", from, to)
+	}
+	return from, to, nil
+}
+
+// blockTime returns the time of a block, raft timestamps are in nanoseconds
+func blockTime(header *types.Header, raftMode bool) time.Time {
+	if raftMode {
+		return time.Unix(0, int64(header.Time)).UTC()
+	}
+	return time.Unix(int64(header.Time), 0).UTC()
+}
+
+// reportWriter writes the records of a dataset
+type reportWriter interface {
+	write(record []string) error
+	flush() error
+}
+
+type csvReportWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvReportWriter) write(record []string) error { return w.w.Write(record) }
+
+func (w *csvReportWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlReportWriter struct {
+	columns []string
+	enc     *json.Encoder
+}
+
+func (w *jsonlReportWriter) write(record []string) error {
+	obj := make(map[string]string, len(record))
+	for i, value := range record {
+		obj[w.columns[i]] = value
+	}
+	return w.enc.Encode(obj)
+}
+
+func (w *jsonlReportWriter) flush() error { return nil }
+
+func newReportWriter(format string, columns []string, out io.Writer) (reportWriter, error) {
+	switch format {
+	case ReportFormatJSONL:
+		return &jsonlReportWriter{columns: columns, enc: json.NewEncoder(out)}, nil
+	case ReportFormatParquet:
+		return newParquetReportWriter(columns, out)
+	}
+	w := &csvReportWriter{w: csv.NewWriter(out)}
+	return w, w.write(columns)
+}
+
+// ExportReport writes the records of the blocks selected by the query to the output
+// of each dataset. The private transactions are reported with the receipts and events
+// of the private state psi only.
+func ExportReport(chain ReportChain, raftMode bool, psi types.PrivateStateIdentifier, query *ReportQuery, outputs map[string]io.Writer) (*ReportSummary, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	redactions, _ := query.redactions()
+	from, to, err := query.blockRange(chain, raftMode)
+	if err != nil {
+		return nil, err
+	}
+	writers := make(map[string]reportWriter)
+	for _, dataset := range query.DatasetNames() {
+		out, ok := outputs[dataset]
+		if !ok {
+			return nil, fmt.Errorf("no output for report dataset %s", dataset)
+		}
+		if writers[dataset], err = newReportWriter(query.Format, reportColumns[dataset], out); err != nil {
+			return nil, err
+		}
+	}
+	summary := &ReportSummary{FromBlock: from, ToBlock: to, Records: make(map[string]int)}
+	emit := func(dataset string, values map[string]string) error {
+		w, ok := writers[dataset]
+		if !ok {
+			return nil
+		}
+		columns := reportColumns[dataset]
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			value := values[column]
+			if hash, redacted := redactions[column]; redacted && value != "" {
+				if hash {
+					value = crypto.Keccak256Hash([]byte(value)).Hex()
+				} else {
+					value = redactedValue
+				}
+			}
+			record[i] = value
+		}
+		summary.Records[dataset]++
+		return w.write(record)
+	}
+	for number := from; number <= to; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		if err := exportBlockReport(chain.Config(), block, chain.GetReceiptsByHash(block.Hash()), raftMode, psi, emit); err != nil {
+			return nil, err
+		}
+	}
+	for _, w := range writers {
+		if err := w.flush(); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// exportBlockReport emits the records of the transactions of a block
+func exportBlockReport(config *params.ChainConfig, block *types.Block, receipts types.Receipts, raftMode bool, psi types.PrivateStateIdentifier, emit func(dataset string, values map[string]string) error) error {
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("block #%d has %d transactions but %d receipts", block.NumberU64(), len(txs), len(receipts))
+	}
+	blockValues := map[string]string{
+		"blockNumber": strconv.FormatUint(block.NumberU64(), 10),
+		"blockHash":   block.Hash().Hex(),
+		"timestamp":   blockTime(block.Header(), raftMode).Format(time.RFC3339Nano),
+	}
+	for i, tx := range txs {
+		var signer types.Signer = types.HomesteadSigner{}
+		if tx.Protected() && !tx.IsPrivate() {
+			signer = types.NewEIP155Signer(config.ChainID)
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("block #%d transaction %s: %v", block.NumberU64(), tx.Hash().Hex(), err)
+		}
+		txValues := map[string]string{
+			"txHash":    tx.Hash().Hex(),
+			"txIndex":   strconv.Itoa(i),
+			"isPrivate": strconv.FormatBool(tx.IsPrivate()),
+		}
+		for k, v := range blockValues {
+			txValues[k] = v
+		}
+		values := merge(txValues, map[string]string{
+			"from":     from.Hex(),
+			"value":    tx.Value().String(),
+			"gas":      strconv.FormatUint(tx.Gas(), 10),
+			"gasPrice": tx.GasPrice().String(),
+			"nonce":    strconv.FormatUint(tx.Nonce(), 10),
+			"input":    hexutil.Encode(tx.Data()),
+		})
+		if tx.To() != nil {
+			values["to"] = tx.To().Hex()
+		}
+		if err := emit(ReportTransactions, values); err != nil {
+			return err
+		}
+
+		receipt := receipts[i]
+		if psReceipt, ok := receipt.PSReceipts[psi]; ok {
+			receipt = psReceipt
+		}
+		values = merge(txValues, map[string]string{
+			"status":            strconv.FormatUint(receipt.Status, 10),
+			"gasUsed":           strconv.FormatUint(receipt.GasUsed, 10),
+			"cumulativeGasUsed": strconv.FormatUint(receipt.CumulativeGasUsed, 10),
+			"logs":              strconv.Itoa(len(receipt.Logs)),
+		})
+		if receipt.ContractAddress != (common.Address{}) {
+			values["contractAddress"] = receipt.ContractAddress.Hex()
+		}
+		if len(receipt.RevertReason) > 0 {
+			values["revertReason"] = hexutil.Encode(receipt.RevertReason)
+		}
+		if err := emit(ReportReceipts, values); err != nil {
+			return err
+		}
+
+		for _, l := range receipt.Logs {
+			topics := make([]string, len(l.Topics))
+			for j, topic := range l.Topics {
+				topics[j] = topic.Hex()
+			}
+			values = merge(txValues, map[string]string{
+				"logIndex": strconv.FormatUint(uint64(l.Index), 10),
+				"address":  l.Address.Hex(),
+				"topics":   strings.Join(topics, ";"),
+				"data":     hexutil.Encode(l.Data),
+			})
+			if err := emit(ReportEvents, values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// merge returns the values of both maps
+func merge(a, b map[string]string) map[string]string {
+	m := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
+
+// ExportReport streams a compliance report of the transactions, receipts and events of
+// a range of at most 10000 blocks, as the content of the dataset files in chunks followed
+// by the summary. The private transactions are reported with the receipts and events of
+// the private state of the caller.
+func (api *PrivateDebugAPI) ExportReport(ctx context.Context, query ReportQuery) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	from, to, err := query.blockRange(api.eth.blockchain, api.eth.config.RaftMode)
+	if err != nil {
+		return nil, err
+	}
+	if to-from+1 > maxRPCReportBlocks {
+		return nil, fmt.Errorf("report of %d blocks exceeds the limit of %d, use the export-report command", to-from+1, maxRPCReportBlocks)
+	}
+	psm, err := api.eth.APIBackend.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the range is resolved once, the head may move meanwhile
+	fromBlock, toBlock := rpc.BlockNumber(from), rpc.BlockNumber(to)
+	query.FromBlock, query.ToBlock, query.FromTime, query.ToTime = &fromBlock, &toBlock, nil, nil
+
+	rpcSub := notifier.CreateSubscription()
+	notify := func(chunk *ReportChunk) error {
+		select {
+		case <-rpcSub.Err():
+			return errors.New("report unsubscribed")
+		case <-notifier.Closed():
+			return errors.New("report connection closed")
+		default:
+		}
+		return notifier.Notify(rpcSub.ID, chunk)
+	}
+	go func() {
+		writers := make(map[string]*reportChunkWriter)
+		outputs := make(map[string]io.Writer)
+		for _, dataset := range query.DatasetNames() {
+			writers[dataset] = &reportChunkWriter{dataset: dataset, notify: notify}
+			outputs[dataset] = writers[dataset]
+		}
+		summary, err := ExportReport(api.eth.blockchain, api.eth.config.RaftMode, psm.ID, &query, outputs)
+		for _, w := range writers {
+			if err == nil {
+				err = w.flush()
+			}
+		}
+		if err != nil {
+			log.Warn("Failed to export the report", "err", err)
+			notify(&ReportChunk{Error: err.Error()})
+			return
+		}
+		notify(&ReportChunk{Summary: summary})
+	}()
+	return rpcSub, nil
+}
+
+// reportChunkWriter notifies the data written to a dataset in chunks of reportChunkSize
+type reportChunkWriter struct {
+	dataset string
+	buf     []byte
+	notify  func(chunk *ReportChunk) error
+}
+
+func (w *reportChunkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= reportChunkSize {
+		if err := w.notify(&ReportChunk{Dataset: w.dataset, Data: w.buf[:reportChunkSize]}); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[reportChunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush notifies the rest of the data
+func (w *reportChunkWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.notify(&ReportChunk{Dataset: w.dataset, Data: w.buf})
+	w.buf = nil
+	return err
+}
@@ -0,0 +1,288 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Quorum
+
+// The parquet report files have one UTF8 column per report column, written in plain
+// encoding without compression. The records are buffered by row groups only, so that
+// a report of any size is written with a bounded memory.
+const (
+	parquetMagic = "PAR1"
+
+	// parquetRowGroupRows and parquetRowGroupBytes bound the records buffered before
+	// a row group is written
+	parquetRowGroupRows  = 10000
+	parquetRowGroupBytes = 16 * 1024 * 1024
+)
+
+// parquet-format enum values
+const (
+	parquetTypeByteArray     = 6
+	parquetRepetitionReq     = 0
+	parquetConvertedUTF8     = 0
+	parquetEncodingPlain     = 0
+	parquetEncodingRLE       = 3
+	parquetCodecUncompressed = 0
+	parquetPageData          = 0
+)
+
+// parquetColumnChunk locates a column chunk of a written row group
+type parquetColumnChunk struct {
+	offset int64 // of the page header
+	size   int64 // of the page header and its values
+	values int64
+}
+
+type parquetRowGroup struct {
+	rows    int64
+	columns []parquetColumnChunk
+}
+
+// parquetReportWriter writes the records of a dataset to a parquet file
+type parquetReportWriter struct {
+	out     io.Writer
+	offset  int64
+	columns []string
+
+	values   []bytes.Buffer // plain encoded values of the buffered records, by column
+	buffered int            // bytes of values
+	rows     int64          // buffered records
+	groups   []parquetRowGroup
+}
+
+func newParquetReportWriter(columns []string, out io.Writer) (*parquetReportWriter, error) {
+	w := &parquetReportWriter{out: out, columns: columns, values: make([]bytes.Buffer, len(columns))}
+	return w, w.writeRaw([]byte(parquetMagic))
+}
+
+func (w *parquetReportWriter) writeRaw(b []byte) error {
+	n, err := w.out.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *parquetReportWriter) write(record []string) error {
+	var size [4]byte
+	for i, value := range record {
+		binary.LittleEndian.PutUint32(size[:], uint32(len(value)))
+		w.values[i].Write(size[:])
+		w.values[i].WriteString(value)
+		w.buffered += len(size) + len(value)
+	}
+	w.rows++
+	if w.rows >= parquetRowGroupRows || w.buffered >= parquetRowGroupBytes {
+		return w.writeRowGroup()
+	}
+	return nil
+}
+
+// writeRowGroup writes the buffered records as a row group of one data page per column
+func (w *parquetReportWriter) writeRowGroup() error {
+	if w.rows == 0 {
+		return nil
+	}
+	group := parquetRowGroup{rows: w.rows}
+	for i := range w.values {
+		values := w.values[i].Bytes()
+		var header thriftCompactWriter
+		header.i32(1, parquetPageData)
+		header.i32(2, int32(len(values)))
+		header.i32(3, int32(len(values)))
+		header.beginStruct(5)
+		header.i32(1, int32(w.rows))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.endStruct()
+		header.stop()
+
+		chunk := parquetColumnChunk{offset: w.offset, size: int64(len(header.buf) + len(values)), values: w.rows}
+		if err := w.writeRaw(header.buf); err != nil {
+			return err
+		}
+		if err := w.writeRaw(values); err != nil {
+			return err
+		}
+		group.columns = append(group.columns, chunk)
+		w.values[i].Reset()
+	}
+	w.groups = append(w.groups, group)
+	w.buffered, w.rows = 0, 0
+	return nil
+}
+
+// flush writes the buffered records and the footer of the file
+func (w *parquetReportWriter) flush() error {
+	if err := w.writeRowGroup(); err != nil {
+		return err
+	}
+	var numRows int64
+	for _, group := range w.groups {
+		numRows += group.rows
+	}
+	var meta thriftCompactWriter
+	meta.i32(1, 1) // version
+	meta.beginList(2, thriftStruct, len(w.columns)+1)
+	meta.beginElement()
+	meta.binary(4, []byte("schema"))
+	meta.i32(5, int32(len(w.columns)))
+	meta.endStruct()
+	for _, column := range w.columns {
+		meta.beginElement()
+		meta.i32(1, parquetTypeByteArray)
+		meta.i32(3, parquetRepetitionReq)
+		meta.binary(4, []byte(column))
+		meta.i32(6, parquetConvertedUTF8)
+		meta.endStruct()
+	}
+	meta.i64(3, numRows)
+	meta.beginList(4, thriftStruct, len(w.groups))
+	for _, group := range w.groups {
+		var groupSize int64
+		meta.beginElement()
+		meta.beginList(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			meta.beginElement()
+			meta.i64(2, chunk.offset)
+			meta.beginStruct(3)
+			meta.i32(1, parquetTypeByteArray)
+			meta.beginList(2, thriftI32, 1)
+			meta.varint(parquetEncodingPlain)
+			meta.beginList(3, thriftBinary, 1)
+			meta.bytes([]byte(w.columns[i]))
+			meta.i32(4, parquetCodecUncompressed)
+			meta.i64(5, chunk.values)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.endStruct()
+			meta.endStruct()
+			groupSize += chunk.size
+		}
+		meta.i64(2, groupSize)
+		meta.i64(3, group.rows)
+		meta.endStruct()
+	}
+	meta.binary(6, []byte("quorum export-report"))
+	meta.stop()
+
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(meta.buf)))
+	for _, b := range [][]byte{meta.buf, size[:], []byte(parquetMagic)} {
+		if err := w.writeRaw(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftCompactWriter encodes the parquet metadata with the thrift compact protocol
+type thriftCompactWriter struct {
+	buf    []byte
+	lastID int16   // of the last field of the current struct
+	outer  []int16 // last field ids of the enclosing structs
+}
+
+func (w *thriftCompactWriter) varint(v int64) {
+	u := uint64((v << 1) ^ (v >> 63)) // zigzag
+	w.uvarint(u)
+}
+
+func (w *thriftCompactWriter) uvarint(u uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf = append(w.buf, b[:binary.PutUvarint(b[:], u)]...)
+}
+
+func (w *thriftCompactWriter) bytes(b []byte) {
+	w.uvarint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *thriftCompactWriter) field(id int16, typ byte) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *thriftCompactWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftCompactWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftCompactWriter) binary(id int16, b []byte) {
+	w.field(id, thriftBinary)
+	w.bytes(b)
+}
+
+// beginList writes the header of a list field, followed by its size elements
+func (w *thriftCompactWriter) beginList(id int16, elemType byte, size int) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.uvarint(uint64(size))
+	}
+}
+
+// beginStruct starts a struct field, ended by endStruct
+func (w *thriftCompactWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.beginElement()
+}
+
+// beginElement starts a struct element of a list, ended by endStruct
+func (w *thriftCompactWriter) beginElement() {
+	w.outer = append(w.outer, w.lastID)
+	w.lastID = 0
+}
+
+func (w *thriftCompactWriter) endStruct() {
+	w.stop()
+	w.lastID = w.outer[len(w.outer)-1]
+	w.outer = w.outer[:len(w.outer)-1]
+}
+
+// stop ends the top level struct
+func (w *thriftCompactWriter) stop() {
+	w.buf = append(w.buf, 0)
+}
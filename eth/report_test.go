@@ -0,0 +1,249 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportTestChain is a chain of blocks 10 seconds apart, each with a public and a
+// private transaction
+type reportTestChain struct {
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+}
+
+func newReportTestChain(t *testing.T, blocks int) *reportTestChain {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.NewEIP155Signer(params.QuorumTestChainConfig.ChainID)
+	c := &reportTestChain{receipts: make(map[common.Hash]types.Receipts)}
+	for i := 0; i < blocks; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Time: uint64(1000 + 10*i)}
+		if i == 0 {
+			c.blocks = append(c.blocks, types.NewBlock(header, nil, nil, nil, new(trie.Trie)))
+			continue
+		}
+		publicTx, err := types.SignTx(types.NewTransaction(uint64(2*i), common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), []byte{0xca, 0xfe}), signer, key)
+		require.NoError(t, err)
+		privateTx := types.NewTransaction(uint64(2*i+1), common.Address{2}, big.NewInt(0), 50000, big.NewInt(0), nil)
+		privateTx.SetPrivate()
+		privateTx, err = types.SignTx(privateTx, types.QuorumPrivateTxSigner{}, key)
+		require.NoError(t, err)
+		block := types.NewBlock(header, []*types.Transaction{publicTx, privateTx}, nil, nil, new(trie.Trie))
+		privateReceipt := &types.Receipt{
+			Status:  types.ReceiptStatusSuccessful,
+			GasUsed: 30000,
+			Logs:    []*types.Log{{Address: common.Address{2}, Topics: []common.Hash{{3}}, Data: []byte{4}}},
+		}
+		c.receipts[block.Hash()] = types.Receipts{
+			{Status: types.ReceiptStatusSuccessful, GasUsed: 21000},
+			{PSReceipts: map[types.PrivateStateIdentifier]*types.Receipt{"PS1": privateReceipt}},
+		}
+		c.blocks = append(c.blocks, block)
+	}
+	return c
+}
+
+func (c *reportTestChain) Config() *params.ChainConfig { return params.QuorumTestChainConfig }
+
+func (c *reportTestChain) CurrentBlock() *types.Block { return c.blocks[len(c.blocks)-1] }
+
+func (c *reportTestChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.blocks[number].Header()
+}
+
+func (c *reportTestChain) GetBlockByNumber(number uint64) *types.Block { return c.blocks[number] }
+
+func (c *reportTestChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	return c.receipts[hash]
+}
+
+func exportTestReport(t *testing.T, chain ReportChain, psi types.PrivateStateIdentifier, query *ReportQuery) (*ReportSummary, map[string]string) {
+	buffers := make(map[string]*bytes.Buffer)
+	outputs := make(map[string]io.Writer)
+	for _, dataset := range query.DatasetNames() {
+		buffers[dataset] = new(bytes.Buffer)
+		outputs[dataset] = buffers[dataset]
+	}
+	summary, err := ExportReport(chain, false, psi, query, outputs)
+	require.NoError(t, err)
+	data := make(map[string]string)
+	for dataset, buf := range buffers {
+		data[dataset] = buf.String()
+	}
+	return summary, data
+}
+
+func TestExportReport_csv(t *testing.T) {
+	chain := newReportTestChain(t, 4)
+	from, to := rpc.BlockNumber(1), rpc.BlockNumber(2)
+
+	summary, data := exportTestReport(t, chain, "PS1", &ReportQuery{FromBlock: &from, ToBlock: &to})
+
+	assert.Equal(t, &ReportSummary{FromBlock: 1, ToBlock: 2, Records: map[string]int{
+		ReportTransactions: 4,
+		ReportReceipts:     4,
+		ReportEvents:       2,
+	}}, summary)
+	lines := strings.Split(strings.TrimSpace(data[ReportTransactions]), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, strings.Join(reportColumns[ReportTransactions], ","), lines[0])
+	assert.Contains(t, lines[1], ",0xcafe,false")
+	assert.True(t, strings.HasSuffix(lines[2], ",true"))
+	assert.Contains(t, data[ReportReceipts], ",1,30000,0,,1,,true", "the private receipt is the one of the private state")
+	assert.Contains(t, data[ReportEvents], common.Hash{3}.Hex())
+}
+
+func TestExportReport_whenOtherPrivateState(t *testing.T) {
+	chain := newReportTestChain(t, 2)
+
+	summary, _ := exportTestReport(t, chain, "PS2", &ReportQuery{})
+
+	assert.Equal(t, 0, summary.Records[ReportEvents], "the events of the private transactions are the ones of PS2")
+}
+
+func TestExportReport_redact(t *testing.T) {
+	chain := newReportTestChain(t, 2)
+	from := rpc.BlockNumber(1)
+
+	_, data := exportTestReport(t, chain, "PS1", &ReportQuery{
+		FromBlock: &from,
+		Datasets:  []string{ReportTransactions},
+		Format:    ReportFormatJSONL,
+		Redact:    []string{"input", "to:hash"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(data[ReportTransactions]), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"input":"REDACTED"`)
+	assert.Contains(t, lines[0], crypto.Keccak256Hash([]byte(common.Address{1}.Hex())).Hex())
+	assert.NotContains(t, lines[0], common.Address{1}.Hex())
+	assert.Contains(t, lines[1], `"input":"REDACTED"`)
+}
+
+func TestExportReport_parquet(t *testing.T) {
+	chain := newReportTestChain(t, 3)
+	from := rpc.BlockNumber(1)
+
+	summary, data := exportTestReport(t, chain, "PS1", &ReportQuery{
+		FromBlock: &from,
+		Datasets:  []string{ReportTransactions},
+		Format:    ReportFormatParquet,
+	})
+
+	assert.Equal(t, 4, summary.Records[ReportTransactions])
+	file := []byte(data[ReportTransactions])
+	require.True(t, len(file) > 12)
+	assert.Equal(t, parquetMagic, string(file[:4]))
+	assert.Equal(t, parquetMagic, string(file[len(file)-4:]))
+	footerSize := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	require.True(t, footerSize < len(file)-12)
+	footer := file[len(file)-8-footerSize : len(file)-8]
+	for _, column := range reportColumns[ReportTransactions] {
+		assert.Contains(t, string(footer), column)
+	}
+	// the values are plain encoded, prefixed by their length
+	assert.Contains(t, string(file), "\x06\x00\x00\x000xcafe")
+	assert.Contains(t, string(file), "\x04\x00\x00\x00true")
+}
+
+func TestExportReport_whenInvalidSender(t *testing.T) {
+	chain := newReportTestChain(t, 2)
+	block := types.NewBlock(chain.blocks[1].Header(), []*types.Transaction{types.NewTransaction(0, common.Address{1}, big.NewInt(0), 21000, big.NewInt(0), nil)}, nil, nil, new(trie.Trie))
+	chain.blocks[1] = block
+	chain.receipts[block.Hash()] = types.Receipts{{Status: types.ReceiptStatusSuccessful}}
+
+	_, err := ExportReport(chain, false, "PS1", &ReportQuery{}, map[string]io.Writer{
+		ReportTransactions: ioutil.Discard,
+		ReportReceipts:     ioutil.Discard,
+		ReportEvents:       ioutil.Discard,
+	})
+
+	assert.Error(t, err, "the transaction has no valid signature")
+}
+
+func TestReportChunkWriter(t *testing.T) {
+	var chunks []*ReportChunk
+	w := &reportChunkWriter{dataset: ReportEvents, notify: func(chunk *ReportChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}}
+	data := bytes.Repeat([]byte{1}, 2*reportChunkSize+10)
+
+	n, err := w.Write(data[:reportChunkSize+5])
+	require.NoError(t, err)
+	assert.Equal(t, reportChunkSize+5, n)
+	_, err = w.Write(data[reportChunkSize+5:])
+	require.NoError(t, err)
+	require.NoError(t, w.flush())
+
+	require.Len(t, chunks, 3)
+	var streamed []byte
+	for _, chunk := range chunks {
+		assert.Equal(t, ReportEvents, chunk.Dataset)
+		streamed = append(streamed, chunk.Data...)
+	}
+	assert.Len(t, chunks[2].Data, 10)
+	assert.Equal(t, data, streamed)
+}
+
+func TestReportQuery_blockRange_byTime(t *testing.T) {
+	chain := newReportTestChain(t, 6)
+	fromTime, toTime := time.Unix(1015, 0), time.Unix(1040, 0)
+
+	from, to, err := (&ReportQuery{FromTime: &fromTime, ToTime: &toTime}).blockRange(chain, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), from)
+	assert.Equal(t, uint64(4), to)
+
+	beforeGenesis := time.Unix(900, 0)
+	_, _, err = (&ReportQuery{ToTime: &beforeGenesis}).blockRange(chain, false)
+	assert.Error(t, err)
+}
+
+func TestReportQuery_Validate(t *testing.T) {
+	n := rpc.BlockNumber(1)
+	now := time.Now()
+	for _, query := range []*ReportQuery{
+		{FromBlock: &n, FromTime: &now},
+		{ToBlock: &n, ToTime: &now},
+		{Datasets: []string{"balances"}},
+		{Format: "xml"},
+		{Redact: []string{"password"}},
+	} {
+		assert.Error(t, query.Validate(), "query %+v", query)
+	}
+	assert.NoError(t, (&ReportQuery{Redact: []string{"from:hash", "data"}}).Validate())
+}
@@ -0,0 +1,301 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Quorum
+
+// maxResendFailures is the number of failed payloads reported by the progress of a
+// resend, the others are only counted
+const maxResendFailures = 100
+
+var (
+	errResendRunning     = errors.New("a resend of private payloads is already running")
+	errResendUnsupported = errors.New("the private transaction manager doesn't support resending payloads")
+)
+
+// ResendQuery selects the private transactions whose payloads are resent
+type ResendQuery struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock"` // genesis if not set
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`   // head if not set
+	// ContractAddresses restrict the resend to the transactions creating or calling
+	// the contracts, no restriction if empty
+	ContractAddresses []common.Address `json:"contractAddresses,omitempty"`
+}
+
+// ResendFailure is a payload which couldn't be resent
+type ResendFailure struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	TxHash      common.Hash `json:"txHash"`
+	Error       string      `json:"error"`
+}
+
+// ResendProgress reports the progress of a resend of private payloads to a party
+type ResendProgress struct {
+	Target       string          `json:"target"`
+	FromBlock    uint64          `json:"fromBlock"`
+	ToBlock      uint64          `json:"toBlock"`
+	CurrentBlock uint64          `json:"currentBlock"` // last block whose payloads were resent
+	Transactions int             `json:"transactions"` // private transactions selected
+	Resent       int             `json:"resent"`
+	Skipped      int             `json:"skipped"` // the target is not a party
+	Failed       int             `json:"failed"`
+	Failures     []ResendFailure `json:"failures,omitempty"`
+	StartedAt    time.Time       `json:"startedAt"`
+	Done         bool            `json:"done"`
+	Error        string          `json:"error,omitempty"` // why the resend stopped before the last block
+}
+
+// ResendPTM is the private transaction manager resending the payloads
+type ResendPTM interface {
+	private.PayloadResender
+	GetParticipants(txHash common.EncryptedPayloadHash) ([]string, error)
+}
+
+// payloadResend is a resend of private payloads running in the background
+type payloadResend struct {
+	mu       sync.Mutex
+	progress ResendProgress
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+// newPayloadResend resolves the block range of a resend
+func newPayloadResend(chain ReportChain, target string, query *ResendQuery) (*payloadResend, error) {
+	from, to, err := (&ReportQuery{FromBlock: query.FromBlock, ToBlock: query.ToBlock}).blockRange(chain, false)
+	if err != nil {
+		return nil, err
+	}
+	return &payloadResend{
+		progress: ResendProgress{Target: target, FromBlock: from, ToBlock: to, StartedAt: time.Now()},
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Progress returns a copy of the progress of the resend
+func (r *payloadResend) Progress() *ResendProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	progress := r.progress
+	progress.Failures = append([]ResendFailure(nil), r.progress.Failures...)
+	return &progress
+}
+
+// stop aborts the resend and waits for it to return
+func (r *payloadResend) stop() {
+	select {
+	case <-r.quit:
+	default:
+		close(r.quit)
+	}
+	<-r.done
+}
+
+// run resends the payloads of the private transactions of the block range whose
+// parties include the target. The payloads whose parties the private transaction
+// manager can't tell are resent, it rejects the ones the target is not a party of.
+func (r *payloadResend) run(chain ReportChain, ptm ResendPTM, contracts []common.Address) {
+	defer close(r.done)
+
+	var selected map[common.Address]bool
+	if len(contracts) > 0 {
+		selected = make(map[common.Address]bool, len(contracts))
+		for _, address := range contracts {
+			selected[address] = true
+		}
+	}
+	target, from, to := r.progress.Target, r.progress.FromBlock, r.progress.ToBlock
+	logged := time.Now()
+	for number := from; number <= to; number++ {
+		select {
+		case <-r.quit:
+			r.finish(errors.New("aborted"))
+			return
+		default:
+		}
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			r.finish(fmt.Errorf("block #%d not found", number))
+			return
+		}
+		for _, tx := range block.Transactions() {
+			if !tx.IsPrivate() || (selected != nil && !selected[resendContractAddress(tx)]) {
+				continue
+			}
+			hash := common.BytesToEncryptedPayloadHash(tx.Data())
+			if common.EmptyEncryptedPayloadHash(hash) {
+				continue
+			}
+			r.resend(ptm, target, number, tx.Hash(), hash)
+		}
+		r.mu.Lock()
+		r.progress.CurrentBlock = number
+		r.mu.Unlock()
+		if time.Since(logged) > 8*time.Second {
+			progress := r.Progress()
+			log.Info("Resending private payloads", "target", target, "block", number, "to", to, "resent", progress.Resent, "failed", progress.Failed)
+			logged = time.Now()
+		}
+	}
+	r.finish(nil)
+}
+
+// resend resends the payload of a private transaction, unless the target is not one
+// of its parties
+func (r *payloadResend) resend(ptm ResendPTM, target string, number uint64, txHash common.Hash, hash common.EncryptedPayloadHash) {
+	isParty := true
+	if parties, err := ptm.GetParticipants(hash); err == nil {
+		isParty = false
+		for _, party := range parties {
+			if party == target {
+				isParty = true
+				break
+			}
+		}
+	}
+	var err error
+	if isParty {
+		err = ptm.Resend(hash, target)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress.Transactions++
+	switch {
+	case !isParty:
+		r.progress.Skipped++
+	case err != nil:
+		r.progress.Failed++
+		if len(r.progress.Failures) < maxResendFailures {
+			r.progress.Failures = append(r.progress.Failures, ResendFailure{BlockNumber: number, TxHash: txHash, Error: err.Error()})
+		}
+		log.Warn("Failed to resend private payload", "target", target, "block", number, "tx", txHash, "err", err)
+	default:
+		r.progress.Resent++
+	}
+}
+
+func (r *payloadResend) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress.Done = true
+	if err != nil {
+		r.progress.Error = err.Error()
+	}
+	log.Info("Resend of private payloads finished", "target", r.progress.Target, "block", r.progress.CurrentBlock,
+		"resent", r.progress.Resent, "skipped", r.progress.Skipped, "failed", r.progress.Failed, "err", err)
+}
+
+// resendContractAddress returns the address of the contract a private transaction
+// creates or calls
+func resendContractAddress(tx *types.Transaction) common.Address {
+	if to := tx.To(); to != nil {
+		return *to
+	}
+	from, _ := types.Sender(types.HomesteadSigner{}, tx)
+	return crypto.CreateAddress(from, tx.Nonce())
+}
+
+// startPayloadResend starts a resend in the background, unless one is running
+func (s *Ethereum) startPayloadResend(ptm ResendPTM, target string, query *ResendQuery) (*ResendProgress, error) {
+	s.resendMu.Lock()
+	defer s.resendMu.Unlock()
+
+	if s.payloadResend != nil && !s.payloadResend.Progress().Done {
+		return nil, errResendRunning
+	}
+	resend, err := newPayloadResend(s.blockchain, target, query)
+	if err != nil {
+		return nil, err
+	}
+	s.payloadResend = resend
+	log.Info("Resending private payloads", "target", target, "from", resend.progress.FromBlock, "to", resend.progress.ToBlock, "contracts", len(query.ContractAddresses))
+	go resend.run(s.blockchain, ptm, query.ContractAddresses)
+	return resend.Progress(), nil
+}
+
+// stopPayloadResend aborts the running resend, if any, and returns whether one was running
+func (s *Ethereum) stopPayloadResend() bool {
+	s.resendMu.Lock()
+	resend := s.payloadResend
+	s.resendMu.Unlock()
+
+	if resend == nil || resend.Progress().Done {
+		return false
+	}
+	resend.stop()
+	return true
+}
+
+// PrivateQuorumResendAPI orchestrates the resend of private payloads to a party
+// recovering from the loss of its private transaction manager data
+type PrivateQuorumResendAPI struct {
+	e *Ethereum
+}
+
+// NewPrivateQuorumResendAPI creates a new PrivateQuorumResendAPI
+func NewPrivateQuorumResendAPI(e *Ethereum) *PrivateQuorumResendAPI {
+	return &PrivateQuorumResendAPI{e: e}
+}
+
+// ResendPrivatePayloads starts resending to the target TM key the payloads of the
+// private transactions of a block range, optionally restricted to some contracts.
+// The resend runs in the background, see PrivatePayloadsResendStatus.
+func (api *PrivateQuorumResendAPI) ResendPrivatePayloads(target string, query ResendQuery) (*ResendProgress, error) {
+	if !private.IsQuorumPrivacyEnabled() || private.P == nil {
+		return nil, errors.New("private transaction manager is not enabled")
+	}
+	ptm, ok := private.P.(ResendPTM)
+	if !ok {
+		return nil, errResendUnsupported
+	}
+	if target = private.ResolveKey(target); target == "" {
+		return nil, errors.New("missing target TM key")
+	}
+	return api.e.startPayloadResend(ptm, target, &query)
+}
+
+// PrivatePayloadsResendStatus returns the progress of the running or last resend, nil
+// if there was none
+func (api *PrivateQuorumResendAPI) PrivatePayloadsResendStatus() *ResendProgress {
+	api.e.resendMu.Lock()
+	defer api.e.resendMu.Unlock()
+	if api.e.payloadResend == nil {
+		return nil
+	}
+	return api.e.payloadResend.Progress()
+}
+
+// CancelResendPrivatePayloads aborts the running resend and returns whether one was running
+func (api *PrivateQuorumResendAPI) CancelResendPrivatePayloads() bool {
+	return api.e.stopPayloadResend()
+}
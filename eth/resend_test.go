@@ -0,0 +1,113 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubResendPTM struct {
+	participants map[common.EncryptedPayloadHash][]string
+	failing      map[common.EncryptedPayloadHash]bool
+	resent       []common.EncryptedPayloadHash
+}
+
+func (ptm *stubResendPTM) GetParticipants(hash common.EncryptedPayloadHash) ([]string, error) {
+	parties, ok := ptm.participants[hash]
+	if !ok {
+		return nil, errors.New("not the sender")
+	}
+	return parties, nil
+}
+
+func (ptm *stubResendPTM) Resend(hash common.EncryptedPayloadHash, to string) error {
+	if ptm.failing[hash] {
+		return errors.New("unknown payload")
+	}
+	ptm.resent = append(ptm.resent, hash)
+	return nil
+}
+
+// newResendTestChain returns a chain with a private transaction per block, calling
+// the contract of the same index
+func newResendTestChain(t *testing.T, contracts ...common.Address) (*reportTestChain, []common.EncryptedPayloadHash) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := &reportTestChain{blocks: []*types.Block{types.NewBlock(&types.Header{Number: big.NewInt(0)}, nil, nil, nil, new(trie.Trie))}}
+	var hashes []common.EncryptedPayloadHash
+	for i, contract := range contracts {
+		hash := common.BytesToEncryptedPayloadHash([]byte{byte(i + 1)})
+		tx := types.NewTransaction(uint64(i), contract, big.NewInt(0), 50000, big.NewInt(0), hash.Bytes())
+		tx.SetPrivate()
+		tx, err = types.SignTx(tx, types.QuorumPrivateTxSigner{}, key)
+		require.NoError(t, err)
+		header := &types.Header{Number: big.NewInt(int64(i + 1))}
+		chain.blocks = append(chain.blocks, types.NewBlock(header, []*types.Transaction{tx}, nil, nil, new(trie.Trie)))
+		hashes = append(hashes, hash)
+	}
+	return chain, hashes
+}
+
+func TestPayloadResend(t *testing.T) {
+	chain, hashes := newResendTestChain(t, common.Address{1}, common.Address{2}, common.Address{1}, common.Address{1})
+	ptm := &stubResendPTM{
+		participants: map[common.EncryptedPayloadHash][]string{
+			hashes[0]: {"A", "target"},
+			hashes[2]: {"A"},
+		},
+		failing: map[common.EncryptedPayloadHash]bool{hashes[3]: true},
+	}
+	resend, err := newPayloadResend(chain, "target", &ResendQuery{})
+	require.NoError(t, err)
+
+	resend.run(chain, ptm, []common.Address{{1}})
+
+	assert.Equal(t, []common.EncryptedPayloadHash{hashes[0]}, ptm.resent)
+	progress := resend.Progress()
+	assert.True(t, progress.Done)
+	assert.Empty(t, progress.Error)
+	assert.Equal(t, uint64(4), progress.CurrentBlock)
+	assert.Equal(t, 3, progress.Transactions, "the transaction of the other contract is not selected")
+	assert.Equal(t, 1, progress.Resent)
+	assert.Equal(t, 1, progress.Skipped, "the target is not a party")
+	assert.Equal(t, 1, progress.Failed)
+	require.Len(t, progress.Failures, 1)
+	assert.Equal(t, uint64(4), progress.Failures[0].BlockNumber)
+}
+
+func TestPayloadResend_stop(t *testing.T) {
+	chain, _ := newResendTestChain(t, common.Address{1})
+	resend, err := newPayloadResend(chain, "target", &ResendQuery{})
+	require.NoError(t, err)
+	close(resend.quit)
+
+	resend.run(chain, &stubResendPTM{}, nil)
+
+	progress := resend.Progress()
+	assert.True(t, progress.Done)
+	assert.Equal(t, "aborted", progress.Error)
+	assert.Zero(t, progress.Transactions)
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Quorum
+
+// serveThrottle limits the chain data served to the peers syncing from the node, so that
+// the peers catching up (e.g. raft peers joining the cluster) don't degrade the block
+// production when several of them sync at once.
+type serveThrottle struct {
+	slots   chan struct{} // nil unless the number of requests served at once is limited
+	limiter *rate.Limiter // nil unless the bandwidth is capped
+}
+
+func newServeThrottle(maxPeers, bandwidth int) *serveThrottle {
+	t := &serveThrottle{}
+	if maxPeers > 0 {
+		t.slots = make(chan struct{}, maxPeers)
+	}
+	if bandwidth > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(bandwidth), bandwidth)
+	}
+	return t
+}
+
+// acquire waits for a slot to serve a request of a peer, it returns false if quit is
+// closed first. The slot must be released once the response is sent.
+func (t *serveThrottle) acquire(quit <-chan struct{}) bool {
+	if t == nil || t.slots == nil {
+		return true
+	}
+	select {
+	case t.slots <- struct{}{}:
+		return true
+	case <-quit:
+		return false
+	}
+}
+
+func (t *serveThrottle) release() {
+	if t == nil || t.slots == nil {
+		return
+	}
+	<-t.slots
+}
+
+// wait waits until a response of size bytes may be sent within the bandwidth, it returns
+// false if quit is closed first. The responses larger than the burst are paced in chunks.
+func (t *serveThrottle) wait(size int, quit <-chan struct{}) bool {
+	if t == nil || t.limiter == nil {
+		return true
+	}
+	for size > 0 {
+		n := size
+		if n > t.limiter.Burst() {
+			n = t.limiter.Burst()
+		}
+		r := t.limiter.ReserveN(time.Now(), n)
+		select {
+		case <-time.After(r.Delay()):
+		case <-quit:
+			r.Cancel()
+			return false
+		}
+		size -= n
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeThrottle_limitsConcurrentRequests(t *testing.T) {
+	throttle := newServeThrottle(1, 0)
+	quit := make(chan struct{})
+	assert.True(t, throttle.acquire(quit))
+
+	acquired := make(chan bool)
+	go func() { acquired <- throttle.acquire(quit) }()
+	select {
+	case <-acquired:
+		t.Fatal("slot acquired beyond the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	throttle.release()
+	assert.True(t, <-acquired)
+}
+
+func TestServeThrottle_capsBandwidth(t *testing.T) {
+	throttle := newServeThrottle(0, 100000)
+	quit := make(chan struct{})
+
+	start := time.Now()
+	// the burst is sent at once, the remaining 50000 bytes within the bandwidth
+	assert.True(t, throttle.wait(150000, quit))
+
+	assert.True(t, time.Since(start) >= 400*time.Millisecond, "response larger than the burst not paced")
+}
+
+func TestServeThrottle_whenQuit(t *testing.T) {
+	throttle := newServeThrottle(1, 1000)
+	quit := make(chan struct{})
+	assert.True(t, throttle.acquire(quit))
+	close(quit)
+
+	assert.False(t, throttle.acquire(quit))
+	assert.False(t, throttle.wait(5000, quit))
+}
+
+func TestServeThrottle_whenNotThrottled(t *testing.T) {
+	var throttle *serveThrottle
+
+	assert.True(t, throttle.acquire(nil))
+	assert.True(t, throttle.wait(1<<30, nil))
+	throttle.release()
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
@@ -61,6 +62,22 @@ func TestBuildSchema(t *testing.T) {
 	}
 }
 
+func TestNew_whenHTTPServerDisabled(t *testing.T) {
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer stack.Close()
+	ethBackend, err := eth.New(stack, &eth.DefaultConfig)
+	if err != nil {
+		t.Fatalf("could not create eth backend: %v", err)
+	}
+
+	err = New(stack, ethBackend.APIBackend, []string{}, []string{})
+
+	assert.EqualError(t, err, "GraphQL requires the HTTP-RPC server to be enabled (--http)")
+}
+
 // Tests that a graphQL request is successfully handled when graphql is enabled on the specified endpoint
 func TestGraphQLHTTPOnSamePort_GQLRequest_Successful(t *testing.T) {
 	stack := createNode(t, true)
@@ -249,6 +266,10 @@ func (sb *StubBackend) SupportsMultitenancy(rpcCtx context.Context) (*proto.PreA
 	panic("implement me")
 }
 
+func (sb *StubBackend) AsyncCallbackOutbox() *ethapi.AsyncCallbackOutbox {
+	panic("implement me")
+}
+
 func (sb *StubBackend) AccountExtraDataStateGetterByNumber(context.Context, rpc.BlockNumber) (vm.AccountExtraDataStateGetter, error) {
 	panic("implement me")
 }
@@ -257,7 +278,7 @@ func (sb *StubBackend) IsAuthorized(authToken *proto.PreAuthenticatedAuthenticat
 	panic("implement me")
 }
 
-func (sb *StubBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header) (*vm.EVM, func() error, error) {
+func (sb *StubBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
 	panic("implement me")
 }
 
@@ -17,6 +17,7 @@
 package graphql
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/ethereum/go-ethereum/eth"
@@ -34,6 +35,12 @@ func New(stack *node.Node, backend ethapi.Backend, cors, vhosts []string) error
 	if backend == nil {
 		panic("missing backend")
 	}
+	// Quorum
+	// GraphQL is served by the HTTP-RPC server so it's guarded by the same security stack as JSON-RPC,
+	// fail early rather than silently not serving it
+	if stack.Config().HTTPHost == "" {
+		return errors.New("GraphQL requires the HTTP-RPC server to be enabled (--http)")
+	}
 	// check if http server with given endpoint exists and enable graphQL on it
 	return newHandler(stack, backend, cors, vhosts)
 }
@@ -68,7 +75,7 @@ func newHandler(stack *node.Node, backend ethapi.Backend, cors, vhosts []string)
 		authManagerFunc: authManagerFunc,
 		isMultitenant:   stack.Config().EnableMultitenancy,
 		protectedMethod: "graphql_*", // this follows JSON RPC convention using namespace graphql
-		delegate:        node.NewHTTPHandlerStack(h, cors, vhosts),
+		delegate:        node.NewHTTPHandlerStack(h, cors, vhosts, stack.Config().HTTPGzipMinSize),
 	}
 	// need to obtain eth service in order to know if MPS is enabled
 	isMPS := false
@@ -149,6 +149,8 @@ type FilterQuery struct {
 	// {{A, B}, {C, D}}   matches topic (A OR B) in first position AND (C OR D) in second position
 	Topics [][]common.Hash
 	PSI    types.PrivateStateIdentifier
+	// Quorum: keep the filter installed with eth_newFilter across node restarts
+	Persistent bool
 }
 
 // LogFilterer provides access to contract log events using a one-off query or continuous
@@ -0,0 +1,76 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package catchup runs the tasks catching up with the head of the chain, such as the
+// publication or the relay of the new blocks, retrying them after a failure.
+package catchup
+
+import (
+	"context"
+	"time"
+)
+
+// Loop runs a task at start, each time it is woken up, e.g. by a new head, and again
+// after a delay while it fails
+type Loop struct {
+	wake          chan struct{}
+	retryInterval time.Duration
+}
+
+// NewLoop creates a loop running its task again retryInterval after a failure
+func NewLoop(retryInterval time.Duration) *Loop {
+	return &Loop{
+		wake:          make(chan struct{}, 1),
+		retryInterval: retryInterval,
+	}
+}
+
+// Wake runs the task again without blocking the caller, the wake-ups received while the
+// task runs being coalesced into a single run
+func (l *Loop) Wake() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs the task until ctx is canceled, the failures being reported to onError
+func (l *Loop) Run(ctx context.Context, task func() error, onError func(err error)) {
+	// catch up with the head at once
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+	for {
+		select {
+		case <-l.wake:
+		case <-retry.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := task(); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			onError(err)
+			if !retry.Stop() {
+				select {
+				case <-retry.C:
+				default:
+				}
+			}
+			retry.Reset(l.retryInterval)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package catchup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoop_Run(t *testing.T) {
+	loop := NewLoop(10 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	runs, failures := make(chan int, 10), 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		count := 0
+		loop.Run(ctx, func() error {
+			count++
+			runs <- count
+			if count == 2 {
+				return errors.New("unavailable")
+			}
+			return nil
+		}, func(err error) {
+			failures++
+		})
+	}()
+
+	assert.Equal(t, 1, <-runs, "the task runs at start")
+	loop.Wake()
+	assert.Equal(t, 2, <-runs, "the task runs when woken up")
+	select {
+	case run := <-runs:
+		assert.Equal(t, 3, run, "the task runs again after a failure")
+	case <-time.After(time.Second):
+		t.Fatal("the task is not retried")
+	}
+	cancel()
+	<-done
+	assert.Equal(t, 1, failures)
+}
+
+func TestLoop_Wake_coalesces(t *testing.T) {
+	loop := NewLoop(time.Second)
+
+	loop.Wake()
+	loop.Wake()
+
+	assert.Len(t, loop.wake, 1)
+}
@@ -49,6 +49,10 @@ type HandlerT struct {
 	cpuFile   string
 	traceW    io.WriteCloser
 	traceFile string
+
+	// Quorum
+	vmodule      string             // vmodule pattern set with the CLI flag or Vmodule
+	moduleLevels map[string]log.Lvl // verbosity of the modules set with SetModuleLevel
 }
 
 // Verbosity sets the log verbosity ceiling. The verbosity of individual packages
@@ -59,8 +63,14 @@ func (*HandlerT) Verbosity(level int) {
 
 // Vmodule sets the log verbosity pattern. See package log for details on the
 // pattern syntax.
-func (*HandlerT) Vmodule(pattern string) error {
-	return glogger.Vmodule(pattern)
+func (h *HandlerT) Vmodule(pattern string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := glogger.Vmodule(h.moduleRules(pattern)); err != nil {
+		return err
+	}
+	h.vmodule = pattern
+	return nil
 }
 
 // BacktraceAt sets the log backtrace location. See package log for details on
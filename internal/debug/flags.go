@@ -55,6 +55,12 @@ var (
 		Name:  "debug",
 		Usage: "Prepends log messages with call-site location (file and line number)",
 	}
+	// Quorum
+	logJSONFlag = cli.BoolFlag{
+		Name:  "log.json",
+		Usage: "Format logs as JSON objects, with the module and the RPC request correlation fields",
+	}
+	// End Quorum
 	pprofFlag = cli.BoolFlag{
 		Name:  "pprof",
 		Usage: "Enable the pprof HTTP server",
@@ -114,7 +120,7 @@ var (
 
 // Flags holds all command-line flags required for debugging.
 var Flags = []cli.Flag{
-	verbosityFlag, vmoduleFlag, backtraceAtFlag, debugFlag,
+	verbosityFlag, vmoduleFlag, backtraceAtFlag, debugFlag, logJSONFlag,
 	pprofFlag, pprofAddrFlag, pprofPortFlag, memprofilerateFlag,
 	blockprofilerateFlag, cpuprofileFlag, traceFlag,
 }
@@ -144,8 +150,14 @@ func init() {
 func Setup(ctx *cli.Context) error {
 	// logging
 	log.PrintOrigins(ctx.GlobalBool(debugFlag.Name))
+	// Quorum
+	if ctx.GlobalBool(logJSONFlag.Name) {
+		ostream = log.ModuleHandler(log.StreamHandler(os.Stderr, log.JSONFormat()))
+		glogger.SetHandler(ostream)
+	}
+	// End Quorum
 	glogger.Verbosity(log.Lvl(ctx.GlobalInt(verbosityFlag.Name)))
-	glogger.Vmodule(ctx.GlobalString(vmoduleFlag.Name))
+	Handler.Vmodule(ctx.GlobalString(vmoduleFlag.Name))
 	glogger.BacktraceAt(ctx.GlobalString(backtraceAtFlag.Name))
 	log.Root().SetHandler(glogger)
 
@@ -0,0 +1,106 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package debug
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+
+// logModules maps the modules whose verbosity can be set by name to their vmodule
+// patterns. Other modules are given as package paths, e.g.: eth/downloader.
+var logModules = map[string][]string{
+	"consensus":  {"consensus/*", "raft/*"},
+	"permission": {"permission/*"},
+	"private":    {"private/*"},
+	"plugin":     {"plugin/*"},
+}
+
+// SetModuleLevel sets at runtime the log verbosity of a module: consensus, permission,
+// private, plugin or a package path. The level is either a name (trace, debug, info,
+// warn, error, crit) or a number as for the verbosity flag.
+//
+// The levels set for the modules take precedence over the vmodule pattern. As with the
+// vmodule pattern, they can only raise the verbosity above the global one.
+func (h *HandlerT) SetModuleLevel(module string, level string) error {
+	module = strings.TrimSpace(module)
+	if module == "" || strings.ContainsAny(module, ",=") {
+		return fmt.Errorf("invalid module %q", module)
+	}
+	lvl, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.moduleLevels == nil {
+		h.moduleLevels = make(map[string]log.Lvl)
+	}
+	previous, hadPrevious := h.moduleLevels[module]
+	h.moduleLevels[module] = lvl
+	if err := glogger.Vmodule(h.moduleRules(h.vmodule)); err != nil {
+		if hadPrevious {
+			h.moduleLevels[module] = previous
+		} else {
+			delete(h.moduleLevels, module)
+		}
+		return err
+	}
+	log.Info("Changed module log level", "module", module, "level", lvl)
+	return nil
+}
+
+// moduleRules returns the vmodule rules of the module levels followed by pattern,
+// h.mu must be held.
+func (h *HandlerT) moduleRules(pattern string) string {
+	modules := make([]string, 0, len(h.moduleLevels))
+	for module := range h.moduleLevels {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	var rules []string
+	for _, module := range modules {
+		patterns, ok := logModules[module]
+		if !ok {
+			patterns = []string{module}
+		}
+		for _, p := range patterns {
+			rules = append(rules, fmt.Sprintf("%s=%d", p, h.moduleLevels[module]))
+		}
+	}
+	if pattern != "" {
+		rules = append(rules, pattern)
+	}
+	return strings.Join(rules, ",")
+}
+
+func parseLogLevel(level string) (log.Lvl, error) {
+	if n, err := strconv.Atoi(level); err == nil {
+		if n < int(log.LvlCrit) || n > int(log.LvlTrace) {
+			return 0, fmt.Errorf("invalid level %d", n)
+		}
+		return log.Lvl(n), nil
+	}
+	return log.LvlFromString(strings.ToLower(level))
+}
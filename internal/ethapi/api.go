@@ -24,7 +24,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -47,13 +46,17 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
 	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/tracing"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/tyler-smith/go-bip39"
 )
 
@@ -62,6 +65,10 @@ const (
 	maxPrivateIntrinsicDataHex = "11111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111"
 )
 
+// ptmSendTimer measures the round trip to the privacy manager when the payloads
+// of the private transactions are sent.
+var ptmSendTimer = metrics.NewRegisteredTimer("txlatency/ptm/send", nil)
+
 type TransactionType uint8
 
 const (
@@ -119,38 +126,58 @@ func (s *PublicEthereumAPI) Syncing() (interface{}, error) {
 // PublicTxPoolAPI offers and API for the transaction pool. It only operates on data that is non confidential.
 type PublicTxPoolAPI struct {
 	b Backend
+	// Quorum: the payloads of the private pool transactions retrieved from the private
+	// transaction manager, by payload hash
+	payloads *lru.Cache
 }
 
 // NewPublicTxPoolAPI creates a new tx pool service that gives information about the transaction pool.
 func NewPublicTxPoolAPI(b Backend) *PublicTxPoolAPI {
-	return &PublicTxPoolAPI{b}
+	payloads, _ := lru.New(txPoolPayloadCacheLimit)
+	return &PublicTxPoolAPI{b: b, payloads: payloads}
 }
 
 // Content returns the transactions contained within the transaction pool.
-func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction {
+//
+// Quorum: the transactions can be filtered by private state and org. Under multitenancy,
+// the private transactions are only shown to the parties.
+func (s *PublicTxPoolAPI) Content(ctx context.Context, filter *TxPoolFilter) (map[string]map[string]map[string]*RPCTransaction, error) {
+	view, err := newTxPoolView(ctx, s.b, filter, s.payloads)
+	if err != nil {
+		return nil, err
+	}
 	content := map[string]map[string]map[string]*RPCTransaction{
 		"pending": make(map[string]map[string]*RPCTransaction),
 		"queued":  make(map[string]map[string]*RPCTransaction),
 	}
 	pending, queue := s.b.TxPoolContent()
 
-	// Flatten the pending transactions
-	for account, txs := range pending {
+	// Quorum: flatten the transactions shown to the caller, with their privacy metadata
+	flatten := func(account common.Address, txs types.Transactions) map[string]*RPCTransaction {
 		dump := make(map[string]*RPCTransaction)
+		if !view.showsAccount(account) {
+			return dump
+		}
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+			if shown, metadata := view.privacy(tx, true); shown {
+				dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx).withPrivacyMetadata(metadata)
+			}
+		}
+		return dump
+	}
+	// Flatten the pending transactions
+	for account, txs := range pending {
+		if dump := flatten(account, txs); len(dump) > 0 {
+			content["pending"][account.Hex()] = dump
 		}
-		content["pending"][account.Hex()] = dump
 	}
 	// Flatten the queued transactions
 	for account, txs := range queue {
-		dump := make(map[string]*RPCTransaction)
-		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+		if dump := flatten(account, txs); len(dump) > 0 {
+			content["queued"][account.Hex()] = dump
 		}
-		content["queued"][account.Hex()] = dump
 	}
-	return content
+	return content, nil
 }
 
 // Status returns the number of pending and queued transaction in the pool.
@@ -164,7 +191,13 @@ func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
-func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
+//
+// Quorum: the transactions are filtered as the ones of Content.
+func (s *PublicTxPoolAPI) Inspect(ctx context.Context, filter *TxPoolFilter) (map[string]map[string]map[string]string, error) {
+	view, err := newTxPoolView(ctx, s.b, filter, s.payloads)
+	if err != nil {
+		return nil, err
+	}
 	content := map[string]map[string]map[string]string{
 		"pending": make(map[string]map[string]string),
 		"queued":  make(map[string]map[string]string),
@@ -178,25 +211,153 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 		}
 		return fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", tx.Value(), tx.Gas(), tx.GasPrice())
 	}
-	// Flatten the pending transactions
-	for account, txs := range pending {
+	// Quorum: flatten the transactions shown to the caller
+	flatten := func(account common.Address, txs types.Transactions) map[string]string {
 		dump := make(map[string]string)
+		if !view.showsAccount(account) {
+			return dump
+		}
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+			if shown, _ := view.privacy(tx, false); shown {
+				dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+			}
+		}
+		return dump
+	}
+	// Flatten the pending transactions
+	for account, txs := range pending {
+		if dump := flatten(account, txs); len(dump) > 0 {
+			content["pending"][account.Hex()] = dump
 		}
-		content["pending"][account.Hex()] = dump
 	}
 	// Flatten the queued transactions
 	for account, txs := range queue {
-		dump := make(map[string]string)
-		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+		if dump := flatten(account, txs); len(dump) > 0 {
+			content["queued"][account.Hex()] = dump
+		}
+	}
+	return content, nil
+}
+
+// Quorum
+
+// txPoolPayloadCacheLimit is the number of payloads of private pool transactions
+// cached, so that the private transaction manager is not asked again for the payloads
+// of the transactions staying in the pool across the calls
+const txPoolPayloadCacheLimit = 4096
+
+// TxPoolFilter restricts the transactions of txpool_content and txpool_inspect
+type TxPoolFilter struct {
+	// PSI selects the public transactions and the private transactions the private
+	// state is party to. Under multitenancy, the private state must be granted to the
+	// caller and is the one of the caller by default.
+	PSI types.PrivateStateIdentifier `json:"psi,omitempty"`
+	// OrgID selects the transactions sent by the accounts of the org and of its sub
+	// orgs, the permissions must be enabled
+	OrgID string `json:"orgId,omitempty"`
+}
+
+// txPoolView selects the transactions of the pool shown to a caller
+type txPoolView struct {
+	psm *mps.PrivateStateMetadata
+	// restricted hides the private transactions psm is not party to
+	restricted bool
+	orgID      string
+	psmr       mps.PrivateStateMetadataResolver
+	payloads   *lru.Cache
+}
+
+// poolPayload is the part of the payload of a private pool transaction the view needs
+type poolPayload struct {
+	managedParties []string
+	extra          *engine.ExtraMetadata
+}
+
+func newTxPoolView(ctx context.Context, b Backend, filter *TxPoolFilter, payloads *lru.Cache) (*txPoolView, error) {
+	if filter == nil {
+		filter = new(TxPoolFilter)
+	}
+	if filter.OrgID != "" && !pcore.PermissionsEnabled() {
+		return nil, errors.New("the permissions are not enabled, the transactions can't be filtered by org")
+	}
+	token, multitenant := b.SupportsMultitenancy(ctx)
+	if filter.PSI != "" {
+		if multitenant {
+			authorized, err := multitenancy.IsPSIAuthorized(token, filter.PSI)
+			if err != nil {
+				return nil, err
+			}
+			if !authorized {
+				return nil, newPrivacyError(ErrCodeNotParty, fmt.Errorf("private state %s: %w", filter.PSI, multitenancy.ErrNotAuthorized))
+			}
 		}
-		content["queued"][account.Hex()] = dump
+		ctx = rpc.WithPrivateStateIdentifier(ctx, filter.PSI)
+	}
+	psm, err := b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &txPoolView{
+		psm:        psm,
+		restricted: multitenant || filter.PSI != "",
+		orgID:      filter.OrgID,
+		psmr:       b.PSMR(),
+		payloads:   payloads,
+	}, nil
+}
+
+// showsAccount returns whether the transactions sent by the account are shown
+func (v *txPoolView) showsAccount(account common.Address) bool {
+	if v.orgID == "" {
+		return true
 	}
-	return content
+	info, err := pcore.AcctInfoMap.GetAccount(account)
+	if err != nil || info == nil {
+		return false
+	}
+	return info.OrgId == v.orgID || strings.HasPrefix(info.OrgId, v.orgID+".")
 }
 
+// privacy returns whether the transaction is shown, with its privacy metadata if
+// withMetadata is set. The private transactions whose payload can't be retrieved are
+// only shown if the view is not restricted.
+func (v *txPoolView) privacy(tx *types.Transaction, withMetadata bool) (bool, *privacyMetadata) {
+	if !tx.IsPrivate() {
+		return true, &privacyMetadata{}
+	}
+	metadata := &privacyMetadata{isPrivate: true, payloadHash: common.BytesToEncryptedPayloadHash(tx.Data())}
+	if !v.restricted && !withMetadata {
+		return true, metadata
+	}
+	payload, err := v.payload(metadata.payloadHash)
+	if err != nil {
+		log.Debug("Unable to retrieve the private payload of a pool transaction", "hash", tx.Hash(), "err", err)
+		return !v.restricted, metadata
+	}
+	party := payload.extra != nil && !v.psmr.NotIncludeAny(v.psm, payload.managedParties...)
+	if party {
+		metadata.privacyFlag, metadata.psi = &payload.extra.PrivacyFlag, &v.psm.ID
+	}
+	return party || !v.restricted, metadata
+}
+
+// payload retrieves the payload of a private pool transaction from the private
+// transaction manager, once as long as it is cached
+func (v *txPoolView) payload(hash common.EncryptedPayloadHash) (*poolPayload, error) {
+	if cached, ok := v.payloads.Get(hash); ok {
+		return cached.(*poolPayload), nil
+	}
+	_, managedParties, _, extra, err := private.P.Receive(hash)
+	if err != nil {
+		return nil, err
+	}
+	payload := &poolPayload{managedParties: managedParties, extra: extra}
+	v.payloads.Add(hash, payload)
+	return payload, nil
+}
+
+// /Quorum
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -436,6 +597,9 @@ func (s *PrivateAccountAPI) signTransaction(ctx context.Context, args *SendTxArg
 	}
 	// /Quorum
 
+	if signer, ok := wallet.(contextSigner); ok {
+		return signer.SignTxWithPassphraseWithContext(args.withPrivateTxMetadata(ctx), account, passwd, tx, chainID)
+	}
 	return wallet.SignTxWithPassphrase(account, passwd, tx, chainID)
 }
 
@@ -462,6 +626,7 @@ func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args SendTxArgs
 	}
 	if isPrivate && !common.EmptyEncryptedPayloadHash(data) {
 		// replace the original payload with encrypted payload hash
+		args.payload = args.inputOrData()
 		args.Data = data.BytesTypeRef()
 	}
 	// /Quorum
@@ -524,7 +689,7 @@ func (s *PrivateAccountAPI) Sign(ctx context.Context, data hexutil.Bytes, addr c
 		return nil, err
 	}
 	// Assemble sign the data with the wallet
-	signature, err := wallet.SignTextWithPassphrase(account, passwd, data)
+	signature, err := signTextWithPassphrase(ctx, wallet, account, passwd, data)
 	if err != nil {
 		log.Warn("Failed data sign attempt", "address", addr, "err", err)
 		return nil, err
@@ -658,6 +823,9 @@ type AccountResult struct {
 	Nonce        hexutil.Uint64  `json:"nonce"`
 	StorageHash  common.Hash     `json:"storageHash"`
 	StorageProof []StorageResult `json:"storageProof"`
+	// Quorum
+	// PrivateStateRoot is set when the account is private, the proofs are then against this root
+	PrivateStateRoot *common.Hash `json:"privateStateRoot,omitempty"`
 }
 type StorageResult struct {
 	Key   string       `json:"key"`
@@ -666,11 +834,25 @@ type StorageResult struct {
 }
 
 // GetProof returns the Merkle-proof for a given account and optionally some storage keys.
+//
+// Quorum: the proofs of a private account are against the private state root of the
+// caller's private state, which is returned along with the proofs.
 func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
+	// Quorum
+	var privateStateRoot *common.Hash
+	if ps, ok := state.(privateProofApiState); ok && ps.IsPrivate(address) {
+		if err := checkPrivateAccountAccess(ctx, s.b, ps.PrivateState(), address); err != nil {
+			return nil, err
+		}
+		root := ps.PrivateStateRoot()
+		privateStateRoot = &root
+		state = ps.PrivateState()
+	}
+	// End Quorum
 
 	storageTrie := state.StorageTrie(address)
 	storageHash := types.EmptyRootHash
@@ -705,13 +887,14 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 	}
 
 	return &AccountResult{
-		Address:      address,
-		AccountProof: toHexSlice(accountProof),
-		Balance:      (*hexutil.Big)(state.GetBalance(address)),
-		CodeHash:     codeHash,
-		Nonce:        hexutil.Uint64(state.GetNonce(address)),
-		StorageHash:  storageHash,
-		StorageProof: storageProof,
+		Address:          address,
+		AccountProof:     toHexSlice(accountProof),
+		Balance:          (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:         codeHash,
+		Nonce:            hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:      storageHash,
+		StorageProof:     storageProof,
+		PrivateStateRoot: privateStateRoot,
 	}, state.Error()
 }
 
@@ -828,6 +1011,10 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 	if state == nil || err != nil {
 		return nil, err
 	}
+	// Quorum
+	if err := checkPrivateReadAccess(ctx, s.b, state, address); err != nil {
+		return nil, err
+	}
 	code := state.GetCode(address)
 	return code, state.Error()
 }
@@ -840,6 +1027,10 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	if state == nil || err != nil {
 		return nil, err
 	}
+	// Quorum
+	if err := checkPrivateReadAccess(ctx, s.b, state, address); err != nil {
+		return nil, err
+	}
 	res := state.GetState(address, common.HexToHash(key))
 	return res[:], state.Error()
 }
@@ -893,58 +1084,138 @@ func (args *CallArgs) ToMessage(globalGasCap uint64) types.Message {
 	return msg
 }
 
-// account indicates the overriding fields of account during the execution of
-// a message call.
+// OverrideAccount indicates the overriding fields of account during the execution
+// of a message call.
 // Note, state and stateDiff can't be specified at the same time. If state is
 // set, message execution will only use the data in the given state. Otherwise
 // if statDiff is set, all diff will be applied first and then execute the call
 // message.
-type account struct {
+// Quorum: overrides are applied to the private state if the account exists in it,
+// otherwise to the public state. Private forces the override into the private state,
+// e.g.: to simulate a private contract which has not been deployed yet.
+type OverrideAccount struct {
 	Nonce     *hexutil.Uint64              `json:"nonce"`
 	Code      *hexutil.Bytes               `json:"code"`
 	Balance   **hexutil.Big                `json:"balance"`
 	State     *map[common.Hash]common.Hash `json:"state"`
 	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+	Private   bool                         `json:"private"` // Quorum
 }
 
-// Quorum - Multitenancy
-// Before returning the result, we need to inspect the EVM and
-// perform verification check
-func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides map[common.Address]account, vmCfg vm.Config, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
 
-	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
-	if state == nil || err != nil {
-		return nil, err
+// Quorum
+// privateApiState is implemented by the API states which give access to the private state
+type privateApiState interface {
+	PrivateState() vm.MinimalApiState
+}
+
+// privateProofApiState is implemented by the API states which can prove private accounts
+// against the root of the private state
+type privateProofApiState interface {
+	privateApiState
+	IsPrivate(addr common.Address) bool
+	PrivateStateRoot() common.Hash
+}
+
+// checkPrivateAccountAccess makes sure the caller is a party of the given private account
+func checkPrivateAccountAccess(ctx context.Context, b Backend, state vm.MinimalApiState, addr common.Address) error {
+	if _, ok := b.SupportsMultitenancy(ctx); !ok {
+		return nil
 	}
-	// Override the fields of specified contracts before execution.
-	for addr, account := range overrides {
+	psm, err := b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	managedParties, err := state.GetManagedParties(addr)
+	if err != nil {
+		return err
+	}
+	if b.PSMR().NotIncludeAny(psm, managedParties...) {
+		return newPrivacyError(ErrCodeNotParty, fmt.Errorf("account %s: %w", addr.Hex(), multitenancy.ErrNotAuthorized))
+	}
+	return nil
+}
+
+// checkPrivateReadAccess makes sure the caller is a party of the given account when it
+// is private, so that the tenants of a node can't read nor override the code and the
+// storage of the private contracts of one another
+func checkPrivateReadAccess(ctx context.Context, b Backend, state vm.MinimalApiState, addr common.Address) error {
+	if ps, ok := state.(privateProofApiState); ok && ps.IsPrivate(addr) {
+		return checkPrivateAccountAccess(ctx, b, ps.PrivateState(), addr)
+	}
+	return nil
+}
+
+// Apply overrides the fields of specified accounts into the given state.
+func (diff *StateOverride) Apply(state vm.MinimalApiState) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		target := state
+		// Quorum
+		if account.Private {
+			s, ok := state.(privateApiState)
+			if !ok {
+				return fmt.Errorf("account %s: private state overrides are not supported", addr.Hex())
+			}
+			target = s.PrivateState()
+		}
+		// End Quorum
 		// Override account nonce.
 		if account.Nonce != nil {
-			state.SetNonce(addr, uint64(*account.Nonce))
+			target.SetNonce(addr, uint64(*account.Nonce))
 		}
 		// Override account(contract) code.
 		if account.Code != nil {
-			state.SetCode(addr, *account.Code)
+			target.SetCode(addr, *account.Code)
 		}
 		// Override account balance.
 		if account.Balance != nil {
-			state.SetBalance(addr, (*big.Int)(*account.Balance))
+			target.SetBalance(addr, (*big.Int)(*account.Balance))
 		}
 		if account.State != nil && account.StateDiff != nil {
-			return nil, fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
 		}
 		// Replace entire state if caller requires.
 		if account.State != nil {
-			state.SetStorage(addr, *account.State)
+			target.SetStorage(addr, *account.State)
 		}
 		// Apply state diff into specified accounts.
 		if account.StateDiff != nil {
 			for key, value := range *account.StateDiff {
-				state.SetState(addr, key, value)
+				target.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// Quorum - Multitenancy
+// Before returning the result, we need to inspect the EVM and
+// perform verification check
+func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// Quorum
+	// the caller must be a party of the private contracts being overridden
+	if overrides != nil {
+		for addr := range *overrides {
+			if err := checkPrivateReadAccess(ctx, b, state, addr); err != nil {
+				return nil, err
 			}
 		}
 	}
+	// Override the fields of specified contracts before execution.
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
 	var cancel context.CancelFunc
@@ -959,7 +1230,7 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 
 	msg := args.ToMessage(globalGasCap)
 	// Get a new instance of the EVM.
-	evm, vmError, err := b.GetEVM(ctx, msg, state, header)
+	evm, vmError, err := b.GetEVM(ctx, msg, state, header, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1026,13 +1297,9 @@ func (e *revertError) ErrorData() interface{} {
 // Quorum
 // - replaced the default 5s time out with the value passed in vm.calltimeout
 // - multi tenancy verification
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *map[common.Address]account) (hexutil.Bytes, error) {
-	var accounts map[common.Address]account
-	if overrides != nil {
-		accounts = *overrides
-	}
-
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, accounts, vm.Config{}, s.b.CallTimeOut(), s.b.RPCGasCap())
+// - state overrides of private accounts
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, vm.Config{}, s.b.CallTimeOut(), s.b.RPCGasCap())
 	if err != nil {
 		return nil, err
 	}
@@ -1197,6 +1464,107 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, bl
 	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
 }
 
+// accessListResult returns an optional accesslist
+// Its the result of the `eth_createAccessList` RPC call.
+// It contains an error if the transaction itself failed.
+type accessListResult struct {
+	Accesslist *types.AccessList `json:"accessList"`
+	Error      string            `json:"error,omitempty"`
+	GasUsed    hexutil.Uint64    `json:"gasUsed"`
+}
+
+// CreateAccessList creates an EIP-2930 access list for the given transaction.
+// BlockNrOrHash can be specified to create the access list on top of a certain state.
+// Quorum: private transactions are executed against the private state of the caller.
+func (s *PublicBlockChainAPI) CreateAccessList(ctx context.Context, args SendTxArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*accessListResult, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	acl, gasUsed, vmerr, err := AccessList(ctx, s.b, bNrOrHash, args)
+	if err != nil {
+		return nil, err
+	}
+	result := &accessListResult{Accesslist: &acl, GasUsed: hexutil.Uint64(gasUsed)}
+	if vmerr != nil {
+		result.Error = vmerr.Error()
+	}
+	return result, nil
+}
+
+// AccessList creates an access list for the given transaction.
+// If the accesslist creation fails an error is returned.
+// If the transaction itself fails, an vmErr is returned.
+func AccessList(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrHash, args SendTxArgs) (acl types.AccessList, gasUsed uint64, vmErr error, err error) {
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, 0, nil, err
+	}
+	if args.Data != nil && args.Input != nil && !bytes.Equal(*args.Data, *args.Input) {
+		return nil, 0, nil, errors.New(`both "data" and "input" are set and not equal. Please use "input" to pass transaction call data`)
+	}
+	// Quorum
+	// the EVM executes against the private state when the recipient is a private contract
+	if args.To != nil && !args.IsPrivate() {
+		if s, ok := state.(privateApiState); ok && len(s.PrivateState().GetCode(*args.To)) > 0 {
+			return nil, 0, nil, fmt.Errorf("%s is a private contract, privateFor must be specified", args.To.Hex())
+		}
+	}
+	// End Quorum
+	// Retrieve the precompiles and the recipient, both are excluded from the access list
+	precompiles := vm.ActivePrecompiles(b.ChainConfig().Rules(header.Number))
+	var to common.Address
+	if args.To != nil {
+		to = *args.To
+	} else {
+		nonce := state.GetNonce(args.From)
+		if args.Nonce != nil {
+			nonce = uint64(*args.Nonce)
+		}
+		to = crypto.CreateAddress(args.From, nonce)
+	}
+	data := hexutil.Bytes(args.inputOrData())
+	callArgs := CallArgs{
+		From:     &args.From,
+		To:       args.To,
+		Gas:      args.Gas,
+		GasPrice: args.GasPrice,
+		Value:    args.Value,
+		Data:     &data,
+	}
+	msg := callArgs.ToMessage(b.RPCGasCap())
+
+	// Setup context so it may be cancelled the call has completed
+	var cancel context.CancelFunc
+	if timeout := b.CallTimeOut(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	tracer := vm.NewAccessListTracer(nil, args.From, to, precompiles)
+	evm, vmError, err := b.GetEVM(ctx, msg, state, header, &vm.Config{Debug: true, Tracer: tracer})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.Gas()))
+	if err := vmError(); err != nil {
+		return nil, 0, nil, err
+	}
+	if evm.Cancelled() {
+		return nil, 0, nil, fmt.Errorf("execution aborted (timeout = %v)", b.CallTimeOut())
+	}
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to apply transaction: %v", err)
+	}
+	return tracer.AccessList(), res.UsedGas, res.Err, nil
+}
+
 // ExecutionResult groups all structured logs emitted by the EVM
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
@@ -1354,6 +1722,14 @@ type RPCTransaction struct {
 	V                *hexutil.Big    `json:"v"`
 	R                *hexutil.Big    `json:"r"`
 	S                *hexutil.Big    `json:"s"`
+
+	// Quorum: privacy metadata, only returned by eth_getTransactionByHash
+	IsPrivate          *bool                         `json:"isPrivate,omitempty"`
+	PrivacyFlag        *engine.PrivacyFlagType       `json:"privacyFlag,omitempty"`
+	PSI                *types.PrivateStateIdentifier `json:"psi,omitempty"`
+	PrivatePayloadHash *hexutil.Bytes                `json:"privatePayloadHash,omitempty"`
+	// Quorum: the validity window of the transaction, if any
+	Validity *types.TxValidity `json:"validity,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1379,6 +1755,7 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		V:        (*hexutil.Big)(v),
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
+		Validity: tx.Validity(), // Quorum
 	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = &blockHash
@@ -1388,8 +1765,8 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 	return result
 }
 
-// newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
-func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
+// NewRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
+func NewRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
 	return newRPCTransaction(tx, common.Hash{}, 0, 0)
 }
 
@@ -1426,11 +1803,12 @@ func newRPCTransactionFromBlockHash(b *types.Block, hash common.Hash) *RPCTransa
 type PublicTransactionPoolAPI struct {
 	b         Backend
 	nonceLock *AddrLocker
+	outbox    *AsyncCallbackOutbox // Quorum
 }
 
 // NewPublicTransactionPoolAPI creates a new RPC service with methods specific for the transaction pool.
 func NewPublicTransactionPoolAPI(b Backend, nonceLock *AddrLocker) *PublicTransactionPoolAPI {
-	return &PublicTransactionPoolAPI{b, nonceLock}
+	return &PublicTransactionPoolAPI{b: b, nonceLock: nonceLock, outbox: b.AsyncCallbackOutbox()}
 }
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
@@ -1511,6 +1889,55 @@ func (s *PublicTransactionPoolAPI) GetContractPrivacyMetadata(ctx context.Contex
 	return state.GetPrivacyMetadata(address)
 }
 
+// privacyMetadata describes the privacy of a transaction in eth_getTransactionByHash
+// and eth_getTransactionReceipt responses.
+type privacyMetadata struct {
+	isPrivate   bool
+	payloadHash common.EncryptedPayloadHash
+	// only set if the private state of the caller is a party of the transaction
+	privacyFlag *engine.PrivacyFlagType
+	psi         *types.PrivateStateIdentifier
+}
+
+// resolvePrivacyMetadata retrieves the privacy metadata of a transaction, asking the
+// private transaction manager for the details only known to the parties. The details
+// are omitted if the private transaction manager can't be reached, the transaction
+// itself is still returned.
+func resolvePrivacyMetadata(ctx context.Context, b Backend, tx *types.Transaction) (*privacyMetadata, error) {
+	if !tx.IsPrivate() {
+		return &privacyMetadata{}, nil
+	}
+	metadata := &privacyMetadata{isPrivate: true, payloadHash: common.BytesToEncryptedPayloadHash(tx.Data())}
+	_, managedParties, _, extra, err := private.P.Receive(metadata.payloadHash)
+	if err != nil {
+		log.Warn("Failed to retrieve the privacy metadata of the transaction", "tx", tx.Hash(), "err", err)
+		return metadata, nil
+	}
+	if extra == nil {
+		// not a party of the transaction
+		return metadata, nil
+	}
+	psm, err := b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if b.PSMR().NotIncludeAny(psm, managedParties...) {
+		return metadata, nil
+	}
+	metadata.privacyFlag, metadata.psi = &extra.PrivacyFlag, &psm.ID
+	return metadata, nil
+}
+
+// withPrivacyMetadata populates the privacy fields of the transaction
+func (tx *RPCTransaction) withPrivacyMetadata(metadata *privacyMetadata) *RPCTransaction {
+	tx.IsPrivate = &metadata.isPrivate
+	if metadata.isPrivate {
+		tx.PrivatePayloadHash = metadata.payloadHash.BytesTypeRef()
+		tx.PrivacyFlag, tx.PSI = metadata.privacyFlag, metadata.psi
+	}
+	return tx
+}
+
 // /Quorum
 
 // GetTransactionByHash returns the transaction for the given hash
@@ -1521,11 +1948,19 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 		return nil, err
 	}
 	if tx != nil {
-		return newRPCTransaction(tx, blockHash, blockNumber, index), nil
+		metadata, err := resolvePrivacyMetadata(ctx, s.b, tx)
+		if err != nil {
+			return nil, err
+		}
+		return newRPCTransaction(tx, blockHash, blockNumber, index).withPrivacyMetadata(metadata), nil
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
-		return newRPCPendingTransaction(tx), nil
+		metadata, err := resolvePrivacyMetadata(ctx, s.b, tx)
+		if err != nil {
+			return nil, err
+		}
+		return NewRPCPendingTransaction(tx).withPrivacyMetadata(metadata), nil
 	}
 
 	// Transaction unknown, return as such
@@ -1588,6 +2023,18 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if len(receipt.RevertReason) > 0 {
 		fields["revertReason"] = hexutil.Encode(receipt.RevertReason)
 	}
+	metadata, err := resolvePrivacyMetadata(ctx, s.b, tx)
+	if err != nil {
+		return nil, err
+	}
+	fields["isPrivate"] = metadata.isPrivate
+	if metadata.isPrivate {
+		fields["privatePayloadHash"] = metadata.payloadHash.BytesTypeRef()
+		if metadata.privacyFlag != nil {
+			fields["privacyFlag"] = *metadata.privacyFlag
+			fields["psi"] = *metadata.psi
+		}
+	}
 	// End Quorum
 
 	// Assign receipt status or post state.
@@ -1608,7 +2055,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 // Quorum: if signing a private TX, set with tx.SetPrivate() before calling this method.
 // sign is a helper function that signs a transaction with the private key of the given address.
-func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+func (s *PublicTransactionPoolAPI) sign(ctx context.Context, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
 	// Look up the wallet containing the requested signer
 	account := accounts.Account{Address: addr}
 
@@ -1625,9 +2072,50 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 	// /Quorum
 
 	// Request the wallet to sign the transaction
+	return signTx(ctx, wallet, account, tx, chainID)
+}
+
+// Quorum
+// contextSigner is implemented by wallets which take the caller's identity found in the
+// request context into account when signing, e.g.: the account plugin enforcing a signing policy
+type contextSigner interface {
+	SignTxWithContext(ctx context.Context, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	SignTxWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// signTx passes ctx along to the wallet if it supports it
+func signTx(ctx context.Context, wallet accounts.Wallet, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if signer, ok := wallet.(contextSigner); ok {
+		return signer.SignTxWithContext(ctx, account, tx, chainID)
+	}
 	return wallet.SignTx(account, tx, chainID)
 }
 
+// contextTextSigner is implemented by wallets which take the caller's identity found in the
+// request context into account when signing text
+type contextTextSigner interface {
+	SignTextWithContext(ctx context.Context, account accounts.Account, text []byte) ([]byte, error)
+	SignTextWithPassphraseWithContext(ctx context.Context, account accounts.Account, passphrase string, text []byte) ([]byte, error)
+}
+
+// signText passes ctx along to the wallet if it supports it
+func signText(ctx context.Context, wallet accounts.Wallet, account accounts.Account, text []byte) ([]byte, error) {
+	if signer, ok := wallet.(contextTextSigner); ok {
+		return signer.SignTextWithContext(ctx, account, text)
+	}
+	return wallet.SignText(account, text)
+}
+
+// signTextWithPassphrase passes ctx along to the wallet if it supports it
+func signTextWithPassphrase(ctx context.Context, wallet accounts.Wallet, account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	if signer, ok := wallet.(contextTextSigner); ok {
+		return signer.SignTextWithPassphraseWithContext(ctx, account, passphrase, text)
+	}
+	return wallet.SignTextWithPassphrase(account, passphrase, text)
+}
+
+// /Quorum
+
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
 // Quorum: introducing additional arguments encapsulated in PrivateTxArgs struct
 //		   to support private transactions processing.
@@ -1644,6 +2132,10 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+
+	// Quorum: the last block number and time (in seconds) the transaction may be included at
+	ValidUntilBlock *hexutil.Uint64 `json:"validUntilBlock"`
+	ValidUntilTime  *hexutil.Uint64 `json:"validUntilTime"`
 }
 
 func (s SendTxArgs) IsPrivate() bool {
@@ -1667,9 +2159,30 @@ type PrivateTxArgs struct {
 	PrivateFor    []string               `json:"privateFor"`
 	PrivateTxType string                 `json:"restriction"`
 	PrivacyFlag   engine.PrivacyFlagType `json:"privacyFlag"`
+
+	// payload is the plaintext payload once the data is replaced by the hash of the
+	// encrypted payload, for the signing policies of the wallets
+	payload []byte
+}
+
+// withPrivateTxMetadata returns a copy of ctx carrying the privacy metadata of args for the
+// wallets signing the private transaction, e.g.: the external signer
+func (args *PrivateTxArgs) withPrivateTxMetadata(ctx context.Context) context.Context {
+	if args.PrivateFor == nil {
+		return ctx
+	}
+	return accounts.WithPrivateTxMetadata(ctx, &accounts.PrivateTxMetadata{
+		PrivateFrom: args.PrivateFrom,
+		PrivateFor:  args.PrivateFor,
+		PrivacyFlag: args.PrivacyFlag,
+		Payload:     args.payload,
+	})
 }
 
 func (args *PrivateTxArgs) SetDefaultPrivateFrom(ctx context.Context, b Backend) error {
+	// the participant names of the key aliases registry are resolved before anything
+	// uses the keys
+	args.resolveKeyAliases()
 	if args.PrivateFor != nil && len(args.PrivateFrom) == 0 && b.ChainConfig().IsMPS {
 		psm, err := b.PSMR().ResolveForUserContext(ctx)
 		if err != nil {
@@ -1680,6 +2193,15 @@ func (args *PrivateTxArgs) SetDefaultPrivateFrom(ctx context.Context, b Backend)
 	return nil
 }
 
+// resolveKeyAliases replaces the participant names of privateFrom and privateFor by
+// their public keys, see private.ResolveKey
+func (args *PrivateTxArgs) resolveKeyAliases() {
+	if args.PrivateFrom != "" {
+		args.PrivateFrom = private.ResolveKey(args.PrivateFrom)
+	}
+	args.PrivateFor = private.ResolveKeys(args.PrivateFor)
+}
+
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
 func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.GasPrice == nil {
@@ -1756,15 +2278,34 @@ func (args *SendTxArgs) inputOrData() (result []byte) {
 }
 
 func (args *SendTxArgs) toTransaction() *types.Transaction {
+	var tx *types.Transaction
 	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), args.inputOrData())
+		tx = types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), args.inputOrData())
+	} else {
+		tx = types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), args.inputOrData())
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), args.inputOrData())
+	// Quorum
+	if args.ValidUntilBlock != nil || args.ValidUntilTime != nil {
+		var block, time uint64
+		if args.ValidUntilBlock != nil {
+			block = uint64(*args.ValidUntilBlock)
+		}
+		if args.ValidUntilTime != nil {
+			time = uint64(*args.ValidUntilTime)
+		}
+		tx = tx.WithValidity(block, time)
+	}
+	return tx
 }
 
 // TODO: this submits a signed transaction, if it is a signed private transaction that should already be recorded in the tx.
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
-func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction, privateFrom string, isRaw bool) (common.Hash, error) {
+func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction, privateFrom string, isRaw bool) (_ common.Hash, err error) {
+	// Quorum
+	ctx, span := tracing.Start(ctx, "tx.submit")
+	span.SetAttribute("tx.hash", tx.Hash().Hex()).SetAttribute("tx.private", tx.IsPrivate())
+	defer func() { span.SetError(err).End() }()
+
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
@@ -1804,10 +2345,10 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction, pr
 	}
 	if tx.To() == nil {
 		addr := crypto.CreateAddress(from, tx.Nonce())
-		log.Info("Submitted contract creation", "fullhash", tx.Hash().Hex(), "to", addr.Hex())
+		log.FromContext(ctx).Info("Submitted contract creation", "fullhash", tx.Hash().Hex(), "to", addr.Hex())
 		log.EmitCheckpoint(log.TxCreated, "tx", tx.Hash().Hex(), "to", addr.Hex())
 	} else {
-		log.Info("Submitted transaction", "fullhash", tx.Hash().Hex(), "recipient", tx.To())
+		log.FromContext(ctx).Info("Submitted transaction", "fullhash", tx.Hash().Hex(), "recipient", tx.To())
 		log.EmitCheckpoint(log.TxCreated, "tx", tx.Hash().Hex(), "to", tx.To().Hex())
 	}
 	return tx.Hash(), nil
@@ -1847,7 +2388,7 @@ func runSimulation(ctx context.Context, b Backend, from common.Address, tx *type
 	if stateAtBlock == nil || err != nil {
 		return nil, err
 	}
-	evm, _, err := b.GetEVM(ctx, msg, stateAtBlock, header)
+	evm, _, err := b.GetEVM(ctx, msg, stateAtBlock, header, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1907,6 +2448,7 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	}
 	if isPrivate && !common.EmptyEncryptedPayloadHash(data) {
 		// replace the original payload with encrypted payload hash
+		args.payload = args.inputOrData()
 		args.Data = data.BytesTypeRef()
 	}
 	// /Quorum
@@ -1926,7 +2468,7 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	}
 	// /Quorum
 
-	signed, err := wallet.SignTx(account, tx, chainID)
+	signed, err := signTx(args.withPrivateTxMetadata(ctx), wallet, account, tx, chainID)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -2013,7 +2555,7 @@ func (s *PublicTransactionPoolAPI) SendRawPrivateTransaction(ctx context.Context
 // The account associated with addr must be unlocked.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_sign
-func (s *PublicTransactionPoolAPI) Sign(addr common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+func (s *PublicTransactionPoolAPI) Sign(ctx context.Context, addr common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
 	// Look up the wallet containing the requested signer
 	account := accounts.Account{Address: addr}
 
@@ -2022,7 +2564,7 @@ func (s *PublicTransactionPoolAPI) Sign(addr common.Address, data hexutil.Bytes)
 		return nil, err
 	}
 	// Sign the requested hash with the wallet
-	signature, err := wallet.SignText(account, data)
+	signature, err := signText(ctx, wallet, account, data)
 	if err == nil {
 		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
 	}
@@ -2071,7 +2613,7 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	}
 	// End Quorum
 
-	tx, err := s.sign(args.From, toSign)
+	tx, err := s.sign(args.withPrivateTxMetadata(ctx), args.From, toSign)
 	if err != nil {
 		return nil, err
 	}
@@ -2103,7 +2645,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 		}
 		from, _ := types.Sender(signer, tx)
 		if _, exists := accounts[from]; exists {
-			transactions = append(transactions, newRPCPendingTransaction(tx))
+			transactions = append(transactions, NewRPCPendingTransaction(tx))
 		}
 	}
 	return transactions, nil
@@ -2165,7 +2707,7 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 			if sendArgs.IsPrivate() {
 				newTx.SetPrivate()
 			}
-			signedTx, err := s.sign(sendArgs.From, newTx)
+			signedTx, err := s.sign(ctx, sendArgs.From, newTx)
 			if err != nil {
 				return common.Hash{}, err
 			}
@@ -2358,7 +2900,8 @@ func toHexSlice(b [][]byte) []string {
 
 type AsyncSendTxArgs struct {
 	SendTxArgs
-	CallbackUrl string `json:"callbackUrl"`
+	CallbackUrl     string            `json:"callbackUrl"`
+	CallbackHeaders map[string]string `json:"callbackHeaders"`
 }
 
 type AsyncResultSuccess struct {
@@ -2394,16 +2937,8 @@ func (s *PublicTransactionPoolAPI) send(ctx context.Context, asyncArgs AsyncSend
 			resultResponse = &AsyncResultSuccess{Id: id, TxHash: txHash}
 		}
 
-		buf := new(bytes.Buffer)
-		err := json.NewEncoder(buf).Encode(resultResponse)
-		if err != nil {
-			log.Info("Error encoding callback JSON", "err", err.Error())
-			return
-		}
-		_, err = http.Post(asyncArgs.CallbackUrl, "application/json", buf)
-		if err != nil {
-			log.Info("Error sending callback", "err", err.Error())
-			return
+		if err := s.outbox.enqueue(asyncArgs.CallbackUrl, asyncArgs.CallbackHeaders, resultResponse); err != nil {
+			log.Error("Error queueing async transaction callback", "err", err)
 		}
 	}
 
@@ -2424,12 +2959,24 @@ var async = newAsync(100)
 // parties to confirm receipt of the encrypted payloads. An optional callbackUrl may
 // be specified--when a transaction is submitted to the transaction pool, it will be
 // called with a POST request containing either {"error": "error message"} or
-// {"txHash": "0x..."}.
+// {"txHash": "0x..."}. The callback may be an http or https URL, and is sent with the
+// optional callbackHeaders (e.g. an Authorization header). Callbacks are persisted
+// until they are delivered, and failed deliveries are retried with an exponential backoff.
+// The callbackHeaders are not persisted: a callback with headers is not resumed after a
+// restart of the node.
 //
 // Please note: This is a temporary integration to improve performance in high-latency
 // environments when sending many private transactions. It will be removed at a later
 // date when account management is handled outside Ethereum.
 func (s *PublicTransactionPoolAPI) SendTransactionAsync(ctx context.Context, args AsyncSendTxArgs) (common.Hash, error) {
+	if args.CallbackUrl != "" {
+		if s.outbox == nil {
+			return common.Hash{}, errors.New("callbacks are not supported")
+		}
+		if err := validateCallbackUrl(args.CallbackUrl); err != nil {
+			return common.Hash{}, err
+		}
+	}
 
 	select {
 	case async.sem <- struct{}{}:
@@ -2443,10 +2990,38 @@ func (s *PublicTransactionPoolAPI) SendTransactionAsync(ctx context.Context, arg
 	}
 }
 
+// Create2Address is the address of a contract deployed with CREATE2, returned by
+// eth_getCreate2Address
+type Create2Address struct {
+	Address common.Address `json:"address"`
+	// Available is false if an account of the private state of the caller or of the
+	// public state already lives at the address, the deployment would fail
+	Available bool `json:"available"`
+}
+
+// GetCreate2Address precomputes the address of a contract deployed with CREATE2 by a
+// deployer (factory) contract, for the salt and init code. The address is the same in
+// all the private states the contract is deployed to, its availability is checked
+// against the private state of the caller at the block (latest if not set).
+func (s *PublicBlockChainAPI) GetCreate2Address(ctx context.Context, deployer common.Address, salt common.Hash, initCode hexutil.Bytes, blockNrOrHash *rpc.BlockNumberOrHash) (*Create2Address, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	address := crypto.CreateAddress2(deployer, salt, crypto.Keccak256(initCode))
+	codeHash := state.GetCodeHash(address)
+	collides := state.GetNonce(address) != 0 || (codeHash != (common.Hash{}) && codeHash != crypto.Keccak256Hash(nil))
+	return &Create2Address{Address: address, Available: !collides}, state.Error()
+}
+
 // GetQuorumPayload returns the contents of a private transaction
 func (s *PublicBlockChainAPI) GetQuorumPayload(ctx context.Context, digestHex string) (string, error) {
 	if !private.IsQuorumPrivacyEnabled() {
-		return "", fmt.Errorf("PrivateTransactionManager is not enabled")
+		return "", newPrivacyError(ErrCodePTMUnavailable, errPTMNotEnabled)
 	}
 	psm, err := s.b.PSMR().ResolveForUserContext(ctx)
 	if err != nil {
@@ -2467,14 +3042,46 @@ func (s *PublicBlockChainAPI) GetQuorumPayload(ctx context.Context, digestHex st
 	}
 	_, managedParties, data, _, err := private.P.Receive(common.BytesToEncryptedPayloadHash(b))
 	if err != nil {
-		return "", err
+		return "", toPrivacyError(err)
+	}
+	if data == nil && len(managedParties) == 0 {
+		return "", newPrivacyError(ErrCodePayloadNotFound, errPayloadNotFound)
+	}
+	if err := checkPayloadReadAccess(ctx, s.b, managedParties); err != nil {
+		return "", toPrivacyError(err)
 	}
 	if s.b.PSMR().NotIncludeAny(psm, managedParties...) {
-		return "0x", nil
+		return "", newPrivacyError(ErrCodeNotParty, fmt.Errorf("private state %s is not a party of the payload", psm.ID))
 	}
 	return fmt.Sprintf("0x%x", data), nil
 }
 
+// Quorum
+// checkPayloadReadAccess evaluates the security attributes of the parties of a private payload
+// for a read: when multitenancy is on, the caller must be granted access to the private state
+// of at least one of the parties.
+func checkPayloadReadAccess(ctx context.Context, b Backend, managedParties []string) error {
+	token, ok := b.SupportsMultitenancy(ctx)
+	if !ok {
+		return nil
+	}
+	for _, party := range managedParties {
+		psm, err := b.PSMR().ResolveForManagedParty(party)
+		if err != nil {
+			// party not managed by this node
+			continue
+		}
+		isAuthorized, err := multitenancy.IsPSIAuthorized(token, psm.ID)
+		if err != nil {
+			return err
+		}
+		if isAuthorized {
+			return nil
+		}
+	}
+	return newPrivacyError(ErrCodeNotParty, multitenancy.ErrNotAuthorized)
+}
+
 // for raw private transaction, privateTxArgs.privateFrom will be updated with value from Tessera when payload is retrieved
 func checkAndHandlePrivateTransaction(ctx context.Context, b Backend, tx *types.Transaction, privateTxArgs *PrivateTxArgs, from common.Address, txnType TransactionType) (isPrivate bool, hash common.EncryptedPayloadHash, err error) {
 	isPrivate = privateTxArgs != nil && privateTxArgs.PrivateFor != nil
@@ -2499,7 +3106,7 @@ func checkAndHandlePrivateTransaction(ctx context.Context, b Backend, tx *types.
 			return
 		}
 		if psm.NotIncludeAny(privateTxArgs.PrivateFrom) {
-			err = fmt.Errorf("The PrivateFrom (%s) address does not match the specified private state (%s) ", privateTxArgs.PrivateFrom, psm.ID)
+			err = newPrivacyError(ErrCodeNotParty, fmt.Errorf("The PrivateFrom (%s) address does not match the specified private state (%s) ", privateTxArgs.PrivateFrom, psm.ID))
 			return
 		}
 	}
@@ -2535,27 +3142,38 @@ func checkAndHandlePrivateTransaction(ctx context.Context, b Backend, tx *types.
 // The above information along with private originating payload are sent to Transaction Manager
 // to obtain hash of the encrypted private payload
 func handlePrivateTransaction(ctx context.Context, b Backend, tx *types.Transaction, privateTxArgs *PrivateTxArgs, from common.Address, txnType TransactionType) (hash common.EncryptedPayloadHash, err error) {
+	ctx, span := tracing.Start(ctx, "tx.private")
+	span.SetAttribute("tx.type", int(txnType)).SetAttribute("privacyFlag", int(privateTxArgs.PrivacyFlag))
+	defer func() { span.SetError(err).End() }()
+	// the requests to the private transaction manager are traced as part of the span
+	ptm := private.WithContext(ctx)
+
+	logger := log.FromContext(ctx)
 	defer func(start time.Time) {
-		log.Debug("Handle Private Transaction finished", "took", time.Since(start))
+		logger.Debug("Handle Private Transaction finished", "took", time.Since(start))
 	}(time.Now())
 
 	data := tx.Data()
 
 	var affectedCATxHashes common.EncryptedPayloadHashes // of affected contract accounts
 	var merkleRoot common.Hash
-	log.Debug("sending private tx", "txnType", txnType, "data", common.FormatTerminalString(data), "privatefrom", privateTxArgs.PrivateFrom, "privatefor", privateTxArgs.PrivateFor, "privacyFlag", privateTxArgs.PrivacyFlag)
+	logger.Debug("sending private tx", "txnType", txnType, "data", common.FormatTerminalString(data), "privatefrom", privateTxArgs.PrivateFrom, "privatefor", privateTxArgs.PrivateFor, "privacyFlag", privateTxArgs.PrivacyFlag)
 
 	switch txnType {
 	case FillTransaction:
-		hash, err = private.P.StoreRaw(data, privateTxArgs.PrivateFrom)
+		hash, err = ptm.StoreRaw(data, privateTxArgs.PrivateFrom)
+		err = toPrivacyError(err)
 		return
 	case RawTransaction:
 		hash = common.BytesToEncryptedPayloadHash(data)
-		privatePayload, privateFrom, _, revErr := private.P.ReceiveRaw(hash)
+		privatePayload, privateFrom, _, revErr := ptm.ReceiveRaw(hash)
 		if revErr != nil {
-			return common.EncryptedPayloadHash{}, revErr
+			return common.EncryptedPayloadHash{}, toPrivacyError(revErr)
+		}
+		if privatePayload == nil {
+			return common.EncryptedPayloadHash{}, newPrivacyError(ErrCodePayloadNotFound, errPayloadNotFound)
 		}
-		log.Trace("received raw payload", "hash", hash, "privatepayload", common.FormatTerminalString(privatePayload), "privateFrom", privateFrom)
+		logger.Trace("received raw payload", "hash", hash, "privatepayload", common.FormatTerminalString(privatePayload), "privateFrom", privateFrom)
 		privateTxArgs.PrivateFrom = privateFrom
 		var privateTx *types.Transaction
 		if tx.To() == nil {
@@ -2564,38 +3182,42 @@ func handlePrivateTransaction(ctx context.Context, b Backend, tx *types.Transact
 			privateTx = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), tx.GasPrice(), privatePayload)
 		}
 		affectedCATxHashes, merkleRoot, err = simulateExecutionForPE(ctx, b, from, privateTx, privateTxArgs)
-		log.Trace("after simulation", "affectedCATxHashes", affectedCATxHashes, "merkleRoot", merkleRoot, "privacyFlag", privateTxArgs.PrivacyFlag, "error", err)
+		logger.Trace("after simulation", "affectedCATxHashes", affectedCATxHashes, "merkleRoot", merkleRoot, "privacyFlag", privateTxArgs.PrivacyFlag, "error", err)
 		if err != nil {
 			return
 		}
 
-		_, _, data, err = private.P.SendSignedTx(hash, privateTxArgs.PrivateFor, &engine.ExtraMetadata{
+		_, _, data, err = ptm.SendSignedTx(hash, privateTxArgs.PrivateFor, &engine.ExtraMetadata{
 			ACHashes:     affectedCATxHashes,
 			ACMerkleRoot: merkleRoot,
 			PrivacyFlag:  privateTxArgs.PrivacyFlag,
 		})
 		if err != nil {
+			err = toPrivacyError(err)
 			return
 		}
 
 	case NormalTransaction:
 		affectedCATxHashes, merkleRoot, err = simulateExecutionForPE(ctx, b, from, tx, privateTxArgs)
-		log.Trace("after simulation", "affectedCATxHashes", affectedCATxHashes, "merkleRoot", merkleRoot, "privacyFlag", privateTxArgs.PrivacyFlag, "error", err)
+		logger.Trace("after simulation", "affectedCATxHashes", affectedCATxHashes, "merkleRoot", merkleRoot, "privacyFlag", privateTxArgs.PrivacyFlag, "error", err)
 		if err != nil {
 			return
 		}
 
-		_, _, hash, err = private.P.Send(data, privateTxArgs.PrivateFrom, privateTxArgs.PrivateFor, &engine.ExtraMetadata{
+		sendStart := time.Now()
+		_, _, hash, err = ptm.Send(data, privateTxArgs.PrivateFrom, privateTxArgs.PrivateFor, &engine.ExtraMetadata{
 			ACHashes:     affectedCATxHashes,
 			ACMerkleRoot: merkleRoot,
 			PrivacyFlag:  privateTxArgs.PrivacyFlag,
 		})
+		ptmSendTimer.UpdateSince(sendStart)
 		if err != nil {
+			err = toPrivacyError(err)
 			return
 		}
 	}
 
-	log.Info("sent private signed tx",
+	logger.Info("sent private signed tx",
 		"data", common.FormatTerminalString(data),
 		"hash", hash,
 		"privatefrom", privateTxArgs.PrivateFrom,
@@ -2646,7 +3268,7 @@ func simulateExecutionForPE(ctx context.Context, b Backend, from common.Address,
 		log.Debug("Found affected contract", "address", addr.Hex(), "privacyMetadata", privacyMetadata)
 		//privacyMetadata not found=non-party, or another db error
 		if err != nil && privacyFlag.IsNotStandardPrivate() {
-			return nil, common.Hash{}, errors.New("PrivacyMetadata not found: " + err.Error())
+			return nil, common.Hash{}, newPrivacyError(ErrCodePrivacyFlagMismatch, errors.New("PrivacyMetadata not found: "+err.Error()))
 		}
 		// when we run simulation, it's possible that affected contracts may contain public ones
 		// public contract will not have any privacyMetadata attached
@@ -2656,7 +3278,7 @@ func simulateExecutionForPE(ctx context.Context, b Backend, from common.Address,
 		}
 		//if affecteds are not all the same return an error
 		if privacyFlag != privacyMetadata.PrivacyFlag {
-			return nil, common.Hash{}, errors.New("sent privacy flag doesn't match all affected contract flags")
+			return nil, common.Hash{}, newPrivacyError(ErrCodePrivacyFlagMismatch, errors.New("sent privacy flag doesn't match all affected contract flags"))
 		}
 
 		affectedContractsHashes.Add(privacyMetadata.CreationTxHash)
@@ -2,7 +2,10 @@ package ethapi
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"net"
 	"os"
 	"testing"
 	"time"
@@ -23,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/multitenancy"
@@ -520,6 +524,468 @@ func TestHandlePrivateTransaction_whenRawStandardPrivateMessageCall(t *testing.T
 
 }
 
+func TestResolvePrivacyMetadata_whenPublicTransaction(t *testing.T) {
+	metadata, err := resolvePrivacyMetadata(arbitraryCtx, &StubBackend{}, simpleStorageContractMessageCallTx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &privacyMetadata{}, metadata)
+}
+
+func TestResolvePrivacyMetadata_whenPrivateStateIsParty(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	defer func(p private.PrivateTransactionManager) { private.P = p }(private.P)
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+	mockptm.EXPECT().Receive(arbitrarySimpleStorageContractEncryptedPayloadHash).Return("", []string{"some address"}, nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagPartyProtection}, nil)
+	private.P = mockptm
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil)
+	mockpsm.EXPECT().NotIncludeAny(psm, "some address").Return(false)
+
+	tx := types.NewTransaction(0, arbitraryStandardPrivateSimpleStorageContractAddress, big.NewInt(0), 0, big.NewInt(0), arbitrarySimpleStorageContractEncryptedPayloadHash.Bytes())
+	tx.SetPrivate()
+
+	metadata, err := resolvePrivacyMetadata(arbitraryCtx, &MPSStubBackend{psmr: mockpsm}, tx)
+
+	assert.NoError(t, err)
+	flag, psi := engine.PrivacyFlagPartyProtection, types.PrivateStateIdentifier("PS1")
+	assert.Equal(t, &privacyMetadata{isPrivate: true, payloadHash: arbitrarySimpleStorageContractEncryptedPayloadHash, privacyFlag: &flag, psi: &psi}, metadata)
+}
+
+func TestResolvePrivacyMetadata_whenPrivateStateIsNotParty(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	defer func(p private.PrivateTransactionManager) { private.P = p }(private.P)
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+	mockptm.EXPECT().Receive(arbitrarySimpleStorageContractEncryptedPayloadHash).Return("", []string{"other address"}, nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagPartyProtection}, nil)
+	private.P = mockptm
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil)
+	mockpsm.EXPECT().NotIncludeAny(psm, "other address").Return(true)
+
+	tx := types.NewTransaction(0, arbitraryStandardPrivateSimpleStorageContractAddress, big.NewInt(0), 0, big.NewInt(0), arbitrarySimpleStorageContractEncryptedPayloadHash.Bytes())
+	tx.SetPrivate()
+
+	metadata, err := resolvePrivacyMetadata(arbitraryCtx, &MPSStubBackend{psmr: mockpsm}, tx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &privacyMetadata{isPrivate: true, payloadHash: arbitrarySimpleStorageContractEncryptedPayloadHash}, metadata)
+	rpcTx := newRPCTransaction(tx, common.Hash{}, 0, 0).withPrivacyMetadata(metadata)
+	assert.Nil(t, rpcTx.PSI)
+	assert.Nil(t, rpcTx.PrivacyFlag)
+	assert.Equal(t, arbitrarySimpleStorageContractEncryptedPayloadHash.Bytes(), []byte(*rpcTx.PrivatePayloadHash))
+}
+
+func TestStateOverride_Apply(t *testing.T) {
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	publicState, _ := state.New(common.Hash{}, db, nil)
+	privateState, _ := state.New(common.Hash{}, db, nil)
+	publicAddr, privateAddr := common.Address{1}, common.Address{2}
+	code := hexutil.Bytes{0x60, 0x00}
+	balance := (*hexutil.Big)(big.NewInt(10))
+	storage := map[common.Hash]common.Hash{{1}: {2}}
+
+	overrides := &StateOverride{
+		publicAddr:  {Balance: &balance},
+		privateAddr: {Code: &code, StateDiff: &storage, Private: true},
+	}
+	err := overrides.Apply(&stubPrivateApiState{publicState, privateState})
+
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), publicState.GetBalance(publicAddr))
+	assert.False(t, privateState.Exist(publicAddr))
+	assert.Equal(t, []byte(code), privateState.GetCode(privateAddr))
+	assert.Equal(t, common.Hash{2}, privateState.GetState(privateAddr, common.Hash{1}))
+	assert.False(t, publicState.Exist(privateAddr))
+}
+
+func TestStateOverride_Apply_whenPrivateStateNotSupported(t *testing.T) {
+	publicState, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	code := hexutil.Bytes{0x60, 0x00}
+
+	err := (&StateOverride{common.Address{1}: {Code: &code, Private: true}}).Apply(publicState)
+
+	assert.Error(t, err)
+}
+
+func TestStateOverride_Apply_whenBothStateAndStateDiff(t *testing.T) {
+	publicState, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	storage := map[common.Hash]common.Hash{{1}: {2}}
+
+	err := (&StateOverride{common.Address{1}: {State: &storage, StateDiff: &storage}}).Apply(publicState)
+
+	assert.EqualError(t, err, "account 0x0100000000000000000000000000000000000000 has both 'state' and 'stateDiff'")
+}
+
+type stubPrivateApiState struct {
+	*state.StateDB
+	privateState *state.StateDB
+}
+
+func (s *stubPrivateApiState) PrivateState() vm.MinimalApiState {
+	return s.privateState
+}
+
+func (s *stubPrivateApiState) IsPrivate(addr common.Address) bool {
+	return s.privateState.Exist(addr)
+}
+
+func (s *stubPrivateApiState) PrivateStateRoot() common.Hash {
+	return s.privateState.IntermediateRoot(false)
+}
+
+type accessListStubBackend struct {
+	StubBackend
+	state *stubPrivateApiState
+}
+
+func (sb *accessListStubBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (vm.MinimalApiState, *types.Header, error) {
+	return sb.state, &types.Header{Number: arbitraryCurrentBlockNumber, Difficulty: big.NewInt(0)}, nil
+}
+
+func (sb *accessListStubBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	vmCtx := core.NewEVMContext(msg, header, nil, &arbitraryFrom)
+	return vm.NewEVM(vmCtx, sb.state.StateDB, sb.state.privateState, params.QuorumTestChainConfig, *vmConfig), func() error { return nil }, nil
+}
+
+func (sb *accessListStubBackend) CallTimeOut() time.Duration {
+	return 0
+}
+
+func (sb *accessListStubBackend) RPCGasCap() uint64 {
+	return 25000000
+}
+
+func newAccessListStubBackend() *accessListStubBackend {
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	publicState, _ := state.New(common.Hash{}, db, nil)
+	privateState, _ := state.New(common.Hash{}, db, nil)
+	return &accessListStubBackend{state: &stubPrivateApiState{publicState, privateState}}
+}
+
+func TestCreateAccessList(t *testing.T) {
+	backend := newAccessListStubBackend()
+	other := common.Address{0xaa}
+	// BALANCE of other, then STOP
+	backend.state.SetCode(arbitraryTo, append(append([]byte{byte(vm.PUSH20)}, other.Bytes()...), byte(vm.BALANCE), byte(vm.POP), byte(vm.STOP)))
+	gas := hexutil.Uint64(arbitraryGas)
+
+	result, err := NewPublicBlockChainAPI(backend).CreateAccessList(arbitraryCtx, SendTxArgs{From: arbitraryFrom, To: &arbitraryTo, Gas: &gas}, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Error)
+	assert.NotZero(t, result.GasUsed)
+	assert.Equal(t, &types.AccessList{{Address: other, StorageKeys: []common.Hash{}}}, result.Accesslist)
+}
+
+func TestCreateAccessList_whenPrivateContractWithoutPrivateFor(t *testing.T) {
+	backend := newAccessListStubBackend()
+	backend.state.privateState.SetCode(arbitraryTo, []byte{byte(vm.STOP)})
+	gas := hexutil.Uint64(arbitraryGas)
+
+	_, err := NewPublicBlockChainAPI(backend).CreateAccessList(arbitraryCtx, SendTxArgs{From: arbitraryFrom, To: &arbitraryTo, Gas: &gas}, nil)
+
+	assert.EqualError(t, err, arbitraryTo.Hex()+" is a private contract, privateFor must be specified")
+}
+
+type tenantStubBackend struct {
+	MPSStubBackend
+	state        vm.MinimalApiState
+	token        *proto.PreAuthenticatedAuthenticationToken
+	multitenancy bool
+}
+
+func (sb *tenantStubBackend) SupportsMultitenancy(rpcCtx context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool) {
+	return sb.token, sb.multitenancy
+}
+
+func (sb *tenantStubBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (vm.MinimalApiState, *types.Header, error) {
+	return sb.state, nil, nil
+}
+
+func newProofTestStates(t *testing.T, privateAddr common.Address, managedParties ...string) (*stubPrivateApiState, common.Hash) {
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	publicState, _ := state.New(common.Hash{}, db, nil)
+	privateState, _ := state.New(common.Hash{}, db, nil)
+	privateState.SetState(privateAddr, common.Hash{1}, common.Hash{2})
+	privateState.SetManagedParties(privateAddr, managedParties)
+	privateRoot, err := privateState.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateState, _ = state.New(privateRoot, db, nil)
+	return &stubPrivateApiState{publicState, privateState}, privateRoot
+}
+
+func TestGetProof_whenPrivateAccount(t *testing.T) {
+	privateAddr := common.Address{2}
+	states, privateRoot := newProofTestStates(t, privateAddr)
+	api := NewPublicBlockChainAPI(&tenantStubBackend{state: states})
+
+	result, err := api.GetProof(arbitraryCtx, privateAddr, []string{common.Hash{1}.Hex()}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &privateRoot, result.PrivateStateRoot)
+	assert.Equal(t, big.NewInt(2), result.StorageProof[0].Value.ToInt())
+	proofDb := memorydb.New()
+	for _, node := range result.AccountProof {
+		blob := hexutil.MustDecode(node)
+		assert.NoError(t, proofDb.Put(crypto.Keccak256(blob), blob))
+	}
+	account, err := trie.VerifyProof(privateRoot, crypto.Keccak256(privateAddr.Bytes()), proofDb)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, account)
+}
+
+func TestGetProof_whenPublicAccount(t *testing.T) {
+	states, _ := newProofTestStates(t, common.Address{2})
+	api := NewPublicBlockChainAPI(&tenantStubBackend{state: states})
+
+	result, err := api.GetProof(arbitraryCtx, common.Address{1}, nil, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.PrivateStateRoot)
+}
+
+func TestGetProof_whenPrivateAccountAndNotParty(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	privateAddr := common.Address{2}
+	states, _ := newProofTestStates(t, privateAddr, "other address")
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil)
+	mockpsm.EXPECT().NotIncludeAny(psm, "other address").Return(true)
+	api := NewPublicBlockChainAPI(&tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, state: states, multitenancy: true})
+
+	_, err := api.GetProof(arbitraryCtx, privateAddr, nil, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+
+	assert.True(t, errors.Is(err, multitenancy.ErrNotAuthorized))
+}
+
+func TestGetStorageAt_whenPrivateAccountAndNotParty(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	privateAddr := common.Address{2}
+	states, _ := newProofTestStates(t, privateAddr, "other address")
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil).Times(2)
+	mockpsm.EXPECT().NotIncludeAny(psm, "other address").Return(true).Times(2)
+	api := NewPublicBlockChainAPI(&tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, state: states, multitenancy: true})
+
+	_, err := api.GetStorageAt(arbitraryCtx, privateAddr, common.Hash{1}.Hex(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	assert.True(t, errors.Is(err, multitenancy.ErrNotAuthorized))
+
+	_, err = api.GetCode(arbitraryCtx, privateAddr, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	assert.True(t, errors.Is(err, multitenancy.ErrNotAuthorized))
+}
+
+func TestDoCall_whenOverridingPrivateAccountAndNotParty(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	privateAddr := common.Address{2}
+	states, _ := newProofTestStates(t, privateAddr, "other address")
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil)
+	mockpsm.EXPECT().NotIncludeAny(psm, "other address").Return(true)
+	backend := &tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, state: states, multitenancy: true}
+	code := hexutil.Bytes{0}
+
+	_, err := DoCall(arbitraryCtx, backend, callTxArgs, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), &StateOverride{privateAddr: {Code: &code, Private: true}}, vm.Config{}, 0, 0)
+
+	assert.True(t, errors.Is(err, multitenancy.ErrNotAuthorized))
+}
+
+func TestGetStorageAt_whenPrivateAccountAndParty(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	privateAddr := common.Address{2}
+	states, _ := newProofTestStates(t, privateAddr, "some address")
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil)
+	mockpsm.EXPECT().NotIncludeAny(psm, "some address").Return(false)
+	api := NewPublicBlockChainAPI(&tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, state: states, multitenancy: true})
+
+	value, err := api.GetStorageAt(arbitraryCtx, privateAddr, common.Hash{1}.Hex(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+
+	assert.NoError(t, err)
+	assert.Equal(t, common.Hash{2}.Bytes(), []byte(value))
+}
+
+func TestCheckPayloadReadAccess_whenMultitenancyDisabled(t *testing.T) {
+	err := checkPayloadReadAccess(arbitraryCtx, &tenantStubBackend{}, []string{"some address"})
+
+	assert.NoError(t, err)
+}
+
+func TestCheckPayloadReadAccess_whenGrantedPartyPrivateState(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForManagedParty("other address").Return(nil, errors.New("not managed"))
+	mockpsm.EXPECT().ResolveForManagedParty("some address").Return(mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"}), nil)
+	token := &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{{Raw: "psi://PS1?node.eoa=0x0"}}}
+
+	err := checkPayloadReadAccess(arbitraryCtx, &tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, token: token, multitenancy: true}, []string{"other address", "some address"})
+
+	assert.NoError(t, err)
+}
+
+func TestCheckPayloadReadAccess_whenNotGrantedPartyPrivateState(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForManagedParty("some address").Return(mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"}), nil)
+	token := &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{{Raw: "psi://PS2?node.eoa=0x0"}}}
+
+	err := checkPayloadReadAccess(arbitraryCtx, &tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, token: token, multitenancy: true}, []string{"some address"})
+
+	assert.True(t, errors.Is(err, multitenancy.ErrNotAuthorized))
+	assert.Equal(t, ErrCodeNotParty, err.(rpc.Error).ErrorCode())
+}
+
+type txPoolStubBackend struct {
+	tenantStubBackend
+	pending map[common.Address]types.Transactions
+}
+
+func (sb *txPoolStubBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return sb.pending, nil
+}
+
+func TestTxPoolContent_whenMultitenancyHidesOtherTenants(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	defer func(p private.PrivateTransactionManager) { private.P = p }(private.P)
+
+	otherPayloadHash := common.BytesToEncryptedPayloadHash([]byte("other"))
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+	mockptm.EXPECT().Receive(arbitrarySimpleStorageContractEncryptedPayloadHash).Return("", []string{"some address"}, nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStandardPrivate}, nil)
+	mockptm.EXPECT().Receive(otherPayloadHash).Return("", []string{"other address"}, nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStandardPrivate}, nil)
+	private.P = mockptm
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil)
+	mockpsm.EXPECT().NotIncludeAny(psm, "some address").Return(false)
+	mockpsm.EXPECT().NotIncludeAny(psm, "other address").Return(true)
+
+	party := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), arbitrarySimpleStorageContractEncryptedPayloadHash.Bytes())
+	party.SetPrivate()
+	public := types.NewTransaction(1, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), nil)
+	other := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), otherPayloadHash.Bytes())
+	other.SetPrivate()
+	sender, otherSender := common.Address{2}, common.Address{3}
+	backend := &txPoolStubBackend{
+		tenantStubBackend: tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}, multitenancy: true},
+		pending:           map[common.Address]types.Transactions{sender: {party, public}, otherSender: {other}},
+	}
+
+	content, err := NewPublicTxPoolAPI(backend).Content(arbitraryCtx, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, content["pending"], 1)
+	txs := content["pending"][sender.Hex()]
+	assert.Len(t, txs, 2)
+	assert.Equal(t, types.PrivateStateIdentifier("PS1"), *txs["0"].PSI)
+	assert.True(t, *txs["0"].IsPrivate)
+	assert.False(t, *txs["1"].IsPrivate)
+}
+
+func TestTxPoolContent_receivesPayloadOnce(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	defer func(p private.PrivateTransactionManager) { private.P = p }(private.P)
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+	mockptm.EXPECT().Receive(arbitrarySimpleStorageContractEncryptedPayloadHash).Return("", []string{"some address"}, nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStandardPrivate}, nil).Times(1)
+	private.P = mockptm
+	psm := mps.NewPrivateStateMetadata("PS1", "PS1", "", mps.Resident, []string{"some address"})
+	mockpsm := mps.NewMockPrivateStateManager(mockCtrl)
+	mockpsm.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil).Times(2)
+	mockpsm.EXPECT().NotIncludeAny(psm, "some address").Return(false).Times(2)
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), arbitrarySimpleStorageContractEncryptedPayloadHash.Bytes())
+	tx.SetPrivate()
+	sender := common.Address{2}
+	api := NewPublicTxPoolAPI(&txPoolStubBackend{
+		tenantStubBackend: tenantStubBackend{MPSStubBackend: MPSStubBackend{psmr: mockpsm}},
+		pending:           map[common.Address]types.Transactions{sender: {tx}},
+	})
+
+	for i := 0; i < 2; i++ {
+		content, err := api.Content(arbitraryCtx, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, types.PrivateStateIdentifier("PS1"), *content["pending"][sender.Hex()]["0"].PSI)
+	}
+}
+
+func TestTxPoolInspect_whenPrivateStateNotGranted(t *testing.T) {
+	token := &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{{Raw: "psi://PS1?node.eoa=0x0"}}}
+	backend := &txPoolStubBackend{tenantStubBackend: tenantStubBackend{token: token, multitenancy: true}}
+
+	_, err := NewPublicTxPoolAPI(backend).Inspect(arbitraryCtx, &TxPoolFilter{PSI: "PS2"})
+
+	assert.True(t, errors.Is(err, multitenancy.ErrNotAuthorized))
+}
+
+func TestToPrivacyError(t *testing.T) {
+	testCases := []struct {
+		err  error
+		code int
+	}{
+		{multitenancy.ErrNotAuthorized, ErrCodeNotParty},
+		{fmt.Errorf("account: %w", multitenancy.ErrNotAuthorized), ErrCodeNotParty},
+		{engine.ErrPrivateTxManagerNotinUse, ErrCodePTMUnavailable},
+		{engine.ErrPrivateTxManagerNotReady, ErrCodePTMUnavailable},
+		{fmt.Errorf("unable to submit request. Cause: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), ErrCodePTMUnavailable},
+		{newPrivacyError(ErrCodePayloadNotFound, errPayloadNotFound), ErrCodePayloadNotFound},
+	}
+	for _, tc := range testCases {
+		err := toPrivacyError(tc.err)
+
+		rpcErr, ok := err.(rpc.Error)
+		if assert.True(t, ok, tc.err.Error()) {
+			assert.Equal(t, tc.code, rpcErr.ErrorCode(), tc.err.Error())
+		}
+		assert.True(t, errors.Is(err, tc.err))
+	}
+	arbitraryErr := errors.New("arbitrary error")
+	assert.Equal(t, arbitraryErr, toPrivacyError(arbitraryErr))
+	assert.Nil(t, toPrivacyError(nil))
+}
+
+func TestResolvePrivacyMetadata_whenPrivateTransactionManagerFails(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	defer func(p private.PrivateTransactionManager) { private.P = p }(private.P)
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+	mockptm.EXPECT().Receive(arbitrarySimpleStorageContractEncryptedPayloadHash).Return("", nil, nil, nil, errors.New("connection refused"))
+	private.P = mockptm
+
+	tx := types.NewTransaction(0, arbitraryStandardPrivateSimpleStorageContractAddress, big.NewInt(0), 0, big.NewInt(0), arbitrarySimpleStorageContractEncryptedPayloadHash.Bytes())
+	tx.SetPrivate()
+
+	metadata, err := resolvePrivacyMetadata(arbitraryCtx, &StubBackend{}, tx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &privacyMetadata{isPrivate: true, payloadHash: arbitrarySimpleStorageContractEncryptedPayloadHash}, metadata)
+}
+
 type StubBackend struct {
 	getEVMCalled                    bool
 	mockAccountExtraDataStateGetter *vm.MockAccountExtraDataStateGetter
@@ -540,6 +1006,10 @@ func (sb *StubBackend) SupportsMultitenancy(rpcCtx context.Context) (*proto.PreA
 	return nil, false
 }
 
+func (sb *StubBackend) AsyncCallbackOutbox() *AsyncCallbackOutbox {
+	return nil
+}
+
 func (sb *StubBackend) AccountExtraDataStateGetterByNumber(context.Context, rpc.BlockNumber) (vm.AccountExtraDataStateGetter, error) {
 	return sb.mockAccountExtraDataStateGetter, nil
 }
@@ -548,7 +1018,7 @@ func (sb *StubBackend) IsAuthorized(authToken *proto.PreAuthenticatedAuthenticat
 	panic("implement me")
 }
 
-func (sb *StubBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header) (*vm.EVM, func() error, error) {
+func (sb *StubBackend) GetEVM(ctx context.Context, msg core.Message, state vm.MinimalApiState, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
 	sb.getEVMCalled = true
 	vmCtx := core.NewEVMContext(msg, &types.Header{
 		Coinbase:   arbitraryFrom,
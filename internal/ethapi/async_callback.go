@@ -0,0 +1,235 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+// Delivery of eth_sendTransactionAsync callbacks. Callbacks are persisted before being
+// delivered so that they survive a restart of the node, and failed deliveries are retried
+// with an exponential backoff until asyncCallbackMaxAttempts is reached. The callback
+// headers may carry credentials, they are kept in memory only: a callback with headers
+// left over from a previous run is dropped instead of being sent without them.
+
+var (
+	asyncCallbackTimeout       = 30 * time.Second // timeout of a single delivery attempt
+	asyncCallbackRetryInterval = time.Second      // delay before the first retry, doubled on each failure
+	asyncCallbackMaxInterval   = 5 * time.Minute  // upper bound of the delay between retries
+)
+
+const asyncCallbackMaxAttempts = 10
+
+// asyncCallback is a pending callback, stored as JSON in the database.
+type asyncCallback struct {
+	Url        string            `json:"url"`
+	Headers    map[string]string `json:"-"`
+	HasHeaders bool              `json:"hasHeaders,omitempty"` // the headers were not persisted
+	Payload    json.RawMessage   `json:"payload"`
+	Attempts   int               `json:"attempts"`
+}
+
+// AsyncCallbackOutbox persists and delivers eth_sendTransactionAsync callbacks. It is
+// owned by the Ethereum service, which starts and stops it with the node.
+type AsyncCallbackOutbox struct {
+	db     ethdb.KeyValueStore
+	client *http.Client
+
+	ctx  context.Context // cancelled on Stop, interrupts the deliveries
+	quit context.CancelFunc
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	lastId  uint64
+	stopped bool
+}
+
+// NewAsyncCallbackOutbox creates an outbox storing the pending callbacks in db.
+func NewAsyncCallbackOutbox(db ethdb.KeyValueStore) *AsyncCallbackOutbox {
+	ctx, quit := context.WithCancel(context.Background())
+	return &AsyncCallbackOutbox{
+		db:     db,
+		client: &http.Client{Timeout: asyncCallbackTimeout},
+		ctx:    ctx,
+		quit:   quit,
+	}
+}
+
+// validateCallbackUrl makes sure callbacks are only sent to http or https URLs.
+func validateCallbackUrl(callbackUrl string) error {
+	u, err := url.Parse(callbackUrl)
+	if err != nil {
+		return fmt.Errorf("invalid callbackUrl: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid callbackUrl: unsupported scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+// Start resumes the delivery of the callbacks left over from a previous run.
+func (o *AsyncCallbackOutbox) Start() {
+	pending := rawdb.ReadAsyncCallbacks(o.db)
+	if len(pending) > 0 {
+		log.Info("Resuming delivery of async transaction callbacks", "count", len(pending))
+	}
+	for id, data := range pending {
+		var cb asyncCallback
+		if err := json.Unmarshal(data, &cb); err != nil {
+			log.Error("Discarding invalid async transaction callback", "id", id, "err", err)
+			rawdb.DeleteAsyncCallback(o.db, id)
+			continue
+		}
+		if cb.HasHeaders {
+			log.Warn("Discarding async transaction callback whose headers were lost on restart", "id", id, "url", cb.Url)
+			rawdb.DeleteAsyncCallback(o.db, id)
+			continue
+		}
+		o.mu.Lock()
+		if id > o.lastId {
+			o.lastId = id
+		}
+		o.mu.Unlock()
+		o.start(id, &cb)
+	}
+}
+
+// Stop interrupts the deliveries and waits for them to return. The callbacks not
+// delivered yet stay persisted and are resumed on the next Start.
+func (o *AsyncCallbackOutbox) Stop() {
+	o.mu.Lock()
+	o.stopped = true
+	o.mu.Unlock()
+	o.quit()
+	o.wg.Wait()
+}
+
+// start delivers the callback in the background, unless the outbox is stopped.
+func (o *AsyncCallbackOutbox) start(id uint64, cb *asyncCallback) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.stopped {
+		return
+	}
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.deliver(id, cb)
+	}()
+}
+
+// enqueue persists the callback and starts delivering it.
+func (o *AsyncCallbackOutbox) enqueue(callbackUrl string, headers map[string]string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	cb := &asyncCallback{Url: callbackUrl, Headers: headers, HasHeaders: len(headers) > 0, Payload: data}
+	id := o.nextId()
+	if err := o.store(id, cb); err != nil {
+		return err
+	}
+	o.start(id, cb)
+	return nil
+}
+
+// nextId returns a unique, increasing id, also across restarts.
+func (o *AsyncCallbackOutbox) nextId() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	id := uint64(time.Now().UnixNano())
+	if id <= o.lastId {
+		id = o.lastId + 1
+	}
+	o.lastId = id
+	return id
+}
+
+func (o *AsyncCallbackOutbox) store(id uint64, cb *asyncCallback) error {
+	data, err := json.Marshal(cb)
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteAsyncCallback(o.db, id, data)
+}
+
+// deliver posts the callback until it succeeds, the maximum number of attempts is reached
+// or the outbox is stopped.
+func (o *AsyncCallbackOutbox) deliver(id uint64, cb *asyncCallback) {
+	for {
+		err := o.post(cb)
+		if err == nil {
+			break
+		}
+		if o.ctx.Err() != nil {
+			return
+		}
+		cb.Attempts++
+		if cb.Attempts >= asyncCallbackMaxAttempts {
+			log.Error("Giving up sending async transaction callback", "url", cb.Url, "attempts", cb.Attempts, "err", err)
+			break
+		}
+		delay := asyncCallbackRetryInterval << uint(cb.Attempts-1)
+		if delay > asyncCallbackMaxInterval || delay <= 0 {
+			delay = asyncCallbackMaxInterval
+		}
+		log.Warn("Error sending async transaction callback, retrying", "url", cb.Url, "attempts", cb.Attempts, "retryIn", delay, "err", err)
+		if err := o.store(id, cb); err != nil {
+			log.Error("Error persisting async transaction callback", "err", err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-o.ctx.Done():
+			return
+		}
+	}
+	if err := rawdb.DeleteAsyncCallback(o.db, id); err != nil {
+		log.Error("Error deleting async transaction callback", "err", err)
+	}
+}
+
+func (o *AsyncCallbackOutbox) post(cb *asyncCallback) error {
+	req, err := http.NewRequestWithContext(o.ctx, http.MethodPost, cb.Url, bytes.NewReader(cb.Payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range cb.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
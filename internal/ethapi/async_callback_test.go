@@ -0,0 +1,135 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncCallbackOutbox_whenDeliveryFails_thenRetries(t *testing.T) {
+	defer func(interval time.Duration) { asyncCallbackRetryInterval = interval }(asyncCallbackRetryInterval)
+	asyncCallbackRetryInterval = 10 * time.Millisecond
+
+	var calls int32
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- body
+	}))
+	defer srv.Close()
+
+	db := rawdb.NewMemoryDatabase()
+	outbox := NewAsyncCallbackOutbox(db)
+	defer outbox.Stop()
+	err := outbox.enqueue(srv.URL, map[string]string{"Authorization": "Bearer token"}, &AsyncResultSuccess{Id: "1", TxHash: common.Hash{1}})
+	assert.NoError(t, err)
+
+	select {
+	case body := <-received:
+		var result AsyncResultSuccess
+		assert.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, AsyncResultSuccess{Id: "1", TxHash: common.Hash{1}}, result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback not delivered")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Eventually(t, func() bool { return len(rawdb.ReadAsyncCallbacks(db)) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestAsyncCallbackOutbox_whenRestarted_thenResumesDelivery(t *testing.T) {
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- body
+	}))
+	defer srv.Close()
+
+	db := rawdb.NewMemoryDatabase()
+	data, _ := json.Marshal(&asyncCallback{Url: srv.URL, Payload: json.RawMessage(`{"error":"arbitrary error"}`), Attempts: 2})
+	assert.NoError(t, rawdb.WriteAsyncCallback(db, 1, data))
+
+	outbox := NewAsyncCallbackOutbox(db)
+	defer outbox.Stop()
+	outbox.Start()
+
+	select {
+	case body := <-received:
+		assert.JSONEq(t, `{"error":"arbitrary error"}`, string(body))
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback not delivered")
+	}
+	assert.Eventually(t, func() bool { return len(rawdb.ReadAsyncCallbacks(db)) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestAsyncCallbackOutbox_whenCallbackHasHeaders_thenHeadersNotPersisted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	db := rawdb.NewMemoryDatabase()
+	outbox := NewAsyncCallbackOutbox(db)
+	assert.NoError(t, outbox.enqueue(srv.URL, map[string]string{"Authorization": "Bearer token"}, &AsyncResultFailure{Id: "1", Error: "arbitrary error"}))
+	outbox.Stop()
+
+	pending := rawdb.ReadAsyncCallbacks(db)
+	assert.Len(t, pending, 1)
+	for _, data := range pending {
+		assert.NotContains(t, string(data), "Bearer token")
+	}
+
+	// the callback can't be sent without its headers after a restart
+	restarted := NewAsyncCallbackOutbox(db)
+	defer restarted.Stop()
+	restarted.Start()
+	assert.Empty(t, rawdb.ReadAsyncCallbacks(db))
+}
+
+func TestAsyncCallbackOutbox_whenStopped_thenRetryInterrupted(t *testing.T) {
+	defer func(interval time.Duration) { asyncCallbackRetryInterval = interval }(asyncCallbackRetryInterval)
+	asyncCallbackRetryInterval = time.Hour
+
+	failed := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		failed <- struct{}{}
+	}))
+	defer srv.Close()
+
+	db := rawdb.NewMemoryDatabase()
+	outbox := NewAsyncCallbackOutbox(db)
+	assert.NoError(t, outbox.enqueue(srv.URL, nil, &AsyncResultSuccess{Id: "1", TxHash: common.Hash{1}}))
+	<-failed
+
+	stopped := make(chan struct{})
+	go func() {
+		outbox.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry not interrupted")
+	}
+	assert.Len(t, rawdb.ReadAsyncCallbacks(db), 1, "the callback must be resumed on the next start")
+}
+
+func TestValidateCallbackUrl(t *testing.T) {
+	assert.NoError(t, validateCallbackUrl("https://example.com/callback"))
+	assert.NoError(t, validateCallbackUrl("http://localhost:8080"))
+	assert.Error(t, validateCallbackUrl("ftp://example.com"))
+	assert.Error(t, validateCallbackUrl("://"))
+}
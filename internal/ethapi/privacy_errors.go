@@ -0,0 +1,77 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"errors"
+	"net"
+
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/ethereum/go-ethereum/private/engine"
+)
+
+// Quorum
+// JSON-RPC error codes of the privacy failures, so that clients can tell the failures
+// worth retrying (the private transaction manager is unavailable) from the others.
+const (
+	ErrCodeNotParty            = -32010
+	ErrCodePTMUnavailable      = -32011
+	ErrCodePayloadNotFound     = -32012
+	ErrCodePrivacyFlagMismatch = -32013
+)
+
+var (
+	errPTMNotEnabled   = errors.New("PrivateTransactionManager is not enabled")
+	errPayloadNotFound = errors.New("private payload not found")
+)
+
+// privacyError is an API error of a private transaction with a dedicated JSON error code.
+type privacyError struct {
+	error
+	code int
+}
+
+// ErrorCode returns the JSON error code of the privacy failure.
+func (e *privacyError) ErrorCode() int {
+	return e.code
+}
+
+func (e *privacyError) Unwrap() error {
+	return e.error
+}
+
+func newPrivacyError(code int, err error) error {
+	return &privacyError{error: err, code: code}
+}
+
+// toPrivacyError gives the dedicated JSON error code to the known failures of the
+// private transaction manager and of the privacy checks, other errors are returned as-is.
+func toPrivacyError(err error) error {
+	var (
+		pErr   *privacyError
+		netErr net.Error
+	)
+	switch {
+	case err == nil, errors.As(err, &pErr):
+		return err
+	case errors.Is(err, multitenancy.ErrNotAuthorized):
+		return newPrivacyError(ErrCodeNotParty, err)
+	case errors.Is(err, engine.ErrPrivateTxManagerNotinUse), errors.Is(err, engine.ErrPrivateTxManagerNotReady), errors.As(err, &netErr):
+		return newPrivacyError(ErrCodePTMUnavailable, err)
+	}
+	return err
+}
@@ -36,9 +36,11 @@ var Modules = map[string]string{
 	"lespay":           LESPayJs,
 	"raft":             Raft_JS,
 	"istanbul":         Istanbul_JS,
+	"quorum":           Quorum_JS,
 	"quorumPermission": QUORUM_NODE_JS,
 	"quorumExtension":  Extension_JS,
 	"plugin_account":   Account_Plugin_Js,
+	"chaos":            Chaos_JS,
 }
 
 const ChequebookJs = `
@@ -161,6 +163,49 @@ web3._extend({
 			call: 'admin_reloadPlugin',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'setPluginLogLevel',
+			call: 'admin_setPluginLogLevel',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'setLogLevel',
+			call: 'admin_setLogLevel',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'reloadNodeLists',
+			call: 'admin_reloadNodeLists'
+		}),
+		new web3._extend.Method({
+			name: 'dbStats',
+			call: 'admin_dbStats'
+		}),
+		new web3._extend.Method({
+			name: 'securityAdvisories',
+			call: 'admin_securityAdvisories',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'enterMaintenance',
+			call: 'admin_enterMaintenance',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'exitMaintenance',
+			call: 'admin_exitMaintenance'
+		}),
+		new web3._extend.Method({
+			name: 'maintenance',
+			call: 'admin_maintenance'
+		}),
+		new web3._extend.Method({
+			name: 'listAvailablePluginVersions',
+			call: 'admin_listAvailablePluginVersions',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'addPeer',
 			call: 'admin_addPeer',
@@ -181,6 +226,21 @@ web3._extend({
 			call: 'admin_removeTrustedPeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'backup',
+			call: 'admin_backup',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'pauseMinting',
+			call: 'admin_pauseMinting',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'resumeMinting',
+			call: 'admin_resumeMinting'
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -227,6 +287,10 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'peerStats',
+			getter: 'admin_peerStats'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
@@ -493,6 +557,11 @@ web3._extend({
 			call: 'debug_freezeClient',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'explainAuthorization',
+			call: 'debug_explainAuthorization',
+			params: 2,
+		}),
 	],
 	properties: []
 });
@@ -536,6 +605,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'createAccessList',
+			call: 'eth_createAccessList',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'estimateGas',
 			call: 'eth_estimateGas',
@@ -616,6 +691,18 @@ web3._extend({
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getCreate2Address',
+			call: 'eth_getCreate2Address',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getLogsPaginated',
+			call: 'eth_getLogsPaginated',
+			params: 3,
+			inputFormatter: [null, web3._extend.utils.fromDecimal, null]
+		}),
 		new web3._extend.Method({
 			name: 'getPSI',
 			call: 'eth_getPSI',
@@ -757,7 +844,13 @@ web3._extend({
 const RpcJs = `
 web3._extend({
 	property: 'rpc',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'discover',
+			call: 'rpc_discover',
+			params: 0
+		}),
+	],
 	properties: [
 		new web3._extend.Property({
 			name: 'modules',
@@ -1013,6 +1106,24 @@ web3._extend({
                        params: 4,
                        inputFormatter: [null, null, null, null]
                }),
+               new web3._extend.Method({
+                       name: 'createApiKey',
+                       call: 'quorumPermission_createApiKey',
+                       params: 3,
+                       inputFormatter: [null, null, web3._extend.formatters.inputTransactionFormatter]
+               }),
+               new web3._extend.Method({
+                       name: 'revokeApiKey',
+                       call: 'quorumPermission_revokeApiKey',
+                       params: 3,
+                       inputFormatter: [null, null, web3._extend.formatters.inputTransactionFormatter]
+               }),
+               new web3._extend.Method({
+                       name: 'apiKeyList',
+                       call: 'quorumPermission_apiKeyList',
+                       params: 1,
+                       inputFormatter: [null]
+               }),
 
        ],
        properties:
@@ -1098,6 +1209,16 @@ web3._extend({
 			params: 1,
             inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'registerKeyRotation',
+			call: 'istanbul_registerKeyRotation',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'cancelKeyRotation',
+			call: 'istanbul_cancelKeyRotation',
+			params: 0
+		}),
 
 	],
 	properties:
@@ -1110,6 +1231,10 @@ web3._extend({
 			name: 'nodeAddress',
 			getter: 'istanbul_nodeAddress'
 		}),
+		new web3._extend.Property({
+			name: 'keyRotation',
+			getter: 'istanbul_keyRotation'
+		}),
 	]
 });
 `
@@ -1183,6 +1308,106 @@ web3._extend({
 });
 `
 
+const Quorum_JS = `
+web3._extend({
+	property: 'quorum',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'resendPrivatePayloads',
+			call: 'quorum_resendPrivatePayloads',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'cancelResendPrivatePayloads',
+			call: 'quorum_cancelResendPrivatePayloads'
+		}),
+		new web3._extend.Method({
+			name: 'getPrivatePayloadInclusions',
+			call: 'quorum_getPrivatePayloadInclusions',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getPrivatePayloadsByBlock',
+			call: 'quorum_getPrivatePayloadsByBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'nodeInfo',
+			getter: 'quorum_nodeInfo'
+		}),
+		new web3._extend.Property({
+			name: 'privatePayloadsResendStatus',
+			getter: 'quorum_privatePayloadsResendStatus'
+		}),
+		new web3._extend.Property({
+			name: 'blockMakerStatus',
+			getter: 'quorum_blockMakerStatus'
+		}),
+	]
+});
+`
+
+const Chaos_JS = `
+web3._extend({
+	property: 'chaos',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'setSeed',
+			call: 'chaos_setSeed',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setRule',
+			call: 'chaos_setRule',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'removeRule',
+			call: 'chaos_removeRule',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'partition',
+			call: 'chaos_partition',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'heal',
+			call: 'chaos_heal',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'crashBlockMaker',
+			call: 'chaos_crashBlockMaker',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'restartBlockMaker',
+			call: 'chaos_restartBlockMaker',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'reset',
+			call: 'chaos_reset',
+			params: 0
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'status',
+			getter: 'chaos_status'
+		}),
+	]
+});
+`
+
 const Extension_JS = `
 web3._extend({
 	property: 'quorumExtension',
@@ -72,6 +72,9 @@ type LightEthereum struct {
 	netRPCService  *ethapi.PublicNetAPI
 
 	p2pServer *p2p.Server
+
+	asyncCallbacks *ethapi.AsyncCallbackOutbox // Quorum
+	filterAPI      *filters.PublicFilterAPI    // Quorum
 }
 
 // New creates an instance of the light client.
@@ -162,12 +165,14 @@ func New(stack *node.Node, config *eth.Config) (*LightEthereum, error) {
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 
+	leth.asyncCallbacks = ethapi.NewAsyncCallbackOutbox(chainDb) // Quorum
 	leth.ApiBackend = &LesApiBackend{stack.Config().ExtRPCEnabled(), leth, nil}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
 		gpoParams.Default = config.Miner.GasPrice
 	}
 	leth.ApiBackend.gpo = gasprice.NewOracle(leth.ApiBackend, gpoParams)
+	leth.filterAPI = filters.NewPublicFilterAPI(leth.ApiBackend, true) // Quorum
 
 	leth.handler = newClientHandler(config.UltraLightServers, config.UltraLightFraction, checkpoint, leth)
 	if leth.handler.ulc != nil {
@@ -243,7 +248,7 @@ func (s *LightEthereum) APIs() []rpc.API {
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   filters.NewPublicFilterAPI(s.ApiBackend, true),
+			Service:   s.filterAPI,
 			Public:    true,
 		}, {
 			Namespace: "net",
@@ -295,6 +300,8 @@ func (s *LightEthereum) Start() error {
 	s.wg.Add(bloomServiceThreads)
 	s.startBloomHandlers(params.BloomBitsBlocksClient)
 	s.handler.start()
+	s.asyncCallbacks.Start() // Quorum
+	s.filterAPI.RestoreFilters()
 
 	return nil
 }
@@ -303,6 +310,7 @@ func (s *LightEthereum) Start() error {
 // Ethereum protocol.
 func (s *LightEthereum) Stop() error {
 	close(s.closeCh)
+	s.asyncCallbacks.Stop() // Quorum
 	s.serverPool.stop()
 	s.valueTracker.Stop()
 	s.peers.close()
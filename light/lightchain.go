@@ -30,6 +30,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -528,6 +529,14 @@ func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.Tru
 	if clique := lc.hc.Config().Clique; clique != nil {
 		latest -= latest % clique.Epoch // epoch snapshot for clique
 	}
+	// Quorum
+	if istanbulConfig := lc.hc.Config().Istanbul; istanbulConfig != nil {
+		epoch := istanbulConfig.Epoch
+		if epoch == 0 {
+			epoch = istanbul.DefaultConfig.Epoch
+		}
+		latest -= latest % epoch // epoch snapshot for istanbul
+	}
 	if head >= latest {
 		return true
 	}
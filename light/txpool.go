@@ -374,6 +374,19 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 		return core.ErrNegativeValue
 	}
 
+	// Quorum
+	if pool.config.IsQuorum {
+		// Gas price must be zero for Quorum transaction
+		if tx.GasPriceIntCmp(common.Big0) != 0 {
+			return core.ErrInvalidGasPrice
+		}
+		// Ether value is not currently supported on private transactions
+		if tx.IsPrivate() && (len(tx.Data()) == 0 || tx.Value().Sign() != 0) {
+			return core.ErrEtherValueUnsupported
+		}
+	}
+	// End Quorum
+
 	// Transactor should have enough funds to cover the costs
 	// cost == V + GP * GL
 	if b := currentState.GetBalance(from); b.Cmp(tx.Cost()) < 0 {
@@ -0,0 +1,71 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Quorum
+
+type contextKey struct{}
+
+const (
+	moduleKey    = "module"
+	modulePrefix = "github.com/ethereum/go-ethereum/"
+)
+
+// NewContext returns a copy of parent carrying the key/value pairs in ctx, in addition to
+// the ones already carried by parent. The pairs are added to the records logged with the
+// logger returned by FromContext, e.g.: to correlate the logs of a RPC request.
+func NewContext(parent context.Context, ctx ...interface{}) context.Context {
+	return context.WithValue(parent, contextKey{}, newContext(contextFields(parent), ctx))
+}
+
+// FromContext returns the root logger with the key/value pairs carried by ctx.
+func FromContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return root
+	}
+	return root.New(fields...)
+}
+
+func contextFields(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(contextKey{}).([]interface{})
+	return fields
+}
+
+// ModuleHandler adds to the records the module they are logged from, i.e.: the
+// package path relative to the repository root (e.g.: core/vm), and passes them to h.
+func ModuleHandler(h Handler) Handler {
+	return FuncHandler(func(r *Record) error {
+		r.Ctx = append(r.Ctx, moduleKey, callModule(r))
+		return h.Log(r)
+	})
+}
+
+func callModule(r *Record) string {
+	file := fmt.Sprintf("%+s", r.Call)
+	if i := strings.Index(file, modulePrefix); i >= 0 {
+		file = file[i+len(modulePrefix):]
+	}
+	return path.Dir(file)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
 )
 
 // Backend wraps all methods required for mining.
@@ -53,6 +54,9 @@ type Config struct {
 	Recommit               time.Duration  // The time interval for miner to re-create mining work.
 	Noverify               bool           // Disable remote mining solution verification(only useful in ethash).
 	AllowedFutureBlockTime uint64         // Max time (in seconds) from current time allowed for blocks, before they're considered future blocks
+
+	// Quorum
+	OrgQuotas *pcore.OrgQuotaConfig `toml:",omitempty"` // Shares of the blocks each organization may use
 }
 
 // Miner creates blocks and searches for proof-of-work values.
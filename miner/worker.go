@@ -30,12 +30,13 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/mps"
-	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/tracing"
 	"github.com/ethereum/go-ethereum/trie"
 )
 
@@ -97,6 +98,7 @@ type environment struct {
 	// Quorum
 	privateReceipts  []*types.Receipt
 	privateStateRepo mps.PrivateStateRepository
+	orgQuotas        *pcore.OrgQuotaTracker // nil unless the organizations have quotas
 	// End Quorum
 }
 
@@ -110,6 +112,7 @@ type task struct {
 	// Quorum
 	privateReceipts  []*types.Receipt
 	privateStateRepo mps.PrivateStateRepository
+	orgQuotas        *pcore.OrgQuotaTracker // nil unless the organizations have quotas
 	// End Quorum
 }
 
@@ -678,15 +681,11 @@ func (w *worker) resultLoop() {
 			allReceipts := task.privateStateRepo.MergeReceipts(pubReceipts, prvReceipts)
 
 			// Commit block and state to database.
-			_, err := w.chain.WriteBlockWithState(block, allReceipts, logs, task.state, task.privateStateRepo, true)
+			_, err := w.chain.WriteBlockWithState(block, allReceipts, task.privateReceipts, logs, task.state, task.privateStateRepo, true)
 			if err != nil {
 				log.Error("Failed writing block to chain", "err", err)
 				continue
 			}
-			if err := rawdb.WritePrivateBlockBloom(w.eth.ChainDb(), block.NumberU64(), task.privateReceipts); err != nil {
-				log.Error("Failed writing private block bloom", "err", err)
-				continue
-			}
 			log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealhash, "hash", hash,
 				"elapsed", common.PrettyDuration(time.Since(task.createdAt)))
 
@@ -716,6 +715,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 		uncles:           mapset.NewSet(),
 		header:           header,
 		privateStateRepo: privateStateRepo,
+		orgQuotas:        pcore.NewOrgQuotaTracker(w.config.OrgQuotas, header.GasLimit),
 	}
 
 	// when 08 is processed ancestors contain 07 (quick block)
@@ -889,6 +889,14 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			txs.Pop()
 			continue
 		}
+		// Quorum: leave the transactions of the account out once its org used its quota
+		org, allowed := w.current.orgQuotas.Allow(from, tx.Gas())
+		if !allowed {
+			log.Trace("Skipping account over the quota of its org", "sender", from, "org", org)
+			txs.Pop()
+			continue
+		}
+		gasUsed := w.current.header.GasUsed
 		// Start executing the transaction
 		logs, err := w.commitTransaction(tx, coinbase)
 		switch err {
@@ -911,6 +919,7 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
 			w.current.tcount++
+			w.current.orgQuotas.Record(org, w.current.header.GasUsed-gasUsed) // Quorum
 			txs.Shift()
 
 		default:
@@ -952,6 +961,10 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	tstart := time.Now()
 	parent := w.chain.CurrentBlock()
 
+	// Quorum
+	span := tracing.StartRoot("block.mint").SetAttribute("block.number", parent.NumberU64()+1)
+	defer span.End()
+
 	if parent.Time() >= uint64(timestamp) {
 		timestamp = int64(parent.Time() + 1)
 	}
@@ -1090,6 +1103,16 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 		return err
 	}
 	if w.isRunning() {
+		// Quorum
+		// only the blocks to be sealed are validated, the pending block is not
+		if err := w.chain.ValidateLocalBlock(block, receipts); err != nil {
+			log.Warn("Block rejected by validation, not sealing it", "number", block.Number(), "txs", w.current.tcount, "err", err)
+			if update {
+				w.updateSnapshot()
+			}
+			return nil
+		}
+		// End Quorum
 		if interval != nil {
 			interval()
 		}
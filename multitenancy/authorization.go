@@ -36,15 +36,8 @@ func IsAuthorized(authToken *proto.PreAuthenticatedAuthenticationToken, secAttri
 // isAuthorized performs authorization check for one security attribute against
 // the granted access inside the pre-authenticated access token.
 func isAuthorized(authToken *proto.PreAuthenticatedAuthenticationToken, attr *PrivateStateSecurityAttribute) (bool, error) {
-	query := url.Values{}
-	if attr.nodeEOA != nil {
-		query.Set(QueryNodeEOA, toHexAddress(attr.nodeEOA))
-	}
-	if attr.selfEOA != nil {
-		query.Set(QuerySelfEOA, toHexAddress(attr.selfEOA))
-	}
 	// construct the request
-	askValue, err := url.Parse(fmt.Sprintf("%s://%s?%s", SchemePSI, attr.psi, query.Encode()))
+	askValue, err := attr.askURL()
 	if err != nil {
 		return false, err
 	}
@@ -121,13 +114,38 @@ func toHexAddress(a *common.Address) string {
 }
 
 func match(ask, granted *url.URL) bool {
-	return strings.EqualFold(ask.Scheme, granted.Scheme) &&
-		strings.EqualFold(ask.Host, granted.Host) &&
-		matchQuery(ask.Query(), granted.Query())
+	return mismatch(ask, granted) == ""
+}
+
+// mismatch returns why the granted authority doesn't grant the ask, empty if it does
+func mismatch(ask, granted *url.URL) string {
+	if !strings.EqualFold(ask.Scheme, granted.Scheme) {
+		return fmt.Sprintf("scheme %q doesn't match %q", granted.Scheme, ask.Scheme)
+	}
+	if !strings.EqualFold(ask.Host, granted.Host) {
+		return fmt.Sprintf("private state %q doesn't match %q", granted.Host, ask.Host)
+	}
+	return mismatchQuery(ask.Query(), granted.Query())
 }
 
-func matchQuery(ask, granted url.Values) bool {
-	return matchEOA(granted[QueryNodeEOA], ask[QueryNodeEOA]) || matchEOA(granted[QuerySelfEOA], ask[QuerySelfEOA])
+func mismatchQuery(ask, granted url.Values) string {
+	if matchEOA(granted[QueryNodeEOA], ask[QueryNodeEOA]) || matchEOA(granted[QuerySelfEOA], ask[QuerySelfEOA]) {
+		return ""
+	}
+	var reasons []string
+	for _, param := range []string{QueryNodeEOA, QuerySelfEOA} {
+		switch {
+		case len(ask[param]) == 0:
+		case len(granted[param]) == 0:
+			reasons = append(reasons, fmt.Sprintf("%s is not granted", param))
+		default:
+			reasons = append(reasons, fmt.Sprintf("%s %v doesn't grant %v", param, granted[param], ask[param]))
+		}
+	}
+	if len(reasons) == 0 {
+		return fmt.Sprintf("neither %s nor %s is asked", QueryNodeEOA, QuerySelfEOA)
+	}
+	return strings.Join(reasons, ", ")
 }
 
 func matchEOA(grantedEOAs []string, askEOAs []string) bool {
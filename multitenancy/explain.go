@@ -0,0 +1,49 @@
+package multitenancy
+
+import (
+	"net/url"
+
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+)
+
+// AuthorityCheck is the outcome of matching a granted authority with a security attribute
+type AuthorityCheck struct {
+	Authority string `json:"authority"`
+	Matched   bool   `json:"matched"`
+	// Reason is why the authority doesn't grant the security attribute, e.g.: a
+	// scheme, private state or EOA mismatch
+	Reason string `json:"reason,omitempty"`
+}
+
+// Explanation details the authorization check of a security attribute
+type Explanation struct {
+	Ask        string           `json:"ask"` // the security attribute, as an authority URL
+	Authorized bool             `json:"authorized"`
+	Checks     []AuthorityCheck `json:"checks"` // one per granted authority, in order
+}
+
+// ExplainAuthorization performs the authorization check of IsAuthorized for each
+// security attribute, reporting which granted authority matched or why each one
+// didn't. It helps writing the authorities of the access tokens.
+func ExplainAuthorization(authToken *proto.PreAuthenticatedAuthenticationToken, secAttributes ...*PrivateStateSecurityAttribute) ([]*Explanation, error) {
+	explanations := make([]*Explanation, len(secAttributes))
+	for i, attr := range secAttributes {
+		askValue, err := attr.askURL()
+		if err != nil {
+			return nil, err
+		}
+		explanation := &Explanation{Ask: askValue.String(), Checks: make([]AuthorityCheck, 0)}
+		for _, granted := range authToken.GetAuthorities() {
+			check := AuthorityCheck{Authority: granted.GetRaw()}
+			if grantedValue, err := url.Parse(granted.GetRaw()); err != nil {
+				check.Reason = "invalid authority: " + err.Error()
+			} else if check.Reason = mismatch(askValue, grantedValue); check.Reason == "" {
+				check.Matched = true
+				explanation.Authorized = true
+			}
+			explanation.Checks = append(explanation.Checks, check)
+		}
+		explanations[i] = explanation
+	}
+	return explanations, nil
+}
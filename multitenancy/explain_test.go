@@ -0,0 +1,52 @@
+package multitenancy
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainAuthorization(t *testing.T) {
+	granted := []string{
+		"rpc://eth_*",
+		"psi://arbitrary.ps2?node.eoa=0x0",
+		"psi://arbitrary.ps1?self.eoa=0x000000000000000000000000000000000000aaaa",
+		"psi://arbitrary.ps1?node.eoa=0x0",
+		"psi://%zz",
+	}
+	ask := (&PrivateStateSecurityAttribute{}).
+		WithPSI("arbitrary.ps1").
+		WithNodeEOA(common.HexToAddress("0x000000000000000000000000000000000000bbbb"))
+
+	explanations, err := ExplainAuthorization(toToken(granted), ask)
+
+	require.NoError(t, err)
+	require.Len(t, explanations, 1)
+	explanation := explanations[0]
+	assert.Equal(t, "psi://arbitrary.ps1?node.eoa=0x000000000000000000000000000000000000bbbb", explanation.Ask)
+	assert.True(t, explanation.Authorized)
+	require.Len(t, explanation.Checks, 5)
+	assert.Equal(t, `scheme "rpc" doesn't match "psi"`, explanation.Checks[0].Reason)
+	assert.Equal(t, `private state "arbitrary.ps2" doesn't match "arbitrary.ps1"`, explanation.Checks[1].Reason)
+	assert.Equal(t, "node.eoa is not granted", explanation.Checks[2].Reason)
+	assert.Equal(t, AuthorityCheck{Authority: granted[3], Matched: true}, explanation.Checks[3])
+	assert.Contains(t, explanation.Checks[4].Reason, "invalid authority")
+
+	authorized, err := IsAuthorized(toToken(granted), ask)
+	assert.NoError(t, err)
+	assert.Equal(t, authorized, explanation.Authorized)
+}
+
+func TestExplainAuthorization_whenEOAMismatch(t *testing.T) {
+	ask := (&PrivateStateSecurityAttribute{}).
+		WithPSI("arbitrary.ps1").
+		WithSelfEOA(common.HexToAddress("0x000000000000000000000000000000000000bbbb"))
+
+	explanations, err := ExplainAuthorization(toToken([]string{"psi://arbitrary.ps1?self.eoa=0x000000000000000000000000000000000000aaaa"}), ask)
+
+	require.NoError(t, err)
+	assert.False(t, explanations[0].Authorized)
+	assert.Equal(t, "self.eoa [0x000000000000000000000000000000000000aaaa] doesn't grant [0x000000000000000000000000000000000000bbbb]", explanations[0].Checks[0].Reason)
+}
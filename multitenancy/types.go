@@ -2,6 +2,7 @@ package multitenancy
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -34,6 +35,18 @@ func (pssa *PrivateStateSecurityAttribute) String() string {
 	return fmt.Sprintf("psi=%s node.eoa=%s self.eoa=%s", pssa.psi, toHexAddress(pssa.nodeEOA), toHexAddress(pssa.selfEOA))
 }
 
+// askURL returns the security attribute as an authority URL, matched with the granted ones
+func (pssa *PrivateStateSecurityAttribute) askURL() (*url.URL, error) {
+	query := url.Values{}
+	if pssa.nodeEOA != nil {
+		query.Set(QueryNodeEOA, toHexAddress(pssa.nodeEOA))
+	}
+	if pssa.selfEOA != nil {
+		query.Set(QuerySelfEOA, toHexAddress(pssa.selfEOA))
+	}
+	return url.Parse(fmt.Sprintf("%s://%s?%s", SchemePSI, pssa.psi, query.Encode()))
+}
+
 func (pssa *PrivateStateSecurityAttribute) WithPSI(psi types.PrivateStateIdentifier) *PrivateStateSecurityAttribute {
 	pssa.psi = psi
 	return pssa
@@ -0,0 +1,265 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/plugin"
+)
+
+const (
+	// QuorumComponent is the component of the advisories about Quorum itself,
+	// the advisories about a plugin use the name of the plugin.
+	QuorumComponent = "quorum"
+
+	// DefaultAdvisoryInterval is how often the advisory feed is checked when
+	// no interval is configured.
+	DefaultAdvisoryInterval = 24 * time.Hour
+
+	advisoryFetchTimeout = 30 * time.Second
+	advisoryFeedLimit    = 4 * 1024 * 1024
+)
+
+var (
+	advisoryGauge      = metrics.NewRegisteredGauge("advisory/security", nil)
+	endOfLifeGauge     = metrics.NewRegisteredGauge("advisory/endoflife", nil)
+	advisoryErrorMeter = metrics.NewRegisteredMeter("advisory/errors", nil)
+)
+
+// AdvisoryFeed is the document published at the advisory feed URL.
+type AdvisoryFeed struct {
+	Advisories []*Advisory  `json:"advisories"`
+	EndOfLife  []*EndOfLife `json:"endOfLife"`
+}
+
+// Advisory is a known security issue of the versions of a component from
+// Introduced (inclusive) to Fixed (exclusive). An empty bound leaves the range
+// open on this side.
+type Advisory struct {
+	ID         string         `json:"id"`
+	Component  string         `json:"component"`
+	Severity   string         `json:"severity,omitempty"`
+	Summary    string         `json:"summary,omitempty"`
+	Link       string         `json:"link,omitempty"`
+	Introduced plugin.Version `json:"introduced,omitempty"`
+	Fixed      plugin.Version `json:"fixed,omitempty"`
+}
+
+// EndOfLife states that the versions of a component lower than Before are no
+// longer supported since Date.
+type EndOfLife struct {
+	Component string         `json:"component"`
+	Before    plugin.Version `json:"before"`
+	Date      string         `json:"date,omitempty"`
+	Link      string         `json:"link,omitempty"`
+}
+
+func (a *Advisory) affects(version plugin.Version) bool {
+	if len(a.Introduced) > 0 && plugin.CompareVersions(version, a.Introduced) < 0 {
+		return false
+	}
+	return len(a.Fixed) == 0 || plugin.CompareVersions(version, a.Fixed) < 0
+}
+
+func (e *EndOfLife) affects(version plugin.Version) bool {
+	return plugin.CompareVersions(version, e.Before) < 0
+}
+
+// AdvisoryFinding is an advisory or an end of life notice applying to the
+// running version of a component.
+type AdvisoryFinding struct {
+	Component string         `json:"component"`
+	Version   plugin.Version `json:"version"`
+	Advisory  *Advisory      `json:"advisory,omitempty"`
+	EndOfLife *EndOfLife     `json:"endOfLife,omitempty"`
+}
+
+func (f *AdvisoryFinding) key() string {
+	if f.Advisory != nil {
+		return f.Component + "/" + f.Advisory.ID
+	}
+	return f.Component + "/eol/" + string(f.EndOfLife.Before)
+}
+
+// AdvisoryReport is the result of the last check of the advisory feed.
+type AdvisoryReport struct {
+	Feed      string             `json:"feed"`
+	CheckedAt time.Time          `json:"checkedAt"`
+	Error     string             `json:"error,omitempty"`
+	Findings  []*AdvisoryFinding `json:"findings"`
+}
+
+// MatchAdvisories returns the advisories and end of life notices of the feed
+// applying to the given versions of the components.
+func MatchAdvisories(feed *AdvisoryFeed, components map[string]plugin.Version) []*AdvisoryFinding {
+	findings := make([]*AdvisoryFinding, 0)
+	for _, a := range feed.Advisories {
+		if version, ok := components[a.Component]; ok && a.affects(version) {
+			findings = append(findings, &AdvisoryFinding{Component: a.Component, Version: version, Advisory: a})
+		}
+	}
+	for _, e := range feed.EndOfLife {
+		if version, ok := components[e.Component]; ok && e.affects(version) {
+			findings = append(findings, &AdvisoryFinding{Component: e.Component, Version: version, EndOfLife: e})
+		}
+	}
+	return findings
+}
+
+// fetchAdvisoryFeed reads the feed from an http(s) URL, or else from a local
+// file for nodes without internet access.
+func fetchAdvisoryFeed(url string) (*AdvisoryFeed, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		client := &http.Client{Timeout: advisoryFetchTimeout}
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("advisory feed returned %s", resp.Status)
+		}
+		data, err = ioutil.ReadAll(io.LimitReader(resp.Body, advisoryFeedLimit))
+		if err != nil {
+			return nil, err
+		}
+	} else if data, err = ioutil.ReadFile(strings.TrimPrefix(url, "file://")); err != nil {
+		return nil, err
+	}
+	feed := new(AdvisoryFeed)
+	if err := json.Unmarshal(data, feed); err != nil {
+		return nil, fmt.Errorf("invalid advisory feed: %v", err)
+	}
+	return feed, nil
+}
+
+// advisoryMonitor periodically checks the running Quorum version and the
+// installed plugins against the advisory feed, and warns about the findings.
+type advisoryMonitor struct {
+	node     *Node
+	url      string
+	interval time.Duration
+
+	lock   sync.RWMutex
+	report *AdvisoryReport
+	warned map[string]bool // findings already logged, so that each is logged once
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newAdvisoryMonitor(node *Node) *advisoryMonitor {
+	interval := node.config.AdvisoryInterval
+	if interval <= 0 {
+		interval = DefaultAdvisoryInterval
+	}
+	return &advisoryMonitor{
+		node:     node,
+		url:      node.config.AdvisoryFeed,
+		interval: interval,
+		warned:   make(map[string]bool),
+		quit:     make(chan struct{}),
+	}
+}
+
+func (m *advisoryMonitor) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *advisoryMonitor) stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+func (m *advisoryMonitor) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// components returns the running versions of Quorum and of the plugins.
+func (m *advisoryMonitor) components() map[string]plugin.Version {
+	components := map[string]plugin.Version{QuorumComponent: plugin.Version(params.QuorumVersion)}
+	if pm := m.node.PluginManager(); pm != nil {
+		for name, version := range pm.InstalledVersions() {
+			components[name] = version
+		}
+	}
+	return components
+}
+
+// check fetches the feed and updates the report, the metrics and the logs. A
+// feed which can't be fetched keeps the findings of the previous check.
+func (m *advisoryMonitor) check() *AdvisoryReport {
+	report := &AdvisoryReport{Feed: m.url, CheckedAt: time.Now()}
+	feed, err := fetchAdvisoryFeed(m.url)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err != nil {
+		log.Warn("Failed to check the security advisories", "feed", m.url, "err", err)
+		advisoryErrorMeter.Mark(1)
+		report.Error = err.Error()
+		if m.report != nil {
+			report.Findings = m.report.Findings
+		}
+		m.report = report
+		return report
+	}
+	report.Findings = MatchAdvisories(feed, m.components())
+
+	var advisories, endOfLife int64
+	for _, f := range report.Findings {
+		if f.Advisory != nil {
+			advisories++
+		} else {
+			endOfLife++
+		}
+		if m.warned[f.key()] {
+			continue
+		}
+		m.warned[f.key()] = true
+		if f.Advisory != nil {
+			log.Warn("Security advisory affects the running version", "component", f.Component, "version", f.Version,
+				"id", f.Advisory.ID, "severity", f.Advisory.Severity, "fixed", f.Advisory.Fixed, "summary", f.Advisory.Summary, "link", f.Advisory.Link)
+		} else {
+			log.Warn("Running version has reached end of life", "component", f.Component, "version", f.Version,
+				"date", f.EndOfLife.Date, "link", f.EndOfLife.Link)
+		}
+	}
+	advisoryGauge.Update(advisories)
+	endOfLifeGauge.Update(endOfLife)
+	m.report = report
+	return report
+}
+
+// lastReport returns the report of the last check, nil before the first one.
+func (m *advisoryMonitor) lastReport() *AdvisoryReport {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.report
+}
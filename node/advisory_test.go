@@ -0,0 +1,89 @@
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchAdvisories(t *testing.T) {
+	feed := &AdvisoryFeed{
+		Advisories: []*Advisory{
+			{ID: "fixed-before", Component: QuorumComponent, Fixed: "21.1.0"},
+			{ID: "affected", Component: QuorumComponent, Introduced: "20.10.0", Fixed: "21.4.3"},
+			{ID: "introduced-after", Component: QuorumComponent, Introduced: "21.7.0"},
+			{ID: "plugin", Component: "quorum-security-plugin-enterprise", Fixed: "0.2.0"},
+		},
+		EndOfLife: []*EndOfLife{
+			{Component: QuorumComponent, Before: "21.1.0"},
+			{Component: "quorum-security-plugin-enterprise", Before: "0.1.1"},
+		},
+	}
+
+	findings := MatchAdvisories(feed, map[string]plugin.Version{
+		QuorumComponent:                     "21.4.2",
+		"quorum-security-plugin-enterprise": "0.1.0",
+	})
+
+	keys := make([]string, len(findings))
+	for i, f := range findings {
+		keys[i] = f.key()
+	}
+	assert.Equal(t, []string{
+		"quorum/affected",
+		"quorum-security-plugin-enterprise/plugin",
+		"quorum-security-plugin-enterprise/eol/0.1.1",
+	}, keys)
+}
+
+func TestAdvisoryMonitor_check(t *testing.T) {
+	feed := `{"advisories":[{"id":"QSA-1","component":"quorum","fixed":"999.0.0"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer server.Close()
+
+	stack, err := New(&Config{AdvisoryFeed: server.URL})
+	require.NoError(t, err)
+	defer stack.Close()
+
+	monitor := newAdvisoryMonitor(stack)
+	report := monitor.check()
+
+	assert.Empty(t, report.Error)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, plugin.Version(params.QuorumVersion), report.Findings[0].Version)
+	assert.True(t, monitor.warned["quorum/QSA-1"])
+
+	server.Close()
+	report = monitor.check()
+
+	assert.NotEmpty(t, report.Error)
+	assert.Len(t, report.Findings, 1, "findings must be kept when the feed is unreachable")
+	assert.Equal(t, report, monitor.lastReport())
+}
+
+func TestFetchAdvisoryFeed_fromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "feed.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"endOfLife":[{"component":"quorum","before":"2.7.0"}]}`), 0600))
+
+	feed, err := fetchAdvisoryFeed("file://" + path)
+
+	require.NoError(t, err)
+	assert.Len(t, feed.EndOfLife, 1)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not json"), 0600))
+	_, err = fetchAdvisoryFeed(path)
+	assert.Error(t, err)
+}
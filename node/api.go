@@ -20,10 +20,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/debug"
+	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -45,6 +49,10 @@ func (n *Node) apis() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   debug.Handler,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   &privateDebugAPI{n},
 		}, {
 			Namespace: "web3",
 			Version:   "1.0",
@@ -124,6 +132,104 @@ func (api *privateAdminAPI) RemoveTrustedPeer(url string) (bool, error) {
 	return true, nil
 }
 
+// Quorum
+// SetLogLevel changes at runtime the log verbosity of a module: consensus, permission,
+// private, plugin or a package path (e.g.: eth/downloader), without restarting the node.
+func (api *privateAdminAPI) SetLogLevel(module string, level string) (bool, error) {
+	if err := debug.Handler.SetModuleLevel(module, level); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Quorum
+// NodeListsReload is the result of admin_reloadNodeLists
+type NodeListsReload struct {
+	StaticAdded   int      `json:"staticAdded"`
+	StaticRemoved int      `json:"staticRemoved"`
+	Dropped       []string `json:"dropped"` // peers no longer permissioned
+}
+
+// Quorum
+// ReloadNodeLists applies the changes of static-nodes.json and permissioned-nodes.json
+// without restarting the node: the added static nodes are dialed, the removed ones are
+// disconnected as are the peers which are no longer permissioned.
+// static-nodes.json is ignored when the static nodes come from the TOML config, nothing
+// is changed if it can't be parsed.
+func (api *privateAdminAPI) ReloadNodeLists() (*NodeListsReload, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	result := &NodeListsReload{Dropped: []string{}}
+	if api.node.config.P2P.StaticNodes == nil {
+		nodes, err := api.node.config.LoadStaticNodes()
+		if err != nil {
+			return nil, err
+		}
+		result.StaticAdded, result.StaticRemoved = server.ReloadStaticNodes(nodes)
+	}
+	for _, n := range server.DropUnpermissionedPeers() {
+		result.Dropped = append(result.Dropped, n.URLv4())
+	}
+	return result, nil
+}
+
+// Quorum
+// DbStats returns the disk usage of the chaindata, freezer and raft folders of the node,
+// along with their warning thresholds.
+func (api *privateAdminAPI) DbStats() (map[string]*DiskUsage, error) {
+	return api.node.DiskUsage()
+}
+
+// Quorum
+// SecurityAdvisories returns the security advisories and end of life notices applying to
+// the running Quorum and plugin versions, from the last check of the advisory feed. The
+// feed is checked again first if refresh is true.
+func (api *privateAdminAPI) SecurityAdvisories(refresh *bool) (*AdvisoryReport, error) {
+	if api.node.advisories == nil {
+		return nil, fmt.Errorf("security advisory check is disabled, no advisory feed configured")
+	}
+	if refresh != nil && *refresh {
+		return api.node.advisories.check(), nil
+	}
+	if report := api.node.advisories.lastReport(); report != nil {
+		return report, nil
+	}
+	return api.node.advisories.check(), nil
+}
+
+// Quorum
+// EnterMaintenance takes the node out of service for a rolling upgrade: the readiness
+// probe fails and the new RPC calls are rejected except of the admin and consensus APIs
+// and over IPC, then the calls in flight are drained and the consensus roles are handed
+// off, within timeout seconds (60 by default). It is resumed by calling it again if it fails.
+func (api *privateAdminAPI) EnterMaintenance(ctx context.Context, timeout *uint64) (MaintenanceStatus, error) {
+	drainTimeout := defaultMaintenanceTimeout
+	if timeout != nil {
+		drainTimeout = time.Duration(*timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, drainTimeout)
+	defer cancel()
+	err := api.node.EnterMaintenance(ctx)
+	return api.node.MaintenanceStatus(), err
+}
+
+// Quorum
+// ExitMaintenance takes the consensus roles back and serves the RPC calls again
+func (api *privateAdminAPI) ExitMaintenance() (bool, error) {
+	if err := api.node.ExitMaintenance(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Quorum
+// Maintenance returns the maintenance phase of the node
+func (api *privateAdminAPI) Maintenance() MaintenanceStatus {
+	return api.node.MaintenanceStatus()
+}
+
 // PeerEvents creates an RPC subscription which receives peer events from the
 // node's p2p.Server
 func (api *privateAdminAPI) PeerEvents(ctx context.Context) (*rpc.Subscription, error) {
@@ -248,6 +354,8 @@ func (api *privateAdminAPI) StartWS(host *string, port *int, allowedOrigins *str
 	config := wsConfig{
 		Modules: api.node.config.WSModules,
 		Origins: api.node.config.WSOrigins,
+		// Quorum
+		Keepalive: api.node.config.WSKeepalive,
 		// ExposeAll: api.node.config.WSExposeAll,
 	}
 	if apis != nil {
@@ -312,6 +420,17 @@ func (api *publicAdminAPI) Peers() ([]*p2p.PeerInfo, error) {
 	return server.PeersInfo(), nil
 }
 
+// Quorum
+// PeerStats retrieves the ingress and egress bytes, the message counts by protocol code
+// and the error rate of each connected peer.
+func (api *publicAdminAPI) PeerStats() ([]*p2p.PeerStats, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.PeersStats(), nil
+}
+
 // NodeInfo retrieves all the information we know about the host node at the
 // protocol granularity.
 func (api *publicAdminAPI) NodeInfo() (*QuorumNodeInfo, error) {
@@ -330,6 +449,57 @@ func (api *publicAdminAPI) Datadir() string {
 	return api.node.DataDir()
 }
 
+// Quorum
+// privateDebugAPI is the collection of the Quorum debugging API methods of the node
+type privateDebugAPI struct {
+	node *Node
+}
+
+// SecurityAttribute is a security attribute of a private state, as checked against the
+// authorities of an access token when a private state is accessed
+type SecurityAttribute struct {
+	PSI types.PrivateStateIdentifier `json:"psi"`
+	// NodeEOA is the node-managed account signing the transactions, unless SelfEOA is
+	// set. The zero address asks for any account.
+	NodeEOA *common.Address `json:"nodeEOA,omitempty"`
+	// SelfEOA is the self-managed account signing the transactions
+	SelfEOA *common.Address `json:"selfEOA,omitempty"`
+}
+
+// ExplainAuthorization checks hypothetical security attributes against the authorities
+// of an access token, the token of the caller if empty, and returns which authority
+// granted each attribute or why each one didn't
+func (api *privateDebugAPI) ExplainAuthorization(ctx context.Context, token string, attributes []SecurityAttribute) ([]*multitenancy.Explanation, error) {
+	authToken := rpc.PreauthenticatedTokenFromContext(ctx)
+	if token != "" {
+		_, auth, err := api.node.GetSecuritySupports()
+		if err != nil {
+			return nil, err
+		}
+		if auth == nil {
+			return nil, fmt.Errorf("the security plugin is not enabled")
+		}
+		if authToken, err = auth.Authenticate(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	if authToken == nil {
+		return nil, fmt.Errorf("missing access token")
+	}
+	secAttributes := make([]*multitenancy.PrivateStateSecurityAttribute, len(attributes))
+	for i, attribute := range attributes {
+		attr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(attribute.PSI)
+		switch {
+		case attribute.SelfEOA != nil:
+			attr.WithSelfEOA(*attribute.SelfEOA)
+		case attribute.NodeEOA != nil:
+			attr.WithNodeEOA(*attribute.NodeEOA)
+		}
+		secAttributes[i] = attr
+	}
+	return multitenancy.ExplainAuthorization(authToken, secAttributes...)
+}
+
 // publicWeb3API offers helper utils
 type publicWeb3API struct {
 	stack *Node
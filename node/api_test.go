@@ -25,6 +25,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
 )
@@ -348,3 +349,24 @@ func not(ok bool) string {
 	}
 	return "not "
 }
+
+func TestSetLogLevel(t *testing.T) {
+	api := &privateAdminAPI{}
+
+	ok, err := api.SetLogLevel("consensus", "debug")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = api.SetLogLevel("eth/downloader", "5")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = api.SetLogLevel("consensus", "verbose")
+	assert.Error(t, err, "unknown level")
+
+	_, err = api.SetLogLevel("consensus", "6")
+	assert.Error(t, err, "level out of range")
+
+	_, err = api.SetLogLevel("eth=5", "debug")
+	assert.Error(t, err, "invalid module")
+}
@@ -25,12 +25,15 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/external"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/accounts/kms"
 	"github.com/ethereum/go-ethereum/accounts/pluggable"
 	"github.com/ethereum/go-ethereum/accounts/scwallet"
+	"github.com/ethereum/go-ethereum/accounts/threshold"
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -92,6 +95,12 @@ type Config struct {
 	// ExternalSigner specifies an external URI for a clef-type signer
 	ExternalSigner string `toml:",omitempty"`
 
+	// Quorum
+	// KMSKeys are the URLs of the cloud KMS keys used as accounts
+	KMSKeys []string `toml:",omitempty"`
+	// KMSUnlocked lets the cloud KMS accounts sign without being opened first
+	KMSUnlocked bool `toml:",omitempty"`
+
 	// UseLightweightKDF lowers the memory and CPU requirements of the key store
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
@@ -111,6 +120,11 @@ type Config struct {
 	// relative), then that specific path is enforced. An empty path disables IPC.
 	IPCPath string
 
+	// Quorum
+	// IPCSecurity restricts the accounts allowed to attach to the IPC named pipe on
+	// Windows, either with an SDDL security descriptor or a list of allowed SIDs.
+	IPCSecurity rpc.IPCSecurity `toml:",omitempty"`
+
 	// HTTPHost is the host interface on which to start the HTTP RPC server. If this
 	// field is empty, no HTTP API endpoint will be started.
 	HTTPHost string
@@ -143,6 +157,15 @@ type Config struct {
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
 
+	// Quorum
+	// HTTP2 enables HTTP/2 on the HTTP and websocket RPC servers, negotiated over TLS
+	// or in cleartext (h2c) when TLS is not enabled.
+	HTTP2 bool `toml:",omitempty"`
+
+	// HTTPGzipMinSize is the size in bytes above which HTTP RPC responses are compressed
+	// with gzip, when accepted by the client. A negative value disables compression.
+	HTTPGzipMinSize int `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
@@ -169,6 +192,24 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// Quorum
+	// WSKeepalive configures the pings sent over idle WebSocket connections and the closing
+	// of the connections whose client stopped answering them.
+	WSKeepalive rpc.WebsocketKeepalive `toml:",omitempty"`
+
+	// Quorum
+	// GRPCHost is the host interface on which to start the gRPC server serving the
+	// JSON-RPC API. If this field is empty, no gRPC endpoint will be started.
+	GRPCHost string `toml:",omitempty"`
+
+	// GRPCPort is the TCP port number on which to start the gRPC server.
+	GRPCPort int `toml:",omitempty"`
+
+	// GRPCModules is a list of API modules to expose via the gRPC interface.
+	// If the module list is empty, all RPC API endpoints designated public will be
+	// exposed.
+	GRPCModules []string `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -193,6 +234,34 @@ type Config struct {
 	// Quorum: EnableNodePermission comes from EnableNodePermissionFlag --permissioned.
 	EnableNodePermission bool `toml:",omitempty"`
 	EnableMultitenancy   bool `toml:",omitempty"` // comes from MultitenancyFlag flag
+	// Quorum: EnablePermissionApiKeys comes from PermissionApiKeysFlag --permissioned.apikeys
+	EnablePermissionApiKeys bool `toml:",omitempty"`
+
+	// Quorum
+	// BatchRequestLimit is the maximum number of messages in a JSON-RPC batch served over HTTP and WS (0 = no limit)
+	BatchRequestLimit int `toml:",omitempty"`
+	// BatchResponseMaxSize is the maximum aggregated size in bytes of the results of a JSON-RPC batch (0 = no limit)
+	BatchResponseMaxSize int `toml:",omitempty"`
+	// RPCMethodTimeouts are the execution deadlines of the RPC methods, by method name or
+	// namespace (e.g.: eth_call or debug_*)
+	RPCMethodTimeouts rpc.MethodTimeouts `toml:",omitempty"`
+	// RPCRateLimits are the global and per connection rate limits of the calls served over
+	// HTTP, WS, IPC and gRPC
+	RPCRateLimits rpc.RateLimits `toml:",omitempty"`
+	// RPCTrustedProxies are the IP addresses or CIDR ranges of the reverse proxies allowed
+	// to forward the address of the original client of the HTTP, WS and gRPC requests
+	RPCTrustedProxies []string `toml:",omitempty"`
+	// RPCProxyProtocol requires the PROXY protocol header on the HTTP, WS and gRPC
+	// connections accepted from the trusted proxies
+	RPCProxyProtocol bool `toml:",omitempty"`
+	// DiskUsageThresholds are the sizes in bytes of the data categories (e.g.: chaindata,
+	// raftwal) above which the node warns that its disk is filling up
+	DiskUsageThresholds map[string]uint64 `toml:",omitempty"`
+	// AdvisoryFeed is the URL or the file path of the security advisory feed the running
+	// Quorum and plugin versions are checked against. The check is disabled if empty
+	AdvisoryFeed string `toml:",omitempty"`
+	// AdvisoryInterval is the interval between the checks of the advisory feed
+	AdvisoryInterval time.Duration `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
@@ -264,6 +333,16 @@ func (c *Config) WSEndpoint() string {
 	return fmt.Sprintf("%s:%d", c.WSHost, c.WSPort)
 }
 
+// Quorum
+// GRPCEndpoint resolves a gRPC endpoint based on the configured host interface
+// and port parameters.
+func (c *Config) GRPCEndpoint() string {
+	if c.GRPCHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.GRPCHost, c.GRPCPort)
+}
+
 // DefaultWSEndpoint returns the websocket endpoint used by default.
 func DefaultWSEndpoint() string {
 	config := &Config{WSHost: DefaultWSHost, WSPort: DefaultWSPort}
@@ -273,7 +352,7 @@ func DefaultWSEndpoint() string {
 // ExtRPCEnabled returns the indicator whether node enables the external
 // RPC(http, ws or graphql).
 func (c *Config) ExtRPCEnabled() bool {
-	return c.HTTPHost != "" || c.WSHost != ""
+	return c.HTTPHost != "" || c.WSHost != "" || c.GRPCHost != ""
 }
 
 // NodeName returns the devp2p node identifier.
@@ -428,6 +507,36 @@ func (c *Config) parsePersistentNodes(w *bool, path string) []*enode.Node {
 	return nodes
 }
 
+// Quorum
+// LoadStaticNodes is the same as StaticNodes but fails if static-nodes.json can't be
+// parsed, instead of leaving the invalid nodes out, so that a typo doesn't drop the
+// static peers of a running node.
+func (c *Config) LoadStaticNodes() ([]*enode.Node, error) {
+	path := c.ResolvePath(datadirStaticNodes)
+	if c.DataDir == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	var nodelist []string
+	if err := common.LoadJSON(path, &nodelist); err != nil {
+		return nil, fmt.Errorf("can't load node list file: %v", err)
+	}
+	var nodes []*enode.Node
+	for _, url := range nodelist {
+		if url == "" {
+			continue
+		}
+		node, err := enode.Parse(enode.ValidSchemes, url)
+		if err != nil {
+			return nil, fmt.Errorf("node URL %s: %v", url, err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
 // AccountConfig determines the settings for scrypt and keydirectory
 func (c *Config) AccountConfig() (int, int, string, error) {
 	scryptN := keystore.StandardScryptN
@@ -549,6 +658,16 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 				backends = append(backends, schub)
 			}
 		}
+		if len(conf.KMSKeys) > 0 {
+			// Quorum: the public keys are retrieved in the background, the wallets arriving once retrieved
+			kmsBackend, err := kms.NewBackend(conf.KMSKeys, conf.KMSUnlocked)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid cloud KMS keys: %v", err)
+			}
+			backends = append(backends, kmsBackend)
+		}
+		// Quorum: the threshold signing services register their accounts into it
+		backends = append(backends, threshold.NewBackend())
 		if conf.Plugins != nil {
 			if _, ok := conf.Plugins.Providers[plugin.AccountPluginInterfaceName]; ok {
 				pluginBackend := pluggable.NewBackend()
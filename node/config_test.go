@@ -252,3 +252,33 @@ func TestConfig_IsPermissionEnabled_whenPermissionConfigIsNotAvailable(t *testin
 
 	assert.False(t, testObject.IsPermissionEnabled())
 }
+
+func TestConfig_LoadStaticNodes(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	testObject := &Config{Name: "geth", DataDir: tmpdir}
+	file := filepath.Join(tmpdir, "static-nodes.json")
+
+	nodes, err := testObject.LoadStaticNodes()
+	assert.NoError(t, err, "there is no static-nodes.json")
+	assert.Empty(t, nodes)
+
+	url := "enode://a979fb575495b8d6db44f750317d0f4622bf4c2aa3365d6af7c284339968eef29b69ad0dce72a4d8db5ebb4968de0e3bec910127f134779fbcb0cb6d3331163c@52.16.188.185:30303"
+	if err := ioutil.WriteFile(file, []byte(`["`+url+`"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nodes, err = testObject.LoadStaticNodes()
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	for _, malformed := range []string{`["` + url + `"`, `["enode://invalid"]`} {
+		if err := ioutil.WriteFile(file, []byte(malformed), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err = testObject.LoadStaticNodes()
+		assert.Error(t, err, malformed)
+	}
+}
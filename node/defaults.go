@@ -34,6 +34,8 @@ const (
 	DefaultWSPort      = 8546        // Default TCP port for the websocket RPC server
 	DefaultGraphQLHost = "localhost" // Default host interface for the GraphQL server
 	DefaultGraphQLPort = 8547        // Default TCP port for the GraphQL server
+	DefaultGRPCHost    = "localhost" // Default host interface for the gRPC server
+	DefaultGRPCPort    = 8549        // Default TCP port for the gRPC server
 )
 
 // DefaultConfig contains reasonable default settings.
@@ -45,6 +47,8 @@ var DefaultConfig = Config{
 	HTTPTimeouts:        rpc.DefaultHTTPTimeouts,
 	WSPort:              DefaultWSPort,
 	WSModules:           []string{"net", "web3"},
+	GRPCPort:            DefaultGRPCPort,
+	GRPCModules:         []string{"net", "web3"},
 	GraphQLVirtualHosts: []string{"localhost"},
 	P2P: p2p.Config{
 		ListenAddr: ":30303",
@@ -0,0 +1,255 @@
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Disk usage categories of a node. The private state tries are stored in the
+// chaindata database along with the public ones, so they count in chaindata.
+const (
+	DiskUsageChaindata = "chaindata"
+	DiskUsageFreezer   = "freezer"
+	DiskUsageRaftWAL   = "raftwal"
+	DiskUsageRaftSnap  = "raftsnap"
+	DiskUsageRaftState = "raftstate"
+)
+
+// diskUsageCheckInterval is how often the disk usage is checked against the
+// thresholds.
+const diskUsageCheckInterval = time.Minute
+
+var sizeUnits = map[string]uint64{
+	"":   1,
+	"B":  1,
+	"K":  1 << 10,
+	"KB": 1 << 10,
+	"M":  1 << 20,
+	"MB": 1 << 20,
+	"G":  1 << 30,
+	"GB": 1 << 30,
+	"T":  1 << 40,
+	"TB": 1 << 40,
+}
+
+// DiskUsage is the size of a category of the data of the node.
+type DiskUsage struct {
+	Path      string             `json:"path"`
+	Size      uint64             `json:"size"`
+	Human     common.StorageSize `json:"human"`
+	Threshold uint64             `json:"threshold,omitempty"`
+	Exceeded  bool               `json:"exceeded"`
+}
+
+// ParseDiskUsageThresholds parses a comma separated list of thresholds, e.g.
+// chaindata=500GB,raftwal=10GB. Sizes without unit are in bytes.
+func ParseDiskUsageThresholds(s string) (map[string]uint64, error) {
+	thresholds := make(map[string]uint64)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid disk usage threshold %q, want category=size", entry)
+		}
+		category := strings.ToLower(strings.TrimSpace(parts[0]))
+		if !isDiskUsageCategory(category) {
+			return nil, fmt.Errorf("unknown disk usage category %q", category)
+		}
+		size, err := parseSize(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid disk usage threshold %q: %v", entry, err)
+		}
+		thresholds[category] = size
+	}
+	return thresholds, nil
+}
+
+func isDiskUsageCategory(category string) bool {
+	switch category {
+	case DiskUsageChaindata, DiskUsageFreezer, DiskUsageRaftWAL, DiskUsageRaftSnap, DiskUsageRaftState:
+		return true
+	}
+	return false
+}
+
+func parseSize(s string) (uint64, error) {
+	upper := strings.ToUpper(s)
+	number := strings.TrimRight(upper, "KMGTB")
+	unit, ok := sizeUnits[upper[len(number):]]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit in %q", s)
+	}
+	n, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * unit, nil
+}
+
+// dirSize returns the total size of the files below the folder, except the
+// ones below the excluded folders.
+func dirSize(root string, exclude ...string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files can be removed while walking, e.g. compacted tables
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			for _, dir := range exclude {
+				if path == dir && path != root {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
+
+// diskUsagePaths returns the folders of the disk usage categories.
+func (n *Node) diskUsagePaths() map[string]string {
+	if n.config.DataDir == "" {
+		return nil
+	}
+	chaindata := n.ResolvePath("chaindata")
+	n.lock.Lock()
+	freezer := n.freezerDirs["chaindata"]
+	n.lock.Unlock()
+	if freezer == "" {
+		freezer = filepath.Join(chaindata, "ancient")
+	}
+	raftDir := n.config.RaftLogDir
+	if raftDir == "" {
+		raftDir = n.config.DataDir
+	}
+	return map[string]string{
+		DiskUsageChaindata: chaindata,
+		DiskUsageFreezer:   freezer,
+		DiskUsageRaftWAL:   filepath.Join(raftDir, "raft-wal"),
+		DiskUsageRaftSnap:  filepath.Join(raftDir, "raft-snap"),
+		DiskUsageRaftState: filepath.Join(raftDir, "quorum-raft-state"),
+	}
+}
+
+// DiskUsage returns the size of the data of the node by category. The
+// categories without data on disk, e.g. raft ones on an istanbul node, are left
+// out.
+func (n *Node) DiskUsage() (map[string]*DiskUsage, error) {
+	paths := n.diskUsagePaths()
+	usage := make(map[string]*DiskUsage, len(paths))
+	for category, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		var exclude []string
+		if category == DiskUsageChaindata {
+			exclude = append(exclude, paths[DiskUsageFreezer])
+		}
+		size, err := dirSize(path, exclude...)
+		if err != nil {
+			return nil, err
+		}
+		threshold := n.config.DiskUsageThresholds[category]
+		usage[category] = &DiskUsage{
+			Path:      path,
+			Size:      size,
+			Human:     common.StorageSize(size),
+			Threshold: threshold,
+			Exceeded:  threshold > 0 && size >= threshold,
+		}
+	}
+	return usage, nil
+}
+
+// diskMonitor periodically exports the disk usage of the node as metrics and
+// warns when the usage of a category crosses its threshold.
+type diskMonitor struct {
+	node     *Node
+	exceeded map[string]bool
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newDiskMonitor(node *Node) *diskMonitor {
+	return &diskMonitor{
+		node:     node,
+		exceeded: make(map[string]bool),
+		quit:     make(chan struct{}),
+	}
+}
+
+func (m *diskMonitor) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *diskMonitor) stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+func (m *diskMonitor) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(diskUsageCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// check updates the disk usage metrics and logs the thresholds crossed since
+// the previous check, in both directions.
+func (m *diskMonitor) check() {
+	usage, err := m.node.DiskUsage()
+	if err != nil {
+		log.Debug("Failed to compute disk usage", "err", err)
+		return
+	}
+	categories := make([]string, 0, len(usage))
+	for category := range usage {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		u := usage[category]
+		metrics.GetOrRegisterGauge("disk/usage/"+category, nil).Update(int64(u.Size))
+		if u.Threshold == 0 {
+			continue
+		}
+		exceeded := metrics.GetOrRegisterGauge("disk/exceeded/"+category, nil)
+		switch {
+		case u.Exceeded && !m.exceeded[category]:
+			log.Warn("Disk usage threshold exceeded", "category", category, "path", u.Path, "size", u.Human, "threshold", common.StorageSize(u.Threshold))
+			exceeded.Update(1)
+		case !u.Exceeded && m.exceeded[category]:
+			log.Info("Disk usage back below threshold", "category", category, "size", u.Human, "threshold", common.StorageSize(u.Threshold))
+			exceeded.Update(0)
+		}
+		m.exceeded[category] = u.Exceeded
+	}
+}
@@ -0,0 +1,65 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiskUsageThresholds(t *testing.T) {
+	thresholds, err := ParseDiskUsageThresholds("chaindata=500GB, raftwal=10m,freezer=1024")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{
+		DiskUsageChaindata: 500 << 30,
+		DiskUsageRaftWAL:   10 << 20,
+		DiskUsageFreezer:   1024,
+	}, thresholds)
+
+	for _, invalid := range []string{"chaindata", "keystore=1GB", "chaindata=1PB", "chaindata=GB"} {
+		_, err := ParseDiskUsageThresholds(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNodeDiskUsage(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(datadir)
+
+	writeFile := func(path string, size int) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, make([]byte, size), 0600))
+	}
+	writeFile(filepath.Join(datadir, "geth", "chaindata", "000001.ldb"), 100)
+	writeFile(filepath.Join(datadir, "geth", "chaindata", "ancient", "headers.cdat"), 40)
+	writeFile(filepath.Join(datadir, "raft-wal", "0.wal"), 60)
+	writeFile(filepath.Join(datadir, "quorum-raft-state", "000001.ldb"), 20)
+
+	stack, err := New(&Config{
+		Name:                "geth",
+		DataDir:             datadir,
+		DiskUsageThresholds: map[string]uint64{DiskUsageRaftWAL: 50, DiskUsageChaindata: 1000},
+	})
+	require.NoError(t, err)
+	defer stack.Close()
+
+	usage, err := stack.DiskUsage()
+
+	require.NoError(t, err)
+	assert.Len(t, usage, 4, "missing folders must be left out")
+	assert.Equal(t, uint64(100), usage[DiskUsageChaindata].Size, "the freezer is not part of chaindata")
+	assert.False(t, usage[DiskUsageChaindata].Exceeded)
+	assert.Equal(t, uint64(40), usage[DiskUsageFreezer].Size)
+	assert.Equal(t, uint64(60), usage[DiskUsageRaftWAL].Size)
+	assert.True(t, usage[DiskUsageRaftWAL].Exceeded)
+	assert.Equal(t, uint64(20), usage[DiskUsageRaftState].Size)
+
+	monitor := newDiskMonitor(stack)
+	monitor.check()
+	assert.Equal(t, map[string]bool{DiskUsageChaindata: false, DiskUsageRaftWAL: true}, monitor.exceeded)
+}
@@ -37,7 +37,7 @@ func StartHTTPEndpoint(endpoint string, timeouts rpc.HTTPTimeouts, handler http.
 		err          error
 		isTlsEnabled bool
 	)
-	if isTlsEnabled, listener, err = startListener(endpoint, tlsConfigSource); err != nil {
+	if isTlsEnabled, listener, err = startListener(endpoint, tlsConfigSource, nil); err != nil {
 		return nil, nil, isTlsEnabled, err
 	}
 	// make sure timeout values are meaningful
@@ -77,8 +77,10 @@ func checkModuleAvailability(modules []string, apis []rpc.API) (bad, available [
 }
 
 // Quorum
-// Produce net.Listener instance with TLS support if tlsConfigSource provides the config
-func startListener(endpoint string, tlsConfigSource security.TLSConfigurationSource) (bool, net.Listener, error) {
+// Produce net.Listener instance with TLS support if tlsConfigSource provides the config.
+// The connections from the proxies trusted for the PROXY protocol, if any, must start
+// with its header
+func startListener(endpoint string, tlsConfigSource security.TLSConfigurationSource, proxyProtocol *rpc.TrustedProxies, nextProtos ...string) (bool, net.Listener, error) {
 	var tlsConfig *tls.Config
 	var err error
 	var listener net.Listener
@@ -91,15 +93,23 @@ func startListener(endpoint string, tlsConfigSource security.TLSConfigurationSou
 		isTlsEnabled = false
 		err = fmt.Errorf("no TLSConfigurationSource found")
 	}
-	if isTlsEnabled {
-		if listener, err = tls.Listen("tcp", endpoint, tlsConfig); err != nil {
-			return isTlsEnabled, nil, err
-		}
-	} else {
+	if !isTlsEnabled {
 		log.Info("Security: TLS not enabled", "endpoint", endpoint, "reason", err)
-		if listener, err = net.Listen("tcp", endpoint); err != nil {
-			return isTlsEnabled, nil, err
+	}
+	if listener, err = net.Listen("tcp", endpoint); err != nil {
+		return isTlsEnabled, nil, err
+	}
+	// the PROXY protocol header precedes the TLS handshake
+	if proxyProtocol != nil {
+		listener = proxyProtocol.ProxyProtocolListener(listener)
+	}
+	if isTlsEnabled {
+		// Quorum: advertise the application protocols (e.g.: h2) negotiated via ALPN
+		if len(nextProtos) > 0 {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.NextProtos = append(nextProtos, tlsConfig.NextProtos...)
 		}
+		listener = tls.NewListener(listener, tlsConfig)
 	}
 	return isTlsEnabled, listener, nil
 }
@@ -0,0 +1,153 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Quorum
+
+// maintenanceExemptNamespaces are the APIs served while the node enters maintenance, to
+// operate the node and follow the consensus. The calls over IPC are not drained either.
+var maintenanceExemptNamespaces = []string{"admin", "istanbul", "raft"}
+
+// defaultMaintenanceTimeout is how long the node waits for the RPC calls in flight and the
+// hand off of the consensus roles when entering maintenance
+const defaultMaintenanceTimeout = time.Minute
+
+// Phases of the maintenance of the node
+const (
+	MaintenanceOff      = ""            // the node serves the RPC calls and has its consensus roles
+	MaintenanceDraining = "draining"    // the node is not ready, the new RPC calls are rejected, the calls in flight complete
+	MaintenanceHandoff  = "handoff"     // the consensus roles are handed off
+	MaintenanceOn       = "maintenance" // the node is ready to be restarted
+)
+
+// MaintenanceHandler hands off the roles of a service when the node enters maintenance,
+// e.g. the raft leadership or the Istanbul proposals, and takes them back when the node
+// exits maintenance
+type MaintenanceHandler interface {
+	EnterMaintenance(ctx context.Context) error
+	ExitMaintenance() error
+}
+
+// MaintenanceStatus is the maintenance phase of the node
+type MaintenanceStatus struct {
+	Phase    string `json:"phase"`
+	InFlight int    `json:"inFlight"` // RPC calls in flight, being drained
+}
+
+// maintenance takes the node out of service for its upgrade, without cutting the RPC
+// calls in flight nor the consensus. The admin and consensus APIs, and the calls over
+// IPC, are served throughout.
+type maintenance struct {
+	mu       sync.Mutex   // serializes the transitions
+	phase    atomic.Value // string
+	drain    *rpc.Drain
+	handlers []MaintenanceHandler
+}
+
+func newMaintenance() *maintenance {
+	m := &maintenance{drain: rpc.NewDrain(maintenanceExemptNamespaces...)}
+	m.phase.Store(MaintenanceOff)
+	return m
+}
+
+func (m *maintenance) status() MaintenanceStatus {
+	return MaintenanceStatus{Phase: m.phase.Load().(string), InFlight: m.drain.InFlight()}
+}
+
+// enter drains the RPC calls, hands off the roles of the services and then reports
+// the node in maintenance. It is resumed by calling it again if it fails.
+func (m *maintenance) enter(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase.Load() == MaintenanceOn {
+		return nil
+	}
+	m.phase.Store(MaintenanceDraining)
+	m.drain.Start()
+	log.Info("Entering maintenance, draining the RPC calls", "inFlight", m.drain.InFlight())
+	if err := m.drain.Wait(ctx); err != nil {
+		return fmt.Errorf("%d RPC calls still in flight: %v", m.drain.InFlight(), err)
+	}
+	m.phase.Store(MaintenanceHandoff)
+	for _, handler := range m.handlers {
+		if err := handler.EnterMaintenance(ctx); err != nil {
+			return fmt.Errorf("failed to hand off the consensus roles: %v", err)
+		}
+	}
+	m.phase.Store(MaintenanceOn)
+	log.Info("Entered maintenance")
+	return nil
+}
+
+// exit takes the roles of the services back and serves the RPC calls again
+func (m *maintenance) exit() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase.Load() == MaintenanceOff {
+		return nil
+	}
+	var firstErr error
+	for _, handler := range m.handlers {
+		if err := handler.ExitMaintenance(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	m.drain.Stop()
+	m.phase.Store(MaintenanceOff)
+	log.Info("Exited maintenance")
+	return firstErr
+}
+
+// RegisterMaintenanceHandler adds a service handing off its roles when the node enters
+// maintenance
+func (n *Node) RegisterMaintenanceHandler(handler MaintenanceHandler) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != initializingState {
+		panic("can't register maintenance handler on running/stopped node")
+	}
+	n.maintenance.handlers = append(n.maintenance.handlers, handler)
+}
+
+// EnterMaintenance fails the readiness probe, rejects the new RPC calls except of the
+// admin and consensus APIs and over IPC, waits for the calls in flight and hands off the
+// consensus roles
+func (n *Node) EnterMaintenance(ctx context.Context) error {
+	return n.maintenance.enter(ctx)
+}
+
+// ExitMaintenance takes the consensus roles back and serves the RPC calls again
+func (n *Node) ExitMaintenance() error {
+	return n.maintenance.exit()
+}
+
+// MaintenanceStatus returns the maintenance phase of the node
+func (n *Node) MaintenanceStatus() MaintenanceStatus {
+	return n.maintenance.status()
+}
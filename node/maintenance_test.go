@@ -0,0 +1,87 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMaintenanceHandler struct {
+	entered bool
+	err     error
+}
+
+func (h *stubMaintenanceHandler) EnterMaintenance(context.Context) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.entered = true
+	return nil
+}
+
+func (h *stubMaintenanceHandler) ExitMaintenance() error {
+	h.entered = false
+	return nil
+}
+
+type maintenanceTestService struct{}
+
+func (s *maintenanceTestService) Echo(v string) string { return v }
+
+// dialDrainedServer returns a client of a server whose calls are drained by the node
+func dialDrainedServer(t *testing.T, stack *Node) *rpc.Client {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("test", new(maintenanceTestService)))
+	srv.SetDrain(stack.maintenance.drain)
+	return rpc.DialInProc(srv)
+}
+
+func TestNode_EnterMaintenance(t *testing.T) {
+	stack, err := New(testNodeConfig())
+	require.NoError(t, err)
+	defer stack.Close()
+	handler := &stubMaintenanceHandler{err: errors.New("no peer")}
+	stack.RegisterMaintenanceHandler(handler)
+	client := dialDrainedServer(t, stack)
+	defer client.Close()
+	var echo string
+
+	assert.Error(t, stack.EnterMaintenance(context.Background()))
+	assert.Equal(t, MaintenanceHandoff, stack.MaintenanceStatus().Phase, "resumed by entering maintenance again")
+	err = client.Call(&echo, "test_echo", "hello")
+	require.Error(t, err, "the new calls are rejected")
+	rpcErr, ok := err.(rpc.Error)
+	require.True(t, ok)
+	assert.Equal(t, -32006, rpcErr.ErrorCode())
+
+	handler.err = nil
+	require.NoError(t, stack.EnterMaintenance(context.Background()))
+	assert.Equal(t, MaintenanceOn, stack.MaintenanceStatus().Phase)
+	assert.True(t, handler.entered)
+
+	require.NoError(t, stack.ExitMaintenance())
+	assert.Equal(t, MaintenanceOff, stack.MaintenanceStatus().Phase)
+	assert.False(t, handler.entered)
+	require.NoError(t, client.Call(&echo, "test_echo", "hello"))
+	assert.Equal(t, "hello", echo)
+}
@@ -59,12 +59,18 @@ type Node struct {
 	http          *httpServer //
 	ws            *httpServer //
 	ipc           *ipcServer  // Stores information about the ipc http server
+	grpc          *grpcServer // Quorum: stores information about the gRPC server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 
 	// Quorum
-	pluginManager *plugin.PluginManager // Manage all plugins for this node. If plugin is not enabled, an EmptyPluginManager is set.
+	pluginManager *plugin.PluginManager            // Manage all plugins for this node. If plugin is not enabled, an EmptyPluginManager is set.
+	freezerDirs   map[string]string                // Freezer folders of the databases, by database name
+	diskMonitor   *diskMonitor                     // Exports the disk usage and warns about the thresholds crossed
+	advisories    *advisoryMonitor                 // Checks the running versions against the security advisory feed
+	authManagers  []security.AuthenticationManager // Authentication managers of the services, tried after the security plugins
+	maintenance   *maintenance                     // Drains the RPC calls and hands off the consensus roles for the maintenance of the node
 	// End Quorum
 }
 
@@ -112,6 +118,8 @@ func New(conf *Config) (*Node, error) {
 		server:        &p2p.Server{Config: conf.P2P},
 		databases:     make(map[*closeTrackingDB]struct{}),
 		pluginManager: plugin.NewEmptyPluginManager(),
+		freezerDirs:   make(map[string]string),
+		maintenance:   newMaintenance(),
 	}
 
 	// Register built-in APIs.
@@ -150,9 +158,18 @@ func New(conf *Config) (*Node, error) {
 	// End Quorum
 
 	// Configure RPC servers.
-	node.http = newHTTPServer(node.log, conf.HTTPTimeouts).withMultitenancy(node.config.EnableMultitenancy)
-	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts).withMultitenancy(node.config.EnableMultitenancy)
-	node.ipc = newIPCServer(node.log, conf.IPCEndpoint()).withMultitenancy(node.config.EnableMultitenancy)
+	node.inprocHandler.SetMethodTimeouts(conf.RPCMethodTimeouts) // Quorum
+	batchLimits := rpc.BatchLimits{RequestLimit: conf.BatchRequestLimit, ResponseMaxSize: conf.BatchResponseMaxSize}
+	// the global rate limit is shared by all the transports
+	rateLimiter := rpc.NewRateLimiter(conf.RPCRateLimits)
+	trustedProxies, err := rpc.NewTrustedProxies(conf.RPCTrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	node.http = newHTTPServer(node.log, conf.HTTPTimeouts).withMultitenancy(node.config.EnableMultitenancy).withBatchLimits(batchLimits).withHTTP2(conf.HTTP2).withMethodTimeouts(conf.RPCMethodTimeouts).withRateLimiter(rateLimiter).withDrain(node.maintenance.drain).withTrustedProxies(trustedProxies, conf.RPCProxyProtocol)
+	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts).withMultitenancy(node.config.EnableMultitenancy).withBatchLimits(batchLimits).withHTTP2(conf.HTTP2).withMethodTimeouts(conf.RPCMethodTimeouts).withRateLimiter(rateLimiter).withDrain(node.maintenance.drain).withTrustedProxies(trustedProxies, conf.RPCProxyProtocol)
+	node.ipc = newIPCServer(node.log, conf.IPCEndpoint()).withMultitenancy(node.config.EnableMultitenancy).withMethodTimeouts(conf.RPCMethodTimeouts).withRateLimiter(rateLimiter).withSecurity(conf.IPCSecurity)
+	node.grpc = newGRPCServer(node.log, conf.GRPCEndpoint(), conf.GRPCModules).withMultitenancy(node.config.EnableMultitenancy).withBatchLimits(batchLimits).withMethodTimeouts(conf.RPCMethodTimeouts).withRateLimiter(rateLimiter).withDrain(node.maintenance.drain).withTrustedProxies(trustedProxies, conf.RPCProxyProtocol)
 
 	return node, nil
 }
@@ -204,8 +221,18 @@ func (n *Node) Start() error {
 	if err != nil {
 		n.stopServices(started)
 		n.doClose(nil)
+		return err
 	}
-	return err
+	// Quorum
+	if n.config.DataDir != "" {
+		n.diskMonitor = newDiskMonitor(n)
+		n.diskMonitor.start()
+	}
+	if n.config.AdvisoryFeed != "" {
+		n.advisories = newAdvisoryMonitor(n)
+		n.advisories.start()
+	}
+	return nil
 }
 
 // Close stops the Node and releases resources acquired in
@@ -223,6 +250,12 @@ func (n *Node) Close() error {
 		return n.doClose(nil)
 	case runningState:
 		// The node was started, release resources acquired by Start().
+		if n.diskMonitor != nil {
+			n.diskMonitor.stop()
+		}
+		if n.advisories != nil {
+			n.advisories.stop()
+		}
 		var errs []error
 		if err := n.stopServices(n.lifecycles); err != nil {
 			errs = append(errs, err)
@@ -378,6 +411,7 @@ func (n *Node) startRPC() error {
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
+			GzipMinSize:        n.config.HTTPGzipMinSize,
 		}
 		server := n.http
 		if err := server.setListenAddr(n.config.HTTPHost, n.config.HTTPPort); err != nil {
@@ -394,6 +428,8 @@ func (n *Node) startRPC() error {
 		config := wsConfig{
 			Modules: n.config.WSModules,
 			Origins: n.config.WSOrigins,
+			// Quorum
+			Keepalive: n.config.WSKeepalive,
 		}
 		if err := server.setListenAddr(n.config.WSHost, n.config.WSPort); err != nil {
 			return err
@@ -403,6 +439,11 @@ func (n *Node) startRPC() error {
 		}
 	}
 
+	// Quorum: configure gRPC.
+	if err := n.grpc.start(n.rpcAPIs, tls, auth); err != nil {
+		return err
+	}
+
 	if err := n.http.start(tls); err != nil {
 		return err
 	}
@@ -420,6 +461,7 @@ func (n *Node) stopRPC() {
 	n.http.stop()
 	n.ws.stop()
 	n.ipc.stop()
+	n.grpc.stop()
 	n.stopInProc()
 }
 
@@ -570,6 +612,12 @@ func (n *Node) WSEndpoint() string {
 	return "ws://" + n.ws.listenAddr()
 }
 
+// Quorum
+// GRPCEndpoint returns the address of the gRPC server.
+func (n *Node) GRPCEndpoint() string {
+	return n.grpc.listenAddr()
+}
+
 // EventMux retrieves the event multiplexer used by all the network services in
 // the current protocol stack.
 func (n *Node) EventMux() *event.TypeMux {
@@ -624,6 +672,7 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer,
 		case !filepath.IsAbs(freezer):
 			freezer = n.ResolvePath(freezer)
 		}
+		n.freezerDirs[name] = freezer // Quorum
 		db, err = rawdb.NewLevelDBDatabaseWithFreezer(root, cache, handles, freezer, namespace)
 	}
 
@@ -672,24 +721,62 @@ func (n *Node) closeDatabases() (errors []error) {
 }
 
 // Quorum
+//
+// When additional security plugins are configured, e.g.: security.legacy, tokens are authenticated
+// by each of them in turn. TLS configuration is only provided by the main security plugin
 func (n *Node) GetSecuritySupports() (tlsConfigSource security.TLSConfigurationSource, authManager security.AuthenticationManager, err error) {
+	n.lock.Lock()
+	registered := append([]security.AuthenticationManager{}, n.authManagers...)
+	n.lock.Unlock()
+
 	if n.pluginManager.IsEnabled(plugin.SecurityPluginInterfaceName) {
-		sp := new(plugin.SecurityPluginTemplate)
-		if err = n.pluginManager.GetPluginTemplate(plugin.SecurityPluginInterfaceName, sp); err != nil {
-			return
-		}
-		if tlsConfigSource, err = sp.TLSConfigurationSource(); err != nil {
-			return
+		authManagers := make([]security.AuthenticationManager, 0)
+		for _, name := range n.pluginManager.ProvidersOf(plugin.SecurityPluginInterfaceName) {
+			sp := new(plugin.SecurityPluginTemplate)
+			if err = n.pluginManager.GetPluginTemplate(name, sp); err != nil {
+				return
+			}
+			if name == plugin.SecurityPluginInterfaceName {
+				if tlsConfigSource, err = sp.TLSConfigurationSource(); err != nil {
+					return
+				}
+			}
+			var am security.AuthenticationManager
+			if am, err = sp.AuthenticationManager(); err != nil {
+				return
+			}
+			authManagers = append(authManagers, am)
 		}
-		if authManager, err = sp.AuthenticationManager(); err != nil {
-			return
+		authManagers = append(authManagers, registered...)
+		if len(authManagers) == 1 {
+			authManager = authManagers[0]
+		} else {
+			authManager = security.NewChainedAuthenticationManager(authManagers...)
 		}
 	} else {
 		log.Info("Security Plugin is not enabled")
+		if len(registered) > 0 {
+			authManager = security.NewChainedAuthenticationManager(registered...)
+		}
 	}
 	return
 }
 
+// Quorum
+//
+// RegisterAuthenticationManager adds an authentication manager of a service, e.g. the API keys
+// of the permission service, authenticating the RPC tokens in turn with the security plugins.
+// The RPC endpoints require authentication once a manager is registered, even without plugin
+func (n *Node) RegisterAuthenticationManager(authManager security.AuthenticationManager) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != initializingState {
+		panic("can't register authentication manager on running/stopped node")
+	}
+	n.authManagers = append(n.authManagers, authManager)
+}
+
 // Quorum
 //
 // delegate call to node.Config
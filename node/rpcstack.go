@@ -20,7 +20,6 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -33,6 +32,9 @@ import (
 	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
 )
 
 // httpConfig is the JSON-RPC/HTTP configuration.
@@ -40,12 +42,15 @@ type httpConfig struct {
 	Modules            []string
 	CorsAllowedOrigins []string
 	Vhosts             []string
+	GzipMinSize        int // Quorum
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
 	Origins []string
 	Modules []string
+	// Quorum
+	Keepalive rpc.WebsocketKeepalive
 }
 
 type rpcHandler struct {
@@ -80,6 +85,20 @@ type httpServer struct {
 	// Quorum
 	// isMultitenant determines if the server supports mutlitenancy
 	isMultitenant bool
+	// batchLimits restricts the JSON-RPC batches served over HTTP and WS
+	batchLimits rpc.BatchLimits
+	// methodTimeouts are the execution deadlines of the RPC methods
+	methodTimeouts rpc.MethodTimeouts
+	// rateLimiter limits the calls served over HTTP and WS, nil if disabled
+	rateLimiter *rpc.RateLimiter
+	// drain rejects the calls while the node enters maintenance
+	drain *rpc.Drain
+	// http2 enables HTTP/2, over TLS or in cleartext (h2c)
+	http2 bool
+	// trustedProxies are allowed to forward the address of the original client
+	trustedProxies *rpc.TrustedProxies
+	// proxyProtocol requires the PROXY protocol header from the trusted proxies
+	proxyProtocol bool
 }
 
 func newHTTPServer(log log.Logger, timeouts rpc.HTTPTimeouts) *httpServer {
@@ -96,6 +115,49 @@ func (h *httpServer) withMultitenancy(b bool) *httpServer {
 	return h
 }
 
+// Quorum
+// withHTTP2 enables HTTP/2 on this server
+func (h *httpServer) withHTTP2(b bool) *httpServer {
+	h.http2 = b
+	return h
+}
+
+// Quorum
+// withBatchLimits sets the limits applied to JSON-RPC batches served by this server
+func (h *httpServer) withBatchLimits(limits rpc.BatchLimits) *httpServer {
+	h.batchLimits = limits
+	return h
+}
+
+// Quorum
+// withMethodTimeouts sets the execution deadlines of the methods served by this server
+func (h *httpServer) withMethodTimeouts(timeouts rpc.MethodTimeouts) *httpServer {
+	h.methodTimeouts = timeouts
+	return h
+}
+
+// Quorum
+// withRateLimiter sets the rate limiter of the calls served by this server
+func (h *httpServer) withRateLimiter(limiter *rpc.RateLimiter) *httpServer {
+	h.rateLimiter = limiter
+	return h
+}
+
+// Quorum
+// withDrain sets the drain of the calls served by this server
+func (h *httpServer) withDrain(drain *rpc.Drain) *httpServer {
+	h.drain = drain
+	return h
+}
+
+// Quorum
+// withTrustedProxies sets the reverse proxies allowed to forward the address of the
+// original client, with X-Forwarded-For or, if proxyProtocol is set, the PROXY protocol
+func (h *httpServer) withTrustedProxies(proxies *rpc.TrustedProxies, proxyProtocol bool) *httpServer {
+	h.trustedProxies, h.proxyProtocol = proxies, proxyProtocol
+	return h
+}
+
 // setListenAddr configures the listening address of the server.
 // The address can only be set while the server isn't running.
 func (h *httpServer) setListenAddr(host string, port int) error {
@@ -142,7 +204,15 @@ func (h *httpServer) start(tlsConfigSource security.TLSConfigurationSource) erro
 	}
 
 	// Start the server.
-	isTls, listener, err := startListener(h.endpoint, tlsConfigSource)
+	var nextProtos []string
+	if h.http2 {
+		nextProtos = []string{"h2", "http/1.1"}
+	}
+	var proxyProtocol *rpc.TrustedProxies
+	if h.proxyProtocol {
+		proxyProtocol = h.trustedProxies
+	}
+	isTls, listener, err := startListener(h.endpoint, tlsConfigSource, proxyProtocol, nextProtos...)
 	if err != nil {
 		// If the server fails to start, we need to clear out the RPC and WS
 		// configuration so they can be configured another time.
@@ -151,6 +221,10 @@ func (h *httpServer) start(tlsConfigSource security.TLSConfigurationSource) erro
 		return err
 	}
 	h.listener = listener
+	// Quorum: without TLS, HTTP/2 requires prior knowledge from the client (h2c)
+	if h.http2 && !isTls {
+		h.server.Handler = h2c.NewHandler(h, &http2.Server{})
+	}
 	go h.server.Serve(listener)
 
 	// if server is websocket only, return after logging
@@ -164,6 +238,7 @@ func (h *httpServer) start(tlsConfigSource security.TLSConfigurationSource) erro
 		"cors", strings.Join(h.httpConfig.CorsAllowedOrigins, ","),
 		"vhosts", strings.Join(h.httpConfig.Vhosts, ","),
 		"isTls", isTls,
+		"http2", h.http2,
 	)
 
 	// Log all handlers mounted on server.
@@ -251,12 +326,17 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig, authManager se
 
 	// Create RPC server and handler.
 	srv := rpc.NewProtectedServer(authManager, h.isMultitenant)
+	srv.SetBatchLimits(h.batchLimits)
+	srv.SetMethodTimeouts(h.methodTimeouts)
+	srv.SetRateLimiter(h.rateLimiter)
+	srv.SetDrain(h.drain)
+	srv.SetTrustedProxies(h.trustedProxies)
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts),
+		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.GzipMinSize),
 		server:  srv,
 	})
 	return nil
@@ -284,6 +364,12 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig, authManager secur
 
 	// Create RPC server and handler.
 	srv := rpc.NewProtectedServer(authManager, h.isMultitenant)
+	srv.SetBatchLimits(h.batchLimits)
+	srv.SetMethodTimeouts(h.methodTimeouts)
+	srv.SetRateLimiter(h.rateLimiter)
+	srv.SetDrain(h.drain)
+	srv.SetTrustedProxies(h.trustedProxies)
+	srv.SetWebsocketKeepalive(config.Keepalive)
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
@@ -334,11 +420,17 @@ func isWebsocket(r *http.Request) bool {
 }
 
 // NewHTTPHandlerStack returns wrapped http-related handlers
-func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string) http.Handler {
+//
+// Quorum: responses are compressed with gzip, if accepted by the client, once their size
+// exceeds gzipMinSize bytes. A negative gzipMinSize disables compression.
+func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, gzipMinSize int) http.Handler {
 	// Wrap the CORS-handler within a host-handler
 	handler := newCorsHandler(srv, cors)
 	handler = newVHostHandler(vhosts, handler)
-	return newGzipHandler(handler)
+	if gzipMinSize < 0 {
+		return handler
+	}
+	return newGzipHandler(handler, gzipMinSize)
 }
 
 func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
@@ -409,36 +501,77 @@ var gzPool = sync.Pool{
 	},
 }
 
+// Quorum
+// gzipResponseWriter buffers the response until its size exceeds minSize, then
+// compresses it. Smaller responses are sent uncompressed.
 type gzipResponseWriter struct {
-	io.Writer
 	http.ResponseWriter
+	minSize int
+	status  int
+	buf     []byte
+	gz      *gzip.Writer // non-nil once the response is compressed
 }
 
 func (w *gzipResponseWriter) WriteHeader(status int) {
-	w.Header().Del("Content-Length")
-	w.ResponseWriter.WriteHeader(status)
+	// the status is sent along with the first bytes of the body, once the encoding is known
+	w.status = status
 }
 
 func (w *gzipResponseWriter) Write(b []byte) (int, error) {
-	return w.Writer.Write(b)
+	if w.gz != nil {
+		return w.gz.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) > w.minSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *gzipResponseWriter) startGzip() error {
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	w.writeHeader()
+	w.gz = gzPool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *gzipResponseWriter) writeHeader() {
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
 }
 
-func newGzipHandler(next http.Handler) http.Handler {
+// close completes the response, writing the buffered bytes if it was not compressed
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		w.gz.Close()
+		gzPool.Put(w.gz)
+		return
+	}
+	w.writeHeader()
+	if len(w.buf) > 0 {
+		w.ResponseWriter.Write(w.buf)
+	}
+}
+
+func newGzipHandler(next http.Handler, minSize int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
 		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		w.Header().Set("Content-Encoding", "gzip")
-
-		gz := gzPool.Get().(*gzip.Writer)
-		defer gzPool.Put(gz)
-
-		gz.Reset(w)
-		defer gz.Close()
+		gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+		defer gw.close()
 
-		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, Writer: gz}, r)
+		next.ServeHTTP(gw, r)
 	})
 }
 
@@ -453,6 +586,12 @@ type ipcServer struct {
 	// Quorum
 	// isMultitenant determines if the server supports mutlitenancy
 	isMultitenant bool
+	// methodTimeouts are the execution deadlines of the RPC methods
+	methodTimeouts rpc.MethodTimeouts
+	// rateLimiter limits the calls served over IPC, nil if disabled
+	rateLimiter *rpc.RateLimiter
+	// security restricts the accounts allowed to attach to the named pipe
+	security rpc.IPCSecurity
 }
 
 func newIPCServer(log log.Logger, endpoint string) *ipcServer {
@@ -466,6 +605,27 @@ func (is *ipcServer) withMultitenancy(b bool) *ipcServer {
 	return is
 }
 
+// Quorum
+// withMethodTimeouts sets the execution deadlines of the methods served by this server
+func (is *ipcServer) withMethodTimeouts(timeouts rpc.MethodTimeouts) *ipcServer {
+	is.methodTimeouts = timeouts
+	return is
+}
+
+// Quorum
+// withRateLimiter sets the rate limiter of the calls served by this server
+func (is *ipcServer) withRateLimiter(limiter *rpc.RateLimiter) *ipcServer {
+	is.rateLimiter = limiter
+	return is
+}
+
+// Quorum
+// withSecurity sets the accounts allowed to attach to the named pipe of this server
+func (is *ipcServer) withSecurity(security rpc.IPCSecurity) *ipcServer {
+	is.security = security
+	return is
+}
+
 // Start starts the httpServer's http.Server
 func (is *ipcServer) start(apis []rpc.API) error {
 	is.mu.Lock()
@@ -474,12 +634,14 @@ func (is *ipcServer) start(apis []rpc.API) error {
 	if is.listener != nil {
 		return nil // already running
 	}
-	listener, srv, err := rpc.StartIPCEndpoint(is.endpoint, apis)
+	listener, srv, err := rpc.StartIPCEndpointWithSecurity(is.endpoint, apis, is.security)
 	if err != nil {
 		return err
 	}
 	srv.EnableMultitenancy(is.isMultitenant)
-	is.log.Info("IPC endpoint opened", "url", is.endpoint, "isMultitenant", is.isMultitenant)
+	srv.SetMethodTimeouts(is.methodTimeouts)
+	srv.SetRateLimiter(is.rateLimiter)
+	is.log.Info("IPC endpoint opened", "url", is.endpoint, "isMultitenant", is.isMultitenant, "restricted", !is.security.IsEmpty())
 	is.listener, is.srv = listener, srv
 	return nil
 }
@@ -498,6 +660,127 @@ func (is *ipcServer) stop() error {
 	return err
 }
 
+// Quorum
+// grpcServer serves the JSON-RPC API over gRPC, see rpc.Server.RegisterGRPC
+type grpcServer struct {
+	log      log.Logger
+	endpoint string
+	modules  []string
+
+	mu       sync.Mutex
+	listener net.Listener
+	grpc     *grpc.Server
+	srv      *rpc.Server
+
+	isMultitenant  bool
+	batchLimits    rpc.BatchLimits
+	methodTimeouts rpc.MethodTimeouts
+	rateLimiter    *rpc.RateLimiter
+	drain          *rpc.Drain
+	trustedProxies *rpc.TrustedProxies
+	proxyProtocol  bool
+}
+
+func newGRPCServer(log log.Logger, endpoint string, modules []string) *grpcServer {
+	return &grpcServer{log: log, endpoint: endpoint, modules: modules}
+}
+
+// withMultitenancy indicates if this server supports multitenancy
+func (gs *grpcServer) withMultitenancy(b bool) *grpcServer {
+	gs.isMultitenant = b
+	return gs
+}
+
+// withBatchLimits sets the limits applied to JSON-RPC batches served by this server
+func (gs *grpcServer) withBatchLimits(limits rpc.BatchLimits) *grpcServer {
+	gs.batchLimits = limits
+	return gs
+}
+
+// withMethodTimeouts sets the execution deadlines of the methods served by this server
+func (gs *grpcServer) withMethodTimeouts(timeouts rpc.MethodTimeouts) *grpcServer {
+	gs.methodTimeouts = timeouts
+	return gs
+}
+
+// withRateLimiter sets the rate limiter of the calls served by this server
+func (gs *grpcServer) withRateLimiter(limiter *rpc.RateLimiter) *grpcServer {
+	gs.rateLimiter = limiter
+	return gs
+}
+
+// withDrain sets the drain of the calls served by this server
+func (gs *grpcServer) withDrain(drain *rpc.Drain) *grpcServer {
+	gs.drain = drain
+	return gs
+}
+
+// withTrustedProxies sets the reverse proxies allowed to forward the address of the
+// original client, with x-forwarded-for or, if proxyProtocol is set, the PROXY protocol
+func (gs *grpcServer) withTrustedProxies(proxies *rpc.TrustedProxies, proxyProtocol bool) *grpcServer {
+	gs.trustedProxies, gs.proxyProtocol = proxies, proxyProtocol
+	return gs
+}
+
+// start starts the gRPC server if an endpoint is configured
+func (gs *grpcServer) start(apis []rpc.API, tlsConfigSource security.TLSConfigurationSource, authManager security.AuthenticationManager) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.endpoint == "" || gs.listener != nil {
+		return nil // already running or not configured
+	}
+	srv := rpc.NewProtectedServer(authManager, gs.isMultitenant)
+	srv.SetBatchLimits(gs.batchLimits)
+	srv.SetMethodTimeouts(gs.methodTimeouts)
+	srv.SetRateLimiter(gs.rateLimiter)
+	srv.SetDrain(gs.drain)
+	srv.SetTrustedProxies(gs.trustedProxies)
+	if err := RegisterApisFromWhitelist(apis, gs.modules, srv, false); err != nil {
+		return err
+	}
+	var proxyProtocol *rpc.TrustedProxies
+	if gs.proxyProtocol {
+		proxyProtocol = gs.trustedProxies
+	}
+	isTls, listener, err := startListener(gs.endpoint, tlsConfigSource, proxyProtocol, "h2")
+	if err != nil {
+		srv.Stop()
+		return err
+	}
+	gs.grpc = grpc.NewServer()
+	srv.RegisterGRPC(gs.grpc)
+	go gs.grpc.Serve(listener)
+
+	gs.listener, gs.srv = listener, srv
+	gs.log.Info("gRPC server started", "endpoint", listener.Addr(), "isTls", isTls, "isMultitenant", gs.isMultitenant)
+	return nil
+}
+
+func (gs *grpcServer) stop() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.listener == nil {
+		return // not running
+	}
+	gs.grpc.Stop()
+	gs.srv.Stop()
+	gs.log.Info("gRPC server stopped", "endpoint", gs.listener.Addr())
+	gs.listener, gs.grpc, gs.srv = nil, nil, nil
+}
+
+// listenAddr returns the listening address of the server.
+func (gs *grpcServer) listenAddr() string {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.listener != nil {
+		return gs.listener.Addr().String()
+	}
+	return gs.endpoint
+}
+
 // RegisterApisFromWhitelist checks the given modules' availability, generates a whitelist based on the allowed modules,
 // and then registers all of the APIs exposed by the services.
 func RegisterApisFromWhitelist(apis []rpc.API, modules []string, srv *rpc.Server, exposeAll bool) error {
@@ -18,14 +18,25 @@ package node
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/internal/testlog"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/rpc/jsonrpcpb"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
 )
 
 // TestCorsHandler makes sure CORS are properly handled on the http server.
@@ -123,3 +134,75 @@ func testRequest(t *testing.T, key, value, host string, srv *httpServer) *http.R
 	}
 	return resp
 }
+
+// Quorum
+func TestHTTP2Cleartext(t *testing.T) {
+	srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts).withHTTP2(true)
+	assert.NoError(t, srv.enableRPC(nil, httpConfig{}, nil))
+	assert.NoError(t, srv.setListenAddr("localhost", 0))
+	assert.NoError(t, srv.start(nil))
+	defer srv.stop()
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	body := bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`))
+	resp, err := client.Post("http://"+srv.listenAddr(), "application/json", body)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor)
+}
+
+func TestGRPCServer(t *testing.T) {
+	srv := newGRPCServer(testlog.Logger(t, log.LvlDebug), "127.0.0.1:0", nil)
+	assert.NoError(t, srv.start(nil, nil, nil))
+	defer srv.stop()
+
+	conn, err := grpc.Dial(srv.listenAddr(), grpc.WithInsecure())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := jsonrpcpb.NewJsonRpcClient(conn).Call(ctx, &jsonrpcpb.Payload{Data: []byte(`{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"rpc":"1.0"}}`, string(resp.Data))
+}
+
+func TestHTTPHandlerStack_gzipMinSize(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	for _, tc := range []struct {
+		minSize  int
+		wantGzip bool
+	}{
+		{minSize: 0, wantGzip: true},
+		{minSize: 99, wantGzip: true},
+		{minSize: 100, wantGzip: false},
+		{minSize: -1, wantGzip: false},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		NewHTTPHandlerStack(handler, nil, []string{"*"}, tc.minSize).ServeHTTP(rec, req)
+
+		got := rec.Body.Bytes()
+		if tc.wantGzip {
+			assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"), "minSize %d", tc.minSize)
+			r, err := gzip.NewReader(rec.Body)
+			assert.NoError(t, err)
+			got, err = ioutil.ReadAll(r)
+			assert.NoError(t, err)
+		} else {
+			assert.Empty(t, rec.Header().Get("Content-Encoding"), "minSize %d", tc.minSize)
+		}
+		assert.Equal(t, body, string(got), "minSize %d", tc.minSize)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package clientserver implements the scaffolding of the Quorum sub-protocols between
+// a client and its designated server, e.g. qlight and state sync: the exchange of the
+// status of the peers, and the matching of the answers of the server to the requests
+// of the client by request ID.
+package clientserver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+var (
+	ErrServerDisconnected = errors.New("server not connected")
+	ErrClientClosed       = errors.New("client closed")
+	ErrCanceled           = errors.New("request canceled")
+	ErrUnexpectedMsg      = errors.New("unexpected message")
+)
+
+// Status is the first message sent by both sides
+type Status interface {
+	// ProtocolVersion returns the version of the protocol the side runs
+	ProtocolVersion() uint32
+}
+
+// Spec describes a sub-protocol
+type Spec struct {
+	Name       string // name of the sub-protocol, e.g. qlight
+	Version    uint
+	Length     uint64
+	StatusCode uint64 // code of the status message
+	MaxMsgSize uint32
+
+	HandshakeTimeout time.Duration // how long the status of the peer is waited for
+	RequestTimeout   time.Duration // how long an answer of the server is waited for
+
+	// NewStatus returns the value the status of a peer is decoded into
+	NewStatus func() Status
+}
+
+// Protocol returns the sub-protocol running run with the peers
+func (s *Spec) Protocol(run func(p *p2p.Peer, rw p2p.MsgReadWriter) error) p2p.Protocol {
+	return p2p.Protocol{
+		Name:    s.Name,
+		Version: s.Version,
+		Length:  s.Length,
+		Run:     run,
+	}
+}
+
+// Handshake exchanges the status with the peer and returns the status of the peer
+func (s *Spec) Handshake(rw p2p.MsgReadWriter, status Status) (Status, error) {
+	var peerStatus Status
+	errc := make(chan error, 2)
+	go func() {
+		errc <- p2p.Send(rw, s.StatusCode, status)
+	}()
+	go func() {
+		var err error
+		peerStatus, err = s.readStatus(rw)
+		errc <- err
+	}()
+	timeout := time.NewTimer(s.HandshakeTimeout)
+	defer timeout.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return nil, err
+			}
+		case <-timeout.C:
+			return nil, p2p.DiscReadTimeout
+		}
+	}
+	return peerStatus, nil
+}
+
+func (s *Spec) readStatus(rw p2p.MsgReadWriter) (Status, error) {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return nil, err
+	}
+	defer msg.Discard()
+	if msg.Code != s.StatusCode {
+		return nil, fmt.Errorf("%w: first message has code %#x, want status", ErrUnexpectedMsg, msg.Code)
+	}
+	if msg.Size > s.MaxMsgSize {
+		return nil, fmt.Errorf("%s message too large: %v > %v", s.Name, msg.Size, s.MaxMsgSize)
+	}
+	status := s.NewStatus()
+	if err := msg.Decode(status); err != nil {
+		return nil, err
+	}
+	if status.ProtocolVersion() != uint32(s.Version) {
+		return nil, fmt.Errorf("%s protocol version mismatch: %d != %d", s.Name, status.ProtocolVersion(), s.Version)
+	}
+	return status, nil
+}
+
+// Idle discards the messages of a peer which neither side serves, e.g. two
+// servers, so that the other protocols of the peer keep running
+func Idle(rw p2p.MsgReadWriter) error {
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		msg.Discard()
+	}
+}
+
+// Client is the connection of a client to its server. The requests wait for the
+// server to connect, and are answered once the server sends the answer with their
+// request ID.
+type Client struct {
+	spec     *Spec
+	serverID enode.ID
+
+	mu        sync.Mutex
+	server    p2p.MsgReadWriter
+	reqID     uint64
+	pending   map[uint64]chan interface{}
+	connected chan struct{}
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClient creates the client of the server with the node ID serverID
+func NewClient(spec *Spec, serverID enode.ID) *Client {
+	return &Client{
+		spec:      spec,
+		serverID:  serverID,
+		pending:   make(map[uint64]chan interface{}),
+		connected: make(chan struct{}),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Close aborts the pending and future requests
+func (c *Client) Close() {
+	c.closeOnce.Do(func() { close(c.quit) })
+}
+
+// Closed returns a channel closed once the client is closed
+func (c *Client) Closed() <-chan struct{} {
+	return c.quit
+}
+
+// IsServer reports whether p is the server of the client
+func (c *Client) IsServer(p *p2p.Peer) bool {
+	return p.ID() == c.serverID
+}
+
+// Connected returns a channel closed once the server is connected
+func (c *Client) Connected() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// WaitConnected waits for the server to connect, unless cancel or the client is closed
+func (c *Client) WaitConnected(cancel <-chan struct{}) error {
+	select {
+	case <-c.Connected():
+		return nil
+	case <-cancel:
+		return ErrCanceled
+	case <-c.quit:
+		return ErrClientClosed
+	}
+}
+
+// Serve reads the answers of the server until the connection to it is torn down,
+// decode returning the request ID and the answer of a message
+func (c *Client) Serve(rw p2p.MsgReadWriter, decode func(msg p2p.Msg) (uint64, interface{}, error)) error {
+	c.setServer(rw)
+	defer c.removeServer(rw)
+
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		if msg.Size > c.spec.MaxMsgSize {
+			msg.Discard()
+			return ErrUnexpectedMsg
+		}
+		reqID, resp, err := decode(msg)
+		msg.Discard()
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[reqID]
+		delete(c.pending, reqID)
+		c.mu.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+}
+
+// setServer records the connection to the server and wakes up the requests waiting
+// for it
+func (c *Client) setServer(server p2p.MsgReadWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.server = server
+	select {
+	case <-c.connected:
+	default:
+		close(c.connected)
+	}
+}
+
+// removeServer forgets the connection to the server once it is torn down, the
+// pending requests are answered by the timeout
+func (c *Client) removeServer(server p2p.MsgReadWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.server != server {
+		return
+	}
+	c.server = nil
+	c.connected = make(chan struct{})
+}
+
+// Request sends a request to the server and waits for its answer
+func (c *Client) Request(code uint64, makeReq func(reqID uint64) interface{}, cancel <-chan struct{}) (interface{}, error) {
+	c.mu.Lock()
+	server := c.server
+	if server == nil {
+		c.mu.Unlock()
+		return nil, ErrServerDisconnected
+	}
+	c.reqID++
+	reqID := c.reqID
+	ch := make(chan interface{}, 1)
+	c.pending[reqID] = ch
+	c.mu.Unlock()
+
+	cleanup := func() {
+		c.mu.Lock()
+		delete(c.pending, reqID)
+		c.mu.Unlock()
+	}
+	if err := p2p.Send(server, code, makeReq(reqID)); err != nil {
+		cleanup()
+		return nil, err
+	}
+	timeout := time.NewTimer(c.spec.RequestTimeout)
+	defer timeout.Stop()
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-timeout.C:
+		cleanup()
+		return nil, p2p.DiscReadTimeout
+	case <-cancel:
+		cleanup()
+		return nil, ErrCanceled
+	case <-c.quit:
+		cleanup()
+		return nil, ErrClientClosed
+	}
+}
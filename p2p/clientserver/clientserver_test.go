@@ -0,0 +1,106 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package clientserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatus struct {
+	Version uint32
+}
+
+func (s *testStatus) ProtocolVersion() uint32 {
+	return s.Version
+}
+
+type testMsg struct {
+	ReqID uint64
+	Value string
+}
+
+var testSpec = &Spec{
+	Name:             "test",
+	Version:          1,
+	Length:           2,
+	StatusCode:       0x00,
+	MaxMsgSize:       1024,
+	HandshakeTimeout: time.Second,
+	RequestTimeout:   time.Second,
+	NewStatus:        func() Status { return new(testStatus) },
+}
+
+func TestSpec_Handshake_whenVersionMismatch(t *testing.T) {
+	rw1, rw2 := p2p.MsgPipe()
+	defer rw1.Close()
+	go testSpec.Handshake(rw2, &testStatus{Version: 2})
+
+	_, err := testSpec.Handshake(rw1, &testStatus{Version: 1})
+
+	assert.EqualError(t, err, "test protocol version mismatch: 2 != 1")
+}
+
+func TestClient_Request(t *testing.T) {
+	client := NewClient(testSpec, enode.ID{1})
+	defer client.Close()
+	clientRW, serverRW := p2p.MsgPipe()
+	defer clientRW.Close()
+	go client.Serve(clientRW, func(msg p2p.Msg) (uint64, interface{}, error) {
+		var resp testMsg
+		err := msg.Decode(&resp)
+		return resp.ReqID, resp.Value, err
+	})
+	// the server echoes the requests
+	go func() {
+		for {
+			msg, err := serverRW.ReadMsg()
+			if err != nil {
+				return
+			}
+			var req testMsg
+			msg.Decode(&req)
+			p2p.Send(serverRW, 0x01, &req)
+		}
+	}()
+	<-client.Connected()
+
+	resp, err := client.Request(0x01, func(reqID uint64) interface{} {
+		return &testMsg{ReqID: reqID, Value: "value"}
+	}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "value", resp)
+}
+
+func TestClient_Request_whenCanceledOrClosed(t *testing.T) {
+	client := NewClient(testSpec, enode.ID{1})
+	_, err := client.Request(0x01, nil, nil)
+	assert.Equal(t, ErrServerDisconnected, err)
+
+	cancel := make(chan struct{})
+	close(cancel)
+	assert.Equal(t, ErrCanceled, client.WaitConnected(cancel))
+
+	client.Close()
+	assert.Equal(t, ErrClientClosed, client.WaitConnected(nil))
+}
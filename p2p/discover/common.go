@@ -47,6 +47,12 @@ type Config struct {
 	Log          log.Logger         // if set, log messages go here
 	ValidSchemes enr.IdentityScheme // allowed identity schemes
 	Clock        mclock.Clock
+
+	// Quorum
+	// NodeFilter restricts discovery (v4 and v5) to the nodes it accepts, e.g.: the
+	// permissioned nodes: the records of the other nodes are rejected, their requests
+	// are not answered and they are not advertised to the requesters.
+	NodeFilter func(*enode.Node) bool
 }
 
 func (cfg Config) withDefaults() Config {
@@ -42,6 +42,7 @@ var (
 	errExpired          = errors.New("expired")
 	errUnsolicitedReply = errors.New("unsolicited reply")
 	errUnknownNode      = errors.New("unknown node")
+	errNodeFiltered     = errors.New("node filtered") // Quorum
 	errTimeout          = errors.New("RPC timeout")
 	errClockWarp        = errors.New("reply deadline too far in the future")
 	errClosed           = errors.New("socket closed")
@@ -80,6 +81,8 @@ type UDPv4 struct {
 	gotreply        chan reply
 	closeCtx        context.Context
 	cancelCloseCtx  context.CancelFunc
+
+	nodeFilter func(*enode.Node) bool // Quorum
 }
 
 // replyMatcher represents a pending reply.
@@ -141,6 +144,7 @@ func ListenV4(c UDPConn, ln *enode.LocalNode, cfg Config) (*UDPv4, error) {
 		closeCtx:        closeCtx,
 		cancelCloseCtx:  cancel,
 		log:             cfg.Log,
+		nodeFilter:      cfg.NodeFilter,
 	}
 
 	tab, err := newTable(t, ln.Database(), cfg.Bootnodes, t.log)
@@ -312,6 +316,9 @@ func (t *UDPv4) findnode(toid enode.ID, toaddr *net.UDPAddr, target v4wire.Pubke
 		for _, rn := range reply.Nodes {
 			nreceived++
 			n, err := t.nodeFromRPC(toaddr, rn)
+			if err == nil && !t.accepts(&n.Node) {
+				err = errNodeFiltered
+			}
 			if err != nil {
 				t.log.Trace("Invalid neighbor node received", "ip", rn.IP, "addr", toaddr, "err", err)
 				continue
@@ -550,6 +557,10 @@ func (t *UDPv4) handlePacket(from *net.UDPAddr, buf []byte) error {
 	}
 	packet := t.wrapPacket(rawpacket)
 	fromID := fromKey.ID()
+	// Quorum: the requests of the filtered nodes are not answered
+	if err == nil && !t.acceptsRequester(rawpacket, from, fromKey) {
+		err = errNodeFiltered
+	}
 	if err == nil && packet.preverify != nil {
 		err = packet.preverify(packet, from, fromID, fromKey)
 	}
@@ -560,6 +571,27 @@ func (t *UDPv4) handlePacket(from *net.UDPAddr, buf []byte) error {
 	return err
 }
 
+// Quorum
+// accepts reports whether n passes the node filter.
+func (t *UDPv4) accepts(n *enode.Node) bool {
+	return t.nodeFilter == nil || t.nodeFilter(n)
+}
+
+// Quorum
+// acceptsRequester reports whether the request p of the node with the given key is
+// answered, the replies are matched against the requests sent.
+func (t *UDPv4) acceptsRequester(p v4wire.Packet, from *net.UDPAddr, fromKey v4wire.Pubkey) bool {
+	if t.nodeFilter == nil {
+		return true
+	}
+	switch p.Kind() {
+	case v4wire.PingPacket, v4wire.FindnodePacket, v4wire.ENRRequestPacket:
+		key, err := v4wire.DecodePubkey(crypto.S256(), fromKey)
+		return err == nil && t.nodeFilter(enode.NewV4(key, from.IP, 0, from.Port))
+	}
+	return true
+}
+
 // checkBond checks if the given node has a recent enough endpoint proof.
 func (t *UDPv4) checkBond(id enode.ID, ip net.IP) bool {
 	return time.Since(t.db.LastPongReceived(id, ip)) < bondExpiration
@@ -731,7 +763,8 @@ func (t *UDPv4) handleFindnode(h *packetHandlerV4, from *net.UDPAddr, fromID eno
 	p := v4wire.Neighbors{Expiration: uint64(time.Now().Add(expiration).Unix())}
 	var sent bool
 	for _, n := range closest {
-		if netutil.CheckRelayIP(from.IP, n.IP()) == nil {
+		// Quorum: only the accepted nodes are advertised
+		if netutil.CheckRelayIP(from.IP, n.IP()) == nil && t.accepts(&n.Node) {
 			p.Nodes = append(p.Nodes, nodeToRPC(n))
 		}
 		if len(p.Nodes) == v4wire.MaxNeighbors {
@@ -59,6 +59,11 @@ type udpTest struct {
 }
 
 func newUDPTest(t *testing.T) *udpTest {
+	return newUDPTestWithFilter(t, nil)
+}
+
+// Quorum
+func newUDPTestWithFilter(t *testing.T, filter func(*enode.Node) bool) *udpTest {
 	test := &udpTest{
 		t:          t,
 		pipe:       newpipe(),
@@ -72,6 +77,7 @@ func newUDPTest(t *testing.T) *udpTest {
 	test.udp, _ = ListenV4(test.pipe, ln, Config{
 		PrivateKey: test.localkey,
 		Log:        testlog.Logger(t, log.LvlTrace),
+		NodeFilter: filter,
 	})
 	test.table = test.udp.tab
 	// Wait for initial refresh so the table doesn't send unexpected findnode.
@@ -304,6 +310,40 @@ func TestUDPv4_findnode(t *testing.T) {
 	waitNeighbors(want)
 }
 
+// Quorum
+// This test checks that the node filter restricts the answered requests and the
+// advertised nodes.
+func TestUDPv4_findnode_whenNodeFilter(t *testing.T) {
+	allowed := make(map[enode.ID]bool)
+	test := newUDPTestWithFilter(t, func(n *enode.Node) bool { return allowed[n.ID()] })
+	defer test.close()
+
+	var nodes []*node
+	for i := 0; i < 3; i++ {
+		key := newkey()
+		n := wrapNode(enode.NewV4(&key.PublicKey, net.IP{10, 13, 0, byte(i)}, 0, 2000))
+		n.livenessChecks = 1
+		nodes = append(nodes, n)
+	}
+	fillTable(test.table, nodes)
+	allowed[nodes[0].ID()] = true
+	remoteID := v4wire.EncodePubkey(&test.remotekey.PublicKey).ID()
+	test.table.db.UpdateLastPongReceived(remoteID, test.remoteaddr.IP, time.Now())
+
+	// The requests of the filtered node are ignored.
+	test.packetIn(errNodeFiltered, &v4wire.Ping{From: testRemote, To: testLocalAnnounced, Version: 4, Expiration: futureExp})
+	test.packetIn(errNodeFiltered, &v4wire.Findnode{Target: testTarget, Expiration: futureExp})
+
+	// Only the allowed nodes are advertised.
+	allowed[remoteID] = true
+	test.packetIn(nil, &v4wire.Findnode{Target: testTarget, Expiration: futureExp})
+	test.waitPacketOut(func(p *v4wire.Neighbors, to *net.UDPAddr, hash []byte) {
+		if len(p.Nodes) != 1 || p.Nodes[0].ID.ID() != nodes[0].ID() {
+			t.Errorf("wrong neighbors: got %v, want %v", p.Nodes, nodes[0].ID())
+		}
+	})
+}
+
 func TestUDPv4_findnodeMultiReply(t *testing.T) {
 	test := newUDPTest(t)
 	defer test.close()
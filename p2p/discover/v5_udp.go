@@ -71,6 +71,7 @@ type UDPv5 struct {
 	log          log.Logger
 	clock        mclock.Clock
 	validSchemes enr.IdentityScheme
+	nodeFilter   func(*enode.Node) bool // Quorum
 
 	// talkreq handler registry
 	trlock     sync.Mutex
@@ -145,6 +146,7 @@ func newUDPv5(conn UDPConn, ln *enode.LocalNode, cfg Config) (*UDPv5, error) {
 		log:          cfg.Log,
 		validSchemes: cfg.ValidSchemes,
 		clock:        cfg.Clock,
+		nodeFilter:   cfg.NodeFilter,
 		trhandlers:   make(map[string]func([]byte) []byte),
 		// channels into dispatch
 		packetInCh:    make(chan ReadPacket, 1),
@@ -416,10 +418,30 @@ func (t *UDPv5) verifyResponseNode(c *callV5, r *enr.Record, distances []uint, s
 	if _, ok := seen[node.ID()]; ok {
 		return nil, fmt.Errorf("duplicate record")
 	}
+	if !t.accepts(node) {
+		return nil, errNodeFiltered
+	}
 	seen[node.ID()] = struct{}{}
 	return node, nil
 }
 
+// Quorum
+// accepts reports whether n passes the node filter.
+func (t *UDPv5) accepts(n *enode.Node) bool {
+	return t.nodeFilter == nil || t.nodeFilter(n)
+}
+
+// Quorum
+// acceptsRequester reports whether the requests of the given node are answered: its
+// record must be known, from a handshake or the table, and pass the node filter.
+func (t *UDPv5) acceptsRequester(id enode.ID) bool {
+	if t.nodeFilter == nil {
+		return true
+	}
+	n := t.getNode(id)
+	return n != nil && t.nodeFilter(n)
+}
+
 func containsUint(x uint, xs []uint) bool {
 	for _, v := range xs {
 		if x == v {
@@ -637,6 +659,10 @@ func (t *UDPv5) handlePacket(rawpacket []byte, fromAddr *net.UDPAddr) error {
 		return err
 	}
 	if fromNode != nil {
+		if !t.accepts(fromNode) {
+			t.log.Debug("Rejected discv5 handshake of filtered node", "id", fromID, "addr", addr)
+			return nil
+		}
 		// Handshake succeeded, add to table.
 		t.tab.addSeenNode(wrapNode(fromNode))
 	}
@@ -681,6 +707,14 @@ func (t *UDPv5) getNode(id enode.ID) *enode.Node {
 
 // handle processes incoming packets according to their message type.
 func (t *UDPv5) handle(p v5wire.Packet, fromID enode.ID, fromAddr *net.UDPAddr) {
+	// Quorum: the requests of the filtered nodes are not answered
+	switch p.Kind() {
+	case v5wire.PingMsg, v5wire.FindnodeMsg, v5wire.TalkRequestMsg:
+		if !t.acceptsRequester(fromID) {
+			t.log.Debug("Ignored "+p.Name()+" of filtered node", "id", fromID, "addr", fromAddr)
+			return
+		}
+	}
 	switch p := p.(type) {
 	case *v5wire.Unknown:
 		t.handleUnknown(p, fromID, fromAddr)
@@ -793,6 +827,10 @@ func (t *UDPv5) collectTableNodes(rip net.IP, distances []uint, limit int) []*en
 			if netutil.CheckRelayIP(rip, n.IP()) != nil {
 				continue
 			}
+			// Quorum: only the accepted nodes are advertised
+			if dist != 0 && !t.accepts(n) {
+				continue
+			}
 			nodes = append(nodes, n)
 			if len(nodes) >= limit {
 				return nodes
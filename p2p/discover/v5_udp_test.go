@@ -196,6 +196,41 @@ func TestUDPv5_findnodeHandling(t *testing.T) {
 	test.expectNodes([]byte{5}, 5, nodes)
 }
 
+// Quorum
+// This test checks that the node filter restricts the answered requests and the
+// advertised nodes.
+func TestUDPv5_findnodeHandling_whenNodeFilter(t *testing.T) {
+	t.Parallel()
+	allowed := make(map[enode.ID]bool)
+	test := newUDPV5TestWithFilter(t, func(n *enode.Node) bool { return allowed[n.ID()] })
+	defer test.close()
+
+	nodes253 := nodesAtDistance(test.table.self().ID(), 253, 10)
+	fillTable(test.table, wrapNodes(nodes253))
+	allowed[nodes253[0].ID()] = true
+	allowed[nodes253[1].ID()] = true
+
+	// The requests of the unknown node are ignored.
+	test.packetIn(&v5wire.Ping{ReqID: []byte("foo")})
+
+	remote := test.getNode(test.remotekey, test.remoteaddr).Node()
+	allowed[remote.ID()] = true
+	if err := test.db.UpdateNode(remote); err != nil {
+		t.Fatal(err)
+	}
+
+	test.packetIn(&v5wire.Ping{ReqID: []byte("bar")})
+	test.waitPacketOut(func(p *v5wire.Pong, addr *net.UDPAddr, _ v5wire.Nonce) {
+		if !bytes.Equal(p.ReqID, []byte("bar")) {
+			t.Error("wrong request ID in response:", p.ReqID)
+		}
+	})
+
+	// Only the allowed nodes are advertised.
+	test.packetIn(&v5wire.Findnode{ReqID: []byte{0}, Distances: []uint{253}})
+	test.expectNodes([]byte{0}, 1, nodes253[:2])
+}
+
 func (test *udpV5Test) expectNodes(wantReqID []byte, wantTotal uint8, wantNodes []*enode.Node) {
 	nodeSet := make(map[enode.ID]*enr.Record)
 	for _, n := range wantNodes {
@@ -663,6 +698,11 @@ func (c *testCodec) decodeFrame(input []byte) (frame testCodecFrame, p v5wire.Pa
 }
 
 func newUDPV5Test(t *testing.T) *udpV5Test {
+	return newUDPV5TestWithFilter(t, nil)
+}
+
+// Quorum
+func newUDPV5TestWithFilter(t *testing.T, filter func(*enode.Node) bool) *udpV5Test {
 	test := &udpV5Test{
 		t:          t,
 		pipe:       newpipe(),
@@ -680,6 +720,7 @@ func newUDPV5Test(t *testing.T) *udpV5Test {
 		PrivateKey:   test.localkey,
 		Log:          testlog.Logger(t, log.LvlTrace),
 		ValidSchemes: enode.ValidSchemesForTesting,
+		NodeFilter:   filter,
 	})
 	test.udp.codec = &testCodec{test: test, id: ln.ID()}
 	test.table = test.udp.tab
@@ -0,0 +1,282 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package identity binds the node keys to certificates issued by the organizations of a
+// permissioned network.
+//
+// The node certificate holds the node public key as an enode URI subject alternative
+// name, e.g.: enode://<128 hex characters>@127.0.0.1:21000, and is exchanged right after the
+// devp2p handshake. The peers validate it against the trusted CA certificates and the
+// revocation lists. The certificate files are reloaded when they change so that the
+// certificates can be rotated without restarting the node nor changing its key.
+package identity
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// enodeScheme is the scheme of the subject alternative name holding the node key
+const enodeScheme = "enode"
+
+var (
+	ErrNoCertificate = errors.New("no node certificate")
+	ErrKeyMismatch   = errors.New("node certificate is not issued for the node key")
+	ErrRevoked       = errors.New("node certificate is revoked")
+)
+
+// Config locates the PEM files of the node identity
+type Config struct {
+	Certificate string `toml:",omitempty"` // node certificate, followed by the intermediate certificates
+	CA          string `toml:",omitempty"` // trusted CA certificates
+	CRL         string `toml:",omitempty"` // optional revocation lists
+}
+
+// Enabled reports whether the node identity is configured
+func (c Config) Enabled() bool {
+	return c.Certificate != ""
+}
+
+// Identity holds the certificates of the node and validates the ones of its peers
+type Identity struct {
+	config Config
+	key    *ecdsa.PublicKey
+	now    func() time.Time
+
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+	chain    [][]byte
+	roots    *x509.CertPool
+	crls     []*pkix.CertificateList
+}
+
+// New loads the certificates of config and checks the node certificate is valid for the
+// node key
+func New(config Config, key *ecdsa.PublicKey) (*Identity, error) {
+	if !config.Enabled() {
+		return nil, ErrNoCertificate
+	}
+	if config.CA == "" {
+		return nil, errors.New("no CA certificates")
+	}
+	id := &Identity{
+		config:   config,
+		key:      key,
+		now:      time.Now,
+		modTimes: make(map[string]time.Time),
+	}
+	if err := id.load(); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// Certificates returns the DER certificates presented to the peers, the node
+// certificate first. The files are reloaded if they have changed, e.g.: after a
+// certificate rotation.
+func (id *Identity) Certificates() [][]byte {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	id.reloadIfChanged()
+	return id.chain
+}
+
+// Verify checks the certificates presented by a peer: they must chain up to a trusted
+// CA, not be revoked and the node certificate must be issued for the peer key
+func (id *Identity) Verify(key *ecdsa.PublicKey, chain [][]byte) error {
+	id.mu.Lock()
+	id.reloadIfChanged()
+	roots, crls := id.roots, id.crls
+	id.mu.Unlock()
+
+	return verify(key, chain, roots, crls, id.now())
+}
+
+// reloadIfChanged reloads the files when their modification time changes, keeping
+// the current certificates if the new ones are invalid
+func (id *Identity) reloadIfChanged() {
+	changed := false
+	for _, path := range id.paths() {
+		if info, err := os.Stat(path); err == nil && !info.ModTime().Equal(id.modTimes[path]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	if err := id.load(); err != nil {
+		log.Error("Failed to reload node identity, keeping the current certificates", "err", err)
+		return
+	}
+	log.Info("Reloaded node identity")
+}
+
+func (id *Identity) paths() []string {
+	paths := []string{id.config.Certificate, id.config.CA}
+	if id.config.CRL != "" {
+		paths = append(paths, id.config.CRL)
+	}
+	return paths
+}
+
+// load reads the files, the lock being held by the caller except in New
+func (id *Identity) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, path := range id.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[path] = info.ModTime()
+	}
+	chain, err := readPEM(id.config.Certificate, "CERTIFICATE")
+	if err != nil {
+		return err
+	}
+	caCerts, err := readPEM(id.config.CA, "CERTIFICATE")
+	if err != nil {
+		return err
+	}
+	roots := x509.NewCertPool()
+	for _, der := range caCerts {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("invalid CA certificate in %s: %v", id.config.CA, err)
+		}
+		roots.AddCert(cert)
+	}
+	var crls []*pkix.CertificateList
+	if id.config.CRL != "" {
+		ders, err := readPEM(id.config.CRL, "X509 CRL")
+		if err != nil {
+			return err
+		}
+		for _, der := range ders {
+			crl, err := x509.ParseDERCRL(der)
+			if err != nil {
+				return fmt.Errorf("invalid revocation list in %s: %v", id.config.CRL, err)
+			}
+			crls = append(crls, crl)
+		}
+	}
+	if err := verify(id.key, chain, roots, crls, id.now()); err != nil {
+		return fmt.Errorf("invalid node certificate %s: %v", id.config.Certificate, err)
+	}
+	id.modTimes, id.chain, id.roots, id.crls = modTimes, chain, roots, crls
+	return nil
+}
+
+func verify(key *ecdsa.PublicKey, chain [][]byte, roots *x509.CertPool, crls []*pkix.CertificateList, now time.Time) error {
+	if len(chain) == 0 {
+		return ErrNoCertificate
+	}
+	certs := make([]*x509.Certificate, len(chain))
+	for i, der := range chain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	verified, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return err
+	}
+	if !issuedFor(certs[0], key) {
+		return ErrKeyMismatch
+	}
+	for _, c := range verified {
+		if isRevoked(c, crls) {
+			return ErrRevoked
+		}
+	}
+	return nil
+}
+
+// issuedFor reports whether cert holds the node key as an enode URI
+func issuedFor(cert *x509.Certificate, key *ecdsa.PublicKey) bool {
+	want := hex.EncodeToString(crypto.FromECDSAPub(key)[1:])
+	for _, uri := range cert.URIs {
+		if uri.Scheme == enodeScheme && uri.User != nil && uri.User.Username() == want {
+			return true
+		}
+	}
+	return false
+}
+
+// isRevoked reports whether a certificate of the verified chain is listed in a
+// revocation list signed by its issuer
+func isRevoked(chain []*x509.Certificate, crls []*pkix.CertificateList) bool {
+	for i, cert := range chain[:len(chain)-1] {
+		issuer := chain[i+1]
+		for _, crl := range crls {
+			if !bytes.Equal(cert.RawIssuer, issuer.RawSubject) || issuer.CheckCRLSignature(crl) != nil {
+				continue
+			}
+			for _, revoked := range crl.TBSCertList.RevokedCertificates {
+				if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// readPEM returns the DER content of the blocks of the given type
+func readPEM(path string, blockType string) ([][]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ders [][]byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type == blockType {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 {
+		return nil, fmt.Errorf("no %s in %s", blockType, path)
+	}
+	return ders, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{name}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns a DER certificate binding the node key
+func (ca *testCA) issue(t *testing.T, serial int64, nodeKey *ecdsa.PublicKey) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	uri, err := url.Parse("enode://" + hex.EncodeToString(crypto.FromECDSAPub(nodeKey)[1:]) + "@127.0.0.1:21000")
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return der
+}
+
+func (ca *testCA) revoke(t *testing.T, serials ...int64) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, s := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(s), RevocationTime: time.Now()})
+	}
+	der, err := ca.cert.CreateCRL(rand.Reader, ca.key, revoked, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return der
+}
+
+func writePEM(t *testing.T, path string, blockType string, ders ...[]byte) {
+	var data []byte
+	for _, der := range ders {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})...)
+	}
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+}
+
+func setup(t *testing.T) (dir string, ca *testCA, nodeKey *ecdsa.PrivateKey, config Config) {
+	dir, err := ioutil.TempDir("", "identity")
+	require.NoError(t, err)
+	ca = newTestCA(t, "org1")
+	nodeKey, err = crypto.GenerateKey()
+	require.NoError(t, err)
+	config = Config{
+		Certificate: filepath.Join(dir, "node.pem"),
+		CA:          filepath.Join(dir, "ca.pem"),
+	}
+	writePEM(t, config.Certificate, "CERTIFICATE", ca.issue(t, 2, &nodeKey.PublicKey))
+	writePEM(t, config.CA, "CERTIFICATE", ca.cert.Raw)
+	return dir, ca, nodeKey, config
+}
+
+func TestNew(t *testing.T) {
+	dir, _, nodeKey, config := setup(t)
+	defer os.RemoveAll(dir)
+
+	id, err := New(config, &nodeKey.PublicKey)
+	require.NoError(t, err)
+	assert.Len(t, id.Certificates(), 1)
+
+	otherKey, _ := crypto.GenerateKey()
+	_, err = New(config, &otherKey.PublicKey)
+	assert.Error(t, err, "certificate of another node")
+
+	_, err = New(Config{}, &nodeKey.PublicKey)
+	assert.Equal(t, ErrNoCertificate, err)
+}
+
+func TestIdentity_Verify(t *testing.T) {
+	dir, ca, nodeKey, config := setup(t)
+	defer os.RemoveAll(dir)
+	id, err := New(config, &nodeKey.PublicKey)
+	require.NoError(t, err)
+
+	peerKey, _ := crypto.GenerateKey()
+	assert.NoError(t, id.Verify(&peerKey.PublicKey, [][]byte{ca.issue(t, 3, &peerKey.PublicKey)}))
+
+	assert.Equal(t, ErrNoCertificate, id.Verify(&peerKey.PublicKey, nil))
+
+	otherKey, _ := crypto.GenerateKey()
+	assert.Equal(t, ErrKeyMismatch, id.Verify(&peerKey.PublicKey, [][]byte{ca.issue(t, 4, &otherKey.PublicKey)}))
+
+	untrusted := newTestCA(t, "org2")
+	assert.Error(t, id.Verify(&peerKey.PublicKey, [][]byte{untrusted.issue(t, 5, &peerKey.PublicKey)}), "unknown CA")
+}
+
+func TestIdentity_Verify_whenRevoked(t *testing.T) {
+	dir, ca, nodeKey, config := setup(t)
+	defer os.RemoveAll(dir)
+	config.CRL = filepath.Join(dir, "crl.pem")
+	writePEM(t, config.CRL, "X509 CRL", ca.revoke(t, 3))
+	id, err := New(config, &nodeKey.PublicKey)
+	require.NoError(t, err)
+
+	peerKey, _ := crypto.GenerateKey()
+	assert.Equal(t, ErrRevoked, id.Verify(&peerKey.PublicKey, [][]byte{ca.issue(t, 3, &peerKey.PublicKey)}))
+	assert.NoError(t, id.Verify(&peerKey.PublicKey, [][]byte{ca.issue(t, 4, &peerKey.PublicKey)}))
+
+	// a revocation list of another CA is ignored
+	other := newTestCA(t, "org2")
+	writePEM(t, config.CRL, "X509 CRL", other.revoke(t, 3))
+	touch(t, config.CRL)
+	assert.NoError(t, id.Verify(&peerKey.PublicKey, [][]byte{ca.issue(t, 3, &peerKey.PublicKey)}))
+}
+
+func TestIdentity_Certificates_whenRotated(t *testing.T) {
+	dir, ca, nodeKey, config := setup(t)
+	defer os.RemoveAll(dir)
+	id, err := New(config, &nodeKey.PublicKey)
+	require.NoError(t, err)
+	before := id.Certificates()
+
+	rotated := ca.issue(t, 10, &nodeKey.PublicKey)
+	writePEM(t, config.Certificate, "CERTIFICATE", rotated)
+	touch(t, config.Certificate)
+	assert.Equal(t, [][]byte{rotated}, id.Certificates())
+
+	// an invalid certificate is not loaded
+	otherKey, _ := crypto.GenerateKey()
+	writePEM(t, config.Certificate, "CERTIFICATE", ca.issue(t, 11, &otherKey.PublicKey))
+	touch(t, config.Certificate)
+	assert.Equal(t, [][]byte{rotated}, id.Certificates())
+	assert.NotEqual(t, before, id.Certificates())
+}
+
+// touch makes sure the modification time changes whatever the file system resolution
+func touch(t *testing.T, path string) {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	modTime := info.ModTime().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
@@ -101,6 +101,28 @@ func Send(w MsgWriter, msgcode uint64, data interface{}) error {
 	return w.WriteMsg(Msg{Code: msgcode, Size: uint32(size), Payload: r})
 }
 
+// Quorum
+// PriorityMsgWriter is implemented by the MsgWriters able to write a message before
+// the pending writes of the peer, e.g.: for the consensus messages not to queue behind
+// block bodies.
+type PriorityMsgWriter interface {
+	WriteMsgPriority(Msg) error
+}
+
+// SendPriority is the same as Send but the message is written before the pending
+// writes of the peer if w is a PriorityMsgWriter.
+func SendPriority(w MsgWriter, msgcode uint64, data interface{}) error {
+	pw, ok := w.(PriorityMsgWriter)
+	if !ok {
+		return Send(w, msgcode, data)
+	}
+	size, r, err := rlp.EncodeToReader(data)
+	if err != nil {
+		return err
+	}
+	return pw.WriteMsgPriority(Msg{Code: msgcode, Size: uint32(size), Payload: r})
+}
+
 // SendItems writes an RLP with the given code and data elements.
 // For a call such as:
 //
@@ -302,6 +324,24 @@ func (ev *msgEventer) WriteMsg(msg Msg) error {
 	if err != nil {
 		return err
 	}
+	ev.sent(msg)
+	return nil
+}
+
+// WriteMsgPriority is the same as WriteMsg for the priority writes
+func (ev *msgEventer) WriteMsgPriority(msg Msg) error {
+	pw, ok := ev.MsgReadWriter.(PriorityMsgWriter)
+	if !ok {
+		return ev.WriteMsg(msg)
+	}
+	if err := pw.WriteMsgPriority(msg); err != nil {
+		return err
+	}
+	ev.sent(msg)
+	return nil
+}
+
+func (ev *msgEventer) sent(msg Msg) {
 	ev.feed.Send(&PeerEvent{
 		Type:          PeerEventTypeMsgSend,
 		Peer:          ev.peerID,
@@ -311,7 +351,6 @@ func (ev *msgEventer) WriteMsg(msg Msg) error {
 		LocalAddress:  ev.localAddress,
 		RemoteAddress: ev.remoteAddress,
 	})
-	return nil
 }
 
 // Close closes the underlying MsgReadWriter if it implements the io.Closer
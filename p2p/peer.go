@@ -54,6 +54,10 @@ const (
 	discMsg      = 0x01
 	pingMsg      = 0x02
 	pongMsg      = 0x03
+
+	// Quorum: node certificates, exchanged after the protocol handshake
+	certificatesMsg        = 0x04
+	certificatesMaxMsgSize = 64 * 1024
 )
 
 // protoHandshake is the RLP structure of the protocol handshake.
@@ -120,6 +124,7 @@ type Peer struct {
 	// Quorum
 	EthPeerRegistered   chan struct{}
 	EthPeerDisconnected chan struct{}
+	counters            peerCounters // traffic and message statistics, see Stats
 }
 
 // NewPeer returns a peer for testing purposes.
@@ -223,23 +228,39 @@ func (p *Peer) Log() log.Logger {
 
 func (p *Peer) run() (remoteRequested bool, err error) {
 	var (
-		writeStart = make(chan struct{}, 1)
-		writeErr   = make(chan error, 1)
-		readErr    = make(chan error, 1)
-		reason     DiscReason // sent to the peer
+		writeStart    = make(chan struct{})
+		writePriority = make(chan struct{}) // Quorum: the priority writes start before the others
+		writeErr      = make(chan error, 1)
+		readErr       = make(chan error, 1)
+		reason        DiscReason // sent to the peer
+		canWrite      = true
 	)
 	p.wg.Add(2)
 	go p.readLoop(readErr)
 	go p.pingLoop()
 
 	// Start all protocol handlers.
-	writeStart <- struct{}{}
-	p.startProtocols(writeStart, writeErr)
+	p.startProtocols(writeStart, writePriority, writeErr)
 
 	// Wait for an error or disconnect.
 loop:
 	for {
+		var start, priority chan struct{}
+		if canWrite {
+			// Quorum: a pending priority write is allowed to start first
+			select {
+			case writePriority <- struct{}{}:
+				canWrite = false
+				continue
+			default:
+			}
+			start, priority = writeStart, writePriority
+		}
 		select {
+		case start <- struct{}{}:
+			canWrite = false
+		case priority <- struct{}{}:
+			canWrite = false
 		case err = <-writeErr:
 			// A write finished. Allow the next write to start if
 			// there was no error.
@@ -247,7 +268,7 @@ loop:
 				reason = DiscNetworkError
 				break loop
 			}
-			writeStart <- struct{}{}
+			canWrite = true
 		case err = <-readErr:
 			if r, ok := err.(DiscReason); ok {
 				remoteRequested = true
@@ -323,8 +344,10 @@ func (p *Peer) handle(msg Msg) error {
 		// it's a subprotocol message
 		proto, err := p.getProto(msg.Code)
 		if err != nil {
+			p.counters.recordError() // Quorum
 			return fmt.Errorf("msg code out of range: %v", msg.Code)
 		}
+		p.counters.recordIngress(proto.cap(), msg.Code-proto.offset, msg.Size) // Quorum
 		if metrics.Enabled {
 			m := fmt.Sprintf("%s/%s/%d/%#02x", ingressMeterName, proto.Name, proto.Version, msg.Code-proto.offset)
 			metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize))
@@ -377,13 +400,15 @@ outer:
 	return result
 }
 
-func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error) {
+func (p *Peer) startProtocols(writeStart, writePriority <-chan struct{}, writeErr chan<- error) {
 	p.wg.Add(len(p.running))
 	for _, proto := range p.running {
 		proto := proto
 		proto.closed = p.closed
 		proto.wstart = writeStart
+		proto.wpriority = writePriority
 		proto.werr = writeErr
+		proto.counters = &p.counters // Quorum
 		var rw MsgReadWriter = proto
 		if p.events != nil {
 			rw = newMsgEventer(rw, p.events, p.ID(), proto.Name, p.Info().Network.RemoteAddress, p.Info().Network.LocalAddress)
@@ -422,9 +447,23 @@ type protoRW struct {
 	werr   chan<- error    // for write results
 	offset uint64
 	w      MsgWriter
+
+	wpriority <-chan struct{} // Quorum: receives when priority write may start
+	counters  *peerCounters   // Quorum: statistics of the peer, nil if not counted
 }
 
 func (rw *protoRW) WriteMsg(msg Msg) (err error) {
+	return rw.write(msg, rw.wstart)
+}
+
+// Quorum
+// WriteMsgPriority implements PriorityMsgWriter, the message being written before
+// the pending writes of the peer
+func (rw *protoRW) WriteMsgPriority(msg Msg) (err error) {
+	return rw.write(msg, rw.wpriority)
+}
+
+func (rw *protoRW) write(msg Msg, wstart <-chan struct{}) (err error) {
 	if msg.Code >= rw.Length {
 		return newPeerError(errInvalidMsgCode, "not handled")
 	}
@@ -434,8 +473,16 @@ func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	msg.Code += rw.offset
 
 	select {
-	case <-rw.wstart:
+	case <-wstart:
 		err = rw.w.WriteMsg(msg)
+		// Quorum
+		if rw.counters != nil {
+			if err != nil {
+				rw.counters.recordError()
+			} else {
+				rw.counters.recordEgress(rw.cap(), msg.meterCode, msg.Size)
+			}
+		}
 		// Report write status back to Peer.run. It will initiate
 		// shutdown if the error is non-nil and unblock the next write
 		// otherwise. The calling protocol code should exit for errors
@@ -474,6 +521,10 @@ type PeerInfo struct {
 		Static        bool   `json:"static"`
 	} `json:"network"`
 	Protocols map[string]interface{} `json:"protocols"` // Sub-protocol specific metadata fields
+
+	// Quorum
+	// Permissioning and consensus metadata, see Server.SetPeerInfoProvider
+	Quorum map[string]interface{} `json:"quorum,omitempty"`
 }
 
 // Info gathers and returns a collection of metadata known about a peer.
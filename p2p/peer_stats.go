@@ -0,0 +1,131 @@
+package p2p
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+)
+
+// PeerStats are the traffic and message statistics of a connected peer, counted
+// since the connection was established. Only the sub-protocol messages are
+// counted, the sizes being the ones of the decoded message payloads.
+type PeerStats struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	RemoteAddress string        `json:"remoteAddress"`
+	Inbound       bool          `json:"inbound"`
+	Connected     float64       `json:"connected"` // Seconds since the connection was established
+	Ingress       *TrafficStats `json:"ingress"`
+	Egress        *TrafficStats `json:"egress"`
+	Errors        uint64        `json:"errors"`    // Failed writes and messages of unknown protocol codes
+	ErrorRate     float64       `json:"errorRate"` // Errors per message exchanged
+}
+
+// TrafficStats are the statistics of the messages exchanged in one direction.
+type TrafficStats struct {
+	Bytes          uint64            `json:"bytes"`
+	Messages       uint64            `json:"messages"`
+	BytesPerSecond float64           `json:"bytesPerSecond"` // Average since the connection was established
+	ByCode         map[string]uint64 `json:"byCode"`         // Number of messages by protocol code, e.g. eth/64/0x07
+}
+
+type trafficCounters struct {
+	bytes    uint64
+	messages uint64
+	byCode   map[string]uint64
+}
+
+func (c *trafficCounters) record(cap Cap, code uint64, size uint32) {
+	if c.byCode == nil {
+		c.byCode = make(map[string]uint64)
+	}
+	c.bytes += uint64(size)
+	c.messages++
+	c.byCode[fmt.Sprintf("%s/%d/%#02x", cap.Name, cap.Version, code)]++
+}
+
+func (c *trafficCounters) stats(seconds float64) *TrafficStats {
+	stats := &TrafficStats{
+		Bytes:    c.bytes,
+		Messages: c.messages,
+		ByCode:   make(map[string]uint64, len(c.byCode)),
+	}
+	if seconds > 0 {
+		stats.BytesPerSecond = float64(c.bytes) / seconds
+	}
+	for code, n := range c.byCode {
+		stats.ByCode[code] = n
+	}
+	return stats
+}
+
+// peerCounters counts the messages exchanged with a peer, it is safe for
+// concurrent use by the read loop and the protocol writers.
+type peerCounters struct {
+	mu      sync.Mutex
+	ingress trafficCounters
+	egress  trafficCounters
+	errors  uint64
+}
+
+func (c *peerCounters) recordIngress(cap Cap, code uint64, size uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ingress.record(cap, code, size)
+}
+
+func (c *peerCounters) recordEgress(cap Cap, code uint64, size uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.egress.record(cap, code, size)
+}
+
+func (c *peerCounters) recordError() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errors++
+}
+
+// Stats returns the traffic and message statistics of the peer.
+func (p *Peer) Stats() *PeerStats {
+	seconds := time.Duration(mclock.Now() - p.created).Seconds()
+
+	p.counters.mu.Lock()
+	defer p.counters.mu.Unlock()
+
+	stats := &PeerStats{
+		ID:            p.ID().String(),
+		Name:          p.Fullname(),
+		RemoteAddress: p.RemoteAddr().String(),
+		Inbound:       p.Inbound(),
+		Connected:     seconds,
+		Ingress:       p.counters.ingress.stats(seconds),
+		Egress:        p.counters.egress.stats(seconds),
+		Errors:        p.counters.errors,
+	}
+	if messages := stats.Ingress.Messages + stats.Egress.Messages + stats.Errors; messages > 0 {
+		stats.ErrorRate = float64(stats.Errors) / float64(messages)
+	}
+	return stats
+}
+
+// PeersStats returns the traffic and message statistics of the connected peers,
+// sorted by node identifier.
+func (srv *Server) PeersStats() []*PeerStats {
+	stats := make([]*PeerStats, 0, srv.PeerCount())
+	for _, peer := range srv.Peers() {
+		if peer != nil {
+			stats = append(stats, peer.Stats())
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
@@ -0,0 +1,55 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerStats(t *testing.T) {
+	done := make(chan struct{})
+	proto := Protocol{
+		Name:    "a",
+		Version: 1,
+		Length:  5,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			for i := 0; i < 2; i++ {
+				msg, err := rw.ReadMsg()
+				if err != nil {
+					return err
+				}
+				msg.Discard()
+			}
+			err := SendItems(rw, 3, "reply")
+			close(done)
+			if err != nil {
+				return err
+			}
+			<-peer.closed
+			return nil
+		},
+	}
+	closer, rw, peer, _ := testPeer([]Protocol{proto})
+	defer closer()
+
+	require.NoError(t, SendItems(rw, baseProtocolLength+2, uint(1)))
+	require.NoError(t, SendItems(rw, baseProtocolLength+2, uint(2)))
+	require.NoError(t, ExpectMsg(rw, baseProtocolLength+3, []string{"reply"}))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("protocol did not reply")
+	}
+
+	stats := peer.Stats()
+
+	assert.Equal(t, uint64(2), stats.Ingress.Messages)
+	assert.Equal(t, map[string]uint64{"a/1/0x02": 2}, stats.Ingress.ByCode)
+	assert.Equal(t, uint64(1), stats.Egress.Messages)
+	assert.Equal(t, map[string]uint64{"a/1/0x03": 1}, stats.Egress.ByCode)
+	assert.NotZero(t, stats.Egress.Bytes)
+	assert.Zero(t, stats.Errors)
+	assert.Zero(t, stats.ErrorRate)
+}
@@ -168,6 +168,53 @@ func TestPeerProtoEncodeMsg(t *testing.T) {
 	}
 }
 
+// Quorum
+func TestPeerProtoWriteMsgPriority(t *testing.T) {
+	release := make(chan struct{})
+	bulk := Protocol{
+		Name:   "bulk",
+		Length: 1,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			for i := 0; i < 5; i++ {
+				go Send(rw, 0, []uint{uint(i)})
+			}
+			_, err := rw.ReadMsg()
+			return err
+		},
+	}
+	consensus := Protocol{
+		Name:   "consensus",
+		Length: 1,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			<-release
+			if err := SendPriority(rw, 0, []uint{99}); err != nil {
+				t.Errorf("write error: %v", err)
+			}
+			_, err := rw.ReadMsg()
+			return err
+		},
+	}
+	closer, rw, _, _ := testPeer([]Protocol{bulk, consensus})
+	defer closer()
+
+	// the first bulk write is blocked on the pipe and the others are pending
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	time.Sleep(100 * time.Millisecond)
+
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if msg.Code != baseProtocolLength {
+		t.Errorf("first message code mismatch: have %d, want %d", msg.Code, baseProtocolLength)
+	}
+	msg.Discard()
+	if err := ExpectMsg(rw, baseProtocolLength+1, []uint{99}); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestPeerPing(t *testing.T) {
 	closer, rw, _, _ := testPeer(nil)
 	defer closer()
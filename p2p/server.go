@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discv5"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/p2p/identity"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/p2p/netutil"
 	"github.com/ethereum/go-ethereum/permission/core"
@@ -93,6 +94,8 @@ type Config struct {
 
 	// DiscoveryV5 specifies whether the new topic-discovery based V5 discovery
 	// protocol should be started or not.
+	// Quorum: with EnableNodePermission, the discv5 wire protocol is started instead,
+	// restricted to the permissioned nodes.
 	DiscoveryV5 bool `toml:",omitempty"`
 
 	// Name sets the node name of this server.
@@ -157,6 +160,12 @@ type Config struct {
 	EnableNodePermission bool `toml:",omitempty"`
 
 	DataDir string `toml:",omitempty"`
+
+	// Quorum
+	// NodeIdentity binds the node key to a certificate issued by its organization,
+	// presented to and validated by the peers during the handshake.
+	NodeIdentity identity.Config `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 
@@ -187,6 +196,8 @@ type Server struct {
 	localnode *enode.LocalNode
 	ntab      *discover.UDPv4
 	DiscV5    *discv5.Network
+	ntabV5    *discover.UDPv5    // Quorum: permissioned discv5
+	identity  *identity.Identity // Quorum: node certificates
 	discmix   *enode.FairMix
 	dialsched *dialScheduler
 
@@ -208,6 +219,12 @@ type Server struct {
 
 	// permissions - check if node is permissioned
 	isNodePermissionedFunc func(node *enode.Node, nodename string, currentNode string, datadir string, direction string) bool
+	// Quorum
+	// connectionHookFunc is consulted on every connection attempt after other permissioning checks
+	connectionHookFunc func(node *enode.Node, direction string) bool
+	// peerInfoProviders add permissioning and consensus metadata to PeerInfo
+	peerInfoProviders  map[string]func(node *enode.Node) interface{}
+	peerInfoProviderMu sync.RWMutex
 }
 
 type peerOpFunc func(map[enode.ID]*Peer)
@@ -481,6 +498,15 @@ func (srv *Server) Start() (err error) {
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 
+	// Quorum
+	if srv.NodeIdentity.Enabled() {
+		id, err := identity.New(srv.NodeIdentity, &srv.PrivateKey.PublicKey)
+		if err != nil {
+			return fmt.Errorf("node identity: %v", err)
+		}
+		srv.identity = id
+	}
+
 	if err := srv.setupLocalNode(); err != nil {
 		return err
 	}
@@ -595,6 +621,7 @@ func (srv *Server) setupDiscovery() error {
 			Bootnodes:   srv.BootstrapNodes,
 			Unhandled:   unhandled,
 			Log:         srv.log,
+			NodeFilter:  srv.discoveryNodeFilter(), // Quorum
 		}
 		ntab, err := discover.ListenUDP(conn, srv.localnode, cfg)
 		if err != nil {
@@ -605,6 +632,14 @@ func (srv *Server) setupDiscovery() error {
 	}
 
 	// Discovery V5
+	// Quorum: permissioned networks only discover and advertise the permissioned nodes
+	if srv.DiscoveryV5 && srv.EnableNodePermission {
+		var v5conn discover.UDPConn = conn
+		if sconn != nil {
+			v5conn = sconn
+		}
+		return srv.setupPermissionedDiscoveryV5(v5conn)
+	}
 	if srv.DiscoveryV5 {
 		var ntab *discv5.Network
 		var err error
@@ -624,6 +659,61 @@ func (srv *Server) setupDiscovery() error {
 	return nil
 }
 
+// Quorum
+// setupPermissionedDiscoveryV5 starts the discv5 wire protocol with the node permissioning
+// as node filter: the records of the nodes which are not permissioned are rejected, their
+// requests are ignored and they are not advertised.
+func (srv *Server) setupPermissionedDiscoveryV5(conn discover.UDPConn) error {
+	bootnodes := append([]*enode.Node{}, srv.BootstrapNodes...)
+	for _, n := range srv.BootstrapNodesV5 {
+		pub, err := n.ID.Pubkey()
+		if err != nil {
+			return fmt.Errorf("invalid v5 bootnode %v: %v", n, err)
+		}
+		bootnodes = append(bootnodes, enode.NewV4(pub, n.IP, int(n.TCP), int(n.UDP)))
+	}
+	cfg := discover.Config{
+		PrivateKey:  srv.PrivateKey,
+		NetRestrict: srv.NetRestrict,
+		Bootnodes:   bootnodes,
+		Log:         srv.log,
+		NodeFilter:  srv.discoveryNodeFilter(),
+	}
+	ntab, err := discover.ListenV5(conn, srv.localnode, cfg)
+	if err != nil {
+		return err
+	}
+	srv.ntabV5 = ntab
+	srv.discmix.AddSource(ntab.RandomNodes())
+	return nil
+}
+
+// Quorum
+// permissionedDialCandidates filters out the discovered nodes, e.g.: from DNS discovery,
+// which are not permissioned so that they are not dialed.
+func (srv *Server) permissionedDialCandidates(it enode.Iterator) enode.Iterator {
+	if !srv.EnableNodePermission {
+		return it
+	}
+	currentNode := srv.localnode.ID().String()
+	return enode.Filter(it, func(n *enode.Node) bool {
+		return srv.isNodePermissioned(n, currentNode, "OUTGOING")
+	})
+}
+
+// Quorum
+// discoveryNodeFilter returns the node filter of the discovery, nil unless the node
+// permissioning is enabled
+func (srv *Server) discoveryNodeFilter() func(*enode.Node) bool {
+	if !srv.EnableNodePermission {
+		return nil
+	}
+	currentNode := srv.localnode.ID().String()
+	return func(n *enode.Node) bool {
+		return srv.isNodePermissioned(n, currentNode, "DISCOVERY")
+	}
+}
+
 func (srv *Server) setupDialScheduler() {
 	config := dialConfig{
 		self:           srv.localnode.ID(),
@@ -640,7 +730,7 @@ func (srv *Server) setupDialScheduler() {
 	if config.dialer == nil {
 		config.dialer = tcpDialer{&net.Dialer{Timeout: defaultDialTimeout}}
 	}
-	srv.dialsched = newDialScheduler(config, srv.discmix, srv.SetupConn)
+	srv.dialsched = newDialScheduler(config, srv.permissionedDialCandidates(srv.discmix), srv.SetupConn)
 	for _, n := range srv.StaticNodes {
 		srv.dialsched.addStatic(n)
 	}
@@ -796,6 +886,9 @@ running:
 	if srv.DiscV5 != nil {
 		srv.DiscV5.Close()
 	}
+	if srv.ntabV5 != nil {
+		srv.ntabV5.Close()
+	}
 	// Disconnect all peers.
 	for _, p := range peers {
 		p.Disconnect(DiscQuitting)
@@ -1004,17 +1097,24 @@ func (srv *Server) setupConn(c *conn, flags connFlag, dialDest *enode.Node) erro
 			log.Trace("Node Permissioning", "Connection Direction", direction)
 		}
 
-		if srv.isNodePermissionedFunc == nil {
-			if !core.IsNodePermissioned(nodeId, currentNode, srv.DataDir, direction) {
-				return newPeerError(errPermissionDenied, "id=%s…%s %s id=%s…%s", currentNode[:4], currentNode[len(currentNode)-4:], direction, nodeId[:4], nodeId[len(nodeId)-4:])
-			}
-		} else if !srv.isNodePermissionedFunc(node, nodeId, currentNode, srv.DataDir, direction) {
+		if !srv.isNodePermissioned(node, currentNode, direction) {
 			return newPeerError(errPermissionDenied, "id=%s…%s %s id=%s…%s", currentNode[:4], currentNode[len(currentNode)-4:], direction, nodeId[:4], nodeId[len(nodeId)-4:])
 		}
 	} else {
 		clog.Trace("Node Permissioning is Disabled.")
 	}
 
+	if srv.connectionHookFunc != nil {
+		node, direction := c.node, "INCOMING"
+		if dialDest != nil {
+			node, direction = dialDest, "OUTGOING"
+		}
+		if !srv.connectionHookFunc(node, direction) {
+			nodeId := node.ID().String()
+			return newPeerError(errPermissionDenied, "id=%s…%s %s id=%s…%s", currentNode[:4], currentNode[len(currentNode)-4:], direction, nodeId[:4], nodeId[len(nodeId)-4:])
+		}
+	}
+
 	//END - QUORUM Permissioning
 
 	err = srv.checkpoint(c, srv.checkpointPostHandshake)
@@ -1033,6 +1133,19 @@ func (srv *Server) setupConn(c *conn, flags connFlag, dialDest *enode.Node) erro
 		clog.Trace("Wrong devp2p handshake identity", "phsid", hex.EncodeToString(phs.ID))
 		return DiscUnexpectedIdentity
 	}
+	// Quorum: the peer must present a valid certificate for its node key, the certificates
+	// being read for every connection so that the rotated certificates are presented
+	if srv.identity != nil {
+		certs, err := exchangeCertificates(c, srv.identity.Certificates())
+		if err != nil {
+			clog.Trace("Failed node certificates exchange", "err", err)
+			return err
+		}
+		if err := srv.identity.Verify(c.node.Pubkey(), certs); err != nil {
+			clog.Trace("Rejected node certificate", "err", err)
+			return newPeerError(errPermissionDenied, "node certificate: %v", err)
+		}
+	}
 	c.caps, c.name = phs.Caps, phs.Name
 	err = srv.checkpoint(c, srv.checkpointAddPeer)
 	if err != nil {
@@ -1158,7 +1271,7 @@ func (srv *Server) PeersInfo() []*PeerInfo {
 	infos := make([]*PeerInfo, 0, srv.PeerCount())
 	for _, peer := range srv.Peers() {
 		if peer != nil {
-			infos = append(infos, peer.Info())
+			infos = append(infos, srv.withQuorumPeerInfo(peer.Node(), peer.Info()))
 		}
 	}
 	// Sort the result array alphabetically by node identifier
@@ -1176,8 +1289,115 @@ func (srv *Server) SetCheckPeerInRaft(f func(*enode.Node) bool) {
 	srv.checkPeerInRaft = f
 }
 
+// SetPeerInfoProvider registers a function returning the metadata shown under the given
+// name in the quorum field of PeerInfo, e.g.: the org of the peer. The function may return
+// nil if it has nothing to report for the peer. A nil function removes the provider.
+func (srv *Server) SetPeerInfoProvider(name string, f func(node *enode.Node) interface{}) {
+	srv.peerInfoProviderMu.Lock()
+	defer srv.peerInfoProviderMu.Unlock()
+	if f == nil {
+		delete(srv.peerInfoProviders, name)
+		return
+	}
+	if srv.peerInfoProviders == nil {
+		srv.peerInfoProviders = make(map[string]func(node *enode.Node) interface{})
+	}
+	srv.peerInfoProviders[name] = f
+}
+
+// withQuorumPeerInfo populates the quorum field of the PeerInfo using the registered providers
+func (srv *Server) withQuorumPeerInfo(node *enode.Node, info *PeerInfo) *PeerInfo {
+	srv.peerInfoProviderMu.RLock()
+	defer srv.peerInfoProviderMu.RUnlock()
+	for name, f := range srv.peerInfoProviders {
+		if metadata := f(node); metadata != nil {
+			if info.Quorum == nil {
+				info.Quorum = make(map[string]interface{})
+			}
+			info.Quorum[name] = metadata
+		}
+	}
+	return info
+}
+
+// SetConnectionHook sets a function which can veto inbound/outbound connections
+// in addition to the node permissioning checks
+func (srv *Server) SetConnectionHook(f func(node *enode.Node, direction string) bool) {
+	srv.connectionHookFunc = f
+}
+
 func (srv *Server) SetIsNodePermissioned(f func(*enode.Node, string, string, string, string) bool) {
 	if srv.isNodePermissionedFunc == nil {
 		srv.isNodePermissionedFunc = f
 	}
 }
+
+// isNodePermissioned checks node against permissioned-nodes.json, or the permissioning
+// contracts when a permissioning function is set
+func (srv *Server) isNodePermissioned(node *enode.Node, currentNode string, direction string) bool {
+	nodeId := node.ID().String()
+	if srv.isNodePermissionedFunc == nil {
+		return core.IsNodePermissioned(nodeId, currentNode, srv.DataDir, direction)
+	}
+	return srv.isNodePermissionedFunc(node, nodeId, currentNode, srv.DataDir, direction)
+}
+
+// CurrentStaticNodes returns the static nodes, which change when they are reloaded
+func (srv *Server) CurrentStaticNodes() []*enode.Node {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	return srv.StaticNodes
+}
+
+// ReloadStaticNodes replaces the static nodes by nodes: the added ones are dialed and
+// the removed ones are disconnected, without restarting the server.
+// It returns the numbers of added and removed nodes.
+func (srv *Server) ReloadStaticNodes(nodes []*enode.Node) (added int, removed int) {
+	srv.lock.Lock()
+	previous := srv.StaticNodes
+	srv.StaticNodes = nodes
+	srv.lock.Unlock()
+
+	current := make(map[enode.ID]bool, len(nodes))
+	for _, n := range nodes {
+		current[n.ID()] = true
+	}
+	for _, n := range previous {
+		if !current[n.ID()] {
+			srv.RemovePeer(n)
+			removed++
+		}
+		delete(current, n.ID())
+	}
+	for _, n := range nodes {
+		if current[n.ID()] {
+			srv.AddPeer(n)
+			added++
+		}
+	}
+	return added, removed
+}
+
+// DropUnpermissionedPeers checks the connected peers against the node permissioning,
+// e.g.: after permissioned-nodes.json has been edited, and disconnects the ones which
+// are not permissioned anymore. It returns the dropped nodes.
+func (srv *Server) DropUnpermissionedPeers() []*enode.Node {
+	if !srv.EnableNodePermission {
+		return nil
+	}
+	currentNode := srv.NodeInfo().ID
+	var dropped []*enode.Node
+	for _, p := range srv.Peers() {
+		direction := "OUTGOING"
+		if p.Inbound() {
+			direction = "INCOMING"
+		}
+		if !srv.isNodePermissioned(p.Node(), currentNode, direction) {
+			p.Log().Info("Dropping peer no longer permissioned", "id", p.ID())
+			// the peer is removed from the static nodes not to be dialed again
+			srv.RemovePeer(p.Node())
+			dropped = append(dropped, p.Node())
+		}
+	}
+	return dropped
+}
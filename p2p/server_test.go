@@ -20,6 +20,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
@@ -238,6 +239,96 @@ func TestServerRemovePeerDisconnect(t *testing.T) {
 	}
 }
 
+// Quorum
+func TestServerReloadStaticNodes(t *testing.T) {
+	srv1 := &Server{Config: Config{
+		PrivateKey:  newkey(),
+		MaxPeers:    1,
+		NoDiscovery: true,
+		Logger:      testlog.Logger(t, log.LvlTrace).New("server", "1"),
+	}}
+	srv2 := &Server{Config: Config{
+		PrivateKey:  newkey(),
+		MaxPeers:    1,
+		NoDiscovery: true,
+		NoDial:      true,
+		ListenAddr:  "127.0.0.1:0",
+		Logger:      testlog.Logger(t, log.LvlTrace).New("server", "2"),
+	}}
+	srv1.Start()
+	defer srv1.Stop()
+	srv2.Start()
+	defer srv2.Stop()
+
+	ch := make(chan *PeerEvent, 1)
+	sub := srv1.SubscribeEvents(ch)
+	added, removed := srv1.ReloadStaticNodes([]*enode.Node{srv2.Self()})
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 0, removed)
+	select {
+	case ev := <-ch:
+		assert.Equal(t, PeerEventTypeAdd, ev.Type)
+		assert.Equal(t, srv2.Self().ID(), ev.Peer)
+	case <-time.After(2 * time.Second):
+		t.Fatal("static node not connected")
+	}
+	sub.Unsubscribe()
+
+	added, removed = srv1.ReloadStaticNodes(nil)
+	assert.Equal(t, 0, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, 0, srv1.PeerCount())
+	assert.Empty(t, srv1.StaticNodes)
+}
+
+// Quorum
+func TestServerDropUnpermissionedPeers(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	srv1 := &Server{Config: Config{
+		PrivateKey:           newkey(),
+		MaxPeers:             1,
+		NoDiscovery:          true,
+		DataDir:              tmpDir,
+		EnableNodePermission: true,
+		Logger:               testlog.Logger(t, log.LvlTrace).New("server", "1"),
+	}}
+	srv2 := &Server{Config: Config{
+		PrivateKey:  newkey(),
+		MaxPeers:    1,
+		NoDiscovery: true,
+		NoDial:      true,
+		ListenAddr:  "127.0.0.1:0",
+		Logger:      testlog.Logger(t, log.LvlTrace).New("server", "2"),
+	}}
+	srv1.Start()
+	defer srv1.Stop()
+	srv2.Start()
+	defer srv2.Stop()
+
+	permissioned := fmt.Sprintf("[%q]", srv2.Self().URLv4())
+	if err := ioutil.WriteFile(path.Join(tmpDir, params.PERMISSIONED_CONFIG), []byte(permissioned), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !syncAddPeer(srv1, srv2.Self()) {
+		t.Fatal("peer not connected")
+	}
+	assert.Empty(t, srv1.DropUnpermissionedPeers(), "peer still permissioned")
+	assert.Equal(t, 1, srv1.PeerCount())
+
+	if err := ioutil.WriteFile(path.Join(tmpDir, params.PERMISSIONED_CONFIG), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dropped := srv1.DropUnpermissionedPeers()
+	if assert.Len(t, dropped, 1) {
+		assert.Equal(t, srv2.Self().ID(), dropped[0].ID())
+	}
+	assert.Equal(t, 0, srv1.PeerCount())
+}
+
 // This test checks that connections are disconnected just after the encryption handshake
 // when the server is at capacity. Trusted connections should still be accepted.
 func TestServerAtCap(t *testing.T) {
@@ -526,6 +617,125 @@ func TestServerSetupConn_whenNotPermissioned(t *testing.T) {
 	assert.Equal(t, errPermissionDenied, perr.code)
 }
 
+// Quorum
+func TestServerPermissionedDialCandidates(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	var (
+		permitted = enode.NewV4(&newkey().PublicKey, net.IP{127, 0, 0, 1}, 30303, 30303)
+		denied    = enode.NewV4(&newkey().PublicKey, net.IP{127, 0, 0, 2}, 30303, 30303)
+	)
+	permissioned := fmt.Sprintf("[%q]", permitted.URLv4())
+	if err := ioutil.WriteFile(path.Join(tmpDir, params.PERMISSIONED_CONFIG), []byte(permissioned), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := &Server{
+		Config: Config{
+			PrivateKey:           newkey(),
+			NoDiscovery:          true,
+			NoDial:               true,
+			DataDir:              tmpDir,
+			EnableNodePermission: true,
+		},
+		log: log.New(),
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("couldn't start server: %v", err)
+	}
+	defer srv.Stop()
+
+	it := srv.permissionedDialCandidates(enode.IterNodes([]*enode.Node{denied, permitted}))
+	defer it.Close()
+	nodes := enode.ReadNodes(it, 2)
+	if assert.Len(t, nodes, 1) {
+		assert.Equal(t, permitted.ID(), nodes[0].ID())
+	}
+
+	srv.EnableNodePermission = false
+	assert.Len(t, enode.ReadNodes(srv.permissionedDialCandidates(enode.IterNodes([]*enode.Node{denied, permitted})), 2), 2)
+}
+
+// Quorum
+func TestExchangeCertificates(t *testing.T) {
+	ours, theirs := [][]byte{{1, 2, 3}, {4, 5}}, [][]byte{make([]byte, 2*baseProtocolMaxMsgSize)}
+	rw1, rw2 := MsgPipe()
+	defer rw1.Close()
+	result := make(chan [][]byte, 1)
+	go func() {
+		certs, err := exchangeCertificates(rw2, theirs)
+		assert.NoError(t, err)
+		result <- certs
+	}()
+
+	certs, err := exchangeCertificates(rw1, ours)
+
+	assert.NoError(t, err)
+	assert.Equal(t, theirs, certs, "a certificate chain larger than the handshake is exchanged")
+	assert.Equal(t, ours, <-result)
+
+	go Send(rw2, pingMsg, []uint{})
+	_, err = readCertificates(rw1)
+	assert.Error(t, err, "the node certificates are expected")
+}
+
+func TestServerSetupConn_whenConnectionHookDenies(t *testing.T) {
+	var (
+		clientkey, srvkey = newkey(), newkey()
+		clientpub         = &clientkey.PublicKey
+	)
+	clientNode := enode.NewV4(clientpub, nil, 0, 0)
+	var hookedNode *enode.Node
+	var hookedDirection string
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  srvkey,
+			NoDiscovery: true,
+		},
+		newTransport: func(fd net.Conn, key *ecdsa.PublicKey) transport { return newTestTransport(clientpub, fd, key) },
+		log:          log.New(),
+	}
+	srv.SetConnectionHook(func(node *enode.Node, direction string) bool {
+		hookedNode, hookedDirection = node, direction
+		return false
+	})
+	if err := srv.Start(); err != nil {
+		t.Fatalf("couldn't start server: %v", err)
+	}
+	defer srv.Stop()
+	p1, _ := net.Pipe()
+	err := srv.SetupConn(p1, dynDialedConn, clientNode)
+
+	assert.IsType(t, &peerError{}, err)
+	perr := err.(*peerError)
+	assert.Equal(t, errPermissionDenied, perr.code)
+	assert.Equal(t, clientNode.ID(), hookedNode.ID())
+	assert.Equal(t, "OUTGOING", hookedDirection)
+}
+
+func TestServerWithQuorumPeerInfo(t *testing.T) {
+	node := enode.NewV4(&newkey().PublicKey, nil, 0, 0)
+	srv := &Server{}
+	srv.SetPeerInfoProvider("org", func(n *enode.Node) interface{} {
+		if n.ID() != node.ID() {
+			return nil
+		}
+		return "org1"
+	})
+	srv.SetPeerInfoProvider("nothing", func(*enode.Node) interface{} { return nil })
+
+	info := srv.withQuorumPeerInfo(node, &PeerInfo{})
+	assert.Equal(t, map[string]interface{}{"org": "org1"}, info.Quorum)
+
+	other := enode.NewV4(&newkey().PublicKey, nil, 0, 0)
+	assert.Nil(t, srv.withQuorumPeerInfo(other, &PeerInfo{}).Quorum)
+
+	srv.SetPeerInfoProvider("org", nil)
+	assert.Nil(t, srv.withQuorumPeerInfo(node, &PeerInfo{}).Quorum)
+}
+
 type setupTransport struct {
 	pubkey            *ecdsa.PublicKey
 	encHandshakeErr   error
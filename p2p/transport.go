@@ -175,3 +175,45 @@ func readProtocolHandshake(rw MsgReader) (*protoHandshake, error) {
 	}
 	return &hs, nil
 }
+
+// Quorum
+// exchangeCertificates sends the node certificates to the peer and reads its own. They are
+// exchanged after the protocol handshake, as a certificate chain may exceed the size
+// limit of the handshake.
+func exchangeCertificates(rw MsgReadWriter, ours [][]byte) ([][]byte, error) {
+	werr := make(chan error, 1)
+	go func() { werr <- Send(rw, certificatesMsg, ours) }()
+	theirs, err := readCertificates(rw)
+	if err != nil {
+		<-werr // make sure the write terminates too
+		return nil, err
+	}
+	if err := <-werr; err != nil {
+		return nil, fmt.Errorf("write error: %v", err)
+	}
+	return theirs, nil
+}
+
+func readCertificates(rw MsgReader) ([][]byte, error) {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return nil, err
+	}
+	defer msg.Discard()
+	if msg.Size > certificatesMaxMsgSize {
+		return nil, fmt.Errorf("message too big")
+	}
+	if msg.Code == discMsg {
+		var reason [1]DiscReason
+		rlp.Decode(msg.Payload, &reason)
+		return nil, reason[0]
+	}
+	if msg.Code != certificatesMsg {
+		return nil, fmt.Errorf("expected node certificates, got %x", msg.Code)
+	}
+	var certs [][]byte
+	if err := msg.Decode(&certs); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -241,20 +242,20 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, false, 32, 35, big.NewInt(0), big.NewInt(0), nil, nil, false}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, false, 32, 35, big.NewInt(0), big.NewInt(0), nil, nil, nil, false}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false, 32, 32, big.NewInt(0), big.NewInt(0), nil, nil, false}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false, 32, 32, big.NewInt(0), big.NewInt(0), nil, nil, nil, false}
 
-	TestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, false, 32, 32, big.NewInt(0), big.NewInt(0), nil, nil, false}
+	TestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, false, 32, 32, big.NewInt(0), big.NewInt(0), nil, nil, nil, false}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 
-	QuorumTestChainConfig    = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, true, 64, 32, big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), false}
-	QuorumMPSTestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, true, 64, 32, big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), true}
+	QuorumTestChainConfig    = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, true, 64, 32, big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), nil, false}
+	QuorumMPSTestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil, nil, true, 64, 32, big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), nil, true}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -304,6 +305,22 @@ type MaxCodeConfigStruct struct {
 	Size  uint64   `json:"size,omitempty"`
 }
 
+// Quorum
+//
+// GasScheduleConfig overrides the gas costs of opcodes and precompiled contracts from
+// a block, until the block of the next gas schedule. The costs not overridden are the
+// ones of the fork in force.
+type GasScheduleConfig struct {
+	Block *big.Int `json:"block"`
+	// Opcodes are the costs of the opcodes by name (e.g. "SSTORE"). The cost replaces
+	// both the constant and the dynamic gas of the opcode, except the memory expansion,
+	// and the opcode earns no gas refund.
+	Opcodes map[string]uint64 `json:"opcodes,omitempty"`
+	// Precompiles are the costs of the precompiled contracts by address, regardless of
+	// their input
+	Precompiles map[common.Address]uint64 `json:"precompiles,omitempty"`
+}
+
 // ChainConfig is the core config which determines the blockchain settings.
 //
 // ChainConfig is stored in the database on a per block basis. This means
@@ -350,6 +367,15 @@ type ChainConfig struct {
 	MaxCodeSizeConfig []MaxCodeConfigStruct `json:"maxCodeSizeConfig,omitempty"`
 	// Quorum
 	PrivacyEnhancementsBlock *big.Int `json:"privacyEnhancementsBlock,omitempty"`
+	// to tune the gas costs, in ascending order of blocks
+	GasScheduleConfig []GasScheduleConfig `json:"gasScheduleConfig,omitempty"`
+	// Quorum
+	// TransactionValidityBlock enables the transactions with a validity window (valid-until
+	// block number or time)
+	TransactionValidityBlock *big.Int `json:"transactionValidityBlock,omitempty"`
+	// PrivateContractCollisionBlock enables the address collision checks of the private
+	// contract creations against the public state
+	PrivateContractCollisionBlock *big.Int `json:"privateContractCollisionBlock,omitempty"`
 
 	IsMPS bool `json:"isMPS"` // multiple private states flag
 }
@@ -379,6 +405,8 @@ type IstanbulConfig struct {
 	Epoch          uint64   `json:"epoch"`                    // Epoch length to reset votes and checkpoint
 	ProposerPolicy uint64   `json:"policy"`                   // The policy for proposer selection
 	Ceil2Nby3Block *big.Int `json:"ceil2Nby3Block,omitempty"` // Number of confirmations required to move from one state to next [2F + 1 to Ceil(2N/3)]
+	// Quorum: block from which the validators may hand over their seat to their new key
+	KeyRotationBlock *big.Int `json:"keyRotationBlock,omitempty"`
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -618,6 +646,82 @@ func (c *ChainConfig) IsPrivacyEnhancementsEnabled(num *big.Int) bool {
 	return isForked(c.PrivacyEnhancementsBlock, num)
 }
 
+// Quorum
+//
+// IsTransactionValidityEnabled returns whether num represents a block number after the
+// transaction validity window fork
+func (c *ChainConfig) IsTransactionValidityEnabled(num *big.Int) bool {
+	return isForked(c.TransactionValidityBlock, num)
+}
+
+// Quorum
+//
+// IsPrivateContractCollisionEnabled returns whether num represents a block number after
+// the private contract address collision fork
+func (c *ChainConfig) IsPrivateContractCollisionEnabled(num *big.Int) bool {
+	return isForked(c.PrivateContractCollisionBlock, num)
+}
+
+// Quorum
+//
+// IsNoPoW returns whether the chain runs without proof-of-work, the Quorum chains whose
+// consensus is not ethash: no ethash cache nor DAG is ever generated for them, unless
+// an ethash mode is explicitly configured, e.g. with --fakepow or Eth.Ethash.PowMode
+func (c *ChainConfig) IsNoPoW() bool {
+	return c.IsQuorum && c.Ethash == nil
+}
+
+// GetGasSchedule returns the gas cost overrides in force at the given block number,
+// nil if the costs are the ones of the forks
+func (c *ChainConfig) GetGasSchedule(num *big.Int) *GasScheduleConfig {
+	var schedule *GasScheduleConfig
+	for i := range c.GasScheduleConfig {
+		if !isForked(c.GasScheduleConfig[i].Block, num) {
+			break
+		}
+		schedule = &c.GasScheduleConfig[i]
+	}
+	return schedule
+}
+
+// validates the gasScheduleConfig data passed in config
+func (c *ChainConfig) CheckGasScheduleConfigData() error {
+	var prevBlock *big.Int
+	for _, schedule := range c.GasScheduleConfig {
+		if schedule.Block == nil {
+			return errors.New("block number not given in gasScheduleConfig data")
+		}
+		if prevBlock != nil && schedule.Block.Cmp(prevBlock) <= 0 {
+			return errors.New("invalid gasScheduleConfig detail, block order has to be strictly ascending")
+		}
+		prevBlock = schedule.Block
+	}
+	return nil
+}
+
+// isGasScheduleConfigCompatible checks that the gas schedules in force up to head are
+// the same in both configs
+func isGasScheduleConfigCompatible(c1, c2 *ChainConfig, head *big.Int) *ConfigCompatError {
+	for i := 0; i < len(c1.GasScheduleConfig) || i < len(c2.GasScheduleConfig); i++ {
+		var s1, s2 *GasScheduleConfig
+		var b1, b2 *big.Int
+		if i < len(c1.GasScheduleConfig) {
+			s1, b1 = &c1.GasScheduleConfig[i], c1.GasScheduleConfig[i].Block
+		}
+		if i < len(c2.GasScheduleConfig) {
+			s2, b2 = &c2.GasScheduleConfig[i], c2.GasScheduleConfig[i].Block
+		}
+		if !isForked(b1, head) && !isForked(b2, head) {
+			return nil
+		}
+		if s1 == nil || s2 == nil || !configNumEqual(b1, b2) ||
+			!reflect.DeepEqual(s1.Opcodes, s2.Opcodes) || !reflect.DeepEqual(s1.Precompiles, s2.Precompiles) {
+			return newCompatError("gas schedule", b1, b2)
+		}
+	}
+	return nil
+}
+
 // /Quorum
 
 // CheckCompatible checks whether scheduled fork transitions have been imported
@@ -745,6 +849,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int, isQuor
 	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.Ceil2Nby3Block, newcfg.Istanbul.Ceil2Nby3Block, head) {
 		return newCompatError("Ceil 2N/3 fork block", c.Istanbul.Ceil2Nby3Block, newcfg.Istanbul.Ceil2Nby3Block)
 	}
+	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.KeyRotationBlock, newcfg.Istanbul.KeyRotationBlock, head) {
+		return newCompatError("Istanbul key rotation fork block", c.Istanbul.KeyRotationBlock, newcfg.Istanbul.KeyRotationBlock)
+	}
 	if isForkIncompatible(c.QIP714Block, newcfg.QIP714Block, head) {
 		return newCompatError("permissions fork block", c.QIP714Block, newcfg.QIP714Block)
 	}
@@ -754,6 +861,15 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int, isQuor
 	if isForkIncompatible(c.PrivacyEnhancementsBlock, newcfg.PrivacyEnhancementsBlock, head) {
 		return newCompatError("Privacy Enhancements fork block", c.PrivacyEnhancementsBlock, newcfg.PrivacyEnhancementsBlock)
 	}
+	if err := isGasScheduleConfigCompatible(c, newcfg, head); err != nil {
+		return err
+	}
+	if isForkIncompatible(c.TransactionValidityBlock, newcfg.TransactionValidityBlock, head) {
+		return newCompatError("transaction validity fork block", c.TransactionValidityBlock, newcfg.TransactionValidityBlock)
+	}
+	if isForkIncompatible(c.PrivateContractCollisionBlock, newcfg.PrivateContractCollisionBlock, head) {
+		return newCompatError("private contract collision fork block", c.PrivateContractCollisionBlock, newcfg.PrivateContractCollisionBlock)
+	}
 	return nil
 }
 
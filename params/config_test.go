@@ -87,6 +87,8 @@ func TestMaxCodeSizeAndTransactionSizeLimit(t *testing.T) {
 }
 
 func TestCheckCompatible(t *testing.T) {
+	storedGasSchedule := []GasScheduleConfig{{Block: big.NewInt(10), Opcodes: map[string]uint64{"SSTORE": 5000}}}
+	changedGasSchedule := []GasScheduleConfig{{Block: big.NewInt(10), Opcodes: map[string]uint64{"SSTORE": 2000}}}
 	type test struct {
 		stored, new *ChainConfig
 		head        uint64
@@ -295,6 +297,40 @@ func TestCheckCompatible(t *testing.T) {
 			head:    15,
 			wantErr: nil,
 		},
+		{
+			stored:  &ChainConfig{GasScheduleConfig: storedGasSchedule},
+			new:     &ChainConfig{GasScheduleConfig: changedGasSchedule},
+			head:    4,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{GasScheduleConfig: storedGasSchedule},
+			new:    &ChainConfig{GasScheduleConfig: changedGasSchedule},
+			head:   30,
+			wantErr: &ConfigCompatError{
+				What:         "gas schedule",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored:  &ChainConfig{GasScheduleConfig: storedGasSchedule},
+			new:     &ChainConfig{GasScheduleConfig: append(storedGasSchedule, GasScheduleConfig{Block: big.NewInt(20)})},
+			head:    15,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{GasScheduleConfig: storedGasSchedule},
+			new:    &ChainConfig{GasScheduleConfig: append(storedGasSchedule, GasScheduleConfig{Block: big.NewInt(20)})},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "gas schedule",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(20),
+				RewindTo:     19,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -304,3 +340,47 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestGetGasSchedule(t *testing.T) {
+	config := &ChainConfig{GasScheduleConfig: []GasScheduleConfig{
+		{Block: big.NewInt(10), Opcodes: map[string]uint64{"SSTORE": 5000}},
+		{Block: big.NewInt(20)},
+	}}
+	tests := []struct {
+		block int64
+		want  *GasScheduleConfig
+	}{
+		{9, nil},
+		{10, &config.GasScheduleConfig[0]},
+		{19, &config.GasScheduleConfig[0]},
+		{20, &config.GasScheduleConfig[1]},
+	}
+	for _, test := range tests {
+		if have := config.GetGasSchedule(big.NewInt(test.block)); have != test.want {
+			t.Errorf("block %d: gas schedule mismatch: have %v, want %v", test.block, have, test.want)
+		}
+	}
+	if err := config.CheckGasScheduleConfigData(); err != nil {
+		t.Errorf("valid gas schedule rejected: %v", err)
+	}
+	config.GasScheduleConfig[1].Block = big.NewInt(10)
+	if err := config.CheckGasScheduleConfigData(); err == nil {
+		t.Errorf("gas schedules at the same block accepted")
+	}
+}
+
+func TestIsNoPoW(t *testing.T) {
+	tests := []struct {
+		config *ChainConfig
+		want   bool
+	}{
+		{&ChainConfig{IsQuorum: true}, true},
+		{&ChainConfig{IsQuorum: true, Ethash: new(EthashConfig)}, false},
+		{&ChainConfig{}, false},
+	}
+	for i, test := range tests {
+		if have := test.config.IsNoPoW(); have != test.want {
+			t.Errorf("test %d: no proof-of-work mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package permission
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -12,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/permission/core"
 	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var isStringAlphaNumeric = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`).MatchString
@@ -533,6 +535,42 @@ func (q *QuorumControlsAPI) ConnectionAllowed(enodeId, ip string, port, raftPort
 	}
 }
 
+// CreateApiKey creates an API key of the org, granting the RPC methods and private
+// states to the internal tools of the org. The caller must be an org admin, with its
+// account unlocked on the node and granted by the token of the security plugin. The
+// secret of the key is returned once.
+func (q *QuorumControlsAPI) CreateApiKey(ctx context.Context, orgId string, args ApiKeyArgs, txa ethapi.SendTxArgs) (*NewApiKey, error) {
+	if err := q.valManageApiKeys(orgId, txa.From); err != nil {
+		return nil, err
+	}
+	key, err := q.permCtrl.apiKeys.create(orgId, args, txa.From, rpc.PreauthenticatedTokenFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	log.Info("API key created", "org", orgId, "id", key.Id, "by", txa.From)
+	return key, nil
+}
+
+// RevokeApiKey revokes an API key of the org, the caller must be an org admin
+func (q *QuorumControlsAPI) RevokeApiKey(orgId string, id string, txa ethapi.SendTxArgs) (string, error) {
+	if err := q.valManageApiKeys(orgId, txa.From); err != nil {
+		return "", err
+	}
+	if err := q.permCtrl.apiKeys.revoke(orgId, id); err != nil {
+		return "", err
+	}
+	log.Info("API key revoked", "org", orgId, "id", id, "by", txa.From)
+	return actionSuccess, nil
+}
+
+// ApiKeyList returns the API keys of the org, without their secrets
+func (q *QuorumControlsAPI) ApiKeyList(orgId string) ([]ApiKey, error) {
+	if q.permCtrl.apiKeys == nil {
+		return nil, ErrApiKeysDisabled
+	}
+	return q.permCtrl.apiKeys.list(orgId)
+}
+
 // check if the account is network admin
 func (q *QuorumControlsAPI) isNetworkAdmin(account common.Address) bool {
 	ac, _ := core.AcctInfoMap.GetAccount(account)
@@ -955,6 +993,17 @@ func (q *QuorumControlsAPI) valApproveAdminRole(args ptype.TxArgs) error {
 	return nil
 }
 
+func (q *QuorumControlsAPI) valManageApiKeys(orgId string, from common.Address) error {
+	if q.permCtrl.apiKeys == nil {
+		return ErrApiKeysDisabled
+	}
+	// check if caller is org admin
+	if er := q.isOrgAdmin(from, orgId); er != nil {
+		return er
+	}
+	return q.permCtrl.checkAccountUnlocked(from)
+}
+
 func (q *QuorumControlsAPI) valAddNewRole(args ptype.TxArgs) error {
 	if args.RoleId == "" {
 		return ptype.ErrInvalidInput
@@ -0,0 +1,341 @@
+package permission
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/ethereum/go-ethereum/permission/core"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+)
+
+const (
+	// apiKeyScheme prefixes the API keys in the Authorization header of the RPC calls
+	apiKeyScheme = "ApiKey "
+	// apiKeyTokenLifetime is how long a call authenticated by an API key is valid, the
+	// key is checked again on the next authentication
+	apiKeyTokenLifetime = time.Hour
+)
+
+var (
+	apiKeyPrefix = []byte("apikey-") // apiKeyPrefix + id -> apiKeyRecord
+
+	ErrApiKeysDisabled   = errors.New("API keys are not enabled, see --permissioned.apikeys")
+	ErrApiKeyNotFound    = errors.New("API key does not exist for the org")
+	ErrApiKeyNoAuthority = errors.New("API key must grant at least one authority")
+	// ErrApiKeyUnauthenticated is returned when the caller creating an API key isn't
+	// authenticated by the security plugin as the org admin
+	ErrApiKeyUnauthenticated = errors.New("API keys can only be created by an org admin authenticated by the security plugin")
+
+	// apiKeyGrantableServices are the only namespaces granted by the API keys, the
+	// other ones, e.g. admin, txpool or the namespaces of the plugins, are left to the
+	// node operators
+	apiKeyGrantableServices = map[string]bool{
+		"eth":  true,
+		"net":  true,
+		"web3": true,
+	}
+
+	errNotApiKey         = errors.New("not an API key")
+	errInvalidApiKey     = errors.New("invalid API key")
+	errApiKeyExpired     = errors.New("API key expired")
+	errApiKeyOrgInactive = errors.New("org of the API key is not active")
+)
+
+// ApiKeyArgs are the authorities and private states granted by a new API key
+type ApiKeyArgs struct {
+	// Authorities are the RPC methods granted as service_method, * for any method,
+	// e.g. eth_*. Only the eth, net and web3 namespaces can be granted.
+	Authorities []string `json:"authorities"`
+	// PSIs are the private states granted, with any of their accounts
+	PSIs []string `json:"psis"`
+	// Lifetime is the number of seconds the key is valid, 0 if the key doesn't expire
+	Lifetime uint64 `json:"lifetime"`
+}
+
+// ApiKey is an API key of an org, authenticating the RPC calls of its internal tools
+// with a fixed set of granted authorities, in place of the tokens of the security
+// plugin. The keys are local to the node.
+type ApiKey struct {
+	Id          string         `json:"id"`
+	OrgId       string         `json:"orgId"`
+	Authorities []string       `json:"authorities"`
+	PSIs        []string       `json:"psis,omitempty"`
+	CreatedBy   common.Address `json:"createdBy"`
+	CreatedAt   int64          `json:"createdAt"`
+	ExpiresAt   int64          `json:"expiresAt,omitempty"` // unix time, 0 if the key doesn't expire
+}
+
+// NewApiKey is an API key just created, with the secret sent by the RPC clients
+// which is not stored by the node
+type NewApiKey struct {
+	ApiKey
+	Key string `json:"key"` // value of the Authorization header: ApiKey <id>.<secret>
+}
+
+// apiKeyRecord is an API key as stored, with the hash of its secret
+type apiKeyRecord struct {
+	ApiKey
+	SecretHash common.Hash `json:"secretHash"`
+}
+
+// apiKeyStore keeps the API keys in the node database and authenticates the RPC
+// calls made with them
+type apiKeyStore struct {
+	mu sync.Mutex // serializes the updates
+	db ethdb.KeyValueStore
+}
+
+func newApiKeyStore(db ethdb.KeyValueStore) *apiKeyStore {
+	return &apiKeyStore{db: db}
+}
+
+func apiKeyKey(id string) []byte {
+	return append(append([]byte{}, apiKeyPrefix...), id...)
+}
+
+// parseAuthority returns the granted authority of service_method
+func parseAuthority(authority string) (*proto.GrantedAuthority, error) {
+	elem := strings.SplitN(authority, "_", 2)
+	if len(elem) != 2 || elem[0] == "" || elem[1] == "" {
+		return nil, fmt.Errorf("invalid authority %q, expected service_method", authority)
+	}
+	return &proto.GrantedAuthority{Service: elem[0], Method: elem[1]}, nil
+}
+
+// checkApiKeyGrants verifies that the API key only grants the grantable namespaces,
+// and no more than the caller: the caller's token must grant the org admin account,
+// the authorities and the private states of the key.
+func checkApiKeyGrants(args ApiKeyArgs, orgAdmin common.Address, caller *proto.PreAuthenticatedAuthenticationToken) error {
+	if caller == nil || !isAccountGranted(caller, orgAdmin) {
+		return ErrApiKeyUnauthenticated
+	}
+	for _, authority := range args.Authorities {
+		requested, err := parseAuthority(authority)
+		if err != nil {
+			return err
+		}
+		if !apiKeyGrantableServices[requested.Service] {
+			return fmt.Errorf("authority %q can't be granted by an API key", authority)
+		}
+		if !isGrantedAuthority(caller.GetAuthorities(), requested) {
+			return fmt.Errorf("authority %q is not granted to the caller", authority)
+		}
+	}
+	for _, psi := range args.PSIs {
+		if ok, err := multitenancy.IsPSIAuthorized(caller, types.PrivateStateIdentifier(psi)); err != nil || !ok {
+			return fmt.Errorf("private state %q is not granted to the caller", psi)
+		}
+	}
+	return nil
+}
+
+// isAccountGranted reports whether the token grants the node-managed account on one
+// of its private states
+func isAccountGranted(token *proto.PreAuthenticatedAuthenticationToken, account common.Address) bool {
+	for _, granted := range token.GetAuthorities() {
+		u, err := url.Parse(granted.GetRaw())
+		if err != nil || u.Scheme != multitenancy.SchemePSI {
+			continue
+		}
+		attr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(types.PrivateStateIdentifier(u.Host)).WithNodeEOA(account)
+		if ok, err := multitenancy.IsAuthorized(token, attr); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isGrantedAuthority reports whether the authorities cover the whole requested authority
+func isGrantedAuthority(authorities []*proto.GrantedAuthority, requested *proto.GrantedAuthority) bool {
+	for _, granted := range authorities {
+		if (granted.Service == "*" || granted.Service == requested.Service) && (granted.Method == "*" || granted.Method == requested.Method) {
+			return true
+		}
+	}
+	return false
+}
+
+// create stores a new API key of the org and returns it with its secret, the grants
+// being capped at the ones of the caller
+func (s *apiKeyStore) create(orgId string, args ApiKeyArgs, createdBy common.Address, caller *proto.PreAuthenticatedAuthenticationToken) (*NewApiKey, error) {
+	if len(args.Authorities) == 0 {
+		return nil, ErrApiKeyNoAuthority
+	}
+	if err := checkApiKeyGrants(args, createdBy, caller); err != nil {
+		return nil, err
+	}
+	for _, psi := range args.PSIs {
+		if psi == "" || !isStringAlphaNumeric(psi) {
+			return nil, fmt.Errorf("invalid private state identifier %q", psi)
+		}
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rec := &apiKeyRecord{
+		ApiKey: ApiKey{
+			Id:          hex.EncodeToString(id[:]),
+			OrgId:       orgId,
+			Authorities: args.Authorities,
+			PSIs:        args.PSIs,
+			CreatedBy:   createdBy,
+			CreatedAt:   now.Unix(),
+		},
+		SecretHash: sha256.Sum256(secret[:]),
+	}
+	if args.Lifetime > 0 {
+		rec.ExpiresAt = now.Add(time.Duration(args.Lifetime) * time.Second).Unix()
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.db.Put(apiKeyKey(rec.Id), data); err != nil {
+		return nil, err
+	}
+	return &NewApiKey{
+		ApiKey: rec.ApiKey,
+		Key:    apiKeyScheme + rec.Id + "." + hex.EncodeToString(secret[:]),
+	}, nil
+}
+
+// get returns an API key, nil if it doesn't exist
+func (s *apiKeyStore) get(id string) (*apiKeyRecord, error) {
+	if has, err := s.db.Has(apiKeyKey(id)); err != nil || !has {
+		return nil, err
+	}
+	data, err := s.db.Get(apiKeyKey(id))
+	if err != nil {
+		return nil, err
+	}
+	rec := new(apiKeyRecord)
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// revoke deletes an API key of the org
+func (s *apiKeyStore) revoke(orgId, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	if rec == nil || rec.OrgId != orgId {
+		return ErrApiKeyNotFound
+	}
+	return s.db.Delete(apiKeyKey(id))
+}
+
+// list returns the API keys of the org, without their secrets
+func (s *apiKeyStore) list(orgId string) ([]ApiKey, error) {
+	it := s.db.NewIterator(apiKeyPrefix, nil)
+	defer it.Release()
+
+	keys := make([]ApiKey, 0)
+	for it.Next() {
+		rec := new(apiKeyRecord)
+		if err := json.Unmarshal(it.Value(), rec); err != nil {
+			return nil, err
+		}
+		if rec.OrgId == orgId {
+			keys = append(keys, rec.ApiKey)
+		}
+	}
+	return keys, it.Error()
+}
+
+// Authenticate grants the authorities and private states of an API key, as long as
+// the key is not expired and its org is active
+func (s *apiKeyStore) Authenticate(_ context.Context, token string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	if !strings.HasPrefix(token, apiKeyScheme) {
+		return nil, errNotApiKey
+	}
+	elem := strings.SplitN(strings.TrimPrefix(token, apiKeyScheme), ".", 2)
+	if len(elem) != 2 {
+		return nil, errInvalidApiKey
+	}
+	secret, err := hex.DecodeString(elem[1])
+	if err != nil {
+		return nil, errInvalidApiKey
+	}
+	rec, err := s.get(elem[0])
+	if err != nil || rec == nil {
+		return nil, errInvalidApiKey
+	}
+	hash := sha256.Sum256(secret)
+	if subtle.ConstantTimeCompare(hash[:], rec.SecretHash[:]) != 1 {
+		return nil, errInvalidApiKey
+	}
+	now := time.Now()
+	if rec.ExpiresAt != 0 && now.Unix() >= rec.ExpiresAt {
+		return nil, errApiKeyExpired
+	}
+	// the permissions are loaded once the network is synced
+	if core.OrgInfoMap == nil || !core.IsOrgActive(rec.OrgId) {
+		return nil, errApiKeyOrgInactive
+	}
+	return rec.token(now)
+}
+
+// IsEnabled is always true, the store exists only when the API keys are enabled
+func (s *apiKeyStore) IsEnabled(context.Context) (bool, error) {
+	return true, nil
+}
+
+// token returns the authentication token granted by the API key at the time
+func (rec *apiKeyRecord) token(now time.Time) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	expiry := now.Add(apiKeyTokenLifetime)
+	if rec.ExpiresAt != 0 && time.Unix(rec.ExpiresAt, 0).Before(expiry) {
+		expiry = time.Unix(rec.ExpiresAt, 0)
+	}
+	expiredAt, err := ptypes.TimestampProto(expiry)
+	if err != nil {
+		return nil, err
+	}
+	authorities := make([]*proto.GrantedAuthority, 0, len(rec.Authorities)+len(rec.PSIs))
+	for _, authority := range rec.Authorities {
+		granted, err := parseAuthority(authority)
+		if err != nil {
+			return nil, err
+		}
+		authorities = append(authorities, granted)
+	}
+	// the private states grant no RPC method by themselves
+	for _, psi := range rec.PSIs {
+		authorities = append(authorities, &proto.GrantedAuthority{
+			Raw: fmt.Sprintf("%s://%s?%s=%s&%s=%s", multitenancy.SchemePSI, psi,
+				multitenancy.QuerySelfEOA, multitenancy.AnyEOAAddress, multitenancy.QueryNodeEOA, multitenancy.AnyEOAAddress),
+		})
+	}
+	return &proto.PreAuthenticatedAuthenticationToken{
+		RawToken:    []byte(apiKeyScheme + rec.Id),
+		ExpiredAt:   expiredAt,
+		Authorities: authorities,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/ethereum/go-ethereum/permission/core"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withApiKeyOrgs(t *testing.T, orgs map[string]core.OrgStatus) {
+	saved := core.OrgInfoMap
+	t.Cleanup(func() { core.OrgInfoMap = saved })
+	core.OrgInfoMap = core.NewOrgCache(10)
+	for orgId, status := range orgs {
+		core.OrgInfoMap.UpsertOrg(orgId, "", orgId, big.NewInt(1), status)
+	}
+}
+
+// orgAdminToken is the token of the security plugin authenticating the org admin
+// common.Address{1} on the private state tenantA
+var orgAdminToken = &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+	{Service: "eth", Method: "*"},
+	{Raw: "psi://tenantA?node.eoa=" + strings.ToLower(common.Address{1}.Hex())},
+}}
+
+func TestApiKeyStore_Authenticate(t *testing.T) {
+	withApiKeyOrgs(t, map[string]core.OrgStatus{arbitraryOrgToAdd: core.OrgApproved})
+	store := newApiKeyStore(rawdb.NewMemoryDatabase())
+
+	key, err := store.create(arbitraryOrgToAdd, ApiKeyArgs{Authorities: []string{"eth_*"}, PSIs: []string{"tenantA"}}, common.Address{1}, orgAdminToken)
+	require.NoError(t, err)
+
+	authToken, err := store.Authenticate(context.Background(), key.Key)
+	require.NoError(t, err)
+	assert.NoError(t, verifyApiKeyAccess(authToken.Authorities, "eth", "getBalance"))
+	assert.Error(t, verifyApiKeyAccess(authToken.Authorities, "admin", "addPeer"))
+	psi, err := multitenancy.ExtractPSI(authToken)
+	require.NoError(t, err)
+	assert.Equal(t, types.PrivateStateIdentifier("tenantA"), psi)
+
+	_, err = store.Authenticate(context.Background(), apiKeyScheme+key.Id+"."+strings.Repeat("00", 32))
+	assert.Equal(t, errInvalidApiKey, err)
+	_, err = store.Authenticate(context.Background(), "Bearer token")
+	assert.Equal(t, errNotApiKey, err)
+
+	assert.Equal(t, ErrApiKeyNotFound, store.revoke("ORG2", key.Id), "the key is of another org")
+	require.NoError(t, store.revoke(arbitraryOrgToAdd, key.Id))
+	_, err = store.Authenticate(context.Background(), key.Key)
+	assert.Equal(t, errInvalidApiKey, err)
+}
+
+func TestApiKeyStore_Authenticate_whenOrgSuspended(t *testing.T) {
+	withApiKeyOrgs(t, map[string]core.OrgStatus{arbitraryOrgToAdd: core.OrgSuspended})
+	store := newApiKeyStore(rawdb.NewMemoryDatabase())
+
+	key, err := store.create(arbitraryOrgToAdd, ApiKeyArgs{Authorities: []string{"eth_*"}}, common.Address{1}, orgAdminToken)
+	require.NoError(t, err)
+
+	_, err = store.Authenticate(context.Background(), key.Key)
+	assert.Equal(t, errApiKeyOrgInactive, err)
+}
+
+func TestApiKeyStore_list(t *testing.T) {
+	store := newApiKeyStore(rawdb.NewMemoryDatabase())
+
+	_, err := store.create(arbitraryOrgToAdd, ApiKeyArgs{}, common.Address{1}, orgAdminToken)
+	assert.Equal(t, ErrApiKeyNoAuthority, err)
+	_, err = store.create(arbitraryOrgToAdd, ApiKeyArgs{Authorities: []string{"eth"}}, common.Address{1}, orgAdminToken)
+	assert.Error(t, err, "the authority has no method")
+	key, err := store.create(arbitraryOrgToAdd, ApiKeyArgs{Authorities: []string{"eth_blockNumber"}, Lifetime: 60}, common.Address{1}, orgAdminToken)
+	require.NoError(t, err)
+	_, err = store.create(arbitrarySubOrg, ApiKeyArgs{Authorities: []string{"eth_*"}}, common.Address{1}, orgAdminToken)
+	require.NoError(t, err)
+
+	keys, err := store.list(arbitraryOrgToAdd)
+	require.NoError(t, err)
+	assert.Equal(t, []ApiKey{key.ApiKey}, keys)
+	assert.Equal(t, key.CreatedAt+60, keys[0].ExpiresAt)
+}
+
+func TestCheckApiKeyGrants(t *testing.T) {
+	caller := &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+		{Service: "eth", Method: "*"},
+		{Service: "quorumPermission", Method: "*"},
+		{Service: "txpool", Method: "*"},
+		{Raw: "psi://tenantA?self.eoa=0x0&node.eoa=0x0"},
+	}}
+	admin := common.Address{1}
+
+	assert.NoError(t, checkApiKeyGrants(ApiKeyArgs{Authorities: []string{"eth_getBalance"}, PSIs: []string{"tenantA"}}, admin, caller))
+	for _, args := range []ApiKeyArgs{
+		{Authorities: []string{"*_*"}},
+		{Authorities: []string{"admin_addPeer"}},
+		{Authorities: []string{"quorumPermission_addOrg"}},
+		{Authorities: []string{"txpool_content"}},
+		{Authorities: []string{"net_version"}},
+		{Authorities: []string{"eth_call"}, PSIs: []string{"tenantB"}},
+	} {
+		assert.Error(t, checkApiKeyGrants(args, admin, caller), "%v", args)
+	}
+	assert.Equal(t, ErrApiKeyUnauthenticated, checkApiKeyGrants(ApiKeyArgs{Authorities: []string{"eth_blockNumber"}}, admin, nil), "the caller is not authenticated")
+	assert.Equal(t, ErrApiKeyUnauthenticated, checkApiKeyGrants(ApiKeyArgs{Authorities: []string{"eth_blockNumber"}}, common.Address{2}, orgAdminToken), "the org admin is not the caller")
+}
+
+// verifyApiKeyAccess checks the access to a method as the RPC server does
+func verifyApiKeyAccess(authorities []*proto.GrantedAuthority, service, method string) error {
+	for _, a := range authorities {
+		if (a.Service == "*" || a.Service == service) && (a.Method == "*" || a.Method == method) {
+			return nil
+		}
+	}
+	return errors.New("access denied")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/permission/core"
 	ptype "github.com/ethereum/go-ethereum/permission/core/types"
 	v1 "github.com/ethereum/go-ethereum/permission/v1"
@@ -36,6 +37,7 @@ type PermissionCtrl struct {
 	errorChan          chan error      // channel to capture error when starting aysnc
 	networkInitialized bool
 	controlService     ptype.ControlService
+	apiKeys            *apiKeyStore // nil unless the API keys are enabled
 }
 
 var permissionService *PermissionCtrl
@@ -71,6 +73,15 @@ func NewQuorumPermissionCtrl(stack *node.Node, pconfig *ptype.PermissionConfig,
 	if err != nil {
 		return nil, err
 	}
+	// the API keys authenticate the RPC calls with the security plugin, if any
+	if stack.Config().EnablePermissionApiKeys {
+		db, err := stack.OpenDatabase("apikeys", 0, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		p.apiKeys = newApiKeyStore(db)
+		stack.RegisterAuthenticationManager(p.apiKeys)
+	}
 	stopChan, stopSubscription := ptype.SubscribeStopEvent()
 	inProcRPCServerSub := stack.EventMux().Subscribe(rpc.InProcServerReadyEvent{})
 	log.Debug("permission service: waiting for InProcRPC Server")
@@ -199,6 +210,24 @@ func (p *PermissionCtrl) ConnectionAllowed(_enodeId, _ip string, _port, _raftPor
 	return cs.ConnectionAllowed(_enodeId, _ip, _port, _raftPort)
 }
 
+// PeerPermissionInfo is the permissioning data about a peer shown in admin_peers
+type PeerPermissionInfo struct {
+	OrgId  string `json:"orgId,omitempty"`
+	Status string `json:"status"` // the node status or Unknown if the node is not in the node list
+}
+
+// peerInfo returns the org and status of the peer from the node cache
+func (p *PermissionCtrl) peerInfo(node *enode.Node) interface{} {
+	if core.NodeInfoMap == nil {
+		return nil
+	}
+	info := core.NodeInfoMap.GetNodeByEnodeID(node.ID())
+	if info == nil {
+		return &PeerPermissionInfo{Status: "Unknown"}
+	}
+	return &PeerPermissionInfo{OrgId: info.OrgId, Status: info.Status.String()}
+}
+
 func (p *PermissionCtrl) IsTransactionAllowed(_sender common.Address, _target common.Address, _value *big.Int, _gasPrice *big.Int, _gasLimit *big.Int, _payload []byte, transactionType core.TransactionType) error {
 	// If permissions model is not in use return nil
 	if core.PermissionModel == core.Default {
@@ -260,6 +289,19 @@ func (p *PermissionCtrl) validateAccount(from common.Address) (accounts.Wallet,
 	return w, nil
 }
 
+// checkAccountUnlocked ensures the caller controls an account of the node, by signing
+// with it: the API keys are managed without permission transaction
+func (p *PermissionCtrl) checkAccountUnlocked(from common.Address) error {
+	w, err := p.validateAccount(from)
+	if err != nil {
+		return ptype.ErrInvalidAccount
+	}
+	if _, err := w.SignText(accounts.Account{Address: from}, from.Bytes()); err != nil {
+		return err
+	}
+	return nil
+}
+
 // getTxParams extracts the transaction related parameters
 func (p *PermissionCtrl) getTxParams(txa ethapi.SendTxArgs) (*bind.TransactOpts, error) {
 	w, err := p.validateAccount(txa.From)
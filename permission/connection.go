@@ -1,14 +1,19 @@
 package permission
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/plugin/nodepermission"
 )
 
+const connectionHookTimeout = 5 * time.Second
+
 func isNodePermissionedV1(enodeId string, nodename string, currentNode string, direction string) bool {
 	permissionedList := core.NodeInfoMap.GetNodeList()
 
@@ -63,3 +68,46 @@ func IsNodePermissioned(node *enode.Node, nodename string, currentNode string, d
 	}
 	return false
 }
+
+// NewConnectionHook returns a p2p connection hook which delegates the decision to the
+// node permission plugin. Connection requests are enriched with the organization of
+// the remote node when contract-based permissioning is in place.
+// Connections are denied if the plugin fails to respond
+func NewConnectionHook(p nodepermission.PluginNodePermission) func(node *enode.Node, direction string) bool {
+	return func(node *enode.Node, direction string) bool {
+		req := &nodepermission.ConnectionRequest{
+			EnodeID:   node.EnodeID(),
+			EnodeURL:  node.String(),
+			IP:        node.IP().String(),
+			TCPPort:   uint16(node.TCP()),
+			RaftPort:  uint16(node.RaftPort()),
+			Direction: nodepermission.Inbound,
+			OrgID:     orgIdOf(node),
+		}
+		if direction == "OUTGOING" {
+			req.Direction = nodepermission.Outbound
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), connectionHookTimeout)
+		defer cancel()
+		allowed, reason, err := p.ConnectionAllowed(ctx, req)
+		if err != nil {
+			log.Error("Node permission plugin failed, connection not allowed", "connection", direction, "node", node.String(), "err", err)
+			return false
+		}
+		log.Debug("Node permission plugin", "connection", direction, "node", node.String(), "allowed", allowed, "reason", reason)
+		return allowed
+	}
+}
+
+// orgIdOf returns the organization the node belongs to or empty if not known
+func orgIdOf(node *enode.Node) string {
+	if core.PermissionModel == core.Default || !core.PermissionsEnabled() || core.NodeInfoMap == nil {
+		return ""
+	}
+	for _, n := range core.NodeInfoMap.GetNodeList() {
+		if strings.Contains(n.Url, node.EnodeID()) {
+			return n.OrgId
+		}
+	}
+	return ""
+}
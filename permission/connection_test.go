@@ -0,0 +1,47 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/plugin/nodepermission"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubNodePermission struct {
+	allowed bool
+	err     error
+	req     *nodepermission.ConnectionRequest
+}
+
+func (s *stubNodePermission) ConnectionAllowed(_ context.Context, req *nodepermission.ConnectionRequest) (bool, string, error) {
+	s.req = req
+	return s.allowed, "arbitrary reason", s.err
+}
+
+func TestNewConnectionHook(t *testing.T) {
+	node := enode.MustParse(arbitraryNode1)
+	stub := &stubNodePermission{allowed: true}
+	testObject := NewConnectionHook(stub)
+
+	assert.True(t, testObject(node, "OUTGOING"))
+	assert.Equal(t, node.EnodeID(), stub.req.EnodeID)
+	assert.Equal(t, "127.0.0.1", stub.req.IP)
+	assert.Equal(t, uint16(21000), stub.req.TCPPort)
+	assert.Equal(t, uint16(50401), stub.req.RaftPort)
+	assert.Equal(t, nodepermission.Outbound, stub.req.Direction)
+	assert.Empty(t, stub.req.OrgID)
+
+	stub.allowed = false
+	assert.False(t, testObject(node, "INCOMING"))
+	assert.Equal(t, nodepermission.Inbound, stub.req.Direction)
+}
+
+func TestNewConnectionHook_whenPluginFails(t *testing.T) {
+	stub := &stubNodePermission{allowed: true, err: errors.New("arbitrary error")}
+	testObject := NewConnectionHook(stub)
+
+	assert.False(t, testObject(enode.MustParse(arbitraryNode1), "INCOMING"))
+}
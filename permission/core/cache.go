@@ -6,12 +6,37 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	lru "github.com/hashicorp/golang-lru"
 )
 
+// kinds of PermissionEvent
+const (
+	OrgPermissionEvent     = "org"
+	NodePermissionEvent    = "node"
+	AccountPermissionEvent = "account"
+	RolePermissionEvent    = "role"
+)
+
+// PermissionEvent is broadcast when an organization, node, account or role
+// is added or updated in the cache
+type PermissionEvent struct {
+	Kind string
+	Info interface{} // one of *OrgInfo, *NodeInfo, *AccountInfo or *RoleInfo
+}
+
+var permissionFeed event.Feed
+
+// SubscribePermissionEvent registers a subscription of PermissionEvent
+func SubscribePermissionEvent(ch chan<- PermissionEvent) event.Subscription {
+	return permissionFeed.Subscribe(ch)
+}
+
 type TransactionType uint8
 
 const (
@@ -74,6 +99,21 @@ const (
 	NodeRecoveryInitiated
 )
 
+var nodeStatusNames = map[NodeStatus]string{
+	NodePendingApproval:   "PendingApproval",
+	NodeApproved:          "Approved",
+	NodeDeactivated:       "Deactivated",
+	NodeBlackListed:       "Blacklisted",
+	NodeRecoveryInitiated: "RecoveryInitiated",
+}
+
+func (s NodeStatus) String() string {
+	if name, ok := nodeStatusNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
 type AcctStatus uint8
 
 const (
@@ -122,6 +162,8 @@ var syncStarted = false
 var defaultAccess = FullAccess
 var qip714BlockReached = false
 var networkBootUpCompleted = false
+var cachesLoading int32     // 1 while the caches are populated in the background
+var cachesLoadingGen uint64 // incremented each time the caches start loading
 var networkAdminRole string
 var orgAdminRole string
 var PermissionModel = Default
@@ -142,12 +184,20 @@ type OrgCache struct {
 	mux               sync.Mutex
 	evicted           bool
 	populateCacheFunc func(orgId string) (*OrgInfo, error)
+	populateListFunc  func() ([]OrgInfo, error)
+	contractList      contractList // read with populateListFunc while the caches are loading
 }
 
 func (o *OrgCache) PopulateCacheFunc(cf func(string) (*OrgInfo, error)) {
 	o.populateCacheFunc = cf
 }
 
+// PopulateListFunc sets the function reading all the orgs from the contract, listed
+// instead of the cached orgs while the caches are loading
+func (o *OrgCache) PopulateListFunc(lf func() ([]OrgInfo, error)) {
+	o.populateListFunc = lf
+}
+
 func NewOrgCache(cacheSize int) *OrgCache {
 	orgCache := OrgCache{evicted: false}
 	onEvictedFunc := func(k interface{}, v interface{}) {
@@ -164,14 +214,23 @@ type RoleKey struct {
 
 type RoleCache struct {
 	c                 *lru.Cache
+	mux               sync.Mutex
 	evicted           bool
 	populateCacheFunc func(*RoleKey) (*RoleInfo, error)
+	populateListFunc  func() ([]RoleInfo, error)
+	contractList      contractList // read with populateListFunc while the caches are loading
 }
 
 func (r *RoleCache) PopulateCacheFunc(cf func(*RoleKey) (*RoleInfo, error)) {
 	r.populateCacheFunc = cf
 }
 
+// PopulateListFunc sets the function reading all the roles from the contract, listed
+// instead of the cached roles while the caches are loading
+func (r *RoleCache) PopulateListFunc(lf func() ([]RoleInfo, error)) {
+	r.populateListFunc = lf
+}
+
 func NewRoleCache(cacheSize int) *RoleCache {
 	roleCache := RoleCache{evicted: false}
 	onEvictedFunc := func(k interface{}, v interface{}) {
@@ -188,9 +247,12 @@ type NodeKey struct {
 
 type NodeCache struct {
 	c                       *lru.Cache
+	mux                     sync.Mutex
 	evicted                 bool
 	populateCacheFunc       func(string) (*NodeInfo, error)
 	populateAndValidateFunc func(string, string) bool
+	populateListFunc        func() ([]NodeInfo, error)
+	contractList            contractList // read with populateListFunc while the caches are loading
 }
 
 func (n *NodeCache) PopulateValidateFunc(cf func(string, string) bool) {
@@ -201,6 +263,12 @@ func (n *NodeCache) PopulateCacheFunc(cf func(string) (*NodeInfo, error)) {
 	n.populateCacheFunc = cf
 }
 
+// PopulateListFunc sets the function reading all the nodes from the contract, listed
+// instead of the cached nodes while the caches are loading
+func (n *NodeCache) PopulateListFunc(lf func() ([]NodeInfo, error)) {
+	n.populateListFunc = lf
+}
+
 func NewNodeCache(cacheSize int) *NodeCache {
 	nodeCache := NodeCache{evicted: false}
 	onEvictedFunc := func(k interface{}, v interface{}) {
@@ -217,14 +285,23 @@ type AccountKey struct {
 
 type AcctCache struct {
 	c                 *lru.Cache
+	mux               sync.Mutex
 	evicted           bool
 	populateCacheFunc func(account common.Address) (*AccountInfo, error)
+	populateListFunc  func() ([]AccountInfo, error)
+	contractList      contractList // read with populateListFunc while the caches are loading
 }
 
 func (a *AcctCache) PopulateCacheFunc(cf func(common.Address) (*AccountInfo, error)) {
 	a.populateCacheFunc = cf
 }
 
+// PopulateListFunc sets the function reading all the accounts from the contract, listed
+// instead of the cached accounts while the caches are loading
+func (a *AcctCache) PopulateListFunc(lf func() ([]AccountInfo, error)) {
+	a.populateListFunc = lf
+}
+
 func NewAcctCache(cacheSize int) *AcctCache {
 	acctCache := AcctCache{evicted: false}
 	onEvictedFunc := func(k interface{}, v interface{}) {
@@ -262,6 +339,62 @@ func SetNetworkBootUpCompleted() {
 	setDefaultAccess()
 }
 
+// return bool to indicate if the network boot has been completed
+func IsNetworkBootUpCompleted() bool {
+	return networkBootUpCompleted
+}
+
+// sets whether the caches are being populated from the contract. while they are,
+// the records missing from the caches are fetched from the contract
+func SetCachesLoading(loading bool) {
+	if loading {
+		atomic.AddUint64(&cachesLoadingGen, 1)
+		atomic.StoreInt32(&cachesLoading, 1)
+	} else {
+		atomic.StoreInt32(&cachesLoading, 0)
+	}
+}
+
+// return bool to indicate if the caches are being populated from the contract
+func CachesLoading() bool {
+	return atomic.LoadInt32(&cachesLoading) == 1
+}
+
+// contractList is a list of records read from the contract once while the caches are
+// loading, instead of on every listing
+type contractList struct {
+	mu   sync.Mutex
+	gen  uint64
+	list interface{} // nil until read during the current loading
+}
+
+// get returns the list read from the contract during the current loading of the caches,
+// read with read on first use
+func (l *contractList) get(read func() (interface{}, error)) (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	gen := atomic.LoadUint64(&cachesLoadingGen)
+	if l.list != nil && l.gen == gen {
+		return l.list, nil
+	}
+	list, err := read()
+	if err != nil {
+		return nil, err
+	}
+	l.list, l.gen = list, gen
+	return list, nil
+}
+
+// marks the caches as evicted when they could not be populated from the contract.
+// as after an eviction, the records missing from the caches are fetched from the
+// contract on first use
+func SetCachesEvicted() {
+	OrgInfoMap.evicted = true
+	RoleInfoMap.evicted = true
+	NodeInfoMap.evicted = true
+	AcctInfoMap.evicted = true
+}
+
 // return bool to indicate if permissions is enabled
 func PermissionsEnabled() bool {
 	if PermissionModel == V2 {
@@ -287,6 +420,25 @@ func GetDefaults() (string, string, AccessType) {
 	return networkAdminRole, orgAdminRole, defaultAccess
 }
 
+// Quorum
+// SaveState returns a function restoring the permission state of the process as it is
+// now: the caches, the defaults, the transaction check and the progress of the network.
+// It is meant for the in-process backends enabling the permissions, e.g. in tests.
+func SaveState() (restore func()) {
+	var (
+		orgs, nodes, roles, accts = OrgInfoMap, NodeInfoMap, RoleInfoMap, AcctInfoMap
+		nwAdminRole, oAdminRole   = networkAdminRole, orgAdminRole
+		access, model, allowed    = defaultAccess, PermissionModel, PermissionTransactionAllowedFunc
+		synced, qip714, bootUp    = syncStarted, qip714BlockReached, networkBootUpCompleted
+	)
+	return func() {
+		OrgInfoMap, NodeInfoMap, RoleInfoMap, AcctInfoMap = orgs, nodes, roles, accts
+		networkAdminRole, orgAdminRole = nwAdminRole, oAdminRole
+		defaultAccess, PermissionModel, PermissionTransactionAllowedFunc = access, model, allowed
+		syncStarted, qip714BlockReached, networkBootUpCompleted = synced, qip714, bootUp
+	}
+}
+
 func GetNodeUrl(enodeId string, ip string, port uint16, raftport uint16, isRaft bool) string {
 	if isRaft {
 		return fmt.Sprintf("enode://%s@%s:%d?discport=0&raftport=%d", enodeId, strings.Trim(ip, "\x00"), port, raftport)
@@ -297,11 +449,36 @@ func GetNodeUrl(enodeId string, ip string, port uint16, raftport uint16, isRaft
 func (o *OrgCache) UpsertOrg(orgId, parentOrg, ultimateParent string, level *big.Int, status OrgStatus) {
 	defer o.mux.Unlock()
 	o.mux.Lock()
-	var key OrgKey
+	norg := o.upsertOrg(orgId, parentOrg, ultimateParent, level, status)
+	permissionFeed.Send(PermissionEvent{Kind: OrgPermissionEvent, Info: norg})
+}
+
+// AddOrgIfAbsent caches the org read from the contract unless the org is cached
+// already, e.g. updated by an event in the meantime. It reports whether the org
+// was added.
+func (o *OrgCache) AddOrgIfAbsent(orgId, parentOrg, ultimateParent string, level *big.Int, status OrgStatus) bool {
+	defer o.mux.Unlock()
+	o.mux.Lock()
+	if o.c.Contains(orgKeyOf(orgId, parentOrg)) {
+		return false
+	}
+	norg := o.upsertOrg(orgId, parentOrg, ultimateParent, level, status)
+	permissionFeed.Send(PermissionEvent{Kind: OrgPermissionEvent, Info: norg})
+	return true
+}
+
+// returns the key of the org, prefixed with its parent org if any
+func orgKeyOf(orgId, parentOrg string) OrgKey {
 	if parentOrg == "" {
-		key = OrgKey{OrgId: orgId}
-	} else {
-		key = OrgKey{OrgId: parentOrg + "." + orgId}
+		return OrgKey{OrgId: orgId}
+	}
+	return OrgKey{OrgId: parentOrg + "." + orgId}
+}
+
+// upserts the org and adds it to the sub orgs of its parent. the caller holds the lock
+func (o *OrgCache) upsertOrg(orgId, parentOrg, ultimateParent string, level *big.Int, status OrgStatus) *OrgInfo {
+	key := orgKeyOf(orgId, parentOrg)
+	if parentOrg != "" {
 		pkey := OrgKey{OrgId: parentOrg}
 		if ent, ok := o.c.Get(pkey); ok {
 			porg := ent.(*OrgInfo)
@@ -314,17 +491,29 @@ func (o *OrgCache) UpsertOrg(orgId, parentOrg, ultimateParent string, level *big
 
 	norg := &OrgInfo{orgId, key.OrgId, parentOrg, ultimateParent, level, nil, status}
 	o.c.Add(key, norg)
+	return norg
 }
 
 func (o *OrgCache) UpsertOrgWithSubOrgList(orgRec *OrgInfo) {
-	var key OrgKey
-	if orgRec.ParentOrgId == "" {
-		key = OrgKey{OrgId: orgRec.OrgId}
-	} else {
-		key = OrgKey{OrgId: orgRec.ParentOrgId + "." + orgRec.OrgId}
+	defer o.mux.Unlock()
+	o.mux.Lock()
+	key := orgKeyOf(orgRec.OrgId, orgRec.ParentOrgId)
+	orgRec.FullOrgId = key.OrgId
+	o.c.Add(key, orgRec)
+}
+
+// caches the org fetched from the contract unless it was cached in the meantime,
+// and returns the cached org
+func (o *OrgCache) addOrgWithSubOrgList(orgRec *OrgInfo) *OrgInfo {
+	defer o.mux.Unlock()
+	o.mux.Lock()
+	key := orgKeyOf(orgRec.OrgId, orgRec.ParentOrgId)
+	if ent, ok := o.c.Get(key); ok {
+		return ent.(*OrgInfo)
 	}
 	orgRec.FullOrgId = key.OrgId
 	o.c.Add(key, orgRec)
+	return orgRec
 }
 
 func containsKey(s []string, e string) bool {
@@ -343,33 +532,89 @@ func (o *OrgCache) GetOrg(orgId string) (*OrgInfo, error) {
 	}
 	// check if the org cache is evicted. if yes we need
 	// fetch the record from the contract
-	if o.evicted {
+	if o.evicted || CachesLoading() {
 		// call cache population function to populate from contract
 		orgRec, err := o.populateCacheFunc(orgId)
 		if err != nil {
 			return nil, err
 		}
-		// insert the received record into cache
-		o.UpsertOrgWithSubOrgList(orgRec)
-		//return the record
-		return orgRec, nil
+		// insert the received record into cache, unless an event updated it meanwhile
+		return o.addOrgWithSubOrgList(orgRec), nil
 	}
 	return nil, errors.New("Org does not exist")
 }
 
+// HasOrg reports whether the org is cached
+func (o *OrgCache) HasOrg(orgId string) bool {
+	return o.c.Contains(OrgKey{OrgId: orgId})
+}
+
 func (o *OrgCache) GetOrgList() []OrgInfo {
-	olist := make([]OrgInfo, len(o.c.Keys()))
-	for i, k := range o.c.Keys() {
-		v, _ := o.c.Get(k)
-		vp := v.(*OrgInfo)
-		olist[i] = *vp
+	// the cache is partial while loading, so the orgs are read once from the contract
+	// and listed with the cached ones, which are up to date with the events
+	var contractOrgs []OrgInfo
+	if CachesLoading() && o.populateListFunc != nil {
+		list, err := o.contractList.get(func() (interface{}, error) { return o.populateListFunc() })
+		if err == nil {
+			contractOrgs = list.([]OrgInfo)
+		} else {
+			log.Warn("Failed to read the orgs from contract, listing the cached orgs", "err", err)
+		}
+	}
+	seen := make(map[OrgKey]bool)
+	olist := make([]OrgInfo, 0, len(contractOrgs)+o.c.Len())
+	for _, org := range contractOrgs {
+		key := OrgKey{OrgId: org.FullOrgId}
+		if v, ok := o.c.Get(key); ok {
+			org = *v.(*OrgInfo)
+		}
+		seen[key] = true
+		olist = append(olist, org)
+	}
+	for _, k := range o.c.Keys() {
+		if !seen[k.(OrgKey)] {
+			v, _ := o.c.Get(k)
+			olist = append(olist, *v.(*OrgInfo))
+		}
 	}
 	return olist
 }
 
 func (n *NodeCache) UpsertNode(orgId string, url string, status NodeStatus) {
+	permissionFeed.Send(PermissionEvent{Kind: NodePermissionEvent, Info: n.upsertNode(orgId, url, status)})
+}
+
+func (n *NodeCache) upsertNode(orgId string, url string, status NodeStatus) *NodeInfo {
+	defer n.mux.Unlock()
+	n.mux.Lock()
+	key := NodeKey{OrgId: orgId, Url: url}
+	info := &NodeInfo{orgId, url, status}
+	n.c.Add(key, info)
+	return info
+}
+
+// AddNodeIfAbsent caches the node read from the contract unless the node is cached
+// already, e.g. updated by an event in the meantime. It reports whether the node
+// was added.
+func (n *NodeCache) AddNodeIfAbsent(orgId string, url string, status NodeStatus) bool {
+	info, added := n.addNode(orgId, url, status)
+	if added {
+		permissionFeed.Send(PermissionEvent{Kind: NodePermissionEvent, Info: info})
+	}
+	return added
+}
+
+// caches the node unless it is cached already, and returns the cached node
+func (n *NodeCache) addNode(orgId string, url string, status NodeStatus) (*NodeInfo, bool) {
+	defer n.mux.Unlock()
+	n.mux.Lock()
 	key := NodeKey{OrgId: orgId, Url: url}
-	n.c.Add(key, &NodeInfo{orgId, url, status})
+	if ent, ok := n.c.Get(key); ok {
+		return ent.(*NodeInfo), false
+	}
+	info := &NodeInfo{orgId, url, status}
+	n.c.Add(key, info)
+	return info, true
 }
 
 func (n *NodeCache) GetNodeByUrl(url string) (*NodeInfo, error) {
@@ -382,7 +627,7 @@ func (n *NodeCache) GetNodeByUrl(url string) (*NodeInfo, error) {
 	}
 	// check if the node cache is evicted. if yes we need
 	// fetch the record from the contract
-	if n.evicted {
+	if n.evicted || CachesLoading() {
 
 		// call cache population function to populate from contract
 		nodeRec, err := n.populateCacheFunc(url)
@@ -390,27 +635,102 @@ func (n *NodeCache) GetNodeByUrl(url string) (*NodeInfo, error) {
 			return nil, err
 		}
 
-		// insert the received record into cache
-		n.UpsertNode(nodeRec.OrgId, nodeRec.Url, nodeRec.Status)
+		// insert the received record into cache, unless an event updated it meanwhile
+		nodeRec, _ = n.addNode(nodeRec.OrgId, nodeRec.Url, nodeRec.Status)
 		//return the record
-		return nodeRec, err
+		return nodeRec, nil
 	}
 	return nil, errors.New("Node does not exist")
 }
 
+// GetNodeByEnodeID returns the cached node with the given enode ID, nil if not found
+func (n *NodeCache) GetNodeByEnodeID(id enode.ID) *NodeInfo {
+	for _, k := range n.c.Keys() {
+		recEnodeId, err := enode.ParseV4(k.(NodeKey).Url)
+		if err != nil || recEnodeId.ID() != id {
+			continue
+		}
+		if v, ok := n.c.Get(k); ok {
+			return v.(*NodeInfo)
+		}
+	}
+	// the node may not be cached yet while the caches are loading
+	if CachesLoading() {
+		for _, info := range n.GetNodeList() {
+			if recEnodeId, err := enode.ParseV4(info.Url); err == nil && recEnodeId.ID() == id {
+				return &info
+			}
+		}
+	}
+	return nil
+}
+
 func (n *NodeCache) GetNodeList() []NodeInfo {
-	olist := make([]NodeInfo, len(n.c.Keys()))
-	for i, k := range n.c.Keys() {
-		v, _ := n.c.Get(k)
-		vp := v.(*NodeInfo)
-		olist[i] = *vp
+	// the cache is partial while loading, so the nodes are read once from the contract
+	// and listed with the cached ones, which are up to date with the events
+	var contractNodes []NodeInfo
+	if CachesLoading() && n.populateListFunc != nil {
+		list, err := n.contractList.get(func() (interface{}, error) { return n.populateListFunc() })
+		if err == nil {
+			contractNodes = list.([]NodeInfo)
+		} else {
+			log.Warn("Failed to read the nodes from contract, listing the cached nodes", "err", err)
+		}
+	}
+	seen := make(map[NodeKey]bool)
+	olist := make([]NodeInfo, 0, len(contractNodes)+n.c.Len())
+	for _, node := range contractNodes {
+		key := NodeKey{OrgId: node.OrgId, Url: node.Url}
+		if v, ok := n.c.Get(key); ok {
+			node = *v.(*NodeInfo)
+		}
+		seen[key] = true
+		olist = append(olist, node)
+	}
+	for _, k := range n.c.Keys() {
+		if !seen[k.(NodeKey)] {
+			v, _ := n.c.Get(k)
+			olist = append(olist, *v.(*NodeInfo))
+		}
 	}
 	return olist
 }
 
 func (a *AcctCache) UpsertAccount(orgId string, role string, acct common.Address, orgAdmin bool, status AcctStatus) {
+	permissionFeed.Send(PermissionEvent{Kind: AccountPermissionEvent, Info: a.upsertAccount(orgId, role, acct, orgAdmin, status)})
+}
+
+func (a *AcctCache) upsertAccount(orgId string, role string, acct common.Address, orgAdmin bool, status AcctStatus) *AccountInfo {
+	defer a.mux.Unlock()
+	a.mux.Lock()
+	key := AccountKey{acct}
+	info := &AccountInfo{orgId, role, acct, orgAdmin, status}
+	a.c.Add(key, info)
+	return info
+}
+
+// AddAccountIfAbsent caches the account read from the contract unless the account is
+// cached already, e.g. updated by an event in the meantime. It reports whether the
+// account was added.
+func (a *AcctCache) AddAccountIfAbsent(orgId string, role string, acct common.Address, orgAdmin bool, status AcctStatus) bool {
+	info, added := a.addAccount(orgId, role, acct, orgAdmin, status)
+	if added {
+		permissionFeed.Send(PermissionEvent{Kind: AccountPermissionEvent, Info: info})
+	}
+	return added
+}
+
+// caches the account unless it is cached already, and returns the cached account
+func (a *AcctCache) addAccount(orgId string, role string, acct common.Address, orgAdmin bool, status AcctStatus) (*AccountInfo, bool) {
+	defer a.mux.Unlock()
+	a.mux.Lock()
 	key := AccountKey{acct}
-	a.c.Add(key, &AccountInfo{orgId, role, acct, orgAdmin, status})
+	if ent, ok := a.c.Get(key); ok {
+		return ent.(*AccountInfo), false
+	}
+	info := &AccountInfo{orgId, role, acct, orgAdmin, status}
+	a.c.Add(key, info)
+	return info, true
 }
 
 func (a *AcctCache) GetAccount(acct common.Address) (*AccountInfo, error) {
@@ -420,14 +740,14 @@ func (a *AcctCache) GetAccount(acct common.Address) (*AccountInfo, error) {
 
 	// check if the account cache is evicted. if yes we need
 	// fetch the record from the contract
-	if a.evicted {
+	if a.evicted || CachesLoading() {
 		// call function to populate cache with the record
 		acctRec, err := a.populateCacheFunc(acct)
-		// insert the received record into cache
 		if err != nil {
 			return nil, err
 		}
-		a.UpsertAccount(acctRec.OrgId, acctRec.RoleId, acctRec.AcctId, acctRec.IsOrgAdmin, acctRec.Status)
+		// insert the received record into cache, unless an event updated it meanwhile
+		acctRec, _ = a.addAccount(acctRec.OrgId, acctRec.RoleId, acctRec.AcctId, acctRec.IsOrgAdmin, acctRec.Status)
 		//return the record
 		return acctRec, nil
 	}
@@ -435,11 +755,32 @@ func (a *AcctCache) GetAccount(acct common.Address) (*AccountInfo, error) {
 }
 
 func (a *AcctCache) GetAcctList() []AccountInfo {
-	alist := make([]AccountInfo, len(a.c.Keys()))
-	for i, k := range a.c.Keys() {
-		v, _ := a.c.Get(k)
-		vp := v.(*AccountInfo)
-		alist[i] = *vp
+	// the cache is partial while loading, so the accounts are read once from the contract
+	// and listed with the cached ones, which are up to date with the events
+	var contractAccts []AccountInfo
+	if CachesLoading() && a.populateListFunc != nil {
+		list, err := a.contractList.get(func() (interface{}, error) { return a.populateListFunc() })
+		if err == nil {
+			contractAccts = list.([]AccountInfo)
+		} else {
+			log.Warn("Failed to read the accounts from contract, listing the cached accounts", "err", err)
+		}
+	}
+	seen := make(map[AccountKey]bool)
+	alist := make([]AccountInfo, 0, len(contractAccts)+a.c.Len())
+	for _, acct := range contractAccts {
+		key := AccountKey{acct.AcctId}
+		if v, ok := a.c.Get(key); ok {
+			acct = *v.(*AccountInfo)
+		}
+		seen[key] = true
+		alist = append(alist, acct)
+	}
+	for _, k := range a.c.Keys() {
+		if !seen[k.(AccountKey)] {
+			v, _ := a.c.Get(k)
+			alist = append(alist, *v.(*AccountInfo))
+		}
 	}
 	return alist
 }
@@ -475,9 +816,40 @@ func (a *AcctCache) GetAcctListRole(orgId, roleId string) []AccountInfo {
 }
 
 func (r *RoleCache) UpsertRole(orgId string, role string, voter bool, admin bool, access AccessType, active bool) {
+	permissionFeed.Send(PermissionEvent{Kind: RolePermissionEvent, Info: r.upsertRole(orgId, role, voter, admin, access, active)})
+}
+
+func (r *RoleCache) upsertRole(orgId string, role string, voter bool, admin bool, access AccessType, active bool) *RoleInfo {
+	defer r.mux.Unlock()
+	r.mux.Lock()
 	key := RoleKey{orgId, role}
-	r.c.Add(key, &RoleInfo{orgId, role, voter, admin, access, active})
+	info := &RoleInfo{orgId, role, voter, admin, access, active}
+	r.c.Add(key, info)
+	return info
+}
+
+// AddRoleIfAbsent caches the role read from the contract unless the role is cached
+// already, e.g. updated by an event in the meantime. It reports whether the role
+// was added.
+func (r *RoleCache) AddRoleIfAbsent(orgId string, role string, voter bool, admin bool, access AccessType, active bool) bool {
+	info, added := r.addRole(orgId, role, voter, admin, access, active)
+	if added {
+		permissionFeed.Send(PermissionEvent{Kind: RolePermissionEvent, Info: info})
+	}
+	return added
+}
 
+// caches the role unless it is cached already, and returns the cached role
+func (r *RoleCache) addRole(orgId string, role string, voter bool, admin bool, access AccessType, active bool) (*RoleInfo, bool) {
+	defer r.mux.Unlock()
+	r.mux.Lock()
+	key := RoleKey{orgId, role}
+	if ent, ok := r.c.Get(key); ok {
+		return ent.(*RoleInfo), false
+	}
+	info := &RoleInfo{orgId, role, voter, admin, access, active}
+	r.c.Add(key, info)
+	return info, true
 }
 
 func (r *RoleCache) GetRole(orgId string, roleId string) (*RoleInfo, error) {
@@ -487,14 +859,14 @@ func (r *RoleCache) GetRole(orgId string, roleId string) (*RoleInfo, error) {
 	}
 	// check if the role cache is evicted. if yes we need
 	// fetch the record from the contract
-	if r.evicted {
+	if r.evicted || CachesLoading() {
 		// call cache population function to populate from contract
 		roleRec, err := r.populateCacheFunc(&RoleKey{RoleId: roleId, OrgId: orgId})
 		if err != nil {
 			return nil, err
 		}
-		// insert the received record into cache
-		r.UpsertRole(roleRec.OrgId, roleRec.RoleId, roleRec.IsVoter, roleRec.IsAdmin, roleRec.Access, roleRec.Active)
+		// insert the received record into cache, unless an event updated it meanwhile
+		roleRec, _ = r.addRole(roleRec.OrgId, roleRec.RoleId, roleRec.IsVoter, roleRec.IsAdmin, roleRec.Access, roleRec.Active)
 
 		//return the record
 		return roleRec, nil
@@ -503,11 +875,32 @@ func (r *RoleCache) GetRole(orgId string, roleId string) (*RoleInfo, error) {
 }
 
 func (r *RoleCache) GetRoleList() []RoleInfo {
-	rlist := make([]RoleInfo, len(r.c.Keys()))
-	for i, k := range r.c.Keys() {
-		v, _ := r.c.Get(k)
-		vp := v.(*RoleInfo)
-		rlist[i] = *vp
+	// the cache is partial while loading, so the roles are read once from the contract
+	// and listed with the cached ones, which are up to date with the events
+	var contractRoles []RoleInfo
+	if CachesLoading() && r.populateListFunc != nil {
+		list, err := r.contractList.get(func() (interface{}, error) { return r.populateListFunc() })
+		if err == nil {
+			contractRoles = list.([]RoleInfo)
+		} else {
+			log.Warn("Failed to read the roles from contract, listing the cached roles", "err", err)
+		}
+	}
+	seen := make(map[RoleKey]bool)
+	rlist := make([]RoleInfo, 0, len(contractRoles)+r.c.Len())
+	for _, role := range contractRoles {
+		key := RoleKey{OrgId: role.OrgId, RoleId: role.RoleId}
+		if v, ok := r.c.Get(key); ok {
+			role = *v.(*RoleInfo)
+		}
+		seen[key] = true
+		rlist = append(rlist, role)
+	}
+	for _, k := range r.c.Keys() {
+		if !seen[k.(RoleKey)] {
+			v, _ := r.c.Get(k)
+			rlist = append(rlist, *v.(*RoleInfo))
+		}
 	}
 	return rlist
 }
@@ -553,6 +946,11 @@ func checkIfOrgActive(orgId string) bool {
 	return false
 }
 
+// IsOrgActive checks if the given org is active in the network
+func IsOrgActive(orgId string) bool {
+	return checkIfOrgActive(orgId)
+}
+
 // checks if the passed account is linked to a org admin or
 // network admin role
 func CheckIfAdminAccount(acctId common.Address) bool {
@@ -612,7 +1010,7 @@ func ValidateNodeForTxn(hexnodeId string, from common.Address) bool {
 			}
 		}
 	}
-	if NodeInfoMap.evicted {
+	if NodeInfoMap.evicted || CachesLoading() {
 		return NodeInfoMap.populateAndValidateFunc(hexnodeId, acOrgRec.UltimateParent)
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	testifyassert "github.com/stretchr/testify/assert"
 )
@@ -86,6 +87,121 @@ func TestOrgCache_UpsertOrg(t *testing.T) {
 	assert.True(len(orgList) == 3, fmt.Sprintf("Expected 3 entries, got %v", len(orgList)))
 }
 
+func TestOrgCache_GetOrg_whenCachesLoading(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	OrgInfoMap = NewOrgCache(params.DEFAULT_ORGCACHE_SIZE)
+	OrgInfoMap.PopulateCacheFunc(func(orgId string) (*OrgInfo, error) {
+		return &OrgInfo{OrgId: orgId, FullOrgId: orgId, UltimateParent: orgId, Level: big.NewInt(1), Status: OrgApproved}, nil
+	})
+
+	_, err := OrgInfoMap.GetOrg(NETWORKADMIN)
+	assert.Error(err, "the org is not fetched from the contract once the caches are loaded")
+
+	SetCachesLoading(true)
+	defer SetCachesLoading(false)
+	orgInfo, err := OrgInfoMap.GetOrg(NETWORKADMIN)
+
+	assert.NoError(err)
+	assert.Equal(NETWORKADMIN, orgInfo.OrgId)
+	assert.True(OrgInfoMap.HasOrg(NETWORKADMIN), "the fetched org is cached")
+}
+
+func TestNodeCache_GetNodeByEnodeID(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	NodeInfoMap.UpsertNode(NETWORKADMIN, NODE1, NodeApproved)
+
+	node1, _ := enode.ParseV4(NODE1)
+	nodeInfo := NodeInfoMap.GetNodeByEnodeID(node1.ID())
+	assert.NotNil(nodeInfo)
+	assert.Equal(NETWORKADMIN, nodeInfo.OrgId)
+	assert.Equal("Approved", nodeInfo.Status.String())
+
+	node2, _ := enode.ParseV4(NODE2)
+	assert.Nil(NodeInfoMap.GetNodeByEnodeID(node2.ID()))
+}
+
+func TestNodeCache_GetNodeList_whenCachesLoading(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	NodeInfoMap.UpsertNode(NETWORKADMIN, NODE1, NodeApproved)
+	NodeInfoMap.PopulateListFunc(func() ([]NodeInfo, error) {
+		return []NodeInfo{{NETWORKADMIN, NODE1, NodeApproved}, {ORGADMIN, NODE2, NodeApproved}}, nil
+	})
+	node2, _ := enode.ParseV4(NODE2)
+
+	assert.Len(NodeInfoMap.GetNodeList(), 1, "the nodes are listed from the contract only while the caches are loading")
+	assert.Nil(NodeInfoMap.GetNodeByEnodeID(node2.ID()))
+
+	SetCachesLoading(true)
+	defer SetCachesLoading(false)
+
+	assert.Len(NodeInfoMap.GetNodeList(), 2)
+	nodeInfo := NodeInfoMap.GetNodeByEnodeID(node2.ID())
+	assert.NotNil(nodeInfo, "the node not cached yet is found in the contract")
+	assert.Equal(ORGADMIN, nodeInfo.OrgId)
+}
+
+func TestNodeCache_GetNodeList_whenCachesLoading_thenContractReadOnce(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	reads := 0
+	NodeInfoMap.PopulateListFunc(func() ([]NodeInfo, error) {
+		reads++
+		return []NodeInfo{{NETWORKADMIN, NODE1, NodeApproved}, {ORGADMIN, NODE2, NodeApproved}}, nil
+	})
+	SetCachesLoading(true)
+	defer SetCachesLoading(false)
+
+	assert.Len(NodeInfoMap.GetNodeList(), 2)
+	// updated by an event after the contract was read
+	NodeInfoMap.UpsertNode(ORGADMIN, NODE2, NodeDeactivated)
+	nodes := NodeInfoMap.GetNodeList()
+
+	assert.Equal(1, reads)
+	assert.Len(nodes, 2)
+	assert.Equal(NodeDeactivated, nodes[1].Status)
+
+	// read again on the next loading
+	SetCachesLoading(true)
+	NodeInfoMap.GetNodeList()
+	assert.Equal(2, reads)
+}
+
+func TestNodeCache_AddNodeIfAbsent(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	NodeInfoMap.UpsertNode(NETWORKADMIN, NODE1, NodeDeactivated)
+
+	assert.False(NodeInfoMap.AddNodeIfAbsent(NETWORKADMIN, NODE1, NodeApproved), "the node updated by an event is kept")
+	assert.True(NodeInfoMap.AddNodeIfAbsent(NETWORKADMIN, NODE2, NodeApproved))
+
+	nodeInfo, err := NodeInfoMap.GetNodeByUrl(NODE1)
+	assert.NoError(err)
+	assert.Equal(NodeDeactivated, nodeInfo.Status)
+	nodeInfo, err = NodeInfoMap.GetNodeByUrl(NODE2)
+	assert.NoError(err)
+	assert.Equal(NodeApproved, nodeInfo.Status)
+}
+
+func TestOrgCache_AddOrgIfAbsent_whenSubOrg(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	OrgInfoMap = NewOrgCache(params.DEFAULT_ORGCACHE_SIZE)
+	OrgInfoMap.UpsertOrg(NETWORKADMIN, "", NETWORKADMIN, big.NewInt(1), OrgApproved)
+	OrgInfoMap.UpsertOrg("SUB1", NETWORKADMIN, NETWORKADMIN, big.NewInt(2), OrgSuspended)
+
+	assert.False(OrgInfoMap.AddOrgIfAbsent("SUB1", NETWORKADMIN, NETWORKADMIN, big.NewInt(2), OrgApproved), "the sub org is keyed by its full org id")
+	orgInfo, err := OrgInfoMap.GetOrg(NETWORKADMIN + ".SUB1")
+	assert.NoError(err)
+	assert.Equal(OrgSuspended, orgInfo.Status)
+}
+
 func TestNodeCache_UpsertNode(t *testing.T) {
 	assert := testifyassert.New(t)
 
@@ -431,3 +547,17 @@ func Test_checkIfOrgActive(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscribePermissionEvent(t *testing.T) {
+	assert := testifyassert.New(t)
+	ch := make(chan PermissionEvent, 1)
+	sub := SubscribePermissionEvent(ch)
+	defer sub.Unsubscribe()
+
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	NodeInfoMap.UpsertNode(NETWORKADMIN, NODE1, NodeApproved)
+
+	ev := <-ch
+	assert.Equal(NodePermissionEvent, ev.Kind)
+	assert.Equal(&NodeInfo{NETWORKADMIN, NODE1, NodeApproved}, ev.Info)
+}
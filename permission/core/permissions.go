@@ -6,6 +6,8 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -15,24 +17,59 @@ import (
 
 // check if a given node is permissioned to connect to the change
 func IsNodePermissioned(nodename string, currentNode string, datadir string, direction string) bool {
-	var permissionedList []string
-	nodes := ParsePermissionedNodes(datadir)
-	for _, v := range nodes {
-		permissionedList = append(permissionedList, v.ID().String())
-	}
-
-	log.Debug("IsNodePermissioned", "permissionedList", permissionedList)
-	for _, v := range permissionedList {
-		if v == nodename {
-			log.Debug("IsNodePermissioned", "connection", direction, "nodename", nodename[:params.NODE_NAME_LENGTH], "ALLOWED-BY", currentNode[:params.NODE_NAME_LENGTH])
-			// check if the node is blacklisted
-			return !isNodeBlackListed(nodename, datadir)
+	// the list is parsed again only when permissioned-nodes.json changes, the discovery
+	// checks the nodes on every packet
+	permissionedList, _ := cachedNodeIDs(filepath.Join(datadir, params.PERMISSIONED_CONFIG), func(string) (map[string]bool, error) {
+		ids := make(map[string]bool)
+		for _, v := range ParsePermissionedNodes(datadir) {
+			ids[v.ID().String()] = true
 		}
+		return ids, nil
+	})
+
+	if permissionedList[nodename] {
+		log.Debug("IsNodePermissioned", "connection", direction, "nodename", nodename[:params.NODE_NAME_LENGTH], "ALLOWED-BY", currentNode[:params.NODE_NAME_LENGTH])
+		// check if the node is blacklisted
+		return !isNodeBlackListed(nodename, datadir)
 	}
 	log.Debug("IsNodePermissioned", "connection", direction, "nodename", nodename[:params.NODE_NAME_LENGTH], "DENIED-BY", currentNode[:params.NODE_NAME_LENGTH])
 	return false
 }
 
+// nodeListFile is the cached content of a node list file
+type nodeListFile struct {
+	modTime time.Time
+	size    int64
+	ids     map[string]bool
+	err     error
+}
+
+var (
+	nodeListFilesMu sync.Mutex
+	nodeListFiles   = make(map[string]*nodeListFile) // by path
+)
+
+// cachedNodeIDs returns the node ids of the list file at path, loaded again only when the
+// file has changed. It returns the error of os.Stat if the file can't be found.
+func cachedNodeIDs(path string, load func(path string) (map[string]bool, error)) (map[string]bool, error) {
+	nodeListFilesMu.Lock()
+	defer nodeListFilesMu.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		delete(nodeListFiles, path)
+		// let the loader report the missing file
+		ids, _ := load(path)
+		return ids, err
+	}
+	if f, ok := nodeListFiles[path]; ok && f.modTime.Equal(info.ModTime()) && f.size == info.Size() {
+		return f.ids, f.err
+	}
+	ids, err := load(path)
+	nodeListFiles[path] = &nodeListFile{modTime: info.ModTime(), size: info.Size(), ids: ids, err: err}
+	return ids, err
+}
+
 //this is a shameless copy from the config.go. It is a duplication of the code
 //for the timebeing to allow reload of the permissioned nodes while the server is running
 
@@ -79,30 +116,39 @@ func isNodeBlackListed(nodeName, dataDir string) bool {
 	log.Debug("isNodeBlackListed", "DataDir", dataDir, "file", params.BLACKLIST_CONFIG)
 
 	path := filepath.Join(dataDir, params.BLACKLIST_CONFIG)
-	if _, err := os.Stat(path); err != nil {
+	blackListed, err := cachedNodeIDs(path, loadBlackListedNodes)
+	if os.IsNotExist(err) {
 		log.Debug("Read Error for disallowed-nodes.json file. disallowed-nodes.json file is not present.", "err", err)
 		return false
 	}
+	if err != nil {
+		return true
+	}
+	return blackListed[nodeName]
+}
+
+// loadBlackListedNodes returns the ids of the nodes of disallowed-nodes.json
+func loadBlackListedNodes(path string) (map[string]bool, error) {
 	// Load the nodes from the config file
 	blob, err := ioutil.ReadFile(path)
 	if err != nil {
 		log.Debug("isNodeBlackListed: Failed to access nodes", "err", err)
-		return true
+		return nil, err
 	}
 
 	nodelist := []string{}
 	if err := json.Unmarshal(blob, &nodelist); err != nil {
 		log.Debug("parsePermissionedNodes: Failed to load nodes", "err", err)
-		return true
+		return nil, err
 	}
 
+	ids := make(map[string]bool)
 	for _, v := range nodelist {
-		n, _ := enode.ParseV4(v)
-		if nodeName == n.ID().String() {
-			return true
+		if n, err := enode.ParseV4(v); err == nil {
+			ids[n.ID().String()] = true
 		}
 	}
-	return false
+	return ids, nil
 }
 
 // function checks for account access to execute the transaction
@@ -65,6 +65,27 @@ func TestIsNodePermissioned(t *testing.T) {
 
 }
 
+func TestIsNodePermissioned_whenFileChanges(t *testing.T) {
+	d, _ := ioutil.TempDir("", "qdata")
+	defer os.RemoveAll(d)
+	writeNodeToFile(d, params.PERMISSIONED_CONFIG, node1)
+	n1, _ := enode.ParseV4(node1)
+	n2, _ := enode.ParseV4(node2)
+	if IsNodePermissioned(n2.ID().String(), n1.EnodeID(), d, "INWARD") {
+		t.Fatal("node not in permissioned-nodes.json is permissioned")
+	}
+
+	writeNodeToFile(d, params.PERMISSIONED_CONFIG, node2)
+
+	if !IsNodePermissioned(n2.ID().String(), n1.EnodeID(), d, "INWARD") {
+		t.Error("node added to permissioned-nodes.json is not permissioned")
+	}
+	writeNodeToFile(d, params.BLACKLIST_CONFIG, node2)
+	if IsNodePermissioned(n2.ID().String(), n1.EnodeID(), d, "INWARD") {
+		t.Error("node added to disallowed-nodes.json is permissioned")
+	}
+}
+
 func Test_isNodeBlackListed(t *testing.T) {
 	type args struct {
 		nodeName string
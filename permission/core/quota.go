@@ -0,0 +1,159 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// deferredTxMeter counts the transactions left out of a block by the quota of their org
+var deferredTxMeter = metrics.NewRegisteredMeter("permission/orgquota/deferred", nil)
+
+// OrgQuotaConfig limits the share of each block an organization may use, so that one
+// member can't consume all the capacity of the network. The quotas are enforced by the
+// block maker on the transactions sent from the accounts of the organizations, the
+// sub-organizations sharing the quota of their ultimate parent.
+type OrgQuotaConfig struct {
+	// GasShare is the share of the block gas limit an organization may use, 0 for no limit
+	GasShare float64 `toml:",omitempty"`
+	// MaxTxs is the number of transactions of an organization per block, 0 for no limit
+	MaxTxs int `toml:",omitempty"`
+	// Orgs overrides the quotas of some organizations, by ultimate parent org id
+	Orgs map[string]*OrgQuota `toml:",omitempty"`
+}
+
+// OrgQuota is the quota of an organization
+type OrgQuota struct {
+	GasShare float64 `toml:",omitempty"`
+	MaxTxs   int     `toml:",omitempty"`
+}
+
+// Enabled reports whether a quota applies to any organization
+func (c *OrgQuotaConfig) Enabled() bool {
+	if c == nil {
+		return false
+	}
+	if c.GasShare > 0 || c.MaxTxs > 0 {
+		return true
+	}
+	for _, quota := range c.Orgs {
+		if quota != nil && (quota.GasShare > 0 || quota.MaxTxs > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the shares are within (0, 1] and the counts are positive
+func (c *OrgQuotaConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	check := func(name string, gasShare float64, maxTxs int) error {
+		if gasShare < 0 || gasShare > 1 {
+			return fmt.Errorf("%s: the gas share must be between 0 and 1", name)
+		}
+		if maxTxs < 0 {
+			return fmt.Errorf("%s: the number of transactions must not be negative", name)
+		}
+		return nil
+	}
+	if err := check("default quota", c.GasShare, c.MaxTxs); err != nil {
+		return err
+	}
+	for org, quota := range c.Orgs {
+		if quota == nil {
+			return fmt.Errorf("quota of org %q: missing", org)
+		}
+		if strings.Contains(org, ".") {
+			return errors.New("the quotas are set per ultimate parent org, not per sub-org " + org)
+		}
+		if err := check("quota of org "+org, quota.GasShare, quota.MaxTxs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *OrgQuotaConfig) quota(org string) OrgQuota {
+	if quota, ok := c.Orgs[org]; ok {
+		return *quota
+	}
+	return OrgQuota{GasShare: c.GasShare, MaxTxs: c.MaxTxs}
+}
+
+// OrgQuotaTracker accounts the gas and transactions of the organizations while a
+// block is packed. A nil tracker allows every transaction.
+type OrgQuotaTracker struct {
+	config   *OrgQuotaConfig
+	gasLimit uint64
+	orgOf    func(common.Address) string
+	gasUsed  map[string]uint64
+	txs      map[string]int
+}
+
+// NewOrgQuotaTracker returns the tracker of a block, nil if no quota applies
+func NewOrgQuotaTracker(config *OrgQuotaConfig, gasLimit uint64) *OrgQuotaTracker {
+	if !config.Enabled() {
+		return nil
+	}
+	return newOrgQuotaTracker(config, gasLimit, orgOfAccount)
+}
+
+func newOrgQuotaTracker(config *OrgQuotaConfig, gasLimit uint64, orgOf func(common.Address) string) *OrgQuotaTracker {
+	return &OrgQuotaTracker{
+		config:   config,
+		gasLimit: gasLimit,
+		orgOf:    orgOf,
+		gasUsed:  make(map[string]uint64),
+		txs:      make(map[string]int),
+	}
+}
+
+// orgOfAccount returns the ultimate parent org of an account from the permission
+// caches, empty if the account doesn't belong to an org or permissioning is disabled
+func orgOfAccount(account common.Address) string {
+	if !PermissionsEnabled() || AcctInfoMap == nil || OrgInfoMap == nil {
+		return ""
+	}
+	acct, err := AcctInfoMap.GetAccount(account)
+	if err != nil || acct == nil {
+		return ""
+	}
+	if org, err := OrgInfoMap.GetOrg(acct.OrgId); err == nil && org != nil && org.UltimateParent != "" {
+		return org.UltimateParent
+	}
+	return strings.SplitN(acct.OrgId, ".", 2)[0]
+}
+
+// Allow returns the org of the sender of a transaction, and whether the transaction
+// fits in the remaining quota of the org given its gas limit. The transactions of the
+// accounts without an org are always allowed.
+func (t *OrgQuotaTracker) Allow(from common.Address, gas uint64) (string, bool) {
+	if t == nil {
+		return "", true
+	}
+	org := t.orgOf(from)
+	if org == "" {
+		return "", true
+	}
+	quota := t.config.quota(org)
+	if (quota.MaxTxs > 0 && t.txs[org] >= quota.MaxTxs) ||
+		(quota.GasShare > 0 && t.gasUsed[org]+gas > uint64(quota.GasShare*float64(t.gasLimit))) {
+		deferredTxMeter.Mark(1)
+		return org, false
+	}
+	return org, true
+}
+
+// Record accounts a transaction of the org included in the block
+func (t *OrgQuotaTracker) Record(org string, gasUsed uint64) {
+	if t == nil || org == "" {
+		return
+	}
+	t.gasUsed[org] += gasUsed
+	t.txs[org]++
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func TestOrgQuotaTracker_Allow(t *testing.T) {
+	assert := testifyassert.New(t)
+	var (
+		org1, org2, none = common.Address{1}, common.Address{2}, common.Address{3}
+		orgs             = map[common.Address]string{org1: "ORG1", org2: "ORG2"}
+	)
+	tracker := newOrgQuotaTracker(&OrgQuotaConfig{
+		GasShare: 0.5,
+		Orgs:     map[string]*OrgQuota{"ORG2": {MaxTxs: 1}},
+	}, 100000, func(a common.Address) string { return orgs[a] })
+
+	org, allowed := tracker.Allow(org1, 40000)
+	assert.True(allowed)
+	assert.Equal("ORG1", org)
+	tracker.Record(org, 40000)
+
+	_, allowed = tracker.Allow(org1, 21000)
+	assert.False(allowed, "over the gas share of the org")
+	_, allowed = tracker.Allow(org1, 10000)
+	assert.True(allowed)
+
+	org, allowed = tracker.Allow(org2, 90000)
+	assert.True(allowed, "the org overrides the default gas share")
+	tracker.Record(org, 90000)
+	_, allowed = tracker.Allow(org2, 21000)
+	assert.False(allowed, "over the number of transactions of the org")
+
+	_, allowed = tracker.Allow(none, 100000)
+	assert.True(allowed, "the accounts without org are not limited")
+}
+
+func TestOrgQuotaTracker_whenDisabled(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	assert.Nil(NewOrgQuotaTracker(nil, 100000))
+	assert.Nil(NewOrgQuotaTracker(&OrgQuotaConfig{}, 100000))
+
+	var tracker *OrgQuotaTracker
+	_, allowed := tracker.Allow(common.Address{1}, 100000)
+	assert.True(allowed)
+}
+
+func TestOrgQuotaConfig_Validate(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	assert.NoError((*OrgQuotaConfig)(nil).Validate())
+	assert.NoError((&OrgQuotaConfig{GasShare: 0.25, MaxTxs: 10}).Validate())
+	assert.Error((&OrgQuotaConfig{GasShare: 1.5}).Validate())
+	assert.Error((&OrgQuotaConfig{MaxTxs: -1}).Validate())
+	assert.Error((&OrgQuotaConfig{Orgs: map[string]*OrgQuota{"ORG1.SUB1": {MaxTxs: 1}}}).Validate())
+}
@@ -1,6 +1,7 @@
 package permission
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -39,6 +40,8 @@ func (p *PermissionCtrl) AfterStart() error {
 	// set the function point for transaction allowed check
 	pcore.PermissionTransactionAllowedFunc = p.IsTransactionAllowed
 	setPermissionService(p)
+	// report the org and status of peers in admin_peers
+	p.node.Server().SetPeerInfoProvider("permission", p.peerInfo)
 
 	// set the default access to ReadOnly
 	pcore.SetDefaults(p.permConfig.NwAdminRole, p.permConfig.OrgAdminRole, p.IsV2Permission())
@@ -143,16 +146,20 @@ func (p *PermissionCtrl) instantiateCache(orgCacheSize, roleCacheSize, nodeCache
 	// instantiate the cache objects for permissions
 	pcore.OrgInfoMap = pcore.NewOrgCache(orgCacheSize)
 	pcore.OrgInfoMap.PopulateCacheFunc(p.populateOrgToCache)
+	pcore.OrgInfoMap.PopulateListFunc(p.orgsFromContract)
 
 	pcore.RoleInfoMap = pcore.NewRoleCache(roleCacheSize)
 	pcore.RoleInfoMap.PopulateCacheFunc(p.populateRoleToCache)
+	pcore.RoleInfoMap.PopulateListFunc(p.rolesFromContract)
 
 	pcore.NodeInfoMap = pcore.NewNodeCache(nodeCacheSize)
 	pcore.NodeInfoMap.PopulateCacheFunc(p.populateNodeCache)
 	pcore.NodeInfoMap.PopulateValidateFunc(p.populateNodeCacheAndValidate)
+	pcore.NodeInfoMap.PopulateListFunc(p.nodesFromContract)
 
 	pcore.AcctInfoMap = pcore.NewAcctCache(accountCacheSize)
 	pcore.AcctInfoMap.PopulateCacheFunc(p.populateAccountToCache)
+	pcore.AcctInfoMap.PopulateListFunc(p.accountsFromContract)
 }
 
 // Thus function checks if the initial network boot up status and if no
@@ -172,18 +179,72 @@ func (p *PermissionCtrl) populateInitPermissions(orgCacheSize, roleCacheSize, no
 			return err
 		}
 	} else {
-		//populate orgs, nodes, roles and accounts from contract
-		for _, f := range []func() error{
-			p.populateOrgsFromContract,
-			p.populateNodesFromContract,
-			p.populateRolesFromContract,
-			p.populateAccountsFromContract,
-		} {
-			if err := f(); err != nil {
-				return err
-			}
-		}
+		// replaying the contract can take minutes on large networks, so the caches
+		// are populated in the background. Meanwhile the records missing from the
+		// caches are fetched from the contract on first use, and the lists are read
+		// from the contract.
+		pcore.SetCachesLoading(true)
 		pcore.SetNetworkBootUpCompleted()
+		go p.populateCachesFromContract()
+	}
+	return nil
+}
+
+const (
+	// the attempts to populate the caches from contract before falling back to
+	// fetching the missing records on first use
+	cacheLoadAttempts   = 3
+	cacheLoadRetryDelay = 10 * time.Second
+)
+
+var errCacheLoadStopped = errors.New("permission service stopped")
+
+// populates orgs, nodes, roles and accounts from contract, retrying on failure. The
+// records already cached, fetched on first use or updated by an event, are kept.
+func (p *PermissionCtrl) populateCachesFromContract() {
+	stopChan, stopSubscription := ptype.SubscribeStopEvent()
+	defer stopSubscription.Unsubscribe()
+	defer pcore.SetCachesLoading(false)
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := p.populateCachesFromContractOnce(stopChan)
+		switch {
+		case err == nil:
+			log.Info("Permission caches populated from contract", "elapsed", time.Since(start))
+			return
+		case err == errCacheLoadStopped:
+			return
+		case attempt == cacheLoadAttempts:
+			// the records missing from the caches keep being fetched from the contract on first use
+			pcore.SetCachesEvicted()
+			log.Error("Failed to populate the permission caches from contract", "attempts", attempt, "err", err)
+			return
+		}
+		log.Warn("Failed to populate the permission caches from contract, retrying", "err", err, "retryIn", cacheLoadRetryDelay)
+		select {
+		case <-stopChan:
+			return
+		case <-time.After(cacheLoadRetryDelay):
+		}
+	}
+}
+
+func (p *PermissionCtrl) populateCachesFromContractOnce(stopChan <-chan ptype.StopEvent) error {
+	for _, f := range []func() error{
+		p.populateOrgsFromContract,
+		p.populateNodesFromContract,
+		p.populateRolesFromContract,
+		p.populateAccountsFromContract,
+	} {
+		select {
+		case <-stopChan:
+			return errCacheLoadStopped
+		default:
+		}
+		if err := f(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -220,69 +281,134 @@ func (p *PermissionCtrl) bootupNetwork() error {
 
 // populates the account access details from contract into cache
 func (p *PermissionCtrl) populateAccountsFromContract() error {
-	if numberOfRoles, err := p.contract.GetNumberOfAccounts(); err == nil {
-		iOrgNum := numberOfRoles.Uint64()
-		for k := uint64(0); k < iOrgNum; k++ {
-			if addr, org, role, status, orgAdmin, err := p.contract.GetAccountDetailsFromIndex(big.NewInt(int64(k))); err == nil {
-				pcore.AcctInfoMap.UpsertAccount(org, role, addr, orgAdmin, pcore.AcctStatus(int(status.Int64())))
-			}
-		}
-	} else {
+	accounts, err := p.accountsFromContract()
+	if err != nil {
 		return err
 	}
+	for _, a := range accounts {
+		pcore.AcctInfoMap.AddAccountIfAbsent(a.OrgId, a.RoleId, a.AcctId, a.IsOrgAdmin, a.Status)
+	}
 	return nil
 }
 
-// populates the role details from contract into cache
-func (p *PermissionCtrl) populateRolesFromContract() error {
-	if numberOfRoles, err := p.contract.GetNumberOfRoles(); err == nil {
-		iOrgNum := numberOfRoles.Uint64()
-		for k := uint64(0); k < iOrgNum; k++ {
-			if roleStruct, err := p.contract.GetRoleDetailsFromIndex(big.NewInt(int64(k))); err == nil {
-				pcore.RoleInfoMap.UpsertRole(roleStruct.OrgId, roleStruct.RoleId, roleStruct.Voter, roleStruct.Admin, pcore.AccessType(int(roleStruct.AccessType.Int64())), roleStruct.Active)
-			}
+// reads the account access details from contract
+func (p *PermissionCtrl) accountsFromContract() ([]pcore.AccountInfo, error) {
+	numberOfAccounts, err := p.contract.GetNumberOfAccounts()
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]pcore.AccountInfo, 0, numberOfAccounts.Uint64())
+	for k := uint64(0); k < numberOfAccounts.Uint64(); k++ {
+		addr, org, role, status, orgAdmin, err := p.contract.GetAccountDetailsFromIndex(big.NewInt(int64(k)))
+		if err != nil {
+			return nil, err
 		}
+		accounts = append(accounts, pcore.AccountInfo{OrgId: org, RoleId: role, AcctId: addr, IsOrgAdmin: orgAdmin, Status: pcore.AcctStatus(int(status.Int64()))})
+	}
+	return accounts, nil
+}
 
-	} else {
+// populates the role details from contract into cache
+func (p *PermissionCtrl) populateRolesFromContract() error {
+	roles, err := p.rolesFromContract()
+	if err != nil {
 		return err
 	}
+	for _, r := range roles {
+		pcore.RoleInfoMap.AddRoleIfAbsent(r.OrgId, r.RoleId, r.IsVoter, r.IsAdmin, r.Access, r.Active)
+	}
 	return nil
 }
 
+// reads the role details from contract
+func (p *PermissionCtrl) rolesFromContract() ([]pcore.RoleInfo, error) {
+	numberOfRoles, err := p.contract.GetNumberOfRoles()
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]pcore.RoleInfo, 0, numberOfRoles.Uint64())
+	for k := uint64(0); k < numberOfRoles.Uint64(); k++ {
+		roleStruct, err := p.contract.GetRoleDetailsFromIndex(big.NewInt(int64(k)))
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, pcore.RoleInfo{OrgId: roleStruct.OrgId, RoleId: roleStruct.RoleId, IsVoter: roleStruct.Voter, IsAdmin: roleStruct.Admin, Access: pcore.AccessType(int(roleStruct.AccessType.Int64())), Active: roleStruct.Active})
+	}
+	return roles, nil
+}
+
 // populates the Node details from contract into cache
 func (p *PermissionCtrl) populateNodesFromContract() error {
-	if numberOfNodes, err := p.contract.GetNumberOfNodes(); err == nil {
-		iOrgNum := numberOfNodes.Uint64()
-		for k := uint64(0); k < iOrgNum; k++ {
-			if orgId, url, status, err := p.contract.GetNodeDetailsFromIndex(big.NewInt(int64(k))); err == nil {
-				pcore.NodeInfoMap.UpsertNode(orgId, url, pcore.NodeStatus(int(status.Int64())))
-			}
-		}
-	} else {
+	nodes, err := p.nodesFromContract()
+	if err != nil {
 		return err
 	}
+	for _, n := range nodes {
+		pcore.NodeInfoMap.AddNodeIfAbsent(n.OrgId, n.Url, n.Status)
+	}
 	return nil
 }
 
+// reads the Node details from contract
+func (p *PermissionCtrl) nodesFromContract() ([]pcore.NodeInfo, error) {
+	numberOfNodes, err := p.contract.GetNumberOfNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]pcore.NodeInfo, 0, numberOfNodes.Uint64())
+	for k := uint64(0); k < numberOfNodes.Uint64(); k++ {
+		orgId, url, status, err := p.contract.GetNodeDetailsFromIndex(big.NewInt(int64(k)))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, pcore.NodeInfo{OrgId: orgId, Url: url, Status: pcore.NodeStatus(int(status.Int64()))})
+	}
+	return nodes, nil
+}
+
 // populates the org details from contract into cache
 func (p *PermissionCtrl) populateOrgsFromContract() error {
-
-	if numberOfOrgs, err := p.contract.GetNumberOfOrgs(); err == nil {
-		iOrgNum := numberOfOrgs.Uint64()
-		for k := uint64(0); k < iOrgNum; k++ {
-			if orgId, porgId, ultParent, level, status, err := p.contract.GetOrgInfo(big.NewInt(int64(k))); err == nil {
-				pcore.OrgInfoMap.UpsertOrg(orgId, porgId, ultParent, level, pcore.OrgStatus(int(status.Int64())))
-			}
-		}
-	} else {
+	orgs, err := p.orgsFromContract()
+	if err != nil {
 		return err
 	}
+	for _, o := range orgs {
+		pcore.OrgInfoMap.AddOrgIfAbsent(o.OrgId, o.ParentOrgId, o.UltimateParent, o.Level, o.Status)
+	}
 	return nil
 }
 
+// reads the org details from contract, with the sub orgs of each org
+func (p *PermissionCtrl) orgsFromContract() ([]pcore.OrgInfo, error) {
+	numberOfOrgs, err := p.contract.GetNumberOfOrgs()
+	if err != nil {
+		return nil, err
+	}
+	orgs := make([]pcore.OrgInfo, 0, numberOfOrgs.Uint64())
+	index := make(map[string]int)
+	for k := uint64(0); k < numberOfOrgs.Uint64(); k++ {
+		orgId, porgId, ultParent, level, status, err := p.contract.GetOrgInfo(big.NewInt(int64(k)))
+		if err != nil {
+			return nil, err
+		}
+		fullOrgId := orgId
+		if porgId != "" {
+			fullOrgId = porgId + "." + orgId
+		}
+		index[fullOrgId] = len(orgs)
+		orgs = append(orgs, pcore.OrgInfo{OrgId: orgId, FullOrgId: fullOrgId, ParentOrgId: porgId, UltimateParent: ultParent, Level: level, Status: pcore.OrgStatus(int(status.Int64()))})
+	}
+	for _, o := range orgs {
+		if i, ok := index[o.ParentOrgId]; ok && o.ParentOrgId != "" {
+			orgs[i].SubOrgList = append(orgs[i].SubOrgList, o.FullOrgId)
+		}
+	}
+	return orgs, nil
+}
+
 // Reads the node list from static-nodes.json and populates into the contract
 func (p *PermissionCtrl) populateStaticNodesToContract() error {
-	nodes := p.node.Server().Config.StaticNodes
+	nodes := p.node.Server().CurrentStaticNodes()
 	for _, node := range nodes {
 		url := pcore.GetNodeUrl(node.EnodeID(), node.IP().String(), uint16(node.TCP()), uint16(node.RaftPort()), p.isRaft)
 		_, err := p.contract.AddAdminNode(url)
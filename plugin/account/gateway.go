@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
 	"github.com/jpmorganchase/quorum-account-plugin-sdk-go/proto"
 )
 
@@ -211,3 +212,54 @@ func ToUrl(strUrl string) (accounts.URL, error) {
 
 	return url, nil
 }
+
+type signingPolicy struct {
+	client proto_common.PluginSigningPolicyServiceClient
+}
+
+func (g *signingPolicy) Evaluate(ctx context.Context, req *SigningRequest) (bool, string, error) {
+	pbReq := &proto_common.PluginSigningPolicy_EvaluateRequest{
+		Address: req.Account.Address.Bytes(),
+	}
+	if tx := req.Transaction; tx != nil {
+		pbTx := &proto_common.PluginSigningPolicy_Transaction{
+			Nonce:    tx.Nonce(),
+			Value:    tx.Value().Bytes(),
+			Gas:      tx.Gas(),
+			GasPrice: tx.GasPrice().Bytes(),
+			Data:     tx.Data(),
+			Private:  tx.IsPrivate(),
+		}
+		// the policy is evaluated against the plaintext payload of a private transaction
+		if tx.IsPrivate() && req.PrivatePayload != nil {
+			pbTx.Data = req.PrivatePayload
+		}
+		if tx.To() != nil {
+			pbTx.To = tx.To().Bytes()
+		}
+		if req.ChainID != nil {
+			pbTx.ChainId = req.ChainID.Bytes()
+		}
+		pbReq.Payload = &proto_common.PluginSigningPolicy_EvaluateRequest_Transaction{Transaction: pbTx}
+	} else {
+		pbReq.Payload = &proto_common.PluginSigningPolicy_EvaluateRequest_Data{Data: req.Data}
+	}
+	if req.Identity != nil {
+		authorities := make([]string, len(req.Identity.Authorities))
+		for i, a := range req.Identity.Authorities {
+			authorities[i] = a.Raw
+		}
+		pbReq.Identity = &proto_common.PluginSigningPolicy_Identity{
+			RawToken:    req.Identity.RawToken,
+			Authorities: authorities,
+		}
+	}
+	resp, err := g.client.Evaluate(ctx, pbReq)
+	if err != nil {
+		return false, "", err
+	}
+	if resp == nil {
+		return false, "", errors.New("empty response from plugin")
+	}
+	return resp.Allowed, resp.Reason, nil
+}
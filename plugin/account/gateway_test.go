@@ -3,17 +3,23 @@ package account
 import (
 	"context"
 	"encoding/json"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/plugin/account/internal/testutils"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
 	"github.com/golang/mock/gomock"
 	"github.com/jpmorganchase/quorum-account-plugin-sdk-go/mock_proto"
 	"github.com/jpmorganchase/quorum-account-plugin-sdk-go/proto"
+	secproto "github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -336,3 +342,133 @@ func Test_ToUrl_Invalid(t *testing.T) {
 	_, err := ToUrl(strUrl)
 	require.Error(t, err)
 }
+
+func TestSigningPolicy_Evaluate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	to := common.HexToAddress("0x2332f90a329c2c55ba120b1449d36a144d1f9fe4")
+	tx := types.NewTransaction(1, to, big.NewInt(10), 21000, big.NewInt(2), []byte{0xa9, 0x05, 0x9c, 0xbb})
+	identity := &secproto.PreAuthenticatedAuthenticationToken{
+		RawToken:    []byte("raw token"),
+		Authorities: []*secproto.GrantedAuthority{{Raw: "signer://0x4d6d744b6da435b5bbdde2526dc20e9a41cb72e5"}},
+	}
+	wantReq := &proto_common.PluginSigningPolicy_EvaluateRequest{
+		Address: acct1.Address.Bytes(),
+		Payload: &proto_common.PluginSigningPolicy_EvaluateRequest_Transaction{
+			Transaction: &proto_common.PluginSigningPolicy_Transaction{
+				Nonce:    1,
+				To:       to.Bytes(),
+				Value:    big.NewInt(10).Bytes(),
+				Gas:      21000,
+				GasPrice: big.NewInt(2).Bytes(),
+				Data:     []byte{0xa9, 0x05, 0x9c, 0xbb},
+				ChainId:  big.NewInt(20).Bytes(),
+			},
+		},
+		Identity: &proto_common.PluginSigningPolicy_Identity{
+			RawToken:    []byte("raw token"),
+			Authorities: []string{"signer://0x4d6d744b6da435b5bbdde2526dc20e9a41cb72e5"},
+		},
+	}
+
+	mockClient := proto_common.NewMockPluginSigningPolicyServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Evaluate(gomock.Any(), gomock.Eq(wantReq)).
+		Return(&proto_common.PluginSigningPolicy_EvaluateResponse{Allowed: false, Reason: "value too high"}, nil)
+
+	g := &signingPolicy{client: mockClient}
+	allowed, reason, err := g.Evaluate(context.Background(), &SigningRequest{
+		Account:     acct1,
+		Transaction: tx,
+		ChainID:     big.NewInt(20),
+		Identity:    identity,
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, "value too high", reason)
+}
+
+func TestSigningPolicy_Evaluate_whenPrivateTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	to := common.HexToAddress("0x2332f90a329c2c55ba120b1449d36a144d1f9fe4")
+	tx := types.NewTransaction(1, to, big.NewInt(0), 21000, big.NewInt(0), common.EncryptedPayloadHash{1}.Bytes())
+	tx.SetPrivate()
+	wantReq := &proto_common.PluginSigningPolicy_EvaluateRequest{
+		Address: acct1.Address.Bytes(),
+		Payload: &proto_common.PluginSigningPolicy_EvaluateRequest_Transaction{
+			Transaction: &proto_common.PluginSigningPolicy_Transaction{
+				Nonce:    1,
+				To:       to.Bytes(),
+				Value:    big.NewInt(0).Bytes(),
+				Gas:      21000,
+				GasPrice: big.NewInt(0).Bytes(),
+				Data:     []byte{0xa9, 0x05, 0x9c, 0xbb},
+				Private:  true,
+			},
+		},
+	}
+
+	mockClient := proto_common.NewMockPluginSigningPolicyServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Evaluate(gomock.Any(), gomock.Eq(wantReq)).
+		Return(&proto_common.PluginSigningPolicy_EvaluateResponse{Allowed: true}, nil)
+
+	g := &signingPolicy{client: mockClient}
+	allowed, _, err := g.Evaluate(context.Background(), &SigningRequest{
+		Account:        acct1,
+		Transaction:    tx,
+		PrivatePayload: []byte{0xa9, 0x05, 0x9c, 0xbb},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, allowed, "the policy is evaluated against the plaintext payload")
+}
+
+func TestSigningPolicy_Evaluate_whenSigningData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wantReq := &proto_common.PluginSigningPolicy_EvaluateRequest{
+		Address: acct1.Address.Bytes(),
+		Payload: &proto_common.PluginSigningPolicy_EvaluateRequest_Data{Data: []byte("arbitrary data")},
+	}
+
+	mockClient := proto_common.NewMockPluginSigningPolicyServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Evaluate(gomock.Any(), gomock.Eq(wantReq)).
+		Return(&proto_common.PluginSigningPolicy_EvaluateResponse{Allowed: true}, nil)
+
+	g := &signingPolicy{client: mockClient}
+	allowed, _, err := g.Evaluate(context.Background(), &SigningRequest{Account: acct1, Data: []byte("arbitrary data")})
+
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestReloadableSigningPolicy_Evaluate_whenNotImplemented(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := proto_common.NewMockPluginSigningPolicyServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Evaluate(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.Unimplemented, "not implemented"))
+
+	p := &ReloadableSigningPolicy{
+		DispenseFunc: func() (SigningPolicy, error) {
+			return &signingPolicy{client: mockClient}, nil
+		},
+	}
+	allowed, _, err := p.Evaluate(context.Background(), &SigningRequest{Account: acct1})
+
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type DispenseFunc func() (Service, error)
@@ -103,3 +105,25 @@ func (am *ReloadableService) ImportRawKey(ctx context.Context, rawKey string, ne
 	}
 	return s.ImportRawKey(ctx, rawKey, newAccountConfig)
 }
+
+type SigningPolicyDispenseFunc func() (SigningPolicy, error)
+
+// ReloadableSigningPolicy dispenses the signing policy from the plugin for every request
+// so it keeps working after the plugin is reloaded. Requests are allowed if the plugin
+// doesn't implement the signing policy service.
+type ReloadableSigningPolicy struct {
+	DispenseFunc SigningPolicyDispenseFunc
+}
+
+func (p *ReloadableSigningPolicy) Evaluate(ctx context.Context, req *SigningRequest) (bool, string, error) {
+	s, err := p.DispenseFunc()
+	if err != nil {
+		return false, "", err
+	}
+	allowed, reason, err := s.Evaluate(ctx, req)
+	if rpcStatus, ok := status.FromError(err); ok && err != nil && rpcStatus.Code() == codes.Unimplemented {
+		log.Trace("account plugin doesn't enforce a signing policy")
+		return true, "", nil
+	}
+	return allowed, reason, err
+}
@@ -2,9 +2,12 @@ package account
 
 import (
 	"context"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 )
 
 type Service interface {
@@ -24,3 +27,25 @@ type CreatorService interface {
 	NewAccount(ctx context.Context, newAccountConfig interface{}) (accounts.Account, error)
 	ImportRawKey(ctx context.Context, rawKey string, newAccountConfig interface{}) (accounts.Account, error)
 }
+
+// SigningRequest describes what is about to be signed by the account plugin
+type SigningRequest struct {
+	Account accounts.Account
+	// transaction to be signed, nil when signing arbitrary data
+	Transaction *types.Transaction
+	// chain ID used to sign Transaction, nil if it's not replay-protected
+	ChainID *big.Int
+	// plaintext payload of a private Transaction, whose data is the hash of the encrypted
+	// payload, nil if it's unknown
+	PrivatePayload []byte
+	// arbitrary data to be signed when Transaction is nil
+	Data []byte
+	// identity of the caller, nil if the request doesn't come from an authenticated JSON RPC call
+	Identity *proto.PreAuthenticatedAuthenticationToken
+}
+
+// SigningPolicy is evaluated for every signing request before the account plugin signs
+type SigningPolicy interface {
+	// Evaluate returns true if the request is allowed, otherwise a reason for the denial
+	Evaluate(ctx context.Context, req *SigningRequest) (bool, string, error)
+}
@@ -13,3 +13,15 @@ func NewPluginManagerAPI(pm *PluginManager) *PluginManagerAPI {
 func (pmapi *PluginManagerAPI) ReloadPlugin(name PluginInterfaceName) (bool, error) {
 	return pmapi.pm.Reload(name)
 }
+
+// SetPluginLogLevel changes at runtime the minimum level of logs forwarded from the plugin:
+// trace, debug, info, warn, error or off
+func (pmapi *PluginManagerAPI) SetPluginLogLevel(name PluginInterfaceName, level string) (bool, error) {
+	return pmapi.pm.SetLogLevel(name, level)
+}
+
+// ListAvailablePluginVersions reports versions available in Plugin Central and in the
+// local plugin directory for each configured plugin, and whether the installed one is outdated
+func (pmapi *PluginManagerAPI) ListAvailablePluginVersions() map[PluginInterfaceName]*PluginVersionsInfo {
+	return pmapi.pm.AvailableVersions()
+}
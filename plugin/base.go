@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	slog "log"
@@ -10,14 +12,24 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	iplugin "github.com/ethereum/go-ethereum/internal/plugin"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/plugin/certificate"
+	"github.com/ethereum/go-ethereum/plugin/events"
 	"github.com/ethereum/go-ethereum/plugin/initializer"
+	pmetrics "github.com/ethereum/go-ethereum/plugin/metrics"
+	"github.com/ethereum/go-ethereum/plugin/rpcapi"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/pborman/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type managedPlugin interface {
@@ -45,20 +57,38 @@ type basePlugin struct {
 	pluginWorkspace  string           // plugin workspace
 	commands         []string         // plugin executable commands
 	logger           log.Logger
+	relay            *namedPipeRelay // only available when using named pipe transport
+	tls              *managedTLS     // only available when mutual TLS is managed by geth
+	logDelegate      *logDelegate    // forwards logs from the plugin process
+	started          int32           // 1 if the plugin has been started successfully
+	lazyMux          sync.Mutex      // serializes starting a lazily initialized plugin on first use
+
+	stopped  int32 // 1 once the plugin has been stopped, it is not started again on use
+	startErr error // error of the start of a lazily initialized plugin, guarded by lazyMux
 }
 
 var basePluginPointerType = reflect.TypeOf(&basePlugin{})
 
 func newBasePlugin(pm *PluginManager, pluginInterface PluginInterfaceName, pluginDefinition PluginDefinition, gateways plugin.PluginSet) (*basePlugin, error) {
 	gateways[initializer.ConnectorName] = &initializer.PluginConnector{}
+	gateways[pmetrics.ConnectorName] = &pmetrics.PluginConnector{}
+	gateways[rpcapi.ConnectorName] = &rpcapi.PluginConnector{}
+	gateways[certificate.ConnectorName] = &certificate.PluginConnector{}
+	gateways[events.ConnectorName] = &events.PluginConnector{Hub: pm.eventHub}
 
+	level, err := pluginDefinition.logLevel()
+	if err != nil {
+		return nil, err
+	}
+	logger := log.New("provider", pluginInterface, "plugin", pluginDefinition.Name, "version", pluginDefinition.Version)
 	// build basePlugin
 	return &basePlugin{
 		pm:               pm,
 		pluginInterface:  pluginInterface,
-		logger:           log.New("provider", pluginInterface, "plugin", pluginDefinition.Name, "version", pluginDefinition.Version),
+		logger:           logger,
 		pluginDefinition: &pluginDefinition,
 		gateways:         gateways,
+		logDelegate:      newLogDelegate(logger.New("from", "plugin"), level),
 	}, nil
 
 }
@@ -66,12 +96,7 @@ func newBasePlugin(pm *PluginManager, pluginInterface PluginInterfaceName, plugi
 // metadata.Command must be populated correctly here
 func (bp *basePlugin) load() error {
 	// Get plugin distribution path
-	pluginDistFilePath, err := bp.pm.downloader.Download(bp.pluginDefinition)
-	if err != nil {
-		return err
-	}
-	// get file checksum
-	pluginChecksum, err := bp.checksum(pluginDistFilePath)
+	pluginDistFilePath, pluginChecksum, err := bp.pm.downloader.Download(bp.pluginDefinition)
 	if err != nil {
 		return err
 	}
@@ -100,27 +125,74 @@ func (bp *basePlugin) load() error {
 		bp.commands = append([]string{executable}, pluginMeta.Parameters...)
 	}
 	command.Dir = unPackDir
-	bp.client = plugin.NewClient(&plugin.ClientConfig{
+	if sandbox := bp.pluginDefinition.Sandbox; sandbox != nil {
+		bp.logger.Info("sandboxing plugin", "writablePaths", sandbox.WritablePaths)
+		if err := sandbox.apply(command, unPackDir); err != nil {
+			return err
+		}
+	}
+	transport := bp.pm.transportConfig()
+	config := &plugin.ClientConfig{
 		HandshakeConfig:  iplugin.DefaultHandshakeConfig,
 		Plugins:          bp.gateways,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-		Cmd:              command,
-		AutoMTLS:         true,
-		Logger:           &logDelegate{bp.logger.New("from", "plugin")},
-	})
+		Logger:           bp.logDelegate,
+		// forward anything the plugin prints out after the handshake
+		SyncStdout: bp.logDelegate.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Info}),
+	}
+	if bp.pm.certificateAuthority != nil {
+		if bp.tls, err = newManagedTLS(bp.pm.certificateAuthority, bp.pm.certificateValidity, bp.logger); err != nil {
+			return err
+		}
+		env, err := bp.tls.environ()
+		if err != nil {
+			return err
+		}
+		command.Env = append(command.Env, env...)
+		config.TLSConfig = bp.tls.tlsConfig()
+	}
+	if transport.Type == TransportNamedPipe {
+		// the plugin library can't dial named pipes so we launch the plugin and relay the pipe.
+		// Other users may open the named pipe, so mutual TLS managed by geth is required
+		relay := &namedPipeRelay{
+			dial:   dialNamedPipe,
+			pipe:   fmt.Sprintf(`\\.\pipe\quorum-plugin-%s`, uuid.New()),
+			logger: bp.logDelegate.eLogger,
+		}
+		if config.Reattach, err = relay.start(command); err != nil {
+			return err
+		}
+		bp.relay = relay
+	} else {
+		command.Env = append(command.Env, transport.environ()...)
+		config.Cmd = command
+		// mutual TLS authenticates both ends, which is a must for TCP
+		config.AutoMTLS = config.TLSConfig == nil
+		config.MinPort, config.MaxPort = transport.MinPort, transport.MaxPort
+	}
+	bp.client = plugin.NewClient(config)
 
 	bp.pluginWorkspace = unPackDir
 	return nil
 }
 
-func (bp *basePlugin) Start() (err error) {
+// Start starts the plugin, also when it has been stopped or has failed to start
+func (bp *basePlugin) Start() error {
+	bp.lazyMux.Lock()
+	defer bp.lazyMux.Unlock()
+	atomic.StoreInt32(&bp.stopped, 0)
+	bp.startErr = bp.start()
+	return bp.startErr
+}
+
+func (bp *basePlugin) start() (err error) {
 	startTime := time.Now()
 	defer func(startTime time.Time) {
 		if err == nil {
 			bp.logger.Info("Plugin started", "took", time.Since(startTime))
 		} else {
 			bp.logger.Error("Plugin failed to start", "error", err, "took", time.Since(startTime))
-			_ = bp.Stop()
+			_ = bp.stop()
 		}
 	}(startTime)
 	bp.logger.Info("Starting plugin")
@@ -135,20 +207,92 @@ func (bp *basePlugin) Start() (err error) {
 		return
 	}
 	bp.logger.Debug("Starting plugin: Initializing")
-	err = bp.init()
+	if err = bp.init(); err != nil {
+		return
+	}
+	if bp.tls != nil {
+		bp.tls.start(bp.certificateRotator)
+	}
+	bp.logger.Debug("Starting plugin: Connecting events")
+	if err = bp.connectEvents(); err != nil {
+		return
+	}
+	atomic.StoreInt32(&bp.started, 1)
 	return
 }
 
+var errPluginStopped = errors.New("plugin is stopped")
+
+// ensureStarted starts a lazily initialized plugin if it's not yet running. It is started
+// once, its start error is returned to the following uses and it is not started again
+// once stopped.
+func (bp *basePlugin) ensureStarted() error {
+	if !bp.pluginDefinition.Lazy || atomic.LoadInt32(&bp.started) == 1 {
+		return nil
+	}
+	bp.lazyMux.Lock()
+	defer bp.lazyMux.Unlock()
+	switch {
+	case atomic.LoadInt32(&bp.started) == 1:
+		return nil
+	case atomic.LoadInt32(&bp.stopped) == 1:
+		return errPluginStopped
+	case bp.startErr != nil:
+		return bp.startErr
+	}
+	bp.logger.Info("Starting lazily initialized plugin on first use")
+	bp.startErr = bp.start()
+	return bp.startErr
+}
+
 func (bp *basePlugin) Stop() error {
+	atomic.StoreInt32(&bp.stopped, 1)
+	return bp.stop()
+}
+
+// stop releases the plugin process and its resources
+func (bp *basePlugin) stop() error {
+	atomic.StoreInt32(&bp.started, 0)
+	if bp.tls != nil {
+		bp.tls.stop()
+		bp.tls = nil
+	}
 	if bp.client != nil {
 		bp.client.Kill()
 	}
+	if bp.relay != nil {
+		_ = bp.relay.Close()
+		bp.relay = nil
+	}
 	if bp.pluginWorkspace == "" {
 		return nil
 	}
 	return bp.cleanPluginWorkspace()
 }
 
+var errPluginNotStarted = errors.New("not started")
+
+// health pings the plugin process, lazily initialized plugins not yet used are reported as not started
+func (bp *basePlugin) health() error {
+	if atomic.LoadInt32(&bp.started) == 0 {
+		if atomic.LoadInt32(&bp.stopped) == 1 {
+			return errPluginStopped
+		}
+		if bp.pluginDefinition.Lazy {
+			return errPluginNotStarted
+		}
+		return errors.New("plugin is not running")
+	}
+	if bp.client == nil || bp.client.Exited() {
+		return errors.New("plugin process exited")
+	}
+	rpcClient, err := bp.client.Client()
+	if err != nil {
+		return err
+	}
+	return rpcClient.Ping()
+}
+
 func (bp *basePlugin) cleanPluginWorkspace() error {
 	workspace, err := os.Open(bp.pluginWorkspace)
 	if err != nil {
@@ -170,7 +314,7 @@ func (bp *basePlugin) cleanPluginWorkspace() error {
 
 func (bp *basePlugin) init() error {
 	bp.logger.Info("Initializing plugin")
-	raw, err := bp.dispense(initializer.ConnectorName)
+	raw, err := bp.dispenseStarted(initializer.ConnectorName)
 	if err != nil {
 		return err
 	}
@@ -185,7 +329,41 @@ func (bp *basePlugin) init() error {
 	return c.Init(context.Background(), bp.pm.nodeName, rawConfig)
 }
 
+// connectEvents lets the plugin subscribe to chain events if it supports it
+func (bp *basePlugin) connectEvents() error {
+	if bp.pm.eventHub == nil {
+		return nil
+	}
+	raw, err := bp.dispenseStarted(events.ConnectorName)
+	if err != nil {
+		return err
+	}
+	c, ok := raw.(events.PluginEventConsumer)
+	if !ok {
+		return fmt.Errorf("missing plugin event consumer. Make sure it is in the plugin set")
+	}
+	if err := c.Connect(context.Background()); err != nil {
+		if rpcStatus, ok := status.FromError(err); ok && rpcStatus.Code() == codes.Unimplemented {
+			bp.logger.Debug("Plugin doesn't consume chain events")
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// dispense returns the gateway to the named plugin service,
+// starting the plugin first if it's lazily initialized
 func (bp *basePlugin) dispense(name string) (interface{}, error) {
+	if err := bp.ensureStarted(); err != nil {
+		return nil, err
+	}
+	return bp.dispenseStarted(name)
+}
+
+// dispenseStarted is the same as dispense but never starts the plugin,
+// it is used while the plugin is being started
+func (bp *basePlugin) dispenseStarted(name string) (interface{}, error) {
 	rpcClient, err := bp.client.Client()
 	if err != nil {
 		return nil, err
@@ -193,12 +371,62 @@ func (bp *basePlugin) dispense(name string) (interface{}, error) {
 	return rpcClient.Dispense(name)
 }
 
+// collectMetrics returns the metrics exposed by the plugin. It fails with gRPC
+// codes.Unimplemented status if the plugin doesn't implement the metrics service
+func (bp *basePlugin) collectMetrics(ctx context.Context) ([]pmetrics.Metric, error) {
+	if bp.client == nil {
+		return nil, fmt.Errorf("plugin is not started")
+	}
+	raw, err := bp.dispenseStarted(pmetrics.ConnectorName)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := raw.(pmetrics.PluginMetrics)
+	if !ok {
+		return nil, fmt.Errorf("missing plugin metrics. Make sure it is in the plugin set")
+	}
+	return c.Collect(ctx)
+}
+
+func (bp *basePlugin) certificateRotator() (certificate.PluginCertificateRotator, error) {
+	raw, err := bp.dispenseStarted(certificate.ConnectorName)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := raw.(certificate.PluginCertificateRotator)
+	if !ok {
+		return nil, fmt.Errorf("missing plugin certificate rotator. Make sure it is in the plugin set")
+	}
+	return c, nil
+}
+
+// rpcAPI returns the gateway to the JSON RPC methods exposed by the plugin
+func (bp *basePlugin) rpcAPI() (rpcapi.PluginRPCAPI, error) {
+	if err := bp.ensureStarted(); err != nil {
+		return nil, err
+	}
+	if bp.client == nil {
+		return nil, fmt.Errorf("plugin is not started")
+	}
+	raw, err := bp.dispense(rpcapi.ConnectorName)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := raw.(rpcapi.PluginRPCAPI)
+	if !ok {
+		return nil, fmt.Errorf("missing plugin RPC API. Make sure it is in the plugin set")
+	}
+	return c, nil
+}
+
 func (bp *basePlugin) Config() *PluginDefinition {
 	return bp.pluginDefinition
 }
 
-func (bp *basePlugin) checksum(pluginFile string) (string, error) {
-	return getSha256Checksum(pluginFile)
+// setLogLevel changes verbosity of logs forwarded from the plugin
+func (bp *basePlugin) setLogLevel(level hclog.Level) {
+	bp.logDelegate.SetLevel(level)
+	bp.logger.Info("plugin log level changed", "level", logLevelString(level))
 }
 
 func (bp *basePlugin) Info() (PluginInterfaceName, interface{}) {
@@ -207,19 +435,47 @@ func (bp *basePlugin) Info() (PluginInterfaceName, interface{}) {
 	info["version"] = bp.pluginDefinition.Version
 	info["config"] = bp.pluginDefinition.Config
 	info["executable"] = bp.commands
+	info["lazy"] = bp.pluginDefinition.Lazy
+	info["started"] = atomic.LoadInt32(&bp.started) == 1
+	info["logLevel"] = logLevelString(bp.logDelegate.GetLevel())
 	return bp.pluginInterface, info
 }
 
+// logDelegate forwards logs from the plugin to the geth logger, dropping those
+// below the plugin log level which can be changed at runtime
 type logDelegate struct {
 	eLogger log.Logger
+	level   *int32 // hclog.Level shared by all loggers derived from the same plugin
+}
+
+func newLogDelegate(logger log.Logger, level hclog.Level) *logDelegate {
+	l := int32(level)
+	return &logDelegate{eLogger: logger, level: &l}
+}
+
+func (ld *logDelegate) enabled(level hclog.Level) bool {
+	return level >= ld.GetLevel()
 }
 
 func (ld *logDelegate) Trace(msg string, args ...interface{}) {
-	ld.eLogger.Trace(msg, args...)
+	if ld.enabled(hclog.Trace) {
+		ld.eLogger.Trace(msg, args...)
+	}
 }
 
 func (ld *logDelegate) Log(level hclog.Level, msg string, args ...interface{}) {
-	//TODO : implement the method
+	switch level {
+	case hclog.Trace:
+		ld.Trace(msg, args...)
+	case hclog.Debug, hclog.NoLevel:
+		ld.Debug(msg, args...)
+	case hclog.Info:
+		ld.Info(msg, args...)
+	case hclog.Warn:
+		ld.Warn(msg, args...)
+	case hclog.Error:
+		ld.Error(msg, args...)
+	}
 }
 
 func (ld *logDelegate) Name() string {
@@ -227,43 +483,51 @@ func (ld *logDelegate) Name() string {
 }
 
 func (ld *logDelegate) Debug(msg string, args ...interface{}) {
-	ld.eLogger.Debug(msg, args...)
+	if ld.enabled(hclog.Debug) {
+		ld.eLogger.Debug(msg, args...)
+	}
 }
 
 func (ld *logDelegate) Info(msg string, args ...interface{}) {
-	ld.eLogger.Info(msg, args...)
+	if ld.enabled(hclog.Info) {
+		ld.eLogger.Info(msg, args...)
+	}
 }
 
 func (ld *logDelegate) Warn(msg string, args ...interface{}) {
-	ld.eLogger.Warn(msg, args...)
+	if ld.enabled(hclog.Warn) {
+		ld.eLogger.Warn(msg, args...)
+	}
 }
 
 func (ld *logDelegate) Error(msg string, args ...interface{}) {
-	ld.eLogger.Error(msg, args...)
+	if ld.enabled(hclog.Error) {
+		ld.eLogger.Error(msg, args...)
+	}
 }
 
 func (ld *logDelegate) IsTrace() bool {
-	return true
+	return ld.enabled(hclog.Trace)
 }
 
-func (*logDelegate) IsDebug() bool {
-	return true
+func (ld *logDelegate) IsDebug() bool {
+	return ld.enabled(hclog.Debug)
 }
 
-func (*logDelegate) IsInfo() bool {
-	return true
+func (ld *logDelegate) IsInfo() bool {
+	return ld.enabled(hclog.Info)
 }
 
-func (*logDelegate) IsWarn() bool {
-	return true
+func (ld *logDelegate) IsWarn() bool {
+	return ld.enabled(hclog.Warn)
 }
 
-func (*logDelegate) IsError() bool {
-	return true
+func (ld *logDelegate) IsError() bool {
+	return ld.enabled(hclog.Error)
 }
 
 func (ld *logDelegate) With(args ...interface{}) hclog.Logger {
-	return &logDelegate{ld.eLogger.New(args...)}
+	return &logDelegate{eLogger: ld.eLogger.New(args...), level: ld.level}
 }
 
 func (ld *logDelegate) Named(name string) hclog.Logger {
@@ -275,17 +539,52 @@ func (ld *logDelegate) ResetNamed(name string) hclog.Logger {
 }
 
 func (ld *logDelegate) SetLevel(level hclog.Level) {
+	atomic.StoreInt32(ld.level, int32(level))
+}
+
+func (ld *logDelegate) GetLevel() hclog.Level {
+	return hclog.Level(atomic.LoadInt32(ld.level))
 }
 
 func (*logDelegate) StandardLogger(opts *hclog.StandardLoggerOptions) *slog.Logger {
 	return nil
 }
 
-func (*logDelegate) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
-	return nil
+func (ld *logDelegate) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	level := hclog.Info
+	if opts != nil && opts.ForceLevel != hclog.NoLevel {
+		level = opts.ForceLevel
+	}
+	return &lineWriter{write: func(line string) {
+		ld.Log(level, line)
+	}}
 }
 
 // ImpliedArgs returns With key/value pairs
 func (*logDelegate) ImpliedArgs() []interface{} {
 	return nil
 }
+
+// lineWriter calls write for every complete line written to it
+type lineWriter struct {
+	mux   sync.Mutex
+	buf   []byte
+	write func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimRight(string(w.buf[:i]), "\r"); len(line) > 0 {
+			w.write(line)
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
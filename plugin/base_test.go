@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRecordingLogger() (log.Logger, *[]*log.Record) {
+	records := make([]*log.Record, 0)
+	logger := log.New()
+	logger.SetHandler(log.FuncHandler(func(r *log.Record) error {
+		records = append(records, r)
+		return nil
+	}))
+	return logger, &records
+}
+
+func TestLogDelegate_filtersByLevel(t *testing.T) {
+	logger, records := newRecordingLogger()
+	testObject := newLogDelegate(logger, hclog.Info)
+	derived := testObject.With("arbitrary", "value")
+
+	derived.Debug("dropped")
+	derived.Info("forwarded")
+	testObject.SetLevel(hclog.Error)
+	derived.Warn("dropped")
+	derived.Log(hclog.Error, "forwarded")
+	testObject.SetLevel(logLevelOff)
+	derived.Error("dropped")
+
+	assert.Len(t, *records, 2)
+	for _, r := range *records {
+		assert.Equal(t, "forwarded", r.Msg)
+	}
+	assert.False(t, derived.IsWarn())
+}
+
+func TestLogDelegate_StandardWriter(t *testing.T) {
+	logger, records := newRecordingLogger()
+	testObject := newLogDelegate(logger, hclog.Trace)
+	w := testObject.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Warn})
+
+	_, _ = fmt.Fprint(w, "first line\nsecond ")
+	_, _ = fmt.Fprint(w, "line\r\n\n")
+
+	assert.Len(t, *records, 2)
+	assert.Equal(t, "first line", (*records)[0].Msg)
+	assert.Equal(t, "second line", (*records)[1].Msg)
+	assert.Equal(t, log.LvlWarn, (*records)[1].Lvl)
+}
+
+func TestParseLogLevel(t *testing.T) {
+	for _, s := range []string{"trace", "debug", "info", "warn", "error", "off"} {
+		level, err := parseLogLevel(s)
+
+		assert.NoError(t, err)
+		assert.Equal(t, s, logLevelString(level))
+	}
+	_, err := parseLogLevel("verbose")
+	assert.Error(t, err)
+}
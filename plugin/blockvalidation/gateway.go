@@ -0,0 +1,35 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type PluginGateway struct {
+	client proto_common.PluginBlockValidationServiceClient
+}
+
+func (g *PluginGateway) Validate(ctx context.Context, block *types.Block, receipts types.Receipts) (bool, string, error) {
+	rawBlock, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return false, "", err
+	}
+	rawReceipts := make([][]byte, len(receipts))
+	for i, r := range receipts {
+		if rawReceipts[i], err = json.Marshal(r); err != nil {
+			return false, "", err
+		}
+	}
+	resp, err := g.client.Validate(ctx, &proto_common.PluginBlockValidation_ValidateRequest{
+		Block:    rawBlock,
+		Receipts: rawReceipts,
+	})
+	if err != nil {
+		return false, "", err
+	}
+	return resp.Valid, resp.Reason, nil
+}
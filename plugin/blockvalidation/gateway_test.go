@@ -0,0 +1,45 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginGateway_Validate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(0), nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody([]*types.Transaction{tx}, nil)
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash(), Logs: []*types.Log{}}
+	rawBlock, _ := rlp.EncodeToBytes(block)
+	rawReceipt, _ := json.Marshal(receipt)
+	mockClient := proto_common.NewMockPluginBlockValidationServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Validate(gomock.Any(), gomock.Eq(&proto_common.PluginBlockValidation_ValidateRequest{
+			Block:    rawBlock,
+			Receipts: [][]byte{rawReceipt},
+		})).
+		Return(&proto_common.PluginBlockValidation_ValidateResponse{
+			Valid:  false,
+			Reason: "arbitrary reason",
+		}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	valid, reason, err := testObject.Validate(context.Background(), block, types.Receipts{receipt})
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, "arbitrary reason", reason)
+}
@@ -0,0 +1,133 @@
+// A sample block validation plugin rejecting blocks which break simple business rules:
+// too many transactions or transactions sent to denied addresses.
+//
+// Example of plugin configuration:
+//
+//	{
+//	  "maxTransactions": 500,
+//	  "deniedAddresses": ["0x0000000000000000000000000000000000000001"]
+//	}
+//
+// To package the plugin, build this program and zip it with a plugin-meta.json file:
+//
+//	{
+//	  "name": "quorum-block-validation-sample",
+//	  "version": "1.0.0",
+//	  "entrypoint": "quorum-block-validation-sample"
+//	}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	iplugin "github.com/ethereum/go-ethereum/internal/plugin"
+	"github.com/ethereum/go-ethereum/plugin/blockvalidation"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/ethereum/go-ethereum/plugin/initializer"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+type config struct {
+	// 0 means unlimited
+	MaxTransactions int              `json:"maxTransactions"`
+	DeniedAddresses []common.Address `json:"deniedAddresses"`
+}
+
+type validator struct {
+	mux    sync.RWMutex
+	config *config
+	denied map[common.Address]bool
+}
+
+func (v *validator) Init(_ context.Context, req *proto_common.PluginInitialization_Request) (*proto_common.PluginInitialization_Response, error) {
+	cfg := new(config)
+	if len(req.RawConfiguration) > 0 {
+		if err := json.Unmarshal(req.RawConfiguration, cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration: %v", err)
+		}
+	}
+	denied := make(map[common.Address]bool, len(cfg.DeniedAddresses))
+	for _, a := range cfg.DeniedAddresses {
+		denied[a] = true
+	}
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	v.config, v.denied = cfg, denied
+	return &proto_common.PluginInitialization_Response{}, nil
+}
+
+func (v *validator) Validate(_ context.Context, req *proto_common.PluginBlockValidation_ValidateRequest) (*proto_common.PluginBlockValidation_ValidateResponse, error) {
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(req.Block, block); err != nil {
+		return nil, fmt.Errorf("invalid block: %v", err)
+	}
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+	if v.config == nil {
+		return nil, fmt.Errorf("plugin is not initialized")
+	}
+	if reason := v.check(block); reason != "" {
+		return &proto_common.PluginBlockValidation_ValidateResponse{Valid: false, Reason: reason}, nil
+	}
+	return &proto_common.PluginBlockValidation_ValidateResponse{Valid: true}, nil
+}
+
+// check returns the reason if the block breaks any rule
+func (v *validator) check(block *types.Block) string {
+	if max := v.config.MaxTransactions; max > 0 && len(block.Transactions()) > max {
+		return fmt.Sprintf("%d transactions exceed the limit of %d", len(block.Transactions()), max)
+	}
+	for _, tx := range block.Transactions() {
+		if tx.To() != nil && v.denied[*tx.To()] {
+			return fmt.Sprintf("transaction %s is sent to denied address %s", tx.Hash().Hex(), tx.To().Hex())
+		}
+	}
+	return ""
+}
+
+type initializerConnector struct {
+	plugin.Plugin
+	server proto_common.PluginInitializerServer
+}
+
+func (c *initializerConnector) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	proto_common.RegisterPluginInitializerServer(s, c.server)
+	return nil
+}
+
+func (c *initializerConnector) GRPCClient(context.Context, *plugin.GRPCBroker, *grpc.ClientConn) (interface{}, error) {
+	return nil, iplugin.ErrNotSupported
+}
+
+type validatorConnector struct {
+	plugin.Plugin
+	server proto_common.PluginBlockValidationServiceServer
+}
+
+func (c *validatorConnector) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	proto_common.RegisterPluginBlockValidationServiceServer(s, c.server)
+	return nil
+}
+
+func (c *validatorConnector) GRPCClient(context.Context, *plugin.GRPCBroker, *grpc.ClientConn) (interface{}, error) {
+	return nil, iplugin.ErrNotSupported
+}
+
+func main() {
+	v := new(validator)
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: iplugin.DefaultHandshakeConfig,
+		Plugins: map[string]plugin.Plugin{
+			initializer.ConnectorName:     &initializerConnector{server: v},
+			blockvalidation.ConnectorName: &validatorConnector{server: v},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
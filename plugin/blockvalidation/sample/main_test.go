@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBlock(t *testing.T, to ...common.Address) []byte {
+	txs := make([]*types.Transaction, len(to))
+	for i, a := range to {
+		txs[i] = types.NewTransaction(uint64(i), a, big.NewInt(0), 21000, big.NewInt(0), nil)
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(txs, nil)
+	raw, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestValidator_Validate(t *testing.T) {
+	assert := assert.New(t)
+	testObject := new(validator)
+	_, err := testObject.Init(context.Background(), &proto_common.PluginInitialization_Request{
+		RawConfiguration: []byte(`{"maxTransactions": 2, "deniedAddresses": ["0x0000000000000000000000000000000000000009"]}`),
+	})
+	assert.NoError(err)
+
+	testCases := []struct {
+		to     []common.Address
+		valid  bool
+		reason string
+	}{
+		{[]common.Address{{1}, {2}}, true, ""},
+		{[]common.Address{{1}, {2}, {3}}, false, "3 transactions exceed the limit of 2"},
+		{[]common.Address{common.HexToAddress("0x09")}, false, "is sent to denied address 0x0000000000000000000000000000000000000009"},
+	}
+	for _, tc := range testCases {
+		resp, err := testObject.Validate(context.Background(), &proto_common.PluginBlockValidation_ValidateRequest{
+			Block: newBlock(t, tc.to...),
+		})
+
+		assert.NoError(err)
+		assert.Equal(tc.valid, resp.Valid)
+		assert.Contains(resp.Reason, tc.reason)
+	}
+}
+
+func TestValidator_Validate_whenNotInitialized(t *testing.T) {
+	_, err := new(validator).Validate(context.Background(), &proto_common.PluginBlockValidation_ValidateRequest{
+		Block: newBlock(t),
+	})
+
+	assert.EqualError(t, err, "plugin is not initialized")
+}
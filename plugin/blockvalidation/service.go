@@ -0,0 +1,27 @@
+package blockvalidation
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PluginBlockValidator validates blocks produced by this node before they are sealed
+type PluginBlockValidator interface {
+	// Validate returns true if the block is valid, otherwise a reason for the rejection
+	Validate(ctx context.Context, block *types.Block, receipts types.Receipts) (bool, string, error)
+}
+
+type PluginBlockValidatorDeferFunc func() (PluginBlockValidator, error)
+
+type ReloadablePluginBlockValidator struct {
+	DeferFunc PluginBlockValidatorDeferFunc
+}
+
+func (d *ReloadablePluginBlockValidator) Validate(ctx context.Context, block *types.Block, receipts types.Receipts) (bool, string, error) {
+	p, err := d.DeferFunc()
+	if err != nil {
+		return false, "", err
+	}
+	return p.Validate(ctx, block, receipts)
+}
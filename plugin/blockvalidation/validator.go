@@ -0,0 +1,29 @@
+package blockvalidation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const validateTimeout = 5 * time.Second
+
+// NewLocalBlockValidator returns a validator vetoing blocks rejected by the plugin.
+// Blocks are also rejected if the plugin fails to respond in time
+func NewLocalBlockValidator(p PluginBlockValidator) core.LocalBlockValidator {
+	return func(block *types.Block, receipts types.Receipts) error {
+		ctx, cancel := context.WithTimeout(context.Background(), validateTimeout)
+		defer cancel()
+		valid, reason, err := p.Validate(ctx, block, receipts)
+		if err != nil {
+			return fmt.Errorf("block validation plugin failed: %v", err)
+		}
+		if !valid {
+			return fmt.Errorf("block rejected by validation plugin: %s", reason)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,31 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubPluginBlockValidator struct {
+	valid  bool
+	reason string
+	err    error
+}
+
+func (s *stubPluginBlockValidator) Validate(_ context.Context, _ *types.Block, _ types.Receipts) (bool, string, error) {
+	return s.valid, s.reason, s.err
+}
+
+func TestNewLocalBlockValidator(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+
+	assert.NoError(t, NewLocalBlockValidator(&stubPluginBlockValidator{valid: true})(block, nil))
+	assert.EqualError(t, NewLocalBlockValidator(&stubPluginBlockValidator{reason: "arbitrary reason"})(block, nil),
+		"block rejected by validation plugin: arbitrary reason")
+	assert.EqualError(t, NewLocalBlockValidator(&stubPluginBlockValidator{valid: true, err: errors.New("arbitrary error")})(block, nil),
+		"block validation plugin failed: arbitrary error")
+}
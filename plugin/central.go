@@ -2,7 +2,10 @@ package plugin
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"text/template"
@@ -22,12 +26,14 @@ import (
 type CentralClient struct {
 	config     *PluginCentralConfiguration
 	httpClient *http.Client
+	retryDelay time.Duration
 }
 
 // Create New Central Client
 func NewPluginCentralClient(config *PluginCentralConfiguration) *CentralClient {
 	c := &CentralClient{
-		config: config,
+		config:     config,
+		retryDelay: time.Second,
 	}
 	c.httpClient = &http.Client{}
 	c.httpClient.Transport = &http.Transport{
@@ -86,42 +92,101 @@ func (cc *CentralClient) PluginSignature(definition *PluginDefinition) ([]byte,
 	return buf.Bytes(), nil
 }
 
-// retrieve plugin distribution file
-func (cc *CentralClient) PluginDistribution(definition *PluginDefinition, outFilePath string) error {
+// retrieve plugin distribution file and return its hex encoded sha256 checksum.
+//
+// Content is streamed into a partial file next to outFilePath and hashed as it arrives.
+// A failed transfer is resumed from the partial file using HTTP range requests, so only
+// the missing bytes are requested again. outFilePath only appears once the download
+// has completed successfully.
+func (cc *CentralClient) PluginDistribution(definition *PluginDefinition, outFilePath string) (string, error) {
 	target, err := cc.toURLFromTemplate(cc.config.PluginDistPathTemplate, definition)
 	if err != nil {
-		return err
+		return "", err
 	}
 	log.Debug("downloading plugin zip file", "url", target)
-	outFile, err := os.Create(outFilePath)
+	partialFilePath := outFilePath + partialFileSuffix
+	attempts := cc.config.MaxDownloadAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		var checksum string
+		checksum, err = cc.downloadResumable(target, partialFilePath)
+		if err == nil {
+			return checksum, os.Rename(partialFilePath, outFilePath)
+		}
+		if _, ok := err.(*permanentDownloadError); ok || attempt >= attempts {
+			return "", err
+		}
+		log.Warn("plugin download interrupted, resuming", "url", target, "attempt", attempt, "err", err)
+		time.Sleep(time.Duration(attempt) * cc.retryDelay)
+	}
+}
+
+// retrieve available versions of a plugin from the Maven metadata file
+func (cc *CentralClient) PluginVersions(name string) ([]Version, error) {
+	target, err := cc.toURLFromTemplate(cc.config.PluginMetadataPathTemplate, &PluginDefinition{Name: name})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		_ = outFile.Close()
-	}()
-	return cc.download(target, outFile)
+	log.Debug("downloading plugin metadata file", "url", target)
+	buf := new(bytes.Buffer)
+	if err := cc.download(target, buf); err != nil {
+		return nil, err
+	}
+	var metadata struct {
+		Versions []string `xml:"versioning>versions>version"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &metadata); err != nil {
+		return nil, fmt.Errorf("invalid plugin metadata: %v", err)
+	}
+	versions := make([]Version, 0, len(metadata.Versions))
+	for _, v := range metadata.Versions {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			versions = append(versions, Version(v))
+		}
+	}
+	return versions, nil
 }
 
 // perform HTTP GET
 //
 // caller needs to close the reader
 func (cc *CentralClient) get(target string) (io.ReadCloser, error) {
+	res, err := cc.getRange(target, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// perform HTTP GET requesting content starting from offset if it's greater than 0.
+// The server may ignore the range and respond with the full content.
+//
+// caller needs to close the response body
+func (cc *CentralClient) getRange(target string, offset int64) (*http.Response, error) {
 	if err := isValidTargetURL(cc.config.BaseURL, target); err != nil {
 		return nil, err
 	}
-	res, err := cc.httpClient.Get(target)
+	req, err := http.NewRequest(http.MethodGet, target, nil)
 	if err != nil {
 		return nil, err
 	}
-	if res.StatusCode != http.StatusOK {
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	res, err := cc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK && (offset == 0 || res.StatusCode != http.StatusPartialContent) {
 		defer func() {
 			_ = res.Body.Close()
 		}()
 		data, _ := ioutil.ReadAll(res.Body)
-		return nil, fmt.Errorf("HTTP GET error: code=%d, status=%s, body=%s", res.StatusCode, res.Status, string(data))
+		return nil, &httpError{code: res.StatusCode, status: res.Status, body: string(data)}
 	}
-	return res.Body, nil
+	return res, nil
 }
 
 // return full URL using config.BaseURL
@@ -177,6 +242,121 @@ func (cc *CentralClient) download(target string, out io.Writer) (err error) {
 	return err
 }
 
+// download target into partialFilePath, continuing from the content already in the file.
+// It returns hex encoded sha256 checksum of the complete file.
+func (cc *CentralClient) downloadResumable(target string, partialFilePath string) (checksum string, err error) {
+	defer func(start time.Time) {
+		log.Debug("download completed", "url", target, "err", err, "took", time.Since(start))
+	}(time.Now())
+	outFile, err := os.OpenFile(partialFilePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return "", &permanentDownloadError{err}
+	}
+	defer func() {
+		_ = outFile.Close()
+	}()
+	// hash what has been downloaded previously so the checksum covers the whole file
+	hash := sha256.New()
+	offset, err := io.Copy(hash, outFile)
+	if err != nil {
+		return "", &permanentDownloadError{err}
+	}
+	res, err := cc.getRange(target, offset)
+	if err != nil {
+		if httpErr, ok := err.(*httpError); ok {
+			if httpErr.code == http.StatusRequestedRangeNotSatisfiable {
+				// partial file doesn't match the remote one, start over
+				log.Debug("discarding partial download", "path", partialFilePath, "size", offset)
+				if err := outFile.Truncate(0); err != nil {
+					return "", &permanentDownloadError{err}
+				}
+				return "", err
+			}
+			if httpErr.code >= 400 && httpErr.code < 500 {
+				return "", &permanentDownloadError{err}
+			}
+		}
+		return "", err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	total := res.ContentLength
+	if res.StatusCode == http.StatusPartialContent {
+		start, size, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			_ = outFile.Truncate(0)
+			return "", fmt.Errorf("unexpected Content-Range %q for offset %d", res.Header.Get("Content-Range"), offset)
+		}
+		log.Debug("resuming plugin download", "url", target, "offset", offset)
+		if size >= 0 {
+			total = size
+		}
+	} else {
+		// full content is returned, start over
+		if err := outFile.Truncate(0); err != nil {
+			return "", &permanentDownloadError{err}
+		}
+		if _, err := outFile.Seek(0, io.SeekStart); err != nil {
+			return "", &permanentDownloadError{err}
+		}
+		hash.Reset()
+		offset = 0
+	}
+	var remaining int64 = -1
+	if total >= 0 {
+		remaining = total - offset
+		if err := ensureDiskSpace(filepath.Dir(partialFilePath), remaining); err != nil {
+			return "", &permanentDownloadError{err}
+		}
+	}
+	// the file write happens before hashing so both always cover the same bytes
+	written, err := io.Copy(io.MultiWriter(outFile, hash), res.Body)
+	if err != nil {
+		return "", err
+	}
+	if remaining >= 0 && written != remaining {
+		return "", fmt.Errorf("incomplete download: expected %d bytes but received %d", remaining, written)
+	}
+	if err := outFile.Sync(); err != nil {
+		return "", &permanentDownloadError{err}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// parse Content-Range header value in the format of "bytes <start>-<end>/<size>"
+// size is -1 if it's unknown
+func parseContentRange(value string) (start int64, size int64, err error) {
+	var end int64
+	if strings.HasSuffix(value, "/*") {
+		size = -1
+		_, err = fmt.Sscanf(value, "bytes %d-%d/*", &start, &end)
+	} else {
+		_, err = fmt.Sscanf(value, "bytes %d-%d/%d", &start, &end, &size)
+	}
+	return
+}
+
+// returned when the remote server responds with an unexpected HTTP status code
+type httpError struct {
+	code   int
+	status string
+	body   string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("HTTP GET error: code=%d, status=%s, body=%s", e.code, e.status, e.body)
+}
+
+// returned when retrying the download would not help
+type permanentDownloadError struct {
+	err error
+}
+
+func (e *permanentDownloadError) Error() string {
+	return e.err.Error()
+}
+
 // An adapter function for tls.Dial with CA verification & SSL Pinning support.
 type Dialer func(network, addr string) (net.Conn, error)
 
@@ -2,16 +2,21 @@ package plugin
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -117,9 +122,165 @@ func TestCentralClient_PluginDistribution(t *testing.T) {
 
 	testObject := NewPluginCentralClient(arbitraryConfig)
 
-	err = testObject.PluginDistribution(arbitraryDef, path.Join(tmpDir, "download.zip"))
+	actualChecksum, err := testObject.PluginDistribution(arbitraryDef, path.Join(tmpDir, "download.zip"))
 
 	assert.NoError(t, err)
+	assert.Equal(t, sha256Hex(arbitraryData), actualChecksum)
+	actualData, err := ioutil.ReadFile(path.Join(tmpDir, "download.zip"))
+	assert.NoError(t, err)
+	assert.Equal(t, arbitraryData, actualData)
+	assert.False(t, common.FileExist(path.Join(tmpDir, "download.zip"+partialFileSuffix)))
+}
+
+func TestCentralClient_PluginDistribution_resumesPartialDownload(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	arbitraryData := []byte("arbitrary data which has been partially downloaded")
+	outFilePath := path.Join(tmpDir, "download.zip")
+	if err := ioutil.WriteFile(outFilePath+partialFileSuffix, arbitraryData[:10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	var ranges []string
+	arbitraryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ranges = append(ranges, req.Header.Get("Range"))
+		http.ServeContent(w, req, "download.zip", time.Time{}, bytes.NewReader(arbitraryData))
+	}))
+	defer arbitraryServer.Close()
+	arbitraryConfig := &PluginCentralConfiguration{
+		BaseURL: arbitraryServer.URL,
+	}
+	arbitraryConfig.SetDefaults()
+
+	testObject := NewPluginCentralClient(arbitraryConfig)
+
+	actualChecksum, err := testObject.PluginDistribution(&PluginDefinition{Name: "arbitrary-plugin", Version: "1.0.0"}, outFilePath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bytes=10-"}, ranges)
+	assert.Equal(t, sha256Hex(arbitraryData), actualChecksum)
+	actualData, err := ioutil.ReadFile(outFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, arbitraryData, actualData)
+}
+
+func TestCentralClient_PluginDistribution_resumesAfterInterruption(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	arbitraryData := []byte("arbitrary data which is interrupted in the middle of the transfer")
+	var ranges []string
+	arbitraryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ranges = append(ranges, req.Header.Get("Range"))
+		if len(ranges) == 1 {
+			// promise the full content but only send half of it
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(arbitraryData)))
+			_, _ = w.Write(arbitraryData[:len(arbitraryData)/2])
+			return
+		}
+		http.ServeContent(w, req, "download.zip", time.Time{}, bytes.NewReader(arbitraryData))
+	}))
+	defer arbitraryServer.Close()
+	arbitraryConfig := &PluginCentralConfiguration{
+		BaseURL: arbitraryServer.URL,
+	}
+	arbitraryConfig.SetDefaults()
+
+	testObject := NewPluginCentralClient(arbitraryConfig)
+	testObject.retryDelay = time.Millisecond
+
+	actualChecksum, err := testObject.PluginDistribution(&PluginDefinition{Name: "arbitrary-plugin", Version: "1.0.0"}, path.Join(tmpDir, "download.zip"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", len(arbitraryData)/2)}, ranges)
+	assert.Equal(t, sha256Hex(arbitraryData), actualChecksum)
+}
+
+func TestCentralClient_PluginDistribution_whenNotFound(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	requestCount := 0
+	arbitraryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestCount++
+		http.NotFound(w, req)
+	}))
+	defer arbitraryServer.Close()
+	arbitraryConfig := &PluginCentralConfiguration{
+		BaseURL: arbitraryServer.URL,
+	}
+	arbitraryConfig.SetDefaults()
+
+	testObject := NewPluginCentralClient(arbitraryConfig)
+
+	_, err = testObject.PluginDistribution(&PluginDefinition{Name: "arbitrary-plugin", Version: "1.0.0"}, path.Join(tmpDir, "download.zip"))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, requestCount, "client errors must not be retried")
+	assert.False(t, common.FileExist(path.Join(tmpDir, "download.zip")))
+}
+
+func TestEnsureDiskSpace(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	assert.NoError(t, ensureDiskSpace(tmpDir, 1))
+	if free, _ := freeDiskSpace(tmpDir); free >= 0 {
+		assert.Error(t, ensureDiskSpace(tmpDir, math.MaxInt64))
+	}
+}
+
+func TestCentralClient_PluginVersions(t *testing.T) {
+	arbitraryMetadata := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.quorum.plugin</groupId>
+  <artifactId>arbitrary-plugin</artifactId>
+  <versioning>
+    <latest>1.1.0</latest>
+    <release>1.1.0</release>
+    <versions>
+      <version>1.0.0</version>
+      <version>1.1.0</version>
+    </versions>
+  </versioning>
+</metadata>`)
+	arbitraryServer := newTestServer("/maven/bin/arbitrary-plugin/maven-metadata.xml", arbitraryMetadata)
+	defer arbitraryServer.Close()
+	arbitraryConfig := &PluginCentralConfiguration{
+		BaseURL:                    arbitraryServer.URL,
+		PluginMetadataPathTemplate: "maven/bin/{{.Name}}/maven-metadata.xml",
+	}
+
+	testObject := NewPluginCentralClient(arbitraryConfig)
+
+	actualValue, err := testObject.PluginVersions("arbitrary-plugin")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Version{"1.0.0", "1.1.0"}, actualValue)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
 }
 
 func newTestServer(pattern string, returnedData []byte) *httptest.Server {
@@ -0,0 +1,19 @@
+package certificate
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+type PluginGateway struct {
+	client proto_common.PluginCertificateServiceClient
+}
+
+func (g *PluginGateway) Rotate(ctx context.Context, certPEM []byte, keyPEM []byte) error {
+	_, err := g.client.Rotate(ctx, &proto_common.PluginCertificate_RotateRequest{
+		Certificate: certPEM,
+		PrivateKey:  keyPEM,
+	})
+	return err
+}
@@ -0,0 +1,31 @@
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginGateway_Rotate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &proto_common.PluginCertificate_RotateRequest{
+		Certificate: []byte("arbitrary cert"),
+		PrivateKey:  []byte("arbitrary key"),
+	}
+	mockClient := proto_common.NewMockPluginCertificateServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Rotate(gomock.Any(), gomock.Eq(req)).
+		Return(&proto_common.PluginCertificate_RotateResponse{}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	err := testObject.Rotate(context.Background(), []byte("arbitrary cert"), []byte("arbitrary key"))
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,10 @@
+package certificate
+
+import "context"
+
+// PluginCertificateRotator is implemented by plugins which support
+// certificates managed by geth
+type PluginCertificateRotator interface {
+	// Rotate replaces the certificate the plugin presents to geth
+	Rotate(ctx context.Context, certPEM []byte, keyPEM []byte) error
+}
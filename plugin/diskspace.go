@@ -0,0 +1,16 @@
+package plugin
+
+import "fmt"
+
+// ensure the filesystem containing dir has room for size more bytes.
+// The check is skipped if free space can't be determined on the platform.
+func ensureDiskSpace(dir string, size int64) error {
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return fmt.Errorf("unable to determine free disk space in %s: %v", dir, err)
+	}
+	if free >= 0 && uint64(size) > uint64(free) {
+		return fmt.Errorf("insufficient disk space in %s: %d bytes required but only %d bytes available", dir, size, free)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+// +build !linux,!darwin,!freebsd,!windows
+
+package plugin
+
+// free disk space is unknown on this platform
+func freeDiskSpace(dir string) (int64, error) {
+	return -1, nil
+}
@@ -0,0 +1,14 @@
+// +build linux darwin freebsd
+
+package plugin
+
+import "golang.org/x/sys/unix"
+
+// return number of bytes available to unprivileged users in the filesystem containing dir
+func freeDiskSpace(dir string) (int64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return int64(uint64(stat.Bavail) * uint64(stat.Bsize)), nil
+}
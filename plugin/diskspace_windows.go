@@ -0,0 +1,18 @@
+// +build windows
+
+package plugin
+
+import "golang.org/x/sys/windows"
+
+// return number of bytes available to the current user in the volume containing dir
+func freeDiskSpace(dir string) (int64, error) {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if err := windows.GetDiskFreeSpaceEx(dirPtr, &free, nil, nil); err != nil {
+		return 0, err
+	}
+	return int64(free), nil
+}
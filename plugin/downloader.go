@@ -8,6 +8,9 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// suffix of the file holding an incomplete plugin download which is resumed on the next attempt
+const partialFileSuffix = ".part"
+
 // get plugin zip file from local or remote
 type Downloader struct {
 	pm *PluginManager
@@ -19,16 +22,24 @@ func NewDownloader(pm *PluginManager) *Downloader {
 	}
 }
 
-func (d *Downloader) Download(definition *PluginDefinition) (string, error) {
+// Download returns the path to the plugin zip file together with its hex encoded sha256 checksum.
+// When downloading from Plugin Central, the checksum is calculated while the content is streamed
+// so the file doesn't need to be read again.
+func (d *Downloader) Download(definition *PluginDefinition) (string, string, error) {
 	// check if plugin is already in the local
 	pluginFile := path.Join(d.pm.pluginBaseDir, definition.DistFileName())
 	exist := common.FileExist(pluginFile)
 	log.Debug("checking plugin zip file", "path", pluginFile, "exist", exist)
 	if exist {
-		return pluginFile, nil
+		checksum, err := getSha256Checksum(pluginFile)
+		if err != nil {
+			return "", "", err
+		}
+		return pluginFile, checksum, nil
 	}
-	if err := d.pm.centralClient.PluginDistribution(definition, pluginFile); err != nil {
-		return "", fmt.Errorf("can't download from Plugin Central due to: %s. Please download the plugin manually and copy it to %s", err, d.pm.pluginBaseDir)
+	checksum, err := d.pm.centralClient.PluginDistribution(definition, pluginFile)
+	if err != nil {
+		return "", "", fmt.Errorf("can't download from Plugin Central due to: %s. Please download the plugin manually and copy it to %s", err, d.pm.pluginBaseDir)
 	}
-	return pluginFile, nil
+	return pluginFile, checksum, nil
 }
@@ -28,11 +28,12 @@ func TestDownloader_Download_whenPluginIsAvailableLocally(t *testing.T) {
 	}, false, false, "")
 	testObject := NewDownloader(arbitraryPm)
 
-	actualPath, err := testObject.Download(&PluginDefinition{
+	actualPath, actualChecksum, err := testObject.Download(&PluginDefinition{
 		Name:    "arbitrary-plugin",
 		Version: "1.0.0",
 	})
 
 	assert.NoError(t, err)
 	assert.Equal(t, arbitraryPluginDistPath, actualPath)
+	assert.Equal(t, sha256Hex([]byte{}), actualChecksum)
 }
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"google.golang.org/grpc"
+)
+
+// broker is the subset of the plugin library connection broker used to serve the event service
+type broker interface {
+	NextId() uint32
+	AcceptAndServe(id uint32, s func([]grpc.ServerOption) *grpc.Server)
+}
+
+type PluginGateway struct {
+	client proto_common.PluginEventConsumerServiceClient
+	broker broker
+	hub    *Hub
+}
+
+func (g *PluginGateway) Connect(ctx context.Context) error {
+	if g.hub == nil {
+		return fmt.Errorf("event service is not available")
+	}
+	id := g.broker.NextId()
+	if _, err := g.client.Connect(ctx, &proto_common.PluginEvents_ConnectRequest{
+		BrokerId: id,
+	}); err != nil {
+		return err
+	}
+	// the plugin dials asynchronously, the broker holds the connection attempt until accepted
+	go g.broker.AcceptAndServe(id, func(opts []grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		proto_common.RegisterPluginEventServiceServer(s, &eventServer{hub: g.hub})
+		return s
+	})
+	return nil
+}
+
+// eventServer streams events from the hub to the plugin
+type eventServer struct {
+	hub *Hub
+}
+
+func (s *eventServer) Subscribe(req *proto_common.PluginEvents_SubscribeRequest, stream proto_common.PluginEventService_SubscribeServer) error {
+	types := make(map[proto_common.PluginEvents_Type]bool)
+	for _, t := range req.Types {
+		types[t] = true
+	}
+	sub := s.hub.subscribe()
+	defer s.hub.unsubscribe(sub)
+	for {
+		select {
+		case ev, ok := <-sub:
+			if !ok {
+				return nil
+			}
+			if len(types) > 0 && !types[ev.Type] {
+				continue
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
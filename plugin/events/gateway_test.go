@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type stubBroker struct {
+	id     uint32
+	served chan uint32
+}
+
+func (b *stubBroker) NextId() uint32 {
+	return b.id
+}
+
+func (b *stubBroker) AcceptAndServe(id uint32, s func([]grpc.ServerOption) *grpc.Server) {
+	b.served <- id
+}
+
+func TestPluginGateway_Connect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := proto_common.NewMockPluginEventConsumerServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Connect(gomock.Any(), gomock.Eq(&proto_common.PluginEvents_ConnectRequest{BrokerId: 7})).
+		Return(&proto_common.PluginEvents_ConnectResponse{}, nil)
+	broker := &stubBroker{id: 7, served: make(chan uint32, 1)}
+
+	testObject := &PluginGateway{client: mockClient, broker: broker, hub: NewHub()}
+
+	err := testObject.Connect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), <-broker.served)
+}
+
+func TestPluginGateway_Connect_whenPluginFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := proto_common.NewMockPluginEventConsumerServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Connect(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("arbitrary error"))
+	broker := &stubBroker{id: 7, served: make(chan uint32, 1)}
+
+	testObject := &PluginGateway{client: mockClient, broker: broker, hub: NewHub()}
+
+	err := testObject.Connect(context.Background())
+
+	assert.EqualError(t, err, "arbitrary error")
+	assert.Len(t, broker.served, 0)
+}
+
+func TestEventServer_Subscribe_whenFilteringTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan *proto_common.PluginEvents_Event, 1)
+	mockStream := proto_common.NewMockPluginEventService_SubscribeServer(ctrl)
+	mockStream.EXPECT().Context().Return(ctx).AnyTimes()
+	mockStream.EXPECT().Send(gomock.Any()).DoAndReturn(func(ev *proto_common.PluginEvents_Event) error {
+		received <- ev
+		return nil
+	})
+	testObject := &eventServer{hub: hub}
+
+	done := make(chan error)
+	go func() {
+		done <- testObject.Subscribe(&proto_common.PluginEvents_SubscribeRequest{
+			Types: []proto_common.PluginEvents_Type{proto_common.PluginEvents_TX_POOL},
+		}, mockStream)
+	}()
+	for subscriberCount(hub) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.send(&proto_common.PluginEvents_Event{Type: proto_common.PluginEvents_CHAIN_HEAD})
+	hub.send(&proto_common.PluginEvents_Event{Type: proto_common.PluginEvents_TX_POOL})
+
+	assert.Equal(t, proto_common.PluginEvents_TX_POOL, (<-received).Type)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Equal(t, 0, subscriberCount(hub))
+}
+
+func subscriberCount(h *Hub) int {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return len(h.subscribers)
+}
@@ -0,0 +1,167 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+const (
+	sourceChanSize     = 64
+	subscriberChanSize = 256
+)
+
+// Backend provides the chain and transaction pool events
+type Backend interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription
+}
+
+// Hub fans out chain head, transaction pool and permission events to plugin subscribers.
+// Events are dropped for subscribers which don't keep up so that plugins never slow down
+// block processing
+type Hub struct {
+	mux         sync.Mutex
+	subscribers map[chan *proto_common.PluginEvents_Event]struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[chan *proto_common.PluginEvents_Event]struct{}),
+	}
+}
+
+// Start following events from the backend and permissioning
+func (h *Hub) Start(b Backend) {
+	h.quit = make(chan struct{})
+	chainHeadCh := make(chan core.ChainHeadEvent, sourceChanSize)
+	txsCh := make(chan core.NewTxsEvent, sourceChanSize)
+	permissionCh := make(chan pcore.PermissionEvent, sourceChanSize)
+	subs := []event.Subscription{
+		b.SubscribeChainHeadEvent(chainHeadCh),
+		b.SubscribeNewTxsEvent(txsCh),
+		pcore.SubscribePermissionEvent(permissionCh),
+	}
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer func() {
+			for _, s := range subs {
+				s.Unsubscribe()
+			}
+		}()
+		for {
+			select {
+			case ev := <-chainHeadCh:
+				h.send(chainHeadEvent(ev.Block))
+			case ev := <-txsCh:
+				h.send(txPoolEvent(ev.Txs))
+			case ev := <-permissionCh:
+				if e, err := permissionEvent(ev); err != nil {
+					log.Warn("Unable to encode permission event", "kind", ev.Kind, "err", err)
+				} else {
+					h.send(e)
+				}
+			case <-h.quit:
+				return
+			}
+		}
+	}()
+}
+
+func (h *Hub) Stop() {
+	if h.quit == nil {
+		return
+	}
+	close(h.quit)
+	h.wg.Wait()
+	h.quit = nil
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for ch := range h.subscribers {
+		close(ch)
+		delete(h.subscribers, ch)
+	}
+}
+
+func (h *Hub) subscribe() chan *proto_common.PluginEvents_Event {
+	ch := make(chan *proto_common.PluginEvents_Event, subscriberChanSize)
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (h *Hub) unsubscribe(ch chan *proto_common.PluginEvents_Event) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if _, ok := h.subscribers[ch]; ok {
+		close(ch)
+		delete(h.subscribers, ch)
+	}
+}
+
+func (h *Hub) send(ev *proto_common.PluginEvents_Event) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			log.Warn("Plugin event subscriber is too slow, event dropped", "type", ev.Type)
+		}
+	}
+}
+
+func chainHeadEvent(block *types.Block) *proto_common.PluginEvents_Event {
+	return &proto_common.PluginEvents_Event{
+		Type: proto_common.PluginEvents_CHAIN_HEAD,
+		ChainHead: &proto_common.PluginEvents_ChainHead{
+			Number:     block.NumberU64(),
+			Hash:       block.Hash().Hex(),
+			ParentHash: block.ParentHash().Hex(),
+			Timestamp:  block.Time(),
+			TxHashes:   txHashes(block.Transactions()),
+		},
+	}
+}
+
+func txPoolEvent(txs []*types.Transaction) *proto_common.PluginEvents_Event {
+	return &proto_common.PluginEvents_Event{
+		Type: proto_common.PluginEvents_TX_POOL,
+		TxPool: &proto_common.PluginEvents_TxPool{
+			TxHashes: txHashes(txs),
+		},
+	}
+}
+
+func permissionEvent(ev pcore.PermissionEvent) (*proto_common.PluginEvents_Event, error) {
+	payload, err := json.Marshal(ev.Info)
+	if err != nil {
+		return nil, err
+	}
+	return &proto_common.PluginEvents_Event{
+		Type: proto_common.PluginEvents_PERMISSION,
+		Permission: &proto_common.PluginEvents_Permission{
+			Kind:    ev.Kind,
+			Payload: payload,
+		},
+	}, nil
+}
+
+func txHashes(txs []*types.Transaction) []string {
+	hashes := make([]string, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash().Hex()
+	}
+	return hashes
+}
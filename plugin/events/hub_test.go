@@ -0,0 +1,80 @@
+package events
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubBackend struct {
+	chainHeadFeed event.Feed
+	txsFeed       event.Feed
+}
+
+func (b *stubBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.chainHeadFeed.Subscribe(ch)
+}
+
+func (b *stubBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txsFeed.Subscribe(ch)
+}
+
+func TestHub_whenTypical(t *testing.T) {
+	assert := assert.New(t)
+	backend := &stubBackend{}
+	testObject := NewHub()
+	sub := testObject.subscribe()
+	testObject.Start(backend)
+	defer testObject.Stop()
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(0), nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10), Time: 1000}).WithBody([]*types.Transaction{tx}, nil)
+	backend.chainHeadFeed.Send(core.ChainHeadEvent{Block: block})
+
+	ev := <-sub
+	assert.Equal(proto_common.PluginEvents_CHAIN_HEAD, ev.Type)
+	assert.Equal(uint64(10), ev.ChainHead.Number)
+	assert.Equal(block.Hash().Hex(), ev.ChainHead.Hash)
+	assert.Equal(uint64(1000), ev.ChainHead.Timestamp)
+	assert.Equal([]string{tx.Hash().Hex()}, ev.ChainHead.TxHashes)
+
+	backend.txsFeed.Send(core.NewTxsEvent{Txs: []*types.Transaction{tx}})
+
+	ev = <-sub
+	assert.Equal(proto_common.PluginEvents_TX_POOL, ev.Type)
+	assert.Equal([]string{tx.Hash().Hex()}, ev.TxPool.TxHashes)
+}
+
+func TestPermissionEvent(t *testing.T) {
+	info := &pcore.NodeInfo{OrgId: "ORG1", Url: "enode://arbitrary", Status: pcore.NodeApproved}
+
+	ev, err := permissionEvent(pcore.PermissionEvent{Kind: pcore.NodePermissionEvent, Info: info})
+
+	assert.NoError(t, err)
+	assert.Equal(t, proto_common.PluginEvents_PERMISSION, ev.Type)
+	assert.Equal(t, "node", ev.Permission.Kind)
+	expected, _ := json.Marshal(info)
+	assert.Equal(t, expected, ev.Permission.Payload)
+}
+
+func TestHub_whenSubscriberIsSlow(t *testing.T) {
+	testObject := NewHub()
+	sub := testObject.subscribe()
+
+	for i := 0; i < subscriberChanSize+1; i++ {
+		testObject.send(&proto_common.PluginEvents_Event{})
+	}
+
+	assert.Len(t, sub, subscriberChanSize)
+	testObject.unsubscribe(sub)
+	_, ok := <-sub
+	assert.True(t, ok, "buffered events are still readable")
+}
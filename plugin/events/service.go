@@ -0,0 +1,11 @@
+package events
+
+import (
+	"context"
+)
+
+// PluginEventConsumer is implemented by plugins reacting to chain events
+type PluginEventConsumer interface {
+	// Connect serves the event service and tells the plugin where to subscribe to it
+	Connect(ctx context.Context) error
+}
@@ -0,0 +1,184 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	chainEventChanSize = 64
+	exportTimeout      = 30 * time.Second
+)
+
+// Backend provides the chain data being exported
+type Backend interface {
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+	// GetReceipts returns receipts of a block from the perspective of the private state in ctx
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	PSMR() mps.PrivateStateMetadataResolver
+}
+
+// ChainExporter follows the canonical chain and pushes every new block and
+// its receipts to the exporter plugin. Private receipts are only pushed for
+// the private states which the node grants to the plugin and the plugin asks for
+type ChainExporter struct {
+	backend  Backend
+	exporter PluginExporter
+	granted  []types.PrivateStateIdentifier // private states granted by the node configuration
+	psis     []types.PrivateStateIdentifier // authorized private states
+	anyPSI   types.PrivateStateIdentifier   // used to read public receipts when there's no authorized private state
+
+	sub  event.Subscription
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewChainExporter creates the exporter of the chain, granting the plugin the private
+// receipts of the private states, which come from the node configuration
+func NewChainExporter(backend Backend, exporter PluginExporter, granted []types.PrivateStateIdentifier) *ChainExporter {
+	return &ChainExporter{
+		backend:  backend,
+		exporter: exporter,
+		granted:  granted,
+	}
+}
+
+func (e *ChainExporter) Start() error {
+	// the plugin describes the private states it asks for, it is only given the ones
+	// granted by the node
+	requested, err := e.exporter.Describe(context.Background())
+	if err != nil {
+		return err
+	}
+	isRequested := make(map[types.PrivateStateIdentifier]bool, len(requested))
+	for _, psi := range requested {
+		isRequested[psi] = true
+	}
+	for _, psi := range requested {
+		if !containsPSI(e.granted, psi) {
+			log.Warn("Exporter: ignore private state not granted to the plugin", "psi", psi)
+		}
+	}
+	managed := make(map[types.PrivateStateIdentifier]bool)
+	for _, psi := range e.backend.PSMR().PSIs() {
+		managed[psi] = true
+		e.anyPSI = psi
+	}
+	if managed[types.DefaultPrivateStateIdentifier] {
+		e.anyPSI = types.DefaultPrivateStateIdentifier
+	}
+	e.psis = make([]types.PrivateStateIdentifier, 0, len(e.granted))
+	for _, psi := range e.granted {
+		if !isRequested[psi] {
+			continue
+		}
+		if !managed[psi] {
+			log.Warn("Exporter: ignore private state not managed by this node", "psi", psi)
+			continue
+		}
+		e.psis = append(e.psis, psi)
+	}
+	log.Info("Exporter: start exporting chain data", "psis", e.psis)
+	events := make(chan core.ChainEvent, chainEventChanSize)
+	e.sub = e.backend.SubscribeChainEvent(events)
+	e.quit = make(chan struct{})
+	e.wg.Add(1)
+	go e.loop(events)
+	return nil
+}
+
+func (e *ChainExporter) Stop() {
+	if e.quit == nil {
+		return
+	}
+	e.sub.Unsubscribe()
+	close(e.quit)
+	e.wg.Wait()
+	e.quit = nil
+}
+
+func (e *ChainExporter) loop(events chan core.ChainEvent) {
+	defer e.wg.Done()
+	for {
+		select {
+		case ev := <-events:
+			if err := e.export(ev.Block); err != nil {
+				log.Error("Exporter: failed to export block", "number", ev.Block.Number(), "hash", ev.Hash, "err", err)
+			}
+		case err := <-e.sub.Err():
+			if err != nil {
+				log.Error("Exporter: chain event subscription failed", "err", err)
+			}
+			return
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+func (e *ChainExporter) export(block *types.Block) error {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	var receipts types.Receipts
+	privateReceipts := make(map[types.PrivateStateIdentifier]types.Receipts, len(e.psis))
+	for _, psi := range e.psis {
+		all, err := e.receipts(ctx, block, psi)
+		if err != nil {
+			return err
+		}
+		// receipts of public transactions are the same for every private state
+		if receipts == nil {
+			receipts = filterReceipts(block, all, false)
+		}
+		privateReceipts[psi] = filterReceipts(block, all, true)
+	}
+	if receipts == nil {
+		all, err := e.receipts(ctx, block, e.anyPSI)
+		if err != nil {
+			return err
+		}
+		receipts = filterReceipts(block, all, false)
+	}
+	return e.exporter.ExportBlock(ctx, block, receipts, privateReceipts)
+}
+
+func (e *ChainExporter) receipts(ctx context.Context, block *types.Block, psi types.PrivateStateIdentifier) (types.Receipts, error) {
+	all, err := e.backend.GetReceipts(rpc.WithPrivateStateIdentifier(ctx, psi), block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if len(all) != len(block.Transactions()) {
+		return nil, fmt.Errorf("receipts not found for block %s", block.Hash().Hex())
+	}
+	return all, nil
+}
+
+// filterReceipts returns receipts of either private or public transactions in the block
+func filterReceipts(block *types.Block, receipts types.Receipts, private bool) types.Receipts {
+	filtered := make(types.Receipts, 0, len(receipts))
+	for i, tx := range block.Transactions() {
+		if tx.IsPrivate() == private {
+			filtered = append(filtered, receipts[i])
+		}
+	}
+	return filtered
+}
+
+func containsPSI(psis []types.PrivateStateIdentifier, psi types.PrivateStateIdentifier) bool {
+	for _, p := range psis {
+		if p == psi {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+type stubBackend struct {
+	feed     event.Feed
+	psmr     mps.PrivateStateMetadataResolver
+	receipts map[types.PrivateStateIdentifier]types.Receipts
+}
+
+func (b *stubBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return b.feed.Subscribe(ch)
+}
+
+func (b *stubBackend) GetReceipts(ctx context.Context, _ common.Hash) (types.Receipts, error) {
+	psi, _ := rpc.PrivateStateIdentifierFromContext(ctx)
+	return b.receipts[psi], nil
+}
+
+func (b *stubBackend) PSMR() mps.PrivateStateMetadataResolver {
+	return b.psmr
+}
+
+type exportedBlock struct {
+	block           *types.Block
+	receipts        types.Receipts
+	privateReceipts map[types.PrivateStateIdentifier]types.Receipts
+}
+
+type stubExporter struct {
+	psis     []types.PrivateStateIdentifier
+	exported chan exportedBlock
+}
+
+func (e *stubExporter) Describe(_ context.Context) ([]types.PrivateStateIdentifier, error) {
+	return e.psis, nil
+}
+
+func (e *stubExporter) ExportBlock(_ context.Context, block *types.Block, receipts types.Receipts, privateReceipts map[types.PrivateStateIdentifier]types.Receipts) error {
+	e.exported <- exportedBlock{block, receipts, privateReceipts}
+	return nil
+}
+
+func TestChainExporter_whenNewBlock(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	publicTx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	privateTx := types.NewTransaction(1, common.Address{}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	privateTx.SetPrivate()
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{publicTx, privateTx}, nil, nil, new(trie.Trie))
+	publicReceipt := &types.Receipt{TxHash: publicTx.Hash()}
+	ps1Receipt := &types.Receipt{TxHash: privateTx.Hash(), Status: types.ReceiptStatusSuccessful}
+	ps2Receipt := &types.Receipt{TxHash: privateTx.Hash()}
+
+	psmr := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	psmr.EXPECT().PSIs().Return([]types.PrivateStateIdentifier{"PS1", "PS2"})
+	backend := &stubBackend{
+		psmr: psmr,
+		receipts: map[types.PrivateStateIdentifier]types.Receipts{
+			"PS1": {publicReceipt, ps1Receipt},
+			"PS2": {publicReceipt, ps2Receipt},
+		},
+	}
+	stub := &stubExporter{
+		psis:     []types.PrivateStateIdentifier{"PS1", "PS2", "PS3"},
+		exported: make(chan exportedBlock, 1),
+	}
+	// PS2 is asked for by the plugin but not granted by the node
+	testObject := NewChainExporter(backend, stub, []types.PrivateStateIdentifier{"PS1", "PS3"})
+
+	assert.NoError(testObject.Start())
+	defer testObject.Stop()
+	backend.feed.Send(core.ChainEvent{Block: block, Hash: block.Hash()})
+
+	select {
+	case actual := <-stub.exported:
+		assert.Equal(block.Hash(), actual.block.Hash())
+		assert.Equal(types.Receipts{publicReceipt}, actual.receipts)
+		assert.Equal(map[types.PrivateStateIdentifier]types.Receipts{"PS1": {ps1Receipt}}, actual.privateReceipts)
+	case <-time.After(time.Second):
+		t.Fatal("block not exported")
+	}
+}
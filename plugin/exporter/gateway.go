@@ -0,0 +1,73 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type PluginGateway struct {
+	client proto_common.PluginExporterServiceClient
+}
+
+func (g *PluginGateway) Describe(ctx context.Context) ([]types.PrivateStateIdentifier, error) {
+	resp, err := g.client.Describe(ctx, &proto_common.PluginExporter_DescribeRequest{})
+	if err != nil {
+		return nil, err
+	}
+	psis := make([]types.PrivateStateIdentifier, len(resp.PrivateStateIdentifiers))
+	for i, psi := range resp.PrivateStateIdentifiers {
+		psis[i] = types.ToPrivateStateIdentifier(psi)
+	}
+	return psis, nil
+}
+
+func (g *PluginGateway) ExportBlock(ctx context.Context, block *types.Block, receipts types.Receipts, privateReceipts map[types.PrivateStateIdentifier]types.Receipts) error {
+	rawBlock, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	rawReceipts, err := encodeReceipts(receipts)
+	if err != nil {
+		return err
+	}
+	// deterministic order for the plugin
+	psis := make([]string, 0, len(privateReceipts))
+	for psi := range privateReceipts {
+		psis = append(psis, psi.String())
+	}
+	sort.Strings(psis)
+	rawPrivateReceipts := make([]*proto_common.PluginExporter_PrivateReceipts, len(psis))
+	for i, psi := range psis {
+		raw, err := encodeReceipts(privateReceipts[types.ToPrivateStateIdentifier(psi)])
+		if err != nil {
+			return err
+		}
+		rawPrivateReceipts[i] = &proto_common.PluginExporter_PrivateReceipts{
+			PrivateStateIdentifier: psi,
+			Receipts:               raw,
+		}
+	}
+	_, err = g.client.ExportBlock(ctx, &proto_common.PluginExporter_ExportBlockRequest{
+		Block:           rawBlock,
+		Receipts:        rawReceipts,
+		PrivateReceipts: rawPrivateReceipts,
+	})
+	return err
+}
+
+func encodeReceipts(receipts types.Receipts) ([][]byte, error) {
+	raw := make([][]byte, len(receipts))
+	for i, r := range receipts {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		raw[i] = data
+	}
+	return raw, nil
+}
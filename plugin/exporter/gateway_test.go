@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginGateway_Describe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := proto_common.NewMockPluginExporterServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Describe(gomock.Any(), gomock.Any()).
+		Return(&proto_common.PluginExporter_DescribeResponse{
+			PrivateStateIdentifiers: []string{"PS1", "PS2"},
+		}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	actual, err := testObject.Describe(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PrivateStateIdentifier{"PS1", "PS2"}, actual)
+}
+
+func TestPluginGateway_ExportBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: common.HexToHash("0x1")}
+	privateReceipt := &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: common.HexToHash("0x2")}
+	rawBlock, _ := rlp.EncodeToBytes(block)
+	rawReceipt, _ := json.Marshal(receipt)
+	rawPrivateReceipt, _ := json.Marshal(privateReceipt)
+	req := &proto_common.PluginExporter_ExportBlockRequest{
+		Block:    rawBlock,
+		Receipts: [][]byte{rawReceipt},
+		PrivateReceipts: []*proto_common.PluginExporter_PrivateReceipts{
+			{PrivateStateIdentifier: "PS1", Receipts: [][]byte{rawPrivateReceipt}},
+			{PrivateStateIdentifier: "PS2", Receipts: [][]byte{}},
+		},
+	}
+	mockClient := proto_common.NewMockPluginExporterServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		ExportBlock(gomock.Any(), gomock.Eq(req)).
+		Return(&proto_common.PluginExporter_ExportBlockResponse{}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	err := testObject.ExportBlock(context.Background(), block, types.Receipts{receipt}, map[types.PrivateStateIdentifier]types.Receipts{
+		"PS2": {},
+		"PS1": {privateReceipt},
+	})
+
+	assert.NoError(t, err)
+}
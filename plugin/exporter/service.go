@@ -0,0 +1,40 @@
+package exporter
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PluginExporter pushes chain data to external systems
+type PluginExporter interface {
+	// Describe returns the private states for which the plugin is authorized to receive private receipts
+	Describe(ctx context.Context) ([]types.PrivateStateIdentifier, error)
+	// ExportBlock sends a new block together with receipts of its public transactions
+	// and receipts of its private transactions for each authorized private state
+	ExportBlock(ctx context.Context, block *types.Block, receipts types.Receipts, privateReceipts map[types.PrivateStateIdentifier]types.Receipts) error
+}
+
+type PluginExporterDeferFunc func() (PluginExporter, error)
+
+// ReloadablePluginExporter dispenses the plugin client on every call so it keeps
+// working after the plugin is reloaded
+type ReloadablePluginExporter struct {
+	DeferFunc PluginExporterDeferFunc
+}
+
+func (d *ReloadablePluginExporter) Describe(ctx context.Context) ([]types.PrivateStateIdentifier, error) {
+	p, err := d.DeferFunc()
+	if err != nil {
+		return nil, err
+	}
+	return p.Describe(ctx)
+}
+
+func (d *ReloadablePluginExporter) ExportBlock(ctx context.Context, block *types.Block, receipts types.Receipts, privateReceipts map[types.PrivateStateIdentifier]types.Receipts) error {
+	p, err := d.DeferFunc()
+	if err != nil {
+		return err
+	}
+	return p.ExportBlock(ctx, block, receipts, privateReceipts)
+}
@@ -11,16 +11,40 @@
 
 // generate stubs
 //go:generate protoc -I ../../vendor/github.com/jpmorganchase/quorum-plugin-definitions -I ../../vendor --go_out=plugins=grpc:proto_common init.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common metrics.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common api.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common exporter.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common nodepermission.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common certificate.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common events.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common blockvalidation.proto
+//go:generate protoc -I proto --go_out=plugins=grpc:proto_common signingpolicy.proto
 
 // generate mocks for unit testing
 //go:generate mockgen -package proto_common -destination proto_common/mock_init.go -source proto_common/init.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_metrics.go -source proto_common/metrics.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_api.go -source proto_common/api.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_exporter.go -source proto_common/exporter.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_nodepermission.go -source proto_common/nodepermission.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_certificate.go -source proto_common/certificate.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_blockvalidation.go -source proto_common/blockvalidation.pb.go
+//go:generate mockgen -package proto_common -destination proto_common/mock_signingpolicy.go -source proto_common/signingpolicy.pb.go
+// reflect mode is used for streaming services as source mode fails to resolve embedded gRPC stream interfaces
+//go:generate mockgen -package proto_common -self_package github.com/ethereum/go-ethereum/plugin/gen/proto_common -destination proto_common/mock_events.go github.com/ethereum/go-ethereum/plugin/gen/proto_common PluginEventServiceClient,PluginEventService_SubscribeClient,PluginEventServiceServer,PluginEventService_SubscribeServer,PluginEventConsumerServiceClient,PluginEventConsumerServiceServer
 
 // fix fmt
 //go:generate goimports -w ./
 
 // generate documentation
 //go:generate protoc -I ../../vendor/github.com/jpmorganchase/quorum-plugin-definitions -I ../../vendor --doc_out=docs.markdown.tmpl,init_interface.md:../../docs/PluggableArchitecture/Plugins/ init.proto
-//go:generate protoc -I ../../vendor/github.com/jpmorganchase/quorum-plugin-definitions -I ../../vendor --doc_out=docs.markdown.tmpl,interface.md:../../docs/PluggableArchitecture/Plugins/helloworld/ helloworld.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,metrics_interface.md:../../docs/PluggableArchitecture/Plugins/ metrics.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,rpcapi_interface.md:../../docs/PluggableArchitecture/Plugins/ api.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,interface.md:../../docs/PluggableArchitecture/Plugins/exporter/ exporter.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,interface.md:../../docs/PluggableArchitecture/Plugins/nodepermission/ nodepermission.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,certificate_interface.md:../../docs/PluggableArchitecture/Plugins/ certificate.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,events_interface.md:../../docs/PluggableArchitecture/Plugins/ events.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,interface.md:../../docs/PluggableArchitecture/Plugins/blockvalidation/ blockvalidation.proto
+//go:generate protoc -I proto --doc_out=docs.markdown.tmpl,signingpolicy_interface.md:../../docs/PluggableArchitecture/Plugins/account/ signingpolicy.proto
 //go:generate protoc -I ../../vendor/github.com/jpmorganchase/quorum-plugin-definitions -I ../../vendor --doc_out=docs.markdown.tmpl,interface.md:../../docs/PluggableArchitecture/Plugins/security/ security.proto
 //go:generate protoc -I ../../vendor/github.com/jpmorganchase/quorum-plugin-definitions -I ../../vendor --doc_out=docs.markdown.tmpl,interface.md:../../docs/PluggableArchitecture/Plugins/account/ account.proto
 
@@ -0,0 +1,434 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: api.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// *
+// A wrapper message to logically group other messages
+type PluginRPCAPI struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginRPCAPI) Reset()         { *m = PluginRPCAPI{} }
+func (m *PluginRPCAPI) String() string { return proto.CompactTextString(m) }
+func (*PluginRPCAPI) ProtoMessage()    {}
+func (*PluginRPCAPI) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+func (m *PluginRPCAPI) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginRPCAPI.Unmarshal(m, b)
+}
+func (m *PluginRPCAPI) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginRPCAPI.Marshal(b, m, deterministic)
+}
+func (m *PluginRPCAPI) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginRPCAPI.Merge(m, src)
+}
+func (m *PluginRPCAPI) XXX_Size() int {
+	return xxx_messageInfo_PluginRPCAPI.Size(m)
+}
+func (m *PluginRPCAPI) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginRPCAPI.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginRPCAPI proto.InternalMessageInfo
+
+// *
+// Describe a JSON RPC method exposed by the plugin
+type PluginRPCAPI_Method struct {
+	// name of the method without the namespace, e.g.: `greeting`.
+	//
+	// `geth` exposes it as `plugin@<interface>_<name>`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// maximum number of positional parameters accepted by the method
+	ParamsCount          uint32   `protobuf:"varint,2,opt,name=paramsCount,proto3" json:"paramsCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginRPCAPI_Method) Reset()         { *m = PluginRPCAPI_Method{} }
+func (m *PluginRPCAPI_Method) String() string { return proto.CompactTextString(m) }
+func (*PluginRPCAPI_Method) ProtoMessage()    {}
+func (*PluginRPCAPI_Method) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 0}
+}
+
+func (m *PluginRPCAPI_Method) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginRPCAPI_Method.Unmarshal(m, b)
+}
+func (m *PluginRPCAPI_Method) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginRPCAPI_Method.Marshal(b, m, deterministic)
+}
+func (m *PluginRPCAPI_Method) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginRPCAPI_Method.Merge(m, src)
+}
+func (m *PluginRPCAPI_Method) XXX_Size() int {
+	return xxx_messageInfo_PluginRPCAPI_Method.Size(m)
+}
+func (m *PluginRPCAPI_Method) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginRPCAPI_Method.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginRPCAPI_Method proto.InternalMessageInfo
+
+func (m *PluginRPCAPI_Method) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PluginRPCAPI_Method) GetParamsCount() uint32 {
+	if m != nil {
+		return m.ParamsCount
+	}
+	return 0
+}
+
+type PluginRPCAPI_DescribeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginRPCAPI_DescribeRequest) Reset()         { *m = PluginRPCAPI_DescribeRequest{} }
+func (m *PluginRPCAPI_DescribeRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginRPCAPI_DescribeRequest) ProtoMessage()    {}
+func (*PluginRPCAPI_DescribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 1}
+}
+
+func (m *PluginRPCAPI_DescribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginRPCAPI_DescribeRequest.Unmarshal(m, b)
+}
+func (m *PluginRPCAPI_DescribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginRPCAPI_DescribeRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginRPCAPI_DescribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginRPCAPI_DescribeRequest.Merge(m, src)
+}
+func (m *PluginRPCAPI_DescribeRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginRPCAPI_DescribeRequest.Size(m)
+}
+func (m *PluginRPCAPI_DescribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginRPCAPI_DescribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginRPCAPI_DescribeRequest proto.InternalMessageInfo
+
+type PluginRPCAPI_DescribeResponse struct {
+	Methods              []*PluginRPCAPI_Method `protobuf:"bytes,1,rep,name=methods,proto3" json:"methods,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *PluginRPCAPI_DescribeResponse) Reset()         { *m = PluginRPCAPI_DescribeResponse{} }
+func (m *PluginRPCAPI_DescribeResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginRPCAPI_DescribeResponse) ProtoMessage()    {}
+func (*PluginRPCAPI_DescribeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 2}
+}
+
+func (m *PluginRPCAPI_DescribeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginRPCAPI_DescribeResponse.Unmarshal(m, b)
+}
+func (m *PluginRPCAPI_DescribeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginRPCAPI_DescribeResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginRPCAPI_DescribeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginRPCAPI_DescribeResponse.Merge(m, src)
+}
+func (m *PluginRPCAPI_DescribeResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginRPCAPI_DescribeResponse.Size(m)
+}
+func (m *PluginRPCAPI_DescribeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginRPCAPI_DescribeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginRPCAPI_DescribeResponse proto.InternalMessageInfo
+
+func (m *PluginRPCAPI_DescribeResponse) GetMethods() []*PluginRPCAPI_Method {
+	if m != nil {
+		return m.Methods
+	}
+	return nil
+}
+
+type PluginRPCAPI_InvokeRequest struct {
+	// name of the method being invoked without the namespace
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// JSON-encoded positional parameters
+	Params [][]byte `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"`
+	// the private state identifier the caller is authorized to operate on
+	PrivateStateIdentifier string   `protobuf:"bytes,3,opt,name=privateStateIdentifier,proto3" json:"privateStateIdentifier,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *PluginRPCAPI_InvokeRequest) Reset()         { *m = PluginRPCAPI_InvokeRequest{} }
+func (m *PluginRPCAPI_InvokeRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginRPCAPI_InvokeRequest) ProtoMessage()    {}
+func (*PluginRPCAPI_InvokeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 3}
+}
+
+func (m *PluginRPCAPI_InvokeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginRPCAPI_InvokeRequest.Unmarshal(m, b)
+}
+func (m *PluginRPCAPI_InvokeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginRPCAPI_InvokeRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginRPCAPI_InvokeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginRPCAPI_InvokeRequest.Merge(m, src)
+}
+func (m *PluginRPCAPI_InvokeRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginRPCAPI_InvokeRequest.Size(m)
+}
+func (m *PluginRPCAPI_InvokeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginRPCAPI_InvokeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginRPCAPI_InvokeRequest proto.InternalMessageInfo
+
+func (m *PluginRPCAPI_InvokeRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *PluginRPCAPI_InvokeRequest) GetParams() [][]byte {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *PluginRPCAPI_InvokeRequest) GetPrivateStateIdentifier() string {
+	if m != nil {
+		return m.PrivateStateIdentifier
+	}
+	return ""
+}
+
+type PluginRPCAPI_InvokeResponse struct {
+	// JSON-encoded result
+	Result               []byte   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginRPCAPI_InvokeResponse) Reset()         { *m = PluginRPCAPI_InvokeResponse{} }
+func (m *PluginRPCAPI_InvokeResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginRPCAPI_InvokeResponse) ProtoMessage()    {}
+func (*PluginRPCAPI_InvokeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 4}
+}
+
+func (m *PluginRPCAPI_InvokeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginRPCAPI_InvokeResponse.Unmarshal(m, b)
+}
+func (m *PluginRPCAPI_InvokeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginRPCAPI_InvokeResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginRPCAPI_InvokeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginRPCAPI_InvokeResponse.Merge(m, src)
+}
+func (m *PluginRPCAPI_InvokeResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginRPCAPI_InvokeResponse.Size(m)
+}
+func (m *PluginRPCAPI_InvokeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginRPCAPI_InvokeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginRPCAPI_InvokeResponse proto.InternalMessageInfo
+
+func (m *PluginRPCAPI_InvokeResponse) GetResult() []byte {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*PluginRPCAPI)(nil), "proto_common.PluginRPCAPI")
+	proto.RegisterType((*PluginRPCAPI_Method)(nil), "proto_common.PluginRPCAPI.Method")
+	proto.RegisterType((*PluginRPCAPI_DescribeRequest)(nil), "proto_common.PluginRPCAPI.DescribeRequest")
+	proto.RegisterType((*PluginRPCAPI_DescribeResponse)(nil), "proto_common.PluginRPCAPI.DescribeResponse")
+	proto.RegisterType((*PluginRPCAPI_InvokeRequest)(nil), "proto_common.PluginRPCAPI.InvokeRequest")
+	proto.RegisterType((*PluginRPCAPI_InvokeResponse)(nil), "proto_common.PluginRPCAPI.InvokeResponse")
+}
+
+func init() {
+	proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c)
+}
+
+var fileDescriptor_00212fb1f9d3bf1c = []byte{
+	// 332 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xc1, 0x4b, 0xeb, 0x40,
+	0x10, 0xc6, 0x49, 0xfb, 0xc8, 0x7b, 0x9d, 0xa6, 0x4f, 0x5d, 0xa1, 0x86, 0x9c, 0xa2, 0xa7, 0xa8,
+	0x10, 0xa1, 0x82, 0x17, 0x41, 0xb0, 0xf5, 0xd2, 0x83, 0x58, 0xb6, 0x37, 0x41, 0x24, 0x4d, 0x47,
+	0x5d, 0xec, 0xee, 0xa6, 0xbb, 0x9b, 0xfa, 0x97, 0x7a, 0xf0, 0xbf, 0x11, 0x77, 0x13, 0x4c, 0x05,
+	0x4b, 0x4f, 0xc9, 0x37, 0x33, 0x5f, 0xbe, 0xdf, 0x0c, 0x81, 0x4e, 0x56, 0xb0, 0xb4, 0x50, 0xd2,
+	0x48, 0x12, 0xd8, 0xc7, 0x63, 0x2e, 0x39, 0x97, 0xe2, 0xe8, 0xbd, 0x05, 0xc1, 0x64, 0x51, 0x3e,
+	0x33, 0x41, 0x27, 0xa3, 0xeb, 0xc9, 0x38, 0xba, 0x02, 0xff, 0x16, 0xcd, 0x8b, 0x9c, 0x13, 0x02,
+	0x7f, 0x44, 0xc6, 0x31, 0xf4, 0x62, 0x2f, 0xe9, 0x50, 0xfb, 0x4e, 0x62, 0xe8, 0x16, 0x99, 0xca,
+	0xb8, 0x1e, 0xc9, 0x52, 0x98, 0xb0, 0x15, 0x7b, 0x49, 0x8f, 0x36, 0x4b, 0xd1, 0x1e, 0xec, 0xdc,
+	0xa0, 0xce, 0x15, 0x9b, 0x21, 0xc5, 0x65, 0x89, 0xda, 0x44, 0x77, 0xb0, 0xfb, 0x5d, 0xd2, 0x85,
+	0x14, 0x1a, 0xc9, 0x25, 0xfc, 0xe5, 0x36, 0x46, 0x87, 0x5e, 0xdc, 0x4e, 0xba, 0x83, 0xc3, 0xb4,
+	0xc9, 0x95, 0x36, 0x99, 0x52, 0x07, 0x44, 0x6b, 0x47, 0xf4, 0x06, 0xbd, 0xb1, 0x58, 0xc9, 0xd7,
+	0x3a, 0x81, 0xf4, 0xc1, 0x77, 0xbd, 0x0a, 0xb6, 0x52, 0x5f, 0x75, 0xc7, 0x16, 0xb6, 0xe2, 0x76,
+	0x12, 0xd0, 0x4a, 0x91, 0x0b, 0xe8, 0x17, 0x8a, 0xad, 0x32, 0x83, 0x53, 0x93, 0x19, 0x1c, 0xcf,
+	0x51, 0x18, 0xf6, 0xc4, 0x50, 0x85, 0x6d, 0xeb, 0xff, 0xa5, 0x1b, 0x25, 0xf0, 0xbf, 0x0e, 0xae,
+	0xf6, 0xe8, 0x83, 0xaf, 0x50, 0x97, 0x0b, 0x63, 0x93, 0x03, 0x5a, 0xa9, 0xc1, 0x87, 0x07, 0xfb,
+	0xcd, 0x1d, 0xa6, 0xa8, 0x56, 0x2c, 0x47, 0x92, 0xc3, 0xbf, 0xfa, 0x16, 0xe4, 0x64, 0xc3, 0xca,
+	0x3f, 0x6f, 0x78, 0xba, 0xd5, 0x6c, 0x05, 0xf5, 0x00, 0xbe, 0xc3, 0x24, 0xc9, 0x06, 0xdb, 0xda,
+	0x09, 0xa3, 0xe3, 0x2d, 0x26, 0xdd, 0xe7, 0x87, 0x67, 0x70, 0x90, 0x4b, 0x9e, 0x2e, 0x4b, 0xa9,
+	0x4a, 0x9e, 0x16, 0x76, 0xd2, 0xb9, 0x87, 0xbe, 0x73, 0xdc, 0xaf, 0xfd, 0x64, 0x33, 0xdf, 0xaa,
+	0xf3, 0xcf, 0x01, 0x00, 0x27, 0xba, 0x5a, 0xed, 0x86, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginRPCAPIServiceClient is the client API for PluginRPCAPIService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginRPCAPIServiceClient interface {
+	Describe(ctx context.Context, in *PluginRPCAPI_DescribeRequest, opts ...grpc.CallOption) (*PluginRPCAPI_DescribeResponse, error)
+	Invoke(ctx context.Context, in *PluginRPCAPI_InvokeRequest, opts ...grpc.CallOption) (*PluginRPCAPI_InvokeResponse, error)
+}
+
+type pluginRPCAPIServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginRPCAPIServiceClient(cc grpc.ClientConnInterface) PluginRPCAPIServiceClient {
+	return &pluginRPCAPIServiceClient{cc}
+}
+
+func (c *pluginRPCAPIServiceClient) Describe(ctx context.Context, in *PluginRPCAPI_DescribeRequest, opts ...grpc.CallOption) (*PluginRPCAPI_DescribeResponse, error) {
+	out := new(PluginRPCAPI_DescribeResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginRPCAPIService/Describe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginRPCAPIServiceClient) Invoke(ctx context.Context, in *PluginRPCAPI_InvokeRequest, opts ...grpc.CallOption) (*PluginRPCAPI_InvokeResponse, error) {
+	out := new(PluginRPCAPI_InvokeResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginRPCAPIService/Invoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginRPCAPIServiceServer is the server API for PluginRPCAPIService service.
+type PluginRPCAPIServiceServer interface {
+	Describe(context.Context, *PluginRPCAPI_DescribeRequest) (*PluginRPCAPI_DescribeResponse, error)
+	Invoke(context.Context, *PluginRPCAPI_InvokeRequest) (*PluginRPCAPI_InvokeResponse, error)
+}
+
+// UnimplementedPluginRPCAPIServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginRPCAPIServiceServer struct {
+}
+
+func (*UnimplementedPluginRPCAPIServiceServer) Describe(ctx context.Context, req *PluginRPCAPI_DescribeRequest) (*PluginRPCAPI_DescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (*UnimplementedPluginRPCAPIServiceServer) Invoke(ctx context.Context, req *PluginRPCAPI_InvokeRequest) (*PluginRPCAPI_InvokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invoke not implemented")
+}
+
+func RegisterPluginRPCAPIServiceServer(s *grpc.Server, srv PluginRPCAPIServiceServer) {
+	s.RegisterService(&_PluginRPCAPIService_serviceDesc, srv)
+}
+
+func _PluginRPCAPIService_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginRPCAPI_DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginRPCAPIServiceServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginRPCAPIService/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginRPCAPIServiceServer).Describe(ctx, req.(*PluginRPCAPI_DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginRPCAPIService_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginRPCAPI_InvokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginRPCAPIServiceServer).Invoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginRPCAPIService/Invoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginRPCAPIServiceServer).Invoke(ctx, req.(*PluginRPCAPI_InvokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginRPCAPIService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginRPCAPIService",
+	HandlerType: (*PluginRPCAPIServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _PluginRPCAPIService_Describe_Handler,
+		},
+		{
+			MethodName: "Invoke",
+			Handler:    _PluginRPCAPIService_Invoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
@@ -0,0 +1,267 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: blockvalidation.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// *
+// A wrapper message to logically group other messages
+type PluginBlockValidation struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginBlockValidation) Reset()         { *m = PluginBlockValidation{} }
+func (m *PluginBlockValidation) String() string { return proto.CompactTextString(m) }
+func (*PluginBlockValidation) ProtoMessage()    {}
+func (*PluginBlockValidation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0bcb02a1df688d5f, []int{0}
+}
+
+func (m *PluginBlockValidation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginBlockValidation.Unmarshal(m, b)
+}
+func (m *PluginBlockValidation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginBlockValidation.Marshal(b, m, deterministic)
+}
+func (m *PluginBlockValidation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginBlockValidation.Merge(m, src)
+}
+func (m *PluginBlockValidation) XXX_Size() int {
+	return xxx_messageInfo_PluginBlockValidation.Size(m)
+}
+func (m *PluginBlockValidation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginBlockValidation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginBlockValidation proto.InternalMessageInfo
+
+type PluginBlockValidation_ValidateRequest struct {
+	// RLP-encoded block
+	Block []byte `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	// JSON-encoded receipts of the transactions in the block, as per the public state
+	Receipts             [][]byte `protobuf:"bytes,2,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginBlockValidation_ValidateRequest) Reset()         { *m = PluginBlockValidation_ValidateRequest{} }
+func (m *PluginBlockValidation_ValidateRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginBlockValidation_ValidateRequest) ProtoMessage()    {}
+func (*PluginBlockValidation_ValidateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0bcb02a1df688d5f, []int{0, 0}
+}
+
+func (m *PluginBlockValidation_ValidateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginBlockValidation_ValidateRequest.Unmarshal(m, b)
+}
+func (m *PluginBlockValidation_ValidateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginBlockValidation_ValidateRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginBlockValidation_ValidateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginBlockValidation_ValidateRequest.Merge(m, src)
+}
+func (m *PluginBlockValidation_ValidateRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginBlockValidation_ValidateRequest.Size(m)
+}
+func (m *PluginBlockValidation_ValidateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginBlockValidation_ValidateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginBlockValidation_ValidateRequest proto.InternalMessageInfo
+
+func (m *PluginBlockValidation_ValidateRequest) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *PluginBlockValidation_ValidateRequest) GetReceipts() [][]byte {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+type PluginBlockValidation_ValidateResponse struct {
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// reason for rejecting the block, used for logging
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginBlockValidation_ValidateResponse) Reset() {
+	*m = PluginBlockValidation_ValidateResponse{}
+}
+func (m *PluginBlockValidation_ValidateResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginBlockValidation_ValidateResponse) ProtoMessage()    {}
+func (*PluginBlockValidation_ValidateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0bcb02a1df688d5f, []int{0, 1}
+}
+
+func (m *PluginBlockValidation_ValidateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginBlockValidation_ValidateResponse.Unmarshal(m, b)
+}
+func (m *PluginBlockValidation_ValidateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginBlockValidation_ValidateResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginBlockValidation_ValidateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginBlockValidation_ValidateResponse.Merge(m, src)
+}
+func (m *PluginBlockValidation_ValidateResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginBlockValidation_ValidateResponse.Size(m)
+}
+func (m *PluginBlockValidation_ValidateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginBlockValidation_ValidateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginBlockValidation_ValidateResponse proto.InternalMessageInfo
+
+func (m *PluginBlockValidation_ValidateResponse) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *PluginBlockValidation_ValidateResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*PluginBlockValidation)(nil), "proto_common.PluginBlockValidation")
+	proto.RegisterType((*PluginBlockValidation_ValidateRequest)(nil), "proto_common.PluginBlockValidation.ValidateRequest")
+	proto.RegisterType((*PluginBlockValidation_ValidateResponse)(nil), "proto_common.PluginBlockValidation.ValidateResponse")
+}
+
+func init() {
+	proto.RegisterFile("blockvalidation.proto", fileDescriptor_0bcb02a1df688d5f)
+}
+
+var fileDescriptor_0bcb02a1df688d5f = []byte{
+	// 234 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4d, 0xca, 0xc9, 0x4f,
+	0xce, 0x2e, 0x4b, 0xcc, 0xc9, 0x4c, 0x49, 0x2c, 0xc9, 0xcc, 0xcf, 0xd3, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x17, 0xe2, 0x01, 0x53, 0xf1, 0xc9, 0xf9, 0xb9, 0xb9, 0xf9, 0x79, 0x4a, 0xf3, 0x18, 0xb9,
+	0x44, 0x03, 0x72, 0x4a, 0xd3, 0x33, 0xf3, 0x9c, 0x40, 0xaa, 0xc3, 0xe0, 0xaa, 0xa5, 0x9c, 0xb9,
+	0xf8, 0xa1, 0xbc, 0xd4, 0xa0, 0xd4, 0xc2, 0xd2, 0xd4, 0xe2, 0x12, 0x21, 0x11, 0x2e, 0x56, 0xb0,
+	0x99, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x3c, 0x41, 0x10, 0x8e, 0x90, 0x14, 0x17, 0x47, 0x51, 0x6a,
+	0x72, 0x6a, 0x66, 0x41, 0x49, 0xb1, 0x04, 0x93, 0x02, 0xb3, 0x06, 0x4f, 0x10, 0x9c, 0x2f, 0xe5,
+	0xc0, 0x25, 0x80, 0x30, 0xa4, 0xb8, 0x20, 0x3f, 0xaf, 0x38, 0x15, 0x64, 0x0a, 0xd8, 0x51, 0x60,
+	0x53, 0x38, 0x82, 0x20, 0x1c, 0x21, 0x31, 0x2e, 0xb6, 0xa2, 0xd4, 0xc4, 0xe2, 0xfc, 0x3c, 0x09,
+	0x26, 0x05, 0x46, 0x0d, 0xce, 0x20, 0x28, 0xcf, 0x68, 0x2a, 0x23, 0x97, 0x0c, 0x56, 0x07, 0x06,
+	0xa7, 0x16, 0x95, 0x65, 0x26, 0xa7, 0x0a, 0x95, 0x72, 0x71, 0xc0, 0xac, 0x10, 0x32, 0xd6, 0x43,
+	0xf6, 0x9c, 0x1e, 0x56, 0x7d, 0x7a, 0x68, 0xbe, 0x92, 0x32, 0x21, 0x4d, 0x13, 0xc4, 0x17, 0x4e,
+	0x16, 0x5c, 0xe2, 0xc9, 0xf9, 0xb9, 0x7a, 0x85, 0xa5, 0xf9, 0x45, 0xa5, 0xb9, 0x7a, 0x05, 0x60,
+	0x4d, 0x10, 0x83, 0x9c, 0xf8, 0xd1, 0x34, 0x47, 0xa1, 0x04, 0x79, 0x12, 0x1b, 0x98, 0x67, 0x0c,
+	0x18, 0x00, 0x2f, 0xc1, 0x29, 0x70, 0xa0, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginBlockValidationServiceClient is the client API for PluginBlockValidationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginBlockValidationServiceClient interface {
+	Validate(ctx context.Context, in *PluginBlockValidation_ValidateRequest, opts ...grpc.CallOption) (*PluginBlockValidation_ValidateResponse, error)
+}
+
+type pluginBlockValidationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginBlockValidationServiceClient(cc grpc.ClientConnInterface) PluginBlockValidationServiceClient {
+	return &pluginBlockValidationServiceClient{cc}
+}
+
+func (c *pluginBlockValidationServiceClient) Validate(ctx context.Context, in *PluginBlockValidation_ValidateRequest, opts ...grpc.CallOption) (*PluginBlockValidation_ValidateResponse, error) {
+	out := new(PluginBlockValidation_ValidateResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginBlockValidationService/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginBlockValidationServiceServer is the server API for PluginBlockValidationService service.
+type PluginBlockValidationServiceServer interface {
+	Validate(context.Context, *PluginBlockValidation_ValidateRequest) (*PluginBlockValidation_ValidateResponse, error)
+}
+
+// UnimplementedPluginBlockValidationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginBlockValidationServiceServer struct {
+}
+
+func (*UnimplementedPluginBlockValidationServiceServer) Validate(ctx context.Context, req *PluginBlockValidation_ValidateRequest) (*PluginBlockValidation_ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+
+func RegisterPluginBlockValidationServiceServer(s *grpc.Server, srv PluginBlockValidationServiceServer) {
+	s.RegisterService(&_PluginBlockValidationService_serviceDesc, srv)
+}
+
+func _PluginBlockValidationService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginBlockValidation_ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginBlockValidationServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginBlockValidationService/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginBlockValidationServiceServer).Validate(ctx, req.(*PluginBlockValidation_ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginBlockValidationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginBlockValidationService",
+	HandlerType: (*PluginBlockValidationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Validate",
+			Handler:    _PluginBlockValidationService_Validate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "blockvalidation.proto",
+}
@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: certificate.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// *
+// A wrapper message to logically group other messages
+type PluginCertificate struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginCertificate) Reset()         { *m = PluginCertificate{} }
+func (m *PluginCertificate) String() string { return proto.CompactTextString(m) }
+func (*PluginCertificate) ProtoMessage()    {}
+func (*PluginCertificate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0d34c34dd33be4b, []int{0}
+}
+
+func (m *PluginCertificate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginCertificate.Unmarshal(m, b)
+}
+func (m *PluginCertificate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginCertificate.Marshal(b, m, deterministic)
+}
+func (m *PluginCertificate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginCertificate.Merge(m, src)
+}
+func (m *PluginCertificate) XXX_Size() int {
+	return xxx_messageInfo_PluginCertificate.Size(m)
+}
+func (m *PluginCertificate) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginCertificate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginCertificate proto.InternalMessageInfo
+
+type PluginCertificate_RotateRequest struct {
+	// PEM-encoded x509 certificate to be presented by the plugin
+	Certificate []byte `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// PEM-encoded private key of the certificate
+	PrivateKey           []byte   `protobuf:"bytes,2,opt,name=privateKey,proto3" json:"privateKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginCertificate_RotateRequest) Reset()         { *m = PluginCertificate_RotateRequest{} }
+func (m *PluginCertificate_RotateRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginCertificate_RotateRequest) ProtoMessage()    {}
+func (*PluginCertificate_RotateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0d34c34dd33be4b, []int{0, 0}
+}
+
+func (m *PluginCertificate_RotateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginCertificate_RotateRequest.Unmarshal(m, b)
+}
+func (m *PluginCertificate_RotateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginCertificate_RotateRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginCertificate_RotateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginCertificate_RotateRequest.Merge(m, src)
+}
+func (m *PluginCertificate_RotateRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginCertificate_RotateRequest.Size(m)
+}
+func (m *PluginCertificate_RotateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginCertificate_RotateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginCertificate_RotateRequest proto.InternalMessageInfo
+
+func (m *PluginCertificate_RotateRequest) GetCertificate() []byte {
+	if m != nil {
+		return m.Certificate
+	}
+	return nil
+}
+
+func (m *PluginCertificate_RotateRequest) GetPrivateKey() []byte {
+	if m != nil {
+		return m.PrivateKey
+	}
+	return nil
+}
+
+type PluginCertificate_RotateResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginCertificate_RotateResponse) Reset()         { *m = PluginCertificate_RotateResponse{} }
+func (m *PluginCertificate_RotateResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginCertificate_RotateResponse) ProtoMessage()    {}
+func (*PluginCertificate_RotateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0d34c34dd33be4b, []int{0, 1}
+}
+
+func (m *PluginCertificate_RotateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginCertificate_RotateResponse.Unmarshal(m, b)
+}
+func (m *PluginCertificate_RotateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginCertificate_RotateResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginCertificate_RotateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginCertificate_RotateResponse.Merge(m, src)
+}
+func (m *PluginCertificate_RotateResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginCertificate_RotateResponse.Size(m)
+}
+func (m *PluginCertificate_RotateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginCertificate_RotateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginCertificate_RotateResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*PluginCertificate)(nil), "proto_common.PluginCertificate")
+	proto.RegisterType((*PluginCertificate_RotateRequest)(nil), "proto_common.PluginCertificate.RotateRequest")
+	proto.RegisterType((*PluginCertificate_RotateResponse)(nil), "proto_common.PluginCertificate.RotateResponse")
+}
+
+func init() {
+	proto.RegisterFile("certificate.proto", fileDescriptor_c0d34c34dd33be4b)
+}
+
+var fileDescriptor_c0d34c34dd33be4b = []byte{
+	// 199 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0x4e, 0x2d, 0x2a,
+	0xc9, 0x4c, 0xcb, 0x4c, 0x4e, 0x2c, 0x49, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x01,
+	0x53, 0xf1, 0xc9, 0xf9, 0xb9, 0xb9, 0xf9, 0x79, 0x4a, 0x15, 0x5c, 0x82, 0x01, 0x39, 0xa5, 0xe9,
+	0x99, 0x79, 0xce, 0x08, 0x85, 0x52, 0x81, 0x5c, 0xbc, 0x41, 0xf9, 0x25, 0x89, 0x25, 0xa9, 0x41,
+	0xa9, 0x85, 0xa5, 0xa9, 0xc5, 0x25, 0x42, 0x0a, 0x5c, 0xdc, 0x48, 0x06, 0x49, 0x30, 0x2a, 0x30,
+	0x6a, 0xf0, 0x04, 0x21, 0x0b, 0x09, 0xc9, 0x71, 0x71, 0x15, 0x14, 0x65, 0x96, 0x25, 0x96, 0xa4,
+	0x7a, 0xa7, 0x56, 0x4a, 0x30, 0x81, 0x15, 0x20, 0x89, 0x48, 0x09, 0x70, 0xf1, 0xc1, 0x8c, 0x2c,
+	0x2e, 0xc8, 0xcf, 0x2b, 0x4e, 0x35, 0x6a, 0x66, 0xe4, 0x92, 0xc0, 0xb0, 0x3a, 0x38, 0xb5, 0xa8,
+	0x2c, 0x33, 0x39, 0x55, 0x28, 0x9d, 0x8b, 0x0d, 0xa2, 0x5c, 0x48, 0x57, 0x0f, 0xd9, 0xbd, 0x7a,
+	0x18, 0x3a, 0xf4, 0x50, 0x5c, 0x2a, 0xa5, 0x47, 0xac, 0x72, 0x88, 0x2b, 0x9c, 0x4c, 0xb8, 0xc4,
+	0x93, 0xf3, 0x73, 0xf5, 0x0a, 0x4b, 0xf3, 0x8b, 0x4a, 0x73, 0xf5, 0x0a, 0xc0, 0xca, 0x21, 0x46,
+	0x38, 0x71, 0x23, 0x69, 0x8b, 0x42, 0x09, 0xb5, 0x24, 0x36, 0x30, 0xcf, 0x18, 0x30, 0x00, 0x5d,
+	0xd9, 0x64, 0x1f, 0x5f, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginCertificateServiceClient is the client API for PluginCertificateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginCertificateServiceClient interface {
+	Rotate(ctx context.Context, in *PluginCertificate_RotateRequest, opts ...grpc.CallOption) (*PluginCertificate_RotateResponse, error)
+}
+
+type pluginCertificateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginCertificateServiceClient(cc grpc.ClientConnInterface) PluginCertificateServiceClient {
+	return &pluginCertificateServiceClient{cc}
+}
+
+func (c *pluginCertificateServiceClient) Rotate(ctx context.Context, in *PluginCertificate_RotateRequest, opts ...grpc.CallOption) (*PluginCertificate_RotateResponse, error) {
+	out := new(PluginCertificate_RotateResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginCertificateService/Rotate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginCertificateServiceServer is the server API for PluginCertificateService service.
+type PluginCertificateServiceServer interface {
+	Rotate(context.Context, *PluginCertificate_RotateRequest) (*PluginCertificate_RotateResponse, error)
+}
+
+// UnimplementedPluginCertificateServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginCertificateServiceServer struct {
+}
+
+func (*UnimplementedPluginCertificateServiceServer) Rotate(ctx context.Context, req *PluginCertificate_RotateRequest) (*PluginCertificate_RotateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rotate not implemented")
+}
+
+func RegisterPluginCertificateServiceServer(s *grpc.Server, srv PluginCertificateServiceServer) {
+	s.RegisterService(&_PluginCertificateService_serviceDesc, srv)
+}
+
+func _PluginCertificateService_Rotate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginCertificate_RotateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginCertificateServiceServer).Rotate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginCertificateService/Rotate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginCertificateServiceServer).Rotate(ctx, req.(*PluginCertificate_RotateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginCertificateService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginCertificateService",
+	HandlerType: (*PluginCertificateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Rotate",
+			Handler:    _PluginCertificateService_Rotate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "certificate.proto",
+}
@@ -0,0 +1,667 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: events.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type PluginEvents_Type int32
+
+const (
+	PluginEvents_CHAIN_HEAD PluginEvents_Type = 0
+	PluginEvents_TX_POOL    PluginEvents_Type = 1
+	PluginEvents_PERMISSION PluginEvents_Type = 2
+)
+
+var PluginEvents_Type_name = map[int32]string{
+	0: "CHAIN_HEAD",
+	1: "TX_POOL",
+	2: "PERMISSION",
+}
+
+var PluginEvents_Type_value = map[string]int32{
+	"CHAIN_HEAD": 0,
+	"TX_POOL":    1,
+	"PERMISSION": 2,
+}
+
+func (x PluginEvents_Type) String() string {
+	return proto.EnumName(PluginEvents_Type_name, int32(x))
+}
+
+func (PluginEvents_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 0}
+}
+
+// *
+// A wrapper message to logically group other messages
+type PluginEvents struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginEvents) Reset()         { *m = PluginEvents{} }
+func (m *PluginEvents) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents) ProtoMessage()    {}
+func (*PluginEvents) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0}
+}
+
+func (m *PluginEvents) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents.Unmarshal(m, b)
+}
+func (m *PluginEvents) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents.Merge(m, src)
+}
+func (m *PluginEvents) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents.Size(m)
+}
+func (m *PluginEvents) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents proto.InternalMessageInfo
+
+// *
+// Sent to the plugin once geth is ready to stream events
+type PluginEvents_ConnectRequest struct {
+	// identifier to be used by the plugin to dial the event service hosted by geth
+	//
+	// via the plugin library connection broker
+	BrokerId             uint32   `protobuf:"varint,1,opt,name=brokerId,proto3" json:"brokerId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginEvents_ConnectRequest) Reset()         { *m = PluginEvents_ConnectRequest{} }
+func (m *PluginEvents_ConnectRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_ConnectRequest) ProtoMessage()    {}
+func (*PluginEvents_ConnectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 0}
+}
+
+func (m *PluginEvents_ConnectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_ConnectRequest.Unmarshal(m, b)
+}
+func (m *PluginEvents_ConnectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_ConnectRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_ConnectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_ConnectRequest.Merge(m, src)
+}
+func (m *PluginEvents_ConnectRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_ConnectRequest.Size(m)
+}
+func (m *PluginEvents_ConnectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_ConnectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_ConnectRequest proto.InternalMessageInfo
+
+func (m *PluginEvents_ConnectRequest) GetBrokerId() uint32 {
+	if m != nil {
+		return m.BrokerId
+	}
+	return 0
+}
+
+type PluginEvents_ConnectResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginEvents_ConnectResponse) Reset()         { *m = PluginEvents_ConnectResponse{} }
+func (m *PluginEvents_ConnectResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_ConnectResponse) ProtoMessage()    {}
+func (*PluginEvents_ConnectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 1}
+}
+
+func (m *PluginEvents_ConnectResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_ConnectResponse.Unmarshal(m, b)
+}
+func (m *PluginEvents_ConnectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_ConnectResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_ConnectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_ConnectResponse.Merge(m, src)
+}
+func (m *PluginEvents_ConnectResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_ConnectResponse.Size(m)
+}
+func (m *PluginEvents_ConnectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_ConnectResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_ConnectResponse proto.InternalMessageInfo
+
+type PluginEvents_SubscribeRequest struct {
+	// types of events to receive. All types if empty
+	Types                []PluginEvents_Type `protobuf:"varint,1,rep,packed,name=types,proto3,enum=proto_common.PluginEvents_Type" json:"types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *PluginEvents_SubscribeRequest) Reset()         { *m = PluginEvents_SubscribeRequest{} }
+func (m *PluginEvents_SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_SubscribeRequest) ProtoMessage()    {}
+func (*PluginEvents_SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 2}
+}
+
+func (m *PluginEvents_SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *PluginEvents_SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_SubscribeRequest.Merge(m, src)
+}
+func (m *PluginEvents_SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_SubscribeRequest.Size(m)
+}
+func (m *PluginEvents_SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_SubscribeRequest proto.InternalMessageInfo
+
+func (m *PluginEvents_SubscribeRequest) GetTypes() []PluginEvents_Type {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+// *
+// A new block has been imported as the head of the canonical chain
+type PluginEvents_ChainHead struct {
+	Number uint64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	// block hash in hex
+	Hash string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// parent block hash in hex
+	ParentHash string `protobuf:"bytes,3,opt,name=parentHash,proto3" json:"parentHash,omitempty"`
+	Timestamp  uint64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// hashes of the transactions in the block, in hex
+	TxHashes             []string `protobuf:"bytes,5,rep,name=txHashes,proto3" json:"txHashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginEvents_ChainHead) Reset()         { *m = PluginEvents_ChainHead{} }
+func (m *PluginEvents_ChainHead) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_ChainHead) ProtoMessage()    {}
+func (*PluginEvents_ChainHead) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 3}
+}
+
+func (m *PluginEvents_ChainHead) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_ChainHead.Unmarshal(m, b)
+}
+func (m *PluginEvents_ChainHead) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_ChainHead.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_ChainHead) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_ChainHead.Merge(m, src)
+}
+func (m *PluginEvents_ChainHead) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_ChainHead.Size(m)
+}
+func (m *PluginEvents_ChainHead) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_ChainHead.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_ChainHead proto.InternalMessageInfo
+
+func (m *PluginEvents_ChainHead) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *PluginEvents_ChainHead) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *PluginEvents_ChainHead) GetParentHash() string {
+	if m != nil {
+		return m.ParentHash
+	}
+	return ""
+}
+
+func (m *PluginEvents_ChainHead) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *PluginEvents_ChainHead) GetTxHashes() []string {
+	if m != nil {
+		return m.TxHashes
+	}
+	return nil
+}
+
+// *
+// New transactions have entered the transaction pool
+type PluginEvents_TxPool struct {
+	// hashes of the transactions, in hex
+	TxHashes             []string `protobuf:"bytes,1,rep,name=txHashes,proto3" json:"txHashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginEvents_TxPool) Reset()         { *m = PluginEvents_TxPool{} }
+func (m *PluginEvents_TxPool) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_TxPool) ProtoMessage()    {}
+func (*PluginEvents_TxPool) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 4}
+}
+
+func (m *PluginEvents_TxPool) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_TxPool.Unmarshal(m, b)
+}
+func (m *PluginEvents_TxPool) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_TxPool.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_TxPool) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_TxPool.Merge(m, src)
+}
+func (m *PluginEvents_TxPool) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_TxPool.Size(m)
+}
+func (m *PluginEvents_TxPool) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_TxPool.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_TxPool proto.InternalMessageInfo
+
+func (m *PluginEvents_TxPool) GetTxHashes() []string {
+	if m != nil {
+		return m.TxHashes
+	}
+	return nil
+}
+
+// *
+// An organization, node, account or role has been added or updated
+// as per contract-based permissioning
+type PluginEvents_Permission struct {
+	// one of: org, node, account, role
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// details of the entity in JSON
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginEvents_Permission) Reset()         { *m = PluginEvents_Permission{} }
+func (m *PluginEvents_Permission) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_Permission) ProtoMessage()    {}
+func (*PluginEvents_Permission) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 5}
+}
+
+func (m *PluginEvents_Permission) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_Permission.Unmarshal(m, b)
+}
+func (m *PluginEvents_Permission) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_Permission.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_Permission) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_Permission.Merge(m, src)
+}
+func (m *PluginEvents_Permission) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_Permission.Size(m)
+}
+func (m *PluginEvents_Permission) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_Permission.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_Permission proto.InternalMessageInfo
+
+func (m *PluginEvents_Permission) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *PluginEvents_Permission) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// *
+// Only the field matching the type is set
+type PluginEvents_Event struct {
+	Type                 PluginEvents_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=proto_common.PluginEvents_Type" json:"type,omitempty"`
+	ChainHead            *PluginEvents_ChainHead  `protobuf:"bytes,2,opt,name=chainHead,proto3" json:"chainHead,omitempty"`
+	TxPool               *PluginEvents_TxPool     `protobuf:"bytes,3,opt,name=txPool,proto3" json:"txPool,omitempty"`
+	Permission           *PluginEvents_Permission `protobuf:"bytes,4,opt,name=permission,proto3" json:"permission,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *PluginEvents_Event) Reset()         { *m = PluginEvents_Event{} }
+func (m *PluginEvents_Event) String() string { return proto.CompactTextString(m) }
+func (*PluginEvents_Event) ProtoMessage()    {}
+func (*PluginEvents_Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f22242cb04491f9, []int{0, 6}
+}
+
+func (m *PluginEvents_Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginEvents_Event.Unmarshal(m, b)
+}
+func (m *PluginEvents_Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginEvents_Event.Marshal(b, m, deterministic)
+}
+func (m *PluginEvents_Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginEvents_Event.Merge(m, src)
+}
+func (m *PluginEvents_Event) XXX_Size() int {
+	return xxx_messageInfo_PluginEvents_Event.Size(m)
+}
+func (m *PluginEvents_Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginEvents_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginEvents_Event proto.InternalMessageInfo
+
+func (m *PluginEvents_Event) GetType() PluginEvents_Type {
+	if m != nil {
+		return m.Type
+	}
+	return PluginEvents_CHAIN_HEAD
+}
+
+func (m *PluginEvents_Event) GetChainHead() *PluginEvents_ChainHead {
+	if m != nil {
+		return m.ChainHead
+	}
+	return nil
+}
+
+func (m *PluginEvents_Event) GetTxPool() *PluginEvents_TxPool {
+	if m != nil {
+		return m.TxPool
+	}
+	return nil
+}
+
+func (m *PluginEvents_Event) GetPermission() *PluginEvents_Permission {
+	if m != nil {
+		return m.Permission
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("proto_common.PluginEvents_Type", PluginEvents_Type_name, PluginEvents_Type_value)
+	proto.RegisterType((*PluginEvents)(nil), "proto_common.PluginEvents")
+	proto.RegisterType((*PluginEvents_ConnectRequest)(nil), "proto_common.PluginEvents.ConnectRequest")
+	proto.RegisterType((*PluginEvents_ConnectResponse)(nil), "proto_common.PluginEvents.ConnectResponse")
+	proto.RegisterType((*PluginEvents_SubscribeRequest)(nil), "proto_common.PluginEvents.SubscribeRequest")
+	proto.RegisterType((*PluginEvents_ChainHead)(nil), "proto_common.PluginEvents.ChainHead")
+	proto.RegisterType((*PluginEvents_TxPool)(nil), "proto_common.PluginEvents.TxPool")
+	proto.RegisterType((*PluginEvents_Permission)(nil), "proto_common.PluginEvents.Permission")
+	proto.RegisterType((*PluginEvents_Event)(nil), "proto_common.PluginEvents.Event")
+}
+
+func init() {
+	proto.RegisterFile("events.proto", fileDescriptor_8f22242cb04491f9)
+}
+
+var fileDescriptor_8f22242cb04491f9 = []byte{
+	// 507 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0xcd, 0x8e, 0xd3, 0x30,
+	0x10, 0x26, 0xdb, 0x3f, 0x32, 0x2d, 0xa5, 0xf8, 0x00, 0x91, 0x85, 0xa0, 0xac, 0x16, 0xa9, 0xfc,
+	0x28, 0xa0, 0x54, 0x1c, 0xe0, 0xb6, 0x2d, 0x95, 0x1a, 0x09, 0xb6, 0x91, 0xdb, 0x03, 0x42, 0x48,
+	0x25, 0x49, 0x2d, 0x1a, 0x6d, 0x63, 0x67, 0x6d, 0x67, 0xb5, 0xbd, 0x70, 0xe0, 0x0d, 0x78, 0x2d,
+	0x9e, 0x0a, 0xc5, 0x69, 0xd3, 0x74, 0x0f, 0xd1, 0x9e, 0xec, 0x99, 0xf9, 0xbe, 0xf9, 0xf9, 0xec,
+	0x81, 0x0e, 0xbd, 0xa6, 0x4c, 0x49, 0x3b, 0x11, 0x5c, 0x71, 0xd4, 0xd1, 0xc7, 0x32, 0xe4, 0x71,
+	0xcc, 0xd9, 0xe9, 0xbf, 0x06, 0x74, 0xbc, 0x4d, 0xfa, 0x2b, 0x62, 0x13, 0x0d, 0xc2, 0x6f, 0xa1,
+	0x3b, 0xe6, 0x8c, 0xd1, 0x50, 0x11, 0x7a, 0x95, 0x52, 0xa9, 0x10, 0x86, 0xfb, 0x81, 0xe0, 0x97,
+	0x54, 0xb8, 0x2b, 0xcb, 0xe8, 0x1b, 0x83, 0x07, 0xa4, 0xb0, 0xf1, 0x23, 0x78, 0x58, 0xa0, 0x65,
+	0xc2, 0x99, 0xa4, 0xd8, 0x85, 0xde, 0x3c, 0x0d, 0x64, 0x28, 0xa2, 0x80, 0xee, 0x53, 0x7c, 0x80,
+	0x86, 0xda, 0x26, 0x54, 0x5a, 0x46, 0xbf, 0x36, 0xe8, 0x3a, 0xcf, 0xed, 0x72, 0x0f, 0x76, 0xb9,
+	0xbe, 0xbd, 0xd8, 0x26, 0x94, 0xe4, 0x68, 0xfc, 0xd7, 0x00, 0x73, 0xbc, 0xf6, 0x23, 0x36, 0xa5,
+	0xfe, 0x0a, 0x3d, 0x86, 0x26, 0x4b, 0xe3, 0x80, 0x0a, 0xdd, 0x45, 0x9d, 0xec, 0x2c, 0x84, 0xa0,
+	0xbe, 0xf6, 0xe5, 0xda, 0x3a, 0xe9, 0x1b, 0x03, 0x93, 0xe8, 0x3b, 0x7a, 0x06, 0x90, 0xf8, 0x82,
+	0x32, 0x35, 0xcd, 0x22, 0x35, 0x1d, 0x29, 0x79, 0xd0, 0x53, 0x30, 0x55, 0x14, 0x53, 0xa9, 0xfc,
+	0x38, 0xb1, 0xea, 0x3a, 0xdd, 0xc1, 0x91, 0x4d, 0xac, 0x6e, 0x32, 0x1c, 0x95, 0x56, 0xa3, 0x5f,
+	0x1b, 0x98, 0xa4, 0xb0, 0xf1, 0x19, 0x34, 0x17, 0x37, 0x1e, 0xe7, 0x9b, 0x23, 0x94, 0x71, 0x0b,
+	0xf5, 0x09, 0xc0, 0xa3, 0x22, 0x8e, 0xa4, 0x8c, 0x38, 0xcb, 0x3a, 0xbc, 0x8c, 0x58, 0xae, 0x9e,
+	0x49, 0xf4, 0x1d, 0x59, 0xd0, 0x4a, 0xfc, 0xed, 0x86, 0xfb, 0x2b, 0xdd, 0x78, 0x87, 0xec, 0x4d,
+	0xfc, 0xe7, 0x04, 0x1a, 0x5a, 0x0c, 0x34, 0x84, 0x7a, 0x26, 0x84, 0xe6, 0xdd, 0x41, 0x35, 0x0d,
+	0x46, 0x23, 0x30, 0xc3, 0xbd, 0x66, 0x3a, 0x75, 0xdb, 0x39, 0xab, 0x60, 0x16, 0xfa, 0x92, 0x03,
+	0x0d, 0x7d, 0x84, 0xa6, 0xd2, 0x43, 0x6a, 0xe9, 0xda, 0xce, 0x8b, 0xaa, 0xd2, 0x1a, 0x48, 0x76,
+	0x04, 0x34, 0x01, 0x48, 0x8a, 0xc9, 0xb5, 0xb4, 0x6d, 0xe7, 0x65, 0x05, 0xfd, 0x20, 0x13, 0x29,
+	0x11, 0x4f, 0x87, 0x50, 0xcf, 0x66, 0x42, 0x5d, 0x80, 0xf1, 0xf4, 0xdc, 0xbd, 0x58, 0x4e, 0x27,
+	0xe7, 0x9f, 0x7b, 0xf7, 0x50, 0x1b, 0x5a, 0x8b, 0x6f, 0x4b, 0x6f, 0x36, 0xfb, 0xd2, 0x33, 0xb2,
+	0xa0, 0x37, 0x21, 0x5f, 0xdd, 0xf9, 0xdc, 0x9d, 0x5d, 0xf4, 0x4e, 0x1c, 0x01, 0xa8, 0x94, 0x7b,
+	0x4e, 0xc5, 0x75, 0x14, 0x52, 0xf4, 0x03, 0xcc, 0xe2, 0x43, 0xa2, 0x37, 0x15, 0xad, 0xdc, 0xfe,
+	0xb6, 0xb8, 0x5f, 0x01, 0xd6, 0xc7, 0x7b, 0xc3, 0xf9, 0x0d, 0xb8, 0xe4, 0x1f, 0x73, 0x26, 0xd3,
+	0x98, 0x8a, 0x7d, 0xed, 0x9f, 0xd0, 0xda, 0xed, 0x07, 0x7a, 0x55, 0xf5, 0x08, 0x47, 0x1b, 0x87,
+	0x5f, 0xdf, 0x05, 0x9a, 0xaf, 0xdb, 0xe8, 0x1d, 0x3c, 0x09, 0x79, 0x6c, 0x5f, 0xa5, 0x5c, 0xa4,
+	0xb1, 0x9d, 0x68, 0x68, 0x4e, 0x1f, 0x35, 0x73, 0xca, 0xf7, 0xa3, 0x8d, 0x0f, 0x9a, 0xda, 0x1a,
+	0xfe, 0x1f, 0x00, 0xb9, 0x0d, 0xd2, 0xd4, 0x16, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginEventServiceClient is the client API for PluginEventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginEventServiceClient interface {
+	Subscribe(ctx context.Context, in *PluginEvents_SubscribeRequest, opts ...grpc.CallOption) (PluginEventService_SubscribeClient, error)
+}
+
+type pluginEventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginEventServiceClient(cc grpc.ClientConnInterface) PluginEventServiceClient {
+	return &pluginEventServiceClient{cc}
+}
+
+func (c *pluginEventServiceClient) Subscribe(ctx context.Context, in *PluginEvents_SubscribeRequest, opts ...grpc.CallOption) (PluginEventService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PluginEventService_serviceDesc.Streams[0], "/proto_common.PluginEventService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pluginEventServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PluginEventService_SubscribeClient interface {
+	Recv() (*PluginEvents_Event, error)
+	grpc.ClientStream
+}
+
+type pluginEventServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *pluginEventServiceSubscribeClient) Recv() (*PluginEvents_Event, error) {
+	m := new(PluginEvents_Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PluginEventServiceServer is the server API for PluginEventService service.
+type PluginEventServiceServer interface {
+	Subscribe(*PluginEvents_SubscribeRequest, PluginEventService_SubscribeServer) error
+}
+
+// UnimplementedPluginEventServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginEventServiceServer struct {
+}
+
+func (*UnimplementedPluginEventServiceServer) Subscribe(req *PluginEvents_SubscribeRequest, srv PluginEventService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+
+func RegisterPluginEventServiceServer(s *grpc.Server, srv PluginEventServiceServer) {
+	s.RegisterService(&_PluginEventService_serviceDesc, srv)
+}
+
+func _PluginEventService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PluginEvents_SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PluginEventServiceServer).Subscribe(m, &pluginEventServiceSubscribeServer{stream})
+}
+
+type PluginEventService_SubscribeServer interface {
+	Send(*PluginEvents_Event) error
+	grpc.ServerStream
+}
+
+type pluginEventServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *pluginEventServiceSubscribeServer) Send(m *PluginEvents_Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _PluginEventService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginEventService",
+	HandlerType: (*PluginEventServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _PluginEventService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "events.proto",
+}
+
+// PluginEventConsumerServiceClient is the client API for PluginEventConsumerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginEventConsumerServiceClient interface {
+	Connect(ctx context.Context, in *PluginEvents_ConnectRequest, opts ...grpc.CallOption) (*PluginEvents_ConnectResponse, error)
+}
+
+type pluginEventConsumerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginEventConsumerServiceClient(cc grpc.ClientConnInterface) PluginEventConsumerServiceClient {
+	return &pluginEventConsumerServiceClient{cc}
+}
+
+func (c *pluginEventConsumerServiceClient) Connect(ctx context.Context, in *PluginEvents_ConnectRequest, opts ...grpc.CallOption) (*PluginEvents_ConnectResponse, error) {
+	out := new(PluginEvents_ConnectResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginEventConsumerService/Connect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginEventConsumerServiceServer is the server API for PluginEventConsumerService service.
+type PluginEventConsumerServiceServer interface {
+	Connect(context.Context, *PluginEvents_ConnectRequest) (*PluginEvents_ConnectResponse, error)
+}
+
+// UnimplementedPluginEventConsumerServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginEventConsumerServiceServer struct {
+}
+
+func (*UnimplementedPluginEventConsumerServiceServer) Connect(ctx context.Context, req *PluginEvents_ConnectRequest) (*PluginEvents_ConnectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+
+func RegisterPluginEventConsumerServiceServer(s *grpc.Server, srv PluginEventConsumerServiceServer) {
+	s.RegisterService(&_PluginEventConsumerService_serviceDesc, srv)
+}
+
+func _PluginEventConsumerService_Connect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginEvents_ConnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginEventConsumerServiceServer).Connect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginEventConsumerService/Connect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginEventConsumerServiceServer).Connect(ctx, req.(*PluginEvents_ConnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginEventConsumerService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginEventConsumerService",
+	HandlerType: (*PluginEventConsumerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Connect",
+			Handler:    _PluginEventConsumerService_Connect_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "events.proto",
+}
@@ -0,0 +1,424 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: exporter.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// *
+// A wrapper message to logically group other messages
+type PluginExporter struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginExporter) Reset()         { *m = PluginExporter{} }
+func (m *PluginExporter) String() string { return proto.CompactTextString(m) }
+func (*PluginExporter) ProtoMessage()    {}
+func (*PluginExporter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a8826adc5babc285, []int{0}
+}
+
+func (m *PluginExporter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginExporter.Unmarshal(m, b)
+}
+func (m *PluginExporter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginExporter.Marshal(b, m, deterministic)
+}
+func (m *PluginExporter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginExporter.Merge(m, src)
+}
+func (m *PluginExporter) XXX_Size() int {
+	return xxx_messageInfo_PluginExporter.Size(m)
+}
+func (m *PluginExporter) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginExporter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginExporter proto.InternalMessageInfo
+
+type PluginExporter_DescribeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginExporter_DescribeRequest) Reset()         { *m = PluginExporter_DescribeRequest{} }
+func (m *PluginExporter_DescribeRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginExporter_DescribeRequest) ProtoMessage()    {}
+func (*PluginExporter_DescribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a8826adc5babc285, []int{0, 0}
+}
+
+func (m *PluginExporter_DescribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginExporter_DescribeRequest.Unmarshal(m, b)
+}
+func (m *PluginExporter_DescribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginExporter_DescribeRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginExporter_DescribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginExporter_DescribeRequest.Merge(m, src)
+}
+func (m *PluginExporter_DescribeRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginExporter_DescribeRequest.Size(m)
+}
+func (m *PluginExporter_DescribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginExporter_DescribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginExporter_DescribeRequest proto.InternalMessageInfo
+
+type PluginExporter_DescribeResponse struct {
+	// private state identifiers for which the plugin is authorized to receive private receipts.
+	//
+	// `geth` ignores the ones which are not managed by the node
+	PrivateStateIdentifiers []string `protobuf:"bytes,1,rep,name=privateStateIdentifiers,proto3" json:"privateStateIdentifiers,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *PluginExporter_DescribeResponse) Reset()         { *m = PluginExporter_DescribeResponse{} }
+func (m *PluginExporter_DescribeResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginExporter_DescribeResponse) ProtoMessage()    {}
+func (*PluginExporter_DescribeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a8826adc5babc285, []int{0, 1}
+}
+
+func (m *PluginExporter_DescribeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginExporter_DescribeResponse.Unmarshal(m, b)
+}
+func (m *PluginExporter_DescribeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginExporter_DescribeResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginExporter_DescribeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginExporter_DescribeResponse.Merge(m, src)
+}
+func (m *PluginExporter_DescribeResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginExporter_DescribeResponse.Size(m)
+}
+func (m *PluginExporter_DescribeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginExporter_DescribeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginExporter_DescribeResponse proto.InternalMessageInfo
+
+func (m *PluginExporter_DescribeResponse) GetPrivateStateIdentifiers() []string {
+	if m != nil {
+		return m.PrivateStateIdentifiers
+	}
+	return nil
+}
+
+// *
+// Receipts of private transactions in a block from the perspective of a private state
+type PluginExporter_PrivateReceipts struct {
+	PrivateStateIdentifier string `protobuf:"bytes,1,opt,name=privateStateIdentifier,proto3" json:"privateStateIdentifier,omitempty"`
+	// JSON-encoded receipts
+	Receipts             [][]byte `protobuf:"bytes,2,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginExporter_PrivateReceipts) Reset()         { *m = PluginExporter_PrivateReceipts{} }
+func (m *PluginExporter_PrivateReceipts) String() string { return proto.CompactTextString(m) }
+func (*PluginExporter_PrivateReceipts) ProtoMessage()    {}
+func (*PluginExporter_PrivateReceipts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a8826adc5babc285, []int{0, 2}
+}
+
+func (m *PluginExporter_PrivateReceipts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginExporter_PrivateReceipts.Unmarshal(m, b)
+}
+func (m *PluginExporter_PrivateReceipts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginExporter_PrivateReceipts.Marshal(b, m, deterministic)
+}
+func (m *PluginExporter_PrivateReceipts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginExporter_PrivateReceipts.Merge(m, src)
+}
+func (m *PluginExporter_PrivateReceipts) XXX_Size() int {
+	return xxx_messageInfo_PluginExporter_PrivateReceipts.Size(m)
+}
+func (m *PluginExporter_PrivateReceipts) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginExporter_PrivateReceipts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginExporter_PrivateReceipts proto.InternalMessageInfo
+
+func (m *PluginExporter_PrivateReceipts) GetPrivateStateIdentifier() string {
+	if m != nil {
+		return m.PrivateStateIdentifier
+	}
+	return ""
+}
+
+func (m *PluginExporter_PrivateReceipts) GetReceipts() [][]byte {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+type PluginExporter_ExportBlockRequest struct {
+	// RLP-encoded block
+	Block []byte `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	// JSON-encoded receipts of public transactions in the block
+	Receipts [][]byte `protobuf:"bytes,2,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	// receipts of private transactions for each authorized private state
+	PrivateReceipts      []*PluginExporter_PrivateReceipts `protobuf:"bytes,3,rep,name=privateReceipts,proto3" json:"privateReceipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
+	XXX_unrecognized     []byte                            `json:"-"`
+	XXX_sizecache        int32                             `json:"-"`
+}
+
+func (m *PluginExporter_ExportBlockRequest) Reset()         { *m = PluginExporter_ExportBlockRequest{} }
+func (m *PluginExporter_ExportBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginExporter_ExportBlockRequest) ProtoMessage()    {}
+func (*PluginExporter_ExportBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a8826adc5babc285, []int{0, 3}
+}
+
+func (m *PluginExporter_ExportBlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginExporter_ExportBlockRequest.Unmarshal(m, b)
+}
+func (m *PluginExporter_ExportBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginExporter_ExportBlockRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginExporter_ExportBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginExporter_ExportBlockRequest.Merge(m, src)
+}
+func (m *PluginExporter_ExportBlockRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginExporter_ExportBlockRequest.Size(m)
+}
+func (m *PluginExporter_ExportBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginExporter_ExportBlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginExporter_ExportBlockRequest proto.InternalMessageInfo
+
+func (m *PluginExporter_ExportBlockRequest) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *PluginExporter_ExportBlockRequest) GetReceipts() [][]byte {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+func (m *PluginExporter_ExportBlockRequest) GetPrivateReceipts() []*PluginExporter_PrivateReceipts {
+	if m != nil {
+		return m.PrivateReceipts
+	}
+	return nil
+}
+
+type PluginExporter_ExportBlockResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginExporter_ExportBlockResponse) Reset()         { *m = PluginExporter_ExportBlockResponse{} }
+func (m *PluginExporter_ExportBlockResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginExporter_ExportBlockResponse) ProtoMessage()    {}
+func (*PluginExporter_ExportBlockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a8826adc5babc285, []int{0, 4}
+}
+
+func (m *PluginExporter_ExportBlockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginExporter_ExportBlockResponse.Unmarshal(m, b)
+}
+func (m *PluginExporter_ExportBlockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginExporter_ExportBlockResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginExporter_ExportBlockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginExporter_ExportBlockResponse.Merge(m, src)
+}
+func (m *PluginExporter_ExportBlockResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginExporter_ExportBlockResponse.Size(m)
+}
+func (m *PluginExporter_ExportBlockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginExporter_ExportBlockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginExporter_ExportBlockResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*PluginExporter)(nil), "proto_common.PluginExporter")
+	proto.RegisterType((*PluginExporter_DescribeRequest)(nil), "proto_common.PluginExporter.DescribeRequest")
+	proto.RegisterType((*PluginExporter_DescribeResponse)(nil), "proto_common.PluginExporter.DescribeResponse")
+	proto.RegisterType((*PluginExporter_PrivateReceipts)(nil), "proto_common.PluginExporter.PrivateReceipts")
+	proto.RegisterType((*PluginExporter_ExportBlockRequest)(nil), "proto_common.PluginExporter.ExportBlockRequest")
+	proto.RegisterType((*PluginExporter_ExportBlockResponse)(nil), "proto_common.PluginExporter.ExportBlockResponse")
+}
+
+func init() {
+	proto.RegisterFile("exporter.proto", fileDescriptor_a8826adc5babc285)
+}
+
+var fileDescriptor_a8826adc5babc285 = []byte{
+	// 320 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x41, 0x4f, 0xf2, 0x40,
+	0x10, 0x4d, 0xbf, 0xe6, 0x33, 0x30, 0x10, 0xd0, 0x55, 0xa4, 0xd9, 0x53, 0xe3, 0xa9, 0x07, 0x5d,
+	0x15, 0x13, 0xe3, 0x99, 0xe8, 0xc1, 0xc4, 0x03, 0x59, 0x12, 0x0f, 0x5e, 0x0c, 0xac, 0x23, 0xd9,
+	0x48, 0xbb, 0xcb, 0xee, 0x96, 0xf8, 0x33, 0xfc, 0x05, 0xfe, 0x48, 0x7f, 0x81, 0xb1, 0x5b, 0x94,
+	0xa2, 0x10, 0x4e, 0xcd, 0xeb, 0xcc, 0x7b, 0x33, 0xf3, 0xde, 0x42, 0x0b, 0x5f, 0xb5, 0x32, 0x0e,
+	0x0d, 0xd3, 0x46, 0x39, 0x45, 0x9a, 0xc5, 0xe7, 0x51, 0xa8, 0x34, 0x55, 0xd9, 0xd1, 0x5b, 0x08,
+	0xad, 0xc1, 0x34, 0x9f, 0xc8, 0xec, 0xa6, 0x6c, 0xa3, 0x7b, 0xd0, 0xbe, 0x46, 0x2b, 0x8c, 0x1c,
+	0x23, 0xc7, 0x59, 0x8e, 0xd6, 0xd1, 0x3b, 0xd8, 0xfd, 0xf9, 0x65, 0xb5, 0xca, 0x2c, 0x92, 0x2b,
+	0xe8, 0x6a, 0x23, 0xe7, 0x23, 0x87, 0x43, 0x37, 0x72, 0x78, 0xfb, 0x84, 0x99, 0x93, 0xcf, 0x12,
+	0x8d, 0x8d, 0x82, 0x38, 0x4c, 0xea, 0x7c, 0x5d, 0x99, 0x22, 0xb4, 0x07, 0xbe, 0xc4, 0x51, 0xa0,
+	0xd4, 0xce, 0x92, 0x4b, 0x38, 0xfc, 0xbb, 0x3b, 0x0a, 0xe2, 0x20, 0xa9, 0xf3, 0x35, 0x55, 0x42,
+	0xa1, 0x66, 0x4a, 0x8d, 0xe8, 0x5f, 0x1c, 0x26, 0x4d, 0xfe, 0x8d, 0xe9, 0x7b, 0x00, 0xc4, 0x1f,
+	0xd5, 0x9f, 0x2a, 0xf1, 0x52, 0xde, 0x42, 0x0e, 0xe0, 0xff, 0xf8, 0x0b, 0x17, 0xca, 0x4d, 0xee,
+	0xc1, 0x26, 0x21, 0x72, 0x0f, 0x6d, 0x5d, 0xdd, 0x37, 0x0a, 0xe3, 0x30, 0x69, 0xf4, 0x8e, 0xd9,
+	0xb2, 0x97, 0xac, 0xea, 0x23, 0x5b, 0xb9, 0x91, 0xaf, 0x8a, 0xd0, 0x0e, 0xec, 0x57, 0xf6, 0xf3,
+	0xc6, 0xf6, 0x3e, 0x02, 0xe8, 0x54, 0xa5, 0x86, 0x68, 0xe6, 0x52, 0x20, 0x99, 0x40, 0x6d, 0x11,
+	0x03, 0xd9, 0x3c, 0x7b, 0x35, 0xc0, 0x93, 0x2d, 0xbb, 0xcb, 0x6c, 0x35, 0x34, 0x96, 0x36, 0x23,
+	0xa7, 0x1b, 0xd9, 0xbf, 0x3d, 0xa6, 0x67, 0xdb, 0x13, 0xfc, 0xc4, 0xfe, 0x39, 0x74, 0x85, 0x4a,
+	0xd9, 0x2c, 0x57, 0x26, 0x4f, 0x99, 0x2e, 0x08, 0x5e, 0xa4, 0x5f, 0x5b, 0x10, 0x1f, 0x2a, 0x4f,
+	0x77, 0xbc, 0x53, 0xa0, 0x8b, 0xcf, 0x01, 0x00, 0xff, 0xa9, 0x81, 0xd0, 0xe1, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginExporterServiceClient is the client API for PluginExporterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginExporterServiceClient interface {
+	Describe(ctx context.Context, in *PluginExporter_DescribeRequest, opts ...grpc.CallOption) (*PluginExporter_DescribeResponse, error)
+	ExportBlock(ctx context.Context, in *PluginExporter_ExportBlockRequest, opts ...grpc.CallOption) (*PluginExporter_ExportBlockResponse, error)
+}
+
+type pluginExporterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginExporterServiceClient(cc grpc.ClientConnInterface) PluginExporterServiceClient {
+	return &pluginExporterServiceClient{cc}
+}
+
+func (c *pluginExporterServiceClient) Describe(ctx context.Context, in *PluginExporter_DescribeRequest, opts ...grpc.CallOption) (*PluginExporter_DescribeResponse, error) {
+	out := new(PluginExporter_DescribeResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginExporterService/Describe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginExporterServiceClient) ExportBlock(ctx context.Context, in *PluginExporter_ExportBlockRequest, opts ...grpc.CallOption) (*PluginExporter_ExportBlockResponse, error) {
+	out := new(PluginExporter_ExportBlockResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginExporterService/ExportBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginExporterServiceServer is the server API for PluginExporterService service.
+type PluginExporterServiceServer interface {
+	Describe(context.Context, *PluginExporter_DescribeRequest) (*PluginExporter_DescribeResponse, error)
+	ExportBlock(context.Context, *PluginExporter_ExportBlockRequest) (*PluginExporter_ExportBlockResponse, error)
+}
+
+// UnimplementedPluginExporterServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginExporterServiceServer struct {
+}
+
+func (*UnimplementedPluginExporterServiceServer) Describe(ctx context.Context, req *PluginExporter_DescribeRequest) (*PluginExporter_DescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (*UnimplementedPluginExporterServiceServer) ExportBlock(ctx context.Context, req *PluginExporter_ExportBlockRequest) (*PluginExporter_ExportBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportBlock not implemented")
+}
+
+func RegisterPluginExporterServiceServer(s *grpc.Server, srv PluginExporterServiceServer) {
+	s.RegisterService(&_PluginExporterService_serviceDesc, srv)
+}
+
+func _PluginExporterService_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginExporter_DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginExporterServiceServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginExporterService/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginExporterServiceServer).Describe(ctx, req.(*PluginExporter_DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginExporterService_ExportBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginExporter_ExportBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginExporterServiceServer).ExportBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginExporterService/ExportBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginExporterServiceServer).ExportBlock(ctx, req.(*PluginExporter_ExportBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginExporterService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginExporterService",
+	HandlerType: (*PluginExporterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _PluginExporterService_Describe_Handler,
+		},
+		{
+			MethodName: "ExportBlock",
+			Handler:    _PluginExporterService_ExportBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "exporter.proto",
+}
@@ -0,0 +1,332 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: metrics.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// *
+// Type of a metric which determines how `geth` re-exports it
+type PluginMetrics_Type int32
+
+const (
+	PluginMetrics_GAUGE         PluginMetrics_Type = 0
+	PluginMetrics_GAUGE_FLOAT64 PluginMetrics_Type = 1
+	PluginMetrics_COUNTER       PluginMetrics_Type = 2
+)
+
+var PluginMetrics_Type_name = map[int32]string{
+	0: "GAUGE",
+	1: "GAUGE_FLOAT64",
+	2: "COUNTER",
+}
+
+var PluginMetrics_Type_value = map[string]int32{
+	"GAUGE":         0,
+	"GAUGE_FLOAT64": 1,
+	"COUNTER":       2,
+}
+
+func (x PluginMetrics_Type) String() string {
+	return proto.EnumName(PluginMetrics_Type_name, int32(x))
+}
+
+func (PluginMetrics_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{0, 0}
+}
+
+// *
+// A wrapper message to logically group other messages
+type PluginMetrics struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginMetrics) Reset()         { *m = PluginMetrics{} }
+func (m *PluginMetrics) String() string { return proto.CompactTextString(m) }
+func (*PluginMetrics) ProtoMessage()    {}
+func (*PluginMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{0}
+}
+
+func (m *PluginMetrics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginMetrics.Unmarshal(m, b)
+}
+func (m *PluginMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginMetrics.Marshal(b, m, deterministic)
+}
+func (m *PluginMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginMetrics.Merge(m, src)
+}
+func (m *PluginMetrics) XXX_Size() int {
+	return xxx_messageInfo_PluginMetrics.Size(m)
+}
+func (m *PluginMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginMetrics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginMetrics proto.InternalMessageInfo
+
+// *
+// A single metric reported by the plugin
+type PluginMetrics_Metric struct {
+	// name of the metric, e.g.: `requests/total`. `geth` prefixes it with `plugin/<interface>/`
+	Name string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type PluginMetrics_Type `protobuf:"varint,2,opt,name=type,proto3,enum=proto_common.PluginMetrics_Type" json:"type,omitempty"`
+	// current value of the metric. It is truncated to an integer for `GAUGE` and `COUNTER`
+	Value                float64  `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginMetrics_Metric) Reset()         { *m = PluginMetrics_Metric{} }
+func (m *PluginMetrics_Metric) String() string { return proto.CompactTextString(m) }
+func (*PluginMetrics_Metric) ProtoMessage()    {}
+func (*PluginMetrics_Metric) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{0, 0}
+}
+
+func (m *PluginMetrics_Metric) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginMetrics_Metric.Unmarshal(m, b)
+}
+func (m *PluginMetrics_Metric) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginMetrics_Metric.Marshal(b, m, deterministic)
+}
+func (m *PluginMetrics_Metric) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginMetrics_Metric.Merge(m, src)
+}
+func (m *PluginMetrics_Metric) XXX_Size() int {
+	return xxx_messageInfo_PluginMetrics_Metric.Size(m)
+}
+func (m *PluginMetrics_Metric) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginMetrics_Metric.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginMetrics_Metric proto.InternalMessageInfo
+
+func (m *PluginMetrics_Metric) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PluginMetrics_Metric) GetType() PluginMetrics_Type {
+	if m != nil {
+		return m.Type
+	}
+	return PluginMetrics_GAUGE
+}
+
+func (m *PluginMetrics_Metric) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type PluginMetrics_Request struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginMetrics_Request) Reset()         { *m = PluginMetrics_Request{} }
+func (m *PluginMetrics_Request) String() string { return proto.CompactTextString(m) }
+func (*PluginMetrics_Request) ProtoMessage()    {}
+func (*PluginMetrics_Request) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{0, 1}
+}
+
+func (m *PluginMetrics_Request) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginMetrics_Request.Unmarshal(m, b)
+}
+func (m *PluginMetrics_Request) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginMetrics_Request.Marshal(b, m, deterministic)
+}
+func (m *PluginMetrics_Request) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginMetrics_Request.Merge(m, src)
+}
+func (m *PluginMetrics_Request) XXX_Size() int {
+	return xxx_messageInfo_PluginMetrics_Request.Size(m)
+}
+func (m *PluginMetrics_Request) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginMetrics_Request.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginMetrics_Request proto.InternalMessageInfo
+
+type PluginMetrics_Response struct {
+	Metrics              []*PluginMetrics_Metric `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *PluginMetrics_Response) Reset()         { *m = PluginMetrics_Response{} }
+func (m *PluginMetrics_Response) String() string { return proto.CompactTextString(m) }
+func (*PluginMetrics_Response) ProtoMessage()    {}
+func (*PluginMetrics_Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{0, 2}
+}
+
+func (m *PluginMetrics_Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginMetrics_Response.Unmarshal(m, b)
+}
+func (m *PluginMetrics_Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginMetrics_Response.Marshal(b, m, deterministic)
+}
+func (m *PluginMetrics_Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginMetrics_Response.Merge(m, src)
+}
+func (m *PluginMetrics_Response) XXX_Size() int {
+	return xxx_messageInfo_PluginMetrics_Response.Size(m)
+}
+func (m *PluginMetrics_Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginMetrics_Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginMetrics_Response proto.InternalMessageInfo
+
+func (m *PluginMetrics_Response) GetMetrics() []*PluginMetrics_Metric {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("proto_common.PluginMetrics_Type", PluginMetrics_Type_name, PluginMetrics_Type_value)
+	proto.RegisterType((*PluginMetrics)(nil), "proto_common.PluginMetrics")
+	proto.RegisterType((*PluginMetrics_Metric)(nil), "proto_common.PluginMetrics.Metric")
+	proto.RegisterType((*PluginMetrics_Request)(nil), "proto_common.PluginMetrics.Request")
+	proto.RegisterType((*PluginMetrics_Response)(nil), "proto_common.PluginMetrics.Response")
+}
+
+func init() {
+	proto.RegisterFile("metrics.proto", fileDescriptor_6039342a2ba47b72)
+}
+
+var fileDescriptor_6039342a2ba47b72 = []byte{
+	// 282 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0xdd, 0x36, 0x6d, 0xcc, 0xd4, 0x48, 0x1c, 0x44, 0x43, 0x4e, 0x21, 0x7a, 0xc8, 0x69,
+	0xd1, 0x58, 0x3c, 0x79, 0x69, 0x4b, 0xad, 0x07, 0xb5, 0xb2, 0xa4, 0x17, 0x2f, 0xa5, 0x86, 0x45,
+	0x0b, 0xd9, 0x6c, 0x9a, 0x3f, 0x42, 0x8f, 0x7e, 0x73, 0x71, 0x37, 0x05, 0x73, 0xc9, 0x69, 0xde,
+	0x83, 0xdf, 0xbc, 0x99, 0x61, 0xc0, 0x16, 0xbc, 0x2a, 0xb6, 0x49, 0x49, 0xf3, 0x42, 0x56, 0x12,
+	0x4f, 0x54, 0x59, 0x27, 0x52, 0x08, 0x99, 0x05, 0x3f, 0x3d, 0xb0, 0xdf, 0xd2, 0xfa, 0x73, 0x9b,
+	0xbd, 0x68, 0xca, 0xfb, 0x82, 0xa1, 0x96, 0x88, 0x60, 0x64, 0x1b, 0xc1, 0x5d, 0xe2, 0x93, 0xd0,
+	0x62, 0x4a, 0xe3, 0x18, 0x8c, 0x6a, 0x9f, 0x73, 0xb7, 0xe7, 0x93, 0xf0, 0x34, 0xf2, 0xe9, 0xff,
+	0x30, 0xda, 0x0a, 0xa2, 0xf1, 0x3e, 0xe7, 0x4c, 0xd1, 0x78, 0x0e, 0x83, 0xef, 0x4d, 0x5a, 0x73,
+	0xb7, 0xef, 0x93, 0x90, 0x30, 0x6d, 0x3c, 0x0b, 0x4c, 0xc6, 0x77, 0x35, 0x2f, 0x2b, 0xef, 0x09,
+	0x8e, 0x19, 0x2f, 0x73, 0x99, 0x95, 0x1c, 0x1f, 0xc0, 0x6c, 0x36, 0x76, 0x89, 0xdf, 0x0f, 0x47,
+	0x51, 0xd0, 0x35, 0x45, 0x57, 0x76, 0x68, 0x09, 0x6e, 0xc1, 0xf8, 0x1b, 0x8c, 0x16, 0x0c, 0x16,
+	0x93, 0xd5, 0x62, 0xee, 0x1c, 0xe1, 0x19, 0xd8, 0x4a, 0xae, 0x1f, 0x9f, 0x97, 0x93, 0xf8, 0x7e,
+	0xec, 0x10, 0x1c, 0x81, 0x39, 0x5b, 0xae, 0x5e, 0xe3, 0x39, 0x73, 0x7a, 0x51, 0x06, 0x17, 0xad,
+	0xcc, 0x99, 0x4c, 0x53, 0x9e, 0x54, 0xb2, 0xc0, 0x18, 0xcc, 0xc6, 0xe0, 0x55, 0xd7, 0x12, 0x87,
+	0x33, 0xae, 0xbb, 0x21, 0x7d, 0xe0, 0xf4, 0x06, 0x2e, 0x13, 0x29, 0xe8, 0xae, 0x96, 0x45, 0x2d,
+	0x68, 0xae, 0x20, 0xdd, 0x38, 0x35, 0x1b, 0xf8, 0xbd, 0xf5, 0xa5, 0x8f, 0xa1, 0x72, 0x77, 0xbf,
+	0x03, 0x00, 0x1f, 0x3e, 0xd3, 0x2a, 0xcb, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginMetricsCollectorClient is the client API for PluginMetricsCollector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginMetricsCollectorClient interface {
+	Collect(ctx context.Context, in *PluginMetrics_Request, opts ...grpc.CallOption) (*PluginMetrics_Response, error)
+}
+
+type pluginMetricsCollectorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginMetricsCollectorClient(cc grpc.ClientConnInterface) PluginMetricsCollectorClient {
+	return &pluginMetricsCollectorClient{cc}
+}
+
+func (c *pluginMetricsCollectorClient) Collect(ctx context.Context, in *PluginMetrics_Request, opts ...grpc.CallOption) (*PluginMetrics_Response, error) {
+	out := new(PluginMetrics_Response)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginMetricsCollector/Collect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginMetricsCollectorServer is the server API for PluginMetricsCollector service.
+type PluginMetricsCollectorServer interface {
+	Collect(context.Context, *PluginMetrics_Request) (*PluginMetrics_Response, error)
+}
+
+// UnimplementedPluginMetricsCollectorServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginMetricsCollectorServer struct {
+}
+
+func (*UnimplementedPluginMetricsCollectorServer) Collect(ctx context.Context, req *PluginMetrics_Request) (*PluginMetrics_Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Collect not implemented")
+}
+
+func RegisterPluginMetricsCollectorServer(s *grpc.Server, srv PluginMetricsCollectorServer) {
+	s.RegisterService(&_PluginMetricsCollector_serviceDesc, srv)
+}
+
+func _PluginMetricsCollector_Collect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginMetrics_Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginMetricsCollectorServer).Collect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginMetricsCollector/Collect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginMetricsCollectorServer).Collect(ctx, req.(*PluginMetrics_Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginMetricsCollector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginMetricsCollector",
+	HandlerType: (*PluginMetricsCollectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Collect",
+			Handler:    _PluginMetricsCollector_Collect_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "metrics.proto",
+}
@@ -0,0 +1,347 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: nodepermission.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type PluginNodePermission_Direction int32
+
+const (
+	PluginNodePermission_INBOUND  PluginNodePermission_Direction = 0
+	PluginNodePermission_OUTBOUND PluginNodePermission_Direction = 1
+)
+
+var PluginNodePermission_Direction_name = map[int32]string{
+	0: "INBOUND",
+	1: "OUTBOUND",
+}
+
+var PluginNodePermission_Direction_value = map[string]int32{
+	"INBOUND":  0,
+	"OUTBOUND": 1,
+}
+
+func (x PluginNodePermission_Direction) String() string {
+	return proto.EnumName(PluginNodePermission_Direction_name, int32(x))
+}
+
+func (PluginNodePermission_Direction) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b1bdcf11b0995561, []int{0, 0}
+}
+
+// *
+// A wrapper message to logically group other messages
+type PluginNodePermission struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginNodePermission) Reset()         { *m = PluginNodePermission{} }
+func (m *PluginNodePermission) String() string { return proto.CompactTextString(m) }
+func (*PluginNodePermission) ProtoMessage()    {}
+func (*PluginNodePermission) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1bdcf11b0995561, []int{0}
+}
+
+func (m *PluginNodePermission) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginNodePermission.Unmarshal(m, b)
+}
+func (m *PluginNodePermission) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginNodePermission.Marshal(b, m, deterministic)
+}
+func (m *PluginNodePermission) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginNodePermission.Merge(m, src)
+}
+func (m *PluginNodePermission) XXX_Size() int {
+	return xxx_messageInfo_PluginNodePermission.Size(m)
+}
+func (m *PluginNodePermission) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginNodePermission.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginNodePermission proto.InternalMessageInfo
+
+// *
+// Describe a p2p connection attempt
+type PluginNodePermission_ConnectionRequest struct {
+	// enode ID of the remote node in hex
+	EnodeId string `protobuf:"bytes,1,opt,name=enodeId,proto3" json:"enodeId,omitempty"`
+	// full enode URL of the remote node
+	EnodeUrl  string                         `protobuf:"bytes,2,opt,name=enodeUrl,proto3" json:"enodeUrl,omitempty"`
+	Ip        string                         `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	TcpPort   uint32                         `protobuf:"varint,4,opt,name=tcpPort,proto3" json:"tcpPort,omitempty"`
+	RaftPort  uint32                         `protobuf:"varint,5,opt,name=raftPort,proto3" json:"raftPort,omitempty"`
+	Direction PluginNodePermission_Direction `protobuf:"varint,6,opt,name=direction,proto3,enum=proto_common.PluginNodePermission_Direction" json:"direction,omitempty"`
+	// organization of the remote node as per contract-based permissioning.
+	//
+	// Empty if permissioning is not enabled or the node doesn't belong to any organization
+	OrgId                string   `protobuf:"bytes,7,opt,name=orgId,proto3" json:"orgId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginNodePermission_ConnectionRequest) Reset() {
+	*m = PluginNodePermission_ConnectionRequest{}
+}
+func (m *PluginNodePermission_ConnectionRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginNodePermission_ConnectionRequest) ProtoMessage()    {}
+func (*PluginNodePermission_ConnectionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1bdcf11b0995561, []int{0, 0}
+}
+
+func (m *PluginNodePermission_ConnectionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginNodePermission_ConnectionRequest.Unmarshal(m, b)
+}
+func (m *PluginNodePermission_ConnectionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginNodePermission_ConnectionRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginNodePermission_ConnectionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginNodePermission_ConnectionRequest.Merge(m, src)
+}
+func (m *PluginNodePermission_ConnectionRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginNodePermission_ConnectionRequest.Size(m)
+}
+func (m *PluginNodePermission_ConnectionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginNodePermission_ConnectionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginNodePermission_ConnectionRequest proto.InternalMessageInfo
+
+func (m *PluginNodePermission_ConnectionRequest) GetEnodeId() string {
+	if m != nil {
+		return m.EnodeId
+	}
+	return ""
+}
+
+func (m *PluginNodePermission_ConnectionRequest) GetEnodeUrl() string {
+	if m != nil {
+		return m.EnodeUrl
+	}
+	return ""
+}
+
+func (m *PluginNodePermission_ConnectionRequest) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *PluginNodePermission_ConnectionRequest) GetTcpPort() uint32 {
+	if m != nil {
+		return m.TcpPort
+	}
+	return 0
+}
+
+func (m *PluginNodePermission_ConnectionRequest) GetRaftPort() uint32 {
+	if m != nil {
+		return m.RaftPort
+	}
+	return 0
+}
+
+func (m *PluginNodePermission_ConnectionRequest) GetDirection() PluginNodePermission_Direction {
+	if m != nil {
+		return m.Direction
+	}
+	return PluginNodePermission_INBOUND
+}
+
+func (m *PluginNodePermission_ConnectionRequest) GetOrgId() string {
+	if m != nil {
+		return m.OrgId
+	}
+	return ""
+}
+
+type PluginNodePermission_ConnectionResponse struct {
+	Allowed bool `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// optional reason for the decision, used for logging
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginNodePermission_ConnectionResponse) Reset() {
+	*m = PluginNodePermission_ConnectionResponse{}
+}
+func (m *PluginNodePermission_ConnectionResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginNodePermission_ConnectionResponse) ProtoMessage()    {}
+func (*PluginNodePermission_ConnectionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1bdcf11b0995561, []int{0, 1}
+}
+
+func (m *PluginNodePermission_ConnectionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginNodePermission_ConnectionResponse.Unmarshal(m, b)
+}
+func (m *PluginNodePermission_ConnectionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginNodePermission_ConnectionResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginNodePermission_ConnectionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginNodePermission_ConnectionResponse.Merge(m, src)
+}
+func (m *PluginNodePermission_ConnectionResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginNodePermission_ConnectionResponse.Size(m)
+}
+func (m *PluginNodePermission_ConnectionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginNodePermission_ConnectionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginNodePermission_ConnectionResponse proto.InternalMessageInfo
+
+func (m *PluginNodePermission_ConnectionResponse) GetAllowed() bool {
+	if m != nil {
+		return m.Allowed
+	}
+	return false
+}
+
+func (m *PluginNodePermission_ConnectionResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("proto_common.PluginNodePermission_Direction", PluginNodePermission_Direction_name, PluginNodePermission_Direction_value)
+	proto.RegisterType((*PluginNodePermission)(nil), "proto_common.PluginNodePermission")
+	proto.RegisterType((*PluginNodePermission_ConnectionRequest)(nil), "proto_common.PluginNodePermission.ConnectionRequest")
+	proto.RegisterType((*PluginNodePermission_ConnectionResponse)(nil), "proto_common.PluginNodePermission.ConnectionResponse")
+}
+
+func init() {
+	proto.RegisterFile("nodepermission.proto", fileDescriptor_b1bdcf11b0995561)
+}
+
+var fileDescriptor_b1bdcf11b0995561 = []byte{
+	// 341 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xbf, 0x4e, 0xf3, 0x30,
+	0x14, 0xc5, 0x3f, 0xe7, 0xa3, 0xff, 0x2e, 0xa5, 0x02, 0xab, 0x02, 0x2b, 0x2c, 0x55, 0x07, 0xd4,
+	0x01, 0x65, 0x28, 0x20, 0x66, 0x4a, 0x85, 0x54, 0x86, 0xb6, 0x0a, 0x74, 0x61, 0x41, 0x25, 0x31,
+	0x95, 0xa5, 0xc4, 0xd7, 0x75, 0x12, 0x58, 0x79, 0x14, 0x5e, 0x91, 0x95, 0x09, 0xc5, 0x6e, 0x42,
+	0x2b, 0x3a, 0xc0, 0x94, 0xfc, 0x72, 0x73, 0x8e, 0xcf, 0xd1, 0x35, 0xb4, 0x25, 0x86, 0x5c, 0x71,
+	0x1d, 0x8b, 0x24, 0x11, 0x28, 0x3d, 0xa5, 0x31, 0x45, 0xda, 0x34, 0x8f, 0xc7, 0x00, 0xe3, 0x18,
+	0x65, 0xf7, 0xd3, 0x81, 0xf6, 0x34, 0xca, 0x16, 0x42, 0x8e, 0x31, 0xe4, 0xd3, 0xf2, 0x67, 0xf7,
+	0x83, 0xc0, 0xc1, 0x35, 0x4a, 0xc9, 0x83, 0x54, 0xa0, 0xf4, 0xf9, 0x32, 0xe3, 0x49, 0x4a, 0x19,
+	0xd4, 0x78, 0xee, 0x3a, 0x0a, 0x19, 0xe9, 0x90, 0x5e, 0xc3, 0x2f, 0x90, 0xba, 0x50, 0x37, 0xaf,
+	0x33, 0x1d, 0x31, 0xc7, 0x8c, 0x4a, 0xa6, 0x2d, 0x70, 0x84, 0x62, 0xff, 0xcd, 0x57, 0x47, 0xa8,
+	0xdc, 0x25, 0x0d, 0xd4, 0x14, 0x75, 0xca, 0x76, 0x3a, 0xa4, 0xb7, 0xe7, 0x17, 0x98, 0xbb, 0xe8,
+	0xf9, 0x73, 0x6a, 0x46, 0x15, 0x33, 0x2a, 0x99, 0xde, 0x42, 0x23, 0x14, 0xda, 0xe6, 0x61, 0xd5,
+	0x0e, 0xe9, 0xb5, 0xfa, 0xa7, 0xde, 0x7a, 0x19, 0x6f, 0x5b, 0x11, 0x6f, 0x58, 0x68, 0xfc, 0x6f,
+	0x39, 0x6d, 0x43, 0x05, 0xf5, 0x62, 0x14, 0xb2, 0x9a, 0x09, 0x65, 0xc1, 0xbd, 0x01, 0xba, 0x5e,
+	0x39, 0x51, 0x28, 0x13, 0x9e, 0xa7, 0x9d, 0x47, 0x11, 0xbe, 0x72, 0xdb, 0xb9, 0xee, 0x17, 0x48,
+	0x0f, 0xa1, 0xaa, 0xf9, 0x3c, 0x41, 0xb9, 0x6a, 0xbc, 0xa2, 0xee, 0x09, 0x34, 0xca, 0x53, 0xe9,
+	0x2e, 0xd4, 0x46, 0xe3, 0xc1, 0x64, 0x36, 0x1e, 0xee, 0xff, 0xa3, 0x4d, 0xa8, 0x4f, 0x66, 0xf7,
+	0x96, 0x48, 0xff, 0x9d, 0xc0, 0xf1, 0xb6, 0xcc, 0x77, 0x5c, 0xbf, 0x88, 0x80, 0xd3, 0xb7, 0x8d,
+	0x1d, 0x5c, 0xad, 0x4e, 0x3d, 0xff, 0x45, 0xe9, 0x1f, 0x9b, 0x73, 0x2f, 0xfe, 0xa8, 0xb2, 0xe5,
+	0x07, 0x97, 0x70, 0x14, 0x60, 0xec, 0x2d, 0x33, 0xd4, 0x59, 0xec, 0x29, 0xa3, 0xb2, 0x4e, 0x83,
+	0xd6, 0xa6, 0xfa, 0x61, 0xe3, 0x62, 0x3d, 0x55, 0x0d, 0x9d, 0x7d, 0x0d, 0x00, 0xb9, 0xad, 0xd8,
+	0x8e, 0x85, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginNodePermissionServiceClient is the client API for PluginNodePermissionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginNodePermissionServiceClient interface {
+	ConnectionAllowed(ctx context.Context, in *PluginNodePermission_ConnectionRequest, opts ...grpc.CallOption) (*PluginNodePermission_ConnectionResponse, error)
+}
+
+type pluginNodePermissionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginNodePermissionServiceClient(cc grpc.ClientConnInterface) PluginNodePermissionServiceClient {
+	return &pluginNodePermissionServiceClient{cc}
+}
+
+func (c *pluginNodePermissionServiceClient) ConnectionAllowed(ctx context.Context, in *PluginNodePermission_ConnectionRequest, opts ...grpc.CallOption) (*PluginNodePermission_ConnectionResponse, error) {
+	out := new(PluginNodePermission_ConnectionResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginNodePermissionService/ConnectionAllowed", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginNodePermissionServiceServer is the server API for PluginNodePermissionService service.
+type PluginNodePermissionServiceServer interface {
+	ConnectionAllowed(context.Context, *PluginNodePermission_ConnectionRequest) (*PluginNodePermission_ConnectionResponse, error)
+}
+
+// UnimplementedPluginNodePermissionServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginNodePermissionServiceServer struct {
+}
+
+func (*UnimplementedPluginNodePermissionServiceServer) ConnectionAllowed(ctx context.Context, req *PluginNodePermission_ConnectionRequest) (*PluginNodePermission_ConnectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectionAllowed not implemented")
+}
+
+func RegisterPluginNodePermissionServiceServer(s *grpc.Server, srv PluginNodePermissionServiceServer) {
+	s.RegisterService(&_PluginNodePermissionService_serviceDesc, srv)
+}
+
+func _PluginNodePermissionService_ConnectionAllowed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginNodePermission_ConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginNodePermissionServiceServer).ConnectionAllowed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginNodePermissionService/ConnectionAllowed",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginNodePermissionServiceServer).ConnectionAllowed(ctx, req.(*PluginNodePermission_ConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginNodePermissionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginNodePermissionService",
+	HandlerType: (*PluginNodePermissionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConnectionAllowed",
+			Handler:    _PluginNodePermissionService_ConnectionAllowed_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nodepermission.proto",
+}
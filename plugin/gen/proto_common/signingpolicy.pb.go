@@ -0,0 +1,482 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: signingpolicy.proto
+
+package proto_common
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// *
+// A wrapper message to logically group other messages
+type PluginSigningPolicy struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginSigningPolicy) Reset()         { *m = PluginSigningPolicy{} }
+func (m *PluginSigningPolicy) String() string { return proto.CompactTextString(m) }
+func (*PluginSigningPolicy) ProtoMessage()    {}
+func (*PluginSigningPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413f4fadba790355, []int{0}
+}
+
+func (m *PluginSigningPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginSigningPolicy.Unmarshal(m, b)
+}
+func (m *PluginSigningPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginSigningPolicy.Marshal(b, m, deterministic)
+}
+func (m *PluginSigningPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginSigningPolicy.Merge(m, src)
+}
+func (m *PluginSigningPolicy) XXX_Size() int {
+	return xxx_messageInfo_PluginSigningPolicy.Size(m)
+}
+func (m *PluginSigningPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginSigningPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginSigningPolicy proto.InternalMessageInfo
+
+// transaction about to be signed
+type PluginSigningPolicy_Transaction struct {
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// empty for contract creation
+	To []byte `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// big-endian encoded value in wei
+	Value []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Gas   uint64 `protobuf:"varint,4,opt,name=gas,proto3" json:"gas,omitempty"`
+	// big-endian encoded gas price in wei
+	GasPrice []byte `protobuf:"bytes,5,opt,name=gasPrice,proto3" json:"gasPrice,omitempty"`
+	// call data, its first 4 bytes identify the method being invoked.
+	//
+	// For private transactions, this is the hash of the encrypted payload
+	Data    []byte `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	Private bool   `protobuf:"varint,7,opt,name=private,proto3" json:"private,omitempty"`
+	// big-endian encoded chain ID, empty if the transaction is not replay-protected
+	ChainId              []byte   `protobuf:"bytes,8,opt,name=chainId,proto3" json:"chainId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginSigningPolicy_Transaction) Reset()         { *m = PluginSigningPolicy_Transaction{} }
+func (m *PluginSigningPolicy_Transaction) String() string { return proto.CompactTextString(m) }
+func (*PluginSigningPolicy_Transaction) ProtoMessage()    {}
+func (*PluginSigningPolicy_Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413f4fadba790355, []int{0, 0}
+}
+
+func (m *PluginSigningPolicy_Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginSigningPolicy_Transaction.Unmarshal(m, b)
+}
+func (m *PluginSigningPolicy_Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginSigningPolicy_Transaction.Marshal(b, m, deterministic)
+}
+func (m *PluginSigningPolicy_Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginSigningPolicy_Transaction.Merge(m, src)
+}
+func (m *PluginSigningPolicy_Transaction) XXX_Size() int {
+	return xxx_messageInfo_PluginSigningPolicy_Transaction.Size(m)
+}
+func (m *PluginSigningPolicy_Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginSigningPolicy_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginSigningPolicy_Transaction proto.InternalMessageInfo
+
+func (m *PluginSigningPolicy_Transaction) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *PluginSigningPolicy_Transaction) GetTo() []byte {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_Transaction) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_Transaction) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+func (m *PluginSigningPolicy_Transaction) GetGasPrice() []byte {
+	if m != nil {
+		return m.GasPrice
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_Transaction) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_Transaction) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *PluginSigningPolicy_Transaction) GetChainId() []byte {
+	if m != nil {
+		return m.ChainId
+	}
+	return nil
+}
+
+// identity of the caller as authenticated by the security plugin
+type PluginSigningPolicy_Identity struct {
+	RawToken []byte `protobuf:"bytes,1,opt,name=rawToken,proto3" json:"rawToken,omitempty"`
+	// raw values of the authorities granted to the caller
+	Authorities          []string `protobuf:"bytes,2,rep,name=authorities,proto3" json:"authorities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginSigningPolicy_Identity) Reset()         { *m = PluginSigningPolicy_Identity{} }
+func (m *PluginSigningPolicy_Identity) String() string { return proto.CompactTextString(m) }
+func (*PluginSigningPolicy_Identity) ProtoMessage()    {}
+func (*PluginSigningPolicy_Identity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413f4fadba790355, []int{0, 1}
+}
+
+func (m *PluginSigningPolicy_Identity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginSigningPolicy_Identity.Unmarshal(m, b)
+}
+func (m *PluginSigningPolicy_Identity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginSigningPolicy_Identity.Marshal(b, m, deterministic)
+}
+func (m *PluginSigningPolicy_Identity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginSigningPolicy_Identity.Merge(m, src)
+}
+func (m *PluginSigningPolicy_Identity) XXX_Size() int {
+	return xxx_messageInfo_PluginSigningPolicy_Identity.Size(m)
+}
+func (m *PluginSigningPolicy_Identity) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginSigningPolicy_Identity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginSigningPolicy_Identity proto.InternalMessageInfo
+
+func (m *PluginSigningPolicy_Identity) GetRawToken() []byte {
+	if m != nil {
+		return m.RawToken
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_Identity) GetAuthorities() []string {
+	if m != nil {
+		return m.Authorities
+	}
+	return nil
+}
+
+type PluginSigningPolicy_EvaluateRequest struct {
+	// address of the account used to sign
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//	*PluginSigningPolicy_EvaluateRequest_Transaction
+	//	*PluginSigningPolicy_EvaluateRequest_Data
+	Payload isPluginSigningPolicy_EvaluateRequest_Payload `protobuf_oneof:"payload"`
+	// not set if the request doesn't come from an authenticated JSON RPC call
+	Identity             *PluginSigningPolicy_Identity `protobuf:"bytes,4,opt,name=identity,proto3" json:"identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *PluginSigningPolicy_EvaluateRequest) Reset()         { *m = PluginSigningPolicy_EvaluateRequest{} }
+func (m *PluginSigningPolicy_EvaluateRequest) String() string { return proto.CompactTextString(m) }
+func (*PluginSigningPolicy_EvaluateRequest) ProtoMessage()    {}
+func (*PluginSigningPolicy_EvaluateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413f4fadba790355, []int{0, 2}
+}
+
+func (m *PluginSigningPolicy_EvaluateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginSigningPolicy_EvaluateRequest.Unmarshal(m, b)
+}
+func (m *PluginSigningPolicy_EvaluateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginSigningPolicy_EvaluateRequest.Marshal(b, m, deterministic)
+}
+func (m *PluginSigningPolicy_EvaluateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginSigningPolicy_EvaluateRequest.Merge(m, src)
+}
+func (m *PluginSigningPolicy_EvaluateRequest) XXX_Size() int {
+	return xxx_messageInfo_PluginSigningPolicy_EvaluateRequest.Size(m)
+}
+func (m *PluginSigningPolicy_EvaluateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginSigningPolicy_EvaluateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginSigningPolicy_EvaluateRequest proto.InternalMessageInfo
+
+func (m *PluginSigningPolicy_EvaluateRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+type isPluginSigningPolicy_EvaluateRequest_Payload interface {
+	isPluginSigningPolicy_EvaluateRequest_Payload()
+}
+
+type PluginSigningPolicy_EvaluateRequest_Transaction struct {
+	Transaction *PluginSigningPolicy_Transaction `protobuf:"bytes,2,opt,name=transaction,proto3,oneof"`
+}
+
+type PluginSigningPolicy_EvaluateRequest_Data struct {
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3,oneof"`
+}
+
+func (*PluginSigningPolicy_EvaluateRequest_Transaction) isPluginSigningPolicy_EvaluateRequest_Payload() {
+}
+
+func (*PluginSigningPolicy_EvaluateRequest_Data) isPluginSigningPolicy_EvaluateRequest_Payload() {}
+
+func (m *PluginSigningPolicy_EvaluateRequest) GetPayload() isPluginSigningPolicy_EvaluateRequest_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_EvaluateRequest) GetTransaction() *PluginSigningPolicy_Transaction {
+	if x, ok := m.GetPayload().(*PluginSigningPolicy_EvaluateRequest_Transaction); ok {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_EvaluateRequest) GetData() []byte {
+	if x, ok := m.GetPayload().(*PluginSigningPolicy_EvaluateRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (m *PluginSigningPolicy_EvaluateRequest) GetIdentity() *PluginSigningPolicy_Identity {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*PluginSigningPolicy_EvaluateRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*PluginSigningPolicy_EvaluateRequest_Transaction)(nil),
+		(*PluginSigningPolicy_EvaluateRequest_Data)(nil),
+	}
+}
+
+type PluginSigningPolicy_EvaluateResponse struct {
+	Allowed bool `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// reason for denying the request, returned to the caller
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginSigningPolicy_EvaluateResponse) Reset()         { *m = PluginSigningPolicy_EvaluateResponse{} }
+func (m *PluginSigningPolicy_EvaluateResponse) String() string { return proto.CompactTextString(m) }
+func (*PluginSigningPolicy_EvaluateResponse) ProtoMessage()    {}
+func (*PluginSigningPolicy_EvaluateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413f4fadba790355, []int{0, 3}
+}
+
+func (m *PluginSigningPolicy_EvaluateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginSigningPolicy_EvaluateResponse.Unmarshal(m, b)
+}
+func (m *PluginSigningPolicy_EvaluateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginSigningPolicy_EvaluateResponse.Marshal(b, m, deterministic)
+}
+func (m *PluginSigningPolicy_EvaluateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginSigningPolicy_EvaluateResponse.Merge(m, src)
+}
+func (m *PluginSigningPolicy_EvaluateResponse) XXX_Size() int {
+	return xxx_messageInfo_PluginSigningPolicy_EvaluateResponse.Size(m)
+}
+func (m *PluginSigningPolicy_EvaluateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginSigningPolicy_EvaluateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginSigningPolicy_EvaluateResponse proto.InternalMessageInfo
+
+func (m *PluginSigningPolicy_EvaluateResponse) GetAllowed() bool {
+	if m != nil {
+		return m.Allowed
+	}
+	return false
+}
+
+func (m *PluginSigningPolicy_EvaluateResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*PluginSigningPolicy)(nil), "proto_common.PluginSigningPolicy")
+	proto.RegisterType((*PluginSigningPolicy_Transaction)(nil), "proto_common.PluginSigningPolicy.Transaction")
+	proto.RegisterType((*PluginSigningPolicy_Identity)(nil), "proto_common.PluginSigningPolicy.Identity")
+	proto.RegisterType((*PluginSigningPolicy_EvaluateRequest)(nil), "proto_common.PluginSigningPolicy.EvaluateRequest")
+	proto.RegisterType((*PluginSigningPolicy_EvaluateResponse)(nil), "proto_common.PluginSigningPolicy.EvaluateResponse")
+}
+
+func init() {
+	proto.RegisterFile("signingpolicy.proto", fileDescriptor_413f4fadba790355)
+}
+
+var fileDescriptor_413f4fadba790355 = []byte{
+	// 434 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0x51, 0x6e, 0xd3, 0x40,
+	0x10, 0x86, 0xeb, 0x24, 0x4d, 0x9c, 0x71, 0x80, 0x6a, 0x5b, 0xc1, 0xca, 0x4f, 0x16, 0x4f, 0x11,
+	0x12, 0x96, 0x08, 0x12, 0x07, 0x88, 0x00, 0xa5, 0x6f, 0x61, 0xdb, 0x27, 0x5e, 0xd0, 0x60, 0xaf,
+	0xdc, 0x15, 0xce, 0xae, 0xb3, 0xbb, 0x4e, 0x95, 0x53, 0x70, 0x24, 0xce, 0xc0, 0x29, 0xb8, 0x06,
+	0xf2, 0xd8, 0x4e, 0x53, 0x54, 0x29, 0xe2, 0xc9, 0xfb, 0x8f, 0xe7, 0x9f, 0xf1, 0xff, 0x59, 0x0b,
+	0x97, 0x4e, 0x15, 0x5a, 0xe9, 0xa2, 0x32, 0xa5, 0xca, 0xf6, 0x69, 0x65, 0x8d, 0x37, 0x6c, 0x46,
+	0x8f, 0x6f, 0x99, 0xd9, 0x6c, 0x8c, 0x7e, 0xfd, 0x7b, 0x04, 0x97, 0xeb, 0xb2, 0x2e, 0x94, 0xbe,
+	0x69, 0x7b, 0xd7, 0xd4, 0x1b, 0xff, 0x0a, 0x20, 0xba, 0xb5, 0xa8, 0x1d, 0x66, 0x5e, 0x19, 0xcd,
+	0xae, 0xe0, 0x5c, 0x1b, 0x9d, 0x49, 0x1e, 0x24, 0xc1, 0x7c, 0x24, 0x5a, 0xc1, 0x9e, 0xc3, 0xc0,
+	0x1b, 0x3e, 0x48, 0x82, 0xf9, 0x4c, 0x0c, 0xbc, 0x69, 0xba, 0x76, 0x58, 0xd6, 0x92, 0x0f, 0xa9,
+	0xd4, 0x0a, 0x76, 0x01, 0xc3, 0x02, 0x1d, 0x1f, 0x91, 0xb3, 0x39, 0xb2, 0x18, 0xc2, 0x02, 0xdd,
+	0xda, 0xaa, 0x4c, 0xf2, 0x73, 0x6a, 0x3d, 0x68, 0xc6, 0x60, 0x94, 0xa3, 0x47, 0x3e, 0xa6, 0x3a,
+	0x9d, 0x19, 0x87, 0x49, 0x65, 0xd5, 0x0e, 0xbd, 0xe4, 0x93, 0x24, 0x98, 0x87, 0xa2, 0x97, 0xcd,
+	0x9b, 0xec, 0x0e, 0x95, 0xbe, 0xce, 0x79, 0x48, 0x86, 0x5e, 0xc6, 0x2b, 0x08, 0xaf, 0x73, 0xa9,
+	0xbd, 0xf2, 0xfb, 0x66, 0x9f, 0xc5, 0xfb, 0x5b, 0xf3, 0x43, 0x6a, 0x0a, 0x30, 0x13, 0x07, 0xcd,
+	0x12, 0x88, 0xb0, 0xf6, 0x77, 0xc6, 0x2a, 0xaf, 0xa4, 0xe3, 0x83, 0x64, 0x38, 0x9f, 0x8a, 0xe3,
+	0x52, 0xfc, 0x27, 0x80, 0x17, 0x9f, 0x9a, 0x28, 0xe8, 0xa5, 0x90, 0xdb, 0x5a, 0x3a, 0xdf, 0xec,
+	0xc5, 0x3c, 0xb7, 0xd2, 0xb9, 0x6e, 0x60, 0x2f, 0xd9, 0x17, 0x88, 0xfc, 0x03, 0x38, 0x82, 0x13,
+	0x2d, 0xde, 0xa6, 0xc7, 0xd4, 0xd3, 0x27, 0x88, 0xa7, 0x47, 0xb4, 0x57, 0x67, 0xe2, 0x78, 0x06,
+	0xbb, 0xea, 0x90, 0x10, 0xd5, 0xd5, 0x59, 0x07, 0xe5, 0x33, 0x84, 0xaa, 0x0b, 0x48, 0x6c, 0xa3,
+	0xc5, 0x9b, 0xd3, 0x5b, 0x7a, 0x24, 0xe2, 0xe0, 0x5d, 0x4e, 0x61, 0x52, 0xe1, 0xbe, 0x34, 0x98,
+	0xc7, 0x1f, 0xe1, 0xe2, 0x21, 0xa8, 0xab, 0x8c, 0x76, 0x44, 0x18, 0xcb, 0xd2, 0xdc, 0xcb, 0x9c,
+	0x92, 0x86, 0xa2, 0x97, 0xec, 0x25, 0x8c, 0xad, 0x44, 0xd7, 0x85, 0x9c, 0x8a, 0x4e, 0x2d, 0x7e,
+	0x06, 0x10, 0x3f, 0xb1, 0xfb, 0x46, 0xda, 0x5d, 0xf3, 0x83, 0xb7, 0x10, 0xf6, 0x4b, 0xd8, 0xbb,
+	0xd3, 0x5f, 0xfc, 0x0f, 0xf9, 0x78, 0xf1, 0x3f, 0x96, 0x36, 0xc3, 0xf2, 0x03, 0xbc, 0xca, 0xcc,
+	0x26, 0xdd, 0xd6, 0xc6, 0xd6, 0x9b, 0xb4, 0x22, 0x4b, 0x3b, 0x66, 0xf9, 0xec, 0x91, 0xf5, 0xeb,
+	0xa3, 0xdb, 0xf1, 0x7d, 0x4c, 0xea, 0xfd, 0xdf, 0x01, 0x00, 0x21, 0xf9, 0x2b, 0x07, 0x49, 0x03,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PluginSigningPolicyServiceClient is the client API for PluginSigningPolicyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PluginSigningPolicyServiceClient interface {
+	Evaluate(ctx context.Context, in *PluginSigningPolicy_EvaluateRequest, opts ...grpc.CallOption) (*PluginSigningPolicy_EvaluateResponse, error)
+}
+
+type pluginSigningPolicyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginSigningPolicyServiceClient(cc grpc.ClientConnInterface) PluginSigningPolicyServiceClient {
+	return &pluginSigningPolicyServiceClient{cc}
+}
+
+func (c *pluginSigningPolicyServiceClient) Evaluate(ctx context.Context, in *PluginSigningPolicy_EvaluateRequest, opts ...grpc.CallOption) (*PluginSigningPolicy_EvaluateResponse, error) {
+	out := new(PluginSigningPolicy_EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/proto_common.PluginSigningPolicyService/Evaluate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginSigningPolicyServiceServer is the server API for PluginSigningPolicyService service.
+type PluginSigningPolicyServiceServer interface {
+	Evaluate(context.Context, *PluginSigningPolicy_EvaluateRequest) (*PluginSigningPolicy_EvaluateResponse, error)
+}
+
+// UnimplementedPluginSigningPolicyServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginSigningPolicyServiceServer struct {
+}
+
+func (*UnimplementedPluginSigningPolicyServiceServer) Evaluate(ctx context.Context, req *PluginSigningPolicy_EvaluateRequest) (*PluginSigningPolicy_EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+
+func RegisterPluginSigningPolicyServiceServer(s *grpc.Server, srv PluginSigningPolicyServiceServer) {
+	s.RegisterService(&_PluginSigningPolicyService_serviceDesc, srv)
+}
+
+func _PluginSigningPolicyService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginSigningPolicy_EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginSigningPolicyServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_common.PluginSigningPolicyService/Evaluate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginSigningPolicyServiceServer).Evaluate(ctx, req.(*PluginSigningPolicy_EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PluginSigningPolicyService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_common.PluginSigningPolicyService",
+	HandlerType: (*PluginSigningPolicyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Evaluate",
+			Handler:    _PluginSigningPolicyService_Evaluate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signingpolicy.proto",
+}
@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	pmetrics "github.com/ethereum/go-ethereum/plugin/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	metricsNamespace              = "plugin"
+	defaultMetricsCollectInterval = 15 * time.Second
+	metricsCollectTimeout         = 5 * time.Second
+)
+
+// implemented by plugins which can be asked for their metrics
+type metricsSource interface {
+	collectMetrics(ctx context.Context) ([]pmetrics.Metric, error)
+}
+
+// metricsBridge periodically collects metrics from plugins implementing the
+// metrics service and re-exports them as plugin/<interface>/<metric> in the
+// geth metrics registry, so there is a single endpoint for observability
+type metricsBridge struct {
+	pm       *PluginManager
+	registry metrics.Registry
+	interval time.Duration
+
+	mux         sync.Mutex
+	unsupported map[PluginInterfaceName]bool                     // plugins not implementing the metrics service
+	registered  map[PluginInterfaceName]map[string]pmetrics.Type // names of re-exported metrics per plugin
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newMetricsBridge(pm *PluginManager, registry metrics.Registry, interval time.Duration) *metricsBridge {
+	return &metricsBridge{
+		pm:          pm,
+		registry:    registry,
+		interval:    interval,
+		unsupported: make(map[PluginInterfaceName]bool),
+		registered:  make(map[PluginInterfaceName]map[string]pmetrics.Type),
+	}
+}
+
+func (b *metricsBridge) start() {
+	b.quit = make(chan struct{})
+	b.wg.Add(1)
+	go b.loop()
+}
+
+// stop terminates the collection loop and unregisters all re-exported metrics
+func (b *metricsBridge) stop() {
+	if b.quit == nil {
+		return
+	}
+	close(b.quit)
+	b.wg.Wait()
+	b.quit = nil
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	for name, registered := range b.registered {
+		for metricName := range registered {
+			b.registry.Unregister(metricName)
+		}
+		delete(b.registered, name)
+	}
+	b.unsupported = make(map[PluginInterfaceName]bool)
+}
+
+func (b *metricsBridge) loop() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	b.collectAll()
+	for {
+		select {
+		case <-ticker.C:
+			b.collectAll()
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+func (b *metricsBridge) collectAll() {
+	for name, p := range b.pm.initializedPlugins {
+		source, ok := p.(metricsSource)
+		if !ok || b.isUnsupported(name) {
+			continue
+		}
+		b.collect(name, source)
+	}
+}
+
+func (b *metricsBridge) collect(name PluginInterfaceName, source metricsSource) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsCollectTimeout)
+	defer cancel()
+	collected, err := source.collectMetrics(ctx)
+	if err != nil {
+		if rpcStatus, ok := status.FromError(err); ok && rpcStatus.Code() == codes.Unimplemented {
+			log.Info("Plugin doesn't implement metrics service", "provider", name)
+			b.mux.Lock()
+			b.unsupported[name] = true
+			b.mux.Unlock()
+			return
+		}
+		log.Debug("Unable to collect plugin metrics", "provider", name, "err", err)
+		return
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	for _, m := range collected {
+		b.update(name, m)
+	}
+}
+
+// update must be called with the lock held
+func (b *metricsBridge) update(name PluginInterfaceName, m pmetrics.Metric) {
+	metricName := fmt.Sprintf("%s/%s/%s", metricsNamespace, name, strings.Trim(m.Name, "/"))
+	registered, ok := b.registered[name]
+	if !ok {
+		registered = make(map[string]pmetrics.Type)
+		b.registered[name] = registered
+	}
+	// the plugin has changed the type of the metric so we need to start over
+	if t, ok := registered[metricName]; ok && t != m.Type {
+		b.registry.Unregister(metricName)
+	}
+	switch m.Type {
+	case pmetrics.Gauge:
+		metrics.GetOrRegisterGauge(metricName, b.registry).Update(int64(m.Value))
+	case pmetrics.GaugeFloat64:
+		metrics.GetOrRegisterGaugeFloat64(metricName, b.registry).Update(m.Value)
+	case pmetrics.Counter:
+		c := metrics.GetOrRegisterCounter(metricName, b.registry)
+		c.Inc(int64(m.Value) - c.Count())
+	default:
+		log.Debug("Ignore plugin metric with unknown type", "provider", name, "metric", m.Name, "type", m.Type)
+		return
+	}
+	registered[metricName] = m.Type
+}
+
+func (b *metricsBridge) isUnsupported(name PluginInterfaceName) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.unsupported[name]
+}
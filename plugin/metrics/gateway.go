@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+type PluginGateway struct {
+	client proto_common.PluginMetricsCollectorClient
+}
+
+func (g *PluginGateway) Collect(ctx context.Context) ([]Metric, error) {
+	resp, err := g.client.Collect(ctx, &proto_common.PluginMetrics_Request{})
+	if err != nil {
+		return nil, err
+	}
+	metrics := make([]Metric, 0, len(resp.Metrics))
+	for _, m := range resp.Metrics {
+		if m == nil || len(m.Name) == 0 {
+			continue
+		}
+		metrics = append(metrics, Metric{
+			Name:  m.Name,
+			Type:  Type(m.Type),
+			Value: m.Value,
+		})
+	}
+	return metrics, nil
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginGateway_Collect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := proto_common.NewMockPluginMetricsCollectorClient(ctrl)
+	mockClient.
+		EXPECT().
+		Collect(gomock.Any(), gomock.Any()).
+		Return(&proto_common.PluginMetrics_Response{
+			Metrics: []*proto_common.PluginMetrics_Metric{
+				{Name: "requests/total", Type: proto_common.PluginMetrics_COUNTER, Value: 10},
+				{Name: "", Type: proto_common.PluginMetrics_GAUGE, Value: 1},
+				{Name: "latency", Type: proto_common.PluginMetrics_GAUGE_FLOAT64, Value: 0.5},
+			},
+		}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	actual, err := testObject.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{Name: "requests/total", Type: Counter, Value: 10},
+		{Name: "latency", Type: GaugeFloat64, Value: 0.5},
+	}, actual)
+}
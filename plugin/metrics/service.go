@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+type Type int32
+
+const (
+	Gauge        = Type(proto_common.PluginMetrics_GAUGE)
+	GaugeFloat64 = Type(proto_common.PluginMetrics_GAUGE_FLOAT64)
+	Counter      = Type(proto_common.PluginMetrics_COUNTER)
+)
+
+// Metric is a single measurement reported by a plugin
+type Metric struct {
+	Name  string
+	Type  Type
+	Value float64
+}
+
+// PluginMetrics is implemented by plugins which want their metrics to be
+// re-exported in the geth metrics registry
+type PluginMetrics interface {
+	Collect(ctx context.Context) ([]Metric, error)
+}
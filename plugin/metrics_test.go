@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	pmetrics "github.com/ethereum/go-ethereum/plugin/metrics"
+	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubMetricsPlugin struct {
+	managedPlugin
+	metrics []pmetrics.Metric
+	err     error
+	calls   int
+}
+
+func (s *stubMetricsPlugin) collectMetrics(_ context.Context) ([]pmetrics.Metric, error) {
+	s.calls++
+	return s.metrics, s.err
+}
+
+func enableMetrics(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	t.Cleanup(func() {
+		metrics.Enabled = enabled
+	})
+}
+
+func TestMetricsBridge_collectAll(t *testing.T) {
+	enableMetrics(t)
+	assert := testifyassert.New(t)
+	registry := metrics.NewRegistry()
+	stub := &stubMetricsPlugin{
+		metrics: []pmetrics.Metric{
+			{Name: "requests/total", Type: pmetrics.Counter, Value: 10},
+			{Name: "/connections/", Type: pmetrics.Gauge, Value: 3},
+			{Name: "latency", Type: pmetrics.GaugeFloat64, Value: 0.25},
+		},
+	}
+	pm := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			BlockValidationPluginInterfaceName: stub,
+		},
+	}
+	testObject := newMetricsBridge(pm, registry, time.Minute)
+
+	testObject.collectAll()
+	stub.metrics[0].Value = 15
+	testObject.collectAll()
+
+	assert.Equal(int64(15), registry.Get("plugin/blockvalidation/requests/total").(metrics.Counter).Count())
+	assert.Equal(int64(3), registry.Get("plugin/blockvalidation/connections").(metrics.Gauge).Value())
+	assert.Equal(0.25, registry.Get("plugin/blockvalidation/latency").(metrics.GaugeFloat64).Value())
+}
+
+func TestMetricsBridge_collectAll_whenTypeChanged(t *testing.T) {
+	enableMetrics(t)
+	assert := testifyassert.New(t)
+	registry := metrics.NewRegistry()
+	stub := &stubMetricsPlugin{
+		metrics: []pmetrics.Metric{{Name: "foo", Type: pmetrics.Gauge, Value: 1}},
+	}
+	pm := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			BlockValidationPluginInterfaceName: stub,
+		},
+	}
+	testObject := newMetricsBridge(pm, registry, time.Minute)
+
+	testObject.collectAll()
+	stub.metrics[0].Type = pmetrics.Counter
+	testObject.collectAll()
+
+	assert.Equal(int64(1), registry.Get("plugin/blockvalidation/foo").(metrics.Counter).Count())
+}
+
+func TestMetricsBridge_collectAll_whenUnimplemented(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubMetricsPlugin{
+		err: status.Error(codes.Unimplemented, "not implemented"),
+	}
+	pm := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			BlockValidationPluginInterfaceName: stub,
+		},
+	}
+	testObject := newMetricsBridge(pm, metrics.NewRegistry(), time.Minute)
+
+	testObject.collectAll()
+	testObject.collectAll()
+
+	assert.Equal(1, stub.calls)
+	assert.True(testObject.isUnsupported(BlockValidationPluginInterfaceName))
+}
+
+func TestMetricsBridge_stop_unregistersMetrics(t *testing.T) {
+	enableMetrics(t)
+	assert := testifyassert.New(t)
+	registry := metrics.NewRegistry()
+	stub := &stubMetricsPlugin{
+		metrics: []pmetrics.Metric{{Name: "foo", Type: pmetrics.Gauge, Value: 1}},
+	}
+	pm := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			BlockValidationPluginInterfaceName: stub,
+		},
+	}
+	testObject := newMetricsBridge(pm, registry, time.Minute)
+	testObject.start()
+	assert.Eventually(func() bool {
+		return registry.Get("plugin/blockvalidation/foo") != nil
+	}, time.Second, 10*time.Millisecond)
+
+	testObject.stop()
+
+	assert.Nil(registry.Get("plugin/blockvalidation/foo"))
+}
@@ -0,0 +1,277 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/plugin/certificate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// environment variables set for the plugin process when mutual TLS is managed by geth
+	envPluginTLSCACert = "QUORUM_PLUGIN_TLS_CA_CERT"
+	envPluginTLSCert   = "QUORUM_PLUGIN_TLS_CERT"
+	envPluginTLSKey    = "QUORUM_PLUGIN_TLS_KEY"
+
+	defaultCertificateValidity = time.Hour
+	minCertificateValidity     = time.Minute
+	certificateAuthorityTTL    = 10 * 365 * 24 * time.Hour // never persisted, renewed on every restart
+	certificateHost            = "localhost"
+	certificateRotateTimeout   = 10 * time.Second
+)
+
+// MutualTLSConfig enables mutual TLS between geth and plugins using short-lived
+// certificates issued by an in-memory certificate authority. Plugins must support it
+// by implementing the certificate service.
+//
+// If not configured, the plugin library negotiates certificates which never expire
+type MutualTLSConfig struct {
+	// validity of issued certificates, e.g.: 30m. Certificates are rotated
+	// when 2/3 of the validity has elapsed. Default is 1h
+	CertificateValidity string `json:"certificateValidity,omitempty" toml:",omitempty"`
+}
+
+func (c *MutualTLSConfig) validity() (time.Duration, error) {
+	if c.CertificateValidity == "" {
+		return defaultCertificateValidity, nil
+	}
+	d, err := time.ParseDuration(c.CertificateValidity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid certificate validity: %v", err)
+	}
+	if d < minCertificateValidity {
+		return 0, fmt.Errorf("certificate validity must be at least %v", minCertificateValidity)
+	}
+	return d, nil
+}
+
+type certificateAuthority struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+	pool    *x509.CertPool
+}
+
+func newCertificateAuthority() (*certificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sn, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "Quorum Plugin CA",
+			Organization: []string{"Quorum"},
+		},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		SerialNumber:          sn,
+		NotBefore:             time.Now().Add(-30 * time.Second),
+		NotAfter:              time.Now().Add(certificateAuthorityTTL),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &certificateAuthority{
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:     key,
+		pool:    pool,
+	}, nil
+}
+
+// issue returns a certificate for localhost with its private key, in PEM format
+func (ca *certificateAuthority) issue(validity time.Duration, usage x509.ExtKeyUsage) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	sn, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   certificateHost,
+			Organization: []string{"Quorum"},
+		},
+		DNSNames:     []string{certificateHost},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement,
+		SerialNumber: sn,
+		NotBefore:    time.Now().Add(-30 * time.Second),
+		NotAfter:     time.Now().Add(validity),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	var certOut, keyOut bytes.Buffer
+	if err := pem.Encode(&certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		return nil, nil, err
+	}
+	if err := pem.Encode(&keyOut, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}); err != nil {
+		return nil, nil, err
+	}
+	return certOut.Bytes(), keyOut.Bytes(), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// managedTLS holds certificates used to secure the connection to a plugin
+// and rotates them before they expire
+type managedTLS struct {
+	ca       *certificateAuthority
+	validity time.Duration
+	logger   log.Logger
+
+	mux        sync.RWMutex
+	clientCert *tls.Certificate // presented by geth
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newManagedTLS(ca *certificateAuthority, validity time.Duration, logger log.Logger) (*managedTLS, error) {
+	m := &managedTLS{
+		ca:       ca,
+		validity: validity,
+		logger:   logger,
+	}
+	if err := m.renewClientCertificate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *managedTLS) renewClientCertificate() error {
+	certPEM, keyPEM, err := m.ca.issue(m.validity, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.clientCert = &cert
+	return nil
+}
+
+// tlsConfig returns the configuration for geth to connect to the plugin. The most recent
+// client certificate is presented on every new connection
+func (m *managedTLS) tlsConfig() *tls.Config {
+	return &tls.Config{
+		RootCAs:    m.ca.pool,
+		ServerName: certificateHost,
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			m.mux.RLock()
+			defer m.mux.RUnlock()
+			return m.clientCert, nil
+		},
+	}
+}
+
+// environ returns environment variables for the plugin process including the initial server certificate
+func (m *managedTLS) environ() ([]string, error) {
+	certPEM, keyPEM, err := m.ca.issue(m.validity, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprintf("%s=%s", envPluginTLSCACert, m.ca.certPEM),
+		fmt.Sprintf("%s=%s", envPluginTLSCert, certPEM),
+		fmt.Sprintf("%s=%s", envPluginTLSKey, keyPEM),
+	}, nil
+}
+
+// start rotating certificates once 2/3 of the validity has elapsed
+func (m *managedTLS) start(rotator func() (certificate.PluginCertificateRotator, error)) {
+	m.quit = make(chan struct{})
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.validity * 2 / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.rotate(rotator); err != nil {
+					if rpcStatus, ok := status.FromError(err); ok && rpcStatus.Code() == codes.Unimplemented {
+						m.logger.Warn("Plugin doesn't support certificate rotation, new connections will fail once its certificate expires")
+						return
+					}
+					m.logger.Error("Unable to rotate plugin certificates", "err", err)
+				}
+			case <-m.quit:
+				return
+			}
+		}
+	}()
+}
+
+func (m *managedTLS) rotate(rotator func() (certificate.PluginCertificateRotator, error)) error {
+	r, err := rotator()
+	if err != nil {
+		return err
+	}
+	certPEM, keyPEM, err := m.ca.issue(m.validity, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), certificateRotateTimeout)
+	defer cancel()
+	if err := r.Rotate(ctx, certPEM, keyPEM); err != nil {
+		return err
+	}
+	if err := m.renewClientCertificate(); err != nil {
+		return err
+	}
+	m.logger.Debug("Plugin certificates rotated")
+	return nil
+}
+
+func (m *managedTLS) stop() {
+	if m.quit == nil {
+		return
+	}
+	close(m.quit)
+	m.wg.Wait()
+	m.quit = nil
+}
@@ -0,0 +1,27 @@
+package nodepermission
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+type PluginGateway struct {
+	client proto_common.PluginNodePermissionServiceClient
+}
+
+func (g *PluginGateway) ConnectionAllowed(ctx context.Context, req *ConnectionRequest) (bool, string, error) {
+	resp, err := g.client.ConnectionAllowed(ctx, &proto_common.PluginNodePermission_ConnectionRequest{
+		EnodeId:   req.EnodeID,
+		EnodeUrl:  req.EnodeURL,
+		Ip:        req.IP,
+		TcpPort:   uint32(req.TCPPort),
+		RaftPort:  uint32(req.RaftPort),
+		Direction: proto_common.PluginNodePermission_Direction(req.Direction),
+		OrgId:     req.OrgID,
+	})
+	if err != nil {
+		return false, "", err
+	}
+	return resp.Allowed, resp.Reason, nil
+}
@@ -0,0 +1,49 @@
+package nodepermission
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginGateway_ConnectionAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &proto_common.PluginNodePermission_ConnectionRequest{
+		EnodeId:   "arbitrary id",
+		EnodeUrl:  "arbitrary url",
+		Ip:        "127.0.0.1",
+		TcpPort:   21000,
+		RaftPort:  50400,
+		Direction: proto_common.PluginNodePermission_OUTBOUND,
+		OrgId:     "ORG1",
+	}
+	mockClient := proto_common.NewMockPluginNodePermissionServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		ConnectionAllowed(gomock.Any(), gomock.Eq(req)).
+		Return(&proto_common.PluginNodePermission_ConnectionResponse{
+			Allowed: false,
+			Reason:  "arbitrary reason",
+		}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	allowed, reason, err := testObject.ConnectionAllowed(context.Background(), &ConnectionRequest{
+		EnodeID:   "arbitrary id",
+		EnodeURL:  "arbitrary url",
+		IP:        "127.0.0.1",
+		TCPPort:   21000,
+		RaftPort:  50400,
+		Direction: Outbound,
+		OrgID:     "ORG1",
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, "arbitrary reason", reason)
+}
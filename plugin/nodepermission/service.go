@@ -0,0 +1,45 @@
+package nodepermission
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+type Direction int32
+
+const (
+	Inbound  = Direction(proto_common.PluginNodePermission_INBOUND)
+	Outbound = Direction(proto_common.PluginNodePermission_OUTBOUND)
+)
+
+// ConnectionRequest describes a p2p connection attempt
+type ConnectionRequest struct {
+	EnodeID   string
+	EnodeURL  string
+	IP        string
+	TCPPort   uint16
+	RaftPort  uint16
+	Direction Direction
+	OrgID     string // empty if the node doesn't belong to any organization
+}
+
+// PluginNodePermission decides if p2p connections are allowed
+type PluginNodePermission interface {
+	// ConnectionAllowed returns true if the connection is allowed, with an optional reason
+	ConnectionAllowed(ctx context.Context, req *ConnectionRequest) (bool, string, error)
+}
+
+type PluginNodePermissionDeferFunc func() (PluginNodePermission, error)
+
+type ReloadablePluginNodePermission struct {
+	DeferFunc PluginNodePermissionDeferFunc
+}
+
+func (d *ReloadablePluginNodePermission) ConnectionAllowed(ctx context.Context, req *ConnectionRequest) (bool, string, error) {
+	p, err := d.DeferFunc()
+	if err != nil {
+		return false, "", err
+	}
+	return p.ConnectionAllowed(ctx, req)
+}
@@ -5,25 +5,27 @@ import (
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/plugin/account"
-	"github.com/ethereum/go-ethereum/plugin/helloworld"
+	"github.com/ethereum/go-ethereum/plugin/blockvalidation"
+	"github.com/ethereum/go-ethereum/plugin/exporter"
+	"github.com/ethereum/go-ethereum/plugin/nodepermission"
 	"github.com/ethereum/go-ethereum/plugin/security"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// a template that returns the hello world plugin instance
-type HelloWorldPluginTemplate struct {
+// a template that returns the block validation plugin instance
+type BlockValidationPluginTemplate struct {
 	*basePlugin
 }
 
-func (p *HelloWorldPluginTemplate) Get() (helloworld.PluginHelloWorld, error) {
-	return &helloworld.ReloadablePluginHelloWorld{
-		DeferFunc: func() (helloworld.PluginHelloWorld, error) {
-			raw, err := p.dispense(helloworld.ConnectorName)
+func (p *BlockValidationPluginTemplate) Get() (blockvalidation.PluginBlockValidator, error) {
+	return &blockvalidation.ReloadablePluginBlockValidator{
+		DeferFunc: func() (blockvalidation.PluginBlockValidator, error) {
+			raw, err := p.dispense(blockvalidation.ConnectorName)
 			if err != nil {
 				return nil, err
 			}
-			return raw.(helloworld.PluginHelloWorld), nil
+			return raw.(blockvalidation.PluginBlockValidator), nil
 		},
 	}, nil
 }
@@ -96,3 +98,51 @@ func (f *ReloadableAccountServiceFactory) Create() (account.Service, error) {
 
 	return am, nil
 }
+
+// CreateSigningPolicy returns the signing policy enforced by the account plugin.
+// All requests are allowed if the plugin doesn't implement the signing policy service.
+func (f *ReloadableAccountServiceFactory) CreateSigningPolicy() account.SigningPolicy {
+	return &account.ReloadableSigningPolicy{
+		DispenseFunc: func() (account.SigningPolicy, error) {
+			raw, err := f.dispense(account.SigningPolicyConnectorName)
+			if err != nil {
+				return nil, err
+			}
+			return raw.(account.SigningPolicy), nil
+		},
+	}
+}
+
+// a template that returns the exporter plugin instance
+type ExporterPluginTemplate struct {
+	*basePlugin
+}
+
+func (p *ExporterPluginTemplate) Get() (exporter.PluginExporter, error) {
+	return &exporter.ReloadablePluginExporter{
+		DeferFunc: func() (exporter.PluginExporter, error) {
+			raw, err := p.dispense(exporter.ConnectorName)
+			if err != nil {
+				return nil, err
+			}
+			return raw.(exporter.PluginExporter), nil
+		},
+	}, nil
+}
+
+// a template that returns the node permission plugin instance
+type NodePermissionPluginTemplate struct {
+	*basePlugin
+}
+
+func (p *NodePermissionPluginTemplate) Get() (nodepermission.PluginNodePermission, error) {
+	return &nodepermission.ReloadablePluginNodePermission{
+		DeferFunc: func() (nodepermission.PluginNodePermission, error) {
+			raw, err := p.dispense(nodepermission.ConnectorName)
+			if err != nil {
+				return nil, err
+			}
+			return raw.(nodepermission.PluginNodePermission), nil
+		},
+	}, nil
+}
@@ -0,0 +1,134 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/plugin/rpcapi"
+	"github.com/ethereum/go-ethereum/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPluginRPCParams bounds the number of params of a method exposed by a plugin
+const maxPluginRPCParams = 32
+
+var (
+	rawMessagePointerType = reflect.TypeOf(&json.RawMessage{})
+	rawMessageType        = reflect.TypeOf(json.RawMessage{})
+	errorType             = reflect.TypeOf((*error)(nil)).Elem()
+	contextType           = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// implemented by plugins which can expose their own JSON RPC methods
+type rpcAPISource interface {
+	rpcAPI() (rpcapi.PluginRPCAPI, error)
+}
+
+// pluginRPCService exposes the JSON RPC methods advertised by a plugin under
+// the plugin@<interface> namespace. As the methods are registered in the RPC servers
+// like any other service, they are subject to the same authentication/authorization
+// and the authorized private state is forwarded to the plugin
+type pluginRPCService struct {
+	name   PluginInterfaceName
+	source rpcAPISource
+
+	mux     sync.Mutex
+	methods []rpcapi.Method // cached once successfully described by the plugin
+}
+
+func newPluginRPCService(name PluginInterfaceName, source rpcAPISource) *pluginRPCService {
+	return &pluginRPCService{
+		name:   name,
+		source: source,
+	}
+}
+
+// Callbacks implements rpc.DynamicService.
+//
+// It's invoked when the service is registered in the RPC servers which happens after
+// plugins have been started. The service is skipped if the plugin fails to describe its
+// methods, and so are the methods with an invalid number of params.
+func (s *pluginRPCService) Callbacks() (map[string]interface{}, error) {
+	methods, err := s.describe()
+	if err != nil {
+		return nil, err
+	}
+	callbacks := make(map[string]interface{}, len(methods))
+	for _, m := range methods {
+		if m.ParamsCount < 0 || m.ParamsCount > maxPluginRPCParams {
+			log.Error("Skipping plugin RPC method with an invalid number of params", "provider", s.name, "method", m.Name, "params", m.ParamsCount)
+			continue
+		}
+		callbacks[m.Name] = s.makeCallback(m)
+	}
+	return callbacks, nil
+}
+
+func (s *pluginRPCService) describe() ([]rpcapi.Method, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.methods != nil {
+		return s.methods, nil
+	}
+	api, err := s.source.rpcAPI()
+	if err != nil {
+		return nil, err
+	}
+	methods, err := api.Describe(context.Background())
+	if err != nil {
+		// plugin doesn't expose any JSON RPC methods
+		if rpcStatus, ok := status.FromError(err); ok && rpcStatus.Code() == codes.Unimplemented {
+			methods = []rpcapi.Method{}
+		} else {
+			return nil, err
+		}
+	}
+	s.methods = methods
+	return s.methods, nil
+}
+
+// makeCallback builds func(context.Context, *json.RawMessage, ...) (json.RawMessage, error)
+// with m.ParamsCount params so params can be validated and decoded by the RPC server
+func (s *pluginRPCService) makeCallback(m rpcapi.Method) interface{} {
+	in := make([]reflect.Type, m.ParamsCount+1)
+	in[0] = contextType
+	for i := 1; i < len(in); i++ {
+		in[i] = rawMessagePointerType
+	}
+	fnType := reflect.FuncOf(in, []reflect.Type{rawMessageType, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		ctx := args[0].Interface().(context.Context)
+		result, err := s.invoke(ctx, m.Name, args[1:])
+		errVal := reflect.Zero(errorType)
+		if err != nil {
+			errVal = reflect.ValueOf(err)
+		}
+		return []reflect.Value{reflect.ValueOf(result), errVal}
+	}).Interface()
+}
+
+func (s *pluginRPCService) invoke(ctx context.Context, method string, args []reflect.Value) (json.RawMessage, error) {
+	// optional params not provided by the caller are not forwarded
+	count := len(args)
+	for count > 0 && args[count-1].IsNil() {
+		count--
+	}
+	params := make([]json.RawMessage, count)
+	for i := 0; i < count; i++ {
+		if args[i].IsNil() {
+			params[i] = json.RawMessage("null")
+		} else {
+			params[i] = *args[i].Interface().(*json.RawMessage)
+		}
+	}
+	api, err := s.source.rpcAPI()
+	if err != nil {
+		return nil, err
+	}
+	psi, _ := rpc.PrivateStateIdentifierFromContext(ctx)
+	return api.Invoke(ctx, psi.String(), method, params)
+}
@@ -0,0 +1,161 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/plugin/rpcapi"
+	"github.com/ethereum/go-ethereum/rpc"
+	testifyassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubRPCAPIPlugin struct {
+	methods       []rpcapi.Method
+	describeErr   error
+	describeCalls int
+
+	psi    string
+	method string
+	params []json.RawMessage
+}
+
+func (s *stubRPCAPIPlugin) rpcAPI() (rpcapi.PluginRPCAPI, error) {
+	return s, nil
+}
+
+func (s *stubRPCAPIPlugin) Describe(_ context.Context) ([]rpcapi.Method, error) {
+	s.describeCalls++
+	return s.methods, s.describeErr
+}
+
+func (s *stubRPCAPIPlugin) Invoke(_ context.Context, psi string, method string, params []json.RawMessage) (json.RawMessage, error) {
+	s.psi, s.method, s.params = psi, method, params
+	return json.RawMessage(fmt.Sprintf(`"%s with %d params"`, method, len(params))), nil
+}
+
+func TestPluginRPCService_whenRegistered(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		methods: []rpcapi.Method{{Name: "Greeting", ParamsCount: 2}},
+	}
+	server := rpc.NewServer()
+	defer server.Stop()
+
+	err := server.RegisterName("plugin@blockvalidation", newPluginRPCService(BlockValidationPluginInterfaceName, stub))
+	assert.NoError(err)
+
+	client := rpc.DialInProc(server)
+	defer client.Close()
+	var result string
+	err = client.CallContext(rpc.WithPrivateStateIdentifier(context.Background(), types.PrivateStateIdentifier("arbitraryPSI")), &result, "plugin@blockvalidation_greeting", "arbitrary msg")
+
+	assert.NoError(err)
+	assert.Equal("Greeting with 1 params", result)
+	assert.Equal("Greeting", stub.method)
+	assert.Equal([]json.RawMessage{json.RawMessage(`"arbitrary msg"`)}, stub.params)
+}
+
+func TestPluginRPCService_Callbacks_whenDescribeIsCached(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		methods: []rpcapi.Method{{Name: "greeting", ParamsCount: 1}},
+	}
+	testObject := newPluginRPCService(BlockValidationPluginInterfaceName, stub)
+
+	_, err := testObject.Callbacks()
+	assert.NoError(err)
+	callbacks, err := testObject.Callbacks()
+
+	assert.NoError(err)
+	assert.Contains(callbacks, "greeting")
+	assert.Equal(1, stub.describeCalls)
+}
+
+func TestPluginRPCService_Callbacks_whenNotImplemented(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		describeErr: status.Error(codes.Unimplemented, "arbitrary error"),
+	}
+	testObject := newPluginRPCService(BlockValidationPluginInterfaceName, stub)
+
+	callbacks, err := testObject.Callbacks()
+
+	assert.NoError(err)
+	assert.Empty(callbacks)
+}
+
+func TestPluginRPCService_Callbacks_whenDescribeFails(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		describeErr: fmt.Errorf("arbitrary error"),
+	}
+	testObject := newPluginRPCService(BlockValidationPluginInterfaceName, stub)
+
+	_, err := testObject.Callbacks()
+	assert.EqualError(err, "arbitrary error")
+	stub.describeErr = nil
+	stub.methods = []rpcapi.Method{{Name: "greeting"}}
+	callbacks, err := testObject.Callbacks()
+
+	assert.NoError(err)
+	assert.Contains(callbacks, "greeting")
+}
+
+func TestPluginRPCService_whenRegisteredAndDescribeFails(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		describeErr: fmt.Errorf("arbitrary error"),
+	}
+	server := rpc.NewServer()
+	defer server.Stop()
+
+	err := server.RegisterName("plugin@blockvalidation", newPluginRPCService(BlockValidationPluginInterfaceName, stub))
+
+	assert.NoError(err, "the other services must be served")
+}
+
+func TestPluginRPCService_Callbacks_whenInvalidParamsCount(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		methods: []rpcapi.Method{
+			{Name: "negative", ParamsCount: -1},
+			{Name: "huge", ParamsCount: 1 << 30},
+			{Name: "greeting", ParamsCount: maxPluginRPCParams},
+		},
+	}
+	testObject := newPluginRPCService(BlockValidationPluginInterfaceName, stub)
+
+	callbacks, err := testObject.Callbacks()
+
+	assert.NoError(err)
+	assert.Len(callbacks, 1)
+	assert.Contains(callbacks, "greeting")
+}
+
+func TestPluginRPCService_invoke_whenOptionalParams(t *testing.T) {
+	assert := testifyassert.New(t)
+	stub := &stubRPCAPIPlugin{
+		methods: []rpcapi.Method{{Name: "greeting", ParamsCount: 3}},
+	}
+	testObject := newPluginRPCService(BlockValidationPluginInterfaceName, stub)
+	callbacks, err := testObject.Callbacks()
+	assert.NoError(err)
+	fn := callbacks["greeting"].(func(context.Context, *json.RawMessage, *json.RawMessage, *json.RawMessage) (json.RawMessage, error))
+
+	first := json.RawMessage(`1`)
+	_, err = fn(rpc.WithPrivateStateIdentifier(context.Background(), types.PrivateStateIdentifier("arbitraryPSI")), &first, nil, nil)
+
+	assert.NoError(err)
+	assert.Equal("arbitraryPSI", stub.psi)
+	assert.Equal([]json.RawMessage{first}, stub.params)
+
+	_, err = fn(context.Background(), nil, &first, nil)
+
+	assert.NoError(err)
+	assert.Equal([]json.RawMessage{json.RawMessage("null"), first}, stub.params)
+}
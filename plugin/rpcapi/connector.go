@@ -1,15 +1,15 @@
-package helloworld
+package rpcapi
 
 import (
 	"context"
 
 	iplugin "github.com/ethereum/go-ethereum/internal/plugin"
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
 	"github.com/hashicorp/go-plugin"
-	"github.com/jpmorganchase/quorum-hello-world-plugin-sdk-go/proto"
 	"google.golang.org/grpc"
 )
 
-const ConnectorName = "ping"
+const ConnectorName = "rpcapi"
 
 type PluginConnector struct {
 	plugin.Plugin
@@ -21,6 +21,6 @@ func (p *PluginConnector) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error
 
 func (p *PluginConnector) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, cc *grpc.ClientConn) (interface{}, error) {
 	return &PluginGateway{
-		client: proto.NewPluginGreetingClient(cc),
+		client: proto_common.NewPluginRPCAPIServiceClient(cc),
 	}, nil
 }
@@ -0,0 +1,49 @@
+package rpcapi
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+)
+
+type PluginGateway struct {
+	client proto_common.PluginRPCAPIServiceClient
+}
+
+func (g *PluginGateway) Describe(ctx context.Context) ([]Method, error) {
+	resp, err := g.client.Describe(ctx, &proto_common.PluginRPCAPI_DescribeRequest{})
+	if err != nil {
+		return nil, err
+	}
+	methods := make([]Method, 0, len(resp.Methods))
+	for _, m := range resp.Methods {
+		if m == nil || len(m.Name) == 0 {
+			continue
+		}
+		methods = append(methods, Method{
+			Name:        m.Name,
+			ParamsCount: int(m.ParamsCount),
+		})
+	}
+	return methods, nil
+}
+
+func (g *PluginGateway) Invoke(ctx context.Context, psi string, method string, params []json.RawMessage) (json.RawMessage, error) {
+	rawParams := make([][]byte, len(params))
+	for i, p := range params {
+		rawParams[i] = p
+	}
+	resp, err := g.client.Invoke(ctx, &proto_common.PluginRPCAPI_InvokeRequest{
+		Method:                 method,
+		Params:                 rawParams,
+		PrivateStateIdentifier: psi,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Result) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	return resp.Result, nil
+}
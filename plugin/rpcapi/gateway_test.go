@@ -0,0 +1,59 @@
+package rpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/plugin/gen/proto_common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginGateway_Describe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := proto_common.NewMockPluginRPCAPIServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Describe(gomock.Any(), gomock.Any()).
+		Return(&proto_common.PluginRPCAPI_DescribeResponse{
+			Methods: []*proto_common.PluginRPCAPI_Method{
+				{Name: "greeting", ParamsCount: 1},
+				{Name: ""},
+			},
+		}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	actual, err := testObject.Describe(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Method{{Name: "greeting", ParamsCount: 1}}, actual)
+}
+
+func TestPluginGateway_Invoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &proto_common.PluginRPCAPI_InvokeRequest{
+		Method:                 "greeting",
+		Params:                 [][]byte{[]byte(`"arbitrary msg"`)},
+		PrivateStateIdentifier: "arbitraryPSI",
+	}
+	mockClient := proto_common.NewMockPluginRPCAPIServiceClient(ctrl)
+	mockClient.
+		EXPECT().
+		Invoke(gomock.Any(), gomock.Eq(req)).
+		Return(&proto_common.PluginRPCAPI_InvokeResponse{
+			Result: []byte(`"arbitrary response"`),
+		}, nil)
+
+	testObject := &PluginGateway{client: mockClient}
+
+	actual, err := testObject.Invoke(context.Background(), "arbitraryPSI", "greeting", []json.RawMessage{json.RawMessage(`"arbitrary msg"`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`"arbitrary response"`), actual)
+}
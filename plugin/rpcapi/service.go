@@ -0,0 +1,21 @@
+package rpcapi
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Method describes a JSON RPC method exposed by a plugin
+type Method struct {
+	Name        string
+	ParamsCount int
+}
+
+// PluginRPCAPI is implemented by plugins which expose their own JSON RPC methods
+type PluginRPCAPI interface {
+	// Describe returns the methods exposed by the plugin
+	Describe(ctx context.Context) ([]Method, error)
+	// Invoke calls the method with JSON-encoded positional params on behalf of
+	// a caller authorized to operate on the given private state
+	Invoke(ctx context.Context, psi string, method string, params []json.RawMessage) (json.RawMessage, error)
+}
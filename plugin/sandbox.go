@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// name of the directory inside the plugin workspace which the sandboxed plugin
+// uses as its temporary directory, the gRPC unix domain socket is created there
+const sandboxTempDirName = ".sandbox"
+
+// SandboxProfile launches the plugin process under a restrictive profile in order to
+// shrink the trust surface. The plugin has no network access other than the
+// unix domain socket used for gRPC, and the filesystem is read-only except for
+// the declared writable paths.
+//
+// Sandboxing is only supported on Linux and relies on bubblewrap (https://github.com/containers/bubblewrap).
+type SandboxProfile struct {
+	// absolute paths which the plugin is allowed to write to
+	WritablePaths []string `json:"writablePaths,omitempty" toml:",omitempty"`
+	// path to the bubblewrap executable, default is to look up "bwrap" in PATH
+	Launcher string `json:"launcher,omitempty" toml:",omitempty"`
+}
+
+// validate checks the profile can be enforced using the given transport
+func (p *SandboxProfile) validate(transport *TransportConfig) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("sandbox is not supported on %s", runtime.GOOS)
+	}
+	if transport.Type != TransportAuto && transport.Type != TransportUnix {
+		return fmt.Errorf("sandbox requires %s transport as network access is disabled", TransportUnix)
+	}
+	for _, p := range p.WritablePaths {
+		if !filepath.IsAbs(p) {
+			return fmt.Errorf("sandbox writable path must be absolute: %s", p)
+		}
+	}
+	return nil
+}
+
+func (p *SandboxProfile) launcher() string {
+	if p.Launcher == "" {
+		return "bwrap"
+	}
+	return p.Launcher
+}
+
+// apply rewrites cmd so the plugin executable is launched inside the sandbox.
+// workspace is where the plugin has been unpacked.
+func (p *SandboxProfile) apply(cmd *exec.Cmd, workspace string) error {
+	launcher, err := exec.LookPath(p.launcher())
+	if err != nil {
+		return fmt.Errorf("sandbox launcher not found: %v", err)
+	}
+	tempDir := filepath.Join(workspace, sandboxTempDirName)
+	if err := os.MkdirAll(tempDir, 0700); err != nil {
+		return err
+	}
+	args := []string{
+		launcher,
+		"--die-with-parent",
+		"--new-session",
+		// network namespace only has a loopback interface
+		"--unshare-all",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		// plugin library creates the unix domain socket in the temporary directory
+		"--bind", tempDir, tempDir,
+		"--setenv", "TMPDIR", tempDir,
+	}
+	for _, path := range p.WritablePaths {
+		args = append(args, "--bind", path, path)
+	}
+	if cmd.Dir != "" {
+		args = append(args, "--chdir", cmd.Dir)
+	}
+	args = append(args, "--")
+	args = append(args, cmd.Args...)
+	cmd.Path = launcher
+	cmd.Args = args
+	return nil
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxProfile_validate(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		assert.Error(t, (&SandboxProfile{}).validate(&TransportConfig{Type: TransportAuto}))
+		return
+	}
+
+	assert.NoError(t, (&SandboxProfile{}).validate(&TransportConfig{Type: TransportAuto}))
+	assert.NoError(t, (&SandboxProfile{WritablePaths: []string{"/var/lib/plugin"}}).validate(&TransportConfig{Type: TransportUnix}))
+	assert.Error(t, (&SandboxProfile{}).validate(&TransportConfig{Type: TransportTCP}), "network must be available for TCP")
+	assert.Error(t, (&SandboxProfile{WritablePaths: []string{"relative/path"}}).validate(&TransportConfig{Type: TransportAuto}))
+}
+
+func TestSandboxProfile_apply(t *testing.T) {
+	launcher, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no executable available to act as the launcher")
+	}
+	workspace, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(workspace)
+	}()
+	executable := filepath.Join(workspace, "arbitrary-plugin")
+	cmd := exec.Command(executable, "arg1")
+	cmd.Dir = workspace
+	testObject := &SandboxProfile{
+		WritablePaths: []string{"/var/lib/plugin"},
+		Launcher:      launcher,
+	}
+
+	err = testObject.apply(cmd, workspace)
+
+	assert.NoError(t, err)
+	tempDir := filepath.Join(workspace, sandboxTempDirName)
+	assert.DirExists(t, tempDir)
+	assert.Equal(t, launcher, cmd.Path)
+	assert.Equal(t, launcher, cmd.Args[0])
+	assert.Contains(t, cmd.Args, "--unshare-all")
+	assert.Subset(t, cmd.Args, []string{"--bind", tempDir, "/var/lib/plugin"})
+	assert.Equal(t, []string{"--chdir", workspace, "--", executable, "arg1"}, cmd.Args[len(cmd.Args)-5:])
+}
+
+func TestSandboxProfile_apply_whenLauncherNotFound(t *testing.T) {
+	testObject := &SandboxProfile{
+		Launcher: "/non/existent/bwrap",
+	}
+
+	err := testObject.apply(exec.Command("arbitrary-plugin"), "")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+)
+
+// ChainedAuthenticationManager authenticates tokens using multiple authentication managers,
+// e.g. to accept tokens from two identity providers during a migration.
+//
+// A token is authenticated if at least one manager authenticates it. When more than one
+// does, granted authorities are merged and the earliest expiry is retained
+type ChainedAuthenticationManager struct {
+	managers []AuthenticationManager
+}
+
+// NewChainedAuthenticationManager returns a manager which tries the given managers in order.
+// Disabled managers are ignored
+func NewChainedAuthenticationManager(managers ...AuthenticationManager) AuthenticationManager {
+	enabled := make([]AuthenticationManager, 0, len(managers))
+	for _, m := range managers {
+		if _, ok := m.(*DisabledAuthenticationManager); ok {
+			continue
+		}
+		enabled = append(enabled, m)
+	}
+	if len(enabled) == 0 {
+		return NewDisabledAuthenticationManager()
+	}
+	return &ChainedAuthenticationManager{
+		managers: enabled,
+	}
+}
+
+func (c *ChainedAuthenticationManager) Authenticate(ctx context.Context, token string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	var (
+		authenticated []*proto.PreAuthenticatedAuthenticationToken
+		errs          []string
+	)
+	for _, m := range c.managers {
+		enabled, err := m.IsEnabled(ctx)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if !enabled {
+			continue
+		}
+		authToken, err := m.Authenticate(ctx, token)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		authenticated = append(authenticated, authToken)
+	}
+	if len(authenticated) == 0 {
+		if len(errs) == 0 {
+			return nil, errors.New("no authentication manager is enabled")
+		}
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+	return mergeTokens(authenticated), nil
+}
+
+func (c *ChainedAuthenticationManager) IsEnabled(ctx context.Context) (bool, error) {
+	var lastErr error
+	for _, m := range c.managers {
+		enabled, err := m.IsEnabled(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if enabled {
+			return true, nil
+		}
+	}
+	return false, lastErr
+}
+
+// mergeTokens combines authorities of all tokens, sorted and without duplicates.
+// Raw token is from the first token and expiry is the earliest one
+func mergeTokens(tokens []*proto.PreAuthenticatedAuthenticationToken) *proto.PreAuthenticatedAuthenticationToken {
+	if len(tokens) == 1 {
+		return tokens[0]
+	}
+	merged := &proto.PreAuthenticatedAuthenticationToken{
+		RawToken:  tokens[0].RawToken,
+		ExpiredAt: tokens[0].ExpiredAt,
+	}
+	seen := make(map[string]bool)
+	for _, t := range tokens {
+		if t.ExpiredAt != nil {
+			if merged.ExpiredAt == nil || before(t.ExpiredAt, merged.ExpiredAt) {
+				merged.ExpiredAt = t.ExpiredAt
+			}
+		}
+		for _, a := range t.Authorities {
+			key := strings.Join([]string{a.Service, a.Method, a.Raw}, "\x00")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged.Authorities = append(merged.Authorities, a)
+		}
+	}
+	sort.SliceStable(merged.Authorities, func(i, j int) bool {
+		a, b := merged.Authorities[i], merged.Authorities[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Raw < b.Raw
+	})
+	return merged
+}
+
+func before(a, b *timestamp.Timestamp) bool {
+	if a.Seconds != b.Seconds {
+		return a.Seconds < b.Seconds
+	}
+	return a.Nanos < b.Nanos
+}
@@ -0,0 +1,95 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+type stubAuthenticationManager struct {
+	token   *proto.PreAuthenticatedAuthenticationToken
+	err     error
+	enabled bool
+}
+
+func (s *stubAuthenticationManager) Authenticate(_ context.Context, _ string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	return s.token, s.err
+}
+
+func (s *stubAuthenticationManager) IsEnabled(_ context.Context) (bool, error) {
+	return s.enabled, nil
+}
+
+func TestChainedAuthenticationManager_Authenticate_whenOneSucceeds(t *testing.T) {
+	assert := testifyassert.New(t)
+	expected := &proto.PreAuthenticatedAuthenticationToken{
+		RawToken:    []byte("arbitrary token"),
+		Authorities: []*proto.GrantedAuthority{{Service: "eth", Method: "blockNumber"}},
+	}
+	testObject := NewChainedAuthenticationManager(
+		&stubAuthenticationManager{err: errors.New("unknown issuer"), enabled: true},
+		&stubAuthenticationManager{token: expected, enabled: true},
+	)
+
+	actual, err := testObject.Authenticate(context.Background(), "arbitrary token")
+
+	assert.NoError(err)
+	assert.Equal(expected, actual)
+}
+
+func TestChainedAuthenticationManager_Authenticate_whenAllFail(t *testing.T) {
+	testObject := NewChainedAuthenticationManager(
+		&stubAuthenticationManager{err: errors.New("unknown issuer 1"), enabled: true},
+		&stubAuthenticationManager{token: &proto.PreAuthenticatedAuthenticationToken{}, enabled: false},
+		&stubAuthenticationManager{err: errors.New("unknown issuer 2"), enabled: true},
+	)
+
+	_, err := testObject.Authenticate(context.Background(), "arbitrary token")
+
+	testifyassert.EqualError(t, err, "unknown issuer 1; unknown issuer 2")
+}
+
+func TestChainedAuthenticationManager_Authenticate_whenMergingAuthorities(t *testing.T) {
+	assert := testifyassert.New(t)
+	testObject := NewChainedAuthenticationManager(
+		&stubAuthenticationManager{enabled: true, token: &proto.PreAuthenticatedAuthenticationToken{
+			RawToken:  []byte("token 1"),
+			ExpiredAt: &timestamp.Timestamp{Seconds: 200},
+			Authorities: []*proto.GrantedAuthority{
+				{Service: "eth", Method: "sendTransaction"},
+				{Service: "admin", Method: "nodeInfo"},
+			},
+		}},
+		&stubAuthenticationManager{enabled: true, token: &proto.PreAuthenticatedAuthenticationToken{
+			RawToken:  []byte("token 2"),
+			ExpiredAt: &timestamp.Timestamp{Seconds: 100},
+			Authorities: []*proto.GrantedAuthority{
+				{Service: "eth", Method: "blockNumber"},
+				{Service: "admin", Method: "nodeInfo"},
+			},
+		}},
+	)
+
+	actual, err := testObject.Authenticate(context.Background(), "arbitrary token")
+
+	assert.NoError(err)
+	assert.Equal([]byte("token 1"), actual.RawToken)
+	assert.Equal(int64(100), actual.ExpiredAt.Seconds)
+	assert.Equal([]*proto.GrantedAuthority{
+		{Service: "admin", Method: "nodeInfo"},
+		{Service: "eth", Method: "blockNumber"},
+		{Service: "eth", Method: "sendTransaction"},
+	}, actual.Authorities)
+}
+
+func TestNewChainedAuthenticationManager_whenAllDisabled(t *testing.T) {
+	testObject := NewChainedAuthenticationManager(NewDisabledAuthenticationManager(), NewDisabledAuthenticationManager())
+
+	_, ok := testObject.(*DisabledAuthenticationManager)
+
+	testifyassert.True(t, ok)
+}
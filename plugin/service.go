@@ -5,11 +5,17 @@ import (
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/ethereum/go-ethereum/accounts/pluggable"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/plugin/events"
+	"github.com/ethereum/go-ethereum/plugin/exporter"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/go-hclog"
 )
 
 // this implements geth service
@@ -23,7 +29,14 @@ type PluginManager struct {
 	mux                sync.Mutex                            // control concurrent access to plugins cache
 	plugins            map[PluginInterfaceName]managedPlugin // lazy load the actual plugin templates
 	initializedPlugins map[PluginInterfaceName]managedPlugin // prepopulate during initialization of plugin manager, needed for starting/stopping/getting info
+	pluginLevels       [][]PluginInterfaceName               // start order of plugins based on their dependencies
 	pluginsStarted     *int32
+	metricsBridge      *metricsBridge          // re-export plugin metrics in the geth metrics registry
+	chainExporter      *exporter.ChainExporter // push chain data to the exporter plugin
+	eventHub           *events.Hub             // stream chain events to plugins, nil if no plugin is configured
+	// issue short-lived certificates for mutual TLS with plugins, nil if not managed by geth
+	certificateAuthority *certificateAuthority
+	certificateValidity  time.Duration
 }
 
 // this is called after PluginManager service has been successfully started
@@ -51,19 +64,25 @@ func (s *PluginManager) Start() (err error) {
 	}
 	log.Info("Starting all plugins", "count", initializedPluginsCount)
 	startedPlugins := make([]managedPlugin, 0, initializedPluginsCount)
-	for _, p := range s.initializedPlugins {
-		if err = p.Start(); err != nil {
+	// plugins in the same level are independent from each other
+	for _, level := range s.startupLevels() {
+		log.Debug("Starting plugins", "providers", level)
+		var started []managedPlugin
+		started, err = startInParallel(s.eagerPluginsOf(level))
+		startedPlugins = append(startedPlugins, started...)
+		if err != nil {
 			break
-		} else {
-			startedPlugins = append(startedPlugins, p)
 		}
 	}
 	if err != nil {
-		for _, p := range startedPlugins {
-			_ = p.Stop()
+		for i := len(startedPlugins) - 1; i >= 0; i-- {
+			_ = startedPlugins[i].Stop()
 		}
 	} else {
 		atomic.StoreInt32(s.pluginsStarted, 1)
+		if metrics.Enabled {
+			s.metricsBridge.start()
+		}
 	}
 	return
 }
@@ -133,10 +152,24 @@ func (s *PluginManager) GetPluginTemplate(name PluginInterfaceName, v managedPlu
 func (s *PluginManager) Stop() error {
 	initializedPluginsCount := len(s.initializedPlugins)
 	log.Info("Stopping all plugins", "count", initializedPluginsCount)
+	if s.metricsBridge != nil {
+		s.metricsBridge.stop()
+	}
+	if s.chainExporter != nil {
+		s.chainExporter.Stop()
+		s.chainExporter = nil
+	}
+	if s.eventHub != nil {
+		s.eventHub.Stop()
+	}
 	allErrors := make([]error, 0)
-	for _, p := range s.initializedPlugins {
-		if err := p.Stop(); err != nil {
-			allErrors = append(allErrors, err)
+	// stop dependent plugins first
+	levels := s.startupLevels()
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, p := range s.pluginsOf(levels[i]) {
+			if err := p.Stop(); err != nil {
+				allErrors = append(allErrors, err)
+			}
 		}
 	}
 	log.Info("All plugins stopped", "errors", allErrors)
@@ -149,6 +182,69 @@ func (s *PluginManager) Stop() error {
 	return fmt.Errorf("%s", allErrors)
 }
 
+// startupLevels returns the plugins grouped by dependency level, all plugins
+// are in a single level if no dependency has been declared
+func (s *PluginManager) startupLevels() [][]PluginInterfaceName {
+	if s.pluginLevels != nil {
+		return s.pluginLevels
+	}
+	level := make([]PluginInterfaceName, 0, len(s.initializedPlugins))
+	for name := range s.initializedPlugins {
+		level = append(level, name)
+	}
+	sortPluginInterfaceNames(level)
+	return [][]PluginInterfaceName{level}
+}
+
+func (s *PluginManager) pluginsOf(names []PluginInterfaceName) []managedPlugin {
+	plugins := make([]managedPlugin, 0, len(names))
+	for _, name := range names {
+		if p, ok := s.initializedPlugins[name]; ok {
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// eagerPluginsOf is the same as pluginsOf but leaves out lazily initialized plugins
+func (s *PluginManager) eagerPluginsOf(names []PluginInterfaceName) []managedPlugin {
+	plugins := make([]managedPlugin, 0, len(names))
+	for _, name := range names {
+		if s.settings != nil && s.settings.Providers[name].Lazy {
+			log.Debug("Deferring start of lazily initialized plugin", "provider", name)
+			continue
+		}
+		if p, ok := s.initializedPlugins[name]; ok {
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// ProvidersOf returns the names of all configured providers of a plugin interface,
+// the main provider comes first followed by the aliased ones in alphabetical order
+func (s *PluginManager) ProvidersOf(name PluginInterfaceName) []PluginInterfaceName {
+	names := make([]PluginInterfaceName, 0)
+	for n := range s.initializedPlugins {
+		if n.IsAlias() && n.Interface() == name {
+			names = append(names, n)
+		}
+	}
+	sortPluginInterfaceNames(names)
+	if _, ok := s.initializedPlugins[name]; ok {
+		names = append([]PluginInterfaceName{name}, names...)
+	}
+	return names
+}
+
+// transportConfig returns the configured transport or the default one
+func (s *PluginManager) transportConfig() *TransportConfig {
+	if s.settings == nil || s.settings.Transport == nil {
+		return &TransportConfig{Type: TransportAuto}
+	}
+	return s.settings.Transport
+}
+
 // Provide details of current plugins being used
 func (s *PluginManager) PluginsInfo() interface{} {
 	info := make(map[PluginInterfaceName]interface{})
@@ -163,6 +259,29 @@ func (s *PluginManager) PluginsInfo() interface{} {
 	return info
 }
 
+// healthSource is implemented by plugins able to report whether they are responsive
+type healthSource interface {
+	health() error
+}
+
+// PluginsHealth reports the health of each plugin: "ok", "not started" for lazily
+// initialized plugins not used yet, or the error preventing the plugin from responding
+func (s *PluginManager) PluginsHealth() map[PluginInterfaceName]string {
+	health := make(map[PluginInterfaceName]string)
+	for name, p := range s.initializedPlugins {
+		source, ok := p.(healthSource)
+		if !ok {
+			continue
+		}
+		if err := source.health(); err != nil {
+			health[name] = err.Error()
+		} else {
+			health[name] = "ok"
+		}
+	}
+	return health
+}
+
 // AddAccountPluginToBackend adds the account plugin to the provided account backend
 func (s *PluginManager) AddAccountPluginToBackend(b *pluggable.Backend) error {
 	v := new(ReloadableAccountServiceFactory)
@@ -176,9 +295,43 @@ func (s *PluginManager) AddAccountPluginToBackend(b *pluggable.Backend) error {
 	if err := b.SetPluginService(service); err != nil {
 		return err
 	}
+	b.SetSigningPolicy(v.CreateSigningPolicy())
 	return nil
 }
 
+// StartChainExporter starts pushing chain data from the provided backend to the exporter plugin,
+// with the private receipts of the private states granted in the exporter settings
+func (s *PluginManager) StartChainExporter(b exporter.Backend) error {
+	t := new(ExporterPluginTemplate)
+	if err := s.GetPluginTemplate(ExporterPluginInterfaceName, t); err != nil {
+		return err
+	}
+	p, err := t.Get()
+	if err != nil {
+		return err
+	}
+	var psis []types.PrivateStateIdentifier
+	if s.settings != nil && s.settings.Exporter != nil {
+		for _, psi := range s.settings.Exporter.PSIs {
+			psis = append(psis, types.ToPrivateStateIdentifier(psi))
+		}
+	}
+	chainExporter := exporter.NewChainExporter(b, p, psis)
+	if err := chainExporter.Start(); err != nil {
+		return err
+	}
+	s.chainExporter = chainExporter
+	return nil
+}
+
+// StartEventService starts streaming chain events from the provided backend to plugins
+func (s *PluginManager) StartEventService(b events.Backend) {
+	if s.eventHub == nil {
+		return
+	}
+	s.eventHub.Start(b)
+}
+
 func (s *PluginManager) Reload(name PluginInterfaceName) (bool, error) {
 	p, ok := s.getPlugin(name)
 	if !ok {
@@ -191,14 +344,47 @@ func (s *PluginManager) Reload(name PluginInterfaceName) (bool, error) {
 	return true, nil
 }
 
+// SetLogLevel changes verbosity of logs forwarded from the given plugin at runtime
+func (s *PluginManager) SetLogLevel(name PluginInterfaceName, level string) (bool, error) {
+	p, ok := s.getPlugin(name)
+	if !ok {
+		return false, fmt.Errorf("no such plugin provider: %s", name)
+	}
+	l, err := parseLogLevel(level)
+	if err != nil {
+		return false, err
+	}
+	leveler, ok := p.(logLeveler)
+	if !ok {
+		return false, fmt.Errorf("plugin provider %s doesn't support changing log level", name)
+	}
+	leveler.setLogLevel(l)
+	return true, nil
+}
+
+// implemented by plugins whose logs are forwarded to geth
+type logLeveler interface {
+	setLogLevel(level hclog.Level)
+}
+
 // this is to configure delegate APIs call to the plugins
 func (s *PluginManager) delegateAPIs() []rpc.API {
 	apis := make([]rpc.API, 0)
 	for _, p := range s.initializedPlugins {
 		interfaceName, _ := p.Info()
-		if pluginProvider, ok := pluginProviders[interfaceName]; ok {
+		namespace := fmt.Sprintf("plugin@%s", interfaceName)
+		// expose methods advertised by the plugin itself, if any
+		if source, ok := p.(rpcAPISource); ok {
+			log.Debug("adding RPC API exposed by plugin", "provider", interfaceName, "namespace", namespace)
+			apis = append(apis, rpc.API{
+				Namespace: namespace,
+				Version:   "1.0.0",
+				Service:   newPluginRPCService(interfaceName, source),
+				Public:    true,
+			})
+		}
+		if pluginProvider, ok := pluginProviders[interfaceName.Interface()]; ok && !interfaceName.IsAlias() {
 			if pluginProvider.apiProviderFunc != nil {
-				namespace := fmt.Sprintf("plugin@%s", interfaceName)
 				log.Debug("adding RPC API delegate for plugin", "provider", interfaceName, "namespace", namespace)
 				if delegates, err := pluginProvider.apiProviderFunc(namespace, s); err != nil {
 					log.Error("unable to delegate RPC API calls to plugin", "provider", interfaceName, "error", err)
@@ -212,16 +398,52 @@ func (s *PluginManager) delegateAPIs() []rpc.API {
 }
 
 func NewPluginManager(nodeName string, settings *Settings, skipVerify bool, localVerify bool, publicKey string) (*PluginManager, error) {
+	if settings.Transport != nil {
+		// the named pipes created by the plugins are not restricted to the user of the node
+		if settings.Transport.Type == TransportNamedPipe && settings.MutualTLS == nil {
+			return nil, fmt.Errorf("plugin: transport %s requires mutualTLS", TransportNamedPipe)
+		}
+		if err := settings.Transport.Validate(); err != nil {
+			return nil, fmt.Errorf("plugin: %v", err)
+		}
+	}
+	for name, definition := range settings.Providers {
+		if definition.Sandbox == nil {
+			continue
+		}
+		transport := settings.Transport
+		if transport == nil {
+			transport = &TransportConfig{Type: TransportAuto}
+		}
+		if err := definition.Sandbox.validate(transport); err != nil {
+			return nil, fmt.Errorf("plugin: %s: %v", name, err)
+		}
+	}
+	pluginLevels, err := startupLevels(settings.Providers)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: %v", err)
+	}
 	pm := &PluginManager{
 		nodeName:           nodeName,
 		pluginBaseDir:      settings.BaseDir.String(),
 		centralClient:      NewPluginCentralClient(settings.CentralConfig),
 		plugins:            make(map[PluginInterfaceName]managedPlugin),
 		initializedPlugins: make(map[PluginInterfaceName]managedPlugin),
+		pluginLevels:       pluginLevels,
+		eventHub:           events.NewHub(),
 		settings:           settings,
 		pluginsStarted:     new(int32),
 	}
 	pm.downloader = NewDownloader(pm)
+	if settings.MutualTLS != nil {
+		if pm.certificateValidity, err = settings.MutualTLS.validity(); err != nil {
+			return nil, fmt.Errorf("plugin: %v", err)
+		}
+		if pm.certificateAuthority, err = newCertificateAuthority(); err != nil {
+			return nil, fmt.Errorf("plugin: unable to create certificate authority: %v", err)
+		}
+	}
+	pm.metricsBridge = newMetricsBridge(pm, metrics.DefaultRegistry, defaultMetricsCollectInterval)
 	if skipVerify {
 		log.Warn("plugin: ignore integrity verification")
 		pm.verifier = NewNonVerifier()
@@ -233,10 +455,23 @@ func NewPluginManager(nodeName string, settings *Settings, skipVerify bool, loca
 	}
 	for pluginName, pluginDefinition := range settings.Providers {
 		log.Debug("Preparing plugin", "provider", pluginName, "name", pluginDefinition.Name, "version", pluginDefinition.Version)
-		pluginProvider, ok := pluginProviders[pluginName]
+		pluginProvider, ok := pluginProviders[pluginName.Interface()]
 		if !ok {
 			return nil, fmt.Errorf("plugin: [%s] is not supported", pluginName)
 		}
+		if pluginName.IsAlias() {
+			if !pluginProvider.multiple {
+				return nil, fmt.Errorf("plugin: [%s] doesn't support multiple providers", pluginName.Interface())
+			}
+			if _, ok := settings.Providers[pluginName.Interface()]; !ok {
+				return nil, fmt.Errorf("plugin: [%s] requires [%s] to be configured", pluginName, pluginName.Interface())
+			}
+		}
+		for _, dependency := range pluginDefinition.DependsOn {
+			if settings.Providers[dependency].Lazy && !pluginDefinition.Lazy {
+				return nil, fmt.Errorf("plugin: [%s] can't depend on lazily initialized [%s]", pluginName, dependency)
+			}
+		}
 		base, err := newBasePlugin(pm, pluginName, pluginDefinition, pluginProvider.pluginSet)
 		if err != nil {
 			return nil, fmt.Errorf("plugin [%s] %s", pluginName, err.Error())
@@ -12,8 +12,8 @@ import (
 func typicalPluginManager(t *testing.T) *PluginManager {
 	testObject, err := NewPluginManager("arbitraryName", &Settings{
 		Providers: map[PluginInterfaceName]PluginDefinition{
-			HelloWorldPluginInterfaceName: {
-				Name:    "arbitrary-helloWorld",
+			BlockValidationPluginInterfaceName: {
+				Name:    "arbitrary-blockValidation",
 				Version: "1.0.0",
 				Config:  "arbitrary config",
 			},
@@ -35,8 +35,8 @@ func TestPluginManager_ProvidersPopulation(t *testing.T) {
 
 	testObject, err := NewPluginManager("arbitraryName", &Settings{
 		Providers: map[PluginInterfaceName]PluginDefinition{
-			HelloWorldPluginInterfaceName: {
-				Name:    "arbitrary-helloWorld",
+			BlockValidationPluginInterfaceName: {
+				Name:    "arbitrary-blockValidation",
 				Version: "1.0.0",
 				Config:  "arbitrary config",
 			},
@@ -49,7 +49,7 @@ func TestPluginManager_ProvidersPopulation(t *testing.T) {
 	}, false, false, "")
 
 	testifyassert.NoError(t, err)
-	testifyassert.Equal(t, fmt.Sprintf("arbitrary-helloWorld-1.0.0-%s-%s", runtime.GOOS, runtime.GOARCH), testObject.initializedPlugins[HelloWorldPluginInterfaceName].(*basePlugin).pluginDefinition.FullName())
+	testifyassert.Equal(t, fmt.Sprintf("arbitrary-blockValidation-1.0.0-%s-%s", runtime.GOOS, runtime.GOARCH), testObject.initializedPlugins[BlockValidationPluginInterfaceName].(*basePlugin).pluginDefinition.FullName())
 	testifyassert.Equal(t, fmt.Sprintf("foo-bar-2.0.0-%s-%s", runtime.GOOS, runtime.GOARCH), testObject.initializedPlugins[arbitraryPluginInterfaceName].(*basePlugin).pluginDefinition.FullName())
 }
 
@@ -57,8 +57,8 @@ func TestPluginManager_GetPluginTemplate_whenTypical(t *testing.T) {
 	assert := testifyassert.New(t)
 	testObject := typicalPluginManager(t)
 
-	p := new(HelloWorldPluginTemplate)
-	err := testObject.GetPluginTemplate(HelloWorldPluginInterfaceName, p)
+	p := new(BlockValidationPluginTemplate)
+	err := testObject.GetPluginTemplate(BlockValidationPluginInterfaceName, p)
 
 	assert.NoError(err)
 	assert.NotNil(p)
@@ -67,12 +67,12 @@ func TestPluginManager_GetPluginTemplate_whenTypical(t *testing.T) {
 func TestPluginManager_GetPlugin_whenReadFromCache(t *testing.T) {
 	assert := testifyassert.New(t)
 	testObject := typicalPluginManager(t)
-	p := new(HelloWorldPluginTemplate)
-	err := testObject.GetPluginTemplate(HelloWorldPluginInterfaceName, p)
+	p := new(BlockValidationPluginTemplate)
+	err := testObject.GetPluginTemplate(BlockValidationPluginInterfaceName, p)
 	assert.NoError(err)
 	assert.NotNil(p)
 
-	actual, ok := testObject.getPlugin(HelloWorldPluginInterfaceName)
+	actual, ok := testObject.getPlugin(BlockValidationPluginInterfaceName)
 
 	assert.True(ok)
 	assert.Equal(p, actual)
@@ -82,7 +82,7 @@ func TestPluginManager_GetPlugin_whenReadFromInitializedPluginsCache(t *testing.
 	assert := testifyassert.New(t)
 	testObject := typicalPluginManager(t)
 
-	actual, ok := testObject.getPlugin(HelloWorldPluginInterfaceName)
+	actual, ok := testObject.getPlugin(BlockValidationPluginInterfaceName)
 
 	assert.True(ok)
 	assert.IsType(new(basePlugin), actual)
@@ -91,13 +91,13 @@ func TestPluginManager_GetPlugin_whenReadFromInitializedPluginsCache(t *testing.
 func TestPluginManager_GetPluginTemplate_whenReadFromCache(t *testing.T) {
 	assert := testifyassert.New(t)
 	testObject := typicalPluginManager(t)
-	p := new(HelloWorldPluginTemplate)
-	err := testObject.GetPluginTemplate(HelloWorldPluginInterfaceName, p)
+	p := new(BlockValidationPluginTemplate)
+	err := testObject.GetPluginTemplate(BlockValidationPluginInterfaceName, p)
 	assert.NoError(err)
 	assert.NotNil(p)
 
-	actual := new(HelloWorldPluginTemplate)
-	err = testObject.GetPluginTemplate(HelloWorldPluginInterfaceName, actual)
+	actual := new(BlockValidationPluginTemplate)
+	err = testObject.GetPluginTemplate(BlockValidationPluginInterfaceName, actual)
 
 	assert.NoError(err)
 	assert.Equal(p, actual)
@@ -108,7 +108,7 @@ func TestPluginManager_GetPluginTemplate_whenPluginTemplateNotExtendBasePlugin(t
 	testObject := typicalPluginManager(t)
 
 	invalid := new(invalidPluginTemplate)
-	err := testObject.GetPluginTemplate(HelloWorldPluginInterfaceName, invalid)
+	err := testObject.GetPluginTemplate(BlockValidationPluginInterfaceName, invalid)
 
 	t.Log(err)
 	assert.Error(err)
@@ -119,7 +119,7 @@ func TestPluginManager_GetPluginTemplate_whenPluginTemplateNotExtendPointerBaseP
 	testObject := typicalPluginManager(t)
 
 	invalid := new(invalidPluginTemplateNoPointer)
-	err := testObject.GetPluginTemplate(HelloWorldPluginInterfaceName, invalid)
+	err := testObject.GetPluginTemplate(BlockValidationPluginInterfaceName, invalid)
 
 	t.Log(err)
 	assert.Error(err)
@@ -144,3 +144,107 @@ func (i invalidPluginTemplate) Stop() error {
 func (i invalidPluginTemplate) Info() (PluginInterfaceName, interface{}) {
 	panic("implement me")
 }
+
+func TestNewPluginManager_whenAliasedProviders(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	testObject, err := NewPluginManager("arbitraryName", &Settings{
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			SecurityPluginInterfaceName:              {Name: "arbitrary-security", Version: "1.0.0"},
+			SecurityPluginInterfaceName + ".zlegacy": {Name: "arbitrary-security", Version: "0.9.0"},
+			SecurityPluginInterfaceName + ".legacy":  {Name: "arbitrary-security", Version: "0.8.0"},
+		},
+	}, false, false, "")
+
+	assert.NoError(err)
+	assert.Equal([]PluginInterfaceName{"security", "security.legacy", "security.zlegacy"}, testObject.ProvidersOf(SecurityPluginInterfaceName))
+}
+
+func TestNewPluginManager_whenAliasNotSupported(t *testing.T) {
+	_, err := NewPluginManager("arbitraryName", &Settings{
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			BlockValidationPluginInterfaceName:          {Name: "arbitrary-blockValidation", Version: "1.0.0"},
+			BlockValidationPluginInterfaceName + ".two": {Name: "arbitrary-blockValidation", Version: "1.0.0"},
+		},
+	}, false, false, "")
+
+	testifyassert.EqualError(t, err, "plugin: [blockvalidation] doesn't support multiple providers")
+}
+
+func TestNewPluginManager_whenAliasWithoutMainProvider(t *testing.T) {
+	_, err := NewPluginManager("arbitraryName", &Settings{
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			SecurityPluginInterfaceName + ".legacy": {Name: "arbitrary-security", Version: "1.0.0"},
+		},
+	}, false, false, "")
+
+	testifyassert.EqualError(t, err, "plugin: [security.legacy] requires [security] to be configured")
+}
+
+func TestNewPluginManager_whenSandboxWithTCPTransport(t *testing.T) {
+	_, err := NewPluginManager("arbitraryName", &Settings{
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			SecurityPluginInterfaceName: {Name: "arbitrary-security", Version: "1.0.0", Sandbox: &SandboxProfile{}},
+		},
+		Transport: &TransportConfig{Type: TransportTCP},
+	}, false, false, "")
+
+	testifyassert.Error(t, err)
+}
+
+func TestNewPluginManager_whenNamedPipeTransportWithoutMutualTLS(t *testing.T) {
+	_, err := NewPluginManager("arbitraryName", &Settings{
+		Transport: &TransportConfig{Type: TransportNamedPipe},
+	}, false, false, "")
+
+	testifyassert.EqualError(t, err, "plugin: transport npipe requires mutualTLS")
+}
+
+func TestPluginManager_SetLogLevel(t *testing.T) {
+	assert := testifyassert.New(t)
+	testObject := typicalPluginManager(t)
+
+	ok, err := testObject.SetLogLevel(BlockValidationPluginInterfaceName, "warn")
+
+	assert.NoError(err)
+	assert.True(ok)
+	p, _ := testObject.getPlugin(BlockValidationPluginInterfaceName)
+	_, info := p.Info()
+	assert.Equal("warn", info.(map[string]interface{})["logLevel"])
+
+	_, err = testObject.SetLogLevel(BlockValidationPluginInterfaceName, "arbitrary")
+	assert.EqualError(err, "invalid log level: arbitrary")
+
+	_, err = testObject.SetLogLevel(SecurityPluginInterfaceName, "info")
+	assert.EqualError(err, "no such plugin provider: security")
+}
+
+func TestNewPluginManager_whenLogLevelIsInvalid(t *testing.T) {
+	_, err := NewPluginManager("arbitraryName", &Settings{
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			BlockValidationPluginInterfaceName: {Name: "arbitrary-blockValidation", Version: "1.0.0", LogLevel: "arbitrary"},
+		},
+	}, false, false, "")
+
+	testifyassert.Error(t, err)
+}
+
+func TestPluginManager_PluginsHealth(t *testing.T) {
+	assert := testifyassert.New(t)
+	lazy, err := newBasePlugin(NewEmptyPluginManager(), BlockValidationPluginInterfaceName, PluginDefinition{Name: "arbitrary-blockValidation", Lazy: true}, plugin.PluginSet{})
+	assert.NoError(err)
+	stopped, err := newBasePlugin(NewEmptyPluginManager(), AccountPluginInterfaceName, PluginDefinition{Name: "arbitrary-account"}, plugin.PluginSet{})
+	assert.NoError(err)
+	testObject := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			BlockValidationPluginInterfaceName: lazy,
+			AccountPluginInterfaceName:         stopped,
+			ExporterPluginInterfaceName:        &stubMetricsPlugin{},
+		},
+	}
+
+	assert.Equal(map[PluginInterfaceName]string{
+		BlockValidationPluginInterfaceName: "not started",
+		AccountPluginInterfaceName:         "plugin is not running",
+	}, testObject.PluginsHealth())
+}
@@ -12,44 +12,38 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/plugin/account"
-	"github.com/ethereum/go-ethereum/plugin/helloworld"
+	"github.com/ethereum/go-ethereum/plugin/blockvalidation"
+	"github.com/ethereum/go-ethereum/plugin/exporter"
+	"github.com/ethereum/go-ethereum/plugin/nodepermission"
 	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/naoina/toml"
 )
 
 const (
-	HelloWorldPluginInterfaceName = PluginInterfaceName("helloworld") // lower-case always
-	SecurityPluginInterfaceName   = PluginInterfaceName("security")
-	AccountPluginInterfaceName    = PluginInterfaceName("account")
+	BlockValidationPluginInterfaceName = PluginInterfaceName("blockvalidation") // lower-case always
+	SecurityPluginInterfaceName        = PluginInterfaceName("security")
+	AccountPluginInterfaceName         = PluginInterfaceName("account")
+	ExporterPluginInterfaceName        = PluginInterfaceName("exporter")
+	NodePermissionPluginInterfaceName  = PluginInterfaceName("nodepermission")
 )
 
+// separates the plugin interface name from an alias when configuring multiple providers
+// of the same interface, e.g.: security.legacy
+const pluginAliasSeparator = "."
+
 var (
 	// define additional plugins being supported here
 	pluginProviders = map[PluginInterfaceName]pluginProvider{
-		HelloWorldPluginInterfaceName: {
-			apiProviderFunc: func(ns string, pm *PluginManager) ([]rpc.API, error) {
-				template := new(HelloWorldPluginTemplate)
-				if err := pm.GetPluginTemplate(HelloWorldPluginInterfaceName, template); err != nil {
-					return nil, err
-				}
-				service, err := template.Get()
-				if err != nil {
-					return nil, err
-				}
-				return []rpc.API{{
-					Namespace: ns,
-					Version:   "1.0.0",
-					Service:   service,
-					Public:    true,
-				}}, nil
-			},
+		BlockValidationPluginInterfaceName: {
 			pluginSet: plugin.PluginSet{
-				helloworld.ConnectorName: &helloworld.PluginConnector{},
+				blockvalidation.ConnectorName: &blockvalidation.PluginConnector{},
 			},
 		},
 		SecurityPluginInterfaceName: {
+			multiple: true,
 			pluginSet: plugin.PluginSet{
 				security.TLSConfigurationConnectorName: &security.TLSConfigurationSourcePluginConnector{},
 				security.AuthenticationConnectorName:   &security.AuthenticationManagerPluginConnector{},
@@ -73,19 +67,32 @@ var (
 				}}, nil
 			},
 			pluginSet: plugin.PluginSet{
-				account.ConnectorName: &account.PluginConnector{},
+				account.ConnectorName:              &account.PluginConnector{},
+				account.SigningPolicyConnectorName: &account.SigningPolicyPluginConnector{},
+			},
+		},
+		ExporterPluginInterfaceName: {
+			pluginSet: plugin.PluginSet{
+				exporter.ConnectorName: &exporter.PluginConnector{},
+			},
+		},
+		NodePermissionPluginInterfaceName: {
+			pluginSet: plugin.PluginSet{
+				nodepermission.ConnectorName: &nodepermission.PluginConnector{},
 			},
 		},
 	}
 
 	// this is the place holder for future solution of the plugin central
 	quorumPluginCentralConfiguration = &PluginCentralConfiguration{
-		CertFingerprint:        "",
-		BaseURL:                "https://artifacts.consensys.net/public/quorum-go-plugins/",
-		PublicKeyURI:           DefaultPublicKeyFile,
-		InsecureSkipTLSVerify:  false,
-		PluginDistPathTemplate: "maven/bin/{{.Name}}/{{.Version}}/{{.Name}}-{{.Version}}-{{.OS}}-{{.Arch}}.zip",
-		PluginSigPathTemplate:  "maven/bin/{{.Name}}/{{.Version}}/{{.Name}}-{{.Version}}-{{.OS}}-{{.Arch}}-sha256.checksum.asc",
+		CertFingerprint:            "",
+		BaseURL:                    "https://artifacts.consensys.net/public/quorum-go-plugins/",
+		PublicKeyURI:               DefaultPublicKeyFile,
+		InsecureSkipTLSVerify:      false,
+		PluginDistPathTemplate:     "maven/bin/{{.Name}}/{{.Version}}/{{.Name}}-{{.Version}}-{{.OS}}-{{.Arch}}.zip",
+		PluginSigPathTemplate:      "maven/bin/{{.Name}}/{{.Version}}/{{.Name}}-{{.Version}}-{{.OS}}-{{.Arch}}-sha256.checksum.asc",
+		PluginMetadataPathTemplate: "maven/bin/{{.Name}}/maven-metadata.xml",
+		MaxDownloadAttempts:        3,
 	}
 )
 
@@ -95,6 +102,9 @@ type pluginProvider struct {
 	apiProviderFunc rpcAPIProviderFunc
 	// contains connectors being registered to the plugin library
 	pluginSet plugin.PluginSet
+	// allows configuring additional providers of the same plugin interface using
+	// aliased names, e.g.: security.legacy
+	multiple bool
 }
 
 type rpcAPIProviderFunc func(ns string, pm *PluginManager) ([]rpc.API, error)
@@ -110,6 +120,43 @@ type PluginDefinition struct {
 	Version Version `json:"version" toml:""`
 	// plugin configuration in a form of map/slice/string
 	Config interface{} `json:"config,omitempty" toml:",omitempty"`
+	// plugin interfaces which must be started before this plugin, e.g.: ["security"]
+	DependsOn []PluginInterfaceName `json:"dependsOn,omitempty" toml:",omitempty"`
+	// launch the plugin process under a restrictive profile, nil value disables sandboxing
+	Sandbox *SandboxProfile `json:"sandbox,omitempty" toml:",omitempty"`
+	// minimum level of logs forwarded from the plugin: trace, debug, info, warn, error or off.
+	// Default is trace which leaves the filtering to geth verbosity
+	LogLevel string `json:"logLevel,omitempty" toml:",omitempty"`
+	// defer downloading and starting the plugin until it's used for the first time
+	Lazy bool `json:"lazy,omitempty" toml:",omitempty"`
+}
+
+func (d *PluginDefinition) logLevel() (hclog.Level, error) {
+	if d.LogLevel == "" {
+		return hclog.Trace, nil
+	}
+	return parseLogLevel(d.LogLevel)
+}
+
+// logLevelOff drops all logs from the plugin
+const logLevelOff = hclog.Error + 1
+
+func parseLogLevel(s string) (hclog.Level, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "off") {
+		return logLevelOff, nil
+	}
+	level := hclog.LevelFromString(s)
+	if level == hclog.NoLevel {
+		return level, fmt.Errorf("invalid log level: %s", s)
+	}
+	return level, nil
+}
+
+func logLevelString(level hclog.Level) string {
+	if level == logLevelOff {
+		return "off"
+	}
+	return level.String()
 }
 
 func ReadMultiFormatConfig(config interface{}) ([]byte, error) {
@@ -191,6 +238,20 @@ func (p *PluginInterfaceName) UnmarshalText(data []byte) error {
 	return nil
 }
 
+// Interface returns the plugin interface name without the alias,
+// e.g.: security for security.legacy
+func (p PluginInterfaceName) Interface() PluginInterfaceName {
+	if i := strings.Index(string(p), pluginAliasSeparator); i >= 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// IsAlias returns true if this names an additional provider of a plugin interface
+func (p PluginInterfaceName) IsAlias() bool {
+	return p != p.Interface()
+}
+
 func (p PluginInterfaceName) String() string {
 	return string(p)
 }
@@ -200,6 +261,16 @@ type Settings struct {
 	BaseDir       EnvironmentAwaredValue                   `json:"baseDir" toml:""`
 	CentralConfig *PluginCentralConfiguration              `json:"central" toml:"Central"`
 	Providers     map[PluginInterfaceName]PluginDefinition `json:"providers" toml:""`
+	Transport     *TransportConfig                         `json:"transport,omitempty" toml:",omitempty"`
+	MutualTLS     *MutualTLSConfig                         `json:"mutualTLS,omitempty" toml:",omitempty"`
+	Exporter      *ExporterConfig                          `json:"exporter,omitempty" toml:",omitempty"`
+}
+
+// ExporterConfig is the configuration of the node for the exporter plugin
+type ExporterConfig struct {
+	// private states whose private receipts are pushed to the exporter plugin, none
+	// by default. The plugin only receives the ones it also asks for.
+	PSIs []string `json:"psis,omitempty" toml:",omitempty"`
 }
 
 func (s *Settings) GetPluginDefinition(name PluginInterfaceName) (*PluginDefinition, bool) {
@@ -213,6 +284,10 @@ func (s *Settings) SetDefaults() {
 	} else {
 		s.CentralConfig.SetDefaults()
 	}
+	if s.Transport == nil {
+		s.Transport = &TransportConfig{}
+	}
+	s.Transport.SetDefaults()
 }
 
 // CheckSettingsAreSupported validates Settings by ensuring that only supportedPlugins are defined.
@@ -252,6 +327,12 @@ type PluginCentralConfiguration struct {
 	// URL path template to the plugin sha256 checksum signature file.
 	// It uses Golang text template.
 	PluginSigPathTemplate string `json:"pluginSigPathTemplate" toml:""`
+	// URL path template to the Maven metadata file listing available versions of the plugin.
+	// It uses Golang text template.
+	PluginMetadataPathTemplate string `json:"pluginMetadataPathTemplate" toml:""`
+	// Number of times a plugin distribution download is attempted before giving up.
+	// Each new attempt resumes from what has been downloaded so far.
+	MaxDownloadAttempts int `json:"maxDownloadAttempts" toml:""`
 }
 
 // populate default values from quorumPluginCentralConfiguration
@@ -268,6 +349,12 @@ func (c *PluginCentralConfiguration) SetDefaults() {
 	if len(c.PluginSigPathTemplate) == 0 {
 		c.PluginSigPathTemplate = quorumPluginCentralConfiguration.PluginSigPathTemplate
 	}
+	if len(c.PluginMetadataPathTemplate) == 0 {
+		c.PluginMetadataPathTemplate = quorumPluginCentralConfiguration.PluginMetadataPathTemplate
+	}
+	if c.MaxDownloadAttempts == 0 {
+		c.MaxDownloadAttempts = quorumPluginCentralConfiguration.MaxDownloadAttempts
+	}
 }
 
 // support URI format with 'env' scheme during JSON/TOML/TEXT unmarshalling
@@ -224,11 +224,11 @@ func TestAccountAPIProviderFunc_OnlyExposeAccountCreationAPI(t *testing.T) {
 func TestSettings_CheckSettingsAreSupported_AllSupported(t *testing.T) {
 	s := Settings{
 		Providers: map[PluginInterfaceName]PluginDefinition{
-			AccountPluginInterfaceName:    {},
-			HelloWorldPluginInterfaceName: {},
+			AccountPluginInterfaceName:         {},
+			BlockValidationPluginInterfaceName: {},
 		},
 	}
-	supported := []PluginInterfaceName{AccountPluginInterfaceName, HelloWorldPluginInterfaceName}
+	supported := []PluginInterfaceName{AccountPluginInterfaceName, BlockValidationPluginInterfaceName}
 
 	err := s.CheckSettingsAreSupported(supported)
 
@@ -238,8 +238,8 @@ func TestSettings_CheckSettingsAreSupported_AllSupported(t *testing.T) {
 func TestSettings_CheckSettingsAreSupported_NoneSupported(t *testing.T) {
 	s := Settings{
 		Providers: map[PluginInterfaceName]PluginDefinition{
-			AccountPluginInterfaceName:    {},
-			HelloWorldPluginInterfaceName: {},
+			AccountPluginInterfaceName:         {},
+			BlockValidationPluginInterfaceName: {},
 		},
 	}
 	supported := []PluginInterfaceName{}
@@ -248,7 +248,7 @@ func TestSettings_CheckSettingsAreSupported_NoneSupported(t *testing.T) {
 
 	require.Error(t, err)
 
-	wantMsgPattern := regexp.MustCompile(`^unsupported plugins configured: \[(account|helloworld) (account|helloworld)\]$`)
+	wantMsgPattern := regexp.MustCompile(`^unsupported plugins configured: \[(account|blockvalidation) (account|blockvalidation)\]$`)
 	matches := wantMsgPattern.FindStringSubmatch(err.Error())
 
 	// make sure the msg matches the pattern and the same plugin is not listed twice
@@ -264,13 +264,13 @@ func TestSettings_CheckSettingsAreSupported_NoneSupported(t *testing.T) {
 func TestSettings_CheckSettingsAreSupported_SomeSupported(t *testing.T) {
 	s := Settings{
 		Providers: map[PluginInterfaceName]PluginDefinition{
-			AccountPluginInterfaceName:    {},
-			HelloWorldPluginInterfaceName: {},
+			AccountPluginInterfaceName:         {},
+			BlockValidationPluginInterfaceName: {},
 		},
 	}
 	supported := []PluginInterfaceName{AccountPluginInterfaceName}
 
 	err := s.CheckSettingsAreSupported(supported)
 
-	require.EqualError(t, err, "unsupported plugins configured: [helloworld]")
+	require.EqualError(t, err, "unsupported plugins configured: [blockvalidation]")
 }
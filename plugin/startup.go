@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// startupLevels orders plugins by their declared dependencies. Plugins in the same
+// level don't depend on each other so they can be started in parallel, and each level
+// only depends on the previous ones
+func startupLevels(providers map[PluginInterfaceName]PluginDefinition) ([][]PluginInterfaceName, error) {
+	inDegree := make(map[PluginInterfaceName]int, len(providers))
+	dependents := make(map[PluginInterfaceName][]PluginInterfaceName)
+	for name, definition := range providers {
+		inDegree[name] += 0
+		seen := make(map[PluginInterfaceName]bool)
+		for _, dependency := range definition.DependsOn {
+			if dependency == name {
+				return nil, fmt.Errorf("plugin [%s] can't depend on itself", name)
+			}
+			if _, ok := providers[dependency]; !ok {
+				return nil, fmt.Errorf("plugin [%s] depends on [%s] which is not configured", name, dependency)
+			}
+			if seen[dependency] {
+				continue
+			}
+			seen[dependency] = true
+			inDegree[name]++
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+	levels := make([][]PluginInterfaceName, 0)
+	var current []PluginInterfaceName
+	for name, degree := range inDegree {
+		if degree == 0 {
+			current = append(current, name)
+		}
+	}
+	visited := 0
+	for len(current) > 0 {
+		sortPluginInterfaceNames(current)
+		levels = append(levels, current)
+		visited += len(current)
+		var next []PluginInterfaceName
+		for _, name := range current {
+			for _, dependent := range dependents[name] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		current = next
+	}
+	if visited != len(inDegree) {
+		cyclic := make([]PluginInterfaceName, 0)
+		for name, degree := range inDegree {
+			if degree > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sortPluginInterfaceNames(cyclic)
+		return nil, fmt.Errorf("circular dependency between plugins %v", cyclic)
+	}
+	return levels, nil
+}
+
+func sortPluginInterfaceNames(names []PluginInterfaceName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+}
+
+// startInParallel starts all plugins at the same time and returns the ones successfully started.
+// All errors are combined
+func startInParallel(plugins []managedPlugin) ([]managedPlugin, error) {
+	var (
+		wg      sync.WaitGroup
+		mux     sync.Mutex
+		started = make([]managedPlugin, 0, len(plugins))
+		errs    = make([]string, 0)
+	)
+	for _, p := range plugins {
+		wg.Add(1)
+		go func(p managedPlugin) {
+			defer wg.Done()
+			err := p.Start()
+			mux.Lock()
+			defer mux.Unlock()
+			if err != nil {
+				name, _ := p.Info()
+				errs = append(errs, fmt.Sprintf("[%s] %s", name, err))
+				return
+			}
+			started = append(started, p)
+		}(p)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return started, fmt.Errorf("plugin: unable to start %s", strings.Join(errs, ", "))
+	}
+	return started, nil
+}
@@ -0,0 +1,234 @@
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/plugin/blockvalidation"
+	"github.com/hashicorp/go-plugin"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+type recordingPlugin struct {
+	name     PluginInterfaceName
+	startErr error
+	delay    time.Duration
+	recorder *startupRecorder
+}
+
+type startupRecorder struct {
+	mux     sync.Mutex
+	started []PluginInterfaceName
+	stopped []PluginInterfaceName
+}
+
+func (p *recordingPlugin) Start() error {
+	time.Sleep(p.delay)
+	if p.startErr != nil {
+		return p.startErr
+	}
+	p.recorder.mux.Lock()
+	defer p.recorder.mux.Unlock()
+	p.recorder.started = append(p.recorder.started, p.name)
+	return nil
+}
+
+func (p *recordingPlugin) Stop() error {
+	p.recorder.mux.Lock()
+	defer p.recorder.mux.Unlock()
+	p.recorder.stopped = append(p.recorder.stopped, p.name)
+	return nil
+}
+
+func (p *recordingPlugin) Info() (PluginInterfaceName, interface{}) {
+	return p.name, nil
+}
+
+func TestStartupLevels(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	levels, err := startupLevels(map[PluginInterfaceName]PluginDefinition{
+		"security":        {},
+		"account":         {DependsOn: []PluginInterfaceName{"security"}},
+		"blockvalidation": {},
+		"exporter":        {DependsOn: []PluginInterfaceName{"account", "security"}},
+		"nodepermission":  {DependsOn: []PluginInterfaceName{"security", "security"}},
+	})
+
+	assert.NoError(err)
+	assert.Equal([][]PluginInterfaceName{
+		{"blockvalidation", "security"},
+		{"account", "nodepermission"},
+		{"exporter"},
+	}, levels)
+}
+
+func TestStartupLevels_whenDependencyNotConfigured(t *testing.T) {
+	_, err := startupLevels(map[PluginInterfaceName]PluginDefinition{
+		"account": {DependsOn: []PluginInterfaceName{"security"}},
+	})
+
+	testifyassert.EqualError(t, err, "plugin [account] depends on [security] which is not configured")
+}
+
+func TestStartupLevels_whenCircularDependency(t *testing.T) {
+	_, err := startupLevels(map[PluginInterfaceName]PluginDefinition{
+		"blockvalidation": {},
+		"security":        {DependsOn: []PluginInterfaceName{"account"}},
+		"account":         {DependsOn: []PluginInterfaceName{"security"}},
+	})
+
+	testifyassert.EqualError(t, err, "circular dependency between plugins [account security]")
+}
+
+func TestPluginManager_Start_whenDependenciesDeclared(t *testing.T) {
+	assert := testifyassert.New(t)
+	recorder := &startupRecorder{}
+	testObject := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			"security": &recordingPlugin{name: "security", delay: 50 * time.Millisecond, recorder: recorder},
+			"account":  &recordingPlugin{name: "account", recorder: recorder},
+		},
+		pluginLevels:   [][]PluginInterfaceName{{"security"}, {"account"}},
+		pluginsStarted: new(int32),
+	}
+
+	assert.NoError(testObject.Start())
+	assert.Equal([]PluginInterfaceName{"security", "account"}, recorder.started)
+
+	assert.NoError(testObject.Stop())
+	assert.Equal([]PluginInterfaceName{"account", "security"}, recorder.stopped)
+}
+
+func TestPluginManager_Start_whenIndependentPluginsStartInParallel(t *testing.T) {
+	recorder := &startupRecorder{}
+	plugins := make(map[PluginInterfaceName]managedPlugin)
+	for i := 0; i < 5; i++ {
+		name := PluginInterfaceName(fmt.Sprintf("arbitrary%d", i))
+		plugins[name] = &recordingPlugin{name: name, delay: 200 * time.Millisecond, recorder: recorder}
+	}
+	testObject := &PluginManager{
+		initializedPlugins: plugins,
+		pluginsStarted:     new(int32),
+	}
+
+	start := time.Now()
+	testifyassert.NoError(t, testObject.Start())
+
+	testifyassert.Len(t, recorder.started, 5)
+	testifyassert.True(t, time.Since(start) < 800*time.Millisecond, "plugins must be started in parallel")
+}
+
+func TestPluginManager_Start_whenFailedThenStopStartedPlugins(t *testing.T) {
+	assert := testifyassert.New(t)
+	recorder := &startupRecorder{}
+	testObject := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			"security": &recordingPlugin{name: "security", recorder: recorder},
+			"account":  &recordingPlugin{name: "account", startErr: fmt.Errorf("arbitrary error"), recorder: recorder},
+			"exporter": &recordingPlugin{name: "exporter", recorder: recorder},
+		},
+		pluginLevels:   [][]PluginInterfaceName{{"security"}, {"account"}, {"exporter"}},
+		pluginsStarted: new(int32),
+	}
+
+	err := testObject.Start()
+
+	assert.EqualError(err, "plugin: unable to start [account] arbitrary error")
+	assert.Equal([]PluginInterfaceName{"security"}, recorder.started)
+	assert.Equal([]PluginInterfaceName{"security"}, recorder.stopped)
+}
+
+func TestPluginManager_Start_whenLazyPluginDeclared(t *testing.T) {
+	assert := testifyassert.New(t)
+	recorder := &startupRecorder{}
+	testObject := &PluginManager{
+		initializedPlugins: map[PluginInterfaceName]managedPlugin{
+			"security": &recordingPlugin{name: "security", recorder: recorder},
+			"account":  &recordingPlugin{name: "account", recorder: recorder},
+		},
+		settings: &Settings{
+			Providers: map[PluginInterfaceName]PluginDefinition{
+				"security": {},
+				"account":  {Lazy: true},
+			},
+		},
+		pluginsStarted: new(int32),
+	}
+
+	assert.NoError(testObject.Start())
+	assert.Equal([]PluginInterfaceName{"security"}, recorder.started)
+}
+
+func TestBasePlugin_dispense_whenLazyThenStartOnFirstUse(t *testing.T) {
+	assert := testifyassert.New(t)
+	tmpDir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	var requestCount int32
+	arbitraryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		http.NotFound(w, req)
+	}))
+	defer arbitraryServer.Close()
+	testObject, err := NewPluginManager("arbitraryName", &Settings{
+		BaseDir:       EnvironmentAwaredValue(tmpDir),
+		CentralConfig: &PluginCentralConfiguration{BaseURL: arbitraryServer.URL},
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			BlockValidationPluginInterfaceName: {Name: "arbitrary-blockValidation", Version: "1.0.0", Lazy: true},
+		},
+	}, true, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testObject.settings.CentralConfig.SetDefaults()
+
+	assert.NoError(testObject.Start())
+	assert.Equal(int32(0), atomic.LoadInt32(&requestCount), "lazy plugin must not be downloaded on startup")
+
+	p, _ := testObject.getPlugin(BlockValidationPluginInterfaceName)
+	_, err = p.(*basePlugin).dispense(blockvalidation.ConnectorName)
+
+	assert.Error(err)
+	assert.Contains(err.Error(), "can't download from Plugin Central")
+	assert.Equal(int32(1), atomic.LoadInt32(&requestCount))
+
+	_, retryErr := p.(*basePlugin).dispense(blockvalidation.ConnectorName)
+
+	assert.Equal(err, retryErr, "the start error is returned to the following uses")
+	assert.Equal(int32(1), atomic.LoadInt32(&requestCount), "lazy plugin must be started once")
+}
+
+func TestBasePlugin_dispense_whenLazyAndStopped(t *testing.T) {
+	assert := testifyassert.New(t)
+	testObject, err := newBasePlugin(NewEmptyPluginManager(), BlockValidationPluginInterfaceName, PluginDefinition{Name: "arbitrary-blockValidation", Version: "1.0.0", Lazy: true}, plugin.PluginSet{})
+	assert.NoError(err)
+
+	assert.NoError(testObject.Stop())
+	_, err = testObject.dispense(blockvalidation.ConnectorName)
+
+	assert.Equal(errPluginStopped, err, "stopped plugin must not be started")
+	assert.Equal(errPluginStopped, testObject.health())
+}
+
+func TestNewPluginManager_whenDependingOnLazyPlugin(t *testing.T) {
+	_, err := NewPluginManager("arbitraryName", &Settings{
+		Providers: map[PluginInterfaceName]PluginDefinition{
+			SecurityPluginInterfaceName: {Name: "arbitrary-security", Version: "1.0.0", Lazy: true},
+			AccountPluginInterfaceName:  {Name: "arbitrary-account", Version: "1.0.0", DependsOn: []PluginInterfaceName{SecurityPluginInterfaceName}},
+		},
+	}, false, false, "")
+
+	testifyassert.EqualError(t, err, "plugin: [account] can't depend on lazily initialized [security]")
+}
@@ -0,0 +1,280 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	iplugin "github.com/ethereum/go-ethereum/internal/plugin"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/go-plugin"
+)
+
+type TransportType string
+
+const (
+	// TransportAuto uses unix domain sockets on unix-like OS and TCP on Windows
+	TransportAuto = TransportType("auto")
+	// TransportUnix uses unix domain sockets, not supported on Windows
+	TransportUnix = TransportType("unix")
+	// TransportTCP uses TCP on the loopback interface, authenticated by mutual TLS
+	TransportTCP = TransportType("tcp")
+	// TransportNamedPipe uses Windows named pipes, only supported on Windows and with
+	// mutual TLS managed by geth
+	TransportNamedPipe = TransportType("npipe")
+
+	// environment variables set for the plugin process so it can listen accordingly
+	envPluginTransport = "QUORUM_PLUGIN_TRANSPORT"
+	envPluginNamedPipe = "QUORUM_PLUGIN_NAMED_PIPE"
+
+	namedPipeStartTimeout = time.Minute
+	namedPipeDialTimeout  = 5 * time.Second
+)
+
+// TransportConfig defines how geth communicates with plugin processes
+type TransportConfig struct {
+	Type TransportType `json:"type" toml:""`
+	// port range the plugin listens on when using TCP transport
+	MinPort uint `json:"minPort,omitempty" toml:",omitempty"`
+	MaxPort uint `json:"maxPort,omitempty" toml:",omitempty"`
+}
+
+func (c *TransportConfig) SetDefaults() {
+	if c.Type == "" {
+		c.Type = TransportAuto
+	}
+}
+
+func (c *TransportConfig) Validate() error {
+	switch c.Type {
+	case TransportAuto, TransportTCP:
+	case TransportUnix:
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("transport %s is not supported on %s", c.Type, runtime.GOOS)
+		}
+	case TransportNamedPipe:
+		if runtime.GOOS != "windows" {
+			return fmt.Errorf("transport %s is not supported on %s", c.Type, runtime.GOOS)
+		}
+	default:
+		return fmt.Errorf("unknown transport %s", c.Type)
+	}
+	if c.MinPort > c.MaxPort {
+		return fmt.Errorf("invalid port range [%d, %d]", c.MinPort, c.MaxPort)
+	}
+	return nil
+}
+
+// environ returns environment variables instructing the plugin which transport to use
+func (c *TransportConfig) environ() []string {
+	return []string{fmt.Sprintf("%s=%s", envPluginTransport, c.Type)}
+}
+
+// namedPipeRelay starts the plugin process listening on a named pipe and exposes
+// the pipe as a unix domain socket in a private directory, which can be reattached
+// by the plugin library
+type namedPipeRelay struct {
+	dial     func(pipe string, timeout time.Duration) (net.Conn, error)
+	pipe     string
+	dir      string
+	listener net.Listener
+	logger   log.Logger
+
+	mux   sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// start launches the plugin process and returns the configuration to reattach to it
+func (r *namedPipeRelay) start(cmd *exec.Cmd) (*plugin.ReattachConfig, error) {
+	handshake := iplugin.DefaultHandshakeConfig
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env,
+		fmt.Sprintf("%s=%s", handshake.MagicCookieKey, handshake.MagicCookieValue),
+		fmt.Sprintf("PLUGIN_PROTOCOL_VERSIONS=%d", handshake.ProtocolVersion),
+		fmt.Sprintf("%s=%s", envPluginTransport, TransportNamedPipe),
+		fmt.Sprintf("%s=%s", envPluginNamedPipe, r.pipe),
+	)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = &logWriter{r.logger}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	kill := func() { _ = cmd.Process.Kill() }
+	lines := make(chan string, 1)
+	scanner := bufio.NewScanner(stdout)
+	go func() {
+		if scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+		// keep draining so the plugin never blocks on writing
+		for scanner.Scan() {
+			r.logger.Debug(scanner.Text())
+		}
+	}()
+	var line string
+	select {
+	case l, ok := <-lines:
+		if !ok {
+			kill()
+			return nil, fmt.Errorf("plugin exited before completing the handshake")
+		}
+		line = l
+	case <-time.After(namedPipeStartTimeout):
+		kill()
+		return nil, fmt.Errorf("timeout while waiting for plugin to start")
+	}
+	if err := r.checkHandshake(line); err != nil {
+		kill()
+		return nil, err
+	}
+	if err := r.listen(); err != nil {
+		kill()
+		return nil, err
+	}
+	return &plugin.ReattachConfig{
+		Protocol: plugin.ProtocolGRPC,
+		Addr:     r.listener.Addr(),
+		Pid:      cmd.Process.Pid,
+	}, nil
+}
+
+// checkHandshake verifies the line written by the plugin once ready:
+// CORE-PROTOCOL-VERSION|APP-PROTOCOL-VERSION|NETWORK-TYPE|NETWORK-ADDR|PROTOCOL
+func (r *namedPipeRelay) checkHandshake(line string) error {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) < 5 {
+		return fmt.Errorf("unrecognized plugin handshake: %s", line)
+	}
+	if parts[0] != strconv.Itoa(plugin.CoreProtocolVersion) {
+		return fmt.Errorf("incompatible plugin core protocol version: %s", parts[0])
+	}
+	if parts[1] != strconv.Itoa(int(iplugin.DefaultHandshakeConfig.ProtocolVersion)) {
+		return fmt.Errorf("incompatible plugin protocol version: %s", parts[1])
+	}
+	if parts[2] != string(TransportNamedPipe) || parts[3] != r.pipe {
+		return fmt.Errorf("plugin doesn't listen on named pipe %s but %s %s", r.pipe, parts[2], parts[3])
+	}
+	if parts[4] != string(plugin.ProtocolGRPC) {
+		return fmt.Errorf("unsupported plugin protocol: %s", parts[4])
+	}
+	return nil
+}
+
+func (r *namedPipeRelay) listen() error {
+	dir, err := ioutil.TempDir("", "plugin")
+	if err != nil {
+		return err
+	}
+	l, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	r.dir, r.listener = dir, l
+	r.conns = make(map[net.Conn]struct{})
+	r.wg.Add(1)
+	go r.serve()
+	return nil
+}
+
+func (r *namedPipeRelay) serve() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		r.wg.Add(1)
+		go r.relay(conn)
+	}
+}
+
+func (r *namedPipeRelay) relay(conn net.Conn) {
+	defer r.wg.Done()
+	pipeConn, err := r.dial(r.pipe, namedPipeDialTimeout)
+	if err != nil {
+		r.logger.Error("Unable to connect to plugin named pipe", "pipe", r.pipe, "err", err)
+		_ = conn.Close()
+		return
+	}
+	if !r.track(conn, pipeConn) {
+		return
+	}
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(pipeConn, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, pipeConn)
+		done <- struct{}{}
+	}()
+	<-done
+	r.untrack(conn, pipeConn)
+}
+
+// track returns false if the relay has been closed in the meantime
+func (r *namedPipeRelay) track(conns ...net.Conn) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.conns == nil {
+		for _, c := range conns {
+			_ = c.Close()
+		}
+		return false
+	}
+	for _, c := range conns {
+		r.conns[c] = struct{}{}
+	}
+	return true
+}
+
+func (r *namedPipeRelay) untrack(conns ...net.Conn) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, c := range conns {
+		_ = c.Close()
+		delete(r.conns, c)
+	}
+}
+
+func (r *namedPipeRelay) Close() error {
+	if r.listener == nil {
+		return nil
+	}
+	err := r.listener.Close()
+	r.mux.Lock()
+	for c := range r.conns {
+		_ = c.Close()
+	}
+	r.conns = nil
+	r.mux.Unlock()
+	r.wg.Wait()
+	_ = os.RemoveAll(r.dir)
+	r.listener = nil
+	return err
+}
+
+type logWriter struct {
+	logger log.Logger
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.logger.Debug(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
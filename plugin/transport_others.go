@@ -0,0 +1,13 @@
+// +build !windows
+
+package plugin
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+func dialNamedPipe(pipe string, timeout time.Duration) (net.Conn, error) {
+	return nil, fmt.Errorf("named pipes are not supported on this platform")
+}
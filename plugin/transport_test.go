@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	testifyassert "github.com/stretchr/testify/assert"
+	testifyrequire "github.com/stretchr/testify/require"
+)
+
+func TestTransportConfig_Validate(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	assert.NoError((&TransportConfig{Type: TransportAuto}).Validate())
+	assert.NoError((&TransportConfig{Type: TransportTCP, MinPort: 10000, MaxPort: 10010}).Validate())
+	assert.EqualError((&TransportConfig{Type: TransportTCP, MinPort: 10010, MaxPort: 10000}).Validate(), "invalid port range [10010, 10000]")
+	assert.EqualError((&TransportConfig{Type: "foo"}).Validate(), "unknown transport foo")
+	if runtime.GOOS == "windows" {
+		assert.Error((&TransportConfig{Type: TransportUnix}).Validate())
+	} else {
+		assert.Error((&TransportConfig{Type: TransportNamedPipe}).Validate())
+	}
+}
+
+func TestSettings_SetDefaults_whenTransportNotSet(t *testing.T) {
+	s := &Settings{}
+
+	s.SetDefaults()
+
+	testifyassert.Equal(t, TransportAuto, s.Transport.Type)
+}
+
+func TestNamedPipeRelay_checkHandshake(t *testing.T) {
+	assert := testifyassert.New(t)
+	testObject := &namedPipeRelay{pipe: `\\.\pipe\arbitrary`}
+
+	assert.NoError(testObject.checkHandshake(`1|1|npipe|\\.\pipe\arbitrary|grpc` + "\n"))
+	assert.Error(testObject.checkHandshake(`1|1|tcp|127.0.0.1:1234|grpc`))
+	assert.Error(testObject.checkHandshake(`1|1|npipe|\\.\pipe\arbitrary|netrpc`))
+	assert.Error(testObject.checkHandshake(`1|2|npipe|\\.\pipe\arbitrary|grpc`))
+	assert.Error(testObject.checkHandshake(`arbitrary output`))
+}
+
+func TestNamedPipeRelay_relay(t *testing.T) {
+	require := testifyrequire.New(t)
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	// a unix socket stands for the named pipe the plugin listens on
+	pipe := filepath.Join(dir, "pipe.sock")
+	pluginListener, err := net.Listen("unix", pipe)
+	require.NoError(err)
+	defer pluginListener.Close()
+	go func() {
+		conn, err := pluginListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err == nil {
+			_, _ = conn.Write(append([]byte("re:"), buf...))
+		}
+	}()
+	testObject := &namedPipeRelay{
+		dial: func(pipe string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", pipe, timeout)
+		},
+		pipe:   pipe,
+		logger: log.New(),
+	}
+	require.NoError(testObject.listen())
+	defer testObject.Close()
+
+	conn, err := net.Dial(testObject.listener.Addr().Network(), testObject.listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(err)
+	buf := make([]byte, 7)
+	_, err = io.ReadFull(conn, buf)
+
+	require.NoError(err)
+	require.Equal("re:ping", string(buf))
+}
@@ -0,0 +1,14 @@
+// +build windows
+
+package plugin
+
+import (
+	"net"
+	"time"
+
+	"gopkg.in/natefinch/npipe.v2"
+)
+
+func dialNamedPipe(pipe string, timeout time.Duration) (net.Conn, error) {
+	return npipe.DialTimeout(pipe, timeout)
+}
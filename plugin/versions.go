@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PluginVersionsInfo reports versions of a configured plugin available for upgrade
+type PluginVersionsInfo struct {
+	Name             string    `json:"name"`
+	InstalledVersion Version   `json:"installedVersion"`
+	LatestVersion    Version   `json:"latestVersion,omitempty"`
+	Available        []Version `json:"available"` // from Plugin Central
+	Local            []Version `json:"local"`     // distributions found in the plugin base directory
+	Outdated         bool      `json:"outdated"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// AvailableVersions queries Plugin Central and the local plugin directory for
+// versions of each configured plugin. Failing to reach Plugin Central is reported
+// per plugin so the local mirror is still usable
+func (s *PluginManager) AvailableVersions() map[PluginInterfaceName]*PluginVersionsInfo {
+	result := make(map[PluginInterfaceName]*PluginVersionsInfo)
+	if s.settings == nil {
+		return result
+	}
+	for interfaceName, definition := range s.settings.Providers {
+		info := &PluginVersionsInfo{
+			Name:             definition.Name,
+			InstalledVersion: definition.Version,
+			Available:        []Version{},
+			Local:            s.localVersions(definition.Name),
+		}
+		if s.centralClient != nil {
+			if versions, err := s.centralClient.PluginVersions(definition.Name); err != nil {
+				info.Error = err.Error()
+			} else {
+				info.Available = sortVersions(versions)
+			}
+		}
+		candidates := append(append([]Version{}, info.Available...), info.Local...)
+		for _, v := range candidates {
+			if len(info.LatestVersion) == 0 || CompareVersions(v, info.LatestVersion) > 0 {
+				info.LatestVersion = v
+			}
+		}
+		info.Outdated = len(info.LatestVersion) > 0 && CompareVersions(info.LatestVersion, definition.Version) > 0
+		result[interfaceName] = info
+	}
+	return result
+}
+
+// InstalledVersions returns the version of each configured plugin by plugin name
+func (s *PluginManager) InstalledVersions() map[string]Version {
+	result := make(map[string]Version)
+	if s.settings == nil {
+		return result
+	}
+	for _, definition := range s.settings.Providers {
+		result[definition.Name] = definition.Version
+	}
+	return result
+}
+
+// localVersions returns versions of plugin distributions for the current platform
+// stored in the plugin base directory, i.e.: <Name>-<Version>-<OS>-<Arch>.zip
+func (s *PluginManager) localVersions(name string) []Version {
+	versions := make([]Version, 0)
+	if len(s.pluginBaseDir) == 0 {
+		return versions
+	}
+	files, err := ioutil.ReadDir(s.pluginBaseDir)
+	if err != nil {
+		return versions
+	}
+	prefix := name + "-"
+	suffix := fmt.Sprintf("-%s-%s.zip", runtime.GOOS, runtime.GOARCH)
+	for _, f := range files {
+		fileName := f.Name()
+		if f.IsDir() || !strings.HasPrefix(fileName, prefix) || !strings.HasSuffix(fileName, suffix) {
+			continue
+		}
+		v := strings.TrimSuffix(strings.TrimPrefix(fileName, prefix), suffix)
+		if _, ok := parseVersion(Version(v)); ok {
+			versions = append(versions, Version(v))
+		}
+	}
+	return sortVersions(versions)
+}
+
+func sortVersions(versions []Version) []Version {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+type semver struct {
+	core       [3]uint64
+	preRelease []string
+}
+
+// parseVersion parses MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD], build metadata is ignored
+func parseVersion(v Version) (*semver, bool) {
+	s := strings.TrimPrefix(string(v), "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+	parsed := &semver{}
+	if i := strings.Index(s, "-"); i >= 0 {
+		parsed.preRelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return nil, false
+	}
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		parsed.core[i] = n
+	}
+	return parsed, true
+}
+
+// CompareVersions returns -1, 0 or 1 following semver precedence.
+// Unparsable versions are lower than any valid version and compared lexically
+func CompareVersions(a, b Version) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(string(a), string(b))
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range va.core {
+		if va.core[i] != vb.core[i] {
+			if va.core[i] < vb.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	// a version without pre-release has higher precedence
+	switch {
+	case len(va.preRelease) == 0 && len(vb.preRelease) == 0:
+		return 0
+	case len(va.preRelease) == 0:
+		return 1
+	case len(vb.preRelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(va.preRelease) && i < len(vb.preRelease); i++ {
+		if c := comparePreRelease(va.preRelease[i], vb.preRelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(va.preRelease) < len(vb.preRelease):
+		return -1
+	case len(va.preRelease) > len(vb.preRelease):
+		return 1
+	}
+	return 0
+}
+
+func comparePreRelease(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1 // numeric identifiers have lower precedence
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	testCases := []struct {
+		a, b     Version
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "1.0.1", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0", "1.9.9", 1},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"1.0.0-beta", "1.0.0-alpha.1", 1},
+		{"1.0.0+build.1", "1.0.0", 0},
+		{"invalid", "0.0.1", -1},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, CompareVersions(tc.a, tc.b), "%s vs %s", tc.a, tc.b)
+		assert.Equal(t, -tc.expected, CompareVersions(tc.b, tc.a), "%s vs %s", tc.b, tc.a)
+	}
+}
+
+func TestPluginManager_AvailableVersions(t *testing.T) {
+	arbitraryMetadata := []byte(`<metadata><versioning><versions><version>1.0.0</version><version>1.2.0</version><version>1.1.0</version></versions></versioning></metadata>`)
+	arbitraryServer := newTestServer("/maven/bin/arbitrary-plugin/maven-metadata.xml", arbitraryMetadata)
+	defer arbitraryServer.Close()
+	baseDir, err := ioutil.TempDir("", "q-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(baseDir)
+	}()
+	for _, f := range []string{
+		fmt.Sprintf("arbitrary-plugin-1.3.0-%s-%s.zip", runtime.GOOS, runtime.GOARCH),
+		fmt.Sprintf("arbitrary-plugin-2.0.0-other-%s.zip", runtime.GOARCH),
+		fmt.Sprintf("other-plugin-9.0.0-%s-%s.zip", runtime.GOOS, runtime.GOARCH),
+	} {
+		if err := ioutil.WriteFile(path.Join(baseDir, f), []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testObject := &PluginManager{
+		pluginBaseDir: baseDir,
+		centralClient: NewPluginCentralClient(&PluginCentralConfiguration{
+			BaseURL:                    arbitraryServer.URL,
+			PluginMetadataPathTemplate: "maven/bin/{{.Name}}/maven-metadata.xml",
+		}),
+		settings: &Settings{
+			Providers: map[PluginInterfaceName]PluginDefinition{
+				BlockValidationPluginInterfaceName: {Name: "arbitrary-plugin", Version: "1.1.0"},
+			},
+		},
+	}
+
+	actual := testObject.AvailableVersions()
+
+	assert.Contains(t, actual, BlockValidationPluginInterfaceName)
+	info := actual[BlockValidationPluginInterfaceName]
+	assert.Empty(t, info.Error)
+	assert.Equal(t, Version("1.1.0"), info.InstalledVersion)
+	assert.Equal(t, []Version{"1.0.0", "1.1.0", "1.2.0"}, info.Available)
+	assert.Equal(t, []Version{"1.3.0"}, info.Local)
+	assert.Equal(t, Version("1.3.0"), info.LatestVersion)
+	assert.True(t, info.Outdated)
+}
+
+func TestPluginManager_AvailableVersions_whenCentralUnreachable(t *testing.T) {
+	arbitraryServer := newTestServer("/nothing", []byte{})
+	defer arbitraryServer.Close()
+	testObject := &PluginManager{
+		centralClient: NewPluginCentralClient(&PluginCentralConfiguration{
+			BaseURL:                    arbitraryServer.URL,
+			PluginMetadataPathTemplate: "maven/bin/{{.Name}}/maven-metadata.xml",
+		}),
+		settings: &Settings{
+			Providers: map[PluginInterfaceName]PluginDefinition{
+				BlockValidationPluginInterfaceName: {Name: "arbitrary-plugin", Version: "1.1.0"},
+			},
+		},
+	}
+
+	info := testObject.AvailableVersions()[BlockValidationPluginInterfaceName]
+
+	assert.NotEmpty(t, info.Error)
+	assert.False(t, info.Outdated)
+}
@@ -0,0 +1,88 @@
+package private
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+var (
+	keyAliasesMu sync.RWMutex
+	keyAliases   KeyAliases
+)
+
+// KeyAliases maps the human-readable names of the participants to the public keys of
+// their private transaction managers, so that the applications use the names in
+// privateFrom and privateFor rather than base64 keys
+type KeyAliases map[string]string
+
+// Validate checks the names and the keys are set, and that no name is a key of the
+// registry
+func (a KeyAliases) Validate() error {
+	keys := make(map[string]bool, len(a))
+	for name, key := range a {
+		if name == "" || key == "" {
+			return errors.New("empty participant name or public key")
+		}
+		keys[key] = true
+	}
+	for name := range a {
+		if keys[name] {
+			return fmt.Errorf("participant name %q is a public key of the registry", name)
+		}
+	}
+	return nil
+}
+
+// LoadKeyAliases reads the aliases from a JSON file, an object of the public keys by
+// participant name
+func LoadKeyAliases(path string) (KeyAliases, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var aliases KeyAliases
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid key aliases file %s: %v", path, err)
+	}
+	if err := aliases.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid key aliases file %s: %v", path, err)
+	}
+	return aliases, nil
+}
+
+// SetKeyAliases sets the aliases resolved by ResolveKey
+func SetKeyAliases(aliases KeyAliases) error {
+	if err := aliases.Validate(); err != nil {
+		return err
+	}
+	keyAliasesMu.Lock()
+	defer keyAliasesMu.Unlock()
+	keyAliases = aliases
+	return nil
+}
+
+// ResolveKey returns the public key of a participant name, or the key itself if it is
+// not a name of the registry
+func ResolveKey(nameOrKey string) string {
+	keyAliasesMu.RLock()
+	defer keyAliasesMu.RUnlock()
+	if key, ok := keyAliases[nameOrKey]; ok {
+		return key
+	}
+	return nameOrKey
+}
+
+// ResolveKeys returns the public keys of participant names or keys, see ResolveKey
+func ResolveKeys(namesOrKeys []string) []string {
+	if namesOrKeys == nil {
+		return nil
+	}
+	keys := make([]string, len(namesOrKeys))
+	for i, nameOrKey := range namesOrKeys {
+		keys[i] = ResolveKey(nameOrKey)
+	}
+	return keys
+}
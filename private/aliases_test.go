@@ -0,0 +1,40 @@
+package private
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveKeys(t *testing.T) {
+	require.NoError(t, SetKeyAliases(KeyAliases{"bank-a": "BULeR8JyUWhiuuCMU/HLA0Q5pzkYT+cHII3ZKBey3Bo="}))
+	defer SetKeyAliases(nil)
+
+	assert.Equal(t, "BULeR8JyUWhiuuCMU/HLA0Q5pzkYT+cHII3ZKBey3Bo=", ResolveKey("bank-a"))
+	assert.Equal(t, []string{"BULeR8JyUWhiuuCMU/HLA0Q5pzkYT+cHII3ZKBey3Bo=", "QfeDAys9MPDs2XHExtc84jKGHxZg/aj52DTh0vtA3Xc="},
+		ResolveKeys([]string{"bank-a", "QfeDAys9MPDs2XHExtc84jKGHxZg/aj52DTh0vtA3Xc="}))
+	assert.Nil(t, ResolveKeys(nil))
+}
+
+func TestLoadKeyAliases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aliases")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "aliases.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"bank-a": "A", "bank-b": "B"}`), 0600))
+
+	aliases, err := LoadKeyAliases(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, KeyAliases{"bank-a": "A", "bank-b": "B"}, aliases)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"bank-a": "A", "A": "B"}`), 0600))
+	_, err = LoadKeyAliases(path)
+	assert.Error(t, err, "a name must not be a key")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"bank-a": ""}`), 0600))
+	_, err = LoadKeyAliases(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Package memory is an in-memory private transaction manager for local development.
+//
+// The payloads are neither encrypted nor distributed: the nodes sharing the manager,
+// e.g.: the in-process nodes of geth quorum-dev, see every private transaction as if
+// they were party to it. It must never be used in a real network.
+package memory
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"golang.org/x/crypto/sha3"
+)
+
+type payload struct {
+	data  []byte
+	from  string
+	to    []string
+	extra engine.ExtraMetadata
+}
+
+// PrivateTransactionManager keeps the private payloads in memory
+type PrivateTransactionManager struct {
+	mu       sync.RWMutex
+	nonce    uint64
+	payloads map[common.EncryptedPayloadHash]*payload
+}
+
+func New() *PrivateTransactionManager {
+	return &PrivateTransactionManager{
+		payloads: make(map[common.EncryptedPayloadHash]*payload),
+	}
+}
+
+func (ptm *PrivateTransactionManager) Name() string {
+	return "InMemory"
+}
+
+func (ptm *PrivateTransactionManager) HasFeature(f engine.PrivateTransactionManagerFeature) bool {
+	return false
+}
+
+// store keeps a payload and returns its hash, unique even for the same data
+func (ptm *PrivateTransactionManager) store(p *payload) common.EncryptedPayloadHash {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+	ptm.nonce++
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], ptm.nonce)
+	hash := common.EncryptedPayloadHash(sha3.Sum512(append(nonce[:], p.data...)))
+	ptm.payloads[hash] = p
+	return hash
+}
+
+func (ptm *PrivateTransactionManager) get(hash common.EncryptedPayloadHash) *payload {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+	return ptm.payloads[hash]
+}
+
+func (ptm *PrivateTransactionManager) Send(data []byte, from string, to []string, extra *engine.ExtraMetadata) (string, []string, common.EncryptedPayloadHash, error) {
+	if extra.PrivacyFlag.IsNotStandardPrivate() {
+		return "", nil, common.EncryptedPayloadHash{}, engine.ErrPrivateTxManagerDoesNotSupportPrivacyEnhancements
+	}
+	p := &payload{data: data, from: from, to: to, extra: *extra}
+	p.extra.Sender = from
+	return from, nil, ptm.store(p), nil
+}
+
+func (ptm *PrivateTransactionManager) StoreRaw(data []byte, from string) (common.EncryptedPayloadHash, error) {
+	return ptm.store(&payload{data: data, from: from}), nil
+}
+
+func (ptm *PrivateTransactionManager) SendSignedTx(data common.EncryptedPayloadHash, to []string, extra *engine.ExtraMetadata) (string, []string, []byte, error) {
+	if extra.PrivacyFlag.IsNotStandardPrivate() {
+		return "", nil, nil, engine.ErrPrivateTxManagerDoesNotSupportPrivacyEnhancements
+	}
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+	p, ok := ptm.payloads[data]
+	if !ok {
+		return "", nil, nil, engine.ErrPrivateTxManagerNotSupported
+	}
+	p.to, p.extra = to, *extra
+	p.extra.Sender = p.from
+	return p.from, nil, data.Bytes(), nil
+}
+
+func (ptm *PrivateTransactionManager) Receive(hash common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+	p := ptm.get(hash)
+	if p == nil {
+		return "", nil, nil, nil, nil
+	}
+	extra := p.extra
+	return p.from, nil, p.data, &extra, nil
+}
+
+func (ptm *PrivateTransactionManager) ReceiveRaw(hash common.EncryptedPayloadHash) ([]byte, string, *engine.ExtraMetadata, error) {
+	p := ptm.get(hash)
+	if p == nil {
+		return nil, "", nil, nil
+	}
+	extra := p.extra
+	return p.data, p.from, &extra, nil
+}
+
+func (ptm *PrivateTransactionManager) IsSender(txHash common.EncryptedPayloadHash) (bool, error) {
+	return ptm.get(txHash) != nil, nil
+}
+
+func (ptm *PrivateTransactionManager) GetParticipants(txHash common.EncryptedPayloadHash) ([]string, error) {
+	p := ptm.get(txHash)
+	if p == nil {
+		return nil, nil
+	}
+	return append([]string{p.from}, p.to...), nil
+}
+
+func (ptm *PrivateTransactionManager) EncryptPayload(data []byte, from string, to []string, extra *engine.ExtraMetadata) ([]byte, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *PrivateTransactionManager) DecryptPayload(payload common.DecryptRequest) ([]byte, *engine.ExtraMetadata, error) {
+	return nil, nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *PrivateTransactionManager) Groups() ([]engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateTransactionManager_Send(t *testing.T) {
+	ptm := New()
+
+	sender, _, hash, err := ptm.Send([]byte("data"), "A", []string{"B"}, &engine.ExtraMetadata{})
+	require.NoError(t, err)
+	assert.Equal(t, "A", sender)
+
+	from, _, data, extra, err := ptm.Receive(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "A", from)
+	assert.Equal(t, []byte("data"), data)
+	assert.Equal(t, "A", extra.Sender)
+
+	participants, err := ptm.GetParticipants(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, participants)
+
+	_, _, other, err := ptm.Send([]byte("data"), "A", []string{"B"}, &engine.ExtraMetadata{})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "same data sent twice")
+
+	_, _, _, err = ptm.Send([]byte("data"), "A", nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagPartyProtection})
+	assert.Equal(t, engine.ErrPrivateTxManagerDoesNotSupportPrivacyEnhancements, err)
+}
+
+func TestPrivateTransactionManager_SendSignedTx(t *testing.T) {
+	ptm := New()
+
+	hash, err := ptm.StoreRaw([]byte("data"), "A")
+	require.NoError(t, err)
+	data, from, _, err := ptm.ReceiveRaw(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+	assert.Equal(t, "A", from)
+
+	_, _, returned, err := ptm.SendSignedTx(hash, []string{"B"}, &engine.ExtraMetadata{})
+	require.NoError(t, err)
+	assert.Equal(t, hash.Bytes(), returned)
+
+	_, _, data, _, err = ptm.Receive(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	_, _, data, extra, err := ptm.Receive(common.EncryptedPayloadHash{1})
+	assert.NoError(t, err, "unknown payload")
+	assert.Nil(t, data)
+	assert.Nil(t, extra)
+}
@@ -92,3 +92,13 @@ type decryptPayloadRequest struct {
 	RecipientNonce  []byte   `json:"recipientNonce"`
 	RecipientKeys   []string `json:"recipientKeys"`
 }
+
+// request object for /resend API
+type resendRequest struct {
+	// INDIVIDUAL to resend one payload
+	Type string `json:"type"`
+	// Base64-encoded public key of the recipient
+	PublicKey string `json:"publicKey"`
+	// Base64-encoded encrypted payload hash
+	Key string `json:"key,omitempty"`
+}
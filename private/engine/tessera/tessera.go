@@ -2,6 +2,7 @@ package tessera
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -16,13 +17,17 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/private/cache"
 	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/tracing"
 	gocache "github.com/patrickmn/go-cache"
 )
 
 type tesseraPrivateTxManager struct {
-	features *engine.FeatureSet
-	client   *engine.Client
-	cache    *gocache.Cache
+	features   *engine.FeatureSet
+	client     *engine.Client
+	cache      *gocache.Cache
+	apiVersion string
+
+	ctx context.Context // the spans of the requests are children of its span, nil if unbound
 }
 
 func Is(ptm interface{}) bool {
@@ -36,13 +41,39 @@ func New(client *engine.Client, version []byte) *tesseraPrivateTxManager {
 		log.Error(fmt.Sprintf("Error parsing version components from the tessera version: %s. Unable to extract transaction manager features.", version))
 	}
 	return &tesseraPrivateTxManager{
-		features: engine.NewFeatureSet(tesseraVersionFeatures(ptmVersion)...),
-		client:   client,
-		cache:    gocache.New(cache.DefaultExpiration, cache.CleanupInterval),
+		features:   engine.NewFeatureSet(tesseraVersionFeatures(ptmVersion)...),
+		client:     client,
+		cache:      gocache.New(cache.DefaultExpiration, cache.CleanupInterval),
+		apiVersion: string(version),
+	}
+}
+
+// WithContext returns a copy of ptm, a tessera private transaction manager, whose
+// requests are traced as children of the span of ctx
+func WithContext(ptm interface{}, ctx context.Context) interface{} {
+	t := *ptm.(*tesseraPrivateTxManager)
+	t.ctx = ctx
+	return &t
+}
+
+// traceRequest starts the span of a request to tessera, the returned function ends it
+func (t *tesseraPrivateTxManager) traceRequest(method, path string) func(statusCode int, err error) {
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	operation := strings.SplitN(strings.SplitN(strings.TrimPrefix(path, "/"), "?", 2)[0], "/", 2)[0]
+	_, span := tracing.Start(ctx, "ptm.request")
+	span.SetAttribute("http.method", method).SetAttribute("ptm.operation", operation)
+	return func(statusCode int, err error) {
+		span.SetAttribute("http.status_code", statusCode).SetError(err).End()
 	}
 }
 
-func (t *tesseraPrivateTxManager) submitJSON(method, path string, request interface{}, response interface{}) (int, error) {
+func (t *tesseraPrivateTxManager) submitJSON(method, path string, request interface{}, response interface{}) (statusCode int, err error) {
+	endSpan := t.traceRequest(method, path)
+	defer func() { endSpan(statusCode, err) }()
+
 	apiVersion := ""
 	if t.features.HasFeature(engine.MultiTenancy) {
 		apiVersion = "vnd.tessera-2.1+"
@@ -57,7 +88,7 @@ func (t *tesseraPrivateTxManager) submitJSON(method, path string, request interf
 	}
 	res, err := t.client.HttpClient.Do(req)
 	if err != nil {
-		return -1, fmt.Errorf("unable to submit request (method:%s,path:%s). Cause: %v", method, path, err)
+		return -1, fmt.Errorf("unable to submit request (method:%s,path:%s). Cause: %w", method, path, err)
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
@@ -70,7 +101,10 @@ func (t *tesseraPrivateTxManager) submitJSON(method, path string, request interf
 	return res.StatusCode, nil
 }
 
-func (t *tesseraPrivateTxManager) submitJSONOld(method, path string, request interface{}, response interface{}) (int, error) {
+func (t *tesseraPrivateTxManager) submitJSONOld(method, path string, request interface{}, response interface{}) (statusCode int, err error) {
+	endSpan := t.traceRequest(method, path)
+	defer func() { endSpan(statusCode, err) }()
+
 	apiVersion := ""
 	req, err := newOptionalJSONRequest(method, t.client.FullPath(path), request, apiVersion)
 	if err != nil {
@@ -78,7 +112,7 @@ func (t *tesseraPrivateTxManager) submitJSONOld(method, path string, request int
 	}
 	res, err := t.client.HttpClient.Do(req)
 	if err != nil {
-		return -1, fmt.Errorf("unable to submit request (method:%s,path:%s). Cause: %v", method, path, err)
+		return -1, fmt.Errorf("unable to submit request (method:%s,path:%s). Cause: %w", method, path, err)
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
@@ -441,6 +475,51 @@ func (t *tesseraPrivateTxManager) HasFeature(f engine.PrivateTransactionManagerF
 	return t.features.HasFeature(f)
 }
 
+// Upcheck returns an error if Tessera is not up
+func (t *tesseraPrivateTxManager) Upcheck() error {
+	res, err := t.client.Get("/upcheck")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return engine.ErrPrivateTxManagerNotReady
+	}
+	return nil
+}
+
+// APIVersion returns the latest API version supported by Tessera
+func (t *tesseraPrivateTxManager) APIVersion() string {
+	return t.apiVersion
+}
+
+// Resend asks Tessera to resend its copy of a payload to a recipient of the payload
+func (t *tesseraPrivateTxManager) Resend(hash common.EncryptedPayloadHash, to string) (err error) {
+	statusCode := -1
+	endSpan := t.traceRequest("POST", "/resend")
+	defer func() { endSpan(statusCode, err) }()
+
+	req, err := newOptionalJSONRequest("POST", t.client.FullPath("/resend"), &resendRequest{
+		Type:      "INDIVIDUAL",
+		PublicKey: to,
+		Key:       hash.ToBase64(),
+	}, "")
+	if err != nil {
+		return err
+	}
+	res, err := t.client.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to submit request (method:POST,path:/resend). Cause: %w", err)
+	}
+	defer res.Body.Close()
+	statusCode = res.StatusCode
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("%d status: %s", res.StatusCode, string(body))
+	}
+	return nil
+}
+
 // don't serialize body if nil
 func newOptionalJSONRequest(method string, path string, body interface{}, apiVersion string) (*http.Request, error) {
 	buf := new(bytes.Buffer)
@@ -508,3 +508,23 @@ func TestReceive_whenCachingRawPayload(t *testing.T) {
 	assert.Equal(arbitraryExtra.ACMerkleRoot, actualExtra.ACMerkleRoot, "cached merkle root")
 	assert.Equal(arbitraryExtra.PrivacyFlag, actualExtra.PrivacyFlag, "cached privacy flag")
 }
+
+func TestUpcheck(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/upcheck", func(response http.ResponseWriter, request *http.Request) {
+		response.Write([]byte("I'm up!"))
+	})
+	testServerUp := httptest.NewServer(mux)
+	defer testServerUp.Close()
+
+	testObjectUp := New(&engine.Client{
+		HttpClient: &http.Client{},
+		BaseURL:    testServerUp.URL,
+	}, []byte("2.0.0"))
+
+	assert.NoError(testObjectUp.Upcheck())
+	assert.Equal("2.0.0", testObjectUp.APIVersion())
+	assert.Equal(engine.ErrPrivateTxManagerNotReady, testObject.Upcheck(), "the test server doesn't serve /upcheck")
+}
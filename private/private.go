@@ -1,6 +1,7 @@
 package private
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/private/engine/constellation"
+	"github.com/ethereum/go-ethereum/private/engine/memory"
 	"github.com/ethereum/go-ethereum/private/engine/notinuse"
 	"github.com/ethereum/go-ethereum/private/engine/tessera"
 )
@@ -45,6 +47,28 @@ type PrivateTransactionManager interface {
 	Groups() ([]engine.PrivacyGroup, error)
 }
 
+// StatusReporter is implemented by private transaction managers able to report
+// whether they are up and the version of their API
+type StatusReporter interface {
+	Upcheck() error
+	APIVersion() string
+}
+
+// PayloadResender is implemented by private transaction managers able to resend their
+// copy of a payload to one of its recipients, e.g. a party recovering from data loss
+type PayloadResender interface {
+	Resend(hash common.EncryptedPayloadHash, to string) error
+}
+
+// WithContext returns P with its requests traced as part of the span of ctx, when the
+// private transaction manager supports it
+func WithContext(ctx context.Context) PrivateTransactionManager {
+	if tessera.Is(P) {
+		return tessera.WithContext(P, ctx).(PrivateTransactionManager)
+	}
+	return P
+}
+
 // This loads any config specified via the legacy environment variable
 func GetLegacyEnvironmentConfig() (http2.Config, error) {
 	return FromEnvironmentOrNil("PRIVATE_CONFIG")
@@ -66,6 +90,20 @@ func InitialiseConnection(cfg http2.Config) error {
 	return err
 }
 
+// InitialiseInMemory sets an in-memory private transaction manager, shared by the
+// in-process nodes of a local development network
+func InitialiseInMemory() {
+	P = memory.New()
+	isPrivacyEnabled = true
+}
+
+// InitialiseWith sets a private transaction manager which is not reached through a
+// PTM connection, e.g.: the qlight client fetching the payloads from its server
+func InitialiseWith(ptm PrivateTransactionManager) {
+	P = ptm
+	isPrivacyEnabled = true
+}
+
 func IsQuorumPrivacyEnabled() bool {
 	return isPrivacyEnabled
 }
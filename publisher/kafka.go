@@ -0,0 +1,117 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	kafkaRecordsContentType = "application/vnd.kafka.binary.v2+json"
+	kafkaResponseType       = "application/vnd.kafka.v2+json"
+)
+
+// kafkaRESTBroker publishes to Kafka through the Confluent REST proxy (API v2). The
+// messages are acknowledged once the proxy returns their offsets, the key of a message
+// being the Kafka record key so that the messages of a topic go to the same partition,
+// in the order they were published.
+type kafkaRESTBroker struct {
+	url    string // base URL of the proxy
+	client *http.Client
+}
+
+func newKafkaRESTBroker(u *url.URL) (*kafkaRESTBroker, error) {
+	if u.Host == "" {
+		return nil, errors.New("the Kafka REST proxy host is missing")
+	}
+	proxy := *u
+	proxy.Scheme = strings.TrimPrefix(u.Scheme, "kafka+")
+	return &kafkaRESTBroker{
+		url:    strings.TrimSuffix(proxy.String(), "/"),
+		client: new(http.Client),
+	}, nil
+}
+
+type kafkaRecord struct {
+	Key   []byte `json:"key"` // base64 encoded by the binary embedded format
+	Value []byte `json:"value"`
+}
+
+type kafkaOffsets struct {
+	Offsets []struct {
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (b *kafkaRESTBroker) Publish(ctx context.Context, topic string, msg *Message) error {
+	body, err := json.Marshal(map[string][]kafkaRecord{"records": {{Key: []byte(msg.Key), Value: msg.Value}}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, b.url+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaRecordsContentType)
+	req.Header.Set("Accept", kafkaResponseType)
+	resp, err := b.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return errMessageTooLarge
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Kafka REST proxy returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	var offsets kafkaOffsets
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return fmt.Errorf("invalid Kafka REST proxy response: %v", err)
+	}
+	if len(offsets.Offsets) != 1 {
+		return fmt.Errorf("Kafka REST proxy returned %d offsets for 1 record", len(offsets.Offsets))
+	}
+	if o := offsets.Offsets[0]; o.ErrorCode != nil || o.Error != "" {
+		if strings.Contains(o.Error, "RecordTooLarge") {
+			return errMessageTooLarge
+		}
+		return fmt.Errorf("Kafka rejected the message: %s", o.Error)
+	}
+	return nil
+}
+
+func (b *kafkaRESTBroker) Close() error {
+	b.client.CloseIdleConnections()
+	return nil
+}
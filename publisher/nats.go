@@ -0,0 +1,318 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const natsDefaultPort = "4222"
+
+// natsBroker publishes to a NATS server with its text protocol. With JetStream, the
+// messages are acknowledged once persisted by the stream of the subject and carry the
+// Nats-Msg-Id header, so that the stream drops the messages published again. Otherwise,
+// the messages are acknowledged once received by the server. The connection is upgraded
+// to TLS with the nats+tls scheme, or if the server requires it.
+type natsBroker struct {
+	addr       string
+	user       *url.Userinfo
+	jetstream  bool
+	inbox      string // subject prefix of the JetStream acknowledgments
+	tlsConfig  *tls.Config
+	requireTLS bool
+
+	conn       net.Conn // nil until connected
+	r          *bufio.Reader
+	reqID      uint64
+	maxPayload int // largest message accepted by the server, 0 if unknown
+}
+
+func newNATSBroker(u *url.URL) (*natsBroker, error) {
+	if u.Hostname() == "" {
+		return nil, errors.New("the NATS server host is missing")
+	}
+	port := u.Port()
+	if port == "" {
+		port = natsDefaultPort
+	}
+	jetstream, _ := strconv.ParseBool(u.Query().Get("jetstream"))
+	tlsConfig := &tls.Config{ServerName: u.Hostname()}
+	if ca := u.Query().Get("tlsca"); ca != "" {
+		data, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return nil, fmt.Errorf("NATS TLS CA: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("NATS TLS CA: no PEM certificate in %s", ca)
+		}
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	return &natsBroker{
+		addr:       net.JoinHostPort(u.Hostname(), port),
+		user:       u.User,
+		jetstream:  jetstream,
+		inbox:      "_INBOX." + hex.EncodeToString(id[:]),
+		tlsConfig:  tlsConfig,
+		requireTLS: u.Scheme == "nats+tls",
+	}, nil
+}
+
+// natsInfo is the INFO message of the protocol
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	MaxPayload  int  `json:"max_payload"`
+}
+
+// natsConnect is the CONNECT message of the protocol
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+	Headers  bool   `json:"headers"`
+	NoEcho   bool   `json:"no_echo"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+}
+
+func (b *natsBroker) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", b.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	b.conn, b.r = conn, bufio.NewReader(conn)
+	// the server greets the client with its INFO
+	line, err := b.readLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting %q", line)
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return fmt.Errorf("invalid NATS greeting: %v", err)
+	}
+	b.maxPayload = info.MaxPayload
+	if b.requireTLS || info.TLSRequired {
+		tlsConn := tls.Client(conn, b.tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("NATS TLS handshake: %v", err)
+		}
+		b.conn, b.r = tlsConn, bufio.NewReader(tlsConn)
+	}
+	connect := &natsConnect{Name: "geth", Lang: "go", Protocol: 1, Headers: b.jetstream, NoEcho: true}
+	if b.user != nil {
+		connect.User = b.user.Username()
+		connect.Pass, _ = b.user.Password()
+	}
+	data, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+	cmd := "CONNECT " + string(data) + "\r\n"
+	if b.jetstream {
+		cmd += "SUB " + b.inbox + ".* 1\r\n"
+	}
+	// the PONG confirms the connection was accepted
+	if _, err := io.WriteString(b.conn, cmd+"PING\r\n"); err != nil {
+		return err
+	}
+	return b.readUntil(func(op string, args []string, payload []byte) (bool, error) {
+		return op == "PONG", nil
+	})
+}
+
+func (b *natsBroker) Publish(ctx context.Context, subject string, msg *Message) error {
+	err := b.publish(ctx, subject, msg)
+	if err != nil && err != errMessageTooLarge {
+		b.Close()
+	}
+	return err
+}
+
+func (b *natsBroker) publish(ctx context.Context, subject string, msg *Message) error {
+	if b.conn == nil {
+		if err := b.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		b.conn.SetDeadline(deadline)
+	}
+	// unblock the connection if the publication is aborted
+	conn, done := b.conn, make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	value := msg.Value
+	if !b.jetstream {
+		if b.maxPayload > 0 && len(value) > b.maxPayload {
+			return errMessageTooLarge
+		}
+		// the server answers the PING once it processed the message
+		cmd := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(value), value)
+		if _, err := io.WriteString(b.conn, cmd); err != nil {
+			return err
+		}
+		return b.readUntil(func(op string, args []string, payload []byte) (bool, error) {
+			return op == "PONG", nil
+		})
+	}
+	b.reqID++
+	reply := b.inbox + "." + strconv.FormatUint(b.reqID, 10)
+	header := "NATS/1.0\r\nNats-Msg-Id: " + subject + ":" + msg.ID + "\r\n\r\n"
+	if b.maxPayload > 0 && len(header)+len(value) > b.maxPayload {
+		return errMessageTooLarge
+	}
+	cmd := fmt.Sprintf("HPUB %s %s %d %d\r\n%s%s\r\n", subject, reply, len(header), len(header)+len(value), header, value)
+	if _, err := io.WriteString(b.conn, cmd); err != nil {
+		return err
+	}
+	return b.readUntil(func(op string, args []string, payload []byte) (bool, error) {
+		if (op != "MSG" && op != "HMSG") || args[0] != reply {
+			return false, nil
+		}
+		return true, jetStreamAckError(op, args, payload)
+	})
+}
+
+// jetStreamAckError returns the error of the acknowledgment of a publication
+func jetStreamAckError(op string, args []string, payload []byte) error {
+	if op == "HMSG" {
+		// a status without a body, e.g. 503 if no stream stores the subject
+		hdrLen, _ := strconv.Atoi(args[len(args)-2])
+		if hdrLen > len(payload) {
+			return errors.New("invalid JetStream acknowledgment")
+		}
+		status := strings.SplitN(string(payload[:hdrLen]), "\r\n", 2)[0]
+		if fields := strings.Fields(status); len(fields) > 1 {
+			return fmt.Errorf("JetStream rejected the message: %s", strings.Join(fields[1:], " "))
+		}
+		payload = payload[hdrLen:]
+	}
+	var ack struct {
+		Stream string `json:"stream"`
+		Error  *struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(payload, &ack); err != nil {
+		return fmt.Errorf("invalid JetStream acknowledgment: %v", err)
+	}
+	if ack.Error != nil {
+		return fmt.Errorf("JetStream rejected the message: %s (%d)", ack.Error.Description, ack.Error.Code)
+	}
+	return nil
+}
+
+// readUntil reads the messages of the server until done accepts one, answering the
+// PINGs of the server meanwhile
+func (b *natsBroker) readUntil(done func(op string, args []string, payload []byte) (bool, error)) error {
+	for {
+		line, err := b.readLine()
+		if err != nil {
+			return err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		op, args := strings.ToUpper(fields[0]), fields[1:]
+		var payload []byte
+		switch op {
+		case "PING":
+			if _, err := io.WriteString(b.conn, "PONG\r\n"); err != nil {
+				return err
+			}
+			continue
+		case "-ERR":
+			reason := strings.Join(args, " ")
+			if strings.Contains(strings.ToLower(reason), "maximum payload") {
+				// the server closes the connection after the violation
+				b.Close()
+				return errMessageTooLarge
+			}
+			return fmt.Errorf("NATS server error: %s", reason)
+		case "MSG", "HMSG":
+			// MSG <subject> <sid> [reply] <size>, HMSG <subject> <sid> [reply] <header size> <size>
+			if len(args) < 3 {
+				return fmt.Errorf("invalid NATS message %q", line)
+			}
+			size, err := strconv.Atoi(args[len(args)-1])
+			if err != nil || size < 0 {
+				return fmt.Errorf("invalid NATS message %q", line)
+			}
+			payload = make([]byte, size+2)
+			if _, err := io.ReadFull(b.r, payload); err != nil {
+				return err
+			}
+			payload = payload[:size]
+		}
+		if ok, err := done(op, args, payload); ok || err != nil {
+			return err
+		}
+	}
+}
+
+func (b *natsBroker) readLine() (string, error) {
+	line, err := b.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (b *natsBroker) Close() error {
+	if b.conn == nil {
+		return nil
+	}
+	err := b.conn.Close()
+	b.conn, b.r = nil, nil
+	return err
+}
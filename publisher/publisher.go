@@ -0,0 +1,421 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package publisher pushes the new blocks, their receipts and the permission events
+// to the topics of a Kafka or NATS message broker, so that indexers consume the chain
+// data without polling the RPC APIs.
+//
+// The blocks are delivered at least once, in order: a block is published again until
+// the broker acknowledges it, and the number of the next block to publish is
+// checkpointed in the database so that the publication resumes where it stopped after a
+// restart. A message larger than the broker accepts is dropped. The permission events
+// are not persisted, the ones raised while the node is down are not published.
+package publisher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/catchup"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+)
+
+const (
+	chainHeadChanSize       = 16
+	permissionEventChanSize = 256
+
+	// publishTimeout is how long the acknowledgment of a message is waited for
+	publishTimeout = 10 * time.Second
+
+	// retryInterval is the delay before publishing again after a failure
+	retryInterval = 5 * time.Second
+
+	// maxPendingPermissionEvents is the number of permission events kept while the
+	// broker is unavailable, the older ones are dropped
+	maxPendingPermissionEvents = 4096
+)
+
+// checkpointPrefix + topic prefix -> number of the next block to publish
+var checkpointPrefix = []byte("quorum-publisher-checkpoint-")
+
+var (
+	publishedBlocksMeter = metrics.NewRegisteredMeter("publisher/blocks", nil)
+	publishErrorsMeter   = metrics.NewRegisteredMeter("publisher/errors", nil)
+	droppedEventsMeter   = metrics.NewRegisteredMeter("publisher/permissions/dropped", nil)
+	oversizedMeter       = metrics.NewRegisteredMeter("publisher/oversized", nil)
+)
+
+// errMessageTooLarge is returned by a broker for a message larger than it accepts,
+// which would never be published
+var errMessageTooLarge = errors.New("message too large for the broker")
+
+// Topics of the messages, after the topic prefix
+const (
+	BlocksTopic      = "blocks"
+	ReceiptsTopic    = "receipts"
+	PermissionsTopic = "permissions"
+)
+
+// Config is the broker the chain data is published to
+type Config struct {
+	// URL of the broker, publication is disabled if empty:
+	//   nats(+tls)://[user:password@]host:port[?jetstream=true][&tlsca=file] for NATS,
+	//   the messages are acknowledged by JetStream if enabled, by the server otherwise.
+	//   The connection is secured with TLS with nats+tls or if the server requires it,
+	//   the certificate of the server being verified against the CAs of tlsca if set.
+	//   kafka+http(s)://host:port[/path] for the Kafka REST proxy
+	URL string `toml:",omitempty"`
+	// Topic is the prefix of the topics (NATS subjects), e.g. the blocks are published
+	// to <Topic>.blocks
+	Topic string `toml:",omitempty"`
+}
+
+// DefaultConfig contains the default publisher options
+var DefaultConfig = Config{
+	Topic: "quorum",
+}
+
+// Enabled reports whether the chain data is published
+func (c *Config) Enabled() bool {
+	return c.URL != ""
+}
+
+// Validate checks the publisher options
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Topic == "" {
+		return errors.New("the publisher topic prefix must be set")
+	}
+	_, err := newBroker(c.URL)
+	return err
+}
+
+// Message is a message published to a topic
+type Message struct {
+	// Key orders the messages: the messages of a key are delivered in the order they
+	// were published, e.g. being sent to the same Kafka partition
+	Key string
+	// ID identifies the message, so that the broker drops the message published again
+	ID    string
+	Value []byte
+}
+
+// Broker is a message broker
+type Broker interface {
+	// Publish sends a message to a topic and returns once the broker acknowledged it,
+	// errMessageTooLarge if the broker does not accept a message of its size
+	Publish(ctx context.Context, topic string, msg *Message) error
+	Close() error
+}
+
+// newBroker returns the client of the broker at rawurl
+func newBroker(rawurl string) (Broker, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publisher URL: %v", err)
+	}
+	switch u.Scheme {
+	case "nats", "nats+tls":
+		return newNATSBroker(u)
+	case "kafka+http", "kafka+https":
+		return newKafkaRESTBroker(u)
+	}
+	return nil, fmt.Errorf("unsupported publisher URL scheme %q, want nats, nats+tls, kafka+http or kafka+https", u.Scheme)
+}
+
+// Backend is the chain being published
+type Backend interface {
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// blockMessage is the message of a block
+type blockMessage struct {
+	Header       *types.Header        `json:"header"`
+	Transactions []*types.Transaction `json:"transactions"`
+}
+
+// receiptsMessage is the message of the receipts of a block. The receipts of the
+// private transactions are the public ones, the private receipts are not published.
+type receiptsMessage struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Receipts    types.Receipts `json:"receipts"`
+}
+
+// permissionMessage is the message of a permission event
+type permissionMessage struct {
+	Kind string      `json:"kind"`
+	Info interface{} `json:"info"`
+}
+
+// queuedEvent is a permission event waiting to be published
+type queuedEvent struct {
+	seq uint64 // sequence number of the event since the start
+	ev  pcore.PermissionEvent
+}
+
+// Service publishes the chain data, from the checkpoint to the head
+type Service struct {
+	topic   string
+	broker  Broker
+	backend Backend
+	db      ethdb.KeyValueStore // checkpoint store
+	runID   string              // random identifier of the run, prefix of the IDs of the permission events
+
+	next uint64 // number of the next block to publish
+
+	pendingMu sync.Mutex
+	pending   []queuedEvent // permission events to publish, oldest first
+	seq       uint64        // sequence number of the next queued permission event
+	loop      *catchup.Loop
+
+	ctx    context.Context // canceled on stop, aborting the pending publication
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates the publisher of the chain data of backend, the checkpoint being stored
+// in db
+func New(config *Config, backend Backend, db ethdb.KeyValueStore) (*Service, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	broker, err := newBroker(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	return newService(config.Topic, broker, backend, db), nil
+}
+
+func newService(topic string, broker Broker, backend Backend, db ethdb.KeyValueStore) *Service {
+	var runID [8]byte
+	if _, err := rand.Read(runID[:]); err != nil {
+		panic(err)
+	}
+	return &Service{
+		topic:   topic,
+		broker:  broker,
+		backend: backend,
+		db:      db,
+		runID:   hex.EncodeToString(runID[:]),
+		loop:    catchup.NewLoop(retryInterval),
+	}
+}
+
+// Start implements node.Lifecycle, starting the publication from the checkpoint
+func (s *Service) Start() error {
+	next, err := s.readCheckpoint()
+	if err != nil {
+		return err
+	}
+	s.next = next
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	log.Info("Publishing the chain data", "topic", s.topic, "from", s.next)
+
+	// subscribed before the loops start, so that no event is missed
+	heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	headSub := s.backend.SubscribeChainHeadEvent(heads)
+	permissions := make(chan pcore.PermissionEvent, permissionEventChanSize)
+	permissionSub := pcore.SubscribePermissionEvent(permissions)
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		defer headSub.Unsubscribe()
+		defer permissionSub.Unsubscribe()
+		s.receiveLoop(heads, permissions)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.loop.Run(s.ctx, s.publishPending, func(err error) {
+			publishErrorsMeter.Mark(1)
+			log.Warn("Failed to publish the chain data, retrying", "next", s.next, "err", err)
+		})
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle
+func (s *Service) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.cancel = nil
+	return s.broker.Close()
+}
+
+// receiveLoop drains the subscriptions, so that the chain and the permission events
+// are never held up by the broker: the heads are coalesced into a single wake-up of the
+// publication, which reads the head from the chain, and the permission events are queued
+func (s *Service) receiveLoop(heads chan core.ChainHeadEvent, permissions chan pcore.PermissionEvent) {
+	for {
+		select {
+		case <-heads:
+		case ev := <-permissions:
+			s.queue(ev)
+		case <-s.ctx.Done():
+			return
+		}
+		s.loop.Wake()
+	}
+}
+
+// queue records a permission event to publish, dropping the oldest one if the broker
+// is unavailable for too long
+func (s *Service) queue(ev pcore.PermissionEvent) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if len(s.pending) >= maxPendingPermissionEvents {
+		droppedEventsMeter.Mark(1)
+		s.pending = s.pending[1:]
+	}
+	s.pending = append(s.pending, queuedEvent{seq: s.seq, ev: ev})
+	s.seq++
+}
+
+// nextPending returns the oldest queued permission event, false if there is none
+func (s *Service) nextPending() (queuedEvent, bool) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if len(s.pending) == 0 {
+		return queuedEvent{}, false
+	}
+	return s.pending[0], true
+}
+
+// dequeue removes a published permission event, unless it was dropped meanwhile
+func (s *Service) dequeue(seq uint64) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if len(s.pending) > 0 && s.pending[0].seq == seq {
+		s.pending = s.pending[1:]
+	}
+}
+
+// publishPending publishes the queued permission events, then the blocks up to the head
+func (s *Service) publishPending() error {
+	for {
+		queued, ok := s.nextPending()
+		if !ok {
+			break
+		}
+		ev := queued.ev
+		value, err := json.Marshal(&permissionMessage{Kind: ev.Kind, Info: ev.Info})
+		if err != nil {
+			log.Error("Failed to encode permission event, dropping it", "kind", ev.Kind, "err", err)
+		} else if err := s.publish(PermissionsTopic, fmt.Sprintf("%s-%d", s.runID, queued.seq), value); err != nil {
+			return err
+		}
+		s.dequeue(queued.seq)
+	}
+	head := s.backend.CurrentBlock().NumberU64()
+	for ; s.next <= head; s.next++ {
+		if err := s.publishBlock(s.next); err != nil {
+			return err
+		}
+		if err := s.writeCheckpoint(s.next + 1); err != nil {
+			return err
+		}
+		publishedBlocksMeter.Mark(1)
+	}
+	return nil
+}
+
+// publishBlock publishes a block and its receipts
+func (s *Service) publishBlock(number uint64) error {
+	block := s.backend.GetBlockByNumber(number)
+	if block == nil {
+		return fmt.Errorf("block #%d not found", number)
+	}
+	receipts := s.backend.GetReceiptsByHash(block.Hash())
+	if len(receipts) != len(block.Transactions()) {
+		return fmt.Errorf("receipts of block #%d not found", number)
+	}
+	id := block.Hash().Hex()
+	value, err := json.Marshal(&blockMessage{Header: block.Header(), Transactions: block.Transactions()})
+	if err != nil {
+		return err
+	}
+	if err := s.publish(BlocksTopic, id, value); err != nil {
+		return err
+	}
+	value, err = json.Marshal(&receiptsMessage{BlockNumber: number, BlockHash: block.Hash(), Receipts: receipts})
+	if err != nil {
+		return err
+	}
+	return s.publish(ReceiptsTopic, id, value)
+}
+
+// publish sends a message to a topic, keyed by the topic prefix so that the messages of
+// the chain are delivered in order. A message larger than the broker accepts is dropped.
+func (s *Service) publish(topic string, id string, value []byte) error {
+	ctx, cancel := context.WithTimeout(s.ctx, publishTimeout)
+	defer cancel()
+	err := s.broker.Publish(ctx, s.topic+"."+topic, &Message{Key: s.topic, ID: id, Value: value})
+	if err == errMessageTooLarge {
+		oversizedMeter.Mark(1)
+		log.Error("Message too large for the broker, dropping it", "topic", topic, "id", id, "size", len(value))
+		return nil
+	}
+	return err
+}
+
+func (s *Service) checkpointKey() []byte {
+	return append(append([]byte{}, checkpointPrefix...), s.topic...)
+}
+
+// readCheckpoint returns the number of the next block to publish, the genesis if the
+// chain data was never published
+func (s *Service) readCheckpoint() (uint64, error) {
+	if has, err := s.db.Has(s.checkpointKey()); err != nil || !has {
+		return 0, err
+	}
+	data, err := s.db.Get(s.checkpointKey())
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, fmt.Errorf("invalid publisher checkpoint %x", data)
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+func (s *Service) writeCheckpoint(next uint64) error {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], next)
+	return s.db.Put(s.checkpointKey(), data[:])
+}
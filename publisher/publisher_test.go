@@ -0,0 +1,364 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/event"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubBackend struct {
+	mu     sync.Mutex
+	blocks []*types.Block
+	feed   event.Feed
+}
+
+func newStubBackend(blocks int) *stubBackend {
+	b := new(stubBackend)
+	for i := 0; i < blocks; i++ {
+		b.addBlock()
+	}
+	return b
+}
+
+func (b *stubBackend) addBlock() {
+	b.mu.Lock()
+	header := &types.Header{Number: big.NewInt(int64(len(b.blocks)))}
+	block := types.NewBlock(header, nil, nil, nil, new(trie.Trie))
+	b.blocks = append(b.blocks, block)
+	b.mu.Unlock()
+	b.feed.Send(core.ChainHeadEvent{Block: block})
+}
+
+func (b *stubBackend) hash(number int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocks[number].Hash().Hex()
+}
+
+func (b *stubBackend) CurrentBlock() *types.Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocks[len(b.blocks)-1]
+}
+
+func (b *stubBackend) GetBlockByNumber(number uint64) *types.Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocks[number]
+}
+
+func (b *stubBackend) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	return types.Receipts{}
+}
+
+func (b *stubBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.feed.Subscribe(ch)
+}
+
+type message struct {
+	topic, id string
+}
+
+// stubBroker records the messages, the ones after failAfter are rejected, as well as the
+// ones larger than maxSize if set. If release is set, the messages are held until it is
+// closed.
+type stubBroker struct {
+	mu        sync.Mutex
+	messages  []message
+	failAfter int
+	maxSize   int
+	published chan struct{}
+	release   chan struct{}
+}
+
+func newStubBroker() *stubBroker {
+	return &stubBroker{failAfter: -1, published: make(chan struct{}, 100)}
+}
+
+func (b *stubBroker) Publish(ctx context.Context, topic string, msg *Message) error {
+	if b.release != nil {
+		select {
+		case <-b.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failAfter >= 0 && len(b.messages) >= b.failAfter {
+		return errors.New("unavailable")
+	}
+	if b.maxSize > 0 && len(msg.Value) > b.maxSize {
+		return errMessageTooLarge
+	}
+	b.messages = append(b.messages, message{topic, msg.ID})
+	b.published <- struct{}{}
+	return nil
+}
+
+func (b *stubBroker) Close() error { return nil }
+
+// waitMessages waits for n more messages and returns all the published messages
+func (b *stubBroker) waitMessages(t *testing.T, n int) []message {
+	for i := 0; i < n; i++ {
+		select {
+		case <-b.published:
+		case <-time.After(time.Second):
+			t.Fatalf("%d messages published, want %d", i, n)
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]message{}, b.messages...)
+}
+
+func TestService_resumesFromCheckpoint(t *testing.T) {
+	backend, broker, db := newStubBackend(3), newStubBroker(), memorydb.New()
+	s := newService("quorum", broker, backend, db)
+
+	require.NoError(t, s.Start())
+	messages := broker.waitMessages(t, 6)
+	require.NoError(t, s.Stop())
+
+	assert.Equal(t, message{"quorum.blocks", backend.hash(0)}, messages[0])
+	assert.Equal(t, message{"quorum.receipts", backend.hash(0)}, messages[1])
+	assert.Equal(t, message{"quorum.blocks", backend.hash(2)}, messages[4])
+
+	backend.addBlock()
+	s = newService("quorum", broker, backend, db)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+	messages = broker.waitMessages(t, 2)
+
+	require.Len(t, messages, 8, "the publication resumes after the checkpoint")
+	assert.Equal(t, message{"quorum.blocks", backend.hash(3)}, messages[6])
+
+	backend.addBlock()
+	messages = broker.waitMessages(t, 2)
+
+	assert.Equal(t, message{"quorum.receipts", backend.hash(4)}, messages[9], "the new head is published")
+}
+
+func TestService_whenBrokerBlocks(t *testing.T) {
+	backend, broker, db := newStubBackend(1), newStubBroker(), memorydb.New()
+	broker.release = make(chan struct{})
+	s := newService("quorum", broker, backend, db)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	heads := 4 * chainHeadChanSize
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < heads; i++ {
+			backend.addBlock()
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the chain head events are held up by the broker")
+	}
+	close(broker.release)
+	messages := broker.waitMessages(t, 2*(heads+1))
+
+	assert.Equal(t, message{"quorum.receipts", backend.hash(heads)}, messages[len(messages)-1], "the coalesced heads are published")
+}
+
+func TestService_publishPending_whenBrokerFails(t *testing.T) {
+	backend, broker, db := newStubBackend(2), newStubBroker(), memorydb.New()
+	broker.failAfter = 3 // the receipts of block #1
+	s := newService("quorum", broker, backend, db)
+	s.ctx = context.Background()
+
+	assert.Error(t, s.publishPending())
+	next, err := s.readCheckpoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), next)
+
+	broker.failAfter = -1
+	require.NoError(t, s.publishPending())
+
+	messages := broker.waitMessages(t, 5)
+	assert.Equal(t, []message{
+		{"quorum.blocks", backend.hash(0)},
+		{"quorum.receipts", backend.hash(0)},
+		{"quorum.blocks", backend.hash(1)},
+		{"quorum.blocks", backend.hash(1)},
+		{"quorum.receipts", backend.hash(1)},
+	}, messages, "the block is published again")
+	next, err = s.readCheckpoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), next)
+}
+
+func TestService_publishPending_dropsOversizedMessages(t *testing.T) {
+	backend, broker, db := newStubBackend(2), newStubBroker(), memorydb.New()
+	s := newService("quorum", broker, backend, db)
+	s.ctx = context.Background()
+	block, err := json.Marshal(&blockMessage{Header: backend.GetBlockByNumber(0).Header()})
+	require.NoError(t, err)
+	broker.maxSize = len(block) - 1
+
+	require.NoError(t, s.publishPending())
+
+	messages := broker.waitMessages(t, 2)
+	assert.Equal(t, []message{
+		{"quorum.receipts", backend.hash(0)},
+		{"quorum.receipts", backend.hash(1)},
+	}, messages, "the blocks too large are dropped")
+	next, err := s.readCheckpoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), next)
+}
+
+func TestService_publishPending_identifiesPermissionEvents(t *testing.T) {
+	backend, broker, db := newStubBackend(1), newStubBroker(), memorydb.New()
+	s := newService("quorum", broker, backend, db)
+	s.ctx = context.Background()
+	ev := pcore.PermissionEvent{Kind: pcore.OrgPermissionEvent, Info: &pcore.OrgInfo{OrgId: "ORG1"}}
+	s.queue(ev)
+	s.queue(ev)
+
+	require.NoError(t, s.publishPending())
+
+	messages := broker.waitMessages(t, 4)
+	assert.Equal(t, "quorum.permissions", messages[0].topic)
+	assert.Equal(t, "quorum.permissions", messages[1].topic)
+	assert.NotEqual(t, messages[0].id, messages[1].id, "the identical events are distinct messages")
+	assert.NotEqual(t, s.runID, newService("quorum", broker, backend, db).runID, "the events of another run are distinct messages")
+}
+
+func TestKafkaRESTBroker_Publish(t *testing.T) {
+	var records map[string][]kafkaRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/kafka/topics/quorum.blocks", r.URL.Path)
+		assert.Equal(t, kafkaRecordsContentType, r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &records))
+		if string(records["records"][0].Value) == "rejected" {
+			fmt.Fprint(w, `{"offsets":[{"partition":null,"offset":null,"error_code":40403,"error":"unknown topic"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"offsets":[{"partition":0,"offset":7,"error_code":null,"error":null}]}`)
+	}))
+	defer server.Close()
+	u, err := url.Parse("kafka+" + server.URL + "/kafka/")
+	require.NoError(t, err)
+	broker, err := newKafkaRESTBroker(u)
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish(context.Background(), "quorum.blocks", &Message{Key: "quorum", ID: "0x01", Value: []byte("value")}))
+	assert.Equal(t, []kafkaRecord{{Key: []byte("quorum"), Value: []byte("value")}}, records["records"])
+
+	assert.Error(t, broker.Publish(context.Background(), "quorum.blocks", &Message{Key: "quorum", ID: "0x02", Value: []byte("rejected")}))
+}
+
+// serveJetStream accepts a NATS client, storing the first message in a stream and
+// rejecting the next ones
+func serveJetStream(l net.Listener, received chan<- string) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "INFO {\"headers\":true,\"max_payload\":64}\r\n")
+	for seq := 1; ; {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "HPUB":
+			size, _ := strconv.Atoi(fields[4])
+			msg := make([]byte, size+2)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			received <- fields[1] + " " + string(msg[:size])
+			ack := fmt.Sprintf(`{"stream":"QUORUM","seq":%d}`, seq)
+			if seq > 1 {
+				ack = `{"error":{"code":503,"description":"insufficient resources"}}`
+			}
+			fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", fields[2], len(ack), ack)
+			seq++
+		}
+	}
+}
+
+func TestNATSBroker_Publish_jetstream(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	received := make(chan string, 2)
+	go serveJetStream(l, received)
+	u, err := url.Parse("nats://" + l.Addr().String() + "?jetstream=true")
+	require.NoError(t, err)
+	broker, err := newNATSBroker(u)
+	require.NoError(t, err)
+	defer broker.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, broker.Publish(ctx, "quorum.blocks", &Message{Key: "quorum", ID: "0x01", Value: []byte("block")}))
+	assert.Equal(t, "quorum.blocks NATS/1.0\r\nNats-Msg-Id: quorum.blocks:0x01\r\n\r\nblock", <-received)
+
+	assert.Equal(t, errMessageTooLarge, broker.Publish(ctx, "quorum.blocks", &Message{Key: "quorum", ID: "0x02", Value: make([]byte, 64)}))
+
+	assert.Error(t, broker.Publish(ctx, "quorum.blocks", &Message{Key: "quorum", ID: "0x03", Value: []byte("block")}))
+	assert.Nil(t, broker.conn, "the connection is reset after a failure")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for _, rawurl := range []string{"nats://localhost", "nats+tls://localhost", "kafka+http://localhost:8082", "kafka+https://proxy/kafka"} {
+		assert.NoError(t, (&Config{URL: rawurl, Topic: "quorum"}).Validate(), rawurl)
+	}
+	for _, rawurl := range []string{"kafka://localhost:9092", "nats://", "nats+tls://localhost?tlsca=/nonexistent", "http://localhost"} {
+		assert.Error(t, (&Config{URL: rawurl, Topic: "quorum"}).Validate(), rawurl)
+	}
+	assert.Error(t, (&Config{URL: "nats://localhost"}).Validate())
+}
@@ -0,0 +1,185 @@
+package qlight
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/clientserver"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/private/engine"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// number of payloads kept by the client, including the ones it is not entitled to
+	cachedPayloads = 10000
+
+	// retryInterval is the delay before fetching again a payload the server didn't
+	// answer, e.g. while it is disconnected
+	retryInterval = 2 * time.Second
+)
+
+// Client is the client side of the qlight protocol. It is the private transaction
+// manager of the client node: the payloads are fetched from the server, which only
+// returns the payloads the private state of the client is entitled to.
+//
+// A qlight client can't send private transactions, it is meant for read-heavy workloads.
+type Client struct {
+	psi   string
+	token string
+	conn  *clientserver.Client
+
+	cache *lru.Cache // EncryptedPayloadHash -> *PrivateData
+}
+
+// NewClient creates the client of a server, the private state of the client is
+// the one of the psi
+func NewClient(psi, token string, server *enode.Node) *Client {
+	cache, _ := lru.New(cachedPayloads)
+	return &Client{
+		psi:   psi,
+		token: token,
+		conn:  clientserver.NewClient(spec, server.ID()),
+		cache: cache,
+	}
+}
+
+// Close aborts the pending and future fetches of payloads
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+func (c *Client) status() *statusData {
+	return &statusData{Version: ProtocolVersion, Client: true, PSI: c.psi, Token: c.token}
+}
+
+func (c *Client) handle(p *p2p.Peer, rw p2p.MsgReadWriter, peerStatus *statusData) error {
+	if peerStatus.Client || !c.conn.IsServer(p) {
+		return clientserver.Idle(rw)
+	}
+	log.Info("Connected to the qlight server", "peer", p.ID(), "psi", c.psi)
+	return c.conn.Serve(rw, func(msg p2p.Msg) (uint64, interface{}, error) {
+		if msg.Code != PrivateDataMsg {
+			return 0, nil, errUnexpectedMsg
+		}
+		var resp privateDataData
+		err := msg.Decode(&resp)
+		return resp.ReqID, resp.Data, err
+	})
+}
+
+// request fetches payloads from the server
+func (c *Client) request(hashes []common.EncryptedPayloadHash) ([]*PrivateData, error) {
+	resp, err := c.conn.Request(GetPrivateDataMsg, func(reqID uint64) interface{} {
+		return &getPrivateDataData{ReqID: reqID, Hashes: hashes}
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	data := resp.([]*PrivateData)
+	if len(data) != len(hashes) {
+		return nil, errUnexpectedMsg
+	}
+	// the answer is cached, a payload of another transaction would corrupt
+	// the private state until the client restarts
+	for i, d := range data {
+		if d == nil || d.Hash != hashes[i] {
+			return nil, errUnexpectedMsg
+		}
+	}
+	return data, nil
+}
+
+// fetch returns the payload of a private transaction. The private state would
+// diverge if a payload was skipped, so fetch waits for the server until it answers
+// or the client is closed.
+func (c *Client) fetch(hash common.EncryptedPayloadHash) (*PrivateData, error) {
+	if cached, ok := c.cache.Get(hash); ok {
+		return cached.(*PrivateData), nil
+	}
+	for {
+		if err := c.conn.WaitConnected(nil); err != nil {
+			return nil, err
+		}
+		data, err := c.request([]common.EncryptedPayloadHash{hash})
+		if err == nil {
+			c.cache.Add(hash, data[0])
+			return data[0], nil
+		}
+		if err == ErrClientClosed {
+			return nil, err
+		}
+		log.Warn("Failed to fetch private payload from the qlight server, retrying", "hash", hash.TerminalString(), "err", err)
+		select {
+		case <-time.After(retryInterval):
+		case <-c.conn.Closed():
+			return nil, ErrClientClosed
+		}
+	}
+}
+
+func (c *Client) Name() string {
+	return "QLight"
+}
+
+func (c *Client) HasFeature(f engine.PrivateTransactionManagerFeature) bool {
+	return false
+}
+
+func (c *Client) Receive(hash common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+	if common.EmptyEncryptedPayloadHash(hash) {
+		return "", nil, nil, nil, nil
+	}
+	data, err := c.fetch(hash)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	if len(data.Payload) == 0 {
+		return "", nil, nil, nil, nil
+	}
+	extra := data.extraMetadata()
+	var managedParties []string
+	if extra != nil {
+		managedParties = extra.ManagedParties
+	}
+	return data.Sender, managedParties, data.Payload, extra, nil
+}
+
+func (c *Client) ReceiveRaw(hash common.EncryptedPayloadHash) ([]byte, string, *engine.ExtraMetadata, error) {
+	sender, _, payload, extra, err := c.Receive(hash)
+	return payload, sender, extra, err
+}
+
+func (c *Client) IsSender(txHash common.EncryptedPayloadHash) (bool, error) {
+	return false, nil
+}
+
+func (c *Client) GetParticipants(txHash common.EncryptedPayloadHash) ([]string, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (c *Client) Send(data []byte, from string, to []string, extra *engine.ExtraMetadata) (string, []string, common.EncryptedPayloadHash, error) {
+	return "", nil, common.EncryptedPayloadHash{}, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (c *Client) StoreRaw(data []byte, from string) (common.EncryptedPayloadHash, error) {
+	return common.EncryptedPayloadHash{}, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (c *Client) SendSignedTx(data common.EncryptedPayloadHash, to []string, extra *engine.ExtraMetadata) (string, []string, []byte, error) {
+	return "", nil, nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (c *Client) EncryptPayload(data []byte, from string, to []string, extra *engine.ExtraMetadata) ([]byte, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (c *Client) DecryptPayload(payload common.DecryptRequest) ([]byte, *engine.ExtraMetadata, error) {
+	return nil, nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (c *Client) Groups() ([]engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
@@ -0,0 +1,169 @@
+// Package qlight implements the qlight client node mode: a lightweight node which
+// syncs the blocks from a designated full node server, and fetches from it the
+// private payloads its private state is entitled to instead of running its own
+// private transaction manager.
+//
+// The qlight sub-protocol runs alongside the eth protocol, which still carries the
+// blocks. Both sides exchange a status, then the client requests the payloads of
+// the private transactions while executing the blocks, and the server answers with
+// the payloads its private transaction manager has for the private state of the
+// client.
+//
+// The server only serves the clients it is configured with an entitlement for, by
+// their node ID, or on a multitenant server the clients whose token grants them
+// access to their private state. The payloads served to a client may further be
+// restricted to some TM keys, and rate limited.
+package qlight
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/clientserver"
+	"github.com/ethereum/go-ethereum/private/engine"
+)
+
+const (
+	ProtocolName    = "qlight"
+	ProtocolVersion = 1
+	ProtocolLength  = 3
+
+	// maximum size of a protocol message
+	protocolMaxMsgSize = 10 * 1024 * 1024
+
+	// maximum number of payloads requested in a message
+	maxPayloadsPerRequest = 256
+
+	// handshakeTimeout is how long the status of the peer is waited for
+	handshakeTimeout = 5 * time.Second
+
+	// requestTimeout is how long an answer of the server is waited for
+	requestTimeout = 10 * time.Second
+)
+
+// qlight protocol message codes
+const (
+	StatusMsg         = 0x00
+	GetPrivateDataMsg = 0x01
+	PrivateDataMsg    = 0x02
+)
+
+var (
+	ErrNotAuthorized      = errors.New("qlight client not authorized for the private state")
+	ErrNotEntitled        = errors.New("qlight client has no entitlement on the server")
+	ErrServerDisconnected = clientserver.ErrServerDisconnected
+	ErrClientClosed       = clientserver.ErrClientClosed
+	errUnexpectedMsg      = clientserver.ErrUnexpectedMsg
+)
+
+// spec is the sub-protocol, the status of the peers being a statusData
+var spec = &clientserver.Spec{
+	Name:             ProtocolName,
+	Version:          ProtocolVersion,
+	Length:           ProtocolLength,
+	StatusCode:       StatusMsg,
+	MaxMsgSize:       protocolMaxMsgSize,
+	HandshakeTimeout: handshakeTimeout,
+	RequestTimeout:   requestTimeout,
+	NewStatus:        func() clientserver.Status { return new(statusData) },
+}
+
+// statusData is the first message sent by both sides
+type statusData struct {
+	Version uint32
+	Client  bool
+	PSI     string // private state of the client
+	Token   string // token authorizing the client on a multitenant server
+}
+
+func (s *statusData) ProtocolVersion() uint32 {
+	return s.Version
+}
+
+// getPrivateDataData requests the payloads of private transactions
+type getPrivateDataData struct {
+	ReqID  uint64
+	Hashes []common.EncryptedPayloadHash
+}
+
+// privateDataData answers a getPrivateDataData, in the order of the request
+type privateDataData struct {
+	ReqID uint64
+	Data  []*PrivateData
+}
+
+// PrivateData is the payload of a private transaction. The payload is empty if the
+// private state of the client is not a party of the transaction
+type PrivateData struct {
+	Hash    common.EncryptedPayloadHash
+	Sender  string
+	Payload []byte
+	Extra   *extraMetadata `rlp:"nil"`
+}
+
+// extraMetadata is the RLP encoding of engine.ExtraMetadata
+type extraMetadata struct {
+	ACHashes       []common.EncryptedPayloadHash
+	ACMerkleRoot   common.Hash
+	PrivacyFlag    uint64
+	ManagedParties []string
+	Sender         string
+}
+
+func newPrivateData(hash common.EncryptedPayloadHash, sender string, payload []byte, extra *engine.ExtraMetadata) *PrivateData {
+	data := &PrivateData{Hash: hash, Sender: sender, Payload: payload}
+	if extra != nil {
+		hashes := make([]common.EncryptedPayloadHash, 0, len(extra.ACHashes))
+		for hash := range extra.ACHashes {
+			hashes = append(hashes, hash)
+		}
+		data.Extra = &extraMetadata{
+			ACHashes:       hashes,
+			ACMerkleRoot:   extra.ACMerkleRoot,
+			PrivacyFlag:    uint64(extra.PrivacyFlag),
+			ManagedParties: extra.ManagedParties,
+			Sender:         extra.Sender,
+		}
+	}
+	return data
+}
+
+func (d *PrivateData) extraMetadata() *engine.ExtraMetadata {
+	if d.Extra == nil {
+		return nil
+	}
+	hashes := make(common.EncryptedPayloadHashes, len(d.Extra.ACHashes))
+	for _, hash := range d.Extra.ACHashes {
+		hashes.Add(hash)
+	}
+	return &engine.ExtraMetadata{
+		ACHashes:       hashes,
+		ACMerkleRoot:   d.Extra.ACMerkleRoot,
+		PrivacyFlag:    engine.PrivacyFlagType(d.Extra.PrivacyFlag),
+		ManagedParties: d.Extra.ManagedParties,
+		Sender:         d.Extra.Sender,
+	}
+}
+
+// Handler serves the qlight protocol for one side, client or server
+type Handler interface {
+	// status returns the status sent to the peers
+	status() *statusData
+	// handle serves a peer once the status were exchanged. It returns when the
+	// connection is torn down
+	handle(p *p2p.Peer, rw p2p.MsgReadWriter, peerStatus *statusData) error
+}
+
+// MakeProtocol returns the qlight sub-protocol served by the handler
+func MakeProtocol(h Handler) p2p.Protocol {
+	return spec.Protocol(func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+		peerStatus, err := spec.Handshake(rw, h.status())
+		if err != nil {
+			p.Log().Debug("qlight handshake failed", "err", err)
+			return err
+		}
+		return h.handle(p, rw, peerStatus.(*statusData))
+	})
+}
@@ -0,0 +1,272 @@
+package qlight
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/plugin/security"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/private/engine/memory"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partiesPTM is an in-memory private transaction manager returning the managed
+// parties of the payloads
+type partiesPTM struct {
+	*memory.PrivateTransactionManager
+	parties map[common.EncryptedPayloadHash][]string
+}
+
+func (ptm *partiesPTM) Receive(hash common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+	sender, _, data, extra, err := ptm.PrivateTransactionManager.Receive(hash)
+	if extra != nil {
+		withParties := *extra
+		withParties.ManagedParties = ptm.parties[hash]
+		extra = &withParties
+	}
+	return sender, ptm.parties[hash], data, extra, err
+}
+
+type stubPSMR struct {
+	mps.PrivateStateMetadataResolver
+	psm *mps.PrivateStateMetadata
+}
+
+func (r *stubPSMR) ResolveForUserContext(ctx context.Context) (*mps.PrivateStateMetadata, error) {
+	return r.psm, nil
+}
+
+func (r *stubPSMR) NotIncludeAny(psm *mps.PrivateStateMetadata, managedParties ...string) bool {
+	return psm.NotIncludeAny(managedParties...)
+}
+
+func newPartiesPTM() *partiesPTM {
+	return &partiesPTM{memory.New(), make(map[common.EncryptedPayloadHash][]string)}
+}
+
+func (ptm *partiesPTM) send(t *testing.T, data string, parties ...string) common.EncryptedPayloadHash {
+	_, _, hash, err := ptm.Send([]byte(data), "A", nil, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStandardPrivate})
+	require.NoError(t, err)
+	ptm.parties[hash] = parties
+	return hash
+}
+
+func TestClient_Receive(t *testing.T) {
+	ptm := newPartiesPTM()
+	entitled, other := ptm.send(t, "entitled", "A", "B"), ptm.send(t, "other", "C")
+	psm := mps.NewPrivateStateMetadata("tenantA", "tenantA", "", mps.Resident, []string{"A"})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	serverNode := enode.NewV4(&key.PublicKey, nil, 0, 0)
+	server, err := NewServer(ptm, &stubPSMR{psm: psm}, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {PSIs: []string{"tenantA"}}},
+	})
+	require.NoError(t, err)
+	client := NewClient("tenantA", "", serverNode)
+	defer client.Close()
+
+	clientRW, serverRW := p2p.MsgPipe()
+	defer clientRW.Close()
+	go MakeProtocol(client).Run(p2p.NewPeer(serverNode.ID(), "server", nil), clientRW)
+	go MakeProtocol(server).Run(p2p.NewPeer(enode.ID{1}, "client", nil), serverRW)
+
+	sender, managedParties, data, extra, err := client.Receive(entitled)
+
+	require.NoError(t, err)
+	assert.Equal(t, "A", sender)
+	assert.Equal(t, []byte("entitled"), data)
+	require.NotNil(t, extra)
+	assert.Equal(t, "A", extra.Sender)
+	assert.Equal(t, []string{"A"}, managedParties, "the parties of the other private states are not sent")
+
+	_, _, data, extra, err = client.Receive(other)
+
+	require.NoError(t, err)
+	assert.Nil(t, data, "the private state of the client is not a party")
+	assert.Nil(t, extra)
+
+	_, _, _, err = client.Send([]byte("data"), "A", nil, &engine.ExtraMetadata{})
+	assert.Equal(t, engine.ErrPrivateTxManagerNotSupported, err)
+}
+
+func TestClient_Receive_whenClosedBeforeServerConnects(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	client := NewClient("private", "", enode.NewV4(&key.PublicKey, nil, 0, 0))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		client.Close()
+	}()
+	_, _, _, _, err = client.Receive(common.BytesToEncryptedPayloadHash([]byte("hash")))
+
+	assert.Equal(t, ErrClientClosed, err)
+}
+
+func TestClient_request_whenServerAnswersOtherPayload(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	serverNode := enode.NewV4(&key.PublicKey, nil, 0, 0)
+	client := NewClient("private", "", serverNode)
+	defer client.Close()
+
+	clientRW, serverRW := p2p.MsgPipe()
+	defer clientRW.Close()
+	go MakeProtocol(client).Run(p2p.NewPeer(serverNode.ID(), "server", nil), clientRW)
+	go func() {
+		if _, err := spec.Handshake(serverRW, &statusData{Version: ProtocolVersion}); err != nil {
+			return
+		}
+		msg, err := serverRW.ReadMsg()
+		if err != nil {
+			return
+		}
+		var req getPrivateDataData
+		if err := msg.Decode(&req); err != nil {
+			return
+		}
+		p2p.Send(serverRW, PrivateDataMsg, &privateDataData{ReqID: req.ReqID, Data: []*PrivateData{{Hash: common.EncryptedPayloadHash{2}}}})
+	}()
+	<-client.conn.Connected()
+
+	_, err = client.request([]common.EncryptedPayloadHash{{1}})
+
+	assert.Equal(t, errUnexpectedMsg, err)
+	_, cached := client.cache.Get(common.EncryptedPayloadHash{1})
+	assert.False(t, cached)
+}
+
+func TestServer_rejectsClientWithoutEntitlement(t *testing.T) {
+	psm := mps.NewPrivateStateMetadata("tenantA", "tenantA", "", mps.Resident, []string{"A"})
+	server, err := NewServer(newPartiesPTM(), &stubPSMR{psm: psm}, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {PSIs: []string{"tenantA"}}},
+	})
+	require.NoError(t, err)
+
+	_, err = server.authorize(enode.ID{2}, "tenantA", "")
+	assert.Equal(t, ErrNotEntitled, err)
+
+	_, err = server.authorize(enode.ID{1}, "tenantB", "")
+	assert.Equal(t, ErrNotAuthorized, err)
+
+	clientRW, serverRW := p2p.MsgPipe()
+	defer clientRW.Close()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- MakeProtocol(server).Run(p2p.NewPeer(enode.ID{2}, "client", nil), serverRW)
+	}()
+	go MakeProtocol(NewClient("tenantA", "", enode.SignNull(new(enr.Record), enode.ID{9}))).Run(p2p.NewPeer(enode.ID{9}, "server", nil), clientRW)
+
+	select {
+	case err := <-errc:
+		assert.Equal(t, ErrNotEntitled, err)
+	case <-time.After(time.Second):
+		t.Fatal("the client without entitlement was served")
+	}
+}
+
+// rejectingAuthManager fails the authentication of every token
+type rejectingAuthManager struct{}
+
+func (rejectingAuthManager) Authenticate(ctx context.Context, token string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	return nil, errors.New("invalid token")
+}
+
+func (rejectingAuthManager) IsEnabled(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+func TestServer_authorize_resolvesAuthManagerOnFirstClient(t *testing.T) {
+	psm := mps.NewPrivateStateMetadata("tenantA", "tenantA", "", mps.Resident, []string{"A"})
+	var (
+		registered security.AuthenticationManager
+		resolved   int
+	)
+	server, err := NewServer(newPartiesPTM(), &stubPSMR{psm: psm}, func() (security.AuthenticationManager, error) {
+		resolved++
+		return registered, nil
+	}, &ServerConfig{})
+	require.NoError(t, err)
+	// the security plugin is registered after the server is created
+	registered = rejectingAuthManager{}
+
+	for i := 0; i < 2; i++ {
+		_, err = server.authorize(enode.ID{1}, "tenantA", "token")
+		assert.EqualError(t, err, "invalid token")
+	}
+	assert.Equal(t, 1, resolved)
+}
+
+func TestServer_privateData_restrictedToTMKeys(t *testing.T) {
+	ptm := newPartiesPTM()
+	entitled, other := ptm.send(t, "entitled", "A", "B"), ptm.send(t, "other", "A", "C")
+	psm := mps.NewPrivateStateMetadata("private", "private", "", mps.Resident, []string{"A", "B", "C"})
+	server, err := NewServer(ptm, &stubPSMR{psm: psm}, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {TMKeys: []string{"B"}}},
+	})
+	require.NoError(t, err)
+	sess, err := server.authorize(enode.ID{1}, "", "")
+	require.NoError(t, err)
+
+	data, err := server.privateData(sess, []common.EncryptedPayloadHash{entitled, other})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte("entitled"), data[0].Payload)
+	assert.Empty(t, data[1].Payload, "no TM key of the client is a party")
+}
+
+func TestServer_privateData_onlyManagedPartiesOfClient(t *testing.T) {
+	ptm := newPartiesPTM()
+	hash := ptm.send(t, "data", "A", "B")
+	psm := mps.NewPrivateStateMetadata("tenantA", "tenantA", "", mps.Resident, []string{"A"})
+	server, err := NewServer(ptm, &stubPSMR{psm: psm}, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {PSIs: []string{"tenantA"}}},
+	})
+	require.NoError(t, err)
+	sess, err := server.authorize(enode.ID{1}, "tenantA", "")
+	require.NoError(t, err)
+
+	data, err := server.privateData(sess, []common.EncryptedPayloadHash{hash})
+
+	require.NoError(t, err)
+	require.NotNil(t, data[0].Extra)
+	assert.Equal(t, []string{"A"}, data[0].Extra.ManagedParties, "B is managed for another private state")
+}
+
+func TestServer_privateData_rateLimited(t *testing.T) {
+	ptm := newPartiesPTM()
+	first, second := ptm.send(t, "first", "A"), ptm.send(t, "second", "A")
+	psm := mps.NewPrivateStateMetadata("private", "private", "", mps.Resident, []string{"A"})
+	server, err := NewServer(ptm, &stubPSMR{psm: psm}, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {}},
+		RateLimit:    0.01,
+	})
+	require.NoError(t, err)
+	sess, err := server.authorize(enode.ID{1}, "", "")
+	require.NoError(t, err)
+
+	_, err = server.privateData(sess, []common.EncryptedPayloadHash{first})
+	require.NoError(t, err)
+
+	_, err = server.privateData(sess, []common.EncryptedPayloadHash{second})
+	assert.Error(t, err, "the next payload is only allowed after the client gave up")
+}
+
+func TestNewServer_whenInvalidEntitlementNodeID(t *testing.T) {
+	_, err := NewServer(newPartiesPTM(), &stubPSMR{}, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{"node": {}},
+	})
+
+	assert.Error(t, err)
+}
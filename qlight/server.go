@@ -0,0 +1,294 @@
+package qlight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/clientserver"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/plugin/security"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+var (
+	servedPayloadsMeter   = metrics.NewRegisteredMeter("qlight/server/payloads", nil)
+	throttledRequestMeter = metrics.NewRegisteredMeter("qlight/server/throttled", nil)
+)
+
+// ServerConfig configures a qlight server
+type ServerConfig struct {
+	// Entitlements restrict the private data served to the clients, by the hex node ID
+	// of the client. The node ID is authenticated by the p2p handshake. Unless
+	// multitenancy is enabled, only the clients with an entitlement are served.
+	Entitlements map[string]*Entitlement `toml:",omitempty"`
+	// RateLimit is the number of payloads per second served to a client, 0 for no limit
+	RateLimit float64 `toml:",omitempty"`
+	// RateBurst is the number of payloads served at once to a client, the rate limit
+	// rounded up if 0
+	RateBurst int `toml:",omitempty"`
+}
+
+// Entitlement is the private data a client is entitled to
+type Entitlement struct {
+	// PSIs are the private states the client may use, only the default private
+	// state if empty
+	PSIs []string `json:"psis,omitempty" toml:",omitempty"`
+	// TMKeys restrict the payloads served to the ones with one of the keys as a
+	// managed party, no restriction if empty
+	TMKeys []string `json:"tmKeys,omitempty" toml:",omitempty"`
+}
+
+func (e *Entitlement) allowsPSI(psi string) bool {
+	if len(e.PSIs) == 0 {
+		return psi == types.DefaultPrivateStateIdentifier.String()
+	}
+	for _, allowed := range e.PSIs {
+		if allowed == psi {
+			return true
+		}
+	}
+	return false
+}
+
+// Server is the server side of the qlight protocol. It serves the clients with the
+// payloads of its private transaction manager, restricted to the ones the private
+// state and the entitlement of each client allow.
+type Server struct {
+	ptm          private.PrivateTransactionManager
+	psmr         mps.PrivateStateMetadataResolver
+	authManager  security.AuthenticationManagerDeferFunc // nil unless multitenancy is enabled
+	authOnce     sync.Once
+	auth         security.AuthenticationManager // resolved from authManager on the first authentication
+	authErr      error
+	entitlements map[enode.ID]*Entitlement
+	rateLimit    float64
+	rateBurst    int
+}
+
+// NewServer creates a server. When multitenancy is enabled, authManager resolves the
+// manager authenticating the tokens of the clients, which must be granted access to their
+// private state. It is resolved on the first authentication, once all the services have
+// registered their authentication managers and the plugins are started.
+func NewServer(ptm private.PrivateTransactionManager, psmr mps.PrivateStateMetadataResolver, authManager security.AuthenticationManagerDeferFunc, config *ServerConfig) (*Server, error) {
+	s := &Server{
+		ptm:          ptm,
+		psmr:         psmr,
+		authManager:  authManager,
+		entitlements: make(map[enode.ID]*Entitlement),
+	}
+	if config == nil {
+		config = new(ServerConfig)
+	}
+	for hexID, entitlement := range config.Entitlements {
+		id, err := enode.ParseID(hexID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node ID of qlight client entitlement %q: %v", hexID, err)
+		}
+		if entitlement == nil {
+			entitlement = new(Entitlement)
+		}
+		s.entitlements[id] = entitlement
+	}
+	if config.RateLimit < 0 || config.RateBurst < 0 {
+		return nil, errors.New("qlight server rate limit and burst must not be negative")
+	}
+	s.rateLimit, s.rateBurst = config.RateLimit, config.RateBurst
+	if authManager == nil && len(s.entitlements) == 0 {
+		log.Warn("The qlight server has no client entitlements, no client will be served")
+	}
+	return s, nil
+}
+
+// resolveAuthManager returns the authentication manager, resolved once, nil if the
+// tokens of the clients are not authenticated
+func (s *Server) resolveAuthManager() (security.AuthenticationManager, error) {
+	if s.authManager == nil {
+		return nil, nil
+	}
+	s.authOnce.Do(func() {
+		s.auth, s.authErr = s.authManager()
+	})
+	return s.auth, s.authErr
+}
+
+// newLimiter returns the limiter of the payloads served to a client, nil if there
+// is no limit
+func (s *Server) newLimiter() *rate.Limiter {
+	if s.rateLimit <= 0 {
+		return nil
+	}
+	burst := s.rateBurst
+	if burst <= 0 {
+		burst = int(math.Ceil(s.rateLimit))
+	}
+	return rate.NewLimiter(rate.Limit(s.rateLimit), burst)
+}
+
+func (s *Server) status() *statusData {
+	return &statusData{Version: ProtocolVersion}
+}
+
+// session is the private data a client is served
+type session struct {
+	psm     *mps.PrivateStateMetadata
+	tmKeys  map[string]bool // nil unless the payloads are restricted to managed parties
+	limiter *rate.Limiter   // nil unless rate limited
+}
+
+func (s *Server) handle(p *p2p.Peer, rw p2p.MsgReadWriter, peerStatus *statusData) error {
+	if !peerStatus.Client {
+		return clientserver.Idle(rw)
+	}
+	sess, err := s.authorize(p.ID(), peerStatus.PSI, peerStatus.Token)
+	if err != nil {
+		p.Log().Warn("Rejected qlight client", "psi", peerStatus.PSI, "err", err)
+		return err
+	}
+	p.Log().Info("Serving qlight client", "psi", sess.psm.ID)
+
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		if msg.Size > protocolMaxMsgSize || msg.Code != GetPrivateDataMsg {
+			msg.Discard()
+			return errUnexpectedMsg
+		}
+		var req getPrivateDataData
+		err = msg.Decode(&req)
+		msg.Discard()
+		if err != nil {
+			return err
+		}
+		if len(req.Hashes) > maxPayloadsPerRequest {
+			return fmt.Errorf("%w: %d payloads requested, at most %d", errUnexpectedMsg, len(req.Hashes), maxPayloadsPerRequest)
+		}
+		data, err := s.privateData(sess, req.Hashes)
+		if err != nil {
+			// left unanswered, the client retries rather than skipping a payload it
+			// may be entitled to
+			p.Log().Warn("Failed to retrieve private payloads for qlight client", "err", err)
+			continue
+		}
+		if err := p2p.Send(rw, PrivateDataMsg, &privateDataData{ReqID: req.ReqID, Data: data}); err != nil {
+			return err
+		}
+	}
+}
+
+// authorize resolves the private state of a client, after checking the client is
+// entitled to it and, when multitenancy is enabled, granted access to it
+func (s *Server) authorize(id enode.ID, psi string, token string) (*session, error) {
+	if psi == "" {
+		psi = types.DefaultPrivateStateIdentifier.String()
+	}
+	authManager, err := s.resolveAuthManager()
+	if err != nil {
+		return nil, err
+	}
+	entitlement := s.entitlements[id]
+	if entitlement == nil && authManager == nil {
+		return nil, ErrNotEntitled
+	}
+	if entitlement != nil && !entitlement.allowsPSI(psi) {
+		return nil, ErrNotAuthorized
+	}
+	ctx := rpc.WithPrivateStateIdentifier(context.Background(), types.PrivateStateIdentifier(psi))
+	if authManager != nil {
+		authToken, err := authManager.Authenticate(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		authorized, err := multitenancy.IsPSIAuthorized(authToken, types.PrivateStateIdentifier(psi))
+		if err != nil {
+			return nil, err
+		}
+		if !authorized {
+			return nil, ErrNotAuthorized
+		}
+	}
+	psm, err := s.psmr.ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sess := &session{psm: psm, limiter: s.newLimiter()}
+	if entitlement != nil && len(entitlement.TMKeys) > 0 {
+		sess.tmKeys = make(map[string]bool, len(entitlement.TMKeys))
+		for _, key := range entitlement.TMKeys {
+			sess.tmKeys[key] = true
+		}
+	}
+	return sess, nil
+}
+
+// privateData returns the payloads of the private transactions, empty for the ones
+// the client is not entitled to. The request fails if it can't be served within
+// the rate limit of the client before the client gives up.
+func (s *Server) privateData(sess *session, hashes []common.EncryptedPayloadHash) ([]*PrivateData, error) {
+	if sess.limiter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		for range hashes {
+			if err := sess.limiter.Wait(ctx); err != nil {
+				throttledRequestMeter.Mark(1)
+				return nil, fmt.Errorf("rate limit exceeded: %v", err)
+			}
+		}
+	}
+	data := make([]*PrivateData, len(hashes))
+	for i, hash := range hashes {
+		sender, managedParties, payload, extra, err := s.ptm.Receive(hash)
+		if err != nil {
+			return nil, fmt.Errorf("payload %s: %v", hash.TerminalString(), err)
+		}
+		if payload == nil || s.psmr.NotIncludeAny(sess.psm, managedParties...) || !sess.hasTMKey(managedParties) {
+			data[i] = &PrivateData{Hash: hash}
+			continue
+		}
+		data[i] = newPrivateData(hash, sender, payload, extra)
+		if data[i].Extra != nil {
+			data[i].Extra.ManagedParties = s.managedParties(sess, data[i].Extra.ManagedParties)
+		}
+	}
+	servedPayloadsMeter.Mark(int64(len(hashes)))
+	return data, nil
+}
+
+// managedParties returns the managed parties which belong to the private state of
+// the client, the client doesn't learn the other parties managed by the server
+func (s *Server) managedParties(sess *session, parties []string) []string {
+	var own []string
+	for _, party := range parties {
+		if !s.psmr.NotIncludeAny(sess.psm, party) {
+			own = append(own, party)
+		}
+	}
+	return own
+}
+
+// hasTMKey reports whether one of the managed parties is a TM key of the entitlement
+// of the client
+func (sess *session) hasTMKey(managedParties []string) bool {
+	if sess.tmKeys == nil {
+		return true
+	}
+	for _, party := range managedParties {
+		if sess.tmKeys[party] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package quorumtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+)
+
+// tokenLifetime is how long the access tokens of the backend are valid
+const tokenLifetime = 24 * time.Hour
+
+var errUnknownToken = errors.New("unknown access token")
+
+// authenticationManager authenticates the access tokens issued by the backend, in
+// place of the security plugin of a multitenant node
+type authenticationManager struct {
+	mu     sync.RWMutex
+	tokens map[string]*proto.PreAuthenticatedAuthenticationToken
+}
+
+func newAuthenticationManager() *authenticationManager {
+	return &authenticationManager{tokens: make(map[string]*proto.PreAuthenticatedAuthenticationToken)}
+}
+
+// issue returns a new token granting all the RPC APIs and the private states of the
+// psis, with any of their accounts
+func (am *authenticationManager) issue(psis ...string) (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	expiredAt, err := ptypes.TimestampProto(time.Now().Add(tokenLifetime))
+	if err != nil {
+		return "", err
+	}
+	token := "Bearer " + hex.EncodeToString(raw[:])
+	authorities := []*proto.GrantedAuthority{{Service: "*", Method: "*"}}
+	for _, psi := range psis {
+		authorities = append(authorities, &proto.GrantedAuthority{
+			Service: "*",
+			Method:  "*",
+			Raw: fmt.Sprintf("%s://%s?%s=%s&%s=%s", multitenancy.SchemePSI, psi,
+				multitenancy.QuerySelfEOA, multitenancy.AnyEOAAddress, multitenancy.QueryNodeEOA, multitenancy.AnyEOAAddress),
+		})
+	}
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.tokens[token] = &proto.PreAuthenticatedAuthenticationToken{
+		RawToken:    []byte(token),
+		ExpiredAt:   expiredAt,
+		Authorities: authorities,
+	}
+	return token, nil
+}
+
+func (am *authenticationManager) Authenticate(_ context.Context, token string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	authToken, ok := am.tokens[token]
+	if !ok {
+		return nil, errUnknownToken
+	}
+	return authToken, nil
+}
+
+func (am *authenticationManager) IsEnabled(_ context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,321 @@
+// Package quorumtest provides an in-memory Quorum backend for the integration tests of
+// Go applications, without a network of nodes and transaction managers.
+//
+// The backend is a single clique node sealing a block as soon as a transaction is
+// pending, with a mock private transaction manager, and optionally multiple private
+// states, multitenancy and the v1 account permissions. The applications use it
+// through the ethclient and bind packages as they use a real node:
+//
+//	backend, err := quorumtest.New(&quorumtest.Config{
+//		PrivateStates: []quorumtest.PrivateState{{PSI: "tenantA"}, {PSI: "tenantB"}},
+//	})
+//	...
+//	defer backend.Close()
+//	client, err := backend.ClientFor("tenantA")
+//	opts := backend.Transactor(0)
+//	opts.PrivateFor = backend.PrivateTransactionManager().Keys("tenantB")
+//	address, tx, contract, err := DeployContract(opts, client)
+//
+// The private transaction manager and the permissions are global to the process,
+// as the ones of a node: only one backend must be running at a time.
+package quorumtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/node"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ChainID is the chain ID of the backend
+const ChainID = 1337
+
+// Balance is the balance of the prefunded accounts
+var Balance = new(big.Int).Mul(big.NewInt(1000000000), big.NewInt(1e18))
+
+var errNotMultitenant = errors.New("multitenancy is not enabled")
+
+// PrivateState is a private state of the node
+type PrivateState struct {
+	// PSI is the private state identifier
+	PSI string
+	// Keys are the keys of the private state in the private transaction manager, the
+	// base64 encoding of the PSI if empty
+	Keys []string
+}
+
+// Config configures a backend
+type Config struct {
+	// Accounts is the number of prefunded accounts, one if 0
+	Accounts int
+	// PrivateStates enables multiple private states, the default private state
+	// "private" of the key DefaultKey is added unless configured. The node has the
+	// single private state of the key DefaultKey if empty.
+	PrivateStates []PrivateState
+	// Multitenancy serves the RPC APIs to the clients authenticated by the tokens of
+	// Backend.Token. Multiple private states must be enabled.
+	Multitenancy bool
+	// Permissioned enables the v1 account permissions, the prefunded accounts are
+	// given full access and the other ones are read-only until given another access
+	// by Backend.SetAccess
+	Permissioned bool
+}
+
+// Backend is an in-memory Quorum node
+type Backend struct {
+	stack    *node.Node
+	eth      *eth.Ethereum
+	ptm      *PrivateTransactionManager
+	accounts []*ecdsa.PrivateKey
+	mps      bool
+
+	auth   *authenticationManager // nil unless multitenancy is enabled
+	server *rpc.Server            // nil unless multitenancy is enabled
+	http   *httptest.Server       // nil unless multitenancy is enabled
+
+	permissioned       bool
+	savedPTM           private.PrivateTransactionManager
+	restorePermissions func()
+}
+
+// New creates and starts a backend
+func New(config *Config) (*Backend, error) {
+	if config == nil {
+		config = new(Config)
+	}
+	mps := len(config.PrivateStates) > 0
+	if config.Multitenancy && !mps {
+		return nil, errors.New("multitenancy requires multiple private states")
+	}
+	states := config.PrivateStates
+	if !hasPrivateState(states, types.DefaultPrivateStateIdentifier.String()) {
+		states = append([]PrivateState{{PSI: types.DefaultPrivateStateIdentifier.String(), Keys: []string{DefaultKey}}}, states...)
+	}
+	ptm, err := newPrivateTransactionManager(states, mps)
+	if err != nil {
+		return nil, err
+	}
+
+	count := config.Accounts
+	if count <= 0 {
+		count = 1
+	}
+	b := &Backend{ptm: ptm, mps: mps, permissioned: config.Permissioned, savedPTM: private.P, restorePermissions: pcore.SaveState()}
+	for i := 0; i < count; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		b.accounts = append(b.accounts, key)
+	}
+	signer, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	genesis := core.DeveloperGenesisBlock(0, crypto.PubkeyToAddress(signer.PublicKey))
+	genesis.Config.IsQuorum = true
+	genesis.Config.IsMPS = mps
+	for _, key := range b.accounts {
+		genesis.Alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: Balance}
+	}
+
+	// the private transactions are processed from the genesis block on
+	private.InitialiseWith(ptm)
+	if config.Permissioned {
+		access := make(map[common.Address]pcore.AccessType, len(b.accounts))
+		for _, address := range b.Accounts() {
+			access[address] = pcore.FullAccess
+		}
+		enablePermissions(access)
+	} else {
+		disablePermissions()
+	}
+
+	if b.stack, err = node.New(&node.Config{EnableMultitenancy: config.Multitenancy}); err != nil {
+		b.restore()
+		return nil, err
+	}
+	ethCfg := eth.DefaultConfig
+	ethCfg.Genesis = genesis
+	ethCfg.NetworkId = ChainID
+	ethCfg.Miner.Etherbase = crypto.PubkeyToAddress(signer.PublicKey)
+	ethCfg.EnableMultitenancy = config.Multitenancy
+	if b.eth, err = eth.New(b.stack, &ethCfg); err != nil {
+		b.stack.Close()
+		b.restore()
+		return nil, err
+	}
+	if err := b.start(signer); err != nil {
+		b.Close()
+		return nil, err
+	}
+	if config.Multitenancy {
+		b.auth = newAuthenticationManager()
+		b.server = rpc.NewProtectedServer(b.auth, true)
+		for _, api := range b.eth.APIs() {
+			if err := b.server.RegisterName(api.Namespace, api.Service); err != nil {
+				b.Close()
+				return nil, err
+			}
+		}
+		b.http = httptest.NewServer(b.server)
+	}
+	return b, nil
+}
+
+func hasPrivateState(states []PrivateState, psi string) bool {
+	for _, state := range states {
+		if state.PSI == psi {
+			return true
+		}
+	}
+	return false
+}
+
+// start starts the node and seals the blocks with the key of the signer
+func (b *Backend) start(signer *ecdsa.PrivateKey) error {
+	if err := b.stack.Start(); err != nil {
+		return err
+	}
+	ks := b.stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	account, err := ks.ImportECDSA(signer, "")
+	if err != nil {
+		return err
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		return err
+	}
+	return b.eth.StartMining(1)
+}
+
+// restore puts back the private transaction manager and the permissions in place
+// before the backend
+func (b *Backend) restore() {
+	private.P = b.savedPTM
+	b.restorePermissions()
+}
+
+// Close stops the backend
+func (b *Backend) Close() error {
+	if b.http != nil {
+		b.http.Close()
+	}
+	if b.server != nil {
+		b.server.Stop()
+	}
+	err := b.stack.Close()
+	b.restore()
+	return err
+}
+
+// Ethereum returns the Ethereum service of the node
+func (b *Backend) Ethereum() *eth.Ethereum {
+	return b.eth
+}
+
+// PrivateTransactionManager returns the mock private transaction manager of the node
+func (b *Backend) PrivateTransactionManager() *PrivateTransactionManager {
+	return b.ptm
+}
+
+// Accounts returns the addresses of the prefunded accounts
+func (b *Backend) Accounts() []common.Address {
+	addresses := make([]common.Address, len(b.accounts))
+	for i, key := range b.accounts {
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return addresses
+}
+
+// Key returns the private key of a prefunded account, by index
+func (b *Backend) Key(account int) *ecdsa.PrivateKey {
+	return b.accounts[account]
+}
+
+// Transactor returns the options of the transactions signed by a prefunded account,
+// by index. The transactions are private once PrivateFor is set.
+func (b *Backend) Transactor(account int) *bind.TransactOpts {
+	return bind.NewKeyedTransactor(b.accounts[account])
+}
+
+// SetAccess gives an access to an account, the permissions must be enabled
+func (b *Backend) SetAccess(account common.Address, access pcore.AccessType) error {
+	if !b.permissioned {
+		return errors.New("the permissions are not enabled")
+	}
+	setAccess(account, access)
+	return nil
+}
+
+// Client returns a client of the default private state
+func (b *Backend) Client() (*ethclient.Client, error) {
+	return b.ClientFor(types.DefaultPrivateStateIdentifier.String())
+}
+
+// ClientFor returns a client of a private state. With multitenancy, the client is
+// authenticated by a token granting the private state only. The private
+// transactions are sent from the first key of the private state unless PrivateFrom
+// is set.
+func (b *Backend) ClientFor(psi string) (*ethclient.Client, error) {
+	keys := b.ptm.Keys(psi)
+	if keys == nil {
+		return nil, fmt.Errorf("no private state %q", psi)
+	}
+	var (
+		c   *rpc.Client
+		err error
+	)
+	switch {
+	case b.auth != nil:
+		token, terr := b.Token(psi)
+		if terr != nil {
+			return nil, terr
+		}
+		c, err = b.DialWithToken(token)
+	case b.mps:
+		c, err = b.stack.AttachWithPSI(types.PrivateStateIdentifier(psi))
+	default:
+		c, err = b.stack.Attach()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClientWithPTM(c, &clientPTM{ptm: b.ptm, from: keys[0]}), nil
+}
+
+// Token returns a new access token granting all the RPC APIs and the private states
+// of the psis, multitenancy must be enabled
+func (b *Backend) Token(psis ...string) (string, error) {
+	if b.auth == nil {
+		return "", errNotMultitenant
+	}
+	return b.auth.issue(psis...)
+}
+
+// DialWithToken returns an RPC client authenticated by a token, multitenancy must
+// be enabled
+func (b *Backend) DialWithToken(token string) (*rpc.Client, error) {
+	if b.http == nil {
+		return nil, errNotMultitenant
+	}
+	c, err := rpc.DialHTTP(b.http.URL)
+	if err != nil {
+		return nil, err
+	}
+	return c.WithHTTPCredentials(func(context.Context) (string, error) { return token, nil }), nil
+}
@@ -0,0 +1,124 @@
+package quorumtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// returnsFortyTwo is the init code of a contract whose runtime code returns 42
+var returnsFortyTwo = common.FromHex("600a600c600039600a6000f3602a60005260206000f3")
+
+func deploy(t *testing.T, opts *bind.TransactOpts, client *ethclient.Client) (common.Address, *types.Receipt) {
+	address, tx, _, err := bind.DeployContract(opts, abi.ABI{}, returnsFortyTwo, client)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	require.NoError(t, err)
+	return address, receipt
+}
+
+func TestBackend_privateContract_isolatedByPrivateState(t *testing.T) {
+	backend, err := New(&Config{
+		PrivateStates: []PrivateState{{PSI: "tenantA"}, {PSI: "tenantB"}, {PSI: "tenantC"}},
+		Multitenancy:  true,
+	})
+	require.NoError(t, err)
+	defer backend.Close()
+	clientA, err := backend.ClientFor("tenantA")
+	require.NoError(t, err)
+	clientB, err := backend.ClientFor("tenantB")
+	require.NoError(t, err)
+	clientC, err := backend.ClientFor("tenantC")
+	require.NoError(t, err)
+
+	opts := backend.Transactor(0)
+	opts.PrivateFor = backend.PrivateTransactionManager().Keys("tenantB")
+	address, receipt := deploy(t, opts, clientA)
+
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	for psi, client := range map[string]*ethclient.Client{"tenantA": clientA, "tenantB": clientB} {
+		code, err := client.CodeAt(context.Background(), address, nil)
+		require.NoError(t, err)
+		assert.Len(t, code, 10, "private state %s", psi)
+	}
+	code, err := clientC.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	assert.Empty(t, code, "tenantC is not a party")
+}
+
+func TestBackend_tokenRestrictedToPrivateState(t *testing.T) {
+	backend, err := New(&Config{
+		PrivateStates: []PrivateState{{PSI: "tenantA"}, {PSI: "tenantB"}},
+		Multitenancy:  true,
+	})
+	require.NoError(t, err)
+	defer backend.Close()
+	token, err := backend.Token("tenantA")
+	require.NoError(t, err)
+	c, err := backend.DialWithToken(token)
+	require.NoError(t, err)
+
+	var blockNumber string
+	assert.NoError(t, c.WithPSI(types.PrivateStateIdentifier("tenantA")).CallContext(context.Background(), &blockNumber, "eth_blockNumber"))
+	assert.Error(t, c.WithPSI(types.PrivateStateIdentifier("tenantB")).CallContext(context.Background(), &blockNumber, "eth_blockNumber"))
+}
+
+func TestBackend_permissions(t *testing.T) {
+	enabled := pcore.PermissionsEnabled()
+	backend, err := New(&Config{Accounts: 2, Permissioned: true})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, backend.Close())
+		assert.Equal(t, enabled, pcore.PermissionsEnabled(), "the permissions are not restored")
+	}()
+	client, err := backend.Client()
+	require.NoError(t, err)
+	require.NoError(t, backend.SetAccess(backend.Accounts()[1], pcore.Transact))
+
+	_, receipt := deploy(t, backend.Transactor(0), client)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	_, _, _, err = bind.DeployContract(backend.Transactor(1), abi.ABI{}, returnsFortyTwo, client)
+	assert.Error(t, err, "the account is not allowed to deploy contracts")
+}
+
+func TestPrivateTransactionManager_Receive_restrictedToParties(t *testing.T) {
+	ptm, err := newPrivateTransactionManager([]PrivateState{
+		{PSI: "private", Keys: []string{DefaultKey}},
+		{PSI: "tenantA", Keys: []string{"A1", "A2"}},
+	}, true)
+	require.NoError(t, err)
+	extra := &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStandardPrivate}
+
+	_, _, hash, err := ptm.Send([]byte("data"), "A2", []string{"remote"}, extra)
+	require.NoError(t, err)
+	sender, managedParties, data, _, err := ptm.Receive(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "A2", sender)
+	assert.Equal(t, []string{"A2"}, managedParties)
+	assert.Equal(t, []byte("data"), data)
+
+	raw, err := ptm.StoreRaw([]byte("raw"), "")
+	require.NoError(t, err)
+	_, _, _, err = ptm.SendSignedTx(raw, []string{"A1"}, extra)
+	require.NoError(t, err)
+	sender, managedParties, _, _, err = ptm.Receive(raw)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultKey, sender)
+	assert.Equal(t, []string{DefaultKey, "A1"}, managedParties)
+
+	_, _, _, err = ptm.Send([]byte("data"), "remote", []string{"A1"}, extra)
+	assert.Error(t, err, "the sender must be a key of the node")
+}
@@ -0,0 +1,81 @@
+package quorumtest
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	v1 "github.com/ethereum/go-ethereum/permission/v1"
+)
+
+const (
+	// permissionsCacheSize is the size of the permission caches, all the records of
+	// the backend are kept
+	permissionsCacheSize = 1000
+
+	permissionsOrg     = "QUORUMTEST"
+	networkAdminRole   = "NWADMIN"
+	orgAdminRole       = "OADMIN"
+	permissionsRolePfx = "ACCESS"
+)
+
+// accessTypes are the access types with a role in the permissions of the backend
+var accessTypes = []pcore.AccessType{pcore.ReadOnly, pcore.Transact, pcore.ContractDeploy, pcore.FullAccess}
+
+// accessRole returns the role granting an access type
+func accessRole(access pcore.AccessType) string {
+	return fmt.Sprintf("%s%d", permissionsRolePfx, access)
+}
+
+// errNotCached is the error of the permission records missing from the caches, the
+// backend has no permissions contract to fetch them from
+func errNotCached(record interface{}) error {
+	return fmt.Errorf("permission record %v not found", record)
+}
+
+// enablePermissions sets up the permission caches as the v1 permissions model does
+// once the network has booted, with the org of the backend and the roles of the
+// access types. The accounts not given an access are read-only.
+//
+// The permissions are global to the process, as the ones of a node.
+func enablePermissions(accounts map[common.Address]pcore.AccessType) {
+	pcore.SetDefaults(networkAdminRole, orgAdminRole, false)
+
+	pcore.OrgInfoMap = pcore.NewOrgCache(permissionsCacheSize)
+	pcore.OrgInfoMap.PopulateCacheFunc(func(orgId string) (*pcore.OrgInfo, error) { return nil, errNotCached(orgId) })
+	pcore.RoleInfoMap = pcore.NewRoleCache(permissionsCacheSize)
+	pcore.RoleInfoMap.PopulateCacheFunc(func(key *pcore.RoleKey) (*pcore.RoleInfo, error) { return nil, errNotCached(*key) })
+	pcore.NodeInfoMap = pcore.NewNodeCache(permissionsCacheSize)
+	pcore.NodeInfoMap.PopulateCacheFunc(func(url string) (*pcore.NodeInfo, error) { return nil, errNotCached(url) })
+	pcore.NodeInfoMap.PopulateValidateFunc(func(string, string) bool { return false })
+	pcore.AcctInfoMap = pcore.NewAcctCache(permissionsCacheSize)
+	pcore.AcctInfoMap.PopulateCacheFunc(func(account common.Address) (*pcore.AccountInfo, error) {
+		return nil, errNotCached(account.Hex())
+	})
+
+	pcore.OrgInfoMap.UpsertOrg(permissionsOrg, "", permissionsOrg, big.NewInt(1), pcore.OrgApproved)
+	for _, access := range accessTypes {
+		pcore.RoleInfoMap.UpsertRole(permissionsOrg, accessRole(access), false, false, access, true)
+	}
+	for account, access := range accounts {
+		setAccess(account, access)
+	}
+	pcore.PermissionTransactionAllowedFunc = new(v1.Control).TransactionAllowed
+
+	pcore.SetQIP714BlockReached()
+	pcore.SetNetworkBootUpCompleted()
+}
+
+// disablePermissions allows all the transactions, in case the permissions were
+// enabled by a previous backend
+func disablePermissions() {
+	pcore.PermissionTransactionAllowedFunc = func(common.Address, common.Address, *big.Int, *big.Int, *big.Int, []byte, pcore.TransactionType) error {
+		return nil
+	}
+}
+
+// setAccess grants an access type to an account
+func setAccess(account common.Address, access pcore.AccessType) {
+	pcore.AcctInfoMap.UpsertAccount(permissionsOrg, accessRole(access), account, false, pcore.AcctActive)
+}
@@ -0,0 +1,243 @@
+package quorumtest
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultKey is the key of the default private state, unless the keys of the private
+// state "private" are configured
+const DefaultKey = "BULeR8JyUWhiuuCMU/HLA0Q5pzkYT+cHII3ZKBey3Bo="
+
+type payload struct {
+	data  []byte
+	from  string
+	to    []string
+	extra engine.ExtraMetadata
+}
+
+// PrivateTransactionManager is the mock private transaction manager of the backend.
+//
+// Unlike the in-memory manager of geth quorum-dev, the payloads are restricted to
+// their parties: the node receives the payloads one of its keys is a party to, and
+// each private state the ones one of its own keys is a party to. The keys which are
+// not keys of the node stand for the parties of other nodes. The payloads are
+// neither encrypted nor persisted.
+type PrivateTransactionManager struct {
+	mu       sync.RWMutex
+	nonce    uint64
+	payloads map[common.EncryptedPayloadHash]*payload
+
+	keys   map[string]bool     // keys of the node
+	states map[string][]string // keys of the private states, by PSI
+	groups []engine.PrivacyGroup
+	mps    bool
+}
+
+// newPrivateTransactionManager creates the manager of the private states, multiple
+// private states are supported if mps is set
+func newPrivateTransactionManager(states []PrivateState, mps bool) (*PrivateTransactionManager, error) {
+	ptm := &PrivateTransactionManager{
+		payloads: make(map[common.EncryptedPayloadHash]*payload),
+		keys:     make(map[string]bool),
+		states:   make(map[string][]string),
+		mps:      mps,
+	}
+	for _, state := range states {
+		if _, ok := ptm.states[state.PSI]; ok {
+			return nil, fmt.Errorf("duplicate private state %q", state.PSI)
+		}
+		keys := state.Keys
+		if len(keys) == 0 {
+			keys = []string{base64.StdEncoding.EncodeToString([]byte(state.PSI))}
+		}
+		for _, key := range keys {
+			if ptm.keys[key] {
+				return nil, fmt.Errorf("key %s of private state %q is the key of another private state", key, state.PSI)
+			}
+			ptm.keys[key] = true
+		}
+		ptm.states[state.PSI] = keys
+		ptm.groups = append(ptm.groups, engine.PrivacyGroup{
+			Type:           engine.PrivacyGroupResident,
+			Name:           state.PSI,
+			PrivacyGroupId: base64.StdEncoding.EncodeToString([]byte(state.PSI)),
+			Description:    "Resident group " + state.PSI,
+			Members:        keys,
+		})
+	}
+	return ptm, nil
+}
+
+// Keys returns the keys of a private state, nil if the node has no such private state
+func (ptm *PrivateTransactionManager) Keys(psi string) []string {
+	return ptm.states[psi]
+}
+
+// store keeps a payload and returns its hash, unique even for the same data
+func (ptm *PrivateTransactionManager) store(p *payload) common.EncryptedPayloadHash {
+	ptm.mu.Lock()
+	defer ptm.mu.Unlock()
+	ptm.nonce++
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], ptm.nonce)
+	hash := common.EncryptedPayloadHash(sha3.Sum512(append(nonce[:], p.data...)))
+	ptm.payloads[hash] = p
+	return hash
+}
+
+func (ptm *PrivateTransactionManager) get(hash common.EncryptedPayloadHash) *payload {
+	ptm.mu.RLock()
+	defer ptm.mu.RUnlock()
+	return ptm.payloads[hash]
+}
+
+// sender resolves the sender of a payload, the first key of the default private
+// state if empty, which must be a key of the node
+func (ptm *PrivateTransactionManager) sender(from string) (string, error) {
+	if from == "" {
+		return ptm.states[types.DefaultPrivateStateIdentifier.String()][0], nil
+	}
+	if !ptm.keys[from] {
+		return "", fmt.Errorf("the sender %s is not a key of the node", from)
+	}
+	return from, nil
+}
+
+// managedParties returns the keys of the node which are parties of a payload
+func (ptm *PrivateTransactionManager) managedParties(p *payload) []string {
+	var managed []string
+	for _, party := range append([]string{p.from}, p.to...) {
+		if ptm.keys[party] && !containsKey(managed, party) {
+			managed = append(managed, party)
+		}
+	}
+	return managed
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (ptm *PrivateTransactionManager) Name() string {
+	return "QuorumTest"
+}
+
+func (ptm *PrivateTransactionManager) HasFeature(f engine.PrivateTransactionManagerFeature) bool {
+	return f == engine.MultiplePrivateStates && ptm.mps
+}
+
+func (ptm *PrivateTransactionManager) Send(data []byte, from string, to []string, extra *engine.ExtraMetadata) (string, []string, common.EncryptedPayloadHash, error) {
+	if extra.PrivacyFlag.IsNotStandardPrivate() {
+		return "", nil, common.EncryptedPayloadHash{}, engine.ErrPrivateTxManagerDoesNotSupportPrivacyEnhancements
+	}
+	from, err := ptm.sender(from)
+	if err != nil {
+		return "", nil, common.EncryptedPayloadHash{}, err
+	}
+	p := &payload{data: data, from: from, to: to, extra: *extra}
+	p.extra.Sender = from
+	return from, ptm.managedParties(p), ptm.store(p), nil
+}
+
+func (ptm *PrivateTransactionManager) StoreRaw(data []byte, from string) (common.EncryptedPayloadHash, error) {
+	from, err := ptm.sender(from)
+	if err != nil {
+		return common.EncryptedPayloadHash{}, err
+	}
+	return ptm.store(&payload{data: data, from: from}), nil
+}
+
+func (ptm *PrivateTransactionManager) SendSignedTx(data common.EncryptedPayloadHash, to []string, extra *engine.ExtraMetadata) (string, []string, []byte, error) {
+	if extra.PrivacyFlag.IsNotStandardPrivate() {
+		return "", nil, nil, engine.ErrPrivateTxManagerDoesNotSupportPrivacyEnhancements
+	}
+	ptm.mu.Lock()
+	p, ok := ptm.payloads[data]
+	if ok {
+		p.to, p.extra = to, *extra
+		p.extra.Sender = p.from
+	}
+	ptm.mu.Unlock()
+	if !ok {
+		return "", nil, nil, fmt.Errorf("no raw payload %s", data.TerminalString())
+	}
+	return p.from, ptm.managedParties(p), data.Bytes(), nil
+}
+
+func (ptm *PrivateTransactionManager) Receive(hash common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+	p := ptm.get(hash)
+	if p == nil {
+		return "", nil, nil, nil, nil
+	}
+	managed := ptm.managedParties(p)
+	if len(managed) == 0 {
+		return "", nil, nil, nil, nil
+	}
+	extra := p.extra
+	extra.ManagedParties = managed
+	return p.from, managed, p.data, &extra, nil
+}
+
+func (ptm *PrivateTransactionManager) ReceiveRaw(hash common.EncryptedPayloadHash) ([]byte, string, *engine.ExtraMetadata, error) {
+	p := ptm.get(hash)
+	if p == nil {
+		return nil, "", nil, nil
+	}
+	extra := p.extra
+	return p.data, p.from, &extra, nil
+}
+
+func (ptm *PrivateTransactionManager) IsSender(txHash common.EncryptedPayloadHash) (bool, error) {
+	p := ptm.get(txHash)
+	return p != nil && ptm.keys[p.from], nil
+}
+
+func (ptm *PrivateTransactionManager) GetParticipants(txHash common.EncryptedPayloadHash) ([]string, error) {
+	p := ptm.get(txHash)
+	if p == nil {
+		return nil, nil
+	}
+	return append([]string{p.from}, p.to...), nil
+}
+
+func (ptm *PrivateTransactionManager) EncryptPayload(data []byte, from string, to []string, extra *engine.ExtraMetadata) ([]byte, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *PrivateTransactionManager) DecryptPayload(payload common.DecryptRequest) ([]byte, *engine.ExtraMetadata, error) {
+	return nil, nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *PrivateTransactionManager) Groups() ([]engine.PrivacyGroup, error) {
+	if !ptm.mps {
+		return nil, engine.ErrPrivateTxManagerNotSupported
+	}
+	return ptm.groups, nil
+}
+
+// clientPTM is the private transaction manager of a client, sending from the keys of
+// its private state by default
+type clientPTM struct {
+	ptm  *PrivateTransactionManager
+	from string
+}
+
+func (c *clientPTM) StoreRaw(data []byte, from string) (common.EncryptedPayloadHash, error) {
+	if from == "" {
+		from = c.from
+	}
+	return c.ptm.StoreRaw(data, from)
+}
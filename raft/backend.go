@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/chaos"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
@@ -16,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -35,6 +38,7 @@ type RaftService struct {
 	minter           *minter
 	nodeKey          *ecdsa.PrivateKey
 	calcGasLimitFunc func(block *types.Block) uint64
+	orgQuotas        *pcore.OrgQuotaConfig // Quorum: shares of the blocks each organization may use
 
 	pendingLogsFeed *event.Feed
 }
@@ -50,6 +54,7 @@ func New(stack *node.Node, chainConfig *params.ChainConfig, raftId, raftPort uin
 		startPeers:       startPeers,
 		nodeKey:          stack.GetNodeKey(),
 		calcGasLimitFunc: e.CalcGasLimit,
+		orgQuotas:        e.OrgQuotas(),
 		pendingLogsFeed:  e.ConsensusServicePendingLogsFeed(),
 	}
 
@@ -60,12 +65,30 @@ func New(stack *node.Node, chainConfig *params.ChainConfig, raftId, raftPort uin
 		return nil, err
 	}
 
+	// Quorum: hand the leadership off when the node enters maintenance
+	stack.RegisterMaintenanceHandler(service.raftProtocolManager)
+	// Quorum: report the raft role in quorum_nodeInfo
+	e.SetNodeRoleNotifier(service.raftProtocolManager)
+	// Quorum: include the raft state in the backups of the node
+	e.AddBackupSource(service.raftProtocolManager)
+
+	// Quorum: the block production paused by admin_pauseMinting is the minter
+	e.SetMintingController(service)
+	// Quorum: the block maker crashed and restarted by the chaos API is the minter
+	chaos.RegisterBlockMaker(chaos.BlockMakerFuncs{Stop: service.haltMinter, Start: service.resumeMinter})
+
 	stack.RegisterAPIs(service.apis())
 	stack.RegisterLifecycle(service)
 
 	return service, nil
 }
 
+// SetCatchUpConfig configures the catch-up of the peers joining the cluster, it must be
+// called before the service is started
+func (service *RaftService) SetCatchUpConfig(config *CatchUpConfig) {
+	service.raftProtocolManager.followerSync = config.FollowerSync
+}
+
 // Utility methods
 
 func (service *RaftService) apis() []rpc.API {
@@ -76,6 +99,50 @@ func (service *RaftService) apis() []rpc.API {
 			Service:   NewPublicRaftAPI(service),
 			Public:    true,
 		},
+		{
+			Namespace: "quorum",
+			Version:   "1.0",
+			Service:   consensus.NewPublicNodeRoleAPI(service.raftProtocolManager),
+			Public:    true,
+		},
+	}
+}
+
+// haltMinter stops minting, even if this node is or becomes the raft leader.
+func (service *RaftService) haltMinter() error {
+	service.minter.halt()
+	return nil
+}
+
+// resumeMinter restarts minting after haltMinter, if this node is the raft leader.
+func (service *RaftService) resumeMinter() error {
+	service.minter.resume()
+	service.startMinterIfLeader()
+	return nil
+}
+
+// PauseMinting stops minting, even if this node is or becomes the raft leader.
+func (service *RaftService) PauseMinting() error {
+	service.minter.pause()
+	return nil
+}
+
+// ResumeMinting restarts minting after PauseMinting, if this node is the raft leader.
+func (service *RaftService) ResumeMinting() error {
+	service.minter.unpause()
+	service.startMinterIfLeader()
+	return nil
+}
+
+// startMinterIfLeader restarts minting if this node is the raft leader, the minter
+// staying stopped while it is halted or paused.
+func (service *RaftService) startMinterIfLeader() {
+	pm := service.raftProtocolManager
+	pm.mu.RLock()
+	role := pm.role
+	pm.mu.RUnlock()
+	if role == minterRole {
+		service.minter.start()
 	}
 }
 
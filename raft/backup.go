@@ -0,0 +1,86 @@
+package raft
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// BackupDir is the directory of the raft state in the backups of the node
+const BackupDir = "raft"
+
+// PauseWrites blocks the persistence of the raft state until the returned function is called
+func (pm *ProtocolManager) PauseWrites() (resume func()) {
+	pm.backupMu.Lock()
+	return pm.backupMu.Unlock
+}
+
+// Backup copies the write-ahead log, the snapshots and the last-applied index into the
+// raft directory of dir. The writes must be paused.
+func (pm *ProtocolManager) Backup(dir string) error {
+	dir = filepath.Join(dir, BackupDir)
+	if err := copyDir(pm.waldir, filepath.Join(dir, filepath.Base(pm.waldir))); err != nil {
+		return err
+	}
+	if err := copyDir(pm.snapdir, filepath.Join(dir, filepath.Base(pm.snapdir))); err != nil {
+		return err
+	}
+	db, err := leveldb.OpenFile(filepath.Join(dir, "quorum-raft-state"), nil)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	it := pm.quorumRaftDb.NewIterator(nil, nil)
+	defer it.Release()
+	batch := new(leveldb.Batch)
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := db.Write(batch, nil); err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// copyDir copies the regular files of src into dst
+func copyDir(src, dst string) error {
+	files, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, file.Name()), filepath.Join(dst, file.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,25 @@
+package raft
+
+import "sort"
+
+// CatchUpConfig configures the catch-up of the peers joining the cluster, so that the
+// leader isn't saturated when several peers join at once. The chain served to the peers
+// catching up is throttled by the eth service.
+type CatchUpConfig struct {
+	// FollowerSync has the peers catching up fetch the chain from the followers, from
+	// the leader only when no follower serves it
+	FollowerSync bool
+}
+
+// syncPeers returns the raft IDs of the peers the chain is synced from, in order
+func (pm *ProtocolManager) syncPeers(peers map[uint16]*Peer, leader uint16) []uint16 {
+	ids := make([]uint16, 0, len(peers))
+	for raftId := range peers {
+		ids = append(ids, raftId)
+	}
+	if !pm.followerSync {
+		return ids
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return ids[i] != leader && ids[j] == leader })
+	return ids
+}
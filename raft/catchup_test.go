@@ -0,0 +1,17 @@
+package raft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPeers_followersFirst(t *testing.T) {
+	peers := map[uint16]*Peer{1: nil, 2: nil, 3: nil}
+	pm := &ProtocolManager{followerSync: true}
+
+	ids := pm.syncPeers(peers, 1)
+
+	assert.Len(t, ids, 3)
+	assert.Equal(t, uint16(1), ids[2])
+}
@@ -23,6 +23,8 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/syndtr/goleveldb/leveldb"
 
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/chaos"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
@@ -69,6 +71,11 @@ type ProtocolManager struct {
 	eventMux      *event.TypeMux
 	minedBlockSub *event.TypeMuxSubscription
 
+	// Role change events
+	roleFeed      event.Feed
+	roleMu        sync.Mutex
+	publishedRole string // last role sent on roleFeed, protected by roleMu
+
 	// Raft proposal events
 	blockProposalC      chan *types.Block      // for mined blocks to raft
 	confChangeProposalC chan raftpb.ConfChange // for config changes from js console to raft
@@ -91,6 +98,15 @@ type ProtocolManager struct {
 	// Storage
 	quorumRaftDb *leveldb.DB             // Persistent storage for last-applied raft index
 	raftStorage  *etcdRaft.MemoryStorage // Volatile raft storage
+
+	// Quorum
+	backupMu sync.RWMutex // held by the backups to pause the persistence of the raft state
+
+	// Quorum: catch-up of the joining peers
+	followerSync bool // the chain is synced from the followers first
+
+	// Quorum: 1 while the leadership is handed off for maintenance
+	maintenance int32
 }
 
 var errNoLeaderElected = errors.New("no leader is currently elected")
@@ -145,10 +161,15 @@ func (pm *ProtocolManager) Start() {
 	pm.startRaft()
 	// update raft peers info to p2p server
 	pm.p2pServer.SetCheckPeerInRaft(pm.peerExist)
+	pm.p2pServer.SetPeerInfoProvider("raft", pm.peerInfo)
 	go pm.minedBroadcastLoop()
 }
 
 func (pm *ProtocolManager) Stop() {
+	// removed before locking as the provider locks pm.mu while admin_peers is served
+	if server := pm.p2pServer; server != nil {
+		server.SetPeerInfoProvider("raft", nil)
+	}
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
@@ -191,14 +212,7 @@ func (pm *ProtocolManager) NodeInfo() *RaftNodeInfo {
 	pm.mu.RLock() // as we read role and peers
 	defer pm.mu.RUnlock()
 
-	roleDescription := ""
-	if pm.role == minterRole {
-		roleDescription = "minter"
-	} else if pm.isVerifierNode() {
-		roleDescription = "verifier"
-	} else if pm.isLearnerNode() {
-		roleDescription = "learner"
-	}
+	roleDescription := pm.roleDescription()
 
 	peerAddresses := make([]*Address, len(pm.peers))
 	peerIdx := 0
@@ -230,6 +244,43 @@ func (pm *ProtocolManager) NodeInfo() *RaftNodeInfo {
 	}
 }
 
+// NodeRole returns the current raft role of this node
+func (pm *ProtocolManager) NodeRole() consensus.NodeRoleEvent {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	return consensus.NodeRoleEvent{Consensus: "raft", Role: pm.roleDescription()}
+}
+
+// SubscribeNodeRoleEvent registers a subscription for changes of the raft role of this node
+func (pm *ProtocolManager) SubscribeNodeRoleEvent(ch chan<- consensus.NodeRoleEvent) event.Subscription {
+	return pm.roleFeed.Subscribe(ch)
+}
+
+// roleDescription must be called with pm.mu held
+func (pm *ProtocolManager) roleDescription() string {
+	if pm.role == minterRole {
+		return "minter"
+	} else if pm.isVerifierNode() {
+		return "verifier"
+	} else if pm.isLearnerNode() {
+		return "learner"
+	}
+	return ""
+}
+
+// publishRole notifies role subscribers if the role of this node has changed since the last notification
+func (pm *ProtocolManager) publishRole() {
+	role := pm.NodeRole()
+
+	pm.roleMu.Lock()
+	defer pm.roleMu.Unlock()
+	if role.Role != pm.publishedRole {
+		pm.publishedRole = role.Role
+		pm.roleFeed.Send(role)
+	}
+}
+
 // There seems to be a very rare race in raft where during `etcdRaft.StartNode`
 // it will call back our `Process` method before it's finished returning the
 // `raft.Node`, `pm.unsafeRawNode`, to us. This re-entrance through a separate
@@ -326,6 +377,36 @@ func (pm *ProtocolManager) peerExist(node *enode.Node) bool {
 	return false
 }
 
+// PeerInfo is the raft data about a peer shown in admin_peers
+type PeerInfo struct {
+	RaftId uint16 `json:"raftId"`
+	Role   string `json:"role"` // minter, verifier or learner
+}
+
+// peerInfo returns the raft ID and role of the peer, nil if it is not a member of the cluster
+func (pm *ProtocolManager) peerInfo(node *enode.Node) interface{} {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	for raftId, p := range pm.peers {
+		if node.ID() != p.p2pNode.ID() {
+			continue
+		}
+		info := &PeerInfo{RaftId: raftId, Role: "verifier"}
+		if raftId == pm.leader {
+			info.Role = "minter"
+		} else {
+			for _, learner := range pm.confState.Learners {
+				if uint16(learner) == raftId {
+					info.Role = "learner"
+				}
+			}
+		}
+		return info
+	}
+	return nil
+}
+
 func (pm *ProtocolManager) ProposeNewPeer(enodeURL string, isLearner bool) (uint16, error) {
 	if pm.isLearnerNode() {
 		return 0, errors.New("learner node can't add peer or learner")
@@ -415,6 +496,14 @@ func (pm *ProtocolManager) WriteMsg(msg p2p.Msg) error {
 //
 
 func (pm *ProtocolManager) Process(ctx context.Context, m raftpb.Message) error {
+	// messages from partitioned peers are lost in chaos builds
+	if chaos.Enabled {
+		if id, ok := pm.peerNodeID(m.From); ok {
+			if drop, _ := chaos.Intercept(id, chaos.Inbound); drop {
+				return nil
+			}
+		}
+	}
 	return pm.rawNode().Step(ctx, m)
 }
 
@@ -693,6 +782,16 @@ func (pm *ProtocolManager) handleRoleChange(roleC <-chan interface{}) {
 			pm.mu.Lock()
 			pm.role = intRole
 			pm.mu.Unlock()
+
+			pm.publishRole()
+			// Quorum: the node in maintenance hands the leadership off again
+			if intRole == minterRole && pm.inMaintenance() {
+				go func() {
+					if err := pm.transferLeadership(context.Background()); err != nil {
+						log.Warn("Failed to hand the raft leadership off for maintenance", "err", err)
+					}
+				}()
+			}
 		case <-pm.quitSync:
 			return
 		}
@@ -807,6 +906,44 @@ func (pm *ProtocolManager) addPeer(address *Address) {
 	pm.peers[raftId] = &Peer{address, p2pNode}
 }
 
+// peerNodeID returns the node ID of the raft peer.
+func (pm *ProtocolManager) peerNodeID(raftId uint64) (enode.ID, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	if peer, ok := pm.peers[uint16(raftId)]; ok && peer.p2pNode != nil {
+		return peer.p2pNode.ID(), true
+	}
+	return enode.ID{}, false
+}
+
+// interceptMessages injects the faults of chaos builds in the messages sent
+// to the raft peers: the dropped messages are removed and the delayed ones are
+// sent later on.
+func (pm *ProtocolManager) interceptMessages(msgs []raftpb.Message) []raftpb.Message {
+	if !chaos.Enabled {
+		return msgs
+	}
+	kept := msgs[:0]
+	for _, msg := range msgs {
+		id, ok := pm.peerNodeID(msg.To)
+		if !ok {
+			kept = append(kept, msg)
+			continue
+		}
+		drop, delay := chaos.Intercept(id, chaos.Outbound)
+		switch {
+		case drop:
+		case delay > 0:
+			delayed := []raftpb.Message{msg}
+			time.AfterFunc(delay, func() { pm.transport.Send(delayed) })
+		default:
+			kept = append(kept, msg)
+		}
+	}
+	return kept
+}
+
 func (pm *ProtocolManager) disconnectFromPeer(raftId uint16, peer *Peer) {
 	pm.p2pServer.RemovePeer(peer.p2pNode)
 	pm.transport.RemovePeer(raftTypes.ID(raftId))
@@ -846,6 +983,8 @@ func (pm *ProtocolManager) eventLoop() {
 			// when the node is first ready it gives us entries to commit and messages
 			// to immediately publish
 		case rd := <-pm.rawNode().Ready():
+			// Quorum: the backups pause the persistence of the raft state
+			pm.backupMu.RLock()
 			pm.wal.Save(rd.HardState, rd.Entries)
 
 			if rd.SoftState != nil {
@@ -863,7 +1002,7 @@ func (pm *ProtocolManager) eventLoop() {
 			pm.raftStorage.Append(rd.Entries)
 
 			// 2: Send all Messages to the nodes named in the To field.
-			pm.transport.Send(rd.Messages)
+			pm.transport.Send(pm.interceptMessages(rd.Messages))
 
 			// 3: Apply Snapshot (if any) and CommittedEntries to the state machine.
 			for _, entry := range pm.entriesToApply(rd.CommittedEntries) {
@@ -895,6 +1034,7 @@ func (pm *ProtocolManager) eventLoop() {
 						if !pm.applyNewChainHead(&block) {
 							// return false only if insert chain is interrupted
 							// stop eventloop
+							pm.backupMu.RUnlock()
 							return
 						}
 					}
@@ -924,6 +1064,9 @@ func (pm *ProtocolManager) eventLoop() {
 							//if raft id exists as peer, you are promoting learner to peer
 							if pm.isRaftIdUsed(raftId) {
 								log.Info("promote learner node to voter node", "raft id", raftId)
+								if raftId == pm.raftId {
+									pm.publishRole()
+								}
 							} else {
 								//if raft id does not exist, you are adding peer/learner
 								log.Info("add peer/learner -> "+confChangeTypeName, "raft id", raftId)
@@ -969,6 +1112,7 @@ func (pm *ProtocolManager) eventLoop() {
 			}
 
 			pm.maybeTriggerSnapshot()
+			pm.backupMu.RUnlock()
 
 			if exitAfterApplying {
 				log.Warn("permanently removing self from the cluster")
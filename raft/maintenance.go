@@ -0,0 +1,80 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	etcdRaft "github.com/coreos/etcd/raft"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+// The node in maintenance hands the raft leadership off to the most up to date peer,
+// and again whenever it is elected until it exits maintenance.
+
+// leadershipTransferPoll is the interval at which the transfer of the leadership is checked
+const leadershipTransferPoll = 100 * time.Millisecond
+
+var errNoTransferee = errors.New("no peer to hand the raft leadership off to")
+
+// EnterMaintenance hands the raft leadership off, waiting for the transfer
+func (pm *ProtocolManager) EnterMaintenance(ctx context.Context) error {
+	atomic.StoreInt32(&pm.maintenance, 1)
+	ticker := time.NewTicker(leadershipTransferPoll)
+	defer ticker.Stop()
+	for pm.isMinter() {
+		if err := pm.transferLeadership(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// ExitMaintenance lets the node keep the leadership again
+func (pm *ProtocolManager) ExitMaintenance() error {
+	atomic.StoreInt32(&pm.maintenance, 0)
+	return nil
+}
+
+func (pm *ProtocolManager) inMaintenance() bool {
+	return atomic.LoadInt32(&pm.maintenance) == 1
+}
+
+func (pm *ProtocolManager) isMinter() bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.role == minterRole
+}
+
+// transferLeadership asks raft to transfer the leadership to the voting peer with the
+// highest matched log index, if the node is the leader
+func (pm *ProtocolManager) transferLeadership(ctx context.Context) error {
+	status := pm.rawNode().Status()
+	if status.RaftState != etcdRaft.StateLeader {
+		return nil
+	}
+	var transferee, match uint64
+	for id, progress := range status.Progress {
+		if id == status.ID || !pm.isVerifier(uint16(id)) {
+			continue
+		}
+		if transferee == etcdRaft.None || progress.Match > match {
+			transferee, match = id, progress.Match
+		}
+	}
+	if transferee == etcdRaft.None {
+		return errNoTransferee
+	}
+	if status.LeadTransferee != transferee {
+		log.Info("Handing the raft leadership off for maintenance", "transferee", transferee)
+		pm.rawNode().TransferLeadership(ctx, status.ID, transferee)
+	}
+	return nil
+}
@@ -35,7 +35,9 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/tracing"
 	"github.com/ethereum/go-ethereum/trie"
 )
 
@@ -50,6 +52,7 @@ type work struct {
 	privateState *state.StateDB
 	Block        *types.Block
 	header       *types.Header
+	orgQuotas    *pcore.OrgQuotaTracker // nil unless the organizations have quotas
 }
 
 type minter struct {
@@ -61,6 +64,8 @@ type minter struct {
 	chainDb          ethdb.Database
 	coinbase         common.Address
 	minting          int32 // Atomic status counter
+	halted           int32 // Atomic flag, set while the minter is crashed by the chaos API
+	paused           int32 // Atomic flag, set while minting is paused by admin_pauseMinting
 	shouldMine       *channels.RingChannel
 	blockTime        time.Duration
 	speculativeChain *speculativeChain
@@ -105,6 +110,9 @@ func newMinter(config *params.ChainConfig, eth *RaftService, blockTime time.Dura
 }
 
 func (minter *minter) start() {
+	if atomic.LoadInt32(&minter.halted) == 1 || atomic.LoadInt32(&minter.paused) == 1 {
+		return
+	}
 	atomic.StoreInt32(&minter.minting, 1)
 	minter.requestMinting()
 }
@@ -117,6 +125,31 @@ func (minter *minter) stop() {
 	atomic.StoreInt32(&minter.minting, 0)
 }
 
+// halt stops minting, and keeps the minter stopped if this node becomes the
+// raft leader again, until resume is called.
+func (minter *minter) halt() {
+	atomic.StoreInt32(&minter.halted, 1)
+	minter.stop()
+}
+
+// resume allows minting again after halt, unless it is paused.
+func (minter *minter) resume() {
+	atomic.StoreInt32(&minter.halted, 0)
+}
+
+// pause stops minting, and keeps the minter stopped if this node becomes the
+// raft leader again, until unpause is called. It is independent of halt, so that
+// the chaos API doesn't resume a paused minter and conversely.
+func (minter *minter) pause() {
+	atomic.StoreInt32(&minter.paused, 1)
+	minter.stop()
+}
+
+// unpause allows minting again after pause, unless it is halted.
+func (minter *minter) unpause() {
+	atomic.StoreInt32(&minter.paused, 0)
+}
+
 // Notify the minting loop that minting should occur, if it's not already been
 // requested. Due to the use of a RingChannel, this function is idempotent if
 // called multiple times before the minting occurs.
@@ -282,6 +315,7 @@ func (minter *minter) createWork() *work {
 		publicState:  publicState,
 		privateState: defaultPrivateState,
 		header:       header,
+		orgQuotas:    pcore.NewOrgQuotaTracker(minter.eth.orgQuotas, header.GasLimit),
 	}
 }
 
@@ -314,11 +348,15 @@ func (minter *minter) mintNewBlock() {
 	minter.mu.Lock()
 	defer minter.mu.Unlock()
 
+	span := tracing.StartRoot("block.mint").SetAttribute("consensus", "raft")
+	defer span.End()
+
 	work := minter.createWork()
 	transactions := minter.getTransactions()
 
 	committedTxes, publicReceipts, _, logs := work.commitTransactions(transactions, minter.chain)
 	txCount := len(committedTxes)
+	span.SetAttribute("block.number", work.header.Number.Uint64()).SetAttribute("block.txs", txCount)
 
 	if txCount == 0 {
 		log.Info("Not minting a new block since there are no pending transactions")
@@ -350,6 +388,12 @@ func (minter *minter) mintNewBlock() {
 
 	block := types.NewBlock(header, committedTxes, nil, publicReceipts, new(trie.Trie))
 
+	if err := minter.chain.ValidateLocalBlock(block, publicReceipts); err != nil {
+		log.Warn("Block rejected by validation, not minting it", "block num", block.Number(), "num txes", txCount, "err", err)
+		span.SetError(err)
+		return
+	}
+
 	log.Info("Generated next block", "block num", block.Number(), "num txes", txCount)
 
 	deleteEmptyObjects := minter.chain.Config().IsEIP158(block.Number())
@@ -373,6 +417,7 @@ func (env *work) commitTransactions(txes *types.TransactionsByPriceAndNonce, bc
 
 	gp := new(core.GasPool).AddGas(env.header.GasLimit)
 	txCount := 0
+	signer := types.MakeSigner(env.config, env.header.Number)
 
 	for {
 		tx := txes.Peek()
@@ -380,6 +425,15 @@ func (env *work) commitTransactions(txes *types.TransactionsByPriceAndNonce, bc
 			break
 		}
 
+		// Quorum: leave the transactions of the account out once its org used its quota
+		from, _ := types.Sender(signer, tx)
+		org, allowed := env.orgQuotas.Allow(from, tx.Gas())
+		if !allowed {
+			log.Trace("Skipping account over the quota of its org", "sender", from, "org", org)
+			txes.Pop()
+			continue
+		}
+
 		env.publicState.Prepare(tx.Hash(), common.Hash{}, txCount)
 
 		publicReceipt, privateReceipt, err := env.commitTransaction(tx, bc, gp)
@@ -389,6 +443,7 @@ func (env *work) commitTransactions(txes *types.TransactionsByPriceAndNonce, bc
 			txes.Pop() // skip rest of txes from this account
 		default:
 			txCount++
+			env.orgQuotas.Record(org, publicReceipt.GasUsed) // Quorum
 			committedTxes = append(committedTxes, tx)
 
 			publicReceipts = append(publicReceipts, publicReceipt)
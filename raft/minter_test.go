@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/coreos/etcd/raft/raftpb"
 	mapset "github.com/deckarep/golang-set"
+	"github.com/eapache/channels"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -75,6 +77,24 @@ func TestSignHeader(t *testing.T) {
 
 }
 
+func TestStart_whenPausedAndResumedByChaos(t *testing.T) {
+	minter := &minter{shouldMine: channels.NewRingChannel(1), paused: 1, halted: 1}
+
+	minter.resume()
+	minter.start()
+
+	if atomic.LoadInt32(&minter.minting) != 0 {
+		t.Errorf("the paused minter was started after the chaos API resumed it")
+	}
+
+	minter.unpause()
+	minter.start()
+
+	if atomic.LoadInt32(&minter.minting) != 1 {
+		t.Errorf("the minter was not started after it was unpaused")
+	}
+}
+
 func TestAddLearner_whenTypical(t *testing.T) {
 
 	raftService := newTestRaftService(t, 1, []uint64{1}, []uint64{})
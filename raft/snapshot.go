@@ -353,10 +353,14 @@ func (pm *ProtocolManager) syncBlockchainUntil(hash common.Hash) {
 	for raftId, peer := range pm.peers {
 		peerMap[raftId] = peer
 	}
+	leader := pm.leader
 	pm.mu.RUnlock()
+	// Quorum: the followers serve the chain first, to spare the leader
+	peerIds := pm.syncPeers(peerMap, leader)
 
 	for {
-		for peerId, peer := range peerMap {
+		for _, peerId := range peerIds {
+			peer := peerMap[peerId]
 			log.Info("synchronizing with peer", "peer id", peerId, "hash", hash)
 
 			peerId := peer.p2pNode.ID().String()
@@ -0,0 +1,78 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package relay
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcDestination sends the relayed transactions to a node of the destination network
+// over JSON-RPC
+type rpcDestination struct {
+	client *rpc.Client
+}
+
+func dialDestination(rawurl string) (Destination, error) {
+	client, err := rpc.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcDestination{client: client}, nil
+}
+
+func (d *rpcDestination) Nonce(ctx context.Context, account common.Address) (uint64, error) {
+	return d.transactionCount(ctx, account, "latest")
+}
+
+func (d *rpcDestination) PendingNonce(ctx context.Context, account common.Address) (uint64, error) {
+	return d.transactionCount(ctx, account, "pending")
+}
+
+func (d *rpcDestination) transactionCount(ctx context.Context, account common.Address, block string) (uint64, error) {
+	var nonce hexutil.Uint64
+	if err := d.client.CallContext(ctx, &nonce, "eth_getTransactionCount", account, block); err != nil {
+		return 0, err
+	}
+	return uint64(nonce), nil
+}
+
+func (d *rpcDestination) SendTransaction(ctx context.Context, args *SendTxArgs) (common.Hash, error) {
+	var hash common.Hash
+	if err := d.client.CallContext(ctx, &hash, "eth_sendTransaction", args); err != nil {
+		if isNonceUsed(err) {
+			return common.Hash{}, errAlreadyRelayed
+		}
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
+
+func (d *rpcDestination) Close() {
+	d.client.Close()
+}
+
+// isNonceUsed reports whether the destination node rejected a transaction because
+// its nonce was used, by a transaction either mined or pending
+func isNonceUsed(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,568 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package relay forwards selected private transactions, or their events, from this
+// network to another Quorum network, e.g. between the consortia of a consortium of
+// consortia.
+//
+// A rule selects the private transactions sent to a contract of this network. Either
+// their input is sent to a target contract of the destination network, or the events
+// the transactions emitted are sent to the relayEvent(bytes32,uint256,bytes32[],bytes)
+// function of the target, with the hash of the source transaction and the index of the
+// event. The relayed transactions are private to the participants of the source
+// transaction, their TM keys mapped to the TM keys of the destination network.
+//
+// The relayed transactions are sent by an account dedicated to the relay, unlocked on
+// the destination node. Each one is sent with the next nonce of the account, which is
+// checkpointed in the database with the position of the relay in the chain. A
+// transaction sent again after a restart has a nonce already used by the destination
+// network, so it is never relayed twice. A transaction dropped by the destination before
+// it is mined would hold up the next ones: it is sent again, or its nonce is skipped with
+// an empty transaction of the sender to itself if it was sent before a restart.
+package relay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/catchup"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	chainHeadChanSize = 16
+
+	// sendTimeout is how long the destination node is waited for
+	sendTimeout = 10 * time.Second
+
+	// retryInterval is the delay before relaying again after a failure
+	retryInterval = 5 * time.Second
+)
+
+// checkpointKey -> RLP encoded checkpoint
+var checkpointKey = []byte("quorum-relay-checkpoint")
+
+var (
+	relayedMeter        = metrics.NewRegisteredMeter("relay/relayed", nil)
+	alreadyRelayedMeter = metrics.NewRegisteredMeter("relay/duplicates", nil)
+	resentMeter         = metrics.NewRegisteredMeter("relay/resent", nil)
+	skippedMeter        = metrics.NewRegisteredMeter("relay/skipped", nil)
+	relayErrorsMeter    = metrics.NewRegisteredMeter("relay/errors", nil)
+)
+
+// relayEventABI is the function of the target contracts the events are relayed to
+const relayEventABI = `[{"type":"function","name":"relayEvent","inputs":[{"name":"sourceTx","type":"bytes32"},{"name":"logIndex","type":"uint256"},{"name":"topics","type":"bytes32[]"},{"name":"data","type":"bytes"}],"outputs":[]}]`
+
+// errAlreadyRelayed is returned by a destination for a transaction whose nonce was
+// already used
+var errAlreadyRelayed = errors.New("transaction already relayed")
+
+// Config is the destination network of the relay and the transactions relayed
+type Config struct {
+	// URL of the RPC endpoint of a node of the destination network, the relay is
+	// disabled if empty
+	URL string `json:"url" toml:",omitempty"`
+	// From is the account sending the relayed transactions, unlocked on the destination
+	// node. It must not send other transactions, its nonces protect from replays.
+	From common.Address `json:"from" toml:",omitempty"`
+	// PrivateFrom is the TM key of the sender on the destination network
+	PrivateFrom string `json:"privateFrom" toml:",omitempty"`
+	// Keys maps the TM keys of the participants on this network to their TM keys on the
+	// destination network. The participants without a mapping are not relayed to.
+	Keys map[string]string `json:"keys" toml:",omitempty"`
+	// PSI is the private state whose receipts select the transactions relayed
+	PSI string `json:"psi,omitempty" toml:",omitempty"`
+	// Gas is the gas limit of the relayed transactions, estimated by the destination
+	// node if 0
+	Gas uint64 `json:"gas,omitempty" toml:",omitempty"`
+	// Rules select the private transactions relayed
+	Rules []*Rule `json:"rules" toml:",omitempty"`
+}
+
+// Rule relays the private transactions sent to a contract
+type Rule struct {
+	// Contract is the contract of this network whose private transactions are relayed
+	Contract common.Address `json:"contract"`
+	// Target is the contract of the destination network the transactions are relayed to
+	Target common.Address `json:"target"`
+	// Events relays the events emitted by the contract, to relayEvent of the target,
+	// instead of the input of the transactions
+	Events bool `json:"events,omitempty" toml:",omitempty"`
+}
+
+// DefaultConfig contains the default relay options
+var DefaultConfig = Config{
+	PSI: types.DefaultPrivateStateIdentifier.String(),
+}
+
+// Enabled reports whether transactions are relayed
+func (c *Config) Enabled() bool {
+	return c.URL != ""
+}
+
+// Validate checks the relay options
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	switch {
+	case c.From == (common.Address{}):
+		return errors.New("the relay sender account must be set")
+	case c.PrivateFrom == "":
+		return errors.New("the relay sender TM key must be set")
+	case len(c.Keys) == 0:
+		return errors.New("the relay has no TM key mapping, no participant would receive the relayed transactions")
+	case len(c.Rules) == 0:
+		return errors.New("the relay has no rules, no transaction would be relayed")
+	}
+	seen := make(map[common.Address]map[bool]bool)
+	for i, rule := range c.Rules {
+		if rule == nil || rule.Contract == (common.Address{}) || rule.Target == (common.Address{}) {
+			return fmt.Errorf("relay rule %d: the contract and the target must be set", i)
+		}
+		if seen[rule.Contract] == nil {
+			seen[rule.Contract] = make(map[bool]bool)
+		}
+		if seen[rule.Contract][rule.Events] {
+			return fmt.Errorf("relay rule %d: duplicate rule for contract %s", i, rule.Contract.Hex())
+		}
+		seen[rule.Contract][rule.Events] = true
+	}
+	return nil
+}
+
+// SendTxArgs is a transaction sent to the destination network
+type SendTxArgs struct {
+	From        common.Address  `json:"from"`
+	To          common.Address  `json:"to"`
+	Gas         *hexutil.Uint64 `json:"gas,omitempty"`
+	Nonce       hexutil.Uint64  `json:"nonce"`
+	Data        hexutil.Bytes   `json:"data"`
+	PrivateFrom string          `json:"privateFrom"`
+	PrivateFor  []string        `json:"privateFor"`
+}
+
+// Destination is the network the transactions are relayed to
+type Destination interface {
+	// Nonce returns the next nonce of the account in the latest block
+	Nonce(ctx context.Context, account common.Address) (uint64, error)
+	// PendingNonce returns the next nonce of the account, after its pending transactions
+	PendingNonce(ctx context.Context, account common.Address) (uint64, error)
+	// SendTransaction sends a transaction, errAlreadyRelayed if its nonce was used
+	SendTransaction(ctx context.Context, args *SendTxArgs) (common.Hash, error)
+	Close()
+}
+
+// Backend is the chain whose transactions are relayed
+type Backend interface {
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// checkpoint is the position of the relay, the messages of the blocks before are relayed
+type checkpoint struct {
+	Block      uint64 // number of the next block to relay
+	Message    uint64 // number of the messages of the block already relayed
+	NonceKnown bool   // false until the nonce of the sender is read from the destination
+	Nonce      uint64 // next nonce of the sender
+}
+
+// message is a transaction relayed
+type message struct {
+	source     common.Hash // hash of the source transaction
+	to         common.Address
+	data       []byte
+	privateFor []string
+}
+
+// Service relays the selected private transactions, from the checkpoint to the head
+type Service struct {
+	config      *Config
+	rules       map[common.Address][]*Rule
+	eventABI    abi.ABI
+	destination Destination
+	backend     Backend
+	ptm         private.PrivateTransactionManager
+	db          ethdb.KeyValueStore // checkpoint store
+
+	checkpoint checkpoint
+	sent       map[uint64]*SendTxArgs // transactions sent since the start and not yet mined, by nonce
+	loop       *catchup.Loop
+
+	ctx    context.Context // canceled on stop, aborting the pending relay
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates the relay of the private transactions of backend, whose payloads are
+// read from ptm. The checkpoint is stored in db.
+func New(config *Config, backend Backend, ptm private.PrivateTransactionManager, db ethdb.KeyValueStore) (*Service, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	destination, err := dialDestination(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	return newService(config, destination, backend, ptm, db), nil
+}
+
+func newService(config *Config, destination Destination, backend Backend, ptm private.PrivateTransactionManager, db ethdb.KeyValueStore) *Service {
+	eventABI, err := abi.JSON(strings.NewReader(relayEventABI))
+	if err != nil {
+		panic(err)
+	}
+	s := &Service{
+		config:      config,
+		rules:       make(map[common.Address][]*Rule),
+		eventABI:    eventABI,
+		destination: destination,
+		backend:     backend,
+		ptm:         ptm,
+		db:          db,
+		sent:        make(map[uint64]*SendTxArgs),
+		loop:        catchup.NewLoop(retryInterval),
+	}
+	for _, rule := range config.Rules {
+		s.rules[rule.Contract] = append(s.rules[rule.Contract], rule)
+	}
+	return s
+}
+
+// Start implements node.Lifecycle, starting the relay from the checkpoint, or from
+// the next block the first time
+func (s *Service) Start() error {
+	cp, err := s.readCheckpoint()
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = &checkpoint{Block: s.backend.CurrentBlock().NumberU64() + 1}
+		if err := s.writeCheckpoint(cp); err != nil {
+			return err
+		}
+	}
+	s.checkpoint = *cp
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	log.Info("Relaying private transactions", "url", s.config.URL, "rules", len(s.config.Rules), "from", s.checkpoint.Block)
+
+	// subscribed before the loop starts, so that no head is missed
+	heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	headSub := s.backend.SubscribeChainHeadEvent(heads)
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		defer headSub.Unsubscribe()
+		s.receiveLoop(heads)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.loop.Run(s.ctx, s.relayPending, func(err error) {
+			relayErrorsMeter.Mark(1)
+			log.Warn("Failed to relay private transactions, retrying", "block", s.checkpoint.Block, "err", err)
+		})
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle
+func (s *Service) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.cancel = nil
+	s.destination.Close()
+	return nil
+}
+
+// receiveLoop drains the head subscription, so that the chain is never held up by the
+// destination, the heads being coalesced into a single wake-up of the relay
+func (s *Service) receiveLoop(heads chan core.ChainHeadEvent) {
+	for {
+		select {
+		case <-heads:
+			s.loop.Wake()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// relayPending relays the messages of the blocks up to the head
+func (s *Service) relayPending() error {
+	if err := s.resendDropped(); err != nil {
+		return err
+	}
+	head := s.backend.CurrentBlock().NumberU64()
+	for s.checkpoint.Block <= head {
+		messages, err := s.messages(s.checkpoint.Block)
+		if err != nil {
+			return err
+		}
+		for s.checkpoint.Message < uint64(len(messages)) {
+			if err := s.relay(messages[s.checkpoint.Message]); err != nil {
+				return err
+			}
+		}
+		next := checkpoint{Block: s.checkpoint.Block + 1, NonceKnown: s.checkpoint.NonceKnown, Nonce: s.checkpoint.Nonce}
+		if err := s.writeCheckpoint(&next); err != nil {
+			return err
+		}
+		s.checkpoint = next
+	}
+	return nil
+}
+
+// relay sends a message with the next nonce of the sender, and checkpoints it
+func (s *Service) relay(msg *message) error {
+	ctx, cancel := context.WithTimeout(s.ctx, sendTimeout)
+	defer cancel()
+	if !s.checkpoint.NonceKnown {
+		nonce, err := s.destination.PendingNonce(ctx, s.config.From)
+		if err != nil {
+			return fmt.Errorf("nonce of the relay sender: %v", err)
+		}
+		s.checkpoint.Nonce, s.checkpoint.NonceKnown = nonce, true
+	}
+	args := &SendTxArgs{
+		From:        s.config.From,
+		To:          msg.to,
+		Nonce:       hexutil.Uint64(s.checkpoint.Nonce),
+		Data:        msg.data,
+		PrivateFrom: s.config.PrivateFrom,
+		PrivateFor:  msg.privateFor,
+	}
+	if s.config.Gas > 0 {
+		gas := hexutil.Uint64(s.config.Gas)
+		args.Gas = &gas
+	}
+	// kept to be sent again if dropped, including when the answer of the destination is lost
+	s.sent[s.checkpoint.Nonce] = args
+	hash, err := s.destination.SendTransaction(ctx, args)
+	switch {
+	case err == errAlreadyRelayed:
+		alreadyRelayedMeter.Mark(1)
+		log.Info("Private transaction already relayed", "source", msg.source, "nonce", s.checkpoint.Nonce)
+	case err != nil:
+		return fmt.Errorf("relay of transaction %s: %v", msg.source.Hex(), err)
+	default:
+		relayedMeter.Mark(1)
+		log.Debug("Relayed private transaction", "source", msg.source, "destination", hash, "nonce", s.checkpoint.Nonce)
+	}
+	next := s.checkpoint
+	next.Message++
+	next.Nonce++
+	if err := s.writeCheckpoint(&next); err != nil {
+		return err
+	}
+	s.checkpoint = next
+	return nil
+}
+
+// resendDropped sends again the transactions dropped by the destination before they
+// were mined, whose nonces would hold up the next relayed transactions. The nonce of a
+// dropped transaction sent before a restart is skipped with an empty transaction of the
+// sender to itself, the message being lost.
+func (s *Service) resendDropped() error {
+	if !s.checkpoint.NonceKnown {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, sendTimeout)
+	defer cancel()
+	mined, err := s.destination.Nonce(ctx, s.config.From)
+	if err != nil {
+		return fmt.Errorf("nonce of the relay sender: %v", err)
+	}
+	for nonce := range s.sent {
+		if nonce < mined {
+			delete(s.sent, nonce)
+		}
+	}
+	pending, err := s.destination.PendingNonce(ctx, s.config.From)
+	if err != nil {
+		return fmt.Errorf("nonce of the relay sender: %v", err)
+	}
+	for nonce := pending; nonce < s.checkpoint.Nonce; nonce++ {
+		args, ok := s.sent[nonce]
+		if ok {
+			resentMeter.Mark(1)
+			log.Warn("Relayed transaction dropped by the destination, sending it again", "nonce", nonce)
+		} else {
+			skippedMeter.Mark(1)
+			log.Error("Relayed transaction dropped by the destination and sent before the restart, skipping its nonce", "nonce", nonce)
+			args = &SendTxArgs{From: s.config.From, To: s.config.From, Nonce: hexutil.Uint64(nonce)}
+		}
+		// the transactions queued after the dropped one are already known
+		if _, err := s.destination.SendTransaction(ctx, args); err != nil && err != errAlreadyRelayed {
+			return fmt.Errorf("transaction of nonce %d dropped by the destination: %v", nonce, err)
+		}
+	}
+	return nil
+}
+
+// messages returns the messages relayed for the transactions of a block, in the order
+// of the transactions and of their events
+func (s *Service) messages(number uint64) ([]*message, error) {
+	block := s.backend.GetBlockByNumber(number)
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	receipts := s.backend.GetReceiptsByHash(block.Hash())
+	if len(receipts) != len(block.Transactions()) {
+		return nil, fmt.Errorf("receipts of block #%d not found", number)
+	}
+	var messages []*message
+	for i, tx := range block.Transactions() {
+		if !tx.IsPrivate() || tx.To() == nil {
+			continue
+		}
+		receipt := receipts[i]
+		if psReceipt, ok := receipt.PSReceipts[types.PrivateStateIdentifier(s.config.PSI)]; ok {
+			receipt = psReceipt
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful || !s.selects(tx, receipt) {
+			continue
+		}
+		txMessages, err := s.txMessages(tx, receipt)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %v", tx.Hash().Hex(), err)
+		}
+		messages = append(messages, txMessages...)
+	}
+	return messages, nil
+}
+
+// selects reports whether a rule selects the transaction or one of its events
+func (s *Service) selects(tx *types.Transaction, receipt *types.Receipt) bool {
+	for _, rule := range s.rules[*tx.To()] {
+		if !rule.Events {
+			return true
+		}
+	}
+	for _, l := range receipt.Logs {
+		for _, rule := range s.rules[l.Address] {
+			if rule.Events {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// txMessages returns the messages relayed for a private transaction, none if the
+// node is not a party
+func (s *Service) txMessages(tx *types.Transaction, receipt *types.Receipt) ([]*message, error) {
+	payloadHash := common.BytesToEncryptedPayloadHash(tx.Data())
+	_, _, payload, _, err := s.ptm.Receive(payloadHash)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, nil
+	}
+	participants, err := s.ptm.GetParticipants(payloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("participants: %v", err)
+	}
+	privateFor := s.mapKeys(participants)
+	if len(privateFor) == 0 {
+		log.Warn("No participant of the private transaction has a TM key on the relay destination, skipping it", "tx", tx.Hash())
+		return nil, nil
+	}
+	var messages []*message
+	for _, rule := range s.rules[*tx.To()] {
+		if !rule.Events {
+			messages = append(messages, &message{source: tx.Hash(), to: rule.Target, data: payload, privateFor: privateFor})
+		}
+	}
+	for _, l := range receipt.Logs {
+		for _, rule := range s.rules[l.Address] {
+			if !rule.Events {
+				continue
+			}
+			topics := make([][32]byte, len(l.Topics))
+			for i, topic := range l.Topics {
+				topics[i] = topic
+			}
+			data, err := s.eventABI.Pack("relayEvent", [32]byte(tx.Hash()), new(big.Int).SetUint64(uint64(l.Index)), topics, l.Data)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, &message{source: tx.Hash(), to: rule.Target, data: data, privateFor: privateFor})
+		}
+	}
+	return messages, nil
+}
+
+// mapKeys returns the TM keys on the destination network of the participants, except
+// the sender of the relayed transactions
+func (s *Service) mapKeys(participants []string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, participant := range participants {
+		key, ok := s.config.Keys[participant]
+		if !ok || key == s.config.PrivateFrom || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// readCheckpoint returns the position of the relay, nil if it never relayed
+func (s *Service) readCheckpoint() (*checkpoint, error) {
+	if has, err := s.db.Has(checkpointKey); err != nil || !has {
+		return nil, err
+	}
+	data, err := s.db.Get(checkpointKey)
+	if err != nil {
+		return nil, err
+	}
+	cp := new(checkpoint)
+	if err := rlp.Decode(bytes.NewReader(data), cp); err != nil {
+		return nil, fmt.Errorf("invalid relay checkpoint: %v", err)
+	}
+	return cp, nil
+}
+
+func (s *Service) writeCheckpoint(cp *checkpoint) error {
+	data, err := rlp.EncodeToBytes(cp)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(checkpointKey, data)
+}
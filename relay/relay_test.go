@@ -0,0 +1,297 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package relay
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/private/engine/memory"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	contractA = common.HexToAddress("0x000000000000000000000000000000000000000a")
+	contractB = common.HexToAddress("0x000000000000000000000000000000000000000b")
+	targetA   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	targetB   = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	sender    = common.HexToAddress("0x0000000000000000000000000000000000000099")
+)
+
+func testConfig() *Config {
+	return &Config{
+		URL:         "http://destination:8545",
+		From:        sender,
+		PrivateFrom: "relayKey",
+		Keys:        map[string]string{"keyA": "relayKey", "keyB": "destKeyB", "keyC": "destKeyC"},
+		PSI:         types.DefaultPrivateStateIdentifier.String(),
+		Rules: []*Rule{
+			{Contract: contractA, Target: targetA},
+			{Contract: contractB, Target: targetB, Events: true},
+		},
+	}
+}
+
+type stubBackend struct {
+	mu       sync.Mutex
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+	feed     event.Feed
+}
+
+func newStubBackend() *stubBackend {
+	b := &stubBackend{receipts: make(map[common.Hash]types.Receipts)}
+	b.addBlock(nil, nil)
+	return b
+}
+
+func (b *stubBackend) addBlock(txs []*types.Transaction, receipts []*types.Receipt) {
+	b.mu.Lock()
+	header := &types.Header{Number: big.NewInt(int64(len(b.blocks)))}
+	block := types.NewBlock(header, txs, nil, receipts, new(trie.Trie))
+	b.blocks = append(b.blocks, block)
+	b.receipts[block.Hash()] = receipts
+	b.mu.Unlock()
+	b.feed.Send(core.ChainHeadEvent{Block: block})
+}
+
+func (b *stubBackend) CurrentBlock() *types.Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocks[len(b.blocks)-1]
+}
+
+func (b *stubBackend) GetBlockByNumber(number uint64) *types.Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if number >= uint64(len(b.blocks)) {
+		return nil
+	}
+	return b.blocks[number]
+}
+
+func (b *stubBackend) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.receipts[hash]
+}
+
+func (b *stubBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.feed.Subscribe(ch)
+}
+
+// stubDestination records the transactions by nonce, a transaction whose nonce was
+// used is rejected like a node would
+type stubDestination struct {
+	mu       sync.Mutex
+	mined    uint64 // next nonce in the latest block
+	nonce    uint64 // next pending nonce
+	sent     map[uint64]*SendTxArgs
+	loseNext bool // accept the next transaction, but fail as if the answer was lost
+}
+
+func newStubDestination(nonce uint64) *stubDestination {
+	return &stubDestination{nonce: nonce, sent: make(map[uint64]*SendTxArgs)}
+}
+
+func (d *stubDestination) Nonce(ctx context.Context, account common.Address) (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mined, nil
+}
+
+// drop drops the pending transactions from nonce on, like a node evicting them
+func (d *stubDestination) drop(nonce uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for n := range d.sent {
+		if n >= nonce {
+			delete(d.sent, n)
+		}
+	}
+	d.nonce = nonce
+}
+
+func (d *stubDestination) PendingNonce(ctx context.Context, account common.Address) (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.nonce, nil
+}
+
+func (d *stubDestination) SendTransaction(ctx context.Context, args *SendTxArgs) (common.Hash, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.sent[uint64(args.Nonce)]; ok {
+		return common.Hash{}, errAlreadyRelayed
+	}
+	d.sent[uint64(args.Nonce)] = args
+	if uint64(args.Nonce) == d.nonce {
+		d.nonce++
+	}
+	if d.loseNext {
+		d.loseNext = false
+		return common.Hash{}, errors.New("connection reset")
+	}
+	return common.BytesToHash([]byte{byte(args.Nonce)}), nil
+}
+
+func (d *stubDestination) Close() {}
+
+func (d *stubDestination) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.sent)
+}
+
+// privateTx returns a private transaction to a contract, with its receipt
+func privateTx(t *testing.T, ptm *memory.PrivateTransactionManager, to common.Address, input string, status uint64, logs ...*types.Log) (*types.Transaction, *types.Receipt) {
+	_, _, hash, err := ptm.Send([]byte(input), "keyA", []string{"keyB", "keyD"}, &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStandardPrivate})
+	require.NoError(t, err)
+	tx := types.NewTransaction(0, to, big.NewInt(0), 100000, big.NewInt(0), hash.Bytes())
+	tx.SetPrivate()
+	return tx, &types.Receipt{Status: status, Logs: logs, TxHash: tx.Hash()}
+}
+
+func TestService_relaysTransactionsAndEvents(t *testing.T) {
+	ptm := memory.New()
+	backend := newStubBackend()
+	destination := newStubDestination(7)
+	service := newService(testConfig(), destination, backend, ptm, memorydb.New())
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	relayedTx, relayedReceipt := privateTx(t, ptm, contractA, "input", types.ReceiptStatusSuccessful)
+	failedTx, failedReceipt := privateTx(t, ptm, contractA, "failed", types.ReceiptStatusFailed)
+	event := &types.Log{Address: contractB, Topics: []common.Hash{{1}, {2}}, Data: []byte("event"), Index: 3}
+	eventTx, eventReceipt := privateTx(t, ptm, common.Address{0xc}, "event", types.ReceiptStatusSuccessful, event)
+	notPartyTx := types.NewTransaction(0, contractA, big.NewInt(0), 100000, big.NewInt(0), common.Hash{1}.Bytes())
+	notPartyTx.SetPrivate()
+	publicTx := types.NewTransaction(0, contractA, big.NewInt(0), 100000, big.NewInt(0), []byte("public"))
+	backend.addBlock(
+		[]*types.Transaction{relayedTx, failedTx, notPartyTx, publicTx, eventTx},
+		[]*types.Receipt{relayedReceipt, failedReceipt, {Status: types.ReceiptStatusSuccessful}, {Status: types.ReceiptStatusSuccessful}, eventReceipt})
+
+	require.Eventually(t, func() bool { return destination.count() == 2 }, time.Second, 10*time.Millisecond)
+
+	tx := destination.sent[7]
+	assert.Equal(t, targetA, tx.To)
+	assert.Equal(t, sender, tx.From)
+	assert.Equal(t, []byte("input"), []byte(tx.Data))
+	assert.Equal(t, "relayKey", tx.PrivateFrom)
+	assert.Equal(t, []string{"destKeyB"}, tx.PrivateFor, "the participants are mapped, except the ones without mapping and the sender")
+
+	eventABI, err := abi.JSON(strings.NewReader(relayEventABI))
+	require.NoError(t, err)
+	expected, err := eventABI.Pack("relayEvent", [32]byte(eventTx.Hash()), big.NewInt(3), [][32]byte{{1}, {2}}, []byte("event"))
+	require.NoError(t, err)
+	tx = destination.sent[8]
+	assert.Equal(t, targetB, tx.To)
+	assert.Equal(t, expected, []byte(tx.Data))
+}
+
+func TestService_doesNotRelayTwice(t *testing.T) {
+	ptm := memory.New()
+	backend := newStubBackend()
+	destination := newStubDestination(0)
+	destination.loseNext = true
+	db := memorydb.New()
+	service := newService(testConfig(), destination, backend, ptm, db)
+	service.ctx, service.checkpoint = context.Background(), checkpoint{Block: 1}
+	first, firstReceipt := privateTx(t, ptm, contractA, "first", types.ReceiptStatusSuccessful)
+	backend.addBlock([]*types.Transaction{first}, []*types.Receipt{firstReceipt})
+
+	assert.Error(t, service.relayPending(), "the answer of the destination is lost")
+	require.NoError(t, service.relayPending())
+
+	assert.Equal(t, 1, destination.count(), "the transaction sent again has a used nonce")
+	cp, err := service.readCheckpoint()
+	require.NoError(t, err)
+	assert.Equal(t, &checkpoint{Block: 2, NonceKnown: true, Nonce: 1}, cp)
+
+	second, secondReceipt := privateTx(t, ptm, contractA, "second", types.ReceiptStatusSuccessful)
+	backend.addBlock([]*types.Transaction{second}, []*types.Receipt{secondReceipt})
+	service = newService(testConfig(), destination, backend, ptm, db)
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	require.Eventually(t, func() bool { return destination.count() == 2 }, time.Second, 10*time.Millisecond, "the relay resumes from its checkpoint")
+	assert.Equal(t, []byte("second"), []byte(destination.sent[1].Data))
+}
+
+func TestService_resendsDroppedTransactions(t *testing.T) {
+	ptm := memory.New()
+	backend := newStubBackend()
+	destination := newStubDestination(0)
+	service := newService(testConfig(), destination, backend, ptm, memorydb.New())
+	service.ctx, service.checkpoint = context.Background(), checkpoint{Block: 1}
+	first, firstReceipt := privateTx(t, ptm, contractA, "first", types.ReceiptStatusSuccessful)
+	second, secondReceipt := privateTx(t, ptm, contractA, "second", types.ReceiptStatusSuccessful)
+	backend.addBlock([]*types.Transaction{first, second}, []*types.Receipt{firstReceipt, secondReceipt})
+	require.NoError(t, service.relayPending())
+
+	destination.drop(0)
+	require.NoError(t, service.relayPending())
+
+	assert.Equal(t, 2, destination.count())
+	assert.Equal(t, []byte("first"), []byte(destination.sent[0].Data), "the dropped transactions are sent again")
+	assert.Equal(t, []byte("second"), []byte(destination.sent[1].Data))
+
+	destination.mined = 1
+	destination.drop(1)
+	restarted := newService(testConfig(), destination, backend, ptm, memorydb.New())
+	restarted.ctx, restarted.checkpoint = context.Background(), service.checkpoint
+	require.NoError(t, restarted.relayPending())
+
+	assert.Empty(t, destination.sent[1].Data, "the nonce of the transaction dropped before the restart is skipped")
+	assert.Equal(t, sender, destination.sent[1].To)
+	assert.Nil(t, destination.sent[1].PrivateFor)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&Config{}).Validate(), "disabled")
+	assert.NoError(t, testConfig().Validate())
+
+	config := testConfig()
+	config.From = common.Address{}
+	assert.Error(t, config.Validate())
+
+	config = testConfig()
+	config.Keys = nil
+	assert.Error(t, config.Validate())
+
+	config = testConfig()
+	config.Rules = append(config.Rules, &Rule{Contract: contractA, Target: targetB})
+	assert.Error(t, config.Validate(), "duplicate rule")
+
+	config = testConfig()
+	config.Rules = append(config.Rules, &Rule{Contract: contractA})
+	assert.Error(t, config.Validate(), "no target")
+}
@@ -79,6 +79,9 @@ type Client struct {
 	isHTTP   bool
 	services *serviceRegistry
 
+	// Quorum: settings applied to the handler serving this client's connection
+	handlerOptions handlerOptions
+
 	idCounter uint32
 
 	// This function, if non-nil, is called when the connection is lost.
@@ -113,6 +116,7 @@ type clientConn struct {
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.WithValue(context.Background(), clientContextKey{}, c)
 	handler := newHandler(ctx, conn, c.idgen, c.services)
+	handler.handlerOptions = c.handlerOptions
 	return &clientConn{conn, handler}
 }
 
@@ -200,7 +204,7 @@ func newClient(initctx context.Context, connect reconnectFunc) (*Client, error)
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), new(serviceRegistry))
+	c := initClient(conn, randomIDGenerator(), new(serviceRegistry), handlerOptions{})
 	c.reconnectFunc = connect
 	if providerFunc := PSIProviderFromContext(initctx); providerFunc != nil {
 		c = c.WithPSIProvider(providerFunc)
@@ -208,22 +212,23 @@ func newClient(initctx context.Context, connect reconnectFunc) (*Client, error)
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, options handlerOptions) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
-		idgen:       idgen,
-		isHTTP:      isHTTP,
-		services:    services,
-		writeConn:   conn,
-		close:       make(chan struct{}),
-		closing:     make(chan struct{}),
-		didClose:    make(chan struct{}),
-		reconnected: make(chan ServerCodec),
-		readOp:      make(chan readOp),
-		readErr:     make(chan error),
-		reqInit:     make(chan *requestOp),
-		reqSent:     make(chan error, 1),
-		reqTimeout:  make(chan *requestOp),
+		idgen:          idgen,
+		isHTTP:         isHTTP,
+		services:       services,
+		handlerOptions: options,
+		writeConn:      conn,
+		close:          make(chan struct{}),
+		closing:        make(chan struct{}),
+		didClose:       make(chan struct{}),
+		reconnected:    make(chan ServerCodec),
+		readOp:         make(chan readOp),
+		readErr:        make(chan error),
+		reqInit:        make(chan *requestOp),
+		reqSent:        make(chan error, 1),
+		reqTimeout:     make(chan *requestOp),
 	}
 	if !isHTTP {
 		go c.dispatch(conn)
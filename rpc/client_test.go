@@ -833,3 +833,37 @@ func verifyPSI(t *testing.T, client *Client, expectedPSI types.PrivateStateIdent
 
 	assert.Equal(t, expectedPSI, resp.PSI, msgAndArgs)
 }
+
+type contextLogService struct{}
+
+func (s *contextLogService) Log(ctx context.Context) {
+	log.FromContext(ctx).Info("contextual log")
+}
+
+func TestClient_InProc_whenLoggingWithRequestContext(t *testing.T) {
+	var records []*log.Record
+	previous := log.Root().GetHandler()
+	defer log.Root().SetHandler(previous)
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Msg == "contextual log" {
+			records = append(records, r)
+		}
+		return nil
+	}))
+	server := NewServer()
+	defer server.Stop()
+	assert.NoError(t, server.RegisterName("ctxlog", new(contextLogService)))
+	client := DialInProc(server)
+	defer client.Close()
+	client.WithPSI("arbitrary_psi")
+
+	assert.NoError(t, client.Call(nil, "ctxlog_log"))
+
+	if assert.Len(t, records, 1) {
+		ctx := records[0].Ctx
+		assert.Equal(t, "reqid", ctx[0])
+		assert.Equal(t, "arbitrary_psi/1", fmt.Sprint(ctx[1]))
+		assert.Equal(t, "psi", ctx[2])
+		assert.Equal(t, types.PrivateStateIdentifier("arbitrary_psi"), ctx[3])
+	}
+}
@@ -31,6 +31,7 @@ const (
 	// keys used to save values in request context
 	ctxAuthenticationError   = securityContextKey("AUTHENTICATION_ERROR")   // key to save error during authentication before processing the request body
 	ctxPreauthenticatedToken = securityContextKey("PREAUTHENTICATED_TOKEN") // key to save the preauthenticated token once authenticated
+	ctxClientAddr            = securityContextKey("CLIENT_ADDR")            // key to save the address of the original client, behind trusted proxies
 )
 
 // WithIsMultitenant populates ctx with ctxIsMultitenant key and provided value
@@ -99,3 +100,16 @@ func PreauthenticatedTokenFromContext(ctx SecurityContext) *proto.PreAuthenticat
 	}
 	return nil
 }
+
+// WithClientAddr populates ctx with ctxClientAddr key and provided value
+func WithClientAddr(ctx context.Context, addr string) SecurityContext {
+	return context.WithValue(ctx, ctxClientAddr, addr)
+}
+
+// ClientAddrFromContext returns the address of the original client from ctx with ctxClientAddr key
+func ClientAddrFromContext(ctx SecurityContext) string {
+	if addr, ok := ctx.Value(ctxClientAddr).(string); ok {
+		return addr
+	}
+	return ""
+}
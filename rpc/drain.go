@@ -0,0 +1,118 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quorum
+// Draining of the calls served by the servers, for the maintenance of the node: the
+// new calls are rejected while the calls in flight complete.
+
+// drainPollInterval is the interval at which the calls in flight are checked while
+// waiting for them to complete
+const drainPollInterval = 100 * time.Millisecond
+
+// Drain tracks the calls in flight of the servers sharing it, and rejects the new
+// calls while draining. The calls of the exempt namespaces are always served and not
+// tracked, so that the node is operated during its maintenance.
+type Drain struct {
+	mu       sync.Mutex
+	draining bool
+	inflight int
+	exempt   map[string]bool
+}
+
+// NewDrain creates a drain serving the calls of the exempt namespaces while draining
+func NewDrain(exempt ...string) *Drain {
+	d := &Drain{exempt: make(map[string]bool)}
+	for _, namespace := range exempt {
+		d.exempt[namespace] = true
+	}
+	return d
+}
+
+// enter records a call in flight, it reports whether the call is tracked or returns an
+// error if the call is rejected
+func (d *Drain) enter(method string) (bool, error) {
+	if d == nil {
+		return false, nil
+	}
+	if elem := strings.SplitN(method, serviceMethodSeparator, 2); d.exempt[elem[0]] {
+		return false, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false, &drainingError{}
+	}
+	d.inflight++
+	return true, nil
+}
+
+// leave records the end of a call tracked by enter
+func (d *Drain) leave() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inflight--
+}
+
+// Start rejects the new calls from now on
+func (d *Drain) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.draining = true
+}
+
+// Stop serves the new calls again
+func (d *Drain) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.draining = false
+}
+
+// Draining reports whether the new calls are rejected
+func (d *Drain) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// InFlight returns the number of calls in flight
+func (d *Drain) InFlight() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inflight
+}
+
+// Wait waits until no call is in flight, or returns the error of the context
+func (d *Drain) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for d.InFlight() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
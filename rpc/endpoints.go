@@ -17,13 +17,66 @@
 package rpc
 
 import (
+	"errors"
+	"fmt"
 	"net"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// Quorum
+// IPCSecurity restricts the accounts allowed to attach to the named pipe of an IPC
+// endpoint. It is only supported on Windows.
+type IPCSecurity struct {
+	// SecurityDescriptor is the SDDL security descriptor of the named pipe
+	SecurityDescriptor string
+	// AllowedSIDs are the SIDs of the accounts granted access to the named pipe besides
+	// the local system and the owner of the node process
+	AllowedSIDs []string
+}
+
+// IsEmpty returns true if the default security of the IPC endpoint is kept.
+func (s IPCSecurity) IsEmpty() bool {
+	return s.SecurityDescriptor == "" && len(s.AllowedSIDs) == 0
+}
+
+// sddl returns the SDDL security descriptor of the named pipe, built from the allowed
+// SIDs when no descriptor is given.
+func (s IPCSecurity) sddl() (string, error) {
+	if s.SecurityDescriptor != "" {
+		if len(s.AllowedSIDs) > 0 {
+			return "", errors.New("IPC security descriptor and allowed SIDs are mutually exclusive")
+		}
+		return s.SecurityDescriptor, nil
+	}
+	if len(s.AllowedSIDs) == 0 {
+		return "", nil
+	}
+	// protected DACL granting full access to the local system and the pipe owner
+	sddl := "D:P(A;;GA;;;SY)(A;;GA;;;OW)"
+	for _, sid := range s.AllowedSIDs {
+		if !strings.HasPrefix(sid, "S-1-") || strings.ContainsAny(sid, "();") {
+			return "", fmt.Errorf("invalid SID %q", sid)
+		}
+		sddl += "(A;;GA;;;" + sid + ")"
+	}
+	return sddl, nil
+}
+
 // StartIPCEndpoint starts an IPC endpoint.
 func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, error) {
+	return StartIPCEndpointWithSecurity(ipcEndpoint, apis, IPCSecurity{})
+}
+
+// Quorum
+// StartIPCEndpointWithSecurity starts an IPC endpoint only accessible to the accounts
+// allowed by the given security.
+func StartIPCEndpointWithSecurity(ipcEndpoint string, apis []API, security IPCSecurity) (net.Listener, *Server, error) {
+	sddl, err := security.sddl()
+	if err != nil {
+		return nil, nil, err
+	}
 	// Register all the APIs exposed by the services.
 	handler := NewServer()
 	for _, api := range apis {
@@ -33,7 +86,7 @@ func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, er
 		log.Debug("IPC registered", "namespace", api.Namespace)
 	}
 	// All APIs registered, start the IPC listener.
-	listener, err := ipcListen(ipcEndpoint)
+	listener, err := ipcListenWithSecurity(ipcEndpoint, sddl)
 	if err != nil {
 		return nil, nil, err
 	}
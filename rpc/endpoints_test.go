@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func TestIPCSecurity_whenEmpty(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	sddl, err := IPCSecurity{}.sddl()
+
+	assert.NoError(err)
+	assert.Empty(sddl)
+}
+
+func TestIPCSecurity_whenAllowedSIDs(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	sddl, err := IPCSecurity{AllowedSIDs: []string{"S-1-5-20", "S-1-5-21-1-2-3-1001"}}.sddl()
+
+	assert.NoError(err)
+	assert.Equal("D:P(A;;GA;;;SY)(A;;GA;;;OW)(A;;GA;;;S-1-5-20)(A;;GA;;;S-1-5-21-1-2-3-1001)", sddl)
+}
+
+func TestIPCSecurity_whenInvalidSID(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	_, err := IPCSecurity{AllowedSIDs: []string{"S-1-5-20)(A;;GA;;;WD"}}.sddl()
+
+	assert.Error(err)
+}
+
+func TestIPCSecurity_whenDescriptorAndAllowedSIDs(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	_, err := IPCSecurity{SecurityDescriptor: "D:P(A;;GA;;;SY)", AllowedSIDs: []string{"S-1-5-20"}}.sddl()
+
+	assert.Error(err)
+}
@@ -16,7 +16,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 var (
 	_ Error = new(methodNotFoundError)
@@ -25,6 +28,9 @@ var (
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
+	_ Error = new(responseTooLargeError)
+	_ Error = new(timeoutError)
+	_ Error = new(rateLimitError)
 )
 
 const defaultErrorCode = -32000
@@ -72,3 +78,46 @@ type invalidParamsError struct{ message string }
 func (e *invalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *invalidParamsError) Error() string { return e.message }
+
+// Quorum
+// aggregated size of the responses in a batch exceeds the configured limit
+type responseTooLargeError struct{ limit int }
+
+func (e *responseTooLargeError) ErrorCode() int { return -32003 }
+
+func (e *responseTooLargeError) Error() string {
+	return fmt.Sprintf("batch response too large, limit is %d bytes", e.limit)
+}
+
+// Quorum
+// execution of the method exceeded its configured deadline
+type timeoutError struct {
+	method  string
+	timeout time.Duration
+}
+
+func (e *timeoutError) ErrorCode() int { return -32002 }
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.method, e.timeout)
+}
+
+func (e *timeoutError) ErrorData() interface{} {
+	return map[string]string{"method": e.method, "timeout": e.timeout.String()}
+}
+
+// Quorum
+// the call exceeds the rate limits of the server
+type rateLimitError struct{}
+
+func (e *rateLimitError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitError) Error() string { return "rate limit exceeded" }
+
+// Quorum
+// the server is draining its calls for the maintenance of the node
+type drainingError struct{}
+
+func (e *drainingError) ErrorCode() int { return -32006 }
+
+func (e *drainingError) Error() string { return "node is in maintenance" }
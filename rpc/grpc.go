@@ -0,0 +1,155 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc/jsonrpcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Quorum
+// gRPC metadata keys carrying the access token and the private state identifier,
+// equivalent to HttpAuthorizationHeader and HttpPrivateStateIdentifierHeader
+const (
+	GRPCAuthorizationMetadata          = "authorization"
+	GRPCPrivateStateIdentifierMetadata = "quorum-psi"
+)
+
+// RegisterGRPC exposes the services of this server on the given gRPC server
+// as the jsonrpcpb.JsonRpc service.
+func (s *Server) RegisterGRPC(gs *grpc.Server) {
+	jsonrpcpb.RegisterJsonRpcServer(gs, &grpcService{server: s})
+}
+
+type grpcService struct {
+	server *Server
+}
+
+// Call serves a single JSON-RPC message or batch, in the same way as the HTTP transport.
+func (g *grpcService) Call(ctx context.Context, in *jsonrpcpb.Payload) (*jsonrpcpb.Payload, error) {
+	if len(in.Data) > maxRequestContentLength {
+		return nil, status.Errorf(codes.ResourceExhausted, "content length too large (%d>%d)", len(in.Data), maxRequestContentLength)
+	}
+	conn := &grpcCallConn{Reader: bytes.NewReader(in.Data), remote: g.server.grpcClientAddr(ctx)}
+	codec := NewCodec(conn)
+	defer codec.close()
+	g.server.authenticateGRPC(ctx, codec)
+	g.server.serveSingleRequest(context.WithValue(ctx, "remote", conn.remote), codec)
+	return &jsonrpcpb.Payload{Data: bytes.TrimRight(conn.response.Bytes(), "\n")}, nil
+}
+
+// Stream serves JSON-RPC messages over a bidirectional stream, in the same way as
+// the WebSocket transport.
+func (g *grpcService) Stream(stream jsonrpcpb.JsonRpc_StreamServer) error {
+	conn := &grpcStreamConn{remote: g.server.grpcClientAddr(stream.Context())}
+	codec := NewFuncCodec(conn, func(v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&jsonrpcpb.Payload{Data: data})
+	}, func(v interface{}) error {
+		in, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if len(in.Data) > maxRequestContentLength {
+			return fmt.Errorf("content length too large (%d>%d)", len(in.Data), maxRequestContentLength)
+		}
+		return json.Unmarshal(in.Data, v)
+	})
+	g.server.authenticateGRPC(stream.Context(), codec)
+	g.server.ServeCodec(codec, 0)
+	return nil
+}
+
+// authenticateGRPC populates the security context of the codec from the gRPC metadata
+func (s *Server) authenticateGRPC(ctx context.Context, cfg securityContextConfigurer) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := firstMetadataValue(md, GRPCAuthorizationMetadata)
+	psi := firstMetadataValue(md, GRPCPrivateStateIdentifierMetadata)
+	userProvidedPSI, found := types.DefaultPrivateStateIdentifier, false
+	if psi != "" {
+		userProvidedPSI, found = types.PrivateStateIdentifier(psi), true
+	}
+	securityContext := WithIsMultitenant(context.Background(), s.isMultitenant)
+	securityContext = WithClientAddr(securityContext, s.grpcClientAddr(ctx))
+	cfg.Configure(authenticate(securityContext, token, token != "", userProvidedPSI, found, s.authenticationManager))
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// grpcClientAddr returns the address of the client of the gRPC call, behind the trusted
+// proxies
+func (s *Server) grpcClientAddr(ctx context.Context) string {
+	var remote string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remote = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return s.trustedProxies.clientAddr(remote, md.Get(GRPCForwardedForMetadata))
+}
+
+// grpcCallConn reads a unary request and buffers the response
+type grpcCallConn struct {
+	io.Reader
+	response bytes.Buffer
+	remote   string
+}
+
+func (c *grpcCallConn) Write(p []byte) (int, error) { return c.response.Write(p) }
+
+// Close does nothing and always returns nil.
+func (c *grpcCallConn) Close() error { return nil }
+
+// RemoteAddr returns the peer address of the gRPC call.
+func (c *grpcCallConn) RemoteAddr() string { return c.remote }
+
+// SetWriteDeadline does nothing and always returns nil.
+func (c *grpcCallConn) SetWriteDeadline(time.Time) error { return nil }
+
+// grpcStreamConn represents the stream of a JsonRpc.Stream call. The stream
+// is closed by the gRPC server when the call returns.
+type grpcStreamConn struct {
+	remote string
+}
+
+// Close does nothing and always returns nil.
+func (c *grpcStreamConn) Close() error { return nil }
+
+// RemoteAddr returns the peer address of the gRPC stream.
+func (c *grpcStreamConn) RemoteAddr() string { return c.remote }
+
+// SetWriteDeadline does nothing and always returns nil.
+func (c *grpcStreamConn) SetWriteDeadline(time.Time) error { return nil }
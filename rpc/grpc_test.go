@@ -0,0 +1,100 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc/jsonrpcpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPCClient(t *testing.T, server *Server) (jsonrpcpb.JsonRpcClient, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	server.RegisterGRPC(gs)
+	go gs.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	require.NoError(t, err)
+	return jsonrpcpb.NewJsonRpcClient(conn), func() {
+		conn.Close()
+		gs.Stop()
+		server.Stop()
+	}
+}
+
+func TestGRPCCall(t *testing.T) {
+	client, stop := newTestGRPCClient(t, newTestServer())
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.Call(ctx, &jsonrpcpb.Payload{Data: []byte(`{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"String":"x","Int":1,"Args":null}}`, string(resp.Data))
+
+	resp, err = client.Call(ctx, &jsonrpcpb.Payload{Data: []byte(`[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]},{"jsonrpc":"2.0","id":2,"method":"nftest_subscribe","params":["someSubscription",1,1]}]`)})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"jsonrpc":"2.0","id":1,"result":{"String":"x","Int":1,"Args":null}},{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"notifications not supported"}}]`, string(resp.Data))
+}
+
+func TestGRPCCall_whenPSIInMetadata(t *testing.T) {
+	client, stop := newTestGRPCClient(t, newTestServer())
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, GRPCPrivateStateIdentifierMetadata, "PS1")
+
+	resp, err := client.Call(ctx, &jsonrpcpb.Payload{Data: []byte(`{"jsonrpc":"2.0","id":1,"method":"test_echoCtxPSI","params":[]}`)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"PSI":"PS1"}}`, string(resp.Data))
+}
+
+func TestGRPCStream_whenSubscribe(t *testing.T) {
+	server := newTestServer()
+	client, stop := newTestGRPCClient(t, server)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Stream(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&jsonrpcpb.Payload{Data: []byte(`{"jsonrpc":"2.0","id":1,"method":"nftest_subscribe","params":["someSubscription",2,10]}`)}))
+	for _, want := range []string{
+		`{"jsonrpc":"2.0","id":1,"result":"0x1"}`,
+		`{"jsonrpc":"2.0","method":"nftest_subscription","params":{"subscription":"0x1","result":10}}`,
+		`{"jsonrpc":"2.0","method":"nftest_subscription","params":{"subscription":"0x1","result":11}}`,
+	} {
+		msg, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, want, string(msg.Data))
+	}
+	require.NoError(t, stream.CloseSend())
+}
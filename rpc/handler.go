@@ -19,6 +19,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -62,6 +63,8 @@ type handler struct {
 	log            log.Logger
 	allowSubscribe bool
 
+	handlerOptions // Quorum
+
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
 }
@@ -102,6 +105,15 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		return
 	}
 
+	// Quorum
+	// Reject batches exceeding the configured length
+	if limit := h.batchLimits.RequestLimit; limit > 0 && len(msgs) > limit {
+		h.startCallProc(func(cp *callProc) {
+			h.conn.writeJSON(cp.ctx, errorMessage(&invalidRequestError{fmt.Sprintf("batch too large, limit is %d messages", limit)}))
+		})
+		return
+	}
+
 	// Handle non-call messages first:
 	calls := make([]*jsonrpcMessage, 0, len(msgs))
 	for _, msg := range msgs {
@@ -115,11 +127,30 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	// Process calls on a goroutine because they may block indefinitely:
 	h.startCallProc(func(cp *callProc) {
 		answers := make([]*jsonrpcMessage, 0, len(msgs))
-		for _, msg := range calls {
-			if answer := h.handleCallMsg(cp, msg); answer != nil {
-				answers = append(answers, answer)
+		// Quorum
+		// each message is authorized against the connection context only, so that values
+		// enriched by a previous message in the batch (e.g.: PSI) are not reused
+		connCtx := cp.ctx
+		responseSize := 0
+		for i, msg := range calls {
+			cp.ctx = connCtx
+			answer := h.handleCallMsg(cp, msg)
+			if answer == nil {
+				continue
+			}
+			responseSize += len(answer.Result)
+			if limit := h.batchLimits.ResponseMaxSize; limit > 0 && responseSize > limit {
+				h.log.Warn("Batch response too large", "limit", limit, "unanswered", len(calls)-i)
+				for _, msg := range calls[i:] {
+					if !msg.isNotification() {
+						answers = append(answers, msg.errorResponse(&responseTooLargeError{limit}))
+					}
+				}
+				break
 			}
+			answers = append(answers, answer)
 		}
+		cp.ctx = connCtx
 		h.addSubscriptions(cp.notifiers)
 		if len(answers) > 0 {
 			h.conn.writeJSON(cp.ctx, answers)
@@ -289,6 +320,28 @@ func (h *handler) handleResponse(msg *jsonrpcMessage) {
 // handleCallMsg executes a call message and returns the answer.
 func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	start := time.Now()
+	// Quorum
+	if msg.isNotification() || msg.isCall() {
+		if err := h.rateLimiter.take(ctx.ctx); err != nil {
+			h.log.Debug("Rate limited "+msg.Method, "reqid", idForLog{msg.ID})
+			if msg.isNotification() {
+				return nil
+			}
+			return msg.errorResponse(err)
+		}
+		tracked, err := h.drain.enter(msg.Method)
+		if err != nil {
+			h.log.Debug("Rejected "+msg.Method+" in maintenance", "reqid", idForLog{msg.ID})
+			if msg.isNotification() {
+				return nil
+			}
+			return msg.errorResponse(err)
+		}
+		if tracked {
+			defer h.drain.leave()
+		}
+	}
+	// End Quorum
 	switch {
 	case msg.isNotification():
 		h.handleCall(ctx, msg)
@@ -324,6 +377,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if r, ok := h.conn.(SecurityContextResolver); ok {
 		secCtx, err := SecureCall(r, msg.Method)
 		if err != nil {
+			h.log.Warn("Denied "+msg.Method, "reqid", idForLog{msg.ID}, "err", err)
 			return securityErrorMessage(msg, err)
 		}
 		h.log.Debug("Enrich call context with values from security context")
@@ -333,12 +387,18 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		if psi, found := PrivateStateIdentifierFromContext(secCtx); found {
 			cp.ctx = WithPrivateStateIdentifier(cp.ctx, psi)
 		}
+		if addr := ClientAddrFromContext(secCtx); addr != "" {
+			cp.ctx = WithClientAddr(cp.ctx, addr)
+		}
 	}
 	// try to extract the PSI from the request ID if it is not already there in the context.
 	// this is mainly to serve IPC and InProc transport
 	if _, found := PrivateStateIdentifierFromContext(cp.ctx); !found {
 		cp.ctx = WithPrivateStateIdentifier(cp.ctx, decodePSI(msg.ID))
 	}
+	// the logs of the request are correlated with its ID and private state
+	psi, _ := PrivateStateIdentifierFromContext(cp.ctx)
+	cp.ctx = log.NewContext(cp.ctx, "reqid", idForLog{msg.ID}, "psi", psi)
 
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
@@ -357,7 +417,21 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args)
+	// Quorum
+	// the context is cancelled when the method exceeds its deadline, interrupting the execution
+	ctx := cp.ctx
+	timeout, hasTimeout := h.methodTimeouts.timeout(msg.Method)
+	if hasTimeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(cp.ctx, timeout)
+		defer cancel()
+	}
+	answer := h.runMethod(ctx, msg, callb, args)
+	if hasTimeout && ctx.Err() == context.DeadlineExceeded {
+		h.log.Warn("Method execution timed out", "reqid", idForLog{msg.ID}, "method", msg.Method, "timeout", timeout)
+		answer = msg.errorResponse(&timeoutError{method: msg.Method, timeout: timeout})
+	}
+	// End Quorum
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
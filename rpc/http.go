@@ -243,21 +243,22 @@ func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClos
 type httpServerConn struct {
 	io.Reader
 	io.Writer
-	r *http.Request
+	r      *http.Request
+	remote string // Quorum: address of the original client
 }
 
-func newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
+func newHTTPServerConn(r *http.Request, w http.ResponseWriter, remote string) ServerCodec {
 	body := io.LimitReader(r.Body, maxRequestContentLength)
-	conn := &httpServerConn{Reader: body, Writer: w, r: r}
+	conn := &httpServerConn{Reader: body, Writer: w, r: r, remote: remote}
 	return NewCodec(conn)
 }
 
 // Close does nothing and always returns nil.
 func (t *httpServerConn) Close() error { return nil }
 
-// RemoteAddr returns the peer address of the underlying connection.
+// RemoteAddr returns the address of the client, behind the trusted proxies.
 func (t *httpServerConn) RemoteAddr() string {
-	return t.r.RemoteAddr
+	return t.remote
 }
 
 // SetWriteDeadline does nothing and always returns nil.
@@ -277,8 +278,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
 	// single request.
+	remote := s.trustedProxies.ClientAddr(r)
 	ctx := r.Context()
-	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
+	ctx = context.WithValue(ctx, "remote", remote)
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
 	if ua := r.Header.Get("User-Agent"); ua != "" {
@@ -288,7 +290,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		ctx = context.WithValue(ctx, "Origin", origin)
 	}
 	w.Header().Set("content-type", contentType)
-	codec := newHTTPServerConn(r, w)
+	codec := newHTTPServerConn(r, w, remote)
 	defer codec.close()
 	s.authenticateHttpRequest(r, codec)
 	s.serveSingleRequest(ctx, codec)
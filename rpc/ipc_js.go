@@ -31,6 +31,11 @@ func ipcListen(endpoint string) (net.Listener, error) {
 	return nil, errNotSupported
 }
 
+// ipcListenWithSecurity will create a named pipe on the given endpoint.
+func ipcListenWithSecurity(endpoint string, sddl string) (net.Listener, error) {
+	return nil, errNotSupported
+}
+
 // newIPCConnection will connect to a named pipe with the given endpoint as name.
 func newIPCConnection(ctx context.Context, endpoint string) (net.Conn, error) {
 	return nil, errNotSupported
@@ -20,6 +20,7 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -28,6 +29,9 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// Quorum
+var errIPCSecurityNotSupported = errors.New("IPC security descriptors are only supported for Windows named pipes")
+
 // ipcListen will create a Unix socket on the given endpoint.
 func ipcListen(endpoint string) (net.Listener, error) {
 	if len(endpoint) > int(max_path_size) {
@@ -48,6 +52,16 @@ func ipcListen(endpoint string) (net.Listener, error) {
 	return l, nil
 }
 
+// Quorum
+// ipcListenWithSecurity will create a Unix socket on the given endpoint, security
+// descriptors are not supported.
+func ipcListenWithSecurity(endpoint string, sddl string) (net.Listener, error) {
+	if sddl != "" {
+		return nil, errIPCSecurityNotSupported
+	}
+	return ipcListen(endpoint)
+}
+
 // newIPCConnection will connect to a Unix socket on the given endpoint.
 func newIPCConnection(ctx context.Context, endpoint string) (net.Conn, error) {
 	return new(net.Dialer).DialContext(ctx, "unix", endpoint)
@@ -35,6 +35,16 @@ func ipcListen(endpoint string) (net.Listener, error) {
 	return npipe.Listen(endpoint)
 }
 
+// Quorum
+// ipcListenWithSecurity will create a named pipe on the given endpoint, protected by the
+// given SDDL security descriptor.
+func ipcListenWithSecurity(endpoint string, sddl string) (net.Listener, error) {
+	if sddl == "" {
+		return ipcListen(endpoint)
+	}
+	return listenPipe(endpoint, sddl)
+}
+
 // newIPCConnection will connect to a named pipe with the given endpoint as name.
 func newIPCConnection(ctx context.Context, endpoint string) (net.Conn, error) {
 	timeout := defaultPipeDialTimeout
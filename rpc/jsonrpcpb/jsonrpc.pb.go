@@ -0,0 +1,240 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: jsonrpc.proto
+
+package jsonrpcpb
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Payload struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Payload) Reset()         { *m = Payload{} }
+func (m *Payload) String() string { return proto.CompactTextString(m) }
+func (*Payload) ProtoMessage()    {}
+func (*Payload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1e2967b911c754b, []int{0}
+}
+
+func (m *Payload) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Payload.Unmarshal(m, b)
+}
+func (m *Payload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Payload.Marshal(b, m, deterministic)
+}
+func (m *Payload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Payload.Merge(m, src)
+}
+func (m *Payload) XXX_Size() int {
+	return xxx_messageInfo_Payload.Size(m)
+}
+func (m *Payload) XXX_DiscardUnknown() {
+	xxx_messageInfo_Payload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Payload proto.InternalMessageInfo
+
+func (m *Payload) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Payload)(nil), "jsonrpcpb.Payload")
+}
+
+func init() {
+	proto.RegisterFile("jsonrpc.proto", fileDescriptor_f1e2967b911c754b)
+}
+
+var fileDescriptor_f1e2967b911c754b = []byte{
+	// 126 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x2a, 0xce, 0xcf,
+	0x2b, 0x2a, 0x48, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x84, 0x72, 0x0b, 0x92, 0x94,
+	0x64, 0xb9, 0xd8, 0x03, 0x12, 0x2b, 0x73, 0xf2, 0x13, 0x53, 0x84, 0x84, 0xb8, 0x58, 0x52, 0x12,
+	0x4b, 0x12, 0x25, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0xc0, 0x6c, 0xa3, 0x7c, 0x2e, 0x76, 0xaf,
+	0xe2, 0xfc, 0xbc, 0xa0, 0x82, 0x64, 0x21, 0x3d, 0x2e, 0x16, 0xe7, 0xc4, 0x9c, 0x1c, 0x21, 0x21,
+	0x3d, 0xb8, 0x6e, 0x3d, 0xa8, 0x56, 0x29, 0x2c, 0x62, 0x42, 0x26, 0x5c, 0x6c, 0xc1, 0x25, 0x45,
+	0xa9, 0x89, 0xb9, 0xc4, 0xea, 0xd0, 0x60, 0x34, 0x60, 0x74, 0xe2, 0x8e, 0x42, 0x38, 0x2e, 0x89,
+	0x0d, 0xec, 0x5c, 0x63, 0xc0, 0x00, 0x87, 0xce, 0x30, 0xb1, 0xbf, 0x00, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// JsonRpcClient is the client API for JsonRpc service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type JsonRpcClient interface {
+	// Call serves a single JSON-RPC message or batch. Subscriptions are not supported.
+	Call(ctx context.Context, in *Payload, opts ...grpc.CallOption) (*Payload, error)
+	// Stream serves JSON-RPC messages over a bidirectional stream. Subscription
+	// notifications are sent on the stream.
+	Stream(ctx context.Context, opts ...grpc.CallOption) (JsonRpc_StreamClient, error)
+}
+
+type jsonRpcClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJsonRpcClient(cc grpc.ClientConnInterface) JsonRpcClient {
+	return &jsonRpcClient{cc}
+}
+
+func (c *jsonRpcClient) Call(ctx context.Context, in *Payload, opts ...grpc.CallOption) (*Payload, error) {
+	out := new(Payload)
+	err := c.cc.Invoke(ctx, "/jsonrpcpb.JsonRpc/Call", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jsonRpcClient) Stream(ctx context.Context, opts ...grpc.CallOption) (JsonRpc_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_JsonRpc_serviceDesc.Streams[0], "/jsonrpcpb.JsonRpc/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jsonRpcStreamClient{stream}
+	return x, nil
+}
+
+type JsonRpc_StreamClient interface {
+	Send(*Payload) error
+	Recv() (*Payload, error)
+	grpc.ClientStream
+}
+
+type jsonRpcStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *jsonRpcStreamClient) Send(m *Payload) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *jsonRpcStreamClient) Recv() (*Payload, error) {
+	m := new(Payload)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// JsonRpcServer is the server API for JsonRpc service.
+type JsonRpcServer interface {
+	// Call serves a single JSON-RPC message or batch. Subscriptions are not supported.
+	Call(context.Context, *Payload) (*Payload, error)
+	// Stream serves JSON-RPC messages over a bidirectional stream. Subscription
+	// notifications are sent on the stream.
+	Stream(JsonRpc_StreamServer) error
+}
+
+// UnimplementedJsonRpcServer can be embedded to have forward compatible implementations.
+type UnimplementedJsonRpcServer struct {
+}
+
+func (*UnimplementedJsonRpcServer) Call(ctx context.Context, req *Payload) (*Payload, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Call not implemented")
+}
+func (*UnimplementedJsonRpcServer) Stream(srv JsonRpc_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+
+func RegisterJsonRpcServer(s *grpc.Server, srv JsonRpcServer) {
+	s.RegisterService(&_JsonRpc_serviceDesc, srv)
+}
+
+func _JsonRpc_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Payload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JsonRpcServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jsonrpcpb.JsonRpc/Call",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JsonRpcServer).Call(ctx, req.(*Payload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JsonRpc_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(JsonRpcServer).Stream(&jsonRpcStreamServer{stream})
+}
+
+type JsonRpc_StreamServer interface {
+	Send(*Payload) error
+	Recv() (*Payload, error)
+	grpc.ServerStream
+}
+
+type jsonRpcStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *jsonRpcStreamServer) Send(m *Payload) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *jsonRpcStreamServer) Recv() (*Payload, error) {
+	m := new(Payload)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _JsonRpc_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jsonrpcpb.JsonRpc",
+	HandlerType: (*JsonRpcServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler:    _JsonRpc_Call_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _JsonRpc_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "jsonrpc.proto",
+}
@@ -0,0 +1,215 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Quorum
+
+// OpenRPCVersion is the version of the OpenRPC specification of the discovery document
+const OpenRPCVersion = "1.2.6"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	bigIntType        = reflect.TypeOf(big.Int{})
+)
+
+// OpenRPCDocument describes the methods served by the server, following the OpenRPC
+// specification (https://spec.open-rpc.org).
+type OpenRPCDocument struct {
+	OpenRPC string          `json:"openrpc"`
+	Info    OpenRPCInfo     `json:"info"`
+	Methods []OpenRPCMethod `json:"methods"`
+}
+
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenRPCMethod struct {
+	Name   string              `json:"name"`
+	Params []OpenRPCDescriptor `json:"params"`
+	Result *OpenRPCDescriptor  `json:"result,omitempty"`
+}
+
+// OpenRPCDescriptor describes a parameter or the result of a method.
+type OpenRPCDescriptor struct {
+	Name     string     `json:"name"`
+	Required bool       `json:"required,omitempty"`
+	Schema   JSONSchema `json:"schema"`
+}
+
+// JSONSchema is the JSON schema of a parameter or result, generated from its Go type.
+type JSONSchema map[string]interface{}
+
+// Discover returns the OpenRPC document describing the methods served by this server,
+// generated from the registered services.
+func (s *RPCService) Discover() *OpenRPCDocument {
+	s.server.services.mu.Lock()
+	defer s.server.services.mu.Unlock()
+
+	doc := &OpenRPCDocument{
+		OpenRPC: OpenRPCVersion,
+		Info:    OpenRPCInfo{Title: "Quorum JSON-RPC API", Version: "1.0"},
+		Methods: []OpenRPCMethod{},
+	}
+	for name, svc := range s.server.services.services {
+		for method, cb := range svc.callbacks {
+			doc.Methods = append(doc.Methods, cb.openRPCMethod(name+serviceMethodSeparator+method))
+		}
+		if len(svc.subscriptions) > 0 {
+			doc.Methods = append(doc.Methods, openRPCSubscribeMethod(name, svc.subscriptions))
+		}
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool {
+		return doc.Methods[i].Name < doc.Methods[j].Name
+	})
+	return doc
+}
+
+// openRPCMethod describes the method served by the callback.
+func (c *callback) openRPCMethod(name string) OpenRPCMethod {
+	m := OpenRPCMethod{Name: name, Params: []OpenRPCDescriptor{}}
+	for i, typ := range c.argTypes {
+		m.Params = append(m.Params, OpenRPCDescriptor{
+			Name: fmt.Sprintf("param%d", i),
+			// trailing pointer parameters are optional, see parsePositionalArguments
+			Required: typ.Kind() != reflect.Ptr,
+			Schema:   jsonSchema(typ, make(map[reflect.Type]bool)),
+		})
+	}
+	if out := c.fn.Type(); out.NumOut() > 0 && c.errPos != 0 {
+		m.Result = &OpenRPCDescriptor{Name: "result", Schema: jsonSchema(out.Out(0), make(map[reflect.Type]bool))}
+	}
+	return m
+}
+
+// openRPCSubscribeMethod describes the <namespace>_subscribe method creating the given
+// subscriptions.
+func openRPCSubscribeMethod(namespace string, subscriptions map[string]*callback) OpenRPCMethod {
+	names := make([]string, 0, len(subscriptions))
+	for name := range subscriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return OpenRPCMethod{
+		Name: namespace + subscribeMethodSuffix,
+		Params: []OpenRPCDescriptor{
+			{Name: "subscription", Required: true, Schema: JSONSchema{"type": "string", "enum": names}},
+			{Name: "params", Schema: JSONSchema{}},
+		},
+		Result: &OpenRPCDescriptor{Name: "subscriptionId", Schema: JSONSchema{"type": "string"}},
+	}
+}
+
+// jsonSchema generates the JSON schema of the values of the given type, as encoded by
+// encoding/json. Types with custom encodings are described as strings when they
+// marshal to text, or left unconstrained.
+func jsonSchema(typ reflect.Type, visiting map[reflect.Type]bool) JSONSchema {
+	for typ.Kind() == reflect.Ptr {
+		if typ.Implements(jsonMarshalerType) || typ.Implements(textMarshalerType) {
+			break
+		}
+		typ = typ.Elem()
+	}
+	switch {
+	case typ == bigIntType || typ == reflect.PtrTo(bigIntType):
+		return JSONSchema{"type": "integer"}
+	case typ.Implements(jsonMarshalerType) || reflect.PtrTo(typ).Implements(jsonMarshalerType):
+		if typ.Implements(textMarshalerType) || reflect.PtrTo(typ).Implements(textMarshalerType) {
+			return JSONSchema{"type": "string"}
+		}
+		return JSONSchema{}
+	case typ.Implements(textMarshalerType) || reflect.PtrTo(typ).Implements(textMarshalerType):
+		return JSONSchema{"type": "string"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return JSONSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return JSONSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return JSONSchema{"type": "number"}
+	case reflect.String:
+		return JSONSchema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return JSONSchema{"type": "string"} // base64
+		}
+		return JSONSchema{"type": "array", "items": jsonSchema(typ.Elem(), visiting)}
+	case reflect.Map:
+		return JSONSchema{"type": "object", "additionalProperties": jsonSchema(typ.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[typ] {
+			return JSONSchema{"type": "object"} // recursive type
+		}
+		visiting[typ] = true
+		defer delete(visiting, typ)
+
+		properties := make(map[string]interface{})
+		addStructProperties(typ, properties, visiting)
+		return JSONSchema{"type": "object", "properties": properties}
+	default:
+		// interfaces and types which can't be encoded
+		return JSONSchema{}
+	}
+}
+
+// addStructProperties adds the schemas of the fields encoded by encoding/json,
+// flattening the embedded structs.
+func addStructProperties(typ reflect.Type, properties map[string]interface{}, visiting map[reflect.Type]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructProperties(ft, properties, visiting)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue // not exported
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(tag, ",string") {
+			properties[name] = JSONSchema{"type": "string"}
+			continue
+		}
+		properties[name] = jsonSchema(field.Type, visiting)
+	}
+}
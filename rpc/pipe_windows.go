@@ -0,0 +1,296 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build windows
+
+package rpc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Quorum
+// The named pipe listener below is used instead of npipe when the IPC endpoint is
+// protected by a security descriptor, as npipe always creates the pipe instances with
+// the default security.
+
+const pipeBufferSize = 4096
+
+var (
+	modadvapi32 = windows.NewLazySystemDLL("advapi32.dll")
+
+	procConvertStringSecurityDescriptorToSecurityDescriptorW = modadvapi32.NewProc("ConvertStringSecurityDescriptorToSecurityDescriptorW")
+
+	errPipeClosed = errors.New("use of closed named pipe")
+)
+
+// pipeTimeoutError is returned by the I/O exceeding the deadline of a pipe connection.
+type pipeTimeoutError struct{}
+
+func (pipeTimeoutError) Error() string   { return "i/o timeout" }
+func (pipeTimeoutError) Timeout() bool   { return true }
+func (pipeTimeoutError) Temporary() bool { return true }
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// securityDescriptorFromString converts the SDDL descriptor into a self-relative
+// security descriptor, to be released with windows.LocalFree.
+func securityDescriptorFromString(sddl string) (windows.Handle, error) {
+	s, err := windows.UTF16PtrFromString(sddl)
+	if err != nil {
+		return 0, err
+	}
+	var sd windows.Handle
+	r, _, err := procConvertStringSecurityDescriptorToSecurityDescriptorW.Call(uintptr(unsafe.Pointer(s)), 1, uintptr(unsafe.Pointer(&sd)), 0)
+	if r == 0 {
+		return 0, err
+	}
+	return sd, nil
+}
+
+// pipeListener accepts the clients of a named pipe created with a security descriptor.
+type pipeListener struct {
+	path string
+	sd   windows.Handle
+	sa   *windows.SecurityAttributes
+
+	mu     sync.Mutex
+	handle windows.Handle // pipe instance waiting for the next client
+	closed bool
+}
+
+// listenPipe creates the first instance of the named pipe, failing if the pipe already
+// exists.
+func listenPipe(path string, sddl string) (*pipeListener, error) {
+	sd, err := securityDescriptorFromString(sddl)
+	if err != nil {
+		return nil, err
+	}
+	l := &pipeListener{path: path, sd: sd}
+	l.sa = &windows.SecurityAttributes{SecurityDescriptor: uintptr(sd)}
+	l.sa.Length = uint32(unsafe.Sizeof(*l.sa))
+	if l.handle, err = l.createInstance(true); err != nil {
+		windows.LocalFree(sd)
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *pipeListener) createInstance(first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.path)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	mode := uint32(windows.PIPE_TYPE_BYTE | windows.PIPE_READMODE_BYTE | windows.PIPE_WAIT)
+	return windows.CreateNamedPipe(name, flags, mode, windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+// Accept waits for a client to connect to the pipe.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	for {
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			return nil, errPipeClosed
+		}
+		handle := l.handle
+		l.mu.Unlock()
+
+		_, err := overlappedIO(handle, time.Time{}, func(o *windows.Overlapped, _ *uint32) error {
+			return windows.ConnectNamedPipe(handle, o)
+		})
+		switch err {
+		case nil, windows.ERROR_PIPE_CONNECTED:
+		case windows.ERROR_NO_DATA:
+			// the client already went away, wait for the next one
+			windows.DisconnectNamedPipe(handle)
+			continue
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.closed {
+			return nil, errPipeClosed
+		}
+		if err != nil && err != windows.ERROR_PIPE_CONNECTED {
+			return nil, err
+		}
+		next, err := l.createInstance(false)
+		if err != nil {
+			windows.CloseHandle(handle)
+			return nil, err
+		}
+		l.handle = next
+		return &pipeConn{handle: handle, addr: pipeAddr(l.path)}, nil
+	}
+}
+
+// Close stops accepting clients, the accepted connections stay open.
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	windows.CancelIoEx(l.handle, nil)
+	err := windows.CloseHandle(l.handle)
+	windows.LocalFree(l.sd)
+	return err
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.path)
+}
+
+// pipeConn is the server end of a named pipe connection.
+type pipeConn struct {
+	handle windows.Handle
+	addr   pipeAddr
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+	closed        bool
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	deadline := c.readDeadline
+	c.mu.Unlock()
+	n, err := overlappedIO(c.handle, deadline, func(o *windows.Overlapped, done *uint32) error {
+		return windows.ReadFile(c.handle, b, done, o)
+	})
+	if err == windows.ERROR_BROKEN_PIPE || err == windows.ERROR_PIPE_NOT_CONNECTED {
+		return n, io.EOF
+	}
+	return n, c.mapError(err)
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	deadline := c.writeDeadline
+	c.mu.Unlock()
+	var written int
+	for written < len(b) {
+		n, err := overlappedIO(c.handle, deadline, func(o *windows.Overlapped, done *uint32) error {
+			return windows.WriteFile(c.handle, b[written:], done, o)
+		})
+		written += n
+		if err != nil {
+			return written, c.mapError(err)
+		}
+	}
+	return written, nil
+}
+
+// mapError reports the I/O aborted by Close as a closed connection.
+func (c *pipeConn) mapError(err error) error {
+	if err == windows.ERROR_OPERATION_ABORTED {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.closed {
+			return errPipeClosed
+		}
+	}
+	return err
+}
+
+func (c *pipeConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	windows.CancelIoEx(c.handle, nil)
+	windows.FlushFileBuffers(c.handle)
+	windows.DisconnectNamedPipe(c.handle)
+	return windows.CloseHandle(c.handle)
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline, c.writeDeadline = t, t
+	return nil
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return nil
+}
+
+func (c *pipeConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	return nil
+}
+
+// overlappedIO starts the given asynchronous operation on the handle and waits for it
+// to complete, cancelling it once the deadline is exceeded.
+func overlappedIO(handle windows.Handle, deadline time.Time, op func(*windows.Overlapped, *uint32) error) (int, error) {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(event)
+
+	var (
+		o    = windows.Overlapped{HEvent: event}
+		done uint32
+	)
+	if err := op(&o, &done); err != windows.ERROR_IO_PENDING {
+		return int(done), err
+	}
+	timeout := uint32(windows.INFINITE)
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d < 0 {
+			d = 0
+		}
+		timeout = uint32(d / time.Millisecond)
+	}
+	if ev, _ := windows.WaitForSingleObject(event, timeout); ev == uint32(windows.WAIT_TIMEOUT) {
+		windows.CancelIoEx(handle, &o)
+		windows.GetOverlappedResult(handle, &o, &done, true)
+		return int(done), pipeTimeoutError{}
+	}
+	err = windows.GetOverlappedResult(handle, &o, &done, true)
+	return int(done), err
+}
@@ -0,0 +1,285 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Quorum
+
+const (
+	// HttpForwardedForHeader carries the addresses of the client and of the proxies
+	// a request went through
+	HttpForwardedForHeader = "X-Forwarded-For"
+	// GRPCForwardedForMetadata is the gRPC equivalent of HttpForwardedForHeader
+	GRPCForwardedForMetadata = "x-forwarded-for"
+
+	proxyHeaderTimeout  = 10 * time.Second
+	proxyV1MaxHeaderLen = 107
+)
+
+var (
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	errMissingProxyHeader = errors.New("missing PROXY protocol header")
+	errInvalidProxyHeader = errors.New("invalid PROXY protocol header")
+)
+
+// TrustedProxies are the reverse proxies allowed to forward the address of the original
+// client, with the X-Forwarded-For header or the PROXY protocol. The nil value trusts no
+// proxy.
+type TrustedProxies struct {
+	nets []*net.IPNet
+}
+
+// NewTrustedProxies parses the IP addresses and CIDR ranges of the trusted proxies,
+// returning nil if there are none.
+func NewTrustedProxies(specs []string) (*TrustedProxies, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	p := &TrustedProxies{}
+	for _, spec := range specs {
+		if !strings.Contains(spec, "/") {
+			ip := net.ParseIP(spec)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", spec)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			p.nets = append(p.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %v", spec, err)
+		}
+		p.nets = append(p.nets, ipnet)
+	}
+	return p, nil
+}
+
+// isTrusted returns true if the host of the address belongs to a trusted proxy.
+func (p *TrustedProxies) isTrusted(addr string) bool {
+	if p == nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range p.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddr returns the address of the original client of a request received from
+// remote, walking the forwarded addresses from the closest proxy and stopping at the
+// first one which is not trusted.
+func (p *TrustedProxies) clientAddr(remote string, forwardedFor []string) string {
+	if !p.isTrusted(remote) {
+		return remote
+	}
+	var forwarded []string
+	for _, value := range forwardedFor {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(addr) == nil {
+			break
+		}
+		remote = addr
+		if !p.isTrusted(addr) {
+			break
+		}
+	}
+	return remote
+}
+
+// ClientAddr returns the address of the original client of the HTTP request.
+func (p *TrustedProxies) ClientAddr(r *http.Request) string {
+	return p.clientAddr(r.RemoteAddr, r.Header.Values(HttpForwardedForHeader))
+}
+
+// ProxyProtocolListener wraps the listener so that the connections accepted from the
+// trusted proxies report the client address sent in their PROXY protocol (v1 or v2)
+// header.
+func (p *TrustedProxies) ProxyProtocolListener(l net.Listener) net.Listener {
+	return &proxyListener{Listener: l, proxies: p}
+}
+
+type proxyListener struct {
+	net.Listener
+	proxies *TrustedProxies
+}
+
+func (l *proxyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.proxies.isTrusted(conn.RemoteAddr().String()) {
+		return conn, nil
+	}
+	return &proxyConn{Conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// proxyConn reads the PROXY protocol header on first use, so that slow proxies don't
+// block the accept loop.
+type proxyConn struct {
+	net.Conn
+	reader *bufio.Reader
+
+	once   sync.Once
+	remote net.Addr
+	err    error
+}
+
+func (c *proxyConn) init() {
+	c.once.Do(func() {
+		c.Conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout))
+		c.remote, c.err = readProxyHeader(c.reader)
+		c.Conn.SetReadDeadline(time.Time{})
+		if c.err != nil {
+			log.Debug("Rejecting proxied RPC connection", "proxy", c.Conn.RemoteAddr(), "err", c.err)
+			c.Conn.Close()
+		}
+	})
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	if c.init(); c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the address of the client, or the one of the proxy if the
+// header didn't carry it.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	if c.init(); c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// readProxyHeader reads the PROXY protocol header and returns the source address it
+// carries, nil for the connections initiated by the proxy itself.
+func readProxyHeader(r *bufio.Reader) (net.Addr, error) {
+	sig, err := r.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case bytes.Equal(sig, proxyV2Signature):
+		return readProxyHeaderV2(r)
+	case bytes.HasPrefix(sig, []byte("PROXY ")):
+		return readProxyHeaderV1(r)
+	default:
+		return nil, errMissingProxyHeader
+	}
+}
+
+// readProxyHeaderV1 reads a human-readable header such as
+// "PROXY TCP4 192.0.2.1 192.0.2.2 56324 8545\r\n".
+func readProxyHeaderV1(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= proxyV1MaxHeaderLen {
+			return nil, errInvalidProxyHeader
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, errInvalidProxyHeader
+	}
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if ip == nil || err != nil {
+		return nil, errInvalidProxyHeader
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyHeaderV2 reads a binary header.
+func readProxyHeaderV2(r *bufio.Reader) (net.Addr, error) {
+	var header [16]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	var (
+		verCmd = header[12]
+		family = header[13]
+		body   = make([]byte, binary.BigEndian.Uint16(header[14:]))
+	)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	if verCmd>>4 != 2 {
+		return nil, errInvalidProxyHeader
+	}
+	if verCmd&0xf == 0 {
+		// LOCAL command, e.g.: health checks of the proxy
+		return nil, nil
+	}
+	switch family >> 4 {
+	case 1: // AF_INET
+		if len(body) < 12 {
+			return nil, errInvalidProxyHeader
+		}
+		return &net.TCPAddr{IP: net.IP(body[:4]), Port: int(binary.BigEndian.Uint16(body[8:]))}, nil
+	case 2: // AF_INET6
+		if len(body) < 36 {
+			return nil, errInvalidProxyHeader
+		}
+		return &net.TCPAddr{IP: net.IP(body[:16]), Port: int(binary.BigEndian.Uint16(body[32:]))}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxiesClientAddr(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		// untrusted peers can't forge their address
+		{"172.16.0.1:1234", []string{"1.2.3.4"}, "172.16.0.1:1234"},
+		// no forwarded address
+		{"10.0.0.1:1234", nil, "10.0.0.1:1234"},
+		{"10.0.0.1:1234", []string{"1.2.3.4"}, "1.2.3.4"},
+		// the addresses added before the first untrusted proxy are ignored
+		{"10.0.0.1:1234", []string{"6.6.6.6, 1.2.3.4", "192.168.1.1"}, "1.2.3.4"},
+		{"10.0.0.1:1234", []string{"192.168.1.2,192.168.1.1"}, "192.168.1.2"},
+		{"10.0.0.1:1234", []string{"garbage, 192.168.1.1"}, "192.168.1.1"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, proxies.clientAddr(test.remote, test.forwarded), "remote %s forwarded %v", test.remote, test.forwarded)
+	}
+	var none *TrustedProxies
+	assert.Equal(t, "10.0.0.1:1234", none.clientAddr("10.0.0.1:1234", []string{"1.2.3.4"}))
+
+	_, err = NewTrustedProxies([]string{"10.0.0"})
+	assert.Error(t, err)
+}
+
+func TestReadProxyHeaderV1(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("PROXY TCP4 1.2.3.4 10.0.0.1 56324 8545\r\nGET / HTTP/1.1\r\n"))
+	addr, err := readProxyHeader(r)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4:56324", addr.String())
+	rest, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "GET / HTTP/1.1\r\n", string(rest))
+
+	addr, err = readProxyHeader(bufio.NewReader(strings.NewReader("PROXY UNKNOWN\r\nGET / HTTP/1.1\r\n")))
+	require.NoError(t, err)
+	assert.Nil(t, addr)
+
+	_, err = readProxyHeader(bufio.NewReader(strings.NewReader("GET / HTTP/1.1\r\n")))
+	assert.Equal(t, errMissingProxyHeader, err)
+
+	_, err = readProxyHeader(bufio.NewReader(strings.NewReader("PROXY " + strings.Repeat("x", 200))))
+	assert.Equal(t, errInvalidProxyHeader, err)
+}
+
+func TestReadProxyHeaderV2(t *testing.T) {
+	header := append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x21, 0x11, 0, 12)
+	header = append(header, net.IPv4(1, 2, 3, 4).To4()...)
+	header = append(header, net.IPv4(10, 0, 0, 1).To4()...)
+	var ports [4]byte
+	binary.BigEndian.PutUint16(ports[:], 56324)
+	binary.BigEndian.PutUint16(ports[2:], 8545)
+	header = append(header, ports[:]...)
+
+	r := bufio.NewReader(strings.NewReader(string(header) + "GET"))
+	addr, err := readProxyHeader(r)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4:56324", addr.String())
+	rest, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "GET", string(rest))
+
+	// LOCAL command
+	local := append(append([]byte{}, proxyV2Signature...), 0x20, 0x00, 0, 0)
+	addr, err = readProxyHeader(bufio.NewReader(strings.NewReader(string(local) + "GET")))
+	require.NoError(t, err)
+	assert.Nil(t, addr)
+}
@@ -0,0 +1,186 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+// Quorum
+// Token bucket rate limiting of the calls served by a server, over all the connections
+// and per client address, behind the trusted proxies. The calls of the connections
+// without address (IPC, in-process) are limited per connection.
+
+// idle period after which the limiter of a client address is discarded
+const clientLimiterExpiry = time.Minute
+
+var (
+	rateLimitRejectedMeter = metrics.NewRegisteredMeter("rpc/ratelimit/rejected", nil)
+	rateLimitDelayedMeter  = metrics.NewRegisteredMeter("rpc/ratelimit/delayed", nil)
+	rateLimitWaitTimer     = metrics.NewRegisteredTimer("rpc/ratelimit/wait", nil)
+)
+
+// RateLimits configures the rate limiting of the calls served by a server. Zero rates mean
+// no limit. A zero burst defaults to the rate, rounded up.
+type RateLimits struct {
+	// GlobalRate is the number of calls per second served over all the connections
+	GlobalRate  float64
+	GlobalBurst int
+	// ConnRate is the number of calls per second served for a single client address,
+	// or over a single connection without address
+	ConnRate  float64
+	ConnBurst int
+	// Wait delays the calls exceeding the limits until they are allowed, instead of
+	// rejecting them with an error
+	Wait bool
+}
+
+func newLimiter(r float64, burst int) *rate.Limiter {
+	if r <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(r))
+	}
+	return rate.NewLimiter(rate.Limit(r), burst)
+}
+
+// rateLimiter limits the calls of a connection, sharing the global limiter of the server
+type rateLimiter struct {
+	global, conn *rate.Limiter
+	wait         bool
+}
+
+// take consumes a token of the connection and global limiters, returning an error if the
+// call is rejected
+func (rl *rateLimiter) take(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	for _, l := range []*rate.Limiter{rl.conn, rl.global} {
+		if l == nil {
+			continue
+		}
+		if !rl.wait {
+			if !l.Allow() {
+				rateLimitRejectedMeter.Mark(1)
+				return &rateLimitError{}
+			}
+			continue
+		}
+		r := l.Reserve()
+		delay := r.Delay()
+		if delay == 0 {
+			continue
+		}
+		rateLimitDelayedMeter.Mark(1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			rateLimitWaitTimer.Update(delay)
+		case <-ctx.Done():
+			timer.Stop()
+			r.Cancel()
+			rateLimitRejectedMeter.Mark(1)
+			return &rateLimitError{}
+		}
+	}
+	return nil
+}
+
+// RateLimiter limits the calls served by the servers sharing it
+type RateLimiter struct {
+	limits RateLimits
+	global *rate.Limiter
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastPurge time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a rate limiter which can be shared by several servers, e.g.: one
+// for each transport. It returns nil if the limits are disabled.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	if limits.GlobalRate <= 0 && limits.ConnRate <= 0 {
+		return nil
+	}
+	return &RateLimiter{
+		limits:  limits,
+		global:  newLimiter(limits.GlobalRate, limits.GlobalBurst),
+		clients: make(map[string]*clientLimiter),
+	}
+}
+
+// forConn returns the limiter of a new long lived connection (WS, IPC, gRPC stream), the
+// one of its client address if it has one
+func (s *RateLimiter) forConn(remoteAddr string) *rateLimiter {
+	if s == nil {
+		return nil
+	}
+	if remoteAddr != "" {
+		return s.forClient(remoteAddr)
+	}
+	return &rateLimiter{global: s.global, conn: newLimiter(s.limits.ConnRate, s.limits.ConnBurst), wait: s.limits.Wait}
+}
+
+// forClient returns the limiter of the client with the given remote address, the original
+// client behind the trusted proxies
+func (s *RateLimiter) forClient(remoteAddr string) *rateLimiter {
+	if s == nil {
+		return nil
+	}
+	rl := &rateLimiter{global: s.global, wait: s.limits.Wait}
+	if s.limits.ConnRate <= 0 {
+		return rl
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastPurge) > clientLimiterExpiry {
+		for addr, c := range s.clients {
+			if now.Sub(c.lastSeen) > clientLimiterExpiry {
+				delete(s.clients, addr)
+			}
+		}
+		s.lastPurge = now
+	}
+	c, ok := s.clients[host]
+	if !ok {
+		c = &clientLimiter{limiter: newLimiter(s.limits.ConnRate, s.limits.ConnBurst)}
+		s.clients[host] = c
+	}
+	c.lastSeen = now
+	rl.conn = c.limiter
+	return rl
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	"google.golang.org/grpc/metadata"
 )
 
 type securityContextSupport interface {
@@ -103,6 +104,7 @@ func SecureCall(resolver SecurityContextResolver, method string) (context.Contex
 		if len(elem) != 2 {
 			log.Warn("unsupported method when performing authorization check", "method", method)
 		} else if err := verifyAccess(elem[0], elem[1], authToken.Authorities); err != nil {
+			log.Debug("RPC call denied", "method", method, "client", ClientAddrFromContext(secCtx), "err", err)
 			return nil, err
 		}
 		// authorization check for PSI when multitenancy is enabled
@@ -136,8 +138,14 @@ func SecureCall(resolver SecurityContextResolver, method string) (context.Contex
 // AuthenticateHttpRequest uses the provided authManager to authenticate an http request and populates
 // the provided ctx with additional information useful for consumers
 func AuthenticateHttpRequest(ctx context.Context, r *http.Request, authManager security.AuthenticationManager) (securityContext context.Context) {
-	securityContext = ctx
 	userProvidedPSI, found := extractPSI(r)
+	token, hasToken := extractToken(r)
+	return authenticate(ctx, token, hasToken, userProvidedPSI, found, authManager)
+}
+
+// authenticate populates the security context from the access token and PSI provided by the caller
+func authenticate(ctx context.Context, token string, hasToken bool, userProvidedPSI types.PrivateStateIdentifier, found bool, authManager security.AuthenticationManager) (securityContext context.Context) {
+	securityContext = ctx
 	if found {
 		securityContext = context.WithValue(securityContext, ctxRequestPrivateStateIdentifier, userProvidedPSI)
 	}
@@ -151,8 +159,14 @@ func AuthenticateHttpRequest(ctx context.Context, r *http.Request, authManager s
 		securityContext = WithPrivateStateIdentifier(securityContext, userProvidedPSI)
 		return
 	}
-	if token, hasToken := extractToken(r); hasToken {
-		if authToken, err := authManager.Authenticate(context.Background(), token); err != nil {
+	if hasToken {
+		// the authentication manager receives the address of the original client
+		authCtx := context.Background()
+		if addr := ClientAddrFromContext(securityContext); addr != "" {
+			authCtx = metadata.AppendToOutgoingContext(authCtx, GRPCForwardedForMetadata, addr)
+		}
+		if authToken, err := authManager.Authenticate(authCtx, token); err != nil {
+			log.Debug("RPC authentication failed", "client", ClientAddrFromContext(securityContext), "err", err)
 			securityContext = context.WithValue(securityContext, ctxAuthenticationError, &securityError{err.Error()})
 		} else {
 			securityContext = WithPreauthenticatedToken(securityContext, authToken)
@@ -18,9 +18,12 @@ package rpc
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/ethereum/go-ethereum/log"
@@ -53,6 +56,73 @@ type Server struct {
 	// The implementation would authenticate the token coming from a request
 	authenticationManager security.AuthenticationManager
 	isMultitenant         bool
+	batchLimits           BatchLimits
+	methodTimeouts        MethodTimeouts
+	rateLimiter           *RateLimiter
+	drain                 *Drain
+	wsKeepalive           WebsocketKeepalive
+	trustedProxies        *TrustedProxies
+}
+
+// Quorum
+// handlerOptions are the server settings applied to the handler of a connection
+type handlerOptions struct {
+	batchLimits    BatchLimits
+	methodTimeouts MethodTimeouts
+	rateLimiter    *rateLimiter
+	drain          *Drain
+}
+
+// Quorum
+// BatchLimits restricts the JSON-RPC batches a server accepts. Zero values mean no limit.
+type BatchLimits struct {
+	// RequestLimit is the maximum number of messages in a batch
+	RequestLimit int
+	// ResponseMaxSize is the maximum aggregated size in bytes of the results of a batch.
+	// The call exceeding the limit and the calls after it are answered with an error.
+	ResponseMaxSize int
+}
+
+// Quorum
+// MethodTimeouts are the execution deadlines of RPC methods, keyed by method name
+// (e.g.: eth_call) or by namespace (e.g.: debug_*). The method name takes precedence
+// over the namespace. The context of a method exceeding its deadline is cancelled and
+// a timeout error is returned to the caller.
+type MethodTimeouts map[string]time.Duration
+
+// timeout returns the deadline configured for the given method
+func (t MethodTimeouts) timeout(method string) (time.Duration, bool) {
+	if d, ok := t[method]; ok {
+		return d, d > 0
+	}
+	if elem := strings.SplitN(method, serviceMethodSeparator, 2); len(elem) == 2 {
+		if d, ok := t[elem[0]+serviceMethodSeparator+"*"]; ok {
+			return d, d > 0
+		}
+	}
+	return 0, false
+}
+
+// ParseMethodTimeouts parses a comma separated list of method=duration,
+// e.g.: eth_call=5s,debug_*=1m
+func ParseMethodTimeouts(spec string) (MethodTimeouts, error) {
+	timeouts := make(MethodTimeouts)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid method timeout %q, expected method=duration", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid method timeout %q: %v", entry, err)
+		}
+		timeouts[strings.TrimSpace(kv[0])] = d
+	}
+	return timeouts, nil
 }
 
 // Quorum
@@ -104,7 +174,12 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services)
+	c := initClient(codec, s.idgen, &s.services, handlerOptions{
+		batchLimits:    s.batchLimits,
+		methodTimeouts: s.methodTimeouts,
+		rateLimiter:    s.rateLimiter.forConn(codec.remoteAddr()),
+		drain:          s.drain,
+	})
 	<-codec.closed()
 	c.Close()
 }
@@ -120,6 +195,12 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services)
 	h.allowSubscribe = false
+	h.handlerOptions = handlerOptions{
+		batchLimits:    s.batchLimits,
+		methodTimeouts: s.methodTimeouts,
+		rateLimiter:    s.rateLimiter.forClient(codec.remoteAddr()),
+		drain:          s.drain,
+	}
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -154,6 +235,7 @@ func (s *Server) Stop() {
 // for subsequent authorization-related activities
 func (s *Server) authenticateHttpRequest(r *http.Request, cfg securityContextConfigurer) {
 	securityContext := WithIsMultitenant(context.Background(), s.isMultitenant)
+	securityContext = WithClientAddr(securityContext, s.trustedProxies.ClientAddr(r))
 	securityContext = AuthenticateHttpRequest(securityContext, r, s.authenticationManager)
 	cfg.Configure(securityContext)
 }
@@ -162,6 +244,39 @@ func (s *Server) EnableMultitenancy(b bool) {
 	s.isMultitenant = b
 }
 
+// SetBatchLimits configures the limits applied to batches received from now on
+func (s *Server) SetBatchLimits(limits BatchLimits) {
+	s.batchLimits = limits
+}
+
+// SetMethodTimeouts configures the execution deadlines of the methods called from now on
+func (s *Server) SetMethodTimeouts(timeouts MethodTimeouts) {
+	s.methodTimeouts = timeouts
+}
+
+// SetRateLimiter configures the rate limiting of the calls received from now on, nil
+// disables it
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// SetDrain configures the draining of the calls received from now on, nil disables it
+func (s *Server) SetDrain(drain *Drain) {
+	s.drain = drain
+}
+
+// SetWebsocketKeepalive configures the keepalive of the websocket connections accepted
+// from now on
+func (s *Server) SetWebsocketKeepalive(keepalive WebsocketKeepalive) {
+	s.wsKeepalive = keepalive
+}
+
+// SetTrustedProxies configures the reverse proxies allowed to forward the address of
+// the original client of the requests received from now on, nil trusts no proxy
+func (s *Server) SetTrustedProxies(proxies *TrustedProxies) {
+	s.trustedProxies = proxies
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
@@ -35,6 +35,8 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestServerRegisterName(t *testing.T) {
@@ -63,6 +65,58 @@ func TestServerRegisterName(t *testing.T) {
 	}
 }
 
+type testDynamicService map[string]interface{}
+
+func (s testDynamicService) Callbacks() (map[string]interface{}, error) {
+	return s, nil
+}
+
+func TestServerRegisterName_whenDynamicService(t *testing.T) {
+	server := NewServer()
+	service := testDynamicService{
+		"Echo": func(ctx context.Context, msg *string) (string, error) {
+			if msg == nil {
+				return "", nil
+			}
+			return *msg, nil
+		},
+	}
+
+	assert.NoError(t, server.RegisterName("dynamic", service))
+	assert.NoError(t, server.RegisterName("empty", testDynamicService{}))
+
+	assert.Contains(t, server.services.services, "dynamic")
+	assert.NotContains(t, server.services.services, "empty")
+
+	client := DialInProc(server)
+	defer client.Close()
+	var result string
+	assert.NoError(t, client.Call(&result, "dynamic_echo", "hello"))
+	assert.Equal(t, "hello", result)
+}
+
+type failingDynamicService struct{}
+
+func (s failingDynamicService) Callbacks() (map[string]interface{}, error) {
+	return nil, errors.New("arbitrary error")
+}
+
+func TestServerRegisterName_whenDynamicServiceFails(t *testing.T) {
+	server := NewServer()
+
+	assert.NoError(t, server.RegisterName("failing", failingDynamicService{}))
+
+	assert.NotContains(t, server.services.services, "failing")
+}
+
+func TestServerRegisterName_whenDynamicServiceHasInvalidCallback(t *testing.T) {
+	server := NewServer()
+
+	err := server.RegisterName("dynamic", testDynamicService{"foo": "bar"})
+
+	assert.EqualError(t, err, "callback foo of service rpc.testDynamicService is not a function")
+}
+
 func TestServer(t *testing.T) {
 	files, err := ioutil.ReadDir("testdata")
 	if err != nil {
@@ -189,6 +243,37 @@ func TestAuthenticateHttpRequest_whenTypical(t *testing.T) {
 	assert.NotNil(t, PreauthenticatedTokenFromContext(captor.context), "must be preauthenticated")
 }
 
+// forwardedForCaptor captures the client address received by the authentication manager
+type forwardedForCaptor struct {
+	stubAuthenticationManager
+	forwardedFor []string
+}
+
+func (s *forwardedForCaptor) Authenticate(ctx context.Context, token string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	s.forwardedFor = md.Get(GRPCForwardedForMetadata)
+	return s.stubAuthenticationManager.Authenticate(ctx, token)
+}
+
+func TestAuthenticateHttpRequest_whenBehindTrustedProxy(t *testing.T) {
+	authManager := &forwardedForCaptor{stubAuthenticationManager: stubAuthenticationManager{isEnabled: true}}
+	protectedServer := NewProtectedServer(authManager, false)
+	proxies, err := NewTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	protectedServer.SetTrustedProxies(proxies)
+	arbitraryRequest, _ := http.NewRequest("POST", "https://arbitraryUrl", nil)
+	arbitraryRequest.RemoteAddr = "10.0.0.1:1234"
+	arbitraryRequest.Header.Set(HttpForwardedForHeader, "192.0.2.7")
+	arbitraryRequest.Header.Set(HttpAuthorizationHeader, "arbitrary value")
+	captor := &securityContextConfigurerCaptor{}
+
+	protectedServer.authenticateHttpRequest(arbitraryRequest, captor)
+
+	assert.Equal(t, "192.0.2.7", ClientAddrFromContext(captor.context))
+	assert.Equal(t, []string{"192.0.2.7"}, authManager.forwardedFor, "the authentication manager must receive the client address")
+	assert.NotNil(t, PreauthenticatedTokenFromContext(captor.context), "must be preauthenticated")
+}
+
 func TestAuthenticateHttpRequest_whenAuthenticationManagerIsDisabled(t *testing.T) {
 	protectedServer := NewProtectedServer(&stubAuthenticationManager{false, nil}, false)
 	arbitraryRequest, _ := http.NewRequest("POST", "https://arbitraryUrl", nil)
@@ -319,3 +404,214 @@ func TestServerContextIdCaptured(t *testing.T) {
 	assert.NoErrorf(t, err, "read error:", err)
 	assert.Equalf(t, buf[:n], []byte(wantResp), "wrong response: %s", buf[:n])
 }
+
+// Quorum
+func TestServerBatchLimits(t *testing.T) {
+	testCases := []struct {
+		name    string
+		limits  BatchLimits
+		request string
+		want    string
+	}{
+		{
+			name:    "within limits",
+			limits:  BatchLimits{RequestLimit: 2, ResponseMaxSize: 1024},
+			request: `[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]},{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",2]}]`,
+			want:    `[{"jsonrpc":"2.0","id":1,"result":{"String":"x","Int":1,"Args":null}},{"jsonrpc":"2.0","id":2,"result":{"String":"x","Int":2,"Args":null}}]`,
+		},
+		{
+			name:    "too many messages",
+			limits:  BatchLimits{RequestLimit: 1},
+			request: `[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]},{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",2]}]`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch too large, limit is 1 messages"}}`,
+		},
+		{
+			name:    "response too large",
+			limits:  BatchLimits{ResponseMaxSize: 60},
+			request: `[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]},{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",2]},{"jsonrpc":"2.0","id":3,"method":"test_echo","params":["x",3]}]`,
+			want:    `[{"jsonrpc":"2.0","id":1,"result":{"String":"x","Int":1,"Args":null}},{"jsonrpc":"2.0","id":2,"error":{"code":-32003,"message":"batch response too large, limit is 60 bytes"}},{"jsonrpc":"2.0","id":3,"error":{"code":-32003,"message":"batch response too large, limit is 60 bytes"}}]`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer()
+			server.SetBatchLimits(tc.limits)
+			defer server.Stop()
+
+			clientConn, serverConn := net.Pipe()
+			defer clientConn.Close()
+			go server.ServeCodec(NewCodec(serverConn), 0)
+
+			clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+			_, err := io.WriteString(clientConn, tc.request+"\n")
+			assert.NoError(t, err)
+			got, err := bufio.NewReader(clientConn).ReadString('\n')
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, strings.TrimRight(got, "\r\n"))
+		})
+	}
+}
+
+func TestServerMethodTimeouts(t *testing.T) {
+	server := newTestServer()
+	server.SetMethodTimeouts(MethodTimeouts{"test_*": 50 * time.Millisecond, "test_echo": 0})
+	defer server.Stop()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(NewCodec(serverConn), 0)
+
+	clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(clientConn)
+
+	_, err := io.WriteString(clientConn, `{"jsonrpc":"2.0","id":1,"method":"test_block"}`+"\n")
+	assert.NoError(t, err)
+	got, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32002,"message":"test_block timed out after 50ms","data":{"method":"test_block","timeout":"50ms"}}}`, strings.TrimRight(got, "\r\n"))
+
+	_, err = io.WriteString(clientConn, `{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",1]}`+"\n")
+	assert.NoError(t, err)
+	got, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":2,"result":{"String":"x","Int":1,"Args":null}}`, strings.TrimRight(got, "\r\n"))
+}
+
+func TestParseMethodTimeouts(t *testing.T) {
+	timeouts, err := ParseMethodTimeouts("eth_call=5s, debug_*=1m")
+	assert.NoError(t, err)
+	assert.Equal(t, MethodTimeouts{"eth_call": 5 * time.Second, "debug_*": time.Minute}, timeouts)
+
+	d, ok := timeouts.timeout("debug_traceTransaction")
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+	_, ok = timeouts.timeout("eth_getBalance")
+	assert.False(t, ok)
+
+	_, err = ParseMethodTimeouts("eth_call")
+	assert.Error(t, err)
+	_, err = ParseMethodTimeouts("eth_call=5")
+	assert.Error(t, err)
+}
+
+func TestServerRateLimits(t *testing.T) {
+	server := newTestServer()
+	server.SetRateLimiter(NewRateLimiter(RateLimits{ConnRate: 0.001, ConnBurst: 1}))
+	defer server.Stop()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(NewCodec(serverConn), 0)
+
+	clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(clientConn)
+
+	_, err := io.WriteString(clientConn, `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`+"\n")
+	assert.NoError(t, err)
+	got, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"String":"x","Int":1,"Args":null}}`, strings.TrimRight(got, "\r\n"))
+
+	_, err = io.WriteString(clientConn, `{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",1]}`+"\n")
+	assert.NoError(t, err)
+	got, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":2,"error":{"code":-32005,"message":"rate limit exceeded"}}`, strings.TrimRight(got, "\r\n"))
+}
+
+func TestRateLimiter_Clients(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{ConnRate: 0.001, ConnBurst: 1})
+
+	assert.NoError(t, limiter.forClient("10.0.0.1:1000").take(context.Background()))
+	// the client limiter is shared by the requests and connections from the same host
+	assert.Error(t, limiter.forClient("10.0.0.1:2000").take(context.Background()))
+	assert.Error(t, limiter.forConn("10.0.0.1:3000").take(context.Background()))
+	assert.NoError(t, limiter.forClient("10.0.0.2:1000").take(context.Background()))
+	// the connections without address are limited on their own
+	assert.NoError(t, limiter.forConn("").take(context.Background()))
+	assert.NoError(t, limiter.forConn("").take(context.Background()))
+
+	assert.Nil(t, NewRateLimiter(RateLimits{}))
+}
+
+func TestRPCService_Discover(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	doc := (&RPCService{server}).Discover()
+
+	assert.Equal(t, OpenRPCVersion, doc.OpenRPC)
+	methods := make(map[string]OpenRPCMethod)
+	for _, m := range doc.Methods {
+		methods[m.Name] = m
+	}
+	assert.Contains(t, methods, "rpc_discover")
+	assert.Contains(t, methods, "rpc_modules")
+
+	echo, ok := methods["test_echo"]
+	if assert.True(t, ok) {
+		assert.Len(t, echo.Params, 3)
+		assert.Equal(t, JSONSchema{"type": "string"}, echo.Params[0].Schema)
+		assert.True(t, echo.Params[0].Required)
+		assert.Equal(t, JSONSchema{"type": "integer"}, echo.Params[1].Schema)
+		assert.False(t, echo.Params[2].Required)
+		assert.Equal(t, "object", echo.Result.Schema["type"])
+		assert.Contains(t, echo.Result.Schema["properties"], "Args")
+	}
+	assert.Nil(t, methods["test_noArgsRets"].Result)
+
+	subscribe, ok := methods["nftest_subscribe"]
+	if assert.True(t, ok) {
+		assert.Equal(t, []string{"hangSubscription", "someSubscription"}, subscribe.Params[0].Schema["enum"])
+	}
+}
+
+func TestServerDrain(t *testing.T) {
+	drain := NewDrain(MetadataApi)
+	server := newTestServer()
+	server.SetDrain(drain)
+	defer server.Stop()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(NewCodec(serverConn), 0)
+
+	clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(clientConn)
+
+	drain.Start()
+	_, err := io.WriteString(clientConn, `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`+"\n")
+	assert.NoError(t, err)
+	got, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32006,"message":"node is in maintenance"}}`, strings.TrimRight(got, "\r\n"))
+
+	_, err = io.WriteString(clientConn, `{"jsonrpc":"2.0","id":2,"method":"rpc_modules"}`+"\n")
+	assert.NoError(t, err)
+	got, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.NotContains(t, got, "error", "the exempt namespaces are served")
+
+	drain.Stop()
+	_, err = io.WriteString(clientConn, `{"jsonrpc":"2.0","id":3,"method":"test_echo","params":["x",1]}`+"\n")
+	assert.NoError(t, err)
+	got, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":3,"result":{"String":"x","Int":1,"Args":null}}`, strings.TrimRight(got, "\r\n"))
+}
+
+func TestDrain_Wait(t *testing.T) {
+	drain := NewDrain()
+	tracked, err := drain.enter("test_block")
+	assert.NoError(t, err)
+	assert.True(t, tracked)
+
+	drain.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*drainPollInterval)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, drain.Wait(ctx))
+
+	drain.leave()
+	assert.NoError(t, drain.Wait(context.Background()))
+	assert.Equal(t, 0, drain.InFlight())
+}
@@ -58,12 +58,40 @@ type callback struct {
 	isSubscribe bool           // true if this is a subscription callback
 }
 
+// Quorum
+//
+// DynamicService is implemented by services whose methods are only known at runtime
+// (e.g.: methods exposed by plugins). Callbacks is invoked when the service is registered
+// and each returned function is exposed as a method with the corresponding name.
+// Functions must satisfy the same criteria as methods of a regular service. A service
+// whose Callbacks fails is skipped, so that it doesn't prevent the other services from
+// being served.
+type DynamicService interface {
+	Callbacks() (map[string]interface{}, error)
+}
+
 func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 	rcvrVal := reflect.ValueOf(rcvr)
 	if name == "" {
 		return fmt.Errorf("no service name for type %s", rcvrVal.Type().String())
 	}
-	callbacks := suitableCallbacks(rcvrVal)
+	var callbacks map[string]*callback
+	if ds, ok := rcvr.(DynamicService); ok {
+		fns, err := ds.Callbacks()
+		if err != nil {
+			log.Error("Skipping RPC service whose methods can't be described", "service", name, "err", err)
+			return nil
+		}
+		if callbacks, err = dynamicCallbacks(ds, fns); err != nil {
+			return err
+		}
+		// it's legit for a dynamic service to have nothing to expose
+		if len(callbacks) == 0 {
+			return nil
+		}
+	} else {
+		callbacks = suitableCallbacks(rcvrVal)
+	}
 	if len(callbacks) == 0 {
 		return fmt.Errorf("service %T doesn't have any suitable methods/subscriptions to expose", rcvr)
 	}
@@ -131,6 +159,25 @@ func suitableCallbacks(receiver reflect.Value) map[string]*callback {
 	return callbacks
 }
 
+// Quorum
+//
+// dynamicCallbacks turns the functions provided by the given service into callbacks
+func dynamicCallbacks(ds DynamicService, fns map[string]interface{}) (map[string]*callback, error) {
+	callbacks := make(map[string]*callback)
+	for name, fn := range fns {
+		fnVal := reflect.ValueOf(fn)
+		if fnVal.Kind() != reflect.Func {
+			return nil, fmt.Errorf("callback %s of service %T is not a function", name, ds)
+		}
+		cb := newCallback(reflect.Value{}, fnVal)
+		if cb == nil || cb.isSubscribe {
+			return nil, fmt.Errorf("callback %s of service %T is not suitable", name, ds)
+		}
+		callbacks[formatName(name)] = cb
+	}
+	return callbacks, nil
+}
+
 // newCallback turns fn (a function) into a callback object. It returns nil if the function
 // is unsuitable as an RPC callback.
 func newCallback(receiver, fn reflect.Value) *callback {
@@ -0,0 +1,5 @@
+// Quorum
+// This test checks that each message of a batch is served with its own private state identifier.
+
+--> [{"jsonrpc":"2.0","id":"PS1/1","method":"test_echoCtxPSI","params":[]}, {"jsonrpc":"2.0","id":"PS2/2","method":"test_echoCtxPSI","params":[]}]
+<-- [{"jsonrpc":"2.0","id":"PS1/1","result":{"PSI":"PS1"}},{"jsonrpc":"2.0","id":"PS2/2","result":{"PSI":"PS2"}}]
@@ -30,6 +30,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/gorilla/websocket"
 )
 
@@ -42,6 +43,36 @@ const (
 
 var wsBufferPool = new(sync.Pool)
 
+// Quorum
+var wsDeadConnMeter = metrics.NewRegisteredMeter("rpc/ws/dead", nil)
+
+// WebsocketKeepalive configures the pings sent over idle websocket connections and the
+// reaping of the connections whose peer stopped answering them.
+type WebsocketKeepalive struct {
+	// PingInterval is the idle period after which a ping is sent
+	PingInterval time.Duration
+	// PongTimeout is the period to wait for the pong answering a ping, defaults to
+	// PingInterval
+	PongTimeout time.Duration
+	// MaxMissedPongs is the number of consecutive unanswered pings after which the
+	// connection is closed, 0 keeps the connection open
+	MaxMissedPongs int
+}
+
+// DefaultWebsocketKeepalive pings idle connections without ever closing them.
+var DefaultWebsocketKeepalive = WebsocketKeepalive{PingInterval: wsPingInterval}
+
+// withDefaults returns the keepalive settings with the missing periods defaulted.
+func (ka WebsocketKeepalive) withDefaults() WebsocketKeepalive {
+	if ka.PingInterval <= 0 {
+		ka.PingInterval = wsPingInterval
+	}
+	if ka.PongTimeout <= 0 {
+		ka.PongTimeout = ka.PingInterval
+	}
+	return ka
+}
+
 // WebsocketHandler returns a handler that serves JSON-RPC to WebSocket connections.
 //
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
@@ -59,7 +90,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := newWebsocketCodec(conn)
+		codec := newWebsocketCodec(conn, s.wsKeepalive)
+		// Quorum: identify the original client behind the trusted proxies
+		codec.(*websocketCodec).remote = s.trustedProxies.ClientAddr(r)
 		s.authenticateHttpRequest(r, codec)
 		s.ServeCodec(codec, 0)
 	})
@@ -184,7 +217,7 @@ func DialWebsocketWithCustomTLS(ctx context.Context, endpoint, origin string, tl
 			}
 			return nil, hErr
 		}
-		return newWebsocketCodec(conn), nil
+		return newWebsocketCodec(conn, DefaultWebsocketKeepalive), nil
 	})
 }
 
@@ -225,15 +258,25 @@ type websocketCodec struct {
 
 	wg        sync.WaitGroup
 	pingReset chan struct{}
+
+	// Quorum
+	keepalive WebsocketKeepalive
+	alive     chan struct{} // signals a pong or a message received from the peer
 }
 
-func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
+func newWebsocketCodec(conn *websocket.Conn, keepalive WebsocketKeepalive) ServerCodec {
 	conn.SetReadLimit(maxRequestContentLength)
 	wc := &websocketCodec{
 		jsonCodec: NewFuncCodec(conn, conn.WriteJSON, conn.ReadJSON).(*jsonCodec),
 		conn:      conn,
 		pingReset: make(chan struct{}, 1),
+		keepalive: keepalive.withDefaults(),
+		alive:     make(chan struct{}, 1),
 	}
+	conn.SetPongHandler(func(string) error {
+		wc.notifyAlive()
+		return nil
+	})
 	wc.wg.Add(1)
 	go wc.pingLoop()
 	return wc
@@ -256,27 +299,71 @@ func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}) error {
 	return err
 }
 
-// pingLoop sends periodic ping frames when the connection is idle.
+func (wc *websocketCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	msgs, batch, err := wc.jsonCodec.readBatch()
+	if err == nil {
+		wc.notifyAlive()
+	}
+	return msgs, batch, err
+}
+
+// notifyAlive notifies pingLoop that the peer is still answering.
+func (wc *websocketCodec) notifyAlive() {
+	select {
+	case wc.alive <- struct{}{}:
+	default:
+	}
+}
+
+// pingLoop sends periodic ping frames when the connection is idle, and closes the
+// connection when the peer misses too many consecutive pongs.
 func (wc *websocketCodec) pingLoop() {
-	var timer = time.NewTimer(wsPingInterval)
+	var (
+		timer    = time.NewTimer(wc.keepalive.PingInterval)
+		awaiting bool // a ping was sent and is not answered yet
+		missed   int
+	)
 	defer wc.wg.Done()
 	defer timer.Stop()
 
+	resetTimer := func(d time.Duration) {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d)
+	}
 	for {
 		select {
 		case <-wc.closed():
 			return
 		case <-wc.pingReset:
-			if !timer.Stop() {
-				<-timer.C
+			// a pending ping must still be answered in time
+			if !awaiting {
+				resetTimer(wc.keepalive.PingInterval)
 			}
-			timer.Reset(wsPingInterval)
+		case <-wc.alive:
+			awaiting, missed = false, 0
+			resetTimer(wc.keepalive.PingInterval)
 		case <-timer.C:
+			if awaiting {
+				missed++
+				if wc.keepalive.MaxMissedPongs > 0 && missed >= wc.keepalive.MaxMissedPongs {
+					log.Debug("Closing dead websocket connection", "remote", wc.remoteAddr(), "missed", missed)
+					wsDeadConnMeter.Mark(1)
+					// unblocks the reader, which releases the subscriptions of the connection
+					wc.conn.Close()
+					return
+				}
+			}
 			wc.jsonCodec.encMu.Lock()
 			wc.conn.SetWriteDeadline(time.Now().Add(wsPingWriteTimeout))
 			wc.conn.WriteMessage(websocket.PingMessage, nil)
 			wc.jsonCodec.encMu.Unlock()
-			timer.Reset(wsPingInterval)
+			awaiting = true
+			timer.Reset(wc.keepalive.PongTimeout)
 		}
 	}
 }
@@ -113,6 +113,53 @@ func TestWebsocketLargeCall(t *testing.T) {
 	}
 }
 
+// Quorum
+// This test checks that the server closes the connections whose client stopped
+// answering the pings, and keeps the others open.
+func TestWebsocketDeadConnectionReaping(t *testing.T) {
+	t.Parallel()
+
+	var (
+		srv     = newTestServer()
+		httpsrv = httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	srv.SetWebsocketKeepalive(WebsocketKeepalive{PingInterval: 50 * time.Millisecond, PongTimeout: 50 * time.Millisecond, MaxMissedPongs: 2})
+	defer srv.Stop()
+	defer httpsrv.Close()
+
+	dead, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer dead.Close()
+	dead.SetPingHandler(func(string) error { return nil })
+
+	alive, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer alive.Close()
+	// the answering client must keep reading to process the pings
+	aliveErr := make(chan error, 1)
+	go func() {
+		alive.SetReadDeadline(time.Now().Add(time.Second))
+		_, _, err := alive.ReadMessage()
+		aliveErr <- err
+	}()
+
+	dead.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = dead.ReadMessage()
+	if ne, ok := err.(net.Error); err == nil || ok && ne.Timeout() {
+		t.Fatal("dead connection was not closed")
+	}
+
+	err = <-aliveErr
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Fatalf("answering connection was closed: %v", err)
+	}
+}
+
 // This test checks that client handles WebSocket ping frames correctly.
 func TestClientWebsocketPing(t *testing.T) {
 	t.Parallel()
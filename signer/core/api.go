@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -534,7 +535,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 		msgs   *ValidationMessages
 	)
 	if args.IsPrivate {
-		msgs = new(ValidationMessages)
+		msgs = args.validatePrivate()
 	} else {
 		msgs, err = api.validator.ValidateTransaction(methodSelector, &args)
 		if err != nil {
@@ -563,6 +564,13 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 	}
 	// Log changes made by the UI to the signing-request
 	logDiff(&req, &result)
+	// Quorum
+	// the data of a private transaction is the hash of the payload already stored in the
+	// private transaction manager, changing it would make the transaction unprocessable
+	if args.IsPrivate && (!result.Transaction.IsPrivate || !bytes.Equal(result.Transaction.data(), args.data())) {
+		return nil, errors.New("the data and the privacy of a private transaction cannot be changed")
+	}
+	// /Quorum
 	var (
 		acc    accounts.Account
 		wallet accounts.Wallet
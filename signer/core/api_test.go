@@ -65,6 +65,10 @@ func (ui *headlessUi) ApproveTx(request *core.SignTxRequest) (core.SignTxRespons
 		newVal := big.NewInt(0).Add(&old, big.NewInt(1))
 		request.Transaction.Value = hexutil.Big(*newVal)
 		return core.SignTxResponse{request.Transaction, true}, nil
+	case "D": // modify the data
+		data := hexutil.Bytes(append([]byte{0x01}, *request.Transaction.Data...))
+		request.Transaction.Data = &data
+		return core.SignTxResponse{request.Transaction, true}, nil
 	default:
 		return core.SignTxResponse{request.Transaction, false}, nil
 	}
@@ -323,3 +327,45 @@ func TestSignTx(t *testing.T) {
 	}
 
 }
+
+func TestSignTx_whenPrivate(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control.approveCh <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := mkTestTx(common.NewMixedcaseAddress(list[0]))
+	payloadHash := hexutil.Bytes(common.BytesToEncryptedPayloadHash([]byte("encrypted payload hash")).Bytes())
+	tx.Data = &payloadHash
+	tx.IsPrivate = true
+	tx.PrivateFor = []string{"ROAZBWtSacxXQrOe3FGAqJDyJjFePR5ce4TSIzmJ0Bc="}
+
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	res, err := api.SignTransaction(context.Background(), tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedTx := &types.Transaction{}
+	if err := rlp.Decode(bytes.NewReader(res.Raw), parsedTx); err != nil {
+		t.Fatal(err)
+	}
+	if !parsedTx.IsPrivate() {
+		t.Error("Expected a private transaction")
+	}
+	if !bytes.Equal(parsedTx.Data(), payloadHash) {
+		t.Errorf("Expected the payload hash as data, got %x", parsedTx.Data())
+	}
+
+	// the UI must not change the payload hash
+	control.approveCh <- "D"
+	res, err = api.SignTransaction(context.Background(), tx, nil)
+	if res != nil {
+		t.Errorf("Expected nil-response, got %v", res)
+	}
+	if err == nil {
+		t.Error("Expected an error when the payload hash is changed")
+	}
+}
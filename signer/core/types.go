@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/private/engine"
 )
 
 type ValidationInfo struct {
@@ -78,6 +79,10 @@ type SendTxArgs struct {
 	Input *hexutil.Bytes `json:"input,omitempty"`
 	// QUORUM
 	IsPrivate bool `json:"isPrivate,omitempty"`
+	// the metadata of a private transaction, whose data is the hash of the encrypted payload
+	PrivateFrom string                 `json:"privateFrom,omitempty"`
+	PrivateFor  []string               `json:"privateFor,omitempty"`
+	PrivacyFlag engine.PrivacyFlagType `json:"privacyFlag,omitempty"`
 	// END QUORUM
 }
 
@@ -106,3 +111,39 @@ func (args *SendTxArgs) toTransaction() (tx *types.Transaction) {
 	}
 	return
 }
+
+// Quorum
+
+func (args *SendTxArgs) data() []byte {
+	if args.Data != nil {
+		return *args.Data
+	}
+	if args.Input != nil {
+		return *args.Input
+	}
+	return nil
+}
+
+// validatePrivate checks a private transaction. Its data being the hash of the encrypted
+// payload stored in the private transaction manager, the calldata cannot be decoded, the
+// parties to the transaction are reported instead.
+func (args *SendTxArgs) validatePrivate() *ValidationMessages {
+	msgs := new(ValidationMessages)
+	if data := args.data(); len(data) != 0 && len(data) != common.EncryptedPayloadHashLength {
+		msgs.Warn(fmt.Sprintf("Private transaction data is %d bytes long, not the hash of an encrypted payload", len(data)))
+	}
+	if args.PrivateFor == nil {
+		msgs.Info("Private transaction without metadata, the parties to the transaction are unknown")
+		return msgs
+	}
+	if args.PrivateFrom != "" {
+		msgs.Info(fmt.Sprintf("Private transaction from %s", args.PrivateFrom))
+	}
+	msgs.Info(fmt.Sprintf("Private transaction for [%s]", strings.Join(args.PrivateFor, ", ")))
+	if args.PrivacyFlag.IsNotStandardPrivate() {
+		msgs.Info(fmt.Sprintf("Privacy flag %d", args.PrivacyFlag))
+	}
+	return msgs
+}
+
+// /Quorum
@@ -0,0 +1,241 @@
+package statesync
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/clientserver"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// progressInterval is the interval of the logs of the sync progress
+const progressInterval = 8 * time.Second
+
+var syncedNodesMeter = metrics.NewRegisteredMeter("statesync/client/nodes", nil)
+
+// Client is the client side of the state sync protocol. It downloads from its
+// server the states of the fast sync pivot block: the public state if the fast sync
+// didn't, and the private states of the node.
+type Client struct {
+	conn  *clientserver.Client
+	db    ethdb.Database
+	psmr  mps.PrivateStateMetadataResolver
+	isMPS bool
+}
+
+// NewClient creates the client of a server. The states are written to db, the
+// private states synced are the ones of psmr.
+func NewClient(server *enode.Node, db ethdb.Database, psmr mps.PrivateStateMetadataResolver, isMPS bool) *Client {
+	return &Client{
+		conn:  clientserver.NewClient(spec, server.ID()),
+		db:    db,
+		psmr:  psmr,
+		isMPS: isMPS,
+	}
+}
+
+// Close aborts the pending and future syncs
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+func (c *Client) status() *statusData {
+	return &statusData{Version: ProtocolVersion, Client: true}
+}
+
+func (c *Client) handle(p *p2p.Peer, rw p2p.MsgReadWriter, peerStatus *statusData) error {
+	if peerStatus.Client || !c.conn.IsServer(p) {
+		return clientserver.Idle(rw)
+	}
+	log.Info("Connected to the state sync server", "peer", p.ID())
+	return c.conn.Serve(rw, func(msg p2p.Msg) (uint64, interface{}, error) {
+		switch msg.Code {
+		case RootsMsg:
+			var roots rootsData
+			err := msg.Decode(&roots)
+			return roots.ReqID, &roots, err
+		case NodeDataMsg:
+			var nodes nodeDataData
+			err := msg.Decode(&nodes)
+			return nodes.ReqID, &nodes, err
+		default:
+			return 0, nil, errUnexpectedMsg
+		}
+	})
+}
+
+// SyncPrivateStates downloads the states of the block of the header from the
+// server, and links the private states to the block. The public state is verified
+// against the root of the header, the private states against the roots disclosed
+// by the server.
+func (c *Client) SyncPrivateStates(header *types.Header, cancel <-chan struct{}) error {
+	if err := c.conn.WaitConnected(cancel); err != nil {
+		return err
+	}
+	psis := c.psis()
+	resp, err := c.conn.Request(GetRootsMsg, func(reqID uint64) interface{} {
+		return &getRootsData{ReqID: reqID, BlockHash: header.Hash(), PSIs: psis}
+	}, cancel)
+	if err != nil {
+		return err
+	}
+	roots, ok := resp.(*rootsData)
+	if !ok {
+		return errUnexpectedMsg
+	}
+	if !roots.Available {
+		return fmt.Errorf("the state sync server doesn't serve the states of block #%d %x", header.Number, header.Hash())
+	}
+	if roots.MPS != c.isMPS {
+		return fmt.Errorf("multiple private states mismatch with the state sync server: server %t, client %t", roots.MPS, c.isMPS)
+	}
+	log.Info("Syncing the states of the pivot block", "number", header.Number, "hash", header.Hash(), "private", len(roots.Private))
+
+	if err := c.syncTrie("public", state.NewStateSync(header.Root, c.db, nil), cancel); err != nil {
+		return err
+	}
+	if err := c.syncExtraData(header.Root, roots.PublicExtraDataRoot, cancel); err != nil {
+		return err
+	}
+	for _, private := range roots.Private {
+		if private.Root != (common.Hash{}) {
+			if err := c.syncTrie(private.PSI, state.NewStateSync(private.Root, c.db, nil), cancel); err != nil {
+				return err
+			}
+		}
+		if err := c.syncExtraData(private.Root, private.ExtraDataRoot, cancel); err != nil {
+			return err
+		}
+	}
+	return c.writePrivateRoots(header, roots)
+}
+
+// psis returns the private states synced, including the empty state of the
+// multiple private states
+func (c *Client) psis() []string {
+	var psis []string
+	for _, psi := range c.psmr.PSIs() {
+		psis = append(psis, psi.String())
+	}
+	if c.isMPS {
+		psis = append(psis, types.EmptyPrivateStateIdentifier.String())
+	}
+	return psis
+}
+
+// syncExtraData downloads the account extra data of a state and links it to the state
+func (c *Client) syncExtraData(stateRoot, root common.Hash, cancel <-chan struct{}) error {
+	if root == (common.Hash{}) || root == emptyRoot {
+		return nil
+	}
+	if err := c.syncTrie("extra data", trie.NewSync(root, c.db, nil, nil), cancel); err != nil {
+		return err
+	}
+	return rawdb.WriteRootHashMapping(c.db, stateRoot, root)
+}
+
+// writePrivateRoots links the private states to the block, in a trie of private
+// states rebuilt from the private states of the client if it runs multiple private
+// states
+func (c *Client) writePrivateRoots(header *types.Header, roots *rootsData) error {
+	if !c.isMPS {
+		for _, private := range roots.Private {
+			if err := rawdb.WritePrivateStateRoot(c.db, header.Root, private.Root); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	triedb := trie.NewDatabase(c.db)
+	tr, err := trie.New(common.Hash{}, triedb)
+	if err != nil {
+		return err
+	}
+	for _, private := range roots.Private {
+		if err := tr.TryUpdate([]byte(private.PSI), private.Root.Bytes()); err != nil {
+			return err
+		}
+	}
+	root, err := tr.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if err := triedb.Commit(root, false, nil); err != nil {
+		return err
+	}
+	return rawdb.WritePrivateStatesTrieRoot(c.db, header.Root, root)
+}
+
+// syncTrie downloads the nodes missing from the database of a trie, and the
+// contract codes for a state trie. The nodes are verified against their hash as
+// they are scheduled from the root down.
+func (c *Client) syncTrie(name string, sched *trie.Sync, cancel <-chan struct{}) error {
+	var (
+		pending  []common.Hash // requested from the server, but not delivered yet
+		synced   int
+		lastLog  = time.Now()
+		start    = time.Now()
+		maxNodes = maxNodesPerRequest
+	)
+	for sched.Pending() > 0 {
+		if len(pending) < maxNodes {
+			nodes, _, codes := sched.Missing(maxNodes - len(pending))
+			pending = append(append(pending, nodes...), codes...)
+		}
+		hashes := pending
+		resp, err := c.conn.Request(GetNodeDataMsg, func(reqID uint64) interface{} {
+			return &getNodeDataData{ReqID: reqID, Hashes: hashes}
+		}, cancel)
+		if err != nil {
+			return err
+		}
+		data, ok := resp.(*nodeDataData)
+		if !ok {
+			return errUnexpectedMsg
+		}
+		delivered := make(map[common.Hash]bool, len(data.Data))
+		for _, blob := range data.Data {
+			hash := crypto.Keccak256Hash(blob)
+			if err := sched.Process(trie.SyncResult{Hash: hash, Data: blob}); err != nil && err != trie.ErrAlreadyProcessed {
+				return fmt.Errorf("invalid %s state node %x from the state sync server: %v", name, hash, err)
+			}
+			delivered[hash] = true
+		}
+		if len(delivered) == 0 {
+			return fmt.Errorf("the state sync server doesn't have the %d missing nodes of the %s state", len(pending), name)
+		}
+		batch := c.db.NewBatch()
+		if err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		undelivered := pending[:0]
+		for _, hash := range pending {
+			if !delivered[hash] {
+				undelivered = append(undelivered, hash)
+			}
+		}
+		pending = undelivered
+		synced += len(delivered)
+		syncedNodesMeter.Mark(int64(len(delivered)))
+		if time.Since(lastLog) > progressInterval {
+			log.Info("Syncing state", "state", name, "nodes", synced, "pending", sched.Pending(), "elapsed", common.PrettyDuration(time.Since(start)))
+			lastLog = time.Now()
+		}
+	}
+	log.Info("Synced state", "state", name, "nodes", synced, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package statesync implements the state sync sub-protocol, which transfers the
+// state of a block from a permissioned node to a node being stood up, e.g. to
+// replace a node, instead of replaying the whole chain.
+//
+// The eth fast sync downloads the blocks and the receipts, and the public state of
+// the pivot block, verified against the state root of its header. The private
+// states are not part of the blocks, so before committing the pivot block the
+// client requests from its server the roots of the public and private states at
+// the pivot, then downloads the trie nodes and the contract codes of each state.
+// Every node is verified against its hash, from the roots down.
+//
+// The server only serves the clients it is configured with an entitlement for, by
+// their node ID authenticated by the p2p handshake, and only discloses the roots of
+// the private states each client is entitled to. The nodes are addressed by their
+// hash, which a client only learns from the nodes of the states it was disclosed.
+package statesync
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/clientserver"
+)
+
+const (
+	ProtocolName    = "qstatesync"
+	ProtocolVersion = 1
+	ProtocolLength  = 5
+
+	// maximum size of a protocol message
+	protocolMaxMsgSize = 10 * 1024 * 1024
+
+	// maximum number of trie nodes and codes requested in a message
+	maxNodesPerRequest = 384
+
+	// soft limit of the size of the trie nodes and codes answered in a message
+	softResponseLimit = 2 * 1024 * 1024
+
+	// handshakeTimeout is how long the status of the peer is waited for
+	handshakeTimeout = 5 * time.Second
+
+	// requestTimeout is how long an answer of the server is waited for
+	requestTimeout = 10 * time.Second
+)
+
+// state sync protocol message codes
+const (
+	StatusMsg      = 0x00
+	GetRootsMsg    = 0x01
+	RootsMsg       = 0x02
+	GetNodeDataMsg = 0x03
+	NodeDataMsg    = 0x04
+)
+
+var (
+	ErrNotAuthorized      = errors.New("state sync client not authorized for the private state")
+	ErrNotEntitled        = errors.New("state sync client has no entitlement on the server")
+	ErrServerDisconnected = clientserver.ErrServerDisconnected
+	ErrClientClosed       = clientserver.ErrClientClosed
+	errUnexpectedMsg      = clientserver.ErrUnexpectedMsg
+	errCanceled           = clientserver.ErrCanceled
+)
+
+// spec is the sub-protocol, the status of the peers being a statusData
+var spec = &clientserver.Spec{
+	Name:             ProtocolName,
+	Version:          ProtocolVersion,
+	Length:           ProtocolLength,
+	StatusCode:       StatusMsg,
+	MaxMsgSize:       protocolMaxMsgSize,
+	HandshakeTimeout: handshakeTimeout,
+	RequestTimeout:   requestTimeout,
+	NewStatus:        func() clientserver.Status { return new(statusData) },
+}
+
+// statusData is the first message sent by both sides
+type statusData struct {
+	Version uint32
+	Client  bool
+}
+
+func (s *statusData) ProtocolVersion() uint32 {
+	return s.Version
+}
+
+// getRootsData requests the roots of the states at a block
+type getRootsData struct {
+	ReqID     uint64
+	BlockHash common.Hash
+	PSIs      []string
+}
+
+// rootsData answers a getRootsData. Available is false if the server doesn't have
+// the states of the block, or the client is not entitled to one of the private states.
+type rootsData struct {
+	ReqID     uint64
+	Available bool
+	MPS       bool // whether the server runs multiple private states
+	// PublicExtraDataRoot is the root of the account extra data of the public state
+	PublicExtraDataRoot common.Hash
+	Private             []*PrivateRoot
+}
+
+// PrivateRoot is the root of a private state at a block
+type PrivateRoot struct {
+	PSI           string
+	Root          common.Hash
+	ExtraDataRoot common.Hash // root of the account extra data of the private state
+}
+
+// getNodeDataData requests trie nodes or contract codes by their hash
+type getNodeDataData struct {
+	ReqID  uint64
+	Hashes []common.Hash
+}
+
+// nodeDataData answers a getNodeDataData with the nodes and codes the server has,
+// which may be fewer than requested
+type nodeDataData struct {
+	ReqID uint64
+	Data  [][]byte
+}
+
+// Handler serves the state sync protocol for one side, client or server
+type Handler interface {
+	// status returns the status sent to the peers
+	status() *statusData
+	// handle serves a peer once the status were exchanged. It returns when the
+	// connection is torn down
+	handle(p *p2p.Peer, rw p2p.MsgReadWriter, peerStatus *statusData) error
+}
+
+// MakeProtocol returns the state sync sub-protocol served by the handler
+func MakeProtocol(h Handler) p2p.Protocol {
+	return spec.Protocol(func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+		peerStatus, err := spec.Handshake(rw, h.status())
+		if err != nil {
+			p.Log().Debug("State sync handshake failed", "err", err)
+			return err
+		}
+		return h.handle(p, rw, peerStatus.(*statusData))
+	})
+}
@@ -0,0 +1,359 @@
+package statesync
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/clientserver"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	servedNodesMeter = metrics.NewRegisteredMeter("statesync/server/nodes", nil)
+	servedBytesMeter = metrics.NewRegisteredMeter("statesync/server/bytes", nil)
+)
+
+var (
+	// emptyRoot is the root of an empty trie
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	// emptyCodeHash is the hash of empty code
+	emptyCodeHash = crypto.Keccak256(nil)
+)
+
+// ServerConfig configures a state sync server
+type ServerConfig struct {
+	// Entitlements restrict the private states served to the clients, by the hex node
+	// ID of the client. The node ID is authenticated by the p2p handshake, only the
+	// clients with an entitlement are served.
+	Entitlements map[string]*Entitlement `toml:",omitempty"`
+}
+
+// Entitlement is the private states a client is entitled to
+type Entitlement struct {
+	// PSIs are the private states served to the client, only the default private
+	// state if empty
+	PSIs []string `json:"psis,omitempty" toml:",omitempty"`
+}
+
+func (e *Entitlement) allowsPSI(psi string, isMPS bool) bool {
+	if isMPS && psi == types.EmptyPrivateStateIdentifier.String() {
+		// the empty state is shared by all the private states
+		return true
+	}
+	if len(e.PSIs) == 0 {
+		return psi == types.DefaultPrivateStateIdentifier.String()
+	}
+	for _, allowed := range e.PSIs {
+		if allowed == psi {
+			return true
+		}
+	}
+	return false
+}
+
+// Server is the server side of the state sync protocol. It serves the clients with
+// the states of the blocks it has, restricted to the private states the entitlement
+// of each client allows.
+type Server struct {
+	db            ethdb.Database
+	publicTrieDB  *trie.Database
+	privateTrieDB *trie.Database
+	entitlements  map[enode.ID]*Entitlement
+}
+
+// NewServer creates a server of the public states of publicTrieDB and of the
+// private states of privateTrieDB
+func NewServer(db ethdb.Database, publicTrieDB, privateTrieDB *trie.Database, config *ServerConfig) (*Server, error) {
+	s := &Server{
+		db:            db,
+		publicTrieDB:  publicTrieDB,
+		privateTrieDB: privateTrieDB,
+		entitlements:  make(map[enode.ID]*Entitlement),
+	}
+	if config == nil {
+		config = new(ServerConfig)
+	}
+	for hexID, entitlement := range config.Entitlements {
+		id, err := enode.ParseID(hexID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node ID of state sync client entitlement %q: %v", hexID, err)
+		}
+		if entitlement == nil {
+			entitlement = new(Entitlement)
+		}
+		s.entitlements[id] = entitlement
+	}
+	if len(s.entitlements) == 0 {
+		log.Warn("The state sync server has no client entitlements, no client will be served")
+	}
+	return s, nil
+}
+
+func (s *Server) status() *statusData {
+	return &statusData{Version: ProtocolVersion}
+}
+
+// nodeKind is the kind of the data served by hash
+type nodeKind uint8
+
+const (
+	stateNode nodeKind = iota // node of a state trie, whose leaves are accounts
+	dataNode                  // node of a trie whose leaves are data, e.g. a storage trie
+	codeNode                  // contract code
+)
+
+// session is the states served to a client: the nodes reachable from the roots
+// served to the client, as the client syncs the tries from their roots
+type session struct {
+	entitlement *Entitlement
+	// allowed are the nodes the client can request: the roots served and the children
+	// of the nodes served, forgotten once served
+	allowed map[common.Hash]nodeKind
+}
+
+func newSession(entitlement *Entitlement) *session {
+	return &session{entitlement: entitlement, allowed: make(map[common.Hash]nodeKind)}
+}
+
+func (sess *session) allow(hash common.Hash, kind nodeKind) {
+	if hash == (common.Hash{}) || hash == emptyRoot || (kind == codeNode && bytes.Equal(hash[:], emptyCodeHash)) {
+		return
+	}
+	sess.allowed[hash] = kind
+}
+
+// allowChildren allows the nodes referenced by a trie node served to the client: its
+// children and, for the leaves of a state trie, the storage trie and the code of the
+// account
+func (sess *session) allowChildren(blob []byte, kind nodeKind) {
+	elems, _, err := rlp.SplitList(blob)
+	if err != nil {
+		return
+	}
+	switch n, _ := rlp.CountValues(elems); n {
+	case 2:
+		key, val, err := rlp.SplitString(elems)
+		if err != nil || len(key) == 0 {
+			return
+		}
+		// the flag of the compact key of a leaf has the terminator bit set
+		if key[0]&0x20 == 0 {
+			sess.allowRef(val, kind)
+			return
+		}
+		if kind != stateNode {
+			return
+		}
+		enc, _, err := rlp.SplitString(val)
+		if err != nil {
+			return
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(enc, &account); err != nil {
+			return
+		}
+		sess.allow(account.Root, dataNode)
+		sess.allow(common.BytesToHash(account.CodeHash), codeNode)
+	case 17:
+		for i := 0; i < 16; i++ {
+			_, _, rest, err := rlp.Split(elems)
+			if err != nil {
+				return
+			}
+			sess.allowRef(elems[:len(elems)-len(rest)], kind)
+			elems = rest
+		}
+	}
+}
+
+// allowRef allows the node referenced by a child of a trie node, by hash or embedded
+// in its parent
+func (sess *session) allowRef(ref []byte, kind nodeKind) {
+	k, content, _, err := rlp.Split(ref)
+	switch {
+	case err != nil:
+	case k == rlp.List:
+		sess.allowChildren(ref, kind)
+	case len(content) == common.HashLength:
+		sess.allow(common.BytesToHash(content), kind)
+	}
+}
+
+func (s *Server) handle(p *p2p.Peer, rw p2p.MsgReadWriter, peerStatus *statusData) error {
+	if !peerStatus.Client {
+		return clientserver.Idle(rw)
+	}
+	entitlement := s.entitlements[p.ID()]
+	if entitlement == nil {
+		p.Log().Warn("Rejected state sync client", "err", ErrNotEntitled)
+		return ErrNotEntitled
+	}
+	sess := newSession(entitlement)
+	p.Log().Info("Serving state sync client")
+
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		if msg.Size > protocolMaxMsgSize {
+			msg.Discard()
+			return errUnexpectedMsg
+		}
+		switch msg.Code {
+		case GetRootsMsg:
+			var req getRootsData
+			err = msg.Decode(&req)
+			msg.Discard()
+			if err != nil {
+				return err
+			}
+			resp, err := s.roots(sess, req.BlockHash, req.PSIs)
+			if err != nil {
+				p.Log().Warn("State sync client requested unavailable states", "block", req.BlockHash, "err", err)
+				resp = &rootsData{}
+			}
+			resp.ReqID = req.ReqID
+			if err := p2p.Send(rw, RootsMsg, resp); err != nil {
+				return err
+			}
+		case GetNodeDataMsg:
+			var req getNodeDataData
+			err = msg.Decode(&req)
+			msg.Discard()
+			if err != nil {
+				return err
+			}
+			if len(req.Hashes) > maxNodesPerRequest {
+				return fmt.Errorf("%w: %d nodes requested, at most %d", errUnexpectedMsg, len(req.Hashes), maxNodesPerRequest)
+			}
+			if err := p2p.Send(rw, NodeDataMsg, &nodeDataData{ReqID: req.ReqID, Data: s.nodeData(sess, req.Hashes)}); err != nil {
+				return err
+			}
+		default:
+			msg.Discard()
+			return errUnexpectedMsg
+		}
+	}
+}
+
+// roots returns the roots of the public state and of the private states psis at a
+// block, failing if the entitlement doesn't allow one of the private states. The
+// client is then allowed to sync the states from the roots.
+func (s *Server) roots(sess *session, blockHash common.Hash, psis []string) (*rootsData, error) {
+	number := rawdb.ReadHeaderNumber(s.db, blockHash)
+	if number == nil {
+		return nil, fmt.Errorf("unknown block")
+	}
+	header := rawdb.ReadHeader(s.db, blockHash, *number)
+	if header == nil {
+		return nil, fmt.Errorf("unknown block")
+	}
+	if _, err := s.publicTrieDB.Node(header.Root); err != nil {
+		return nil, fmt.Errorf("public state: %v", err)
+	}
+	mpsRoot := rawdb.GetPrivateStatesTrieRoot(s.db, header.Root)
+	resp := &rootsData{
+		Available:           true,
+		MPS:                 mpsRoot != (common.Hash{}),
+		PublicExtraDataRoot: rawdb.GetAccountExtraDataRoot(s.db, header.Root),
+	}
+	var mpsTrie *trie.Trie
+	if resp.MPS {
+		var err error
+		if mpsTrie, err = trie.New(mpsRoot, s.privateTrieDB); err != nil {
+			return nil, fmt.Errorf("trie of private states: %v", err)
+		}
+	}
+	for _, psi := range psis {
+		if !sess.entitlement.allowsPSI(psi, resp.MPS) {
+			return nil, fmt.Errorf("%w: %s", ErrNotAuthorized, psi)
+		}
+		var root common.Hash
+		if resp.MPS {
+			enc, err := mpsTrie.TryGet([]byte(psi))
+			if err != nil {
+				return nil, fmt.Errorf("private state %s: %v", psi, err)
+			}
+			if enc == nil {
+				// the private state doesn't exist yet, the client branches it from
+				// the empty state
+				continue
+			}
+			root = common.BytesToHash(enc)
+		} else {
+			if psi != types.DefaultPrivateStateIdentifier.String() {
+				return nil, fmt.Errorf("unknown private state %s", psi)
+			}
+			root = rawdb.GetPrivateStateRoot(s.db, header.Root)
+		}
+		if root != (common.Hash{}) && root != emptyRoot {
+			if _, err := s.privateTrieDB.Node(root); err != nil {
+				return nil, fmt.Errorf("private state %s: %v", psi, err)
+			}
+		}
+		resp.Private = append(resp.Private, &PrivateRoot{
+			PSI:           psi,
+			Root:          root,
+			ExtraDataRoot: rawdb.GetAccountExtraDataRoot(s.db, root),
+		})
+	}
+	sess.allow(header.Root, stateNode)
+	sess.allow(resp.PublicExtraDataRoot, dataNode)
+	for _, private := range resp.Private {
+		sess.allow(private.Root, stateNode)
+		sess.allow(private.ExtraDataRoot, dataNode)
+	}
+	return resp, nil
+}
+
+// nodeData returns the trie nodes and contract codes the server has, of the states
+// served to the client. The nodes not reachable from the roots served to the client,
+// e.g. of the private states the client is not entitled to, are left out.
+func (s *Server) nodeData(sess *session, hashes []common.Hash) [][]byte {
+	var (
+		data [][]byte
+		size int
+	)
+	for _, hash := range hashes {
+		if size >= softResponseLimit {
+			break
+		}
+		kind, ok := sess.allowed[hash]
+		if !ok {
+			continue
+		}
+		var entry []byte
+		if kind == codeNode {
+			entry = rawdb.ReadCodeWithPrefix(s.db, hash)
+		} else {
+			var err error
+			entry, err = s.publicTrieDB.Node(hash)
+			if len(entry) == 0 || err != nil {
+				entry, _ = s.privateTrieDB.Node(hash)
+			}
+		}
+		if len(entry) > 0 {
+			delete(sess.allowed, hash)
+			if kind != codeNode {
+				sess.allowChildren(entry, kind)
+			}
+			data = append(data, entry)
+			size += len(entry)
+		}
+	}
+	servedNodesMeter.Mark(int64(len(data)))
+	servedBytesMeter.Mark(int64(size))
+	return data
+}
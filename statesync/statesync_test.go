@@ -0,0 +1,211 @@
+package statesync
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAddr = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testKey  = common.HexToHash("0x01")
+)
+
+type stubPSMR struct {
+	mps.PrivateStateMetadataResolver
+	psis []types.PrivateStateIdentifier
+}
+
+func (r *stubPSMR) PSIs() []types.PrivateStateIdentifier {
+	return r.psis
+}
+
+// writeState commits to the database a state with a contract, its code, storage and
+// managed parties tagged by the seed
+func writeState(t *testing.T, sdb state.Database, seed string) common.Hash {
+	statedb, err := state.New(common.Hash{}, sdb, nil)
+	require.NoError(t, err)
+	statedb.SetBalance(testAddr, big.NewInt(int64(len(seed))))
+	statedb.SetCode(testAddr, []byte("code of "+seed))
+	statedb.SetState(testAddr, testKey, common.BytesToHash([]byte(seed)))
+	statedb.SetManagedParties(testAddr, []string{seed})
+	root, err := statedb.Commit(false)
+	require.NoError(t, err)
+	require.NoError(t, sdb.TrieDB().Commit(root, false, nil))
+	return root
+}
+
+func assertState(t *testing.T, db ethdb.Database, root common.Hash, seed string) {
+	statedb, err := state.New(root, state.NewDatabase(db), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("code of "+seed), statedb.GetCode(testAddr))
+	assert.Equal(t, common.BytesToHash([]byte(seed)), statedb.GetState(testAddr, testKey))
+	managedParties, err := statedb.GetManagedParties(testAddr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{seed}, managedParties)
+}
+
+// newTestServer returns a server of a block whose private states are the ones of
+// the PSIs, the roots of the private states by PSI
+func newTestServer(t *testing.T, isMPS bool, psis []string, config *ServerConfig) (*Server, *types.Header, map[string]common.Hash) {
+	db := rawdb.NewMemoryDatabase()
+	sdb := state.NewDatabase(db)
+	header := &types.Header{Number: big.NewInt(1), Root: writeState(t, sdb, "public")}
+	rawdb.WriteHeader(db, header)
+
+	roots := make(map[string]common.Hash)
+	for _, psi := range psis {
+		roots[psi] = writeState(t, sdb, psi)
+	}
+	if isMPS {
+		tr, err := trie.New(common.Hash{}, sdb.TrieDB())
+		require.NoError(t, err)
+		for psi, root := range roots {
+			require.NoError(t, tr.TryUpdate([]byte(psi), root.Bytes()))
+		}
+		mpsRoot, err := tr.Commit(nil)
+		require.NoError(t, err)
+		require.NoError(t, sdb.TrieDB().Commit(mpsRoot, false, nil))
+		require.NoError(t, rawdb.WritePrivateStatesTrieRoot(db, header.Root, mpsRoot))
+	} else {
+		require.NoError(t, rawdb.WritePrivateStateRoot(db, header.Root, roots[types.DefaultPrivateStateIdentifier.String()]))
+	}
+	server, err := NewServer(db, sdb.TrieDB(), sdb.TrieDB(), config)
+	require.NoError(t, err)
+	return server, header, roots
+}
+
+// connect runs the protocol between a client and a server, the client having the
+// node ID 1
+func connect(t *testing.T, client *Client, server *Server) func() {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	serverNode := enode.NewV4(&key.PublicKey, nil, 0, 0)
+	client.serverID = serverNode.ID()
+	clientRW, serverRW := p2p.MsgPipe()
+	go MakeProtocol(client).Run(p2p.NewPeer(serverNode.ID(), "server", nil), clientRW)
+	go MakeProtocol(server).Run(p2p.NewPeer(enode.ID{1}, "client", nil), serverRW)
+	return func() {
+		client.Close()
+		clientRW.Close()
+	}
+}
+
+func TestClient_SyncPrivateStates(t *testing.T) {
+	private := types.DefaultPrivateStateIdentifier.String()
+	server, header, roots := newTestServer(t, false, []string{private}, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {}},
+	})
+	db := rawdb.NewMemoryDatabase()
+	client := NewClient(enode.SignNull(new(enr.Record), enode.ID{9}), db, &stubPSMR{psis: []types.PrivateStateIdentifier{types.DefaultPrivateStateIdentifier}}, false)
+	defer connect(t, client, server)()
+
+	err := client.SyncPrivateStates(header, nil)
+
+	require.NoError(t, err)
+	assertState(t, db, header.Root, "public")
+	assert.Equal(t, roots[private], rawdb.GetPrivateStateRoot(db, header.Root))
+	assertState(t, db, roots[private], private)
+}
+
+func TestClient_SyncPrivateStates_onlyEntitledStates(t *testing.T) {
+	server, header, roots := newTestServer(t, true, []string{"tenantA", "tenantB"}, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {PSIs: []string{"tenantA"}}},
+	})
+	db := rawdb.NewMemoryDatabase()
+	client := NewClient(enode.SignNull(new(enr.Record), enode.ID{9}), db, &stubPSMR{psis: []types.PrivateStateIdentifier{"tenantA"}}, true)
+	defer connect(t, client, server)()
+
+	err := client.SyncPrivateStates(header, nil)
+
+	require.NoError(t, err)
+	mpsTrie, err := trie.New(rawdb.GetPrivateStatesTrieRoot(db, header.Root), trie.NewDatabase(db))
+	require.NoError(t, err)
+	enc, err := mpsTrie.TryGet([]byte("tenantA"))
+	require.NoError(t, err)
+	assert.Equal(t, roots["tenantA"], common.BytesToHash(enc))
+	assertState(t, db, roots["tenantA"], "tenantA")
+	enc, err = mpsTrie.TryGet([]byte("tenantB"))
+	require.NoError(t, err)
+	assert.Nil(t, enc)
+	has, err := db.Has(roots["tenantB"].Bytes())
+	require.NoError(t, err)
+	assert.False(t, has, "the private state of another tenant was synced")
+}
+
+func TestClient_SyncPrivateStates_whenNotAuthorized(t *testing.T) {
+	server, header, _ := newTestServer(t, true, []string{"tenantA", "tenantB"}, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {PSIs: []string{"tenantA"}}},
+	})
+	client := NewClient(enode.SignNull(new(enr.Record), enode.ID{9}), rawdb.NewMemoryDatabase(), &stubPSMR{psis: []types.PrivateStateIdentifier{"tenantA", "tenantB"}}, true)
+	defer connect(t, client, server)()
+
+	err := client.SyncPrivateStates(header, nil)
+
+	assert.Error(t, err)
+	_, err = server.roots(newSession(server.entitlements[enode.ID{1}]), header.Hash(), []string{"tenantB"})
+	assert.True(t, errors.Is(err, ErrNotAuthorized))
+}
+
+func TestServer_nodeData_onlyNodesReachableFromEntitledRoots(t *testing.T) {
+	server, header, roots := newTestServer(t, true, []string{"tenantA", "tenantB"}, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {PSIs: []string{"tenantA"}}},
+	})
+	sess := newSession(server.entitlements[enode.ID{1}])
+	codeA, codeB := crypto.Keccak256Hash([]byte("code of tenantA")), crypto.Keccak256Hash([]byte("code of tenantB"))
+
+	assert.Empty(t, server.nodeData(sess, []common.Hash{header.Root, roots["tenantA"]}), "nodes served before their roots")
+
+	_, err := server.roots(sess, header.Hash(), []string{"tenantA"})
+	require.NoError(t, err)
+
+	assert.Empty(t, server.nodeData(sess, []common.Hash{roots["tenantB"], codeB}), "nodes of another tenant served")
+	assert.Empty(t, server.nodeData(sess, []common.Hash{codeA}), "code served before its account")
+	assert.Len(t, server.nodeData(sess, []common.Hash{roots["tenantA"]}), 1)
+	assert.Equal(t, [][]byte{[]byte("code of tenantA")}, server.nodeData(sess, []common.Hash{codeA, codeB}))
+}
+
+func TestServer_rejectsClientWithoutEntitlement(t *testing.T) {
+	server, _, _ := newTestServer(t, false, []string{types.DefaultPrivateStateIdentifier.String()}, &ServerConfig{
+		Entitlements: map[string]*Entitlement{enode.ID{1}.String(): {}},
+	})
+	clientRW, serverRW := p2p.MsgPipe()
+	defer clientRW.Close()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- MakeProtocol(server).Run(p2p.NewPeer(enode.ID{2}, "client", nil), serverRW)
+	}()
+	client := NewClient(enode.SignNull(new(enr.Record), enode.ID{9}), rawdb.NewMemoryDatabase(), &stubPSMR{}, false)
+	defer client.Close()
+	go MakeProtocol(client).Run(p2p.NewPeer(enode.ID{9}, "server", nil), clientRW)
+
+	select {
+	case err := <-errc:
+		assert.Equal(t, ErrNotEntitled, err)
+	case <-time.After(time.Second):
+		t.Fatal("the client without entitlement was served")
+	}
+}
+
+func TestNewServer_whenInvalidEntitlementNodeID(t *testing.T) {
+	_, err := NewServer(rawdb.NewMemoryDatabase(), nil, nil, &ServerConfig{
+		Entitlements: map[string]*Entitlement{"node": {}},
+	})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package tracing records OpenTelemetry spans of the transaction and block processing
+// and exports them to an OTLP collector, so that operators can see where the end-to-end
+// latency is spent.
+//
+// Tracing is disabled by default, in which case starting a span returns a nil span and
+// all the span methods are no-ops.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// Enabled is checked by the functions starting spans.
+var Enabled = false
+
+// exporter receives the ended spans, set by Setup
+var exporter spanExporter
+
+type spanExporter interface {
+	export(span *Span)
+}
+
+type spanContextKey struct{}
+
+// TraceID identifies a trace, i.e. all the spans of the processing of a transaction or a block.
+type TraceID [16]byte
+
+// SpanID identifies a span in a trace.
+type SpanID [8]byte
+
+// Span is a timed operation of a trace.
+type Span struct {
+	traceID  TraceID
+	id       SpanID
+	parentID SpanID
+	name     string
+	start    time.Time
+
+	mu    sync.Mutex
+	end   time.Time
+	attrs []attribute
+	err   error
+}
+
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+// Start starts a span named name, child of the span in ctx if any. The returned context
+// carries the new span, which must be ended by the caller. The span is nil when tracing
+// is disabled.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	if !Enabled {
+		return ctx, nil
+	}
+	span := &Span{name: name, start: time.Now()}
+	if parent := FromContext(ctx); parent != nil {
+		span.traceID, span.parentID = parent.traceID, parent.id
+	} else {
+		rand.Read(span.traceID[:])
+	}
+	rand.Read(span.id[:])
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// StartRoot starts a span named name for the operations without a caller context.
+func StartRoot(name string) *Span {
+	_, span := Start(context.Background(), name)
+	return span
+}
+
+// Child starts a span named name, child of s. The span is nil when s is nil.
+func (s *Span) Child(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	_, span := Start(context.WithValue(context.Background(), spanContextKey{}, s), name)
+	return span
+}
+
+// FromContext returns the span carried by ctx, nil if there is none.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// SetAttribute records a string, bool, integer or float attribute of the span.
+func (s *Span) SetAttribute(key string, value interface{}) *Span {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attribute{key: key, value: value})
+	s.mu.Unlock()
+	return s
+}
+
+// SetError marks the span as failed with err, nil errors are ignored.
+func (s *Span) SetError(err error) *Span {
+	if s == nil || err == nil {
+		return s
+	}
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	return s
+}
+
+// End ends the span and hands it to the exporter.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	ended := !s.end.IsZero()
+	if !ended {
+		s.end = time.Now()
+	}
+	s.mu.Unlock()
+	if !ended && exporter != nil {
+		exporter.export(s)
+	}
+}
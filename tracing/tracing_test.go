@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart_whenDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "test")
+
+	assert.Nil(t, span)
+	assert.Nil(t, FromContext(ctx))
+	// no-ops on a nil span
+	span.SetAttribute("key", "value").SetError(errors.New("error")).End()
+}
+
+type recordingExporter struct {
+	spans []*Span
+}
+
+func (e *recordingExporter) export(span *Span) {
+	e.spans = append(e.spans, span)
+}
+
+func TestStart_whenChildSpan(t *testing.T) {
+	defer func() { Enabled, exporter = false, nil }()
+	recorder := &recordingExporter{}
+	Enabled, exporter = true, recorder
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	child.End()
+	child.End()
+	parent.End()
+
+	require.Len(t, recorder.spans, 2)
+	assert.Equal(t, parent.traceID, child.traceID)
+	assert.Equal(t, parent.id, child.parentID)
+	assert.NotEqual(t, parent.id, child.id)
+	assert.Equal(t, SpanID{}, parent.parentID)
+}
+
+func TestOTLPExporter_Send(t *testing.T) {
+	var received otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, otlpTracesPath, r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+	exporter := &otlpExporter{url: server.URL + otlpTracesPath, serviceName: "geth", client: server.Client()}
+	span := &Span{name: "block.import", start: time.Unix(1, 0), end: time.Unix(2, 0), traceID: TraceID{1}, id: SpanID{2}, parentID: SpanID{3}}
+	span.SetAttribute("block.number", uint64(10)).SetAttribute("private", true).SetError(errors.New("bad block"))
+
+	require.NoError(t, exporter.send([]*Span{span}))
+
+	require.Len(t, received.ResourceSpans, 1)
+	assert.Equal(t, "geth", *received.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	assert.Equal(t, "01000000000000000000000000000000", spans[0].TraceID)
+	assert.Equal(t, "0200000000000000", spans[0].SpanID)
+	assert.Equal(t, "0300000000000000", spans[0].ParentSpanID)
+	assert.Equal(t, "1000000000", spans[0].StartTimeUnixNano)
+	assert.Equal(t, "10", *spans[0].Attributes[0].Value.IntValue)
+	assert.True(t, *spans[0].Attributes[1].Value.BoolValue)
+	assert.Equal(t, &otlpStatus{Code: otlpStatusCodeError, Message: "bad block"}, spans[0].Status)
+}